@@ -0,0 +1,134 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResetRateLimits removes the matching rate limits from every peer in the cluster, including
+// peers in other regions.
+func (s *V1Instance) ResetRateLimits(ctx context.Context, r *ResetRateLimitsReq) (*ResetRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ResetRateLimits")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if _, err := newNameMatcher(r.NamePrefix, r.NameGlob); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	peers := s.GetPeerList()
+	for _, picker := range s.GetRegionPickers() {
+		peers = append(peers, picker.Peers()...)
+	}
+
+	var (
+		resp  ResetRateLimitsResp
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	req := &ResetPeerRateLimitsReq{NamePrefix: r.NamePrefix, NameGlob: r.NameGlob}
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *ResetPeerRateLimitsResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.ResetPeerRateLimits(ctx, req)
+			} else {
+				peerResp, err = peer.ResetPeerRateLimits(ctx, req)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			resp.Removed += peerResp.Removed
+		}(peer)
+	}
+	wg.Wait()
+
+	if s.conf.AuditSink != nil {
+		s.conf.AuditSink.Record(AuditRecord{
+			Time:       clock.Now(),
+			Type:       AuditResetRateLimits,
+			InstanceID: s.conf.InstanceID,
+			NamePrefix: r.NamePrefix,
+			NameGlob:   r.NameGlob,
+			Removed:    resp.Removed,
+			Errors:     resp.Errors,
+		})
+	}
+	s.log.WithField("name_prefix", r.NamePrefix).
+		WithField("name_glob", r.NameGlob).
+		WithField("removed", resp.Removed).
+		WithField("errors", len(resp.Errors)).
+		Warn("rate limits reset via admin API")
+	return &resp, nil
+}
+
+// ResetPeerRateLimits is called by other peers to remove the matching rate limits from this peer.
+func (s *V1Instance) ResetPeerRateLimits(ctx context.Context, r *ResetPeerRateLimitsReq) (*ResetPeerRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ResetPeerRateLimits")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	match, err := newNameMatcher(r.NamePrefix, r.NameGlob)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	removed, err := s.workerPool.Reset(ctx, match)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return &ResetPeerRateLimitsResp{Removed: removed}, nil
+}
+
+// newNameMatcher returns a func which reports if a rate limit name matches
+// either the prefix or the glob. Exactly one of them must be non-empty.
+func newNameMatcher(prefix, glob string) (func(name string) bool, error) {
+	switch {
+	case prefix != "" && glob != "":
+		return nil, fmt.Errorf("only one of 'name_prefix' or 'name_glob' may be provided")
+	case prefix != "":
+		return func(name string) bool {
+			return strings.HasPrefix(name, prefix)
+		}, nil
+	case glob != "":
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid 'name_glob' '%s': %s", glob, err)
+		}
+		return func(name string) bool {
+			ok, _ := path.Match(glob, name)
+			return ok
+		}, nil
+	}
+	return nil, fmt.Errorf("one of 'name_prefix' or 'name_glob' must be provided")
+}
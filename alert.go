@@ -0,0 +1,231 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+// OverLimitAlert notifies `Config.AlertNotifier` when the rate limits in a namespace are over
+// the limit more often than a threshold, such that abuse is surfaced without separate alerting.
+// Each instance counts the OVER_LIMIT decisions of the rate limits it owns, as such the threshold
+// applies to each instance and not to the entire cluster.
+type OverLimitAlert struct {
+	// (Required) The alert applies to rate limit names which start with this prefix
+	Prefix string
+
+	// (Required) The alert fires once more than this many requests per minute are OVER_LIMIT
+	Threshold int64
+
+	// (Optional) How long the threshold must be exceeded, rounded down to whole minutes, before
+	// the alert fires. Defaults to 1 minute
+	For time.Duration
+}
+
+func (a *OverLimitAlert) validate() error {
+	switch {
+	case a.Prefix == "":
+		return errors.New("OverLimitAlerts.Prefix cannot be empty")
+	case a.Threshold <= 0:
+		return errors.Errorf("OverLimitAlerts.Threshold of '%s' must be greater than zero", a.Prefix)
+	case a.For < 0:
+		return errors.Errorf("OverLimitAlerts.For of '%s' cannot be negative", a.Prefix)
+	}
+	return nil
+}
+
+// ParseOverLimitAlert parses an alert in the format used by `GUBER_OVER_LIMIT_ALERTS`, a prefix
+// followed by semicolon separated options, IE: "public-api;threshold=100;for=5m"
+func ParseOverLimitAlert(s string) (OverLimitAlert, error) {
+	parts := strings.Split(strings.TrimSpace(s), ";")
+	a := OverLimitAlert{Prefix: parts[0]}
+	for _, part := range parts[1:] {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch k {
+		case "threshold":
+			a.Threshold, err = strconv.ParseInt(v, 10, 64)
+		case "for":
+			a.For, err = time.ParseDuration(v)
+		default:
+			return a, errors.Errorf("invalid option '%s' in over limit alert '%s'", part, s)
+		}
+		if err != nil {
+			return a, errors.Wrapf(err, "invalid option '%s' in over limit alert '%s'", part, s)
+		}
+	}
+	return a, a.validate()
+}
+
+// AlertEvent is sent to the AlertNotifyFunc when an OverLimitAlert fires or resolves
+type AlertEvent struct {
+	Time       time.Time `json:"time"`
+	InstanceID string    `json:"instance_id"`
+	// The prefix of the OverLimitAlert
+	Prefix    string `json:"prefix"`
+	Threshold int64  `json:"threshold"`
+	// The number of OVER_LIMIT decisions in the most recent minute
+	OverLimit int64 `json:"over_limit"`
+	// Is false when the alert fires and true once the alert is no longer over the threshold
+	Resolved bool `json:"resolved"`
+}
+
+// AlertNotifyFunc delivers an AlertEvent, IE: to a webhook or a paging service
+type AlertNotifyFunc func(ctx context.Context, event AlertEvent) error
+
+// NewWebhookAlertNotifier returns an AlertNotifyFunc which POSTs each event to `url` as JSON
+func NewWebhookAlertNotifier(url string) AlertNotifyFunc {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(ctx context.Context, event AlertEvent) error {
+		return postJSON(ctx, client, url, event)
+	}
+}
+
+// NewSlackAlertNotifier returns an AlertNotifyFunc which posts a message for each event to a
+// Slack incoming webhook `url`
+func NewSlackAlertNotifier(url string) AlertNotifyFunc {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(ctx context.Context, event AlertEvent) error {
+		text := fmt.Sprintf("Gubernator: rate limits '%s*' on '%s' had %d requests over the limit in the last minute (threshold %d)",
+			event.Prefix, event.InstanceID, event.OverLimit, event.Threshold)
+		if event.Resolved {
+			text = fmt.Sprintf("Gubernator: resolved; rate limits '%s*' on '%s' are no longer over the threshold of %d requests per minute",
+				event.Prefix, event.InstanceID, event.Threshold)
+		}
+		return postJSON(ctx, client, url, map[string]string{"text": text})
+	}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook '%s' returned '%s'", url, resp.Status)
+	}
+	return nil
+}
+
+// alertTracker counts the OVER_LIMIT decisions of each OverLimitAlert and compares the count
+// with the threshold once a minute
+type alertTracker struct {
+	alerts []*alertState
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+type alertState struct {
+	OverLimitAlert
+	// OVER_LIMIT decisions since the last evaluation
+	overLimit atomic.Int64
+	// The consecutive minutes the threshold was exceeded
+	minutes int
+	firing  bool
+}
+
+func newAlertTracker(alerts []OverLimitAlert) *alertTracker {
+	t := &alertTracker{done: make(chan struct{})}
+	for _, a := range alerts {
+		t.alerts = append(t.alerts, &alertState{OverLimitAlert: a})
+	}
+	return t
+}
+
+// record counts an OVER_LIMIT decision for every alert which matches the name
+func (t *alertTracker) record(name string) {
+	for _, a := range t.alerts {
+		if strings.HasPrefix(name, a.Prefix) {
+			a.overLimit.Add(1)
+		}
+	}
+}
+
+// stop ends runAlerts and waits for it to return
+func (t *alertTracker) stop() {
+	close(t.done)
+	t.wg.Wait()
+}
+
+// evaluate returns the events of the alerts which fired or resolved since the last evaluation
+func (t *alertTracker) evaluate() []AlertEvent {
+	var events []AlertEvent
+	for _, a := range t.alerts {
+		count := a.overLimit.Swap(0)
+		if count <= a.Threshold {
+			a.minutes = 0
+			if a.firing {
+				a.firing = false
+				events = append(events, AlertEvent{Prefix: a.Prefix, Threshold: a.Threshold, OverLimit: count, Resolved: true})
+			}
+			continue
+		}
+
+		a.minutes++
+		if !a.firing && time.Duration(a.minutes)*time.Minute >= a.For {
+			a.firing = true
+			events = append(events, AlertEvent{Prefix: a.Prefix, Threshold: a.Threshold, OverLimit: count})
+		}
+	}
+	return events
+}
+
+// runAlerts evaluates `Config.OverLimitAlerts` every minute until the instance is closed
+func (s *V1Instance) runAlerts() {
+	defer s.alerts.wg.Done()
+	tick := clock.NewTicker(time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			for _, event := range s.alerts.evaluate() {
+				event.Time = clock.Now()
+				event.InstanceID = s.conf.InstanceID
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				if err := s.conf.AlertNotifier(ctx, event); err != nil {
+					s.log.WithError(err).WithField("prefix", event.Prefix).Error("while notifying over limit alert")
+				}
+				cancel()
+			}
+		case <-s.alerts.done:
+			return
+		}
+	}
+}
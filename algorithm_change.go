@@ -0,0 +1,102 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import "fmt"
+
+// Policies for a rate limit requested with a different algorithm than it was created with,
+// see Config.AlgorithmChangePolicy
+const (
+	// AlgorithmChangeReset discards the existing rate limit and creates a new one
+	AlgorithmChangeReset = "reset"
+	// AlgorithmChangeTranslate carries the remaining hits of the existing rate limit over to the new algorithm
+	AlgorithmChangeTranslate = "translate"
+	// AlgorithmChangeReject responds with ErrAlgorithmChanged until the existing rate limit expires
+	AlgorithmChangeReject = "reject"
+)
+
+// ErrAlgorithmChanged prefixes the error of a rate limit requested with a different algorithm than
+// it was created with when Config.AlgorithmChangePolicy is AlgorithmChangeReject
+const ErrAlgorithmChanged = "ALGORITHM_CHANGED"
+
+// algorithmChangedResp returns the response of a rate limit rejected by AlgorithmChangeReject
+func algorithmChangedResp(r *RateLimitReq, item *CacheItem) *RateLimitResp {
+	metricAlgorithmChangeCounter.WithLabelValues("rejected").Inc()
+	return &RateLimitResp{
+		Limit: r.Limit,
+		Error: fmt.Sprintf("%s; rate limit '%s' was created with algorithm '%s' and cannot be requested with '%s' until it expires",
+			ErrAlgorithmChanged, r.Name, item.Algorithm, r.Algorithm),
+	}
+}
+
+// leakyToTokenBucket replaces the leaky bucket of `item` with a token bucket which begins a new
+// window with the hits remaining in the leaky bucket, including those which leaked since it was
+// last updated.
+func leakyToTokenBucket(conf *Config, r *RateLimitReq, item *CacheItem, b *LeakyBucketItem) (*TokenBucketItem, error) {
+	createdAt := *r.CreatedAt
+	expire := addInt64(addInt64(createdAt, r.Duration), resetJitter(conf, r))
+	if HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
+		var err error
+		expire, err = gregorianExpiration(r.Duration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remaining := b.Remaining
+	if b.Limit > 0 && b.Duration > 0 {
+		remaining += float64(createdAt-b.UpdatedAt) / (float64(b.Duration) / float64(b.Limit))
+	}
+	t := &TokenBucketItem{
+		Limit:     r.Limit,
+		Duration:  r.Duration,
+		Remaining: floatToInt64(remaining),
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	if capacity := tokenCapacity(r); t.Remaining > capacity {
+		t.Remaining = capacity
+	}
+
+	item.Algorithm = Algorithm_TOKEN_BUCKET
+	item.Value = t
+	item.ExpireAt = expire
+	metricAlgorithmChangeCounter.WithLabelValues("translated").Inc()
+	return t, nil
+}
+
+// tokenToLeakyBucket replaces the token bucket of `item` with a leaky bucket which holds the
+// hits remaining in the token bucket, up to the burst of the request.
+func tokenToLeakyBucket(r *RateLimitReq, item *CacheItem, t *TokenBucketItem) *LeakyBucketItem {
+	createdAt := *r.CreatedAt
+	b := &LeakyBucketItem{
+		Limit:     r.Limit,
+		Duration:  r.Duration,
+		Remaining: float64(t.Remaining),
+		UpdatedAt: createdAt,
+		Burst:     r.Burst,
+	}
+	if t.Remaining > r.Burst {
+		b.Remaining = float64(r.Burst)
+	}
+
+	item.Algorithm = Algorithm_LEAKY_BUCKET
+	item.Value = b
+	item.ExpireAt = createdAt + r.Duration
+	metricAlgorithmChangeCounter.WithLabelValues("translated").Inc()
+	return b
+}
@@ -18,7 +18,9 @@ package gubernator
 
 import (
 	"context"
+	"math"
 
+	"github.com/OneOfOne/xxhash"
 	"github.com/mailgun/holster/v4/clock"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -32,14 +34,18 @@ import (
 // IE: client attempts to send 1000 emails but 100 is their limit. The request is rejected as over the
 // limit, but we do not set the remainder to 0 in the cache. The client can retry within the same window
 // with 100 emails and the request will succeed. You can override this default behavior with `DRAIN_OVER_LIMIT`
+//
+// Requests with `Hits = 0` are query only, see isQueryOnly()
 
 // Implements token bucket algorithm for rate limiting. https://en.wikipedia.org/wiki/Token_bucket
-func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
-	tokenBucketTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("tokenBucket"))
-	defer tokenBucketTimer.ObserveDuration()
+func tokenBucket(ctx context.Context, s Store, c Cache, conf *Config, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
+	if !reqState.readCopy {
+		tokenBucketTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("tokenBucket"))
+		defer tokenBucketTimer.ObserveDuration()
+	}
 
 	// Get rate limit from cache.
-	hashKey := r.HashKey()
+	hashKey := reqState.keyOf(conf, r)
 	item, ok := c.GetItem(hashKey)
 
 	if s != nil && !ok {
@@ -55,8 +61,8 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		if item.Value == nil {
 			msgPart := "tokenBucket: Invalid cache item; Value is nil"
 			trace.SpanFromContext(ctx).AddEvent(msgPart, trace.WithAttributes(
-				attribute.String("hashKey", hashKey),
-				attribute.String("key", r.UniqueKey),
+				attribute.String("hashKey", redactKey(conf.KeyRedaction, hashKey)),
+				attribute.String("key", redactKey(conf.KeyRedaction, r.UniqueKey)),
 				attribute.String("name", r.Name),
 			))
 			logrus.Error(msgPart)
@@ -64,8 +70,8 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		} else if item.Key != hashKey {
 			msgPart := "tokenBucket: Invalid cache item; key mismatch"
 			trace.SpanFromContext(ctx).AddEvent(msgPart, trace.WithAttributes(
-				attribute.String("itemKey", item.Key),
-				attribute.String("hashKey", hashKey),
+				attribute.String("itemKey", redactKey(conf.KeyRedaction, item.Key)),
+				attribute.String("hashKey", redactKey(conf.KeyRedaction, hashKey)),
 				attribute.String("name", r.Name),
 			))
 			logrus.Error(msgPart)
@@ -75,6 +81,9 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 
 	if ok {
 		// Item found in cache or store.
+		if reqState.drift != nil {
+			reqState.drift.observe(hashKey, r, item)
+		}
 		if HasBehavior(r.Behavior, Behavior_RESET_REMAINING) {
 			c.Remove(hashKey)
 
@@ -84,22 +93,39 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			return &RateLimitResp{
 				Status:    Status_UNDER_LIMIT,
 				Limit:     r.Limit,
-				Remaining: r.Limit,
+				Remaining: tokenCapacity(r),
 				ResetTime: 0,
 			}, nil
 		}
+		if isQueryOnly(r) {
+			item = item.copy()
+		}
 		t, ok := item.Value.(*TokenBucketItem)
 		if !ok {
 			// Client switched algorithms; perhaps due to a migration?
 			trace.SpanFromContext(ctx).AddEvent("Client switched algorithms; perhaps due to a migration?")
+			if conf.AlgorithmChangePolicy == AlgorithmChangeReject {
+				return algorithmChangedResp(r, item), nil
+			}
 
-			c.Remove(hashKey)
+			b, isLeaky := item.Value.(*LeakyBucketItem)
+			if !isLeaky || conf.AlgorithmChangePolicy != AlgorithmChangeTranslate {
+				if isQueryOnly(r) {
+					return tokenBucketNewItem(ctx, s, c, conf, r, reqState)
+				}
+				metricAlgorithmChangeCounter.WithLabelValues("reset").Inc()
+				c.Remove(hashKey)
 
-			if s != nil {
-				s.Remove(ctx, hashKey)
+				if s != nil {
+					s.Remove(ctx, hashKey)
+				}
+
+				return tokenBucketNewItem(ctx, s, c, conf, r, reqState)
 			}
 
-			return tokenBucketNewItem(ctx, s, c, r, reqState)
+			if t, err = leakyToTokenBucket(conf, r, item, b); err != nil {
+				return nil, err
+			}
 		}
 
 		// Update the limit if it changed.
@@ -123,9 +149,9 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		if t.Duration != r.Duration {
 			span := trace.SpanFromContext(ctx)
 			span.AddEvent("Duration changed")
-			expire := t.CreatedAt + r.Duration
+			expire := addInt64(addInt64(t.CreatedAt, r.Duration), resetJitter(conf, r))
 			if HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
-				expire, err = GregorianExpiration(clock.Now(), r.Duration)
+				expire, err = gregorianExpiration(r.Duration)
 				if err != nil {
 					return nil, err
 				}
@@ -136,9 +162,9 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			if expire <= createdAt {
 				// Renew item.
 				span.AddEvent("Limit has expired")
-				expire = createdAt + r.Duration
+				expire = addInt64(addInt64(createdAt, r.Duration), resetJitter(conf, r))
 				t.CreatedAt = createdAt
-				t.Remaining = t.Limit
+				t.Remaining = tokenCapacity(r)
 			}
 
 			item.ExpireAt = expire
@@ -146,18 +172,47 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			rl.ResetTime = expire
 		}
 
-		if s != nil && reqState.IsOwner {
+		if !isGreedyRefill(r) {
+			if rl.ResetTime, err = rolloverWindow(conf, r, item, t); err != nil {
+				return nil, err
+			}
+			rl.Status = t.Status
+			rl.Remaining = t.Remaining
+		}
+
+		if s != nil && reqState.IsOwner && !isQueryOnly(r) {
 			defer func() {
 				s.OnChange(ctx, r, item)
 			}()
 		}
 
-		// Client is only interested in retrieving the current status or
-		// updating the rate limit config.
+		if isGreedyRefill(r) {
+			greedyRefill(t, *r.CreatedAt, tokenCapacity(r))
+			rl.Status = t.Status
+			rl.Remaining = t.Remaining
+			// The bucket is full once the tokens taken by this request are refilled.
+			defer func() {
+				item.ExpireAt = greedyRefillExpire(t, tokenCapacity(r))
+				rl.ResetTime = item.ExpireAt
+			}()
+		}
+
+		// Client is only interested in retrieving the current status.
 		if r.Hits == 0 {
 			return rl, nil
 		}
 
+		// If the hits over the limit fit in what remains of the grace allowance.
+		if over := r.Hits - t.Remaining; over > 0 && over <= graceRemaining(conf, t) {
+			trace.SpanFromContext(ctx).AddEvent("Over the limit within grace")
+			t.GraceUsed += over
+			t.Remaining = 0
+			t.Status = Status_UNDER_LIMIT
+			rl.Remaining = 0
+			rl.Status = Status_UNDER_LIMIT
+			return rl, nil
+		}
+
 		// If we are already at the limit.
 		if rl.Remaining == 0 && r.Hits > 0 {
 			trace.SpanFromContext(ctx).AddEvent("Already over the limit")
@@ -193,30 +248,32 @@ func tokenBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			return rl, nil
 		}
 
-		t.Remaining -= r.Hits
+		t.Remaining = applyHits(t.Remaining, r.Hits)
 		rl.Remaining = t.Remaining
 		return rl, nil
 	}
 
 	// Item is not found in cache or store, create new.
-	return tokenBucketNewItem(ctx, s, c, r, reqState)
+	return tokenBucketNewItem(ctx, s, c, conf, r, reqState)
 }
 
 // Called by tokenBucket() when adding a new item in the store.
-func tokenBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
+func tokenBucketNewItem(ctx context.Context, s Store, c Cache, conf *Config, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
 	createdAt := *r.CreatedAt
-	expire := createdAt + r.Duration
+	expire := addInt64(addInt64(createdAt, r.Duration), resetJitter(conf, r))
+	capacity := tokenCapacity(r)
 
 	t := &TokenBucketItem{
 		Limit:     r.Limit,
 		Duration:  r.Duration,
-		Remaining: r.Limit - r.Hits,
+		Remaining: applyHits(capacity, r.Hits),
 		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
 	}
 
 	// Add a new rate limit to the cache.
 	if HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
-		expire, err = GregorianExpiration(clock.Now(), r.Duration)
+		expire, err = gregorianExpiration(r.Duration)
 		if err != nil {
 			return nil, err
 		}
@@ -224,7 +281,8 @@ func tokenBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 
 	item := &CacheItem{
 		Algorithm: Algorithm_TOKEN_BUCKET,
-		Key:       r.HashKey(),
+		Key:       conf.HashKey(r),
+		Name:      r.Name,
 		Value:     t,
 		ExpireAt:  expire,
 	}
@@ -237,14 +295,36 @@ func tokenBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 	}
 
 	// Client could be requesting that we always return OVER_LIMIT.
-	if r.Hits > r.Limit {
+	if over := r.Hits - capacity; over > 0 && over <= graceRemaining(conf, t) {
+		trace.SpanFromContext(ctx).AddEvent("Over the limit within grace")
+		t.GraceUsed = over
+		t.Remaining = 0
+		rl.Remaining = 0
+	} else if r.Hits > capacity {
 		trace.SpanFromContext(ctx).AddEvent("Over the limit")
 		if reqState.IsOwner {
 			metricOverLimitCounter.Add(1)
 		}
 		rl.Status = Status_OVER_LIMIT
-		rl.Remaining = r.Limit
-		t.Remaining = r.Limit
+		rl.Remaining = capacity
+		t.Remaining = capacity
+	}
+
+	if isGreedyRefill(r) {
+		item.ExpireAt = greedyRefillExpire(t, capacity)
+		rl.ResetTime = item.ExpireAt
+	}
+
+	// The tokens left unused roll over when the next window starts, see rolloverWindow()
+	if isRollover(r) {
+		if item.ExpireAt, err = windowEnd(conf, r, addInt64(expire, 1)); err != nil {
+			return nil, err
+		}
+	}
+
+	// The window of a new rate limit begins with the first hit.
+	if isQueryOnly(r) {
+		return rl, nil
 	}
 
 	c.Add(item)
@@ -256,10 +336,151 @@ func tokenBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 	return rl, nil
 }
 
+// isQueryOnly returns true if the request only retrieves the status of the rate limit. Such requests
+// are evaluated against a copy of the rate limit, such that they never change the remaining hits,
+// definition or expiration of the rate limit, nor create a rate limit which does not exist. Requests
+// with `RESET_REMAINING` still reset the rate limit.
+func isQueryOnly(r *RateLimitReq) bool {
+	return r.Hits == 0 && !HasBehavior(r.Behavior, Behavior_RESET_REMAINING)
+}
+
+// graceRemaining returns the number of hits over the limit the token bucket may still be
+// granted by `BehaviorConfig.GracePercent`.
+func graceRemaining(conf *Config, t *TokenBucketItem) int64 {
+	if conf.Behaviors.GracePercent <= 0 {
+		return 0
+	}
+	return t.Limit*int64(conf.Behaviors.GracePercent)/100 - t.GraceUsed
+}
+
+// isGreedyRefill returns true if the token bucket should refill tokens continuously
+func isGreedyRefill(r *RateLimitReq) bool {
+	return (HasBehavior(r.Behavior, Behavior_GREEDY_REFILL) ||
+		(r.MaxCapacity > r.Limit && !HasBehavior(r.Behavior, Behavior_ROLLOVER))) &&
+		!HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) &&
+		r.Limit > 0 && r.Duration > 0
+}
+
+// isRollover returns true if the tokens left unused at the end of a window should be added to the
+// next window of the token bucket
+func isRollover(r *RateLimitReq) bool {
+	return HasBehavior(r.Behavior, Behavior_ROLLOVER) && !HasBehavior(r.Behavior, Behavior_GREEDY_REFILL) &&
+		r.MaxCapacity > r.Limit
+}
+
+// windowEnd returns the end of the window of the token bucket which starts at `start`
+func windowEnd(conf *Config, r *RateLimitReq, start int64) (int64, error) {
+	if HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
+		return gregorianExpirationAt(start, r.Duration)
+	}
+	return addInt64(addInt64(start, r.Duration), resetJitter(conf, r)), nil
+}
+
+// rolloverWindow returns the end of the window of the token bucket `t`. A ROLLOVER bucket is kept in
+// the cache until the end of the window after its own, such that the tokens it left unused are added
+// to the window which starts with the first hit after its window ended, up to `MaxCapacity` tokens.
+func rolloverWindow(conf *Config, r *RateLimitReq, item *CacheItem, t *TokenBucketItem) (int64, error) {
+	end, err := windowEnd(conf, r, t.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	// Only a ROLLOVER bucket outlives its window
+	if !isRollover(r) && end >= item.ExpireAt {
+		return item.ExpireAt, nil
+	}
+
+	createdAt := *r.CreatedAt
+	if createdAt > end {
+		remaining := r.Limit
+		if isRollover(r) && t.Remaining > 0 {
+			remaining = addInt64(remaining, t.Remaining)
+			if remaining > r.MaxCapacity {
+				remaining = r.MaxCapacity
+			}
+		}
+		t.Remaining = remaining
+		t.CreatedAt = createdAt
+		t.Status = Status_UNDER_LIMIT
+		t.GraceUsed = 0
+		if end, err = windowEnd(conf, r, createdAt); err != nil {
+			return 0, err
+		}
+	}
+
+	item.ExpireAt = end
+	if isRollover(r) {
+		if item.ExpireAt, err = windowEnd(conf, r, addInt64(end, 1)); err != nil {
+			return 0, err
+		}
+	}
+	return end, nil
+}
+
+// tokenCapacity returns the maximum number of tokens the token bucket may hold
+func tokenCapacity(r *RateLimitReq) int64 {
+	if r.MaxCapacity > r.Limit && isGreedyRefill(r) {
+		return r.MaxCapacity
+	}
+	return r.Limit
+}
+
+// greedyRefill adds the tokens accrued since the bucket was last refilled at the rate of
+// `Limit` tokens per `Duration`, up to `capacity` tokens.
+func greedyRefill(t *TokenBucketItem, now, capacity int64) {
+	if t.UpdatedAt == 0 {
+		t.UpdatedAt = t.CreatedAt
+	}
+	if t.Remaining >= capacity {
+		t.Remaining = capacity
+		t.UpdatedAt = now
+		t.GraceUsed = 0
+		return
+	}
+
+	elapsed := now - t.UpdatedAt
+	if elapsed <= 0 {
+		return
+	}
+	// Floats avoid overflowing int64 with large limits and durations
+	if float64(elapsed) >= float64(t.Duration)*float64(capacity)/float64(t.Limit) {
+		t.Remaining = capacity
+		t.UpdatedAt = now
+		t.GraceUsed = 0
+		t.Status = Status_UNDER_LIMIT
+		return
+	}
+
+	tokens := floatToInt64(float64(elapsed) * float64(t.Limit) / float64(t.Duration))
+	if tokens == 0 {
+		return
+	}
+	t.Status = Status_UNDER_LIMIT
+	t.Remaining += tokens
+	if t.Remaining >= capacity {
+		t.Remaining = capacity
+		t.UpdatedAt = now
+		t.GraceUsed = 0
+		return
+	}
+	// Only advance by the time it took to accrue whole tokens, so partial tokens are not lost
+	t.UpdatedAt = addInt64(t.UpdatedAt, floatToInt64(float64(tokens)*float64(t.Duration)/float64(t.Limit)))
+}
+
+// greedyRefillExpire returns the time at which the bucket will hold `capacity` tokens
+func greedyRefillExpire(t *TokenBucketItem, capacity int64) int64 {
+	missing := capacity - t.Remaining
+	if missing <= 0 {
+		return t.UpdatedAt
+	}
+	return addInt64(t.UpdatedAt, floatToInt64(math.Ceil(float64(missing)*float64(t.Duration)/float64(t.Limit))))
+}
+
 // Implements leaky bucket algorithm for rate limiting https://en.wikipedia.org/wiki/Leaky_bucket
-func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
-	leakyBucketTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.getRateLimit_leakyBucket"))
-	defer leakyBucketTimer.ObserveDuration()
+func leakyBucket(ctx context.Context, s Store, c Cache, conf *Config, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
+	if !reqState.readCopy {
+		leakyBucketTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.getRateLimit_leakyBucket"))
+		defer leakyBucketTimer.ObserveDuration()
+	}
 
 	if r.Burst == 0 {
 		r.Burst = r.Limit
@@ -268,7 +489,7 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 	createdAt := *r.CreatedAt
 
 	// Get rate limit from cache.
-	hashKey := r.HashKey()
+	hashKey := reqState.keyOf(conf, r)
 	item, ok := c.GetItem(hashKey)
 
 	if s != nil && !ok {
@@ -284,8 +505,8 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		if item.Value == nil {
 			msgPart := "leakyBucket: Invalid cache item; Value is nil"
 			trace.SpanFromContext(ctx).AddEvent(msgPart, trace.WithAttributes(
-				attribute.String("hashKey", hashKey),
-				attribute.String("key", r.UniqueKey),
+				attribute.String("hashKey", redactKey(conf.KeyRedaction, hashKey)),
+				attribute.String("key", redactKey(conf.KeyRedaction, r.UniqueKey)),
 				attribute.String("name", r.Name),
 			))
 			logrus.Error(msgPart)
@@ -293,8 +514,8 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		} else if item.Key != hashKey {
 			msgPart := "leakyBucket: Invalid cache item; key mismatch"
 			trace.SpanFromContext(ctx).AddEvent(msgPart, trace.WithAttributes(
-				attribute.String("itemKey", item.Key),
-				attribute.String("hashKey", hashKey),
+				attribute.String("itemKey", redactKey(conf.KeyRedaction, item.Key)),
+				attribute.String("hashKey", redactKey(conf.KeyRedaction, hashKey)),
 				attribute.String("name", r.Name),
 			))
 			logrus.Error(msgPart)
@@ -304,17 +525,37 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 
 	if ok {
 		// Item found in cache or store.
+		if reqState.drift != nil {
+			reqState.drift.observe(hashKey, r, item)
+		}
 
+		if isQueryOnly(r) {
+			item = item.copy()
+		}
 		b, ok := item.Value.(*LeakyBucketItem)
 		if !ok {
 			// Client switched algorithms; perhaps due to a migration?
-			c.Remove(hashKey)
+			trace.SpanFromContext(ctx).AddEvent("Client switched algorithms; perhaps due to a migration?")
+			if conf.AlgorithmChangePolicy == AlgorithmChangeReject {
+				return algorithmChangedResp(r, item), nil
+			}
 
-			if s != nil {
-				s.Remove(ctx, hashKey)
+			t, isToken := item.Value.(*TokenBucketItem)
+			if !isToken || conf.AlgorithmChangePolicy != AlgorithmChangeTranslate {
+				if isQueryOnly(r) {
+					return leakyBucketNewItem(ctx, s, c, conf, r, reqState)
+				}
+				metricAlgorithmChangeCounter.WithLabelValues("reset").Inc()
+				c.Remove(hashKey)
+
+				if s != nil {
+					s.Remove(ctx, hashKey)
+				}
+
+				return leakyBucketNewItem(ctx, s, c, conf, r, reqState)
 			}
 
-			return leakyBucketNewItem(ctx, s, c, r, reqState)
+			b = tokenToLeakyBucket(r, item, t)
 		}
 
 		if HasBehavior(r.Behavior, Behavior_RESET_REMAINING) {
@@ -323,7 +564,7 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 
 		// Update burst, limit and duration if they changed
 		if b.Burst != r.Burst {
-			if r.Burst > int64(b.Remaining) {
+			if r.Burst > floatToInt64(b.Remaining) {
 				b.Remaining = float64(r.Burst)
 			}
 			b.Burst = r.Burst
@@ -354,39 +595,41 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		}
 
 		if r.Hits != 0 {
-			c.UpdateExpiration(r.HashKey(), createdAt+duration)
+			c.UpdateExpiration(conf.HashKey(r), addInt64(createdAt, duration))
 		}
 
 		// Calculate how much leaked out of the bucket since the last time we leaked a hit
 		elapsed := createdAt - b.UpdatedAt
 		leak := float64(elapsed) / rate
 
-		if int64(leak) > 0 {
+		// The leak is infinite when the duration is zero, and NaN if no time has elapsed as well
+		if leak >= 1 {
 			b.Remaining += leak
 			b.UpdatedAt = createdAt
 		}
 
-		if int64(b.Remaining) > b.Burst {
+		if b.Remaining > float64(b.Burst) {
 			b.Remaining = float64(b.Burst)
 		}
 
 		rl := &RateLimitResp{
 			Limit:     b.Limit,
-			Remaining: int64(b.Remaining),
+			Remaining: floatToInt64(b.Remaining),
 			Status:    Status_UNDER_LIMIT,
-			ResetTime: createdAt + (b.Limit-int64(b.Remaining))*int64(rate),
+			ResetTime: leakyResetTime(createdAt, b.Limit, floatToInt64(b.Remaining), rate),
 		}
+		defer setQueueDepth(rl, b.Burst, rate)
 
 		// TODO: Feature missing: check for Duration change between item/request.
 
-		if s != nil && reqState.IsOwner {
+		if s != nil && reqState.IsOwner && !isQueryOnly(r) {
 			defer func() {
 				s.OnChange(ctx, r, item)
 			}()
 		}
 
 		// If we are already at the limit
-		if int64(b.Remaining) == 0 && r.Hits > 0 {
+		if floatToInt64(b.Remaining) == 0 && r.Hits > 0 {
 			if reqState.IsOwner {
 				metricOverLimitCounter.Add(1)
 			}
@@ -395,16 +638,16 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 		}
 
 		// If requested hits takes the remainder
-		if int64(b.Remaining) == r.Hits {
+		if floatToInt64(b.Remaining) == r.Hits {
 			b.Remaining = 0
-			rl.Remaining = int64(b.Remaining)
-			rl.ResetTime = createdAt + (rl.Limit-rl.Remaining)*int64(rate)
+			rl.Remaining = floatToInt64(b.Remaining)
+			rl.ResetTime = leakyResetTime(createdAt, rl.Limit, rl.Remaining, rate)
 			return rl, nil
 		}
 
 		// If requested is more than available, then return over the limit
 		// without updating the bucket, unless `DRAIN_OVER_LIMIT` is set.
-		if r.Hits > int64(b.Remaining) {
+		if r.Hits > floatToInt64(b.Remaining) {
 			if reqState.IsOwner {
 				metricOverLimitCounter.Add(1)
 			}
@@ -414,6 +657,7 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			if HasBehavior(r.Behavior, Behavior_DRAIN_OVER_LIMIT) {
 				b.Remaining = 0
 				rl.Remaining = 0
+				rl.ResetTime = leakyResetTime(createdAt, rl.Limit, rl.Remaining, rate)
 			}
 
 			return rl, nil
@@ -424,17 +668,17 @@ func leakyBucket(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqStat
 			return rl, nil
 		}
 
-		b.Remaining -= float64(r.Hits)
-		rl.Remaining = int64(b.Remaining)
-		rl.ResetTime = createdAt + (rl.Limit-rl.Remaining)*int64(rate)
+		b.Remaining = math.Min(b.Remaining-float64(r.Hits), float64(MaxLimit))
+		rl.Remaining = floatToInt64(b.Remaining)
+		rl.ResetTime = leakyResetTime(createdAt, rl.Limit, rl.Remaining, rate)
 		return rl, nil
 	}
 
-	return leakyBucketNewItem(ctx, s, c, r, reqState)
+	return leakyBucketNewItem(ctx, s, c, conf, r, reqState)
 }
 
 // Called by leakyBucket() when adding a new item in the store.
-func leakyBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
+func leakyBucketNewItem(ctx context.Context, s Store, c Cache, conf *Config, r *RateLimitReq, reqState RateLimitReqState) (resp *RateLimitResp, err error) {
 	createdAt := *r.CreatedAt
 	duration := r.Duration
 	rate := float64(duration) / float64(r.Limit)
@@ -450,8 +694,9 @@ func leakyBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 	}
 
 	// Create a new leaky bucket
+	remaining := applyHits(r.Burst, r.Hits)
 	b := LeakyBucketItem{
-		Remaining: float64(r.Burst - r.Hits),
+		Remaining: float64(remaining),
 		Limit:     r.Limit,
 		Duration:  duration,
 		UpdatedAt: createdAt,
@@ -461,8 +706,8 @@ func leakyBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 	rl := RateLimitResp{
 		Status:    Status_UNDER_LIMIT,
 		Limit:     b.Limit,
-		Remaining: r.Burst - r.Hits,
-		ResetTime: createdAt + (b.Limit-(r.Burst-r.Hits))*int64(rate),
+		Remaining: remaining,
+		ResetTime: leakyResetTime(createdAt, b.Limit, remaining, rate),
 	}
 
 	// Client could be requesting that we start with the bucket OVER_LIMIT
@@ -472,17 +717,24 @@ func leakyBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 		}
 		rl.Status = Status_OVER_LIMIT
 		rl.Remaining = 0
-		rl.ResetTime = createdAt + (rl.Limit-rl.Remaining)*int64(rate)
+		rl.ResetTime = leakyResetTime(createdAt, rl.Limit, rl.Remaining, rate)
 		b.Remaining = 0
 	}
+	setQueueDepth(&rl, b.Burst, rate)
 
 	item := &CacheItem{
-		ExpireAt:  createdAt + duration,
+		ExpireAt:  addInt64(createdAt, duration),
 		Algorithm: r.Algorithm,
-		Key:       r.HashKey(),
+		Key:       conf.HashKey(r),
+		Name:      r.Name,
 		Value:     &b,
 	}
 
+	// The bucket of a new rate limit begins to fill with the first hit.
+	if isQueryOnly(r) {
+		return &rl, nil
+	}
+
 	c.Add(item)
 
 	if s != nil && reqState.IsOwner {
@@ -491,3 +743,34 @@ func leakyBucketNewItem(ctx context.Context, s Store, c Cache, r *RateLimitReq,
 
 	return &rl, nil
 }
+
+// setQueueDepth sets the number of hits in a leaky bucket which have yet to leak out, and the
+// time until they all leak out at `rate` milliseconds per hit.
+func setQueueDepth(rl *RateLimitResp, burst int64, rate float64) {
+	rl.QueueDepth = burst - rl.Remaining
+	if rl.QueueDepth < 0 {
+		rl.QueueDepth = 0
+	}
+	rl.DrainTime = floatToInt64(float64(rl.QueueDepth) * rate)
+}
+
+// resetJitter returns the number of milliseconds added to (or removed from) the reset time of a
+// token bucket when `Config.Behaviors.ResetJitterPercent` is set. This spreads out the resets of rate
+// limits which share the same duration, instead of them all resetting on the same boundary.
+//
+// The offset is derived from the hash key, such that every peer calculates the same reset time
+// for the same rate limit. Gregorian durations are never jittered as they must reset at the end
+// of the calendar interval.
+func resetJitter(conf *Config, r *RateLimitReq) int64 {
+	if conf.Behaviors.ResetJitterPercent == 0 || HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
+		return 0
+	}
+
+	spread := mulInt64(r.Duration, int64(conf.Behaviors.ResetJitterPercent)) / 100
+	if spread <= 0 {
+		return 0
+	}
+
+	// Map the hash of the key onto the range [-spread, spread]
+	return int64(xxhash.ChecksumString64(conf.HashKey(r))%uint64(2*spread+1)) - spread
+}
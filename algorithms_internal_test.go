@@ -0,0 +1,263 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// The behaviors which change the arithmetic of the algorithms
+const fuzzBehaviors = Behavior_GREEDY_REFILL | Behavior_DRAIN_OVER_LIMIT | Behavior_RESET_REMAINING
+
+// fuzzSeeds adds durations and limits at the boundaries of what the algorithms accept
+func fuzzSeeds(f *testing.F) {
+	for _, duration := range []int64{0, 1, 2, Minute, MaxDuration - 1, MaxDuration, MaxDuration + 1, math.MaxInt64, -1} {
+		for _, limit := range []int64{0, 1, 3, 1_000_000, math.MaxInt64, -1} {
+			f.Add(limit, duration, int64(1), int64(0), int64(0), int64(Minute), int32(0))
+			f.Add(limit, duration, limit, limit, limit, duration/2, int32(Behavior_GREEDY_REFILL))
+			f.Add(limit, duration, -MaxLimit, limit, int64(0), int64(0), int32(0))
+		}
+	}
+	f.Add(int64(10), int64(3), int64(math.MaxInt64), int64(math.MaxInt64), int64(0), int64(math.MaxInt64), int32(Behavior_DRAIN_OVER_LIMIT))
+}
+
+// fuzzRequests returns two requests for the same rate limit `elapsed` milliseconds apart, or
+// false if the request is rejected by validateBounds()
+func fuzzRequests(limit, duration, hits, burst, maxCapacity, elapsed int64, behavior int32) (*RateLimitReq, *RateLimitReq, bool) {
+	createdAt := int64(1_700_000_000_000)
+	r := &RateLimitReq{
+		Name:        "fuzz",
+		UniqueKey:   "account:1234",
+		Limit:       limit,
+		Duration:    duration,
+		Hits:        hits,
+		Burst:       burst,
+		MaxCapacity: maxCapacity,
+		Behavior:    Behavior(behavior) & fuzzBehaviors,
+		CreatedAt:   &createdAt,
+	}
+	if elapsed < 0 || validateBounds(r) != nil {
+		return nil, nil, false
+	}
+	next := proto.Clone(r).(*RateLimitReq)
+	*next.CreatedAt = addInt64(createdAt, elapsed)
+	next.Behavior &^= Behavior_RESET_REMAINING
+	return r, next, true
+}
+
+func FuzzTokenBucket(f *testing.F) {
+	fuzzSeeds(f)
+	conf := &Config{}
+	require.NoError(f, conf.SetDefaults())
+	conf.Behaviors.ResetJitterPercent = 100
+
+	f.Fuzz(func(t *testing.T, limit, duration, hits, burst, maxCapacity, elapsed int64, behavior int32) {
+		first, next, ok := fuzzRequests(limit, duration, hits, burst, maxCapacity, elapsed, behavior)
+		if !ok {
+			return
+		}
+		cache := NewLRUCache(0)
+		for _, r := range []*RateLimitReq{first, next} {
+			rl, err := tokenBucket(context.Background(), nil, cache, conf, r, RateLimitReqState{IsOwner: true})
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, rl.Remaining, int64(0))
+			assert.LessOrEqual(t, rl.Remaining, maxRemaining(r, largest(limit, tokenCapacity(r))))
+			assert.GreaterOrEqual(t, rl.ResetTime, int64(0))
+		}
+	})
+}
+
+func FuzzLeakyBucket(f *testing.F) {
+	fuzzSeeds(f)
+	conf := &Config{}
+	require.NoError(f, conf.SetDefaults())
+
+	f.Fuzz(func(t *testing.T, limit, duration, hits, burst, maxCapacity, elapsed int64, behavior int32) {
+		first, next, ok := fuzzRequests(limit, duration, hits, burst, maxCapacity, elapsed, behavior)
+		if !ok {
+			return
+		}
+		cache := NewLRUCache(0)
+		for _, r := range []*RateLimitReq{first, next} {
+			rl, err := leakyBucket(context.Background(), nil, cache, conf, r, RateLimitReqState{IsOwner: true})
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, rl.Remaining, int64(0))
+			assert.LessOrEqual(t, rl.Remaining, maxRemaining(r, largest(limit, r.Burst)))
+			// The reset time of a bucket which holds more than the limit because of its burst is in the past
+			if rl.Remaining <= limit {
+				assert.GreaterOrEqual(t, rl.ResetTime, *r.CreatedAt)
+			}
+			assert.GreaterOrEqual(t, rl.DrainTime, int64(0))
+		}
+	})
+}
+
+// fuzzStep is one request of a fuzzed sequence, see decodeFuzzSteps()
+type fuzzStep struct {
+	hits     int64
+	limit    int64
+	duration int64
+	// The milliseconds the clock jumps ahead before the request
+	jump int64
+}
+
+// decodeFuzzSteps decodes a sequence of requests from `program`, three bytes per request. The first
+// byte selects the operation, the second is its operand and the third is the clock jump. The jumps
+// grow exponentially, such that a sequence may span a few milliseconds as well as several windows.
+func decodeFuzzSteps(limit, duration int64, program []byte) []fuzzStep {
+	var steps []fuzzStep
+	for ; len(program) >= 3; program = program[3:] {
+		op, operand, jump := program[0]%8, int64(program[1]), program[2]
+		step := fuzzStep{limit: limit, duration: duration}
+		switch op {
+		case 0, 1, 2, 3:
+			step.hits = operand
+		case 4:
+			// Over the limit
+			step.hits = addInt64(limit, operand+1)
+		case 5:
+			limit = operand + 1
+			step.limit, step.hits = limit, 1
+		case 6:
+			duration = (operand + 1) * Second
+			step.duration, step.hits = duration, 1
+		case 7:
+			// Query only
+		}
+		if jump < 200 {
+			step.jump = int64(1) << (jump % 40)
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// fuzzSequence evaluates the requests decoded from `program` with `algorithm` against a frozen
+// clock, calling `check` with each request, its response and the response of the previous request
+// of the same window, IE: with the same limit and duration, or nil if this is the first request of
+// the window.
+func fuzzSequence(t *testing.T, algorithm func(context.Context, Store, Cache, *Config, *RateLimitReq, RateLimitReqState) (*RateLimitResp, error),
+	conf *Config, limit, duration int64, behavior int32, program []byte, check func(r *RateLimitReq, rl, prev *RateLimitResp)) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	cache := NewLRUCache(0)
+	var prev *RateLimitResp
+	for _, step := range decodeFuzzSteps(limit, duration, program) {
+		clock.Advance(clock.Duration(step.jump) * clock.Millisecond)
+		createdAt := MillisecondNow()
+		r := &RateLimitReq{
+			Name:      "fuzz",
+			UniqueKey: "account:1234",
+			Limit:     step.limit,
+			Duration:  step.duration,
+			Hits:      step.hits,
+			Behavior:  Behavior(behavior) & (Behavior_GREEDY_REFILL | Behavior_DRAIN_OVER_LIMIT),
+			CreatedAt: &createdAt,
+		}
+		if validateBounds(r) != nil {
+			return
+		}
+		if prev != nil && (prev.Limit != r.Limit || step.duration != duration) {
+			prev = nil
+		}
+		duration = step.duration
+
+		rl, err := algorithm(context.Background(), nil, cache, conf, r, RateLimitReqState{IsOwner: true})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, rl.Remaining, int64(0))
+		assert.LessOrEqual(t, rl.Remaining, largest(r.Limit, r.Burst))
+		check(r, rl, prev)
+		prev = rl
+	}
+}
+
+func FuzzTokenBucketSequence(f *testing.F) {
+	f.Add(int64(10), int64(Minute), int32(0), []byte{0, 3, 0, 0, 3, 10, 4, 0, 200, 0, 7, 30, 7, 0, 200})
+	f.Add(int64(100), int64(Second), int32(Behavior_GREEDY_REFILL), []byte{0, 60, 200, 0, 60, 5, 5, 20, 200, 6, 2, 9, 7, 0, 12})
+	f.Add(int64(1), int64(1), int32(Behavior_DRAIN_OVER_LIMIT), []byte{4, 0, 0, 0, 1, 0, 0, 1, 39, 6, 255, 0})
+	conf := &Config{}
+	require.NoError(f, conf.SetDefaults())
+
+	f.Fuzz(func(t *testing.T, limit, duration int64, behavior int32, program []byte) {
+		fuzzSequence(t, tokenBucket, conf, limit, duration, behavior, program, func(r *RateLimitReq, rl, prev *RateLimitResp) {
+			// The window of a token bucket is only extended by a refill. A refill advances the
+			// bucket by whole milliseconds, which may move its reset time back by one millisecond.
+			if prev != nil {
+				assert.GreaterOrEqual(t, rl.ResetTime, prev.ResetTime-1)
+			}
+		})
+	})
+}
+
+func FuzzLeakyBucketSequence(f *testing.F) {
+	f.Add(int64(10), int64(Minute), int32(0), []byte{0, 3, 0, 0, 3, 10, 4, 0, 200, 0, 7, 30, 7, 0, 200})
+	f.Add(int64(3), int64(Second), int32(Behavior_DRAIN_OVER_LIMIT), []byte{4, 0, 200, 0, 1, 8, 5, 20, 200, 6, 2, 9, 7, 0, 12})
+	f.Add(int64(1), int64(1), int32(0), []byte{4, 0, 0, 0, 1, 0, 0, 1, 39, 6, 255, 0})
+	conf := &Config{}
+	require.NoError(f, conf.SetDefaults())
+
+	f.Fuzz(func(t *testing.T, limit, duration int64, behavior int32, program []byte) {
+		fuzzSequence(t, leakyBucket, conf, limit, duration, behavior, program, func(r *RateLimitReq, rl, prev *RateLimitResp) {
+			// The reset time only moves ahead when hits are taken. It is calculated from the whole
+			// hits remaining as of the request rather than the last leak, as such the hits which
+			// have yet to leak may move it back by up to two leak intervals.
+			if prev != nil {
+				interval := floatToInt64(math.Ceil(2 * float64(r.Duration) / float64(r.Limit)))
+				assert.GreaterOrEqual(t, rl.ResetTime, addInt64(prev.ResetTime, -interval))
+			}
+		})
+	})
+}
+
+// maxRemaining returns the most hits which may remain after `r`. Negative hits return hits to the
+// rate limit, they may raise the remaining hits above the limit by design.
+func maxRemaining(r *RateLimitReq, capacity int64) int64 {
+	if r.Hits < 0 {
+		return MaxLimit
+	}
+	return capacity
+}
+
+func largest(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func TestSaturatingArithmetic(t *testing.T) {
+	assert.Equal(t, int64(3), addInt64(1, 2))
+	assert.Equal(t, int64(math.MaxInt64), addInt64(math.MaxInt64, 1))
+	assert.Equal(t, int64(math.MinInt64), addInt64(math.MinInt64, -1))
+	assert.Equal(t, int64(6), mulInt64(2, 3))
+	assert.Equal(t, int64(math.MaxInt64), mulInt64(math.MaxInt64, 2))
+	assert.Equal(t, int64(math.MinInt64), mulInt64(math.MaxInt64, -2))
+	assert.Equal(t, int64(math.MaxInt64), mulInt64(math.MinInt64, -1))
+	assert.Equal(t, int64(0), floatToInt64(math.NaN()))
+	assert.Equal(t, int64(math.MaxInt64), floatToInt64(math.Inf(1)))
+	assert.Equal(t, int64(math.MinInt64), floatToInt64(math.Inf(-1)))
+	assert.Equal(t, int64(7), applyHits(10, 3))
+	assert.Equal(t, int64(13), applyHits(10, -3))
+	assert.Equal(t, MaxLimit, applyHits(MaxLimit, -MaxLimit))
+	assert.Equal(t, -MaxLimit, applyHits(0, MaxLimit))
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+type AuditType string
+
+const (
+	// AuditOverLimit is recorded by the owning peer each time a rate limit is over the limit
+	AuditOverLimit AuditType = "over_limit"
+	// AuditResetRateLimits is recorded by the peer which received an AdminV1.ResetRateLimits request
+	AuditResetRateLimits AuditType = "reset_rate_limits"
+	// AuditSetOverride is recorded by the peer which received an AdminV1.SetOverride request
+	AuditSetOverride AuditType = "set_override"
+	// AuditDeleteOverride is recorded by the peer which received an AdminV1.DeleteOverride request
+	AuditDeleteOverride AuditType = "delete_override"
+)
+
+// AuditRecord describes a rate limit decision or administrative change recorded by an AuditSink
+type AuditRecord struct {
+	Time       time.Time `json:"time"`
+	Type       AuditType `json:"type"`
+	InstanceID string    `json:"instance_id,omitempty"`
+
+	// Set for AuditOverLimit, AuditSetOverride and AuditDeleteOverride
+	Name      string `json:"name,omitempty"`
+	UniqueKey string `json:"unique_key,omitempty"`
+	Hits      int64  `json:"hits,omitempty"`
+	Limit     int64  `json:"limit,omitempty"`
+	Remaining int64  `json:"remaining,omitempty"`
+	Duration  int64  `json:"duration,omitempty"`
+	DryRun    bool   `json:"dry_run,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// The request values of the client, IE: its identity, see WithRequestValue()
+	Values map[string]string `json:"values,omitempty"`
+
+	// Set for AuditSetOverride
+	Action   string `json:"action,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	ExpireAt int64  `json:"expire_at,omitempty"`
+
+	// Set for AuditResetRateLimits, Errors is also set for AuditSetOverride and AuditDeleteOverride
+	NamePrefix string   `json:"name_prefix,omitempty"`
+	NameGlob   string   `json:"name_glob,omitempty"`
+	Removed    int64    `json:"removed,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// AuditSink receives a record for every OVER_LIMIT decision and every administrative change, such
+// that throttling can be investigated after the fact. Record() is called while handling requests,
+// as such implementations MUST NOT block and MUST be threadsafe. Implementations which ship
+// records to a remote system, IE: Kafka, can use NewAuditSink() to buffer and batch records.
+type AuditSink interface {
+	Record(AuditRecord)
+	Close() error
+}
+
+// AuditWriteFunc writes a batch of records to the destination of an AuditSink
+type AuditWriteFunc func(ctx context.Context, records []AuditRecord) error
+
+type bufferedAuditSink struct {
+	records chan AuditRecord
+	write   AuditWriteFunc
+	log     FieldLogger
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewAuditSink returns an AuditSink which buffers up to 10,000 records and calls `write` with a
+// batch of records at least once a second. Records are dropped and counted by the
+// `gubernator_audit_dropped_counter` metric if the buffer is full.
+func NewAuditSink(write AuditWriteFunc, log FieldLogger) AuditSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &bufferedAuditSink{
+		records: make(chan AuditRecord, 10_000),
+		write:   write,
+		log:     log,
+		cancel:  cancel,
+	}
+	s.wg.Add(1)
+	go s.run(ctx)
+	return s
+}
+
+func (s *bufferedAuditSink) Record(r AuditRecord) {
+	select {
+	case s.records <- r:
+	default:
+		metricAuditDropped.Inc()
+	}
+}
+
+func (s *bufferedAuditSink) run(ctx context.Context) {
+	defer s.wg.Done()
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+	var batch []AuditRecord
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.write(ctx, batch); err != nil {
+			metricAuditDropped.Add(float64(len(batch)))
+			s.log.WithError(err).Errorf("while writing '%d' audit records", len(batch))
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case r, ok := <-s.records:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= 1_000 {
+				flush()
+			}
+		case <-ticker.C():
+			flush()
+		}
+	}
+}
+
+// Close flushes the buffered records. Record() must not be called after Close()
+func (s *bufferedAuditSink) Close() error {
+	s.once.Do(func() {
+		close(s.records)
+		s.wg.Wait()
+		s.cancel()
+	})
+	return nil
+}
+
+// NewFileAuditSink returns an AuditSink which appends each record to the file at `path` as a
+// line of JSON.
+func NewFileAuditSink(path string, log FieldLogger) (AuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while opening audit file '%s'", path)
+	}
+	sink := NewAuditSink(func(_ context.Context, records []AuditRecord) error {
+		w := bufio.NewWriter(f)
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}, log)
+	return &fileAuditSink{AuditSink: sink, file: f}, nil
+}
+
+type fileAuditSink struct {
+	AuditSink
+	file *os.File
+}
+
+func (s *fileAuditSink) Close() error {
+	_ = s.AuditSink.Close()
+	return s.file.Close()
+}
+
+// NewWebhookAuditSink returns an AuditSink which POSTs each batch of records to `url` as a JSON array.
+func NewWebhookAuditSink(url string, log FieldLogger) AuditSink {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return NewAuditSink(func(ctx context.Context, records []AuditRecord) error {
+		b, err := json.Marshal(records)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("audit webhook '%s' returned '%s'", url, resp.Status)
+		}
+		return nil
+	}, log)
+}
+
+func (s *V1Instance) auditOverLimit(ctx context.Context, r *RateLimitReq, resp *RateLimitResp) {
+	s.conf.AuditSink.Record(AuditRecord{
+		Time:       clock.Now(),
+		Type:       AuditOverLimit,
+		InstanceID: s.conf.InstanceID,
+		Name:       r.Name,
+		UniqueKey:  r.UniqueKey,
+		Hits:       r.Hits,
+		Limit:      resp.Limit,
+		Remaining:  resp.Remaining,
+		Duration:   r.Duration,
+		DryRun:     HasBehavior(r.Behavior, Behavior_DRY_RUN),
+		RequestID:  r.RequestId,
+		Values:     RequestValues(ctx),
+	})
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// The max number of values of a single DescribeInstances filter
+const awsMaxFilterValues = 200
+
+type AWSPoolConfig struct {
+	// (Optional) Discover the running EC2 instances which have every one of these tags,
+	// IE: {"gubernator-cluster": "production"}
+	Tags map[string]string
+
+	// (Optional) Discover the in service instances of these Auto Scaling Groups. If Tags is also
+	// set, only the instances of the groups which have every tag are discovered. At least one of
+	// Tags or AutoScalingGroups is required.
+	AutoScalingGroups []string
+
+	// (Optional) The AWS region of the instances. Defaults to the region of the default AWS
+	// configuration, IE: AWS_REGION or the instance metadata service
+	Region string
+
+	// (Required) The port the peers listen for GRPC requests on
+	GRPCPort string
+
+	// (Required) The port the peers listen for HTTP requests on
+	HTTPPort string
+
+	// (Required) Own GRPC address
+	OwnAddress string
+
+	// (Optional) The data center of the discovered peers
+	DataCenter string
+
+	// (Optional) How often the instances are discovered. Defaults to 30 seconds
+	RefreshInterval time.Duration
+
+	// (Optional) Also discover the instances when the process receives SIGUSR1, IE: from the
+	// lifecycle hook of an Auto Scaling Group. Ignored on Windows
+	RefreshOnSignal bool
+
+	// (Optional) The clients used to discover the instances, override for testing. Default to
+	// clients created from the default AWS configuration
+	EC2         ec2.DescribeInstancesAPIClient
+	AutoScaling autoscaling.DescribeAutoScalingGroupsAPIClient
+
+	// (Required) Called when the list of gubernators in the pool updates
+	OnUpdate UpdateFunc
+
+	Logger FieldLogger
+}
+
+// AWSPool discovers the peers running on EC2 instances by their tags or Auto Scaling Group
+type AWSPool struct {
+	log     FieldLogger
+	conf    AWSPoolConfig
+	refresh chan os.Signal
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func NewAWSPool(conf AWSPoolConfig) (*AWSPool, error) {
+	setter.SetDefault(&conf.Logger, logrus.WithField("category", "gubernator"))
+	setter.SetDefault(&conf.RefreshInterval, clock.Second*30)
+
+	switch {
+	case conf.OwnAddress == "":
+		return nil, errors.New("OwnAddress is required")
+	case conf.GRPCPort == "" || conf.HTTPPort == "":
+		return nil, errors.New("GRPCPort and HTTPPort are required")
+	case len(conf.Tags) == 0 && len(conf.AutoScalingGroups) == 0:
+		return nil, errors.New("at least one of Tags or AutoScalingGroups is required")
+	case conf.RefreshInterval < 0:
+		return nil, errors.New("RefreshInterval cannot be negative")
+	}
+
+	if conf.EC2 == nil || (conf.AutoScaling == nil && len(conf.AutoScalingGroups) != 0) {
+		var opts []func(*awsconfig.LoadOptions) error
+		if conf.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(conf.Region))
+		}
+		awsConf, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+		if err != nil {
+			return nil, errors.Wrap(err, "while loading the AWS configuration")
+		}
+		if conf.EC2 == nil {
+			conf.EC2 = ec2.NewFromConfig(awsConf)
+		}
+		if conf.AutoScaling == nil {
+			conf.AutoScaling = autoscaling.NewFromConfig(awsConf)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool := &AWSPool{
+		log:     conf.Logger,
+		conf:    conf,
+		refresh: make(chan os.Signal, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	if conf.RefreshOnSignal {
+		notifyRefresh(pool.refresh)
+	}
+	go pool.run()
+	return pool, nil
+}
+
+func (p *AWSPool) run() {
+	defer close(p.done)
+	tick := clock.NewTicker(p.conf.RefreshInterval)
+	defer tick.Stop()
+
+	for {
+		if err := p.update(); err != nil {
+			p.log.WithError(err).Error("while discovering the peers on AWS")
+		}
+		select {
+		case <-tick.C():
+		case <-p.refresh:
+			p.log.Info("discovering the peers on AWS on signal")
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// update discovers the instances and calls OnUpdate with their peers
+func (p *AWSPool) update() error {
+	ctx, cancel := context.WithTimeout(p.ctx, p.conf.RefreshInterval)
+	defer cancel()
+
+	ips, err := p.discover(ctx)
+	if err != nil {
+		return err
+	}
+	// Keep the current peers rather than remove every peer, IE: while the tags are being changed
+	if len(ips) == 0 {
+		return errors.New("no running instances match the Tags and AutoScalingGroups")
+	}
+
+	var peers []PeerInfo
+	for _, ip := range ips {
+		grpc := net.JoinHostPort(ip, p.conf.GRPCPort)
+		peers = append(peers, PeerInfo{
+			DataCenter:  p.conf.DataCenter,
+			HTTPAddress: net.JoinHostPort(ip, p.conf.HTTPPort),
+			GRPCAddress: grpc,
+			IsOwner:     grpc == p.conf.OwnAddress,
+		})
+	}
+	p.conf.OnUpdate(peers)
+	return nil
+}
+
+// discover returns the sorted private IP addresses of the running instances which match the config
+func (p *AWSPool) discover(ctx context.Context) ([]string, error) {
+	filters := []ec2types.Filter{{Name: aws.String("instance-state-name"), Values: []string{"running"}}}
+	for key, value := range p.conf.Tags {
+		filters = append(filters, ec2types.Filter{Name: aws.String("tag:" + key), Values: []string{value}})
+	}
+	if len(p.conf.AutoScalingGroups) == 0 {
+		return p.describeInstances(ctx, filters)
+	}
+
+	ids, err := p.groupInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ips []string
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > awsMaxFilterValues {
+			n = awsMaxFilterValues
+		}
+		batch := append(filters[:len(filters):len(filters)], ec2types.Filter{Name: aws.String("instance-id"), Values: ids[:n]})
+		found, err := p.describeInstances(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, found...)
+		ids = ids[n:]
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+// groupInstances returns the ids of the in service instances of the Auto Scaling Groups
+func (p *AWSPool) groupInstances(ctx context.Context) ([]string, error) {
+	var ids []string
+	pages := autoscaling.NewDescribeAutoScalingGroupsPaginator(p.conf.AutoScaling, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: p.conf.AutoScalingGroups,
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "during DescribeAutoScalingGroups()")
+		}
+		for _, group := range page.AutoScalingGroups {
+			for _, instance := range group.Instances {
+				if instance.LifecycleState == asgtypes.LifecycleStateInService && instance.InstanceId != nil {
+					ids = append(ids, *instance.InstanceId)
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// describeInstances returns the sorted private IP addresses of the instances which match the filters
+func (p *AWSPool) describeInstances(ctx context.Context, filters []ec2types.Filter) ([]string, error) {
+	var ips []string
+	pages := ec2.NewDescribeInstancesPaginator(p.conf.EC2, &ec2.DescribeInstancesInput{Filters: filters})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "during DescribeInstances()")
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.PrivateIpAddress != nil {
+					ips = append(ips, *instance.PrivateIpAddress)
+				}
+			}
+		}
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (p *AWSPool) Close() {
+	stopRefresh(p.refresh)
+	p.cancel()
+	<-p.done
+}
+
+// ParseAWSTag parses a tag of the form 'key=value'
+func ParseAWSTag(s string) (string, string, error) {
+	key, value, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok || key == "" {
+		return "", "", errors.Errorf("'%s' is invalid; expected format is 'key=value'", s)
+	}
+	return key, value, nil
+}
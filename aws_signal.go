@@ -0,0 +1,35 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyRefresh relays SIGUSR1 to `c`, see AWSPoolConfig.RefreshOnSignal
+func notifyRefresh(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
+
+func stopRefresh(c chan<- os.Signal) {
+	signal.Stop(c)
+}
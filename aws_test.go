@@ -0,0 +1,128 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEC2 returns one page of instances for each instance in `ips`, such that pagination is exercised
+type fakeEC2 struct {
+	mutex   sync.Mutex
+	ips     map[string]string
+	filters [][]ec2types.Filter
+}
+
+func (f *fakeEC2) DescribeInstances(_ context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.filters = append(f.filters, in.Filters)
+
+	var ids []string
+	for _, filter := range in.Filters {
+		if *filter.Name == "instance-id" {
+			ids = filter.Values
+		}
+	}
+	var matched []string
+	for _, id := range ids {
+		if _, ok := f.ips[id]; ok {
+			matched = append(matched, id)
+		}
+	}
+
+	var i int
+	if in.NextToken != nil {
+		for i = range matched {
+			if matched[i] == *in.NextToken {
+				break
+			}
+		}
+	}
+	if i >= len(matched) {
+		return &ec2.DescribeInstancesOutput{}, nil
+	}
+	out := &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{
+		Instances: []ec2types.Instance{{InstanceId: aws.String(matched[i]), PrivateIpAddress: aws.String(f.ips[matched[i]])}},
+	}}}
+	if i+1 < len(matched) {
+		out.NextToken = aws.String(matched[i+1])
+	}
+	return out, nil
+}
+
+type fakeAutoScaling struct{}
+
+func (fakeAutoScaling) DescribeAutoScalingGroups(context.Context, *autoscaling.DescribeAutoScalingGroupsInput, ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	return &autoscaling.DescribeAutoScalingGroupsOutput{AutoScalingGroups: []asgtypes.AutoScalingGroup{{
+		Instances: []asgtypes.Instance{
+			{InstanceId: aws.String("i-1"), LifecycleState: asgtypes.LifecycleStateInService},
+			{InstanceId: aws.String("i-2"), LifecycleState: asgtypes.LifecycleStateInService},
+			{InstanceId: aws.String("i-3"), LifecycleState: asgtypes.LifecycleStatePending},
+		},
+	}}}, nil
+}
+
+func TestAWSPool(t *testing.T) {
+	ec2Client := &fakeEC2{ips: map[string]string{"i-1": "10.0.0.1", "i-2": "10.0.0.2", "i-3": "10.0.0.3"}}
+	updates := make(chan []guber.PeerInfo, 1)
+	pool, err := guber.NewAWSPool(guber.AWSPoolConfig{
+		Tags:              map[string]string{"gubernator-cluster": "production"},
+		AutoScalingGroups: []string{"gubernator"},
+		GRPCPort:          "1051",
+		HTTPPort:          "1050",
+		OwnAddress:        "10.0.0.2:1051",
+		DataCenter:        "us-east-1",
+		RefreshInterval:   time.Hour,
+		EC2:               ec2Client,
+		AutoScaling:       fakeAutoScaling{},
+		OnUpdate:          func(peers []guber.PeerInfo) { updates <- peers },
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	select {
+	case peers := <-updates:
+		assert.Equal(t, []guber.PeerInfo{
+			{DataCenter: "us-east-1", HTTPAddress: "10.0.0.1:1050", GRPCAddress: "10.0.0.1:1051"},
+			{DataCenter: "us-east-1", HTTPAddress: "10.0.0.2:1050", GRPCAddress: "10.0.0.2:1051", IsOwner: true},
+		}, peers)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the peers")
+	}
+
+	ec2Client.mutex.Lock()
+	defer ec2Client.mutex.Unlock()
+	require.NotEmpty(t, ec2Client.filters)
+	assert.Contains(t, ec2Client.filters[0], ec2types.Filter{Name: aws.String("tag:gubernator-cluster"), Values: []string{"production"}})
+	assert.Contains(t, ec2Client.filters[0], ec2types.Filter{Name: aws.String("instance-state-name"), Values: []string{"running"}})
+
+	_, err = guber.NewAWSPool(guber.AWSPoolConfig{GRPCPort: "1051", HTTPPort: "1050", OwnAddress: "10.0.0.2:1051", EC2: ec2Client})
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Policies for a request which found no room in the batch queue of a peer, see
+// BehaviorConfig.BatchOverflowPolicy
+const (
+	// BatchOverflowSpill sends the request to the peer immediately in a request of its own
+	BatchOverflowSpill = "spill"
+	// BatchOverflowReject fails the request with RESOURCE_EXHAUSTED
+	BatchOverflowReject = "reject"
+	// BatchOverflowDrop responds UNDER_LIMIT without sending the request to the peer, as such the
+	// hits of the request are not counted
+	BatchOverflowDrop = "drop"
+)
+
+// MetadataBatchOverflow is the metadata of the response to a rate limit which was dropped by
+// BatchOverflowDrop instead of being evaluated
+const MetadataBatchOverflow = "batch_overflow"
+
+func validateBatchOverflowPolicy(policy string) error {
+	switch policy {
+	case BatchOverflowSpill, BatchOverflowReject, BatchOverflowDrop:
+		return nil
+	}
+	return fmt.Errorf("Behaviors.BatchOverflowPolicy '%s' is invalid; expected one of '%s', '%s' or '%s'",
+		policy, BatchOverflowSpill, BatchOverflowReject, BatchOverflowDrop)
+}
+
+// enqueue adds the request to the batch queue, waiting up to BatchQueueWait for room in a full
+// queue. The queue is full once BatchQueueLimit requests wait to be sent or for the response of the
+// peer. Returns false if the queue is still full, or an error if the context was cancelled.
+func (c *PeerClient) enqueue(ctx context.Context, req *request) (bool, error) {
+	select {
+	case c.queued <- struct{}{}:
+	default:
+		select {
+		case c.queued <- struct{}{}:
+		case <-clock.After(c.conf.Behavior.BatchQueueWait):
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	req.queued = true
+
+	select {
+	case c.queue <- req:
+		return true, nil
+	case <-ctx.Done():
+		c.dequeue([]*request{req})
+		return false, ctx.Err()
+	}
+}
+
+// dequeue makes room in the batch queue for the requests which were sent
+func (c *PeerClient) dequeue(queue []*request) {
+	for _, r := range queue {
+		if r.queued {
+			r.queued = false
+			<-c.queued
+		}
+	}
+}
+
+// overflow applies BatchOverflowPolicy to the request which found no room in the batch queue,
+// and responds to the request and every request coalesced into it.
+func (c *PeerClient) overflow(ctx context.Context, req *request) {
+	policy := c.conf.Behavior.BatchOverflowPolicy
+	metricBatchOverflowCounter.WithLabelValues(policy).Inc()
+
+	switch policy {
+	case BatchOverflowSpill:
+		c.sendBatch(ctx, []*request{req})
+		return
+	case BatchOverflowDrop:
+		if c.coalescing != nil {
+			c.stopCoalescing([]*request{req})
+		}
+		req.respond(&response{rl: &RateLimitResp{
+			Status:    Status_UNDER_LIMIT,
+			Limit:     req.request.Limit,
+			Remaining: req.request.Limit,
+			Metadata:  map[string]string{MetadataBatchOverflow: "dropped"},
+		}})
+		return
+	}
+	if c.coalescing != nil {
+		c.stopCoalescing([]*request{req})
+	}
+	req.respond(&response{err: status.Errorf(codes.ResourceExhausted,
+		"batch queue of peer '%s' is full", c.conf.Info.GRPCAddress)})
+}
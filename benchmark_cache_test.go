@@ -155,6 +155,26 @@ func BenchmarkCache(b *testing.B) {
 				wg.Wait()
 			})
 
+			b.Run("Churn", func(b *testing.B) {
+				// Write more keys than the cache can hold, such that most writes evict an item
+				cache := gubernator.NewLRUCache(1000)
+				expire := clock.Now().Add(time.Hour).UnixMilli()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					key := strconv.Itoa(i % 10_000)
+					if _, ok := cache.GetItem(key); ok {
+						continue
+					}
+					cache.Add(&gubernator.CacheItem{
+						Key:      key,
+						Value:    i,
+						ExpireAt: expire,
+					})
+				}
+			})
 		})
 	}
 }
@@ -18,6 +18,8 @@ package gubernator_test
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"testing"
 
 	guber "github.com/gubernator-io/gubernator/v2"
@@ -25,6 +27,7 @@ import (
 	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/syncutil"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 )
 
 func BenchmarkServer(b *testing.B) {
@@ -107,6 +110,90 @@ func BenchmarkServer(b *testing.B) {
 		}
 	})
 
+	b.Run("GetRateLimits single key", func(b *testing.B) {
+		client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+		require.NoError(b, err, "Error in guber.DialV1Server")
+		b.ResetTimer()
+
+		// Every request hits the same rate limit, as a single busy tenant would
+		for n := 0; n < b.N; n++ {
+			_, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      b.Name(),
+						UniqueKey: "account:1234",
+						Limit:     1_000_000_000,
+						Duration:  guber.Minute,
+						Hits:      1,
+					},
+				},
+			})
+			if err != nil {
+				b.Errorf("Error in client.GetRateLimits: %s", err)
+			}
+		}
+	})
+
+	b.Run("GetRateLimits batch 1000", func(b *testing.B) {
+		client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+		require.NoError(b, err, "Error in guber.DialV1Server")
+		req := &guber.GetRateLimitsReq{Requests: make([]*guber.RateLimitReq, 1000)}
+		for i := range req.Requests {
+			req.Requests[i] = &guber.RateLimitReq{
+				Name:      b.Name(),
+				UniqueKey: fmt.Sprintf("account:%d", i),
+				Limit:     1_000_000_000,
+				Duration:  guber.Minute,
+				Hits:      1,
+			}
+		}
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			if _, err := client.GetRateLimits(ctx, req); err != nil {
+				b.Errorf("Error in client.GetRateLimits: %s", err)
+			}
+		}
+	})
+
+	b.Run("GetRateLimits 90% forwarded", func(b *testing.B) {
+		peer := cluster.PeerAt(0)
+		client, err := guber.DialV1Server(peer.GRPCAddress, nil)
+		require.NoError(b, err, "Error in guber.DialV1Server")
+
+		// Choose keys such that 9 of every 10 requests are owned by another peer
+		var owned, forwarded []string
+		for i := 0; len(owned) < 10 || len(forwarded) < 90; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			owner, err := cluster.FindOwningPeer(b.Name(), key)
+			require.NoError(b, err)
+			if owner.GRPCAddress == peer.GRPCAddress {
+				owned = append(owned, key)
+			} else {
+				forwarded = append(forwarded, key)
+			}
+		}
+		keys := append(owned[:10], forwarded[:90]...)
+		b.ResetTimer()
+
+		for n := 0; n < b.N; n++ {
+			_, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      b.Name(),
+						UniqueKey: keys[n%len(keys)],
+						Limit:     1_000_000_000,
+						Duration:  guber.Minute,
+						Hits:      1,
+					},
+				},
+			})
+			if err != nil {
+				b.Errorf("Error in client.GetRateLimits: %s", err)
+			}
+		}
+	})
+
 	b.Run("HealthCheck", func(b *testing.B) {
 		client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
 		require.NoError(b, err, "Error in guber.DialV1Server")
@@ -146,3 +233,111 @@ func BenchmarkServer(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkWorkerPool measures concurrent hits on distinct keys as the number of workers grows. Each
+// worker owns a shard of the cache and applies hits sequentially without locking, as such hits on
+// different keys only wait for each other when the keys are owned by the same worker.
+func BenchmarkWorkerPool(b *testing.B) {
+	ctx := context.Background()
+	createdAt := epochMillis(clock.Now())
+
+	for _, workers := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			conf := &guber.Config{Workers: workers}
+			require.NoError(b, conf.SetDefaults())
+			pool := guber.NewWorkerPool(conf)
+			defer pool.Close()
+
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				prefix := guber.RandomString(10)
+				for i := 0; pb.Next(); i++ {
+					_, err := pool.GetRateLimit(ctx, &guber.RateLimitReq{
+						Name:      "bench_worker_pool",
+						UniqueKey: prefix + strconv.Itoa(i%1000),
+						Limit:     1_000_000,
+						Duration:  guber.Minute,
+						Hits:      1,
+						CreatedAt: &createdAt,
+					}, guber.RateLimitReqState{IsOwner: true})
+					if err != nil {
+						b.Errorf("Error in pool.GetRateLimit: %s", err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkWorkerPoolReads measures query only requests (Hits = 0) of the same rate limits, which
+// are evaluated by the worker which owns each rate limit unless Config.LockFreeReads. Compare with
+// `-cpu 1,4,16`; lock free reads scale with the number of cores.
+func BenchmarkWorkerPoolReads(b *testing.B) {
+	ctx := context.Background()
+	createdAt := epochMillis(clock.Now())
+
+	for _, lockFree := range []bool{false, true} {
+		b.Run(fmt.Sprintf("LockFreeReads=%t", lockFree), func(b *testing.B) {
+			conf := &guber.Config{Workers: 4, LockFreeReads: lockFree}
+			require.NoError(b, conf.SetDefaults())
+			pool := guber.NewWorkerPool(conf)
+			defer pool.Close()
+
+			newReq := func(i int, hits int64) *guber.RateLimitReq {
+				return &guber.RateLimitReq{
+					Name:      "bench_worker_pool_reads",
+					UniqueKey: strconv.Itoa(i % 100),
+					Limit:     1_000_000,
+					Duration:  guber.Minute,
+					Hits:      hits,
+					CreatedAt: &createdAt,
+				}
+			}
+			for i := 0; i < 100; i++ {
+				_, err := pool.GetRateLimit(ctx, newReq(i, 1), guber.RateLimitReqState{IsOwner: true})
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					_, err := pool.GetRateLimit(ctx, newReq(i, 0), guber.RateLimitReqState{IsOwner: true})
+					if err != nil {
+						b.Errorf("Error in pool.GetRateLimit: %s", err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkGetRateLimitsAllocs reports the allocations of a batch of 10 rate limits evaluated by
+// the instance which owns them, without the gRPC transport. Use `-benchmem` or compare
+// `allocs/op` when changing the path a request takes through the instance.
+func BenchmarkGetRateLimitsAllocs(b *testing.B) {
+	ctx := context.Background()
+	srv, err := guber.NewV1Instance(guber.Config{GRPCServers: []*grpc.Server{grpc.NewServer()}})
+	require.NoError(b, err)
+	defer srv.Close()
+	srv.SetPeers([]guber.PeerInfo{{GRPCAddress: "127.0.0.1:1", IsOwner: true}})
+
+	reqs := make([]*guber.RateLimitReq, 10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range reqs {
+			reqs[i] = &guber.RateLimitReq{
+				Name:      "bench_allocs",
+				UniqueKey: strconv.Itoa(i),
+				Limit:     1 << 40,
+				Duration:  guber.Minute,
+				Hits:      1,
+			}
+		}
+		_, err := srv.GetRateLimits(ctx, &guber.GetRateLimitsReq{Requests: reqs})
+		if err != nil {
+			b.Errorf("Error in srv.GetRateLimits: %s", err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// MaxDuration is the longest duration in milliseconds a rate limit may request, about 1,000 years.
+// The reset time of a rate limit with a longer duration risks overflowing int64.
+const MaxDuration int64 = 1000 * 365 * 24 * 60 * Minute
+
+// MaxLimit is the largest limit, burst, max capacity or number of hits a rate limit may request.
+// The leaky bucket counts the remaining hits as a float64, which cannot represent every integer
+// above 2^53. Negative hits, which return hits to a rate limit, cannot raise the remaining hits
+// above MaxLimit.
+const MaxLimit int64 = 1 << 53
+
+// validateBounds returns an error if the limit, burst, hits or duration of the request are outside
+// the range the algorithms can count without overflowing. A duration of zero is valid, such a rate
+// limit expires as soon as it is created.
+func validateBounds(r *RateLimitReq) error {
+	switch {
+	case r.Limit < 0:
+		return errors.New("field 'limit' cannot be negative")
+	case r.Limit > MaxLimit:
+		return fmt.Errorf("field 'limit' of '%d' exceeds the max limit '%d'", r.Limit, MaxLimit)
+	case r.Burst < 0:
+		return errors.New("field 'burst' cannot be negative")
+	case r.Burst > MaxLimit:
+		return fmt.Errorf("field 'burst' of '%d' exceeds the max limit '%d'", r.Burst, MaxLimit)
+	case r.Hits > MaxLimit || r.Hits < -MaxLimit:
+		return fmt.Errorf("field 'hits' of '%d' exceeds the max hits of '%d' or '-%d'", r.Hits, MaxLimit, MaxLimit)
+	case r.MaxCapacity < 0:
+		return errors.New("field 'max_capacity' cannot be negative")
+	case r.MaxCapacity > MaxLimit:
+		return fmt.Errorf("field 'max_capacity' of '%d' exceeds the max limit '%d'", r.MaxCapacity, MaxLimit)
+	case r.Duration < 0:
+		return errors.New("field 'duration' cannot be negative")
+	case r.Duration > MaxDuration && !HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN):
+		return fmt.Errorf("field 'duration' of '%d' exceeds the max duration '%d'", r.Duration, MaxDuration)
+	}
+	return nil
+}
+
+// addInt64 returns a + b, saturated at the bounds of int64 instead of overflowing
+func addInt64(a, b int64) int64 {
+	c := a + b
+	switch {
+	case b > 0 && c < a:
+		return math.MaxInt64
+	case b < 0 && c > a:
+		return math.MinInt64
+	}
+	return c
+}
+
+// floatToInt64 converts f to int64, saturated at the bounds of int64. NaN, which is the result
+// of dividing a zero duration by a zero limit, converts to 0.
+func floatToInt64(f float64) int64 {
+	switch {
+	case math.IsNaN(f):
+		return 0
+	case f >= math.MaxInt64:
+		return math.MaxInt64
+	case f <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(f)
+}
+
+// mulInt64 returns a * b, saturated at the bounds of int64 instead of overflowing
+func mulInt64(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	c := a * b
+	if c/b == a && !(a == -1 && b == math.MinInt64) && !(b == -1 && a == math.MinInt64) {
+		return c
+	}
+	if (a > 0) == (b > 0) {
+		return math.MaxInt64
+	}
+	return math.MinInt64
+}
+
+// applyHits returns the remaining hits once `hits` are taken from `remaining`. The result never
+// exceeds MaxLimit, such that repeatedly returning hits with negative hits cannot overflow.
+func applyHits(remaining, hits int64) int64 {
+	if r := addInt64(remaining, -hits); r < MaxLimit {
+		return r
+	}
+	return MaxLimit
+}
+
+// leakyResetTime returns the time at which the hits missing from `remaining` to `limit` have
+// leaked back into the bucket at `rate` milliseconds per hit. A bucket with a limit of zero
+// never leaks, as such its reset time is `createdAt`.
+func leakyResetTime(createdAt, limit, remaining int64, rate float64) int64 {
+	if limit == 0 {
+		return createdAt
+	}
+	return addInt64(createdAt, mulInt64(limit-remaining, floatToInt64(rate)))
+}
@@ -26,10 +26,96 @@ type Cache interface {
 	Close() error
 }
 
+// ByteLimitedCache is an optional interface a Cache may implement to limit the
+// cache by the approximate number of bytes used by the items it holds.
+type ByteLimitedCache interface {
+	Cache
+	// SetMaxBytes sets the approximate max number of bytes the cache may use. Zero means no limit.
+	SetMaxBytes(maxBytes int64)
+	// Bytes returns the approximate number of bytes used by the items in the cache.
+	Bytes() int64
+}
+
+// IdleCache is an optional interface a Cache may implement to evict the items which have
+// not been accessed recently, independent of when the items expire. See Config.CacheIdleTTL
+type IdleCache interface {
+	Cache
+	// RemoveIdle removes the items last accessed before `before` in epoch milliseconds and
+	// returns the number of items removed.
+	RemoveIdle(before int64) int
+}
+
+// The policies which decide what happens when a rate limit which is not in the cache is requested
+// while every item in the cache is unexpired. See Config.CacheFullPolicy
+const (
+	// CacheFullEvictLRU evicts the least recently used rate limit, even if it has not expired
+	CacheFullEvictLRU = "evict-lru"
+	// CacheFullReject rejects requests for rate limits which are not in the cache with RESOURCE_EXHAUSTED
+	CacheFullReject = "reject"
+	// CacheFullEvictOldestReset evicts the rate limit which resets soonest, as it loses the least state
+	CacheFullEvictOldestReset = "evict-oldest-reset"
+	// CacheFullSpill saves the least recently used rate limit to the Store before evicting it, such
+	// that it is loaded from the Store the next time it is requested
+	CacheFullSpill = "spill"
+)
+
+// CapacityCache is an optional interface a Cache may implement to decide what happens when
+// a new item is added while the cache is full of unexpired items. See Config.CacheFullPolicy
+type CapacityCache interface {
+	Cache
+	// SetFullPolicy sets how the cache makes room for a new item. `onEvict` is called with each
+	// unexpired item evicted by the CacheFullSpill policy before it is removed.
+	SetFullPolicy(policy string, onEvict func(*CacheItem))
+	// IsFull returns true if adding an item for `key` would evict an unexpired item.
+	IsFull(key string) bool
+}
+
+// TenantCache is an optional interface a Cache may implement to cap the number of items each tenant
+// may hold, such that one tenant cannot evict the items of every other tenant. See Config.CacheTenantSeparator
+type TenantCache interface {
+	Cache
+	// SetTenantQuota partitions the cache by the tenant `tenantOf` returns for each item. Once a tenant
+	// holds `maxItems` of the tenant, its least recently used item is evicted to make room for a new item.
+	SetTenantQuota(tenantOf func(*CacheItem) string, maxItems func(tenant string) int)
+	// TenantSizes returns the number of items each tenant holds. Must be safe to call concurrently.
+	TenantSizes() map[string]int64
+}
+
+// HashedKeyCache is an optional interface a Cache may implement to index its items by a fixed size
+// hash of their key instead of the key itself, such that large caches of long keys use less memory.
+// See Config.CacheKeyHash
+type HashedKeyCache interface {
+	Cache
+	// SetKeyHash indexes the items by `hash` of their key. Items whose key hash collides with the
+	// key of another item must still be found by their key. Must be called before items are added.
+	SetKeyHash(hash HashString64)
+}
+
+// RemovalCache is an optional interface a Cache may implement to report the items which leave the
+// cache, such that copies of the items held elsewhere are released. See Config.LockFreeReads and BatchStore
+type RemovalCache interface {
+	Cache
+	// SetOnRemove sets a function which is called with each item removed from the cache, including
+	// the items which expired or were evicted.
+	SetOnRemove(onRemove func(item *CacheItem))
+}
+
+// ReadCopyCache is an optional interface a Cache may implement to evaluate query only requests
+// against a copy of the rate limit without waiting for the worker which owns the cache. See
+// Config.LockFreeReads
+type ReadCopyCache interface {
+	RemovalCache
+	// Peek returns the item stored for `key`, even if expired, without counting it as accessed.
+	Peek(key string) (*CacheItem, bool)
+}
+
 type CacheItem struct {
 	Algorithm Algorithm
 	Key       string
 	Value     interface{}
+	// The name of the rate limit, used to match rate limits reset via AdminV1.ResetRateLimits.
+	// Items loaded by a Loader or Store which do not set the name cannot be matched.
+	Name string
 
 	// Timestamp when rate limit expires in epoch milliseconds.
 	ExpireAt int64
@@ -40,6 +126,21 @@ type CacheItem struct {
 	InvalidAt int64
 }
 
+// copy returns a copy of the item and its value, such that the copy can be read while
+// the worker which owns the item continues to update it.
+func (item *CacheItem) copy() *CacheItem {
+	c := *item
+	switch v := item.Value.(type) {
+	case *TokenBucketItem:
+		t := *v
+		c.Value = &t
+	case *LeakyBucketItem:
+		l := *v
+		c.Value = &l
+	}
+	return &c
+}
+
 func (item *CacheItem) IsExpired() bool {
 	now := MillisecondNow()
 
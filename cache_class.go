@@ -0,0 +1,154 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CacheClass holds the rate limits of the names which begin with Prefix in a cache of their own,
+// such that rate limits with different lifetimes do not evict each other. IE: short lived abuse
+// counters in a large cache which is never persisted, and monthly billing quotas in a small
+// cache which is saved to the Store. See Config.CacheClasses
+type CacheClass struct {
+	// (Required) The prefix of the rate limit names held by the class, the class with the longest
+	// matching prefix wins
+	Prefix string
+
+	// (Required) The total number of rate limits held by the cache of the class, divided between
+	// the workers the same as Config.CacheSize
+	Size int
+
+	// (Optional) What happens when the cache of the class is full, see Config.CacheFullPolicy.
+	// Defaults to Config.CacheFullPolicy
+	FullPolicy string
+
+	// (Optional) Rate limits of the class which are not accessed within this duration are evicted,
+	// see Config.CacheIdleTTL. Defaults to Config.CacheIdleTTL
+	IdleTTL time.Duration
+
+	// (Optional) If true the rate limits of the class are neither saved to nor loaded from the Store
+	// or the Loader, as such they are lost when the instance restarts. Defaults to false
+	Volatile bool
+}
+
+func (c *CacheClass) validate(conf *Config) error {
+	switch {
+	case c.Prefix == "":
+		return errors.New("CacheClasses.Prefix cannot be empty")
+	case c.Size < 1:
+		return errors.Errorf("CacheClasses.Size of '%s' must be greater than 0", c.Prefix)
+	case c.IdleTTL < 0:
+		return errors.Errorf("CacheClasses.IdleTTL of '%s' cannot be negative", c.Prefix)
+	case c.IdleTTL > 0 && !c.Volatile && conf.Store == nil:
+		return errors.Errorf("CacheClasses.IdleTTL of '%s' requires Store unless the class is volatile", c.Prefix)
+	}
+	switch c.FullPolicy {
+	case CacheFullEvictLRU, CacheFullReject, CacheFullEvictOldestReset:
+	case CacheFullSpill:
+		if c.Volatile || conf.Store == nil {
+			return errors.Errorf("CacheClasses.FullPolicy 'spill' of '%s' requires Store and a class which is not volatile", c.Prefix)
+		}
+	default:
+		return errors.Errorf("CacheClasses.FullPolicy '%s' of '%s' is invalid; expected one of '%s', '%s', '%s' or '%s'",
+			c.FullPolicy, c.Prefix, CacheFullEvictLRU, CacheFullReject, CacheFullEvictOldestReset, CacheFullSpill)
+	}
+	return nil
+}
+
+// ParseCacheClass parses a class in the format used by `GUBER_CACHE_CLASSES`, a prefix followed by
+// semicolon separated options, IE: "abuse_;size=500000;idle_ttl=5m;volatile" or
+// "billing_;size=10000;full_policy=spill"
+func ParseCacheClass(s string) (CacheClass, error) {
+	parts := strings.Split(strings.TrimSpace(s), ";")
+	c := CacheClass{Prefix: parts[0]}
+	for _, part := range parts[1:] {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch k {
+		case "size":
+			c.Size, err = strconv.Atoi(v)
+		case "full_policy":
+			c.FullPolicy = v
+		case "idle_ttl":
+			c.IdleTTL, err = time.ParseDuration(v)
+		case "volatile":
+			c.Volatile = true
+		default:
+			return c, errors.Errorf("invalid option '%s' in cache class '%s'", part, s)
+		}
+		if err != nil {
+			return c, errors.Wrapf(err, "invalid option '%s' in cache class '%s'", part, s)
+		}
+	}
+	if c.Prefix == "" {
+		return c, errors.New("CacheClasses.Prefix cannot be empty")
+	}
+	return c, nil
+}
+
+// sortCacheClasses returns a copy of the classes ordered by longest prefix first
+func sortCacheClasses(classes []CacheClass) []CacheClass {
+	result := append([]CacheClass(nil), classes...)
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i].Prefix) > len(result[j].Prefix) })
+	return result
+}
+
+// classOf returns the index in `worker.classes` of the class of the rate limit name, or -1 if
+// the rate limit is held by the default cache
+func (worker *Worker) classOf(name string) int {
+	for i := range worker.classes {
+		if strings.HasPrefix(name, worker.classes[i].Prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// cacheFor returns the cache which holds the rate limits of the name
+func (worker *Worker) cacheFor(name string) Cache {
+	if i := worker.classOf(name); i >= 0 {
+		return worker.classCaches[i]
+	}
+	return worker.cache
+}
+
+// storeFor returns the Store of the rate limits of the name, which is nil for volatile classes
+func (worker *Worker) storeFor(name string) Store {
+	if i := worker.classOf(name); i >= 0 && worker.classes[i].Volatile {
+		return nil
+	}
+	return worker.conf.Store
+}
+
+// fullPolicyFor returns the CacheFullPolicy of the cache which holds the rate limits of the name
+func (worker *Worker) fullPolicyFor(name string) string {
+	if i := worker.classOf(name); i >= 0 {
+		return worker.classes[i].FullPolicy
+	}
+	return worker.conf.CacheFullPolicy
+}
+
+// caches returns the default cache followed by the cache of each class
+func (worker *Worker) caches() []Cache {
+	return append([]Cache{worker.cache}, worker.classCaches...)
+}
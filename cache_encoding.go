@@ -0,0 +1,137 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// CacheItemVersion is the version of the CacheItemState encoding written by MarshalCacheItem.
+// UnmarshalCacheItem returns an error for items encoded with a later version.
+const CacheItemVersion = 1
+
+// MarshalCacheItem encodes the item as a CacheItemState, such that Store and Loader implementations
+// persist rate limits in the same format as gubernator. Returns an error if the item value is not a
+// TokenBucketItem or LeakyBucketItem.
+func MarshalCacheItem(item *CacheItem) ([]byte, error) {
+	return appendCacheItem(nil, item)
+}
+
+// appendCacheItem appends the encoding of the item to `buf`
+func appendCacheItem(buf []byte, item *CacheItem) ([]byte, error) {
+	state, err := newCacheItemState(item)
+	if err != nil {
+		return nil, err
+	}
+	return proto.MarshalOptions{}.MarshalAppend(buf, state)
+}
+
+// newCacheItemState returns the CacheItemState of the item
+func newCacheItemState(item *CacheItem) (*CacheItemState, error) {
+	state := &CacheItemState{
+		Version:   CacheItemVersion,
+		Key:       item.Key,
+		Name:      item.Name,
+		Algorithm: item.Algorithm,
+		ExpireAt:  item.ExpireAt,
+		InvalidAt: item.InvalidAt,
+	}
+	switch v := item.Value.(type) {
+	case *TokenBucketItem:
+		state.Bucket = &CacheItemState_TokenBucket{TokenBucket: &TokenBucketState{
+			Status:    v.Status,
+			Limit:     v.Limit,
+			Duration:  v.Duration,
+			Remaining: v.Remaining,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+			GraceUsed: v.GraceUsed,
+		}}
+	case *LeakyBucketItem:
+		state.Bucket = &CacheItemState_LeakyBucket{LeakyBucket: &LeakyBucketState{
+			Limit:     v.Limit,
+			Duration:  v.Duration,
+			Remaining: v.Remaining,
+			UpdatedAt: v.UpdatedAt,
+			Burst:     v.Burst,
+		}}
+	default:
+		return nil, fmt.Errorf("unsupported cache item value '%T'", item.Value)
+	}
+	return state, nil
+}
+
+// UnmarshalCacheItem decodes an item encoded by MarshalCacheItem. Fields added by later versions
+// of gubernator are ignored. Items encoded by versions of gubernator prior to CacheItemState
+// are also decoded, such that rate limits persisted before an upgrade are not lost.
+func UnmarshalCacheItem(b []byte) (*CacheItem, error) {
+	// Legacy items begin with the type of bucket, which is never a valid protobuf tag
+	if len(b) > 0 && (b[0] == snapshotTokenBucket || b[0] == snapshotLeakyBucket) {
+		if item := decodeLegacyItem(b); item != nil {
+			return item, nil
+		}
+		return nil, errors.New("invalid legacy cache item")
+	}
+
+	var state CacheItemState
+	if err := proto.Unmarshal(b, &state); err != nil {
+		return nil, errors.Wrap(err, "while decoding cache item")
+	}
+	return cacheItemFromState(&state)
+}
+
+// cacheItemFromState returns the item of a CacheItemState
+func cacheItemFromState(state *CacheItemState) (*CacheItem, error) {
+	if state.Version > CacheItemVersion {
+		return nil, fmt.Errorf("cache item version '%d' is newer than supported version '%d'",
+			state.Version, CacheItemVersion)
+	}
+
+	item := &CacheItem{
+		Key:       state.Key,
+		Name:      state.Name,
+		Algorithm: state.Algorithm,
+		ExpireAt:  state.ExpireAt,
+		InvalidAt: state.InvalidAt,
+	}
+	switch v := state.Bucket.(type) {
+	case *CacheItemState_TokenBucket:
+		item.Value = &TokenBucketItem{
+			Status:    v.TokenBucket.Status,
+			Limit:     v.TokenBucket.Limit,
+			Duration:  v.TokenBucket.Duration,
+			Remaining: v.TokenBucket.Remaining,
+			CreatedAt: v.TokenBucket.CreatedAt,
+			UpdatedAt: v.TokenBucket.UpdatedAt,
+			GraceUsed: v.TokenBucket.GraceUsed,
+		}
+	case *CacheItemState_LeakyBucket:
+		item.Value = &LeakyBucketItem{
+			Limit:     v.LeakyBucket.Limit,
+			Duration:  v.LeakyBucket.Duration,
+			Remaining: v.LeakyBucket.Remaining,
+			UpdatedAt: v.LeakyBucket.UpdatedAt,
+			Burst:     v.LeakyBucket.Burst,
+		}
+	default:
+		return nil, errors.New("cache item has no bucket")
+	}
+	return item, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseTenantPercent parses a tenant share in the format used by `GUBER_CACHE_TENANT_PERCENTS`, IE: "acme=50"
+func ParseTenantPercent(s string) (string, int, error) {
+	tenant, v, ok := strings.Cut(s, "=")
+	tenant = strings.TrimSpace(tenant)
+	percent, err := strconv.Atoi(strings.TrimSpace(v))
+	if !ok || tenant == "" || err != nil {
+		return "", 0, errors.Errorf("'%s' is invalid; expected '<tenant>=<percent>'", s)
+	}
+	return tenant, percent, nil
+}
+
+// setTenantQuota partitions the cache of a worker by `Config.CacheTenantSeparator`, the share of
+// each tenant is a percentage of `cacheSize`, the number of items the cache of the worker holds.
+func setTenantQuota(conf *Config, cache TenantCache, cacheSize int) {
+	sep := conf.CacheTenantSeparator
+	cache.SetTenantQuota(
+		func(item *CacheItem) string {
+			tenant, _, _ := strings.Cut(item.Name, sep)
+			return tenant
+		},
+		func(tenant string) int {
+			percent, ok := conf.CacheTenantPercents[tenant]
+			if !ok {
+				percent = conf.CacheTenantPercent
+			}
+			if n := cacheSize * percent / 100; n > 1 {
+				return n
+			}
+			return 1
+		},
+	)
+}
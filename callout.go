@@ -0,0 +1,149 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mailgun/holster/v4/setter"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// The max size of a decision callout response body
+const maxCalloutResponseSize = 64 << 10
+
+// DecisionCalloutConfig configures an external service which may override the decision of the
+// rate limit algorithm, IE: to apply time of day or geo rules for a tenant. The service runs out of
+// process, as such it cannot affect the stability of gubernator, and each call is bounded by `Timeout`.
+//
+// The service receives a POST with the JSON body
+//
+//	{"request": <RateLimitReq>, "response": <RateLimitResp>}
+//
+// and responds with `200 OK` and the JSON body
+//
+//	{"status": "OVER_LIMIT", "metadata": {"reason": "outside business hours"}}
+//
+// Where `status` is optional and replaces the status decided by the algorithm, and `metadata` is
+// optional and added to the metadata of the response. If the call fails or times out the decision
+// of the algorithm is returned unchanged.
+type DecisionCalloutConfig struct {
+	// (Required) The URL the decisions are POSTed to
+	URL string
+
+	// (Optional) The max time to wait for the service to respond. Defaults to 50ms
+	Timeout time.Duration
+
+	// (Optional) Only call the service for these rate limit names. Defaults to every name
+	Names []string
+
+	// (Optional) The HTTP client used to call the service
+	Client *http.Client
+}
+
+type calloutResponse struct {
+	Status   string            `json:"status"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// NewDecisionCallout returns a BehaviorMiddleware which calls the service described by `conf`
+// after the rate limit algorithm, see DecisionCalloutConfig.
+func NewDecisionCallout(conf DecisionCalloutConfig) BehaviorMiddleware {
+	setter.SetDefault(&conf.Timeout, 50*time.Millisecond)
+	setter.SetDefault(&conf.Client, &http.Client{})
+	names := make(map[string]struct{}, len(conf.Names))
+	for _, name := range conf.Names {
+		names[name] = struct{}{}
+	}
+
+	return func(next RateLimitHandler) RateLimitHandler {
+		return func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+			rl, err := next(ctx, c)
+			if err != nil || rl.Error != "" {
+				return rl, err
+			}
+			if _, ok := names[c.Req.Name]; len(names) != 0 && !ok {
+				return rl, nil
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, conf.Timeout)
+			defer cancel()
+			resp, err := callout(ctx, conf, c.Req, rl)
+			if err != nil {
+				metricCalloutCounter.WithLabelValues("error").Inc()
+				return rl, nil
+			}
+
+			result := "unchanged"
+			if resp.Status != "" && resp.Status != rl.Status.String() {
+				rl.Status = Status(Status_value[resp.Status])
+				result = "overridden"
+			}
+			if len(resp.Metadata) != 0 {
+				if rl.Metadata == nil {
+					rl.Metadata = make(map[string]string, len(resp.Metadata))
+				}
+				for k, v := range resp.Metadata {
+					rl.Metadata[k] = v
+				}
+			}
+			metricCalloutCounter.WithLabelValues(result).Inc()
+			return rl, nil
+		}
+	}
+}
+
+func callout(ctx context.Context, conf DecisionCalloutConfig, req *RateLimitReq, rl *RateLimitResp) (*calloutResponse, error) {
+	r, err := protojson.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	s, err := protojson.Marshal(rl)
+	if err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf(`{"request":%s,"response":%s}`, r, s)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, conf.URL, bytes.NewReader([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpResp, err := conf.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("decision callout '%s' returned '%s'", conf.URL, httpResp.Status)
+	}
+
+	var resp calloutResponse
+	if err := json.NewDecoder(io.LimitReader(httpResp.Body, maxCalloutResponseSize)).Decode(&resp); err != nil {
+		return nil, err
+	}
+	if _, ok := Status_value[resp.Status]; resp.Status != "" && !ok {
+		return nil, fmt.Errorf("decision callout returned invalid status '%s'", resp.Status)
+	}
+	return &resp, nil
+}
@@ -18,72 +18,29 @@ package gubernator
 
 import (
 	crand "crypto/rand"
-	"crypto/tls"
 	"math/rand"
-	"time"
 
-	"github.com/mailgun/holster/v4/clock"
-	"github.com/pkg/errors"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/gubernator-io/gubernator/v2/client"
 )
 
+// The client is implemented by the client package, which clients may import without the dependencies
+// of the server. The aliases below keep it available from this package for compatibility.
 const (
-	Millisecond = 1
-	Second      = 1000 * Millisecond
-	Minute      = 60 * Second
-)
-
-func (m *RateLimitReq) HashKey() string {
-	return m.Name + "_" + m.UniqueKey
-}
-
-// DialV1Server is a convenience function for dialing gubernator instances
-func DialV1Server(server string, tls *tls.Config) (V1Client, error) {
-	if len(server) == 0 {
-		return nil, errors.New("server is empty; must provide a server")
-	}
-
-	// Setup OpenTelemetry interceptor to propagate spans.
-	opts := []grpc.DialOption{
-		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-	}
-	if tls != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tls)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	}
-
-	conn, err := grpc.Dial(server, opts...)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to dial server %s", server)
-	}
-
-	return NewV1Client(conn), nil
-}
-
-// ToTimeStamp is a convenience function to convert a time.Duration
-// to a unix millisecond timestamp. Useful when working with gubernator
-// request and response duration and reset_time fields.
-func ToTimeStamp(duration time.Duration) int64 {
-	return int64(duration / time.Millisecond)
-}
+	Millisecond = client.Millisecond
+	Second      = client.Second
+	Minute      = client.Minute
 
-// FromTimeStamp is a convenience function to convert a unix millisecond
-// timestamp to a time.Duration. Useful when working with gubernator
-// request and response duration and reset_time fields.
-func FromTimeStamp(ts int64) time.Duration {
-	return clock.Now().Sub(FromUnixMilliseconds(ts))
-}
+	maxBatchSize = client.MaxBatchSize
+)
 
-// FromUnixMilliseconds is a convenience function to convert a unix
-// millisecond timestamp to a time.Time. Useful when working with gubernator
-// request and response duration and reset_time fields.
-func FromUnixMilliseconds(ts int64) time.Time {
-	return clock.Unix(0, ts*int64(clock.Millisecond))
-}
+var (
+	DialV1Server          = client.DialV1Server
+	BatchSplitInterceptor = client.BatchSplitInterceptor
+	Throttle              = client.Throttle
+	ToTimeStamp           = client.ToTimeStamp
+	FromTimeStamp         = client.FromTimeStamp
+	FromUnixMilliseconds  = client.FromUnixMilliseconds
+)
 
 // RandomPeer returns a random peer from the list of peers provided
 func RandomPeer(peers []PeerInfo) PeerInfo {
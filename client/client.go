@@ -0,0 +1,201 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client dials Gubernator and checks rate limits. It only depends on GRPC, OpenTelemetry and
+// the proto package, such that client binaries do not import the dependencies of the server.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	pb "github.com/gubernator-io/gubernator/v2/proto"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	Millisecond = 1
+	Second      = 1000 * Millisecond
+	Minute      = 60 * Second
+
+	// MaxBatchSize is the max number of rate limits a server accepts in a single request
+	MaxBatchSize = 1000
+)
+
+// minThrottleWait is the least Throttle() waits before asking again, such that a
+// reset_time in the past does not cause a busy loop
+const minThrottleWait = 10 * time.Millisecond
+
+// DialV1Server is a convenience function for dialing gubernator instances. Additional `opts`
+// are appended to the default dial options, IE: to enable compression
+//
+//	client.DialV1Server(addr, nil, grpc.WithDefaultCallOptions(grpc.UseCompressor(client.CompressionSnappy)))
+//
+// GetRateLimits requests with more rate limits than the server accepts in a single request are
+// split into several RPCs, one at a time, see BatchSplitInterceptor().
+func DialV1Server(server string, tls *tls.Config, opts ...grpc.DialOption) (pb.V1Client, error) {
+	if len(server) == 0 {
+		return nil, errors.New("server is empty; must provide a server")
+	}
+
+	// Setup OpenTelemetry interceptor to propagate spans.
+	dialOpts := []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if tls != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tls)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	// Installed after `opts`, such that a BatchSplitInterceptor in `opts` splits the batch first
+	opts = append(opts, grpc.WithChainUnaryInterceptor(BatchSplitInterceptor(MaxBatchSize, 1)))
+	conn, err := grpc.Dial(server, append(dialOpts, opts...)...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial server %s", server)
+	}
+
+	return pb.NewV1Client(conn), nil
+}
+
+// BatchSplitInterceptor returns a client interceptor which splits GetRateLimits requests of more
+// than `size` rate limits into RPCs of at most `size` rate limits, with at most `parallel` RPCs in
+// flight, and merges the responses in the order of the requests. Use it to split large batches in
+// parallel, IE:
+//
+//	client.DialV1Server(addr, nil, grpc.WithChainUnaryInterceptor(client.BatchSplitInterceptor(1000, 4)))
+//
+// The first RPC which fails cancels the others and its error is returned. `fail_fast` only aborts
+// the rate limits in the same RPC, and rate limits with the same key in different RPCs sent in
+// parallel may be applied in any order.
+func BatchSplitInterceptor(size, parallel int) grpc.UnaryClientInterceptor {
+	if parallel < 1 {
+		parallel = 1
+	}
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		r, ok := req.(*pb.GetRateLimitsReq)
+		resp, isResp := reply.(*pb.GetRateLimitsResp)
+		if !ok || !isResp || method != pb.V1_GetRateLimits_FullMethodName || size < 1 || len(r.Requests) <= size {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		responses := make([][]*pb.RateLimitResp, (len(r.Requests)+size-1)/size)
+		g, ctx := errgroup.WithContext(ctx)
+		g.SetLimit(parallel)
+		for i := range responses {
+			i := i
+			end := (i + 1) * size
+			if end > len(r.Requests) {
+				end = len(r.Requests)
+			}
+			batch := &pb.GetRateLimitsReq{
+				Requests:        r.Requests[i*size : end],
+				MinimalResponse: r.MinimalResponse,
+				FailFast:        r.FailFast,
+				Defaults:        r.Defaults,
+			}
+			g.Go(func() error {
+				var out pb.GetRateLimitsResp
+				if err := invoker(ctx, method, batch, &out, cc, opts...); err != nil {
+					return err
+				}
+				if len(out.Responses) != len(batch.Requests) {
+					return errors.Errorf("expected '%d' responses; got '%d'", len(batch.Requests), len(out.Responses))
+				}
+				responses[i] = out.Responses
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+
+		resp.Responses = make([]*pb.RateLimitResp, 0, len(r.Requests))
+		for _, batch := range responses {
+			resp.Responses = append(resp.Responses, batch...)
+		}
+		resp.Summary = pb.NewBatchSummary(r, resp.Responses)
+		return nil
+	}
+}
+
+// Throttle calls `fn` once the rate limit allows the hits of `req`. Each time the rate limit is
+// over the limit, Throttle sleeps until the `reset_time` of the response and asks again, until the
+// hits are applied or `ctx` is done, such that application code waits for its turn in one line.
+//
+//	err := client.Throttle(ctx, c, &proto.RateLimitReq{
+//		Name: "send_email", UniqueKey: "account:1234", Hits: 1, Limit: 10, Duration: client.Minute,
+//	}, func(ctx context.Context) error { return sendEmail(ctx) })
+//
+// An error in the response, IE: an invalid request, is returned without calling `fn`.
+func Throttle(ctx context.Context, client pb.V1Client, req *pb.RateLimitReq, fn func(context.Context) error) error {
+	for {
+		resp, err := client.GetRateLimits(ctx, &pb.GetRateLimitsReq{Requests: []*pb.RateLimitReq{req}})
+		if err != nil {
+			return errors.Wrap(err, "during GetRateLimits")
+		}
+		if len(resp.Responses) != 1 {
+			return errors.Errorf("expected 1 response; got '%d'", len(resp.Responses))
+		}
+		rl := resp.Responses[0]
+		if rl.Error != "" {
+			return errors.New(rl.Error)
+		}
+		if rl.Status == pb.Status_UNDER_LIMIT {
+			return fn(ctx)
+		}
+
+		wait := time.Until(FromUnixMilliseconds(rl.ResetTime))
+		if wait < minThrottleWait {
+			wait = minThrottleWait
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrap(ctx.Err(), "while waiting for rate limit")
+		}
+	}
+}
+
+// ToTimeStamp is a convenience function to convert a time.Duration
+// to a unix millisecond timestamp. Useful when working with gubernator
+// request and response duration and reset_time fields.
+func ToTimeStamp(duration time.Duration) int64 {
+	return int64(duration / time.Millisecond)
+}
+
+// FromTimeStamp is a convenience function to convert a unix millisecond
+// timestamp to a time.Duration. Useful when working with gubernator
+// request and response duration and reset_time fields.
+func FromTimeStamp(ts int64) time.Duration {
+	return time.Since(FromUnixMilliseconds(ts))
+}
+
+// FromUnixMilliseconds is a convenience function to convert a unix
+// millisecond timestamp to a time.Time. Useful when working with gubernator
+// request and response duration and reset_time fields.
+func FromUnixMilliseconds(ts int64) time.Time {
+	return time.UnixMilli(ts)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// The names of the GRPC compressors this package registers. A GRPC server will respond
+// using the same compressor a client used to make the request, as such clients may pass
+// `grpc.WithDefaultCallOptions(grpc.UseCompressor(client.CompressionGzip))` to
+// `DialV1Server()` to compress both requests and responses.
+const (
+	CompressionGzip   = gzip.Name
+	CompressionSnappy = "snappy"
+)
+
+func init() {
+	c := &snappyCompressor{}
+	c.writers.New = func() any {
+		return &snappyWriter{Writer: snappy.NewBufferedWriter(nil), pool: &c.writers}
+	}
+	encoding.RegisterCompressor(c)
+}
+
+type snappyCompressor struct {
+	writers sync.Pool
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	sw := c.writers.Get().(*snappyWriter)
+	sw.Reset(w)
+	return sw, nil
+}
+
+func (w *snappyWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Writer.Close()
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (c *snappyCompressor) Name() string {
+	return CompressionSnappy
+}
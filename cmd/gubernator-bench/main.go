@@ -0,0 +1,335 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gubernator-bench drives a configurable mix of rate limit requests against a cluster and
+// reports the throughput and latency percentiles, IE:
+//
+//	gubernator-bench -e localhost:1051,localhost:1052 -duration 30s -concurrency 50 \
+//	    -keys 100000 -distribution zipf -batch 10 -algorithms token_bucket,leaky_bucket
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var log *logrus.Logger
+
+type benchConfig struct {
+	endpoints    []string
+	configFile   string
+	duration     time.Duration
+	concurrency  int
+	reqRate      float64
+	timeout      time.Duration
+	keys         int
+	batch        int
+	distribution string
+	zipfS        float64
+	minHits      int64
+	maxHits      int64
+	limit        int64
+	limitPeriod  time.Duration
+	algorithms   []guber.Algorithm
+	behavior     guber.Behavior
+}
+
+// result is the outcome of the requests sent by a single worker
+type result struct {
+	latencies []time.Duration
+	requests  int64
+	checks    int64
+	errors    int64
+	overLimit int64
+}
+
+func main() {
+	log = logrus.StandardLogger()
+	conf, err := parseFlags()
+	checkErr(err)
+
+	clients, err := dial(conf)
+	checkErr(err)
+
+	var limiter *rate.Limiter
+	if conf.reqRate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(conf.reqRate), 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.duration)
+	defer cancel()
+
+	log.Infof("Sending requests to %s for %s...", strings.Join(conf.endpoints, ", "), conf.duration)
+	var sent atomic.Int64
+	results := make([]*result, conf.concurrency)
+	var wg sync.WaitGroup
+	start := clock.Now()
+	for i := 0; i < conf.concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runWorker(ctx, conf, clients[i%len(clients)], limiter, &sent, rand.New(rand.NewSource(time.Now().UnixNano()+int64(i))))
+		}(i)
+	}
+	wg.Wait()
+
+	report(conf, results, clock.Since(start))
+}
+
+func parseFlags() (*benchConfig, error) {
+	var conf benchConfig
+	var endpoints, algorithms, behavior string
+	flag.StringVar(&endpoints, "e", "", "Comma separated list of Gubernator GRPC endpoint addresses, requests are spread across them")
+	flag.StringVar(&conf.configFile, "config", "", "Environment config file, used for the TLS config and GUBER_GRPC_ADDRESS")
+	flag.DurationVar(&conf.duration, "duration", 30*time.Second, "How long to send requests")
+	flag.IntVar(&conf.concurrency, "concurrency", 10, "Number of concurrent requests")
+	flag.Float64Var(&conf.reqRate, "rate", 0, "Max requests per second overall, 0 = no limit")
+	flag.DurationVar(&conf.timeout, "timeout", time.Second, "Request timeout")
+	flag.IntVar(&conf.keys, "keys", 10_000, "Number of unique keys (key cardinality)")
+	flag.IntVar(&conf.batch, "batch", 1, "Number of rate limits in each GetRateLimits request")
+	flag.StringVar(&conf.distribution, "distribution", "uniform", "How keys are chosen; 'uniform' or 'zipf' where a few keys receive most hits")
+	flag.Float64Var(&conf.zipfS, "zipf-s", 1.1, "The skew of the zipf distribution, must be greater than 1")
+	flag.Int64Var(&conf.minHits, "min-hits", 1, "Min hits of each rate limit")
+	flag.Int64Var(&conf.maxHits, "max-hits", 1, "Max hits of each rate limit, hits are uniformly distributed between min and max")
+	flag.Int64Var(&conf.limit, "limit", 1000, "Limit of each rate limit")
+	flag.DurationVar(&conf.limitPeriod, "limit-duration", time.Minute, "Duration of each rate limit")
+	flag.StringVar(&algorithms, "algorithms", "token_bucket", "Comma separated list of algorithms; 'token_bucket' and 'leaky_bucket' chosen per key")
+	flag.StringVar(&behavior, "behavior", "batching", "Behavior of each rate limit; 'batching', 'no_batching' or 'global'")
+	flag.Parse()
+
+	conf.endpoints = splitList(endpoints)
+	if len(conf.endpoints) == 0 {
+		if a := os.Getenv("GUBER_GRPC_ADDRESS"); a != "" {
+			conf.endpoints = []string{a}
+		}
+	}
+	if len(conf.endpoints) == 0 && conf.configFile == "" {
+		return nil, errors.New("please provide a GRPC endpoint via -e or from a config " +
+			"file via -config or set the env GUBER_GRPC_ADDRESS")
+	}
+	if conf.concurrency <= 0 || conf.keys <= 0 || conf.batch <= 0 || conf.duration <= 0 {
+		return nil, errors.New("-concurrency, -keys, -batch and -duration must be greater than 0")
+	}
+	if conf.minHits < 0 || conf.maxHits < conf.minHits {
+		return nil, errors.New("-max-hits cannot be less than -min-hits, which cannot be negative")
+	}
+	if conf.batch > 1000 {
+		return nil, errors.New("-batch cannot exceed 1000")
+	}
+	switch conf.distribution {
+	case "uniform":
+	case "zipf":
+		if conf.zipfS <= 1 {
+			return nil, errors.New("-zipf-s must be greater than 1")
+		}
+	default:
+		return nil, fmt.Errorf("-distribution '%s' is invalid; choices are ['uniform', 'zipf']", conf.distribution)
+	}
+
+	for _, a := range splitList(algorithms) {
+		v, ok := guber.Algorithm_value[strings.ToUpper(a)]
+		if !ok {
+			return nil, fmt.Errorf("-algorithms '%s' is invalid; choices are ['token_bucket', 'leaky_bucket']", a)
+		}
+		conf.algorithms = append(conf.algorithms, guber.Algorithm(v))
+	}
+	if len(conf.algorithms) == 0 {
+		return nil, errors.New("-algorithms cannot be empty")
+	}
+
+	switch behavior {
+	case "batching":
+		conf.behavior = guber.Behavior_BATCHING
+	case "no_batching":
+		conf.behavior = guber.Behavior_NO_BATCHING
+	case "global":
+		conf.behavior = guber.Behavior_GLOBAL
+	default:
+		return nil, fmt.Errorf("-behavior '%s' is invalid; choices are ['batching', 'no_batching', 'global']", behavior)
+	}
+	return &conf, nil
+}
+
+// dial connects to each endpoint, using the TLS config and address from -config if provided
+func dial(conf *benchConfig) ([]guber.V1Client, error) {
+	daemonConf := &guber.DaemonConfig{}
+	if conf.configFile != "" {
+		f, err := os.Open(conf.configFile)
+		if err != nil {
+			return nil, fmt.Errorf("while opening config file: %s", err)
+		}
+		defer f.Close()
+		c, err := guber.SetupDaemonConfig(log, f)
+		if err != nil {
+			return nil, err
+		}
+		if err := guber.SetupTLS(c.TLS); err != nil {
+			return nil, err
+		}
+		daemonConf = &c
+		if len(conf.endpoints) == 0 {
+			conf.endpoints = []string{c.GRPCListenAddress}
+		}
+	}
+
+	var clients []guber.V1Client
+	for _, e := range conf.endpoints {
+		c, err := guber.DialV1Server(e, daemonConf.ClientTLS())
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+// runWorker sends requests one at a time until `ctx` is done
+func runWorker(ctx context.Context, conf *benchConfig, client guber.V1Client, limiter *rate.Limiter,
+	sent *atomic.Int64, rnd *rand.Rand) *result {
+	var r result
+	nextKey := func() int { return rnd.Intn(conf.keys) }
+	if conf.distribution == "zipf" {
+		z := rand.NewZipf(rnd, conf.zipfS, 1, uint64(conf.keys-1))
+		nextKey = func() int { return int(z.Uint64()) }
+	}
+
+	for ctx.Err() == nil {
+		if limiter != nil && limiter.Wait(ctx) != nil {
+			break
+		}
+
+		req := &guber.GetRateLimitsReq{Requests: make([]*guber.RateLimitReq, conf.batch)}
+		for i := range req.Requests {
+			key := nextKey()
+			req.Requests[i] = &guber.RateLimitReq{
+				Name:      "gubernator-bench",
+				UniqueKey: fmt.Sprintf("key-%d", key),
+				Hits:      conf.minHits + rnd.Int63n(conf.maxHits-conf.minHits+1),
+				Limit:     conf.limit,
+				Duration:  conf.limitPeriod.Milliseconds(),
+				Algorithm: conf.algorithms[key%len(conf.algorithms)],
+				Behavior:  conf.behavior,
+			}
+		}
+
+		reqCtx, cancel := context.WithTimeout(context.Background(), conf.timeout)
+		start := clock.Now()
+		resp, err := client.GetRateLimits(reqCtx, req)
+		latency := clock.Since(start)
+		cancel()
+
+		r.requests++
+		r.checks += int64(conf.batch)
+		r.latencies = append(r.latencies, latency)
+		if n := sent.Add(1); n%10_000 == 0 {
+			log.Infof("Sent %d requests", n)
+		}
+		if err != nil {
+			r.errors += int64(conf.batch)
+			continue
+		}
+		for _, rl := range resp.Responses {
+			switch {
+			case rl.Error != "":
+				r.errors++
+			case rl.Status == guber.Status_OVER_LIMIT:
+				r.overLimit++
+			}
+		}
+	}
+	return &r
+}
+
+func report(conf *benchConfig, results []*result, elapsed time.Duration) {
+	var total result
+	for _, r := range results {
+		total.requests += r.requests
+		total.checks += r.checks
+		total.errors += r.errors
+		total.overLimit += r.overLimit
+		total.latencies = append(total.latencies, r.latencies...)
+	}
+	sort.Slice(total.latencies, func(i, j int) bool { return total.latencies[i] < total.latencies[j] })
+
+	fmt.Printf("Endpoints:    %s\n", strings.Join(conf.endpoints, ", "))
+	fmt.Printf("Duration:     %s\n", elapsed.Round(time.Millisecond))
+	fmt.Printf("Concurrency:  %d\n", conf.concurrency)
+	fmt.Printf("Keys:         %d (%s)\n", conf.keys, conf.distribution)
+	fmt.Printf("Batch size:   %d\n", conf.batch)
+	fmt.Println()
+	fmt.Printf("Requests:     %d (%.1f/s)\n", total.requests, float64(total.requests)/elapsed.Seconds())
+	fmt.Printf("Checks:       %d (%.1f/s)\n", total.checks, float64(total.checks)/elapsed.Seconds())
+	fmt.Printf("Over limit:   %d (%.2f%%)\n", total.overLimit, percent(total.overLimit, total.checks))
+	fmt.Printf("Errors:       %d (%.2f%%)\n", total.errors, percent(total.errors, total.checks))
+	fmt.Println()
+	fmt.Println("Request latency:")
+	for _, p := range []float64{50, 90, 99, 99.9} {
+		fmt.Printf("  p%-6v      %s\n", p, percentile(total.latencies, p))
+	}
+	if len(total.latencies) != 0 {
+		fmt.Printf("  max          %s\n", total.latencies[len(total.latencies)-1])
+	}
+}
+
+// percentile returns the p-th percentile of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func percent(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func checkErr(err error) {
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+}
@@ -37,6 +37,7 @@ import (
 
 var log = logrus.WithField("category", "gubernator")
 var Version = "dev-build"
+var Commit = ""
 var tracerCloser io.Closer
 
 func main() {
@@ -49,12 +50,28 @@ func main() {
 
 func Main(ctx context.Context) error {
 	var configFile string
+	var soak bool
+	var soakConf gubernator.SoakConfig
+
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		return Simulate(os.Args[2:], os.Stdout)
+	}
 
 	logrus.Infof("Gubernator %s (%s/%s)", Version, runtime.GOARCH, runtime.GOOS)
+	gubernator.Version = Version
+	if Commit != "" {
+		gubernator.Commit = Commit
+	}
 	flags := flag.NewFlagSet("gubernator", flag.ContinueOnError)
 	flags.SetOutput(io.Discard)
 	flags.StringVar(&configFile, "config", "", "environment config file")
 	flags.BoolVar(&gubernator.DebugEnabled, "debug", false, "enable debug")
+	// Hidden, hits rate limits through this instance and logs the responses which break an invariant
+	flags.BoolVar(&soak, "soak", false, "continuously verify the rate limits of the cluster")
+	flags.IntVar(&soakConf.Rate, "soak-rate", 0, "the requests per second of the soak")
+	flags.IntVar(&soakConf.Keys, "soak-keys", 0, "the number of rate limits hit by the soak")
+	flags.Int64Var(&soakConf.Limit, "soak-limit", 0, "the limit of the rate limits hit by the soak")
+	flags.DurationVar(&soakConf.Duration, "soak-duration", 0, "the duration of the rate limits hit by the soak")
 	if err := flags.Parse(os.Args[1:]); err != nil {
 		if !strings.Contains(err.Error(), "flag provided but not defined") {
 			return fmt.Errorf("while parsing flags: %w", err)
@@ -111,15 +128,30 @@ func Main(ctx context.Context) error {
 		return fmt.Errorf("while spawning daemon: %w", err)
 	}
 
+	stopSoak := func() {}
+	if soak {
+		if stopSoak, err = runSoak(ctx, daemon, soakConf); err != nil {
+			daemon.Close()
+			return fmt.Errorf("while starting soak: %w", err)
+		}
+	}
+
 	// Wait here for signals to clean up our mess
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	select {
 	case <-c:
 		log.Info("caught signal; shutting down")
+		stopSoak()
 		daemon.Close()
 		_ = tracing.CloseTracing(context.Background())
 		return nil
+	case err := <-daemon.Err():
+		log.WithError(err).Error("daemon failed; shutting down")
+		stopSoak()
+		daemon.Close()
+		_ = tracing.CloseTracing(context.Background())
+		return err
 	case <-ctx.Done():
 		return ctx.Err()
 	}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gubernator-io/gubernator/v2"
+)
+
+const simulateUsage = `Usage: gubernator simulate -trace <file> -limit <limits> -duration <duration> [options]
+
+Replays a trace of historical requests against each of the limits offline and
+reports how many requests would have been denied. Each line of the trace is
+'<time>,<key>[,<hits>]' where the time is RFC 3339 or epoch milliseconds.
+
+Options:
+`
+
+// Simulate implements the `gubernator simulate` verb, see gubernator.Simulate()
+func Simulate(args []string, out io.Writer) error {
+	var traceFile, algorithm, limits string
+	var duration time.Duration
+	var burst int64
+	var top int
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		fmt.Fprint(out, simulateUsage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&traceFile, "trace", "", "the trace of requests to replay, '-' reads from stdin")
+	flags.StringVar(&limits, "limit", "", "comma separated limits to compare, IE: '10,50,100'")
+	flags.DurationVar(&duration, "duration", 0, "the duration of the rate limit, IE: '1m'")
+	flags.StringVar(&algorithm, "algorithm", "token_bucket", "'token_bucket' or 'leaky_bucket'")
+	flags.Int64Var(&burst, "burst", 0, "the burst of a leaky bucket, defaults to the limit")
+	flags.IntVar(&top, "top", 0, "report the keys with the most denied requests for each limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if traceFile == "" || limits == "" || duration <= 0 {
+		flags.Usage()
+		return errors.New("-trace, -limit and -duration are required")
+	}
+
+	alg, ok := gubernator.Algorithm_value[strings.ToUpper(algorithm)]
+	if !ok {
+		return fmt.Errorf("-algorithm '%s' is invalid; expected 'token_bucket' or 'leaky_bucket'", algorithm)
+	}
+
+	reader := os.Stdin
+	if traceFile != "-" {
+		f, err := os.Open(traceFile)
+		if err != nil {
+			return fmt.Errorf("while opening trace: %w", err)
+		}
+		defer f.Close()
+		reader = f
+	}
+	trace, err := gubernator.ReadSimulationTrace(reader)
+	if err != nil {
+		return fmt.Errorf("while reading trace: %w", err)
+	}
+
+	results := make(map[int64]*gubernator.SimulationResult)
+	var order []int64
+	for _, s := range strings.Split(limits, ",") {
+		limit, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return fmt.Errorf("-limit '%s' is not an integer", s)
+		}
+		result, err := gubernator.Simulate(gubernator.SimulationConfig{
+			RateLimit: &gubernator.RateLimitReq{
+				Name:      "simulation",
+				Algorithm: gubernator.Algorithm(alg),
+				Limit:     limit,
+				Burst:     burst,
+				Duration:  duration.Milliseconds(),
+			},
+			TopKeys: top,
+		}, trace)
+		if err != nil {
+			return fmt.Errorf("while simulating limit '%d': %w", limit, err)
+		}
+		results[limit] = result
+		order = append(order, limit)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LIMIT\tREQUESTS\tDENIED\tDENIED %\tKEYS\tDENIED KEYS")
+	for _, limit := range order {
+		r := results[limit]
+		fmt.Fprintf(w, "%d\t%d\t%d\t%.2f\t%d\t%d\n", limit, r.Requests, r.Denied,
+			percent(r.Denied, r.Requests), r.Keys, r.DeniedKeys)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if top <= 0 {
+		return nil
+	}
+	for _, limit := range order {
+		if len(results[limit].TopKeys) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nMost denied keys with limit %d:\n", limit)
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tREQUESTS\tDENIED")
+		for _, k := range results[limit].TopKeys {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", k.Key, k.Requests, k.Denied)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func percent(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gubernator-io/gubernator/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// runSoak hits rate limits through the daemon with a gubernator.Soak until the returned func is
+// called, logging each violation of an invariant and the totals every minute. Every instance of the
+// cluster may run a soak, as each hits the rate limits keyed by its own instance id.
+func runSoak(ctx context.Context, daemon *gubernator.Daemon, conf gubernator.SoakConfig) (func(), error) {
+	// Hits the first GRPC listener, as the requests of clients would
+	daemonConf := daemon.Config()
+	client, err := gubernator.DialV1Server(daemon.GRPCListeners[0].Addr().String(), daemonConf.ClientTLS())
+	if err != nil {
+		return nil, err
+	}
+	conf.KeyPrefix = daemon.InstanceID + ":"
+	conf.OnViolation = func(v gubernator.SoakViolation) {
+		log.WithFields(logrus.Fields{
+			"key":        v.Key,
+			"reset_time": v.ResetTime,
+		}).Errorf("soak violation: %s", v.Reason)
+	}
+	conf.OnReport = func(r gubernator.SoakReport) {
+		log.WithFields(logrus.Fields{
+			"requests":    r.Requests,
+			"under_limit": r.UnderLimit,
+			"over_limit":  r.OverLimit,
+			"errors":      r.Errors,
+			"violations":  r.Violations,
+		}).Info("soak report")
+	}
+
+	soak, err := gubernator.NewSoak(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conf.OnReport(soak.Run(ctx, client))
+	}()
+	log.WithField("key_prefix", conf.KeyPrefix).Info("soak started")
+	return func() {
+		cancel()
+		wg.Wait()
+	}, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// coalesceKey identifies the forwarded requests which are identical but for their hits, see
+// BehaviorConfig.CoalesceRequests
+type coalesceKey struct {
+	name      string
+	uniqueKey string
+	algorithm Algorithm
+	behavior  Behavior
+	limit     int64
+	duration  int64
+	burst     int64
+	// The sorted metadata and request values of the request
+	metadata string
+}
+
+// newCoalesceKey returns the key of the request `r`, and false if the request must not be coalesced
+func newCoalesceKey(ctx context.Context, r *RateLimitReq) (coalesceKey, bool) {
+	// A retry of an idempotent request must return the response of the original request alone
+	if r.IdempotencyKey != "" {
+		return coalesceKey{}, false
+	}
+	key := coalesceKey{
+		name:      r.Name,
+		uniqueKey: r.UniqueKey,
+		algorithm: r.Algorithm,
+		behavior:  r.Behavior,
+		limit:     r.Limit,
+		duration:  r.Duration,
+		burst:     r.Burst,
+	}
+	values := RequestValues(ctx)
+	if len(r.Metadata) == 0 && len(values) == 0 {
+		return key, true
+	}
+	pairs := make([]string, 0, len(r.Metadata)+len(values))
+	for k, v := range r.Metadata {
+		pairs = append(pairs, "m:"+k+"="+v)
+	}
+	for k, v := range values {
+		pairs = append(pairs, "v:"+k+"="+v)
+	}
+	sort.Strings(pairs)
+	key.metadata = strings.Join(pairs, "\x00")
+	return key, true
+}
+
+// coalesce adds the hits of `r` to an identical request waiting in the batch queue, and returns the
+// channel the response of the merged request is sent to. Returns nil if there is no such request,
+// in which case `req` is queued and further identical requests are coalesced into it until the
+// batch is sent. The request of `req` is replaced with a copy, as its hits may change.
+func (c *PeerClient) coalesce(ctx context.Context, req *request) chan *response {
+	key, ok := newCoalesceKey(ctx, req.request)
+	if !ok {
+		return nil
+	}
+
+	c.coalesceMutex.Lock()
+	defer c.coalesceMutex.Unlock()
+	if leader, ok := c.coalescing[key]; ok {
+		leader.request.Hits += req.request.Hits
+		resp := make(chan *response, 1)
+		leader.coalesced = append(leader.coalesced, resp)
+		metricCoalescedRequests.Inc()
+		return resp
+	}
+	req.request = proto.Clone(req.request).(*RateLimitReq)
+	req.coalesceKey = &key
+	c.coalescing[key] = req
+	return nil
+}
+
+// stopCoalescing stops coalescing requests into the queued requests, once they are being sent
+func (c *PeerClient) stopCoalescing(queue []*request) {
+	c.coalesceMutex.Lock()
+	defer c.coalesceMutex.Unlock()
+	for _, r := range queue {
+		if r.coalesceKey != nil && c.coalescing[*r.coalesceKey] == r {
+			delete(c.coalescing, *r.coalesceKey)
+		}
+	}
+}
+
+// respond sends the response to the request and every request coalesced into it. Each coalesced
+// request receives a copy of the response, as callers may modify the response.
+func (r *request) respond(resp *response) {
+	r.resp <- resp
+	for _, ch := range r.coalesced {
+		if resp.rl == nil {
+			ch <- resp
+			continue
+		}
+		ch <- &response{rl: proto.Clone(resp.rl).(*RateLimitResp)}
+	}
+}
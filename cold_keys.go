@@ -0,0 +1,271 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// The rate of keys a key filter reports as seen although they are not in the cache of the owner
+	coldKeyFalsePositiveRate = 0.01
+	// The max number of keys answered locally per interval, such that a flood of unique keys
+	// cannot grow the memory of the instance without bound
+	coldKeyMaxAnswered = 100_000
+)
+
+// coldKeys answers the rate limits owned by other peers which the owner has never seen, IE: keys
+// which are not in its cache, without forwarding them. Each peer pulls a bloom filter of the keys
+// in the cache of every other peer every `Config.ColdKeyFilterInterval`. A rate limit whose key is
+// not in the filter of its owner starts with a full bucket, as such this instance answers the hits
+// as under the limit and sends them to the owner asynchronously, like the hits of a GLOBAL rate
+// limit.
+//
+// The owner does not see the hits answered by other peers until they arrive, as such each peer
+// answers at most its share of the limit, IE: the limit divided by the number of peers, for each
+// key per interval. Once the hits arrive the key is in the next filter of the owner and is
+// forwarded as usual.
+type coldKeys struct {
+	interval time.Duration
+	// The filter of the keys in the cache of this instance, rebuilt at most every half interval
+	ownMutex   sync.Mutex
+	own        *GetPeerKeyFilterResp
+	ownBuiltAt time.Time
+	// The filters of the other peers indexed by their GRPC address
+	mutex    sync.Mutex
+	filters  map[string]*keyFilter
+	answered map[string]int64
+	wg       sync.WaitGroup
+	done     chan struct{}
+}
+
+// keyFilter is a bloom filter of the hash keys in the cache of a peer
+type keyFilter struct {
+	bits      []uint64
+	hashes    int
+	fetchedAt time.Time
+}
+
+func newColdKeys(interval time.Duration) *coldKeys {
+	return &coldKeys{
+		interval: interval,
+		filters:  make(map[string]*keyFilter),
+		answered: make(map[string]int64),
+		done:     make(chan struct{}),
+	}
+}
+
+// stop ends runColdKeyFilters and waits for it to return
+func (c *coldKeys) stop() {
+	close(c.done)
+	c.wg.Wait()
+}
+
+// newKeyFilter returns an empty filter sized for `n` keys
+func newKeyFilter(n int) *keyFilter {
+	if n < 1 {
+		n = 1
+	}
+	bits := math.Ceil(-float64(n) * math.Log(coldKeyFalsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := int(math.Round(bits / float64(n) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+	return &keyFilter{bits: make([]uint64, (int(bits)+63)/64), hashes: hashes}
+}
+
+// each calls `fn` with the position of each bit of `key`, derived from a single hash by double hashing
+func (f *keyFilter) each(key string, fn func(bit uint64) bool) bool {
+	size := uint64(len(f.bits)) * 64
+	sum := xxhash.ChecksumString64(key)
+	h1, h2 := sum&math.MaxUint32, sum>>32
+	for i := uint64(0); i < uint64(f.hashes); i++ {
+		if !fn((h1 + i*h2) % size) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *keyFilter) add(key string) {
+	f.each(key, func(bit uint64) bool {
+		f.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+}
+
+// mayContain returns false if `key` was never added to the filter
+func (f *keyFilter) mayContain(key string) bool {
+	return f.each(key, func(bit uint64) bool {
+		return f.bits[bit/64]&(1<<(bit%64)) != 0
+	})
+}
+
+// GetPeerKeyFilter is called by other peers to collect a filter of the keys in the cache of this
+// peer, see Config.ColdKeyFilterInterval. Reports no filter if the rate limits missing from the
+// cache may be in the Store.
+func (s *V1Instance) GetPeerKeyFilter(ctx context.Context, r *GetPeerKeyFilterReq) (*GetPeerKeyFilterResp, error) {
+	if s.coldKeys == nil || s.conf.Store != nil {
+		return &GetPeerKeyFilterResp{}, nil
+	}
+	c := s.coldKeys
+	c.ownMutex.Lock()
+	defer c.ownMutex.Unlock()
+	if c.own != nil && clock.Since(c.ownBuiltAt) < c.interval/2 {
+		return c.own, nil
+	}
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeerKeyFilter")).ObserveDuration()
+
+	var keys []string
+	for item := range s.workerPool.Each(ctx) {
+		keys = append(keys, item.Key)
+	}
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	f := newKeyFilter(len(keys))
+	for _, key := range keys {
+		f.add(key)
+	}
+	c.own = &GetPeerKeyFilterResp{Bits: f.bits, Hashes: int32(f.hashes)}
+	c.ownBuiltAt = clock.Now()
+	return c.own, nil
+}
+
+// runColdKeyFilters pulls the key filter of every other peer each `Config.ColdKeyFilterInterval`
+// until the instance is closed
+func (s *V1Instance) runColdKeyFilters() {
+	defer s.coldKeys.wg.Done()
+	tick := clock.NewTicker(s.coldKeys.interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			s.refreshColdKeyFilters()
+		case <-s.coldKeys.done:
+			return
+		}
+	}
+}
+
+func (s *V1Instance) refreshColdKeyFilters() {
+	c := s.coldKeys
+	ctx, cancel := context.WithTimeout(context.Background(), c.interval)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, peer := range s.GetPeerList() {
+		if peer.Info().IsOwner {
+			continue
+		}
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			addr := peer.Info().GRPCAddress
+			resp, err := peer.GetPeerKeyFilter(ctx, &GetPeerKeyFilterReq{})
+			var f *keyFilter
+			switch {
+			case err != nil:
+				if status.Code(err) != codes.Unimplemented {
+					s.log.WithError(err).WithField("peer", addr).Debug("while fetching key filter")
+				}
+			case len(resp.Bits) != 0 && resp.Hashes > 0:
+				f = &keyFilter{bits: resp.Bits, hashes: int(resp.Hashes), fetchedAt: clock.Now()}
+			}
+
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			// A peer whose filter is not known forwards every rate limit
+			if f == nil {
+				delete(c.filters, addr)
+				return
+			}
+			c.filters[addr] = f
+		}(peer)
+	}
+	wg.Wait()
+
+	// The keys answered with the previous filters are in the new filters once their hits arrive
+	c.mutex.Lock()
+	c.answered = make(map[string]int64)
+	c.mutex.Unlock()
+}
+
+// getColdRateLimit returns nil unless the rate limit is owned by another peer which has never seen
+// its key, in which case the hits are answered as under the limit and sent to the owner, see coldKeys.
+func (s *V1Instance) getColdRateLimit(ctx context.Context, c *RateLimitCheck) *RateLimitResp {
+	r := c.Req
+	// Only a token bucket which starts full with the first hit may be answered without its owner
+	if s.coldKeys == nil || r.Algorithm != Algorithm_TOKEN_BUCKET || r.Hits <= 0 || r.IdempotencyKey != "" ||
+		HasBehavior(r.Behavior, Behavior_GLOBAL|Behavior_DURATION_IS_GREGORIAN|Behavior_RESET_REMAINING|
+			Behavior_MULTI_REGION|Behavior_DRAIN_OVER_LIMIT|Behavior_GREEDY_REFILL|Behavior_REFUNDABLE) {
+		return nil
+	}
+	share := r.Limit / int64(len(s.GetPeerList()))
+	if r.Hits > share {
+		return nil
+	}
+
+	start := clock.Now()
+	if !s.coldKeys.reserve(c.Peer.Info().GRPCAddress, c.Key, r.Hits, share) {
+		return nil
+	}
+	createdAt := epochMillis(start)
+	if r.CreatedAt != nil && *r.CreatedAt != 0 {
+		createdAt = *r.CreatedAt
+	}
+	rl := &RateLimitResp{
+		Status:    Status_UNDER_LIMIT,
+		Limit:     r.Limit,
+		Remaining: applyHits(r.Limit, r.Hits),
+		ResetTime: addInt64(addInt64(createdAt, r.Duration), resetJitter(&s.conf, r)),
+		Metadata:  map[string]string{"owner": c.Peer.Info().GRPCAddress},
+	}
+	s.global.QueueHit(r)
+	observeCheck(ctx, start, "cold", r, rl)
+	return rl
+}
+
+// reserve returns true if the owner at `addr` has never seen `key` and this instance answered less
+// than `share` hits of the key since the filters were refreshed
+func (c *coldKeys) reserve(addr, key string, hits, share int64) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	f, ok := c.filters[addr]
+	// A filter older than two intervals belongs to a peer which no longer responds
+	if !ok || clock.Since(f.fetchedAt) > 2*c.interval {
+		return false
+	}
+	answered, ok := c.answered[key]
+	if f.mayContain(key) || answered+hits > share || (!ok && len(c.answered) >= coldKeyMaxAnswered) {
+		metricColdKeyCounter.WithLabelValues("forwarded").Inc()
+		return false
+	}
+	c.answered[key] = answered + hits
+	metricColdKeyCounter.WithLabelValues("answered").Inc()
+	return true
+}
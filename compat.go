@@ -0,0 +1,161 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// CompatModeMetadataKey is the GRPC metadata key a client sets to the name of a compat mode, such
+// that the responses of the client are translated to the semantics it expects. HTTP clients set
+// the `Grpc-Metadata-Gubernator-Compat` header. See DaemonConfig.CompatModes
+const CompatModeMetadataKey = "gubernator-compat"
+
+// ResponseTransformer changes a rate limit response for legacy clients which expect different field
+// semantics than this version of Gubernator, IE: the reset time in seconds instead of milliseconds.
+type ResponseTransformer func(r *RateLimitResp)
+
+// TransformResetSeconds converts the reset time from unix epoch milliseconds to unix epoch seconds,
+// rounded up such that the client never retries before the rate limit resets
+func TransformResetSeconds(r *RateLimitResp) {
+	r.ResetTime = ceilSeconds(r.ResetTime)
+}
+
+// TransformResetAfterSeconds replaces the reset time with the number of seconds until the rate limit
+// resets, rounded up
+func TransformResetAfterSeconds(r *RateLimitResp) {
+	if r.ResetTime == 0 {
+		return
+	}
+	after := r.ResetTime - epochMillis(clock.Now())
+	if after < 0 {
+		after = 0
+	}
+	r.ResetTime = ceilSeconds(after)
+}
+
+func ceilSeconds(millis int64) int64 {
+	if millis <= 0 {
+		return 0
+	}
+	return (millis + 999) / 1000
+}
+
+// ParseResponseTransformer returns the built-in transformer named `name`, either 'reset_seconds'
+// or 'reset_after_seconds'
+func ParseResponseTransformer(name string) (ResponseTransformer, error) {
+	switch strings.TrimSpace(name) {
+	case "reset_seconds":
+		return TransformResetSeconds, nil
+	case "reset_after_seconds":
+		return TransformResetAfterSeconds, nil
+	}
+	return nil, errors.Errorf("transformer '%s' is invalid; choices are ['reset_seconds', 'reset_after_seconds']", name)
+}
+
+// ParseCompatMode parses a compat mode in the format used by `GUBER_COMPAT_MODES`,
+// IE: "legacy=reset_seconds" returns the name of the mode and its transformer.
+func ParseCompatMode(s string) (string, ResponseTransformer, error) {
+	mode, name, ok := strings.Cut(s, "=")
+	mode = strings.TrimSpace(mode)
+	if !ok || mode == "" {
+		return "", nil, errors.Errorf("compat mode '%s' is invalid; expected '<mode>=<transformer>'", s)
+	}
+	t, err := ParseResponseTransformer(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return mode, t, nil
+}
+
+// compatModes translates the responses of the V1 service for the clients which select a compat
+// mode with CompatModeMetadataKey, such that the server can be upgraded without upgrading every
+// client at the same time. Clients which select no mode, or a mode which is not configured, are
+// answered unchanged.
+type compatModes map[string][]ResponseTransformer
+
+// mode returns the name of the compat mode selected by the client, or an empty string
+func (c compatModes) mode(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(CompatModeMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// rateLimitResponses returns the rate limit responses held by a response of the V1 service
+func rateLimitResponses(resp interface{}) []*RateLimitResp {
+	switch r := resp.(type) {
+	case *GetRateLimitsResp:
+		return r.Responses
+	case *GetRateLimitGroupResp:
+		return r.Responses
+	case *TransactRateLimitsResp:
+		return r.Responses
+	case *GetRateLimitHierarchyResp:
+		return r.Responses
+	case *ReserveRateLimitResp:
+		if r.RateLimit != nil {
+			return []*RateLimitResp{r.RateLimit}
+		}
+	}
+	return nil
+}
+
+// unaryInterceptor returns a server interceptor which translates the responses of the V1 service
+// for the compat mode selected by the client. Responses which hold an error are not changed.
+func (c compatModes) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		resps := rateLimitResponses(resp)
+		if len(resps) == 0 {
+			return resp, nil
+		}
+		name := c.mode(ctx)
+		if name == "" {
+			return resp, nil
+		}
+		transformers, ok := c[name]
+		if !ok {
+			metricCompatResponses.WithLabelValues("unknown").Add(float64(len(resps)))
+			return resp, nil
+		}
+		for _, rl := range resps {
+			if rl == nil || rl.Error != "" {
+				continue
+			}
+			for _, t := range transformers {
+				t(rl)
+			}
+		}
+		metricCompatResponses.WithLabelValues(name).Add(float64(len(resps)))
+		return resp, nil
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCompatModes(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	now := epochMillis(clock.Now())
+	resetTime := now + 1500
+
+	intercept := compatModes{
+		"v0":          {TransformResetSeconds},
+		"v0-relative": {TransformResetAfterSeconds},
+	}.unaryInterceptor()
+	call := func(mode string) *GetRateLimitsResp {
+		ctx := context.Background()
+		if mode != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CompatModeMetadataKey, mode))
+		}
+		handler := func(context.Context, interface{}) (interface{}, error) {
+			return &GetRateLimitsResp{Responses: []*RateLimitResp{
+				{Remaining: 1, ResetTime: resetTime},
+				{Error: "field 'unique_key' cannot be empty", ResetTime: resetTime},
+			}}, nil
+		}
+		resp, err := intercept(ctx, &GetRateLimitsReq{},
+			&grpc.UnaryServerInfo{FullMethod: "/pb.gubernator.V1/GetRateLimits"}, handler)
+		require.NoError(t, err)
+		return resp.(*GetRateLimitsResp)
+	}
+
+	// Clients which select no mode, or a mode which is not configured, are answered unchanged
+	assert.Equal(t, resetTime, call("").Responses[0].ResetTime)
+	assert.Equal(t, resetTime, call("v2").Responses[0].ResetTime)
+
+	resp := call("v0")
+	assert.Equal(t, (resetTime+999)/1000, resp.Responses[0].ResetTime)
+	assert.Equal(t, int64(1), resp.Responses[0].Remaining)
+	// Responses which hold an error are not changed
+	assert.Equal(t, resetTime, resp.Responses[1].ResetTime)
+
+	assert.Equal(t, int64(2), call("v0-relative").Responses[0].ResetTime)
+	clock.Advance(2 * time.Second)
+	assert.Equal(t, int64(0), call("v0-relative").Responses[0].ResetTime)
+}
+
+func TestParseCompatMode(t *testing.T) {
+	mode, _, err := ParseCompatMode(" v0 = reset_seconds ")
+	require.NoError(t, err)
+	assert.Equal(t, "v0", mode)
+
+	_, _, err = ParseCompatMode("reset_seconds")
+	assert.ErrorContains(t, err, "expected '<mode>=<transformer>'")
+	_, _, err = ParseCompatMode("v0=reset_minutes")
+	assert.ErrorContains(t, err, "transformer 'reset_minutes' is invalid")
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/gubernator-io/gubernator/v2/client"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+// The names of the GRPC compressors gubernator registers, see client.CompressionGzip
+const (
+	CompressionGzip   = client.CompressionGzip
+	CompressionSnappy = client.CompressionSnappy
+)
+
+// validateCompression returns an error if `name` is not a registered GRPC compressor.
+// An empty name is valid and means no compression.
+func validateCompression(name string) error {
+	if name == "" {
+		return nil
+	}
+	if encoding.GetCompressor(name) == nil {
+		return errors.Errorf("compression '%s' is invalid; choices are ['%s', '%s']",
+			name, CompressionGzip, CompressionSnappy)
+	}
+	return nil
+}
+
+// compressionInterceptor returns a client interceptor which compresses requests of at least `minBytes`
+// with the `name` compressor. Batches of peer requests repeat the same names and compress well, while
+// compressing small requests costs more CPU than the bandwidth it saves.
+func compressionInterceptor(name string, minBytes int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if m, ok := req.(proto.Message); ok && proto.Size(m) >= minBytes {
+			opts = append(opts, grpc.UseCompressor(name))
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCompressionMinBytes(t *testing.T) {
+	small := &GetPeerRateLimitsReq{Requests: []*RateLimitReq{{Name: "test_compression", UniqueKey: "account:1"}}}
+	large := &GetPeerRateLimitsReq{}
+	for i := 0; i < 100; i++ {
+		large.Requests = append(large.Requests, &RateLimitReq{Name: "test_compression", UniqueKey: fmt.Sprintf("account:%d", i)})
+	}
+	minBytes := proto.Size(small) + 1
+	require.Less(t, minBytes, proto.Size(large))
+
+	// compressor returns the compressor the interceptor passed to the invoker, empty if none
+	compressor := func(minBytes int, req *GetPeerRateLimitsReq) string {
+		var name string
+		intercept := compressionInterceptor(CompressionSnappy, minBytes)
+		err := intercept(context.Background(), "/pb.gubernator.PeersV1/GetPeerRateLimits", req, nil, nil,
+			func(_ context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+				for _, opt := range opts {
+					if c, ok := opt.(grpc.CompressorCallOption); ok {
+						name = c.CompressorType
+					}
+				}
+				return nil
+			})
+		require.NoError(t, err)
+		return name
+	}
+
+	assert.Equal(t, "", compressor(minBytes, small))
+	assert.Equal(t, CompressionSnappy, compressor(minBytes, large))
+	assert.Equal(t, CompressionSnappy, compressor(0, small))
+}
@@ -31,6 +31,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OneOfOne/xxhash"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/setter"
@@ -43,6 +44,7 @@ import (
 	etcd "go.etcd.io/etcd/client/v3"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // BehaviorConfig controls the handling of rate limits in the cluster
@@ -55,6 +57,24 @@ type BehaviorConfig struct {
 	BatchLimit int
 	// DisableBatching disables batching behavior for all ratelimits.
 	DisableBatching bool
+	// The max number of requests queued for batching to a single peer. Once full, requests wait up
+	// to BatchQueueWait for room in the queue before BatchOverflowPolicy applies. Defaults to 1000
+	BatchQueueLimit int
+	// How long a request waits for room in a full batch queue. Defaults to 500ms
+	BatchQueueWait time.Duration
+	// What happens to a request which found no room in the batch queue, one of BatchOverflowSpill,
+	// BatchOverflowReject or BatchOverflowDrop. Defaults to BatchOverflowReject
+	BatchOverflowPolicy string
+	// CoalesceRequests merges the batched requests forwarded to a peer which are identical but for
+	// their hits into a single request with the sum of their hits, and shares the response of the
+	// merged request among them. As the merged request is evaluated as a whole, a request may be
+	// denied which would have been allowed on its own. Requests with an idempotency key are not merged.
+	CoalesceRequests bool
+	// How long to wait before the first attempt to reconnect to a peer after the connection is lost.
+	// Subsequent attempts back off exponentially with jitter.
+	PeerReconnectBaseDelay time.Duration
+	// The max delay between attempts to reconnect to a peer
+	PeerReconnectMaxDelay time.Duration
 
 	// How long a non-owning peer should wait before syncing hits to the owning peer
 	GlobalSyncWait time.Duration
@@ -64,15 +84,69 @@ type BehaviorConfig struct {
 	GlobalBatchLimit int
 	// ForceGlobal forces global behavior on all rate limit checks.
 	ForceGlobal bool
+	// DryRunNames is a list of rate limit names which have the DRY_RUN behavior forced on them.
+	DryRunNames []string
+	// GreedyRefillNames is a list of rate limit names which have the GREEDY_REFILL behavior forced on them.
+	GreedyRefillNames []string
+	// VerifyPeerOwnership causes GetPeerRateLimits to reject rate limits this instance does not own
+	// according to its own picker, instead of evaluating them. This prevents two peers from tracking
+	// the same rate limit when they disagree on the peer list, IE: during a split-brain.
+	VerifyPeerOwnership bool
 
 	// Number of concurrent requests that will be made to peers. Defaults to 100
 	GlobalPeerRequestsConcurrency int
+
+	// (Optional) Spreads out the reset time of token bucket rate limits by up to plus or minus
+	// this percentage of the duration, such that rate limits with the same duration do not all
+	// reset at the same time. Must be less than 100. Defaults to 0 (disabled)
+	ResetJitterPercent int
+
+	// (Optional) The percentage of the limit a token bucket may go over before requests are denied,
+	// IE: 2 allows 102 hits for a limit of 100. This smooths out edge cases where clients and servers
+	// disagree on when the rate limit resets. Defaults to 0 (disabled)
+	GracePercent int
+
+	// (Optional) How long the hits of a request with the REFUNDABLE behavior may be refunded with
+	// RefundRateLimit. Defaults to 1 minute
+	RefundWindow time.Duration
+
+	// (Optional) How long the response to a request with an idempotency key is remembered, such that
+	// a retry of the request returns the original response instead of applying the hits again.
+	// Defaults to 1 minute
+	IdempotencyWindow time.Duration
+
+	// (Optional) How long the number of OVER_LIMIT responses of a rate limit is kept after its last
+	// OVER_LIMIT response, see MetadataOverLimitCount. Each counted rate limit takes memory on the
+	// instance which owns it until the count expires. Defaults to 0 (disabled)
+	OverLimitRetention time.Duration
+
+	// (Optional) When more than this percentage of the requests forwarded to other peers fail within
+	// `DegradedWindow`, rate limits owned by a peer which cannot be reached are evaluated locally with
+	// the limit divided by the number of peers, instead of returning an error. Defaults to 0 (disabled)
+	DegradedErrorPercent int
+	// (Optional) The window over which the error rate of forwarded requests is measured. Defaults to 10 seconds
+	DegradedWindow time.Duration
+
+	// (Optional) When the p99 latency of the requests forwarded to the owning peer over the last minute
+	// exceeds this threshold, rate limits owned by the peer are evaluated by the next peer on the hash ring
+	// instead, from its own possibly stale state. Defaults to 0 (disabled)
+	SlowPeerThreshold time.Duration
+
+	// (Optional) GetRateLimits calls and GetPeerRateLimits RPCs which take longer than this threshold
+	// are logged, with the batch size, the peers involved and the time spent in each phase, and
+	// counted by `gubernator_slow_request_counter`. Defaults to 0 (disabled)
+	SlowRequestThreshold time.Duration
 }
 
 // Config for a gubernator instance
 type Config struct {
 	InstanceID string
 
+	// (Optional) The stable identity of this instance, see DaemonConfig.NodeID. Sent with the rate
+	// limits this instance forwards to recognize itself and reported to peers by GetPeerVersion.
+	// Defaults to a random ID which changes each time the instance is created
+	NodeID string
+
 	// (Required) A list of GRPC servers to register our instance with
 	GRPCServers []*grpc.Server
 
@@ -82,16 +156,62 @@ type Config struct {
 	// (Optional) The cache implementation
 	CacheFactory func(maxSize int) Cache
 
+	// (Optional) Builds the key which identifies a rate limit. Every peer in the cluster must
+	// use the same HashKeyFunc. Defaults to LegacyHashKey
+	HashKey HashKeyFunc
+
+	// (Optional) Applied in order to each rate limit request before it is hashed and evaluated, such that
+	// inconsistent clients do not create duplicate rate limits. See NormalizeLowercase, NormalizeTrimSpace
+	// and NewNameAliases
+	Normalizers []NormalizeFunc
+
+	// (Optional) Middleware added to the behavior pipeline of GetRateLimits, each middleware may decide
+	// or change the response of a rate limit. The first middleware is the outermost. See BehaviorMiddleware
+	Middleware []BehaviorMiddleware
+
+	// (Optional) Caps what clients may request for the rate limits in a namespace, see NamespacePolicy
+	NamespacePolicies []NamespacePolicy
+
+	// (Optional) A YAML file of namespace policies which is reloaded when it changes on disk. A
+	// policy in the file replaces a policy in NamespacePolicies with the same prefix. The version
+	// of the policies in effect is reported by HealthCheck. Defaults to "" (no policy file)
+	PolicyFile string
+
+	// (Optional) Defines the limit, duration and algorithm of the rate limits with the name of a
+	// template, such that clients only provide the unique key, see RateLimitTemplate
+	Templates []RateLimitTemplate
+
 	// (Optional) A persistent store implementation. Allows the implementor the ability to store the rate limits this
 	// instance of gubernator owns. It's up to the implementor to decide what rate limits to persist.
 	// For instance an implementor might only persist rate limits that have an expiration of
 	// longer than 1 hour.
 	Store Store
 
+	// (Optional) The most changes reported with each call to BatchStore.OnChanges(), when Store
+	// implements BatchStore. Defaults to 1000
+	StoreBatchLimit int
+
+	// (Optional) How long changes are queued before they are reported to BatchStore.OnChanges(),
+	// when Store implements BatchStore. Defaults to 100ms
+	StoreBatchWait time.Duration
+
 	// (Optional) A loader from a persistent store. Allows the implementor the ability to load and save
 	// the contents of the cache when the gubernator instance is started and stopped
 	Loader Loader
 
+	// (Optional) How often Loader.Save() is called with the contents of the cache while the instance is
+	// running, in addition to when the instance is closed. Requires Loader. See NewSnapshotLoader.
+	// Defaults to 0 (only saved when closed)
+	SnapshotInterval time.Duration
+
+	// (Optional) The directory every hit applied to the rate limits owned by this instance is
+	// appended to, such that they can be replayed by AdminV1.ReplayJournal after the rate limits are
+	// lost. Defaults to "" (no journal)
+	JournalDir string
+
+	// (Optional) How long the hits in JournalDir are kept. Defaults to 24 hours
+	JournalRetention time.Duration
+
 	// (Optional) This is the peer picker algorithm the server will use decide which peer in the local cluster
 	// will own the rate limit
 	LocalPicker PeerPicker
@@ -111,33 +231,261 @@ type Config struct {
 	// (Optional) The TLS config used when connecting to gubernator peers
 	PeerTLS *tls.Config
 
+	// (Optional) Replaces the server name or the CAs of PeerTLS when connecting to some of the peers,
+	// see PeerTLSOverride
+	PeerTLSOverrides []PeerTLSOverride
+
 	// (Optional) If true, will emit traces for GRPC client requests to other peers
 	PeerTraceGRPC bool
 
+	// (Optional) The GRPC compressor used for requests to other peers, either 'gzip' or 'snappy'.
+	// Defaults to no compression
+	PeerCompression string
+
+	// (Optional) Requests to other peers smaller than this number of bytes are not compressed, as
+	// compressing a small request costs more CPU than the bandwidth it saves. Only large batches of
+	// forwarded requests are compressed when set. Defaults to 0 (every request is compressed)
+	PeerCompressionMinBytes int
+
 	// (Optional) The number of go routine workers used to process concurrent rate limit requests
 	// Default is set to number of CPUs.
 	Workers int
 
+	// (Optional) The number of long-lived goroutines for each worker which evaluate the rate limits
+	// forwarded by other peers, each rate limit on the goroutines of the worker which owns its key.
+	// Avoids moving rate limits between cores on machines with many cores. Defaults to 0, where
+	// each forwarded batch starts up to Workers goroutines of its own
+	FanOutWorkers int
+
+	// (Optional) The number of forwarded rate limits which may wait for the FanOutWorkers of each
+	// worker, beyond which forwarded batches wait for room. Defaults to 100
+	FanOutQueueSize int
+
+	// (Optional) If true, query only requests (Hits = 0) of a rate limit in the cache are evaluated
+	// against a copy of the rate limit published by its worker, instead of waiting for the worker,
+	// such that inspecting rate limits scales with the number of cores. Costs a copy of the rate
+	// limit each time it is updated. Requires a Cache which implements ReadCopyCache. Defaults to false
+	LockFreeReads bool
+
 	// (Optional) The total size of the cache used to store rate limits. Defaults to 50,000
 	CacheSize int
+
+	// (Optional) The approximate max number of bytes used by the rate limits in the cache. When set,
+	// the oldest rate limits are evicted once either CacheSize or MaxCacheBytes is reached.
+	// Requires a Cache which implements ByteLimitedCache. Defaults to 0 (no limit)
+	MaxCacheBytes int64
+
+	// (Optional) Rate limits which are not accessed within this duration are evicted from the cache,
+	// even if they have not expired, IE: a 30 day rate limit for a key seen once. An evicted rate limit
+	// is loaded from the Store the next time it is accessed, as such requires a Store. Requires a Cache
+	// which implements IdleCache. Defaults to 0 (rate limits are only evicted when the cache is full)
+	CacheIdleTTL time.Duration
+
+	// (Optional) What happens when a rate limit which is not in the cache is requested while the cache
+	// is full of unexpired rate limits. One of CacheFullEvictLRU, which evicts the least recently used
+	// rate limit; CacheFullReject, which rejects the request with RESOURCE_EXHAUSTED; CacheFullEvictOldestReset,
+	// which evicts the rate limit which resets soonest; or CacheFullSpill, which saves the least recently
+	// used rate limit to the Store before evicting it, as such requires a Store. Requires a Cache which
+	// implements CapacityCache. Defaults to CacheFullEvictLRU
+	CacheFullPolicy string
+
+	// (Optional) Rate limits whose name begins with the prefix of a class are held in a cache of the
+	// class instead of the cache of CacheSize, such that each class has its own size, eviction and
+	// persistence. Defaults to none
+	CacheClasses []CacheClass
+
+	// (Optional) Partitions the cache by tenant, such that a tenant with millions of rate limits cannot
+	// evict the rate limits of every other tenant. The tenant of a rate limit is the part of its name
+	// before the first separator, IE: with ":" the tenant of "acme:login" is "acme". A name without the
+	// separator is its own tenant. Requires a Cache which implements TenantCache. Defaults to "" (the
+	// cache is not partitioned)
+	CacheTenantSeparator string
+
+	// (Optional) The percentage of the cache a single tenant may use. Once a tenant uses its share, its
+	// least recently used rate limit is evicted to make room for its new rate limits, even if the cache
+	// is not full. Defaults to 10
+	CacheTenantPercent int
+
+	// (Optional) The percentage of the cache of individual tenants, which overrides CacheTenantPercent,
+	// IE: a larger share for a tenant known to have many more keys than the others.
+	CacheTenantPercents map[string]int
+
+	// (Optional) Indexes the rate limits in the cache by this 64 bit hash of their key instead of the
+	// key, such that the index holds no copy of the keys, IE: millions of UUID keys. Keys whose hash
+	// collides are indexed by the key, as such a collision never merges two rate limits. Requires a
+	// Cache which implements HashedKeyCache. Defaults to nil (the cache is indexed by key)
+	CacheKeyHash HashString64
+
+	// (Optional) What happens when a rate limit is requested with a different algorithm than it was
+	// created with, IE: a client migrating a key from TOKEN_BUCKET to LEAKY_BUCKET. One of
+	// AlgorithmChangeReset, which discards the rate limit and creates a new one; AlgorithmChangeTranslate,
+	// which carries the remaining hits over to the new algorithm; or AlgorithmChangeReject, which responds
+	// with ErrAlgorithmChanged until the rate limit expires. Defaults to AlgorithmChangeReset
+	AlgorithmChangePolicy string
+
+	// (Optional) The max length in bytes of the name of a rate limit. Requests for longer names respond
+	// with ErrKeyTooLong. Defaults to 0 (no limit)
+	MaxNameLength int
+
+	// (Optional) The max length in bytes of the unique key of a rate limit, such that clients cannot
+	// bloat the cache and the requests forwarded to peers with long keys. What happens to longer keys
+	// is decided by LongKeyPolicy. Defaults to 0 (no limit)
+	MaxKeyLength int
+
+	// (Optional) What happens to a unique key longer than MaxKeyLength. One of LongKeyReject, which
+	// responds with ErrKeyTooLong; or LongKeyHash, which replaces the key with its SHA-256 digest, as
+	// such MaxKeyLength must be at least the length of the digest. Defaults to LongKeyReject
+	LongKeyPolicy string
+
+	// (Optional) How unique keys appear in logs and trace attributes, as keys often contain email
+	// addresses or tokens. One of KeyRedactionHash, which replaces the key with a short digest;
+	// KeyRedactionMask, which replaces the key with a fixed mask; or KeyRedactionNone, which logs
+	// the key as is for debugging. Defaults to KeyRedactionHash
+	KeyRedaction string
+
+	// (Optional) The rate limits of a name which was not accessed within this duration are removed from
+	// the cache and the Store, such that decommissioned services do not leave rate limits behind. Rate
+	// limits are only removed from the Store if they are in the cache, unless the Store implements
+	// NamespaceStore. See AdminV1.ListNamespaces. Defaults to 0 (namespaces are never removed)
+	NamespaceGCAfter time.Duration
+
+	// (Optional) How often the filter of the keys in the cache of every other peer is pulled, such
+	// that the hits of a token bucket whose owner has never seen the key are answered locally as
+	// under the limit instead of being forwarded. The owner receives the hits asynchronously, each
+	// peer answers at most its share of the limit per interval. Peers with a Store report no filter.
+	// Defaults to 0 (every rate limit owned by another peer is forwarded)
+	ColdKeyFilterInterval time.Duration
+
+	// (Optional) The instance is not ready until SetPeers() has been called with at least this many
+	// peers. HealthCheck reports 'unhealthy' until the instance is ready. Defaults to 0 (always ready)
+	ReadyMinPeers int
+
+	// (Optional) The instance is not ready until SetPeers() has been called with at least this
+	// percentage of the peers peer discovery reported to SetClusterSize(), and is not ready until
+	// SetClusterSize() is called. Defaults to 0 (no percentage is required)
+	ReadyMinPeersPercent int
+
+	// (Optional) If true, GetRateLimits returns codes.Unavailable until the instance is ready.
+	// See ReadyMinPeers
+	UnavailableUntilReady bool
+
+	// (Optional) The timeout of the requests made without a deadline, such that a caller which set
+	// no timeout cannot hold a request forever, IE: while the owning peer does not respond. Applies
+	// to GetRateLimits, GetRateLimitGroup, reservations, transactions, hierarchies, refunds, leases and
+	// RegisterLimits. Set to a negative duration to disable. Defaults to 30 seconds
+	DefaultRequestTimeout time.Duration
+
+	// (Optional) Injects faults for testing client behavior under partial failure. DO NOT use in production
+	Faults *FaultConfig
+
+	// (Optional) Receives a record for every OVER_LIMIT decision made by this instance and every
+	// administrative change made through this instance. See NewFileAuditSink and NewWebhookAuditSink
+	AuditSink AuditSink
+
+	// (Optional) If true, registers the AdminV1 service with GRPCServers which allows clients to
+	// reset rate limits across the entire cluster. Defaults to false
+	AdminEnabled bool
+
+	// (Optional) If true, requests may override which peer evaluates a rate limit with the FORCE_LOCAL
+	// and FORCE_PEER behaviors, else such requests are rejected. Requires AdminEnabled. Defaults to false
+	ForwardingOverridesEnabled bool
+
+	// (Optional) The length of the rolling window over which the usage of each rate limit name is
+	// tracked, see AdminV1.GetNamespaceUsage. Defaults to 0 (usage is not tracked)
+	UsageWindow time.Duration
+
+	// (Optional) Called every UsageExportInterval with the usage of the rate limits owned by this
+	// instance. Requires UsageWindow. See NewWebhookUsageExporter
+	UsageExporter UsageExportFunc
+
+	// (Optional) How often UsageExporter is called. Defaults to 1 minute
+	UsageExportInterval time.Duration
+
+	// (Optional) Adds noise to, or rounds, the usage reported by AdminV1.GetNamespaceUsage and
+	// UsageExporter, such that the exact traffic of each name is not revealed, see UsagePrivacyConfig
+	UsagePrivacy UsagePrivacyConfig
+
+	// (Optional) The number of distinct cost centers the hits and evaluation time of the rate limits
+	// owned by this instance are attributed to, by the `cost_center` metadata of each request, see
+	// MetadataCostCenter. Cost centers seen after this many others are attributed to 'other'. The
+	// UsageReport includes the cost centers if UsageWindow is set. Defaults to 0 (cost centers are
+	// not tracked)
+	MaxCostCenters int
+
+	// (Optional) The number of recent hits recorded for each rate limit, see AdminV1.GetKeyHistory.
+	// Defaults to 0 (hits are not recorded)
+	KeyHistorySize int
+
+	// (Optional) The max number of rate limits whose hits are recorded, once full the rate limit hit
+	// least recently is forgotten. Defaults to 10,000
+	KeyHistoryMaxKeys int
+
+	// (Optional) Calls AlertNotifier when the rate limits in a namespace are over the limit more
+	// often than a threshold, see OverLimitAlert
+	OverLimitAlerts []OverLimitAlert
+
+	// (Optional) Called each time an OverLimitAlert fires or resolves. Required by OverLimitAlerts.
+	// See NewWebhookAlertNotifier and NewSlackAlertNotifier
+	AlertNotifier AlertNotifyFunc
+
+	// (Optional) Proxies the rate limits of designated namespaces to a home cluster, such that they
+	// are enforced once across every cluster, see FederationConfig
+	Federation FederationConfig
+
+	// (Optional) Mirrors a percentage of the requests to a secondary cluster and compares its
+	// decisions with ours, see ShadowConfig
+	Shadow ShadowConfig
+
+	// (Optional) Replicates the rate limits of critical names to a standby peer before responding,
+	// such that they survive the crash of their owner, see StandbyConfig
+	Standby StandbyConfig
+
+	// (Optional) EXPERIMENTAL: The transport used for requests to other peers, either
+	// PeerTransportGRPC or PeerTransportQUIC. QUIC requires PeerTLS and every peer must serve
+	// the PeersV1 service with NewQUICPeerServer(). Defaults to PeerTransportGRPC
+	PeerTransport string
+
+	// (Optional) The token sent with every request made to other peers. The PeersV1 service of
+	// every peer must be protected with PeerAuthConfig.UnaryServerInterceptor(). Defaults to nil
+	// (requests to peers are not authenticated)
+	PeerAuth *PeerAuthConfig
+
+	// (Optional) Signs the request values, IE: the identity of the client, sent along with the
+	// rate limits forwarded to the owning peer, and rejects forwarded rate limits whose values are
+	// not signed. Every peer must have the same key. Defaults to nil (request values are trusted)
+	RequestValueSigning *RequestValueSigningConfig
 }
 
+// SetDefaults fills the fields which are not set with their defaults, then validates the
+// config, see Validate()
 func (c *Config) SetDefaults() error {
 	setter.SetDefault(&c.Behaviors.BatchTimeout, time.Millisecond*500)
 	setter.SetDefault(&c.Behaviors.BatchLimit, maxBatchSize)
 	setter.SetDefault(&c.Behaviors.BatchWait, time.Microsecond*500)
+	setter.SetDefault(&c.Behaviors.BatchQueueLimit, 1000)
+	setter.SetDefault(&c.Behaviors.BatchQueueWait, time.Millisecond*500)
+	setter.SetDefault(&c.Behaviors.BatchOverflowPolicy, BatchOverflowReject)
+	setter.SetDefault(&c.Behaviors.PeerReconnectBaseDelay, time.Millisecond*100)
+	setter.SetDefault(&c.Behaviors.PeerReconnectMaxDelay, time.Second*5)
 
 	setter.SetDefault(&c.Behaviors.GlobalTimeout, time.Millisecond*500)
 	setter.SetDefault(&c.Behaviors.GlobalBatchLimit, maxBatchSize)
 	setter.SetDefault(&c.Behaviors.GlobalSyncWait, time.Millisecond*100)
 
 	setter.SetDefault(&c.Behaviors.GlobalPeerRequestsConcurrency, 100)
+	setter.SetDefault(&c.Behaviors.RefundWindow, time.Minute)
+	setter.SetDefault(&c.Behaviors.IdempotencyWindow, time.Minute)
+	setter.SetDefault(&c.Behaviors.DegradedWindow, 10*time.Second)
 
 	setter.SetDefault(&c.LocalPicker, NewReplicatedConsistentHash(nil, defaultReplicas))
 	setter.SetDefault(&c.RegionPicker, NewRegionPicker(nil))
 
 	setter.SetDefault(&c.CacheSize, 50_000)
 	setter.SetDefault(&c.Workers, runtime.NumCPU())
+	setter.SetDefault(&c.FanOutQueueSize, 100)
+	setter.SetDefault(&c.StoreBatchLimit, 1000)
+	setter.SetDefault(&c.StoreBatchWait, time.Millisecond*100)
+	setter.SetDefault(&c.DefaultRequestTimeout, time.Second*30)
 	setter.SetDefault(&c.Logger, logrus.New().WithField("category", "gubernator"))
 
 	if c.CacheFactory == nil {
@@ -146,15 +494,276 @@ func (c *Config) SetDefaults() error {
 		}
 	}
 
-	if c.Behaviors.BatchLimit > maxBatchSize {
-		return fmt.Errorf("Behaviors.BatchLimit cannot exceed '%d'", maxBatchSize)
+	if c.HashKey == nil {
+		c.HashKey = LegacyHashKey
 	}
 
+	setter.SetDefault(&c.CacheFullPolicy, CacheFullEvictLRU)
+	for i := range c.CacheClasses {
+		setter.SetDefault(&c.CacheClasses[i].FullPolicy, c.CacheFullPolicy)
+		setter.SetDefault(&c.CacheClasses[i].IdleTTL, c.CacheIdleTTL)
+	}
+	if c.CacheTenantSeparator != "" {
+		setter.SetDefault(&c.CacheTenantPercent, 10)
+	}
+	setter.SetDefault(&c.AlgorithmChangePolicy, AlgorithmChangeReset)
+	setter.SetDefault(&c.LongKeyPolicy, LongKeyReject)
+	setter.SetDefault(&c.KeyRedaction, KeyRedactionHash)
+
+	setter.SetDefault(&c.UsageExportInterval, time.Minute)
+	setter.SetDefault(&c.JournalRetention, 24*time.Hour)
+	setter.SetDefault(&c.KeyHistoryMaxKeys, 10_000)
+
 	// Make a copy of the TLS config in case our caller decides to make changes
 	if c.PeerTLS != nil {
 		c.PeerTLS = c.PeerTLS.Clone()
 	}
 
+	return c.Validate()
+}
+
+// ConfigErrors holds every problem found by Config.Validate()
+type ConfigErrors []error
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate returns every problem with the config as ConfigErrors, rather than only the first,
+// such that they are fixed at once instead of one restart at a time. It is called by SetDefaults()
+// and as such by NewV1Instance(). The optional configs which are enabled, IE: Federation, are
+// defaulted as they are validated.
+func (c *Config) Validate() error {
+	var errs ConfigErrors
+	fail := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.CacheSize < 0 {
+		fail(errors.New("CacheSize cannot be negative"))
+	}
+	if c.Workers < 0 {
+		fail(errors.New("Workers cannot be negative"))
+	}
+	// Each worker holds an equal share of the cache, a share of 0 is an unbounded cache
+	if c.CacheSize > 0 && c.CacheSize < c.Workers {
+		fail(fmt.Errorf("CacheSize '%d' cannot be less than Workers '%d', as each worker holds an "+
+			"equal share of the cache; increase CacheSize or reduce Workers", c.CacheSize, c.Workers))
+	}
+	if c.FanOutWorkers < 0 {
+		fail(errors.New("FanOutWorkers cannot be negative"))
+	}
+
+	if c.Behaviors.BatchLimit > maxBatchSize {
+		fail(fmt.Errorf("Behaviors.BatchLimit cannot exceed '%d'", maxBatchSize))
+	}
+	if c.Behaviors.BatchQueueLimit < 0 || c.Behaviors.BatchQueueWait < 0 {
+		fail(errors.New("Behaviors.BatchQueueLimit and Behaviors.BatchQueueWait cannot be negative"))
+	}
+	fail(validateBatchOverflowPolicy(c.Behaviors.BatchOverflowPolicy))
+
+	if c.MaxCacheBytes < 0 {
+		fail(errors.New("MaxCacheBytes cannot be negative"))
+	}
+
+	if c.CacheIdleTTL < 0 {
+		fail(errors.New("CacheIdleTTL cannot be negative"))
+	}
+	if c.StoreBatchLimit < 0 || c.StoreBatchWait < 0 {
+		fail(errors.New("StoreBatchLimit and StoreBatchWait cannot be negative"))
+	}
+	if c.CacheIdleTTL > 0 && c.Store == nil {
+		fail(errors.New("CacheIdleTTL requires Store"))
+	}
+	switch c.CacheFullPolicy {
+	case CacheFullEvictLRU, CacheFullReject, CacheFullEvictOldestReset:
+	case CacheFullSpill:
+		if c.Store == nil {
+			fail(errors.New("CacheFullPolicy 'spill' requires Store"))
+		}
+	default:
+		fail(fmt.Errorf("CacheFullPolicy '%s' is invalid; expected one of '%s', '%s', '%s' or '%s'", c.CacheFullPolicy,
+			CacheFullEvictLRU, CacheFullReject, CacheFullEvictOldestReset, CacheFullSpill))
+	}
+	classes := make(map[string]bool, len(c.CacheClasses))
+	for i := range c.CacheClasses {
+		class := &c.CacheClasses[i]
+		if err := class.validate(c); err != nil {
+			fail(err)
+			continue
+		}
+		if class.Size < c.Workers {
+			fail(errors.Errorf("CacheClasses.Size '%d' of '%s' cannot be less than Workers '%d', as each worker "+
+				"holds an equal share of the class; increase the size of the class", class.Size, class.Prefix, c.Workers))
+		}
+		if classes[class.Prefix] {
+			fail(errors.Errorf("CacheClasses contains more than one class with prefix '%s'", class.Prefix))
+		}
+		classes[class.Prefix] = true
+	}
+	if c.CacheTenantSeparator != "" {
+		if c.CacheTenantPercent < 1 || c.CacheTenantPercent > 100 {
+			fail(errors.New("CacheTenantPercent must be between 1 and 100"))
+		}
+		for tenant, percent := range c.CacheTenantPercents {
+			if percent < 1 || percent > 100 {
+				fail(fmt.Errorf("CacheTenantPercents of tenant '%s' must be between 1 and 100", tenant))
+			}
+		}
+	}
+	switch c.AlgorithmChangePolicy {
+	case AlgorithmChangeReset, AlgorithmChangeTranslate, AlgorithmChangeReject:
+	default:
+		fail(fmt.Errorf("AlgorithmChangePolicy '%s' is invalid; expected one of '%s', '%s' or '%s'", c.AlgorithmChangePolicy,
+			AlgorithmChangeReset, AlgorithmChangeTranslate, AlgorithmChangeReject))
+	}
+	if c.MaxNameLength < 0 {
+		fail(errors.New("MaxNameLength cannot be negative"))
+	}
+	if c.MaxKeyLength < 0 {
+		fail(errors.New("MaxKeyLength cannot be negative"))
+	}
+	switch c.LongKeyPolicy {
+	case LongKeyReject:
+	case LongKeyHash:
+		if c.MaxKeyLength != 0 && c.MaxKeyLength < hashedKeyLength {
+			fail(fmt.Errorf("LongKeyPolicy '%s' requires a MaxKeyLength of at least '%d'", LongKeyHash, hashedKeyLength))
+		}
+	default:
+		fail(fmt.Errorf("LongKeyPolicy '%s' is invalid; expected one of '%s' or '%s'", c.LongKeyPolicy,
+			LongKeyReject, LongKeyHash))
+	}
+	switch c.KeyRedaction {
+	case KeyRedactionHash, KeyRedactionMask, KeyRedactionNone:
+	default:
+		fail(fmt.Errorf("KeyRedaction '%s' is invalid; expected one of '%s', '%s' or '%s'", c.KeyRedaction,
+			KeyRedactionHash, KeyRedactionMask, KeyRedactionNone))
+	}
+	if c.NamespaceGCAfter < 0 {
+		fail(errors.New("NamespaceGCAfter cannot be negative"))
+	}
+	if c.ColdKeyFilterInterval < 0 {
+		fail(errors.New("ColdKeyFilterInterval cannot be negative"))
+	}
+	if c.ForwardingOverridesEnabled && !c.AdminEnabled {
+		fail(errors.New("ForwardingOverridesEnabled requires AdminEnabled"))
+	}
+
+	if c.Behaviors.PeerReconnectMaxDelay < c.Behaviors.PeerReconnectBaseDelay {
+		fail(errors.New("Behaviors.PeerReconnectMaxDelay cannot be less than Behaviors.PeerReconnectBaseDelay"))
+	}
+
+	if c.ReadyMinPeers < 0 {
+		fail(errors.New("ReadyMinPeers cannot be negative"))
+	}
+	if c.ReadyMinPeersPercent < 0 || c.ReadyMinPeersPercent > 100 {
+		fail(errors.New("ReadyMinPeersPercent must be between 0 and 100"))
+	}
+
+	if c.UsageWindow < 0 {
+		fail(errors.New("UsageWindow cannot be negative"))
+	}
+	if c.UsageExporter != nil && c.UsageWindow == 0 {
+		fail(errors.New("UsageExporter requires UsageWindow"))
+	}
+	fail(c.UsagePrivacy.validate())
+	if c.MaxCostCenters < 0 {
+		fail(errors.New("MaxCostCenters cannot be negative"))
+	}
+	if c.KeyHistorySize < 0 {
+		fail(errors.New("KeyHistorySize cannot be negative"))
+	}
+	if c.KeyHistoryMaxKeys < 0 {
+		fail(errors.New("KeyHistoryMaxKeys cannot be negative"))
+	}
+
+	for i := range c.OverLimitAlerts {
+		fail(c.OverLimitAlerts[i].validate())
+	}
+	if len(c.OverLimitAlerts) != 0 && c.AlertNotifier == nil {
+		fail(errors.New("OverLimitAlerts requires AlertNotifier"))
+	}
+
+	fail(c.Federation.validate())
+	fail(c.Shadow.validate())
+	fail(c.Standby.validate())
+
+	for i := range c.NamespacePolicies {
+		fail(c.NamespacePolicies[i].validate())
+	}
+	templates := make(map[string]bool, len(c.Templates))
+	for i := range c.Templates {
+		if err := c.Templates[i].validate(); err != nil {
+			fail(err)
+			continue
+		}
+		if templates[c.Templates[i].Name] {
+			fail(errors.Errorf("Templates contains more than one template named '%s'", c.Templates[i].Name))
+		}
+		templates[c.Templates[i].Name] = true
+	}
+	if c.SnapshotInterval < 0 {
+		fail(errors.New("SnapshotInterval cannot be negative"))
+	}
+	if c.SnapshotInterval > 0 && c.Loader == nil {
+		fail(errors.New("SnapshotInterval requires Loader"))
+	}
+	if c.JournalRetention < 0 {
+		fail(errors.New("JournalRetention cannot be negative"))
+	}
+
+	if c.Faults != nil {
+		fail(c.Faults.validate())
+	}
+
+	if c.Behaviors.ResetJitterPercent < 0 || c.Behaviors.ResetJitterPercent >= 100 {
+		fail(errors.New("Behaviors.ResetJitterPercent must be between 0 and 99"))
+	}
+
+	if c.Behaviors.GracePercent < 0 || c.Behaviors.GracePercent > 100 {
+		fail(errors.New("Behaviors.GracePercent must be between 0 and 100"))
+	}
+	if c.Behaviors.DegradedErrorPercent < 0 || c.Behaviors.DegradedErrorPercent >= 100 {
+		fail(errors.New("Behaviors.DegradedErrorPercent must be between 0 and 99"))
+	}
+	if c.Behaviors.SlowPeerThreshold < 0 {
+		fail(errors.New("Behaviors.SlowPeerThreshold cannot be negative"))
+	}
+	if c.Behaviors.SlowRequestThreshold < 0 {
+		fail(errors.New("Behaviors.SlowRequestThreshold cannot be negative"))
+	}
+
+	if err := validateCompression(c.PeerCompression); err != nil {
+		fail(errors.Wrap(err, "PeerCompression"))
+	}
+	if c.PeerCompressionMinBytes < 0 {
+		fail(errors.New("PeerCompressionMinBytes cannot be negative"))
+	}
+
+	if err := validatePeerTransport(c.PeerTransport); err != nil {
+		fail(errors.Wrap(err, "PeerTransport"))
+	}
+	if c.PeerTransport == PeerTransportQUIC && c.PeerTLS == nil {
+		fail(errors.New("PeerTransport 'quic' requires PeerTLS"))
+	}
+	fail(validatePeerTLSOverrides(c))
+
+	if c.PeerAuth != nil {
+		fail(c.PeerAuth.validate())
+	}
+	if c.RequestValueSigning != nil {
+		fail(c.RequestValueSigning.validate())
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
 	return nil
 }
 
@@ -167,15 +776,33 @@ type PeerInfo struct {
 	GRPCAddress string `json:"grpc-address"`
 	// (Optional) Is true if PeerInfo is for this instance of gubernator
 	IsOwner bool `json:"is-owner,omitempty"`
+	// (Optional) The relative share of the key space owned by this peer when using the
+	// replicated hash picker. A peer with a weight of 4 owns 4 times as many keys as a
+	// peer with a weight of 1. Defaults to 1
+	Weight int `json:"weight,omitempty"`
+	// (Optional) The stable identity of the peer, see DaemonConfig.NodeID. If set, the peer is placed
+	// in the hash ring by its node ID instead of its GRPC address
+	NodeID string `json:"node-id,omitempty"`
+	// (Optional) Is true if the peer never owns rate limits, such that it joins the cluster to
+	// receive the peers and forwards every request to the owning peers, IE: an edge tier in a
+	// far region or behind a public load balancer. Forwarders are not added to the pickers.
+	Forwarder bool `json:"forwarder,omitempty"`
 }
 
-// HashKey returns the hash key used to identify this peer in the Picker.
+// HashKey returns the hash key used to identify this peer in the Picker, the node ID of the peer
+// if it has one, else its GRPC address.
 func (p PeerInfo) HashKey() string {
+	if p.NodeID != "" {
+		return p.NodeID
+	}
 	return p.GRPCAddress
 }
 
 type UpdateFunc func([]PeerInfo)
 
+// DeltaUpdateFunc is called with the peers which joined and the peers which left the cluster
+type DeltaUpdateFunc func(added, removed []PeerInfo)
+
 var DebugEnabled = false
 
 type DaemonConfig struct {
@@ -185,6 +812,16 @@ type DaemonConfig struct {
 	// (Required) The `address:port` that will accept HTTP requests
 	HTTPListenAddress string
 
+	// (Optional) A listener which accepts GRPC requests in place of listening on GRPCListenAddress,
+	// IE: a socket passed by systemd socket activation, a test server or a listener wrapped with a
+	// custom TLS implementation. GRPCListenAddress is replaced by the address of the listener, which
+	// is closed by Daemon.Close(). Cannot be used with GRPCListeners greater than 1
+	GRPCListener net.Listener
+
+	// (Optional) A listener which accepts HTTP requests in place of listening on HTTPListenAddress,
+	// the same as GRPCListener
+	HTTPListener net.Listener
+
 	// (Optional) The `address:port` that will accept HTTP requests for /v1/HealthCheck
 	// without verifying client certificates. Only starts listener when TLS config is provided.
 	// TLS config is identical to what is applied on HTTPListenAddress, except that server
@@ -196,17 +833,328 @@ type DaemonConfig struct {
 	// Default is infinity
 	GRPCMaxConnectionAgeSeconds int
 
+	// (Optional) The number of listeners bound to GRPCListenAddress with SO_REUSEPORT, each with its
+	// own accept loop and GRPC server sharing the same gubernator instance. The kernel spreads new
+	// connections across the listeners, which improves accept throughput on machines with a high
+	// connection rate. Not supported on Windows. Defaults to 1
+	GRPCListeners int
+
+	// (Optional) The max number of connections open on each of the GRPC and HTTP listeners. Once
+	// reached, new connections wait in the listen backlog until a connection closes. The GRPCListeners
+	// share a single limit. Default is no limit
+	MaxConnections int
+
+	// (Optional) The max number of connections open from a single remote IP on each of the GRPC and
+	// HTTP listeners, the GRPCListeners share a single limit. Additional connections from the IP are
+	// closed immediately. Default is no limit
+	MaxConnectionsPerIP int
+
+	// (Optional) The max number of concurrent streams, IE: in-flight RPCs, on each GRPC connection.
+	// Default is the GRPC default
+	GRPCMaxConcurrentStreams uint32
+
+	// (Optional) The max number of requests to the V1 service executing at once. Once reached, up to
+	// MaxQueuedRequests additional requests wait for a request to finish. Default is no limit
+	MaxInFlightRequests int
+
+	// (Optional) The max number of requests waiting for one of the MaxInFlightRequests to finish.
+	// Additional requests are rejected immediately with RESOURCE_EXHAUSTED. Queued requests are
+	// admitted fairly across namespaces, IE: the name of the first rate limit in the request, such
+	// that large batches from one namespace do not starve others. Defaults to 0 (no queue)
+	MaxQueuedRequests int
+
+	// (Optional) How long a queued request waits before it is rejected with RESOURCE_EXHAUSTED.
+	// Defaults to 1 second
+	QueueTimeout time.Duration
+
+	// (Optional) The max number of requests per second to the V1 service, additional requests are
+	// rejected immediately with RESOURCE_EXHAUSTED. Default is no limit
+	MaxRequestsPerSecond int
+
+	// (Optional) The max number of requests forwarded by peers to the PeersV1 service executing at
+	// once, additional requests are rejected immediately with RESOURCE_EXHAUSTED. Counted separately
+	// from MaxInFlightRequests, such that a storm of forwarded requests cannot starve the clients.
+	// Default is no limit
+	MaxInFlightPeerRequests int
+
+	// (Optional) The max number of requests per second to the PeersV1 service, additional requests
+	// are rejected immediately with RESOURCE_EXHAUSTED. Default is no limit
+	MaxPeerRequestsPerSecond int
+
+	// (Optional) The transformers applied to the responses of the V1 service for the clients which
+	// select each compat mode with CompatModeMetadataKey, applied in order. Such that legacy clients
+	// keep the field semantics they expect while the server is upgraded, see ParseCompatMode
+	CompatModes map[string][]ResponseTransformer
+
+	// (Optional) If true, gRPC-Web requests are served on HTTPListenAddress alongside the HTTP gateway.
+	// This allows browsers to call the GRPC API directly without a proxy.
+	GRPCWebEnabled bool
+
+	// (Optional) The origins allowed to make cross-origin gRPC-Web requests. Use '*' to allow any origin.
+	// If empty, only same-origin gRPC-Web requests are allowed.
+	GRPCWebAllowedOrigins []string
+
+	// (Optional) If true, a Swagger UI of the OpenAPI document served at `/openapi.json` is served at
+	// `/openapi/` on HTTPListenAddress. The Swagger UI assets are loaded by the browser from a CDN
+	OpenAPIUIEnabled bool
+
+	// (Optional) Find the unique key of the rate limits requested from `/v1/GetRateLimits` on the
+	// HTTP gateway without one in the HTTP request, such that clients need not construct keys. The
+	// key found by the first extractor which finds one is used, see KeyExtractor
+	HTTPKeyExtractors []KeyExtractor
+
+	// (Optional) The proxies whose X-Forwarded-For header is trusted by the KeyFromClientIP
+	// extractor. Requests from other addresses are keyed by the remote address of the connection
+	HTTPTrustedProxies []*net.IPNet
+
+	// (Optional) The GRPC service config served at `/v1/ServiceConfig` on HTTPListenAddress, such that
+	// clients and DNS TXT records may publish the recommended retry and timeout policies for each method.
+	// Defaults to DefaultServiceConfig
+	ServiceConfig string
+
 	// (Optional) The `address:port` that is advertised to other Gubernator peers.
 	// Defaults to `GRPCListenAddress`
 	AdvertiseAddress string
 
+	// (Optional) The weight advertised to other Gubernator peers via etcd or member-list discovery.
+	// Peers with a higher weight own proportionally more of the key space. Defaults to 1
+	PeerWeight int
+
+	// (Optional) The stable identity of this instance advertised to other Gubernator peers via etcd
+	// or member-list discovery, independent of AdvertiseAddress. Peers place the instance in the hash
+	// ring by its node ID instead of its address, as such an instance rescheduled with another address
+	// owns the same rate limits. Must be unique within the cluster. Defaults to the node ID stored in
+	// NodeIDFile, or none, in which case the instance is identified by its address
+	NodeID string
+
+	// (Optional) A file which holds the node ID of this instance. If the file does not exist a random
+	// node ID is generated and written to it, such that the instance keeps its node ID across restarts
+	// when the file is on a persistent volume. Ignored if NodeID is set
+	NodeIDFile string
+
+	// (Optional) Advertises this instance to other Gubernator peers via etcd or member-list discovery
+	// as a forwarder which never owns rate limits, see `PeerInfo.Forwarder`. Defaults to false
+	PeerForwarder bool
+
+	// (Optional) The CPU utilization of this instance, as a percentage of GOMAXPROCS, above which it
+	// advertises a reduced weight via etcd or member-list discovery, such that the peers move a share
+	// of the rate limits it owns to other peers during a hot spot. Requires a PeerWeight greater than
+	// 1, as the weight cannot be reduced below 1. Defaults to 0 which disables load shedding
+	LoadSheddingCPUPercent int
+
+	// (Optional) The percentage of PeerWeight advertised while shedding load. Defaults to 50
+	LoadSheddingWeightPercent int
+
+	// (Optional) How often the CPU utilization is measured, over the whole interval. Defaults to 10s
+	LoadSheddingInterval time.Duration
+
+	// (Optional) How long the CPU utilization must stay below LoadSheddingCPUPercent before the
+	// configured PeerWeight is advertised again. Defaults to 1m
+	LoadSheddingCooldown time.Duration
+
 	// (Optional) The number of items in the cache. Defaults to 50,000
 	CacheSize int
 
+	// (Optional) The GRPC compressor used for requests to other peers, either 'gzip' or 'snappy'.
+	// Defaults to no compression
+	PeerCompression string
+
+	// (Optional) Requests to other peers smaller than this number of bytes are not compressed, as
+	// compressing a small request costs more CPU than the bandwidth it saves. Only large batches of
+	// forwarded requests are compressed when set. Defaults to 0 (every request is compressed)
+	PeerCompressionMinBytes int
+
+	// (Optional) EXPERIMENTAL: The transport used for requests to other peers, either 'grpc' or
+	// 'quic'. Defaults to 'grpc'
+	PeerTransport string
+
+	// (Optional) Authenticates requests made to the PeersV1 service, set when
+	// `GUBER_PEER_AUTH_TOKEN` is provided
+	PeerAuth *PeerAuthConfig
+
+	// (Optional) Signs the request values forwarded to the owning peer, set when
+	// `GUBER_REQUEST_VALUE_SIGNING_KEY` is provided
+	RequestValueSigning *RequestValueSigningConfig
+
+	// (Optional) The minimum number of peers discovered before the instance reports ready. Defaults to 0
+	ReadyMinPeers int
+
+	// (Optional) The minimum percentage of the cluster size reported by peer discovery which must
+	// be discovered before the instance reports ready. Requires kubernetes discovery, which reports
+	// the cluster size. Defaults to 0
+	ReadyMinPeersPercent int
+
+	// (Optional) If true, GetRateLimits returns UNAVAILABLE until the instance is ready
+	UnavailableUntilReady bool
+
+	// (Optional) The timeout of the requests made without a deadline, see Config.DefaultRequestTimeout
+	DefaultRequestTimeout time.Duration
+
+	// (Optional) Fault injection config, set when any `GUBER_FAULT_*` variable is provided
+	Faults *FaultConfig
+
+	// (Optional) If true, the AdminV1 service is available on the GRPC and HTTP gateway listeners
+	AdminEnabled bool
+
+	// (Optional) If true, requests may override which peer evaluates a rate limit with the FORCE_LOCAL
+	// and FORCE_PEER behaviors. Requires AdminEnabled. Defaults to false
+	ForwardingOverridesEnabled bool
+
+	// (Optional) If true, an operator dashboard showing cluster health, the peers, namespace usage and
+	// the hottest keys is served at `/ui/` on HTTPListenAddress. Requires AdminEnabled, namespace usage
+	// and the hottest keys also require UsageWindow. Defaults to false
+	UIEnabled bool
+
+	// (Optional) The path of a file which audit records are appended to as lines of JSON
+	AuditFile string
+
+	// (Optional) The URL which batches of audit records are POSTed to as a JSON array
+	AuditWebhookURL string
+
+	// (Optional) The length of the rolling window over which the usage of each rate limit name is tracked
+	UsageWindow time.Duration
+
+	// (Optional) The rate limits of a name which was not accessed within this duration are removed
+	NamespaceGCAfter time.Duration
+
+	// (Optional) How often the filter of the keys in the cache of every other peer is pulled, such
+	// that the hits of rate limits the owner has never seen are answered without forwarding them
+	ColdKeyFilterInterval time.Duration
+
+	// (Optional) The URL which the usage of the rate limits owned by this instance is POSTed to as JSON
+	UsageExportURL string
+
+	// (Optional) How often the usage is POSTed to UsageExportURL
+	UsageExportInterval time.Duration
+
+	// (Optional) Adds noise to, or rounds, the reported usage of each rate limit name
+	UsagePrivacy UsagePrivacyConfig
+
+	// (Optional) The number of distinct cost centers consumption is attributed to, see Config.MaxCostCenters
+	MaxCostCenters int
+
+	// (Optional) The number of recent hits recorded for each rate limit, see Config.KeyHistorySize
+	KeyHistorySize int
+
+	// (Optional) The max number of rate limits whose hits are recorded, see Config.KeyHistoryMaxKeys
+	KeyHistoryMaxKeys int
+
+	// (Optional) Notifies AlertWebhookURL or AlertSlackURL when the rate limits in a namespace are
+	// over the limit more often than a threshold, see OverLimitAlert
+	OverLimitAlerts []OverLimitAlert
+
+	// (Optional) Applied in order to each rate limit request before it is hashed and evaluated, see
+	// ParseNormalizer and ParseNameAlias
+	Normalizers []NormalizeFunc
+
+	// (Optional) Proxies the rate limits of designated namespaces to a home cluster. Uses the
+	// client TLS config to connect to the home cluster if TLS is set
+	Federation FederationConfig
+
+	// (Optional) Mirrors a percentage of the requests to a secondary cluster. Uses the client TLS
+	// config to connect to the secondary cluster if TLS is set
+	Shadow ShadowConfig
+
+	// (Optional) Replicates the rate limits of critical names to a standby peer before responding
+	Standby StandbyConfig
+
+	// (Optional) The URL which each AlertEvent is POSTed to as JSON
+	AlertWebhookURL string
+
+	// (Optional) The Slack incoming webhook URL which a message is posted to for each AlertEvent
+	AlertSlackURL string
+
+	// (Optional) The path of a file the cache is saved to when the instance is closed and loaded
+	// from when the instance starts, see NewSnapshotLoader
+	SnapshotFile string
+
+	// (Optional) How often the cache is saved to SnapshotFile while running. Defaults to 0 (only when closed)
+	SnapshotInterval time.Duration
+
+	// (Optional) The directory the hits applied by this instance are journaled to, see Config.JournalDir
+	JournalDir string
+
+	// (Optional) How long the journaled hits are kept. Defaults to 24 hours
+	JournalRetention time.Duration
+
+	// (Optional) The path of a SQLite database every change to a rate limit is written to, such that a
+	// single node deployment keeps its rate limits across restarts, see NewSQLiteStore
+	SQLiteStoreFile string
+
+	// (Optional) Caps what clients may request for the rate limits in a namespace, see NamespacePolicy
+	NamespacePolicies []NamespacePolicy
+
+	// (Optional) A YAML file of namespace policies which is reloaded when it changes on disk
+	PolicyFile string
+
+	// (Optional) Defines the limit, duration and algorithm of the rate limits with the name of a
+	// template, see RateLimitTemplate
+	Templates []RateLimitTemplate
+
+	// (Optional) The URL of a service which may override the decision of the rate limit algorithm,
+	// see DecisionCalloutConfig
+	DecisionCalloutURL string
+
+	// (Optional) The max time to wait for the decision callout. Defaults to 50ms
+	DecisionCalloutTimeout time.Duration
+
+	// (Optional) Only call the decision callout for these rate limit names. Defaults to every name
+	DecisionCalloutNames []string
+
+	// (Optional) The approximate max number of bytes used by the items in the cache. Defaults to 0 (no limit)
+	MaxCacheBytes int64
+
+	// (Optional) What happens when a new rate limit is requested while the cache is full of unexpired
+	// rate limits; 'evict-lru', 'reject', 'evict-oldest-reset' or 'spill'. Defaults to 'evict-lru'
+	CacheFullPolicy string
+
+	// (Optional) Rate limits whose name begins with the prefix of a class are held in a cache of their own
+	CacheClasses []CacheClass
+
+	// (Optional) Partitions the cache by the tenant before this separator in the rate limit name
+	CacheTenantSeparator string
+
+	// (Optional) The percentage of the cache a single tenant may use. Defaults to 10
+	CacheTenantPercent int
+
+	// (Optional) The percentage of the cache of individual tenants, which overrides CacheTenantPercent
+	CacheTenantPercents map[string]int
+
+	// (Optional) Indexes the rate limits in the cache by this hash of their key instead of the key
+	CacheKeyHash HashString64
+
+	// (Optional) What happens when a rate limit is requested with a different algorithm than it was
+	// created with; 'reset', 'translate' or 'reject'. Defaults to 'reset'
+	AlgorithmChangePolicy string
+
+	// (Optional) The max length in bytes of the name of a rate limit. Defaults to 0 (no limit)
+	MaxNameLength int
+
+	// (Optional) The max length in bytes of the unique key of a rate limit. Defaults to 0 (no limit)
+	MaxKeyLength int
+
+	// (Optional) What happens to a unique key longer than MaxKeyLength; 'reject' or 'hash'. Defaults to 'reject'
+	LongKeyPolicy string
+
+	// (Optional) How unique keys appear in logs and traces; 'hash', 'mask' or 'none'. Defaults to 'hash'
+	KeyRedaction string
+
 	// (Optional) The number of go routine workers used to process concurrent rate limit requests
 	// Defaults to the number of CPUs returned by runtime.NumCPU()
 	Workers int
 
+	// (Optional) The number of goroutines for each worker which evaluate the rate limits forwarded
+	// by other peers, see Config.FanOutWorkers. Defaults to 0
+	FanOutWorkers int
+
+	// (Optional) The number of forwarded rate limits which may wait for the FanOutWorkers of each
+	// worker. Defaults to 100
+	FanOutQueueSize int
+
+	// (Optional) If true, query only requests are evaluated against a copy of the rate limit
+	// instead of waiting for its worker. Defaults to false
+	LockFreeReads bool
+
 	// (Optional) Configure how behaviours behave
 	Behaviors BehaviorConfig
 
@@ -215,7 +1163,7 @@ type DaemonConfig struct {
 	DataCenter string
 
 	// (Optional) Which pool to use when discovering other Gubernator peers
-	//  Valid options are [etcd, k8s, member-list] (Defaults to 'member-list')
+	//  Valid options are [etcd, k8s, dns, aws, member-list] (Defaults to 'member-list')
 	PeerDiscoveryType string
 
 	// (Optional) Etcd configuration used for peer discovery
@@ -227,19 +1175,33 @@ type DaemonConfig struct {
 	// (Optional) DNS Configuration used for peer discovery
 	DNSPoolConf DNSPoolConfig
 
+	// (Optional) AWS configuration used for peer discovery
+	AWSPoolConf AWSPoolConfig
+
 	// (Optional) Member list configuration used for peer discovery
 	MemberListPoolConf MemberListPoolConfig
 
 	// (Optional) The PeerPicker as selected by `GUBER_PEER_PICKER`
 	Picker PeerPicker
 
+	// (Optional) The HashKeyFunc as selected by `GUBER_HASH_KEY`
+	HashKey HashKeyFunc
+
 	// (Optional) A Logger which implements the declared logger interface (typically *logrus.Entry)
 	Logger FieldLogger
 
+	// (Optional) Limits the warnings and errors logged with the same message, such that a storm of
+	// identical errors does not flood the logs, see LogSamplingConfig
+	LogSampling LogSamplingConfig
+
 	// (Optional) TLS Configuration; SpawnDaemon() will modify the passed TLS config in an
 	// attempt to build a complete TLS config if one is not provided.
 	TLS *TLSConfig
 
+	// (Optional) Replaces the server name or the CAs of the client TLS config when connecting to
+	// some of the peers, see PeerTLSOverride. Requires TLS
+	PeerTLSOverrides []PeerTLSOverride
+
 	// (Optional) Metrics Flags which enable or disable a collection of some metric types
 	MetricFlags MetricFlags
 
@@ -268,7 +1230,19 @@ func (d *DaemonConfig) ServerTLS() *tls.Config {
 // SetupDaemonConfig returns a DaemonConfig object that is the result of merging the lines
 // in the provided configFile and the environment variables. See `example.conf` for all available config options and their descriptions.
 func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfig, error) {
+	return setupDaemonConfig(logger, configFile, false)
+}
+
+// SetupFromEnv builds a DaemonConfig from the `GUBER_*` environment variables documented in
+// `example.conf`. Unlike SetupDaemonConfig, variables which cannot be parsed are errors instead
+// of being ignored. The error returned lists every invalid variable at once.
+func SetupFromEnv(logger *logrus.Logger) (DaemonConfig, error) {
+	return setupDaemonConfig(logger, nil, true)
+}
+
+func setupDaemonConfig(logger *logrus.Logger, configFile io.Reader, strict bool) (DaemonConfig, error) {
 	log := logrus.NewEntry(logger)
+	env := &envParser{log: log, strict: strict}
 	var conf DaemonConfig
 	var logLevel string
 	var logFormat string
@@ -291,11 +1265,11 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 		case "text":
 			logger.SetFormatter(&logrus.TextFormatter{})
 		default:
-			return conf, errors.New("GUBER_LOG_FORMAT is invalid; expected value is either json or text")
+			env.fail(errors.New("GUBER_LOG_FORMAT is invalid; expected value is either json or text"))
 		}
 	}
 
-	setter.SetDefault(&DebugEnabled, getEnvBool(log, "GUBER_DEBUG"))
+	setter.SetDefault(&DebugEnabled, getEnvBool(env, "GUBER_DEBUG"))
 	setter.SetDefault(&logLevel, os.Getenv("GUBER_LOG_LEVEL"))
 	if DebugEnabled {
 		logger.SetLevel(logrus.DebugLevel)
@@ -303,11 +1277,16 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 	} else if logLevel != "" {
 		logrusLogLevel, err := logrus.ParseLevel(logLevel)
 		if err != nil {
-			return conf, errors.Wrap(err, "invalid log level")
+			env.fail(errors.Wrap(err, "invalid log level"))
 		}
 
 		logger.SetLevel(logrusLogLevel)
 	}
+	setter.SetDefault(&conf.LogSampling.Burst, getEnvInteger(env, "GUBER_LOG_SAMPLING_BURST"))
+	setter.SetDefault(&conf.LogSampling.Interval, getEnvDuration(env, "GUBER_LOG_SAMPLING_INTERVAL"))
+	if err := conf.LogSampling.validate(); err != nil {
+		env.fail(err)
+	}
 
 	// Main config
 	setter.SetDefault(&conf.GRPCListenAddress, os.Getenv("GUBER_GRPC_ADDRESS"),
@@ -316,52 +1295,419 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 		fmt.Sprintf("%s:80", LocalHost()))
 	setter.SetDefault(&conf.InstanceID, GetInstanceID())
 	setter.SetDefault(&conf.HTTPStatusListenAddress, os.Getenv("GUBER_STATUS_HTTP_ADDRESS"), "")
-	setter.SetDefault(&conf.GRPCMaxConnectionAgeSeconds, getEnvInteger(log, "GUBER_GRPC_MAX_CONN_AGE_SEC"), 0)
-	setter.SetDefault(&conf.CacheSize, getEnvInteger(log, "GUBER_CACHE_SIZE"), 50_000)
-	setter.SetDefault(&conf.Workers, getEnvInteger(log, "GUBER_WORKER_COUNT"), 0)
+	setter.SetDefault(&conf.GRPCMaxConnectionAgeSeconds, getEnvInteger(env, "GUBER_GRPC_MAX_CONN_AGE_SEC"), 0)
+	setter.SetDefault(&conf.GRPCListeners, getEnvInteger(env, "GUBER_GRPC_LISTENERS"), 1)
+	if conf.GRPCListeners < 1 {
+		env.fail(errors.New("GUBER_GRPC_LISTENERS must be at least 1"))
+	}
+	setter.SetDefault(&conf.MaxConnections, getEnvInteger(env, "GUBER_MAX_CONNECTIONS"), 0)
+	setter.SetDefault(&conf.MaxConnectionsPerIP, getEnvInteger(env, "GUBER_MAX_CONNECTIONS_PER_IP"), 0)
+	if conf.MaxConnections < 0 || conf.MaxConnectionsPerIP < 0 {
+		env.fail(errors.New("GUBER_MAX_CONNECTIONS and GUBER_MAX_CONNECTIONS_PER_IP cannot be negative"))
+	}
+	if streams := getEnvInteger(env, "GUBER_GRPC_MAX_CONCURRENT_STREAMS"); streams < 0 {
+		env.fail(errors.New("GUBER_GRPC_MAX_CONCURRENT_STREAMS cannot be negative"))
+	} else {
+		setter.SetDefault(&conf.GRPCMaxConcurrentStreams, uint32(streams))
+	}
+	setter.SetDefault(&conf.MaxInFlightRequests, getEnvInteger(env, "GUBER_MAX_IN_FLIGHT_REQUESTS"))
+	setter.SetDefault(&conf.MaxQueuedRequests, getEnvInteger(env, "GUBER_MAX_QUEUED_REQUESTS"))
+	setter.SetDefault(&conf.QueueTimeout, getEnvDuration(env, "GUBER_QUEUE_TIMEOUT"), time.Second)
+	if conf.MaxInFlightRequests < 0 || conf.MaxQueuedRequests < 0 || conf.QueueTimeout < 0 {
+		env.fail(errors.New("GUBER_MAX_IN_FLIGHT_REQUESTS, GUBER_MAX_QUEUED_REQUESTS and GUBER_QUEUE_TIMEOUT cannot be negative"))
+	}
+	if conf.MaxQueuedRequests > 0 && conf.MaxInFlightRequests == 0 {
+		env.fail(errors.New("GUBER_MAX_QUEUED_REQUESTS requires GUBER_MAX_IN_FLIGHT_REQUESTS"))
+	}
+	setter.SetDefault(&conf.MaxRequestsPerSecond, getEnvInteger(env, "GUBER_MAX_REQUESTS_PER_SECOND"))
+	setter.SetDefault(&conf.MaxInFlightPeerRequests, getEnvInteger(env, "GUBER_MAX_IN_FLIGHT_PEER_REQUESTS"))
+	setter.SetDefault(&conf.MaxPeerRequestsPerSecond, getEnvInteger(env, "GUBER_MAX_PEER_REQUESTS_PER_SECOND"))
+	if conf.MaxRequestsPerSecond < 0 || conf.MaxInFlightPeerRequests < 0 || conf.MaxPeerRequestsPerSecond < 0 {
+		env.fail(errors.New("GUBER_MAX_REQUESTS_PER_SECOND, GUBER_MAX_IN_FLIGHT_PEER_REQUESTS and " +
+			"GUBER_MAX_PEER_REQUESTS_PER_SECOND cannot be negative"))
+	}
+	for _, v := range getEnvSlice("GUBER_COMPAT_MODES") {
+		mode, t, err := ParseCompatMode(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_COMPAT_MODES"))
+			continue
+		}
+		if conf.CompatModes == nil {
+			conf.CompatModes = make(map[string][]ResponseTransformer)
+		}
+		conf.CompatModes[mode] = append(conf.CompatModes[mode], t)
+	}
+	setter.SetDefault(&conf.GRPCWebEnabled, getEnvBool(env, "GUBER_GRPC_WEB_ENABLED"))
+	setter.SetDefault(&conf.GRPCWebAllowedOrigins, getEnvSlice("GUBER_GRPC_WEB_ALLOWED_ORIGINS"))
+	setter.SetDefault(&conf.OpenAPIUIEnabled, getEnvBool(env, "GUBER_OPENAPI_UI_ENABLED"))
+	for _, v := range getEnvSlice("GUBER_HTTP_KEY_EXTRACTORS") {
+		e, err := ParseKeyExtractor(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_HTTP_KEY_EXTRACTORS"))
+			continue
+		}
+		conf.HTTPKeyExtractors = append(conf.HTTPKeyExtractors, e)
+	}
+	for _, v := range getEnvSlice("GUBER_HTTP_TRUSTED_PROXIES") {
+		n, err := ParseTrustedProxy(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_HTTP_TRUSTED_PROXIES"))
+			continue
+		}
+		conf.HTTPTrustedProxies = append(conf.HTTPTrustedProxies, n)
+	}
+	if file := os.Getenv("GUBER_SERVICE_CONFIG_FILE"); file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			env.fail(errors.Wrap(err, "while reading GUBER_SERVICE_CONFIG_FILE"))
+		}
+		conf.ServiceConfig = string(b)
+	}
+	setter.SetDefault(&conf.ServiceConfig, DefaultServiceConfig)
+	if err := validateServiceConfig(conf.ServiceConfig); err != nil {
+		env.fail(errors.Wrap(err, "invalid GUBER_SERVICE_CONFIG_FILE"))
+	}
+	setter.SetDefault(&conf.CacheSize, getEnvInteger(env, "GUBER_CACHE_SIZE"), 50_000)
+	setter.SetDefault(&conf.MaxCacheBytes, int64(getEnvInteger(env, "GUBER_CACHE_MAX_BYTES")))
+	setter.SetDefault(&conf.LockFreeReads, getEnvBool(env, "GUBER_LOCK_FREE_READS"))
+	setter.SetDefault(&conf.CacheFullPolicy, os.Getenv("GUBER_CACHE_FULL_POLICY"))
+	for _, v := range getEnvSlice("GUBER_CACHE_CLASSES") {
+		c, err := ParseCacheClass(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_CACHE_CLASSES"))
+			continue
+		}
+		conf.CacheClasses = append(conf.CacheClasses, c)
+	}
+	setter.SetDefault(&conf.CacheTenantSeparator, os.Getenv("GUBER_CACHE_TENANT_SEPARATOR"))
+	setter.SetDefault(&conf.CacheTenantPercent, getEnvInteger(env, "GUBER_CACHE_TENANT_PERCENT"))
+	for _, v := range getEnvSlice("GUBER_CACHE_TENANT_PERCENTS") {
+		tenant, percent, err := ParseTenantPercent(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_CACHE_TENANT_PERCENTS"))
+			continue
+		}
+		if conf.CacheTenantPercents == nil {
+			conf.CacheTenantPercents = make(map[string]int)
+		}
+		conf.CacheTenantPercents[tenant] = percent
+	}
+	if hash := os.Getenv("GUBER_CACHE_KEY_HASH"); hash != "" {
+		hashFuncs := map[string]HashString64{
+			"xxhash": xxhash.ChecksumString64,
+			"fnv1a":  fnv1a.HashString64,
+			"fnv1":   fnv1.HashString64,
+		}
+		fn, ok := hashFuncs[hash]
+		if !ok {
+			env.fail(errors.Errorf("'GUBER_CACHE_KEY_HASH=%s' is invalid; choices are [%s]",
+				hash, validHash64Keys(hashFuncs)))
+		}
+		conf.CacheKeyHash = fn
+	}
+	setter.SetDefault(&conf.AlgorithmChangePolicy, os.Getenv("GUBER_ALGORITHM_CHANGE_POLICY"))
+	setter.SetDefault(&conf.MaxNameLength, getEnvInteger(env, "GUBER_MAX_NAME_LENGTH"))
+	setter.SetDefault(&conf.MaxKeyLength, getEnvInteger(env, "GUBER_MAX_KEY_LENGTH"))
+	setter.SetDefault(&conf.LongKeyPolicy, os.Getenv("GUBER_LONG_KEY_POLICY"))
+	setter.SetDefault(&conf.KeyRedaction, os.Getenv("GUBER_KEY_REDACTION"))
+	setter.SetDefault(&conf.Workers, getEnvInteger(env, "GUBER_WORKER_COUNT"), 0)
+	setter.SetDefault(&conf.FanOutWorkers, getEnvInteger(env, "GUBER_FANOUT_WORKERS"))
+	setter.SetDefault(&conf.FanOutQueueSize, getEnvInteger(env, "GUBER_FANOUT_QUEUE_SIZE"))
+	if conf.FanOutWorkers < 0 {
+		env.fail(errors.New("GUBER_FANOUT_WORKERS cannot be negative"))
+	}
+	setter.SetDefault(&conf.ReadyMinPeers, getEnvInteger(env, "GUBER_READY_MIN_PEERS"), 0)
+	setter.SetDefault(&conf.ReadyMinPeersPercent, getEnvInteger(env, "GUBER_READY_MIN_PEERS_PERCENT"), 0)
+	if conf.ReadyMinPeersPercent < 0 || conf.ReadyMinPeersPercent > 100 {
+		env.fail(errors.New("GUBER_READY_MIN_PEERS_PERCENT must be between 0 and 100"))
+	}
+	setter.SetDefault(&conf.UnavailableUntilReady, getEnvBool(env, "GUBER_UNAVAILABLE_UNTIL_READY"))
+	setter.SetDefault(&conf.DefaultRequestTimeout, getEnvDuration(env, "GUBER_DEFAULT_REQUEST_TIMEOUT"))
+	setter.SetDefault(&conf.AdminEnabled, getEnvBool(env, "GUBER_ADMIN_ENABLED"))
+	setter.SetDefault(&conf.UIEnabled, getEnvBool(env, "GUBER_UI_ENABLED"))
+	if conf.UIEnabled && !conf.AdminEnabled {
+		env.fail(errors.New("GUBER_UI_ENABLED requires GUBER_ADMIN_ENABLED"))
+	}
+	setter.SetDefault(&conf.ForwardingOverridesEnabled, getEnvBool(env, "GUBER_FORWARDING_OVERRIDES_ENABLED"))
+	if conf.ForwardingOverridesEnabled && !conf.AdminEnabled {
+		env.fail(errors.New("GUBER_FORWARDING_OVERRIDES_ENABLED requires GUBER_ADMIN_ENABLED"))
+	}
+	setter.SetDefault(&conf.AuditFile, os.Getenv("GUBER_AUDIT_FILE"))
+	setter.SetDefault(&conf.AuditWebhookURL, os.Getenv("GUBER_AUDIT_WEBHOOK_URL"))
+	if conf.AuditFile != "" && conf.AuditWebhookURL != "" {
+		env.fail(errors.New("only one of GUBER_AUDIT_FILE or GUBER_AUDIT_WEBHOOK_URL may be provided"))
+	}
+	setter.SetDefault(&conf.UsageWindow, getEnvDuration(env, "GUBER_USAGE_WINDOW"))
+	setter.SetDefault(&conf.UsageExportURL, os.Getenv("GUBER_USAGE_EXPORT_URL"))
+	setter.SetDefault(&conf.UsageExportInterval, getEnvDuration(env, "GUBER_USAGE_EXPORT_INTERVAL"))
+	if conf.UsageExportURL != "" && conf.UsageWindow <= 0 {
+		env.fail(errors.New("GUBER_USAGE_EXPORT_URL requires GUBER_USAGE_WINDOW"))
+	}
+	setter.SetDefault(&conf.UsagePrivacy.NoisePercent, getEnvInteger(env, "GUBER_USAGE_NOISE_PERCENT"))
+	setter.SetDefault(&conf.UsagePrivacy.Bucket, int64(getEnvInteger(env, "GUBER_USAGE_BUCKET")))
+	if err := conf.UsagePrivacy.validate(); err != nil {
+		env.fail(err)
+	}
+	setter.SetDefault(&conf.MaxCostCenters, getEnvInteger(env, "GUBER_MAX_COST_CENTERS"), 0)
+	if conf.MaxCostCenters < 0 {
+		env.fail(errors.New("GUBER_MAX_COST_CENTERS cannot be negative"))
+	}
+	setter.SetDefault(&conf.KeyHistorySize, getEnvInteger(env, "GUBER_KEY_HISTORY_SIZE"))
+	setter.SetDefault(&conf.KeyHistoryMaxKeys, getEnvInteger(env, "GUBER_KEY_HISTORY_MAX_KEYS"))
+	setter.SetDefault(&conf.NamespaceGCAfter, getEnvDuration(env, "GUBER_NAMESPACE_GC_AFTER"))
+	if conf.NamespaceGCAfter < 0 {
+		env.fail(errors.New("GUBER_NAMESPACE_GC_AFTER cannot be negative"))
+	}
+	setter.SetDefault(&conf.ColdKeyFilterInterval, getEnvDuration(env, "GUBER_COLD_KEY_FILTER_INTERVAL"))
+	if conf.ColdKeyFilterInterval < 0 {
+		env.fail(errors.New("GUBER_COLD_KEY_FILTER_INTERVAL cannot be negative"))
+	}
+	for _, v := range getEnvSlice("GUBER_OVER_LIMIT_ALERTS") {
+		a, err := ParseOverLimitAlert(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_OVER_LIMIT_ALERTS"))
+			continue
+		}
+		conf.OverLimitAlerts = append(conf.OverLimitAlerts, a)
+	}
+	setter.SetDefault(&conf.AlertWebhookURL, os.Getenv("GUBER_ALERT_WEBHOOK_URL"))
+	setter.SetDefault(&conf.AlertSlackURL, os.Getenv("GUBER_ALERT_SLACK_URL"))
+	if conf.AlertWebhookURL != "" && conf.AlertSlackURL != "" {
+		env.fail(errors.New("only one of GUBER_ALERT_WEBHOOK_URL or GUBER_ALERT_SLACK_URL may be provided"))
+	}
+	if len(conf.OverLimitAlerts) != 0 && conf.AlertWebhookURL == "" && conf.AlertSlackURL == "" {
+		env.fail(errors.New("GUBER_OVER_LIMIT_ALERTS requires GUBER_ALERT_WEBHOOK_URL or GUBER_ALERT_SLACK_URL"))
+	}
+	setter.SetDefault(&conf.Federation.HomeAddress, os.Getenv("GUBER_FEDERATION_HOME_ADDRESS"))
+	setter.SetDefault(&conf.Federation.Prefixes, getEnvSlice("GUBER_FEDERATION_PREFIXES"))
+	setter.SetDefault(&conf.Federation.Timeout, getEnvDuration(env, "GUBER_FEDERATION_TIMEOUT"))
+	if conf.Federation.HomeAddress != "" && len(conf.Federation.Prefixes) == 0 {
+		env.fail(errors.New("GUBER_FEDERATION_HOME_ADDRESS requires GUBER_FEDERATION_PREFIXES"))
+	}
+	setter.SetDefault(&conf.Shadow.Address, os.Getenv("GUBER_SHADOW_ADDRESS"))
+	setter.SetDefault(&conf.Shadow.Percent, getEnvInteger(env, "GUBER_SHADOW_PERCENT"))
+	setter.SetDefault(&conf.Shadow.Timeout, getEnvDuration(env, "GUBER_SHADOW_TIMEOUT"))
+	setter.SetDefault(&conf.Shadow.MaxInFlight, getEnvInteger(env, "GUBER_SHADOW_MAX_IN_FLIGHT"))
+	setter.SetDefault(&conf.Standby.Names, getEnvSlice("GUBER_STANDBY_NAMES"))
+	setter.SetDefault(&conf.Standby.Timeout, getEnvDuration(env, "GUBER_STANDBY_TIMEOUT"))
+	for _, v := range getEnvSlice("GUBER_NORMALIZE") {
+		n, err := ParseNormalizer(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_NORMALIZE"))
+			continue
+		}
+		conf.Normalizers = append(conf.Normalizers, n)
+	}
+	if aliases := getEnvSlice("GUBER_NAME_ALIASES"); len(aliases) != 0 {
+		names := make(map[string]string, len(aliases))
+		for _, v := range aliases {
+			alias, name, err := ParseNameAlias(v)
+			if err != nil {
+				env.fail(errors.Wrap(err, "invalid GUBER_NAME_ALIASES"))
+				continue
+			}
+			names[alias] = name
+		}
+		conf.Normalizers = append(conf.Normalizers, NewNameAliases(names))
+	}
+	for _, v := range getEnvSlice("GUBER_NAMESPACE_POLICIES") {
+		p, err := ParseNamespacePolicy(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_NAMESPACE_POLICIES"))
+			continue
+		}
+		conf.NamespacePolicies = append(conf.NamespacePolicies, p)
+	}
+	setter.SetDefault(&conf.PolicyFile, os.Getenv("GUBER_POLICY_FILE"))
+	for _, v := range getEnvSlice("GUBER_RATE_LIMIT_TEMPLATES") {
+		t, err := ParseRateLimitTemplate(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_RATE_LIMIT_TEMPLATES"))
+			continue
+		}
+		conf.Templates = append(conf.Templates, t)
+	}
+	if v := os.Getenv("GUBER_RATE_LIMIT_SCHEDULES"); v != "" {
+		for _, s := range strings.Split(v, ";") {
+			name, ls, err := ParseLimitSchedule(s)
+			if err != nil {
+				env.fail(errors.Wrap(err, "invalid GUBER_RATE_LIMIT_SCHEDULES"))
+				continue
+			}
+			found := false
+			for i := range conf.Templates {
+				if conf.Templates[i].Name == name {
+					conf.Templates[i].Schedules = append(conf.Templates[i].Schedules, ls)
+					found = true
+				}
+			}
+			if !found {
+				env.fail(errors.Errorf("invalid GUBER_RATE_LIMIT_SCHEDULES; no template named '%s' in GUBER_RATE_LIMIT_TEMPLATES", name))
+			}
+		}
+	}
+	setter.SetDefault(&conf.SnapshotFile, os.Getenv("GUBER_SNAPSHOT_FILE"))
+	setter.SetDefault(&conf.SnapshotInterval, getEnvDuration(env, "GUBER_SNAPSHOT_INTERVAL"))
+	if conf.SnapshotInterval != 0 && conf.SnapshotFile == "" {
+		env.fail(errors.New("GUBER_SNAPSHOT_INTERVAL requires GUBER_SNAPSHOT_FILE"))
+	}
+	setter.SetDefault(&conf.JournalDir, os.Getenv("GUBER_JOURNAL_DIR"))
+	setter.SetDefault(&conf.JournalRetention, getEnvDuration(env, "GUBER_JOURNAL_RETENTION"))
+	if conf.JournalRetention < 0 {
+		env.fail(errors.New("GUBER_JOURNAL_RETENTION cannot be negative"))
+	}
+	setter.SetDefault(&conf.SQLiteStoreFile, os.Getenv("GUBER_SQLITE_STORE_FILE"))
+	setter.SetDefault(&conf.DecisionCalloutURL, os.Getenv("GUBER_DECISION_CALLOUT_URL"))
+	setter.SetDefault(&conf.DecisionCalloutTimeout, getEnvDuration(env, "GUBER_DECISION_CALLOUT_TIMEOUT"))
+	setter.SetDefault(&conf.DecisionCalloutNames, getEnvSlice("GUBER_DECISION_CALLOUT_NAMES"))
 	setter.SetDefault(&conf.AdvertiseAddress, os.Getenv("GUBER_ADVERTISE_ADDRESS"), conf.GRPCListenAddress)
 	setter.SetDefault(&conf.DataCenter, os.Getenv("GUBER_DATA_CENTER"), "")
-	setter.SetDefault(&conf.MetricFlags, getEnvMetricFlags(log, "GUBER_METRIC_FLAGS"))
+	setter.SetDefault(&conf.PeerCompression, os.Getenv("GUBER_PEER_COMPRESSION"))
+	if err := validateCompression(conf.PeerCompression); err != nil {
+		env.fail(errors.Wrap(err, "GUBER_PEER_COMPRESSION"))
+	}
+	setter.SetDefault(&conf.PeerCompressionMinBytes, getEnvInteger(env, "GUBER_PEER_COMPRESSION_MIN_BYTES"))
+	setter.SetDefault(&conf.PeerTransport, os.Getenv("GUBER_PEER_TRANSPORT"))
+	if err := validatePeerTransport(conf.PeerTransport); err != nil {
+		env.fail(errors.Wrap(err, "GUBER_PEER_TRANSPORT"))
+	}
+	if token := os.Getenv("GUBER_PEER_AUTH_TOKEN"); token != "" {
+		conf.PeerAuth = &PeerAuthConfig{
+			Token:        token,
+			AcceptTokens: getEnvSlice("GUBER_PEER_AUTH_ACCEPT_TOKENS"),
+		}
+		if err := conf.PeerAuth.validate(); err != nil {
+			env.fail(errors.Wrap(err, "GUBER_PEER_AUTH_ACCEPT_TOKENS"))
+		}
+	} else if os.Getenv("GUBER_PEER_AUTH_ACCEPT_TOKENS") != "" {
+		env.fail(errors.New("GUBER_PEER_AUTH_ACCEPT_TOKENS requires GUBER_PEER_AUTH_TOKEN"))
+	}
+	if key := os.Getenv("GUBER_REQUEST_VALUE_SIGNING_KEY"); key != "" {
+		conf.RequestValueSigning = &RequestValueSigningConfig{
+			Key:        key,
+			AcceptKeys: getEnvSlice("GUBER_REQUEST_VALUE_ACCEPT_KEYS"),
+		}
+		if err := conf.RequestValueSigning.validate(); err != nil {
+			env.fail(errors.Wrap(err, "GUBER_REQUEST_VALUE_ACCEPT_KEYS"))
+		}
+	} else if os.Getenv("GUBER_REQUEST_VALUE_ACCEPT_KEYS") != "" {
+		env.fail(errors.New("GUBER_REQUEST_VALUE_ACCEPT_KEYS requires GUBER_REQUEST_VALUE_SIGNING_KEY"))
+	}
+	setter.SetDefault(&conf.PeerWeight, getEnvInteger(env, "GUBER_PEER_WEIGHT"), 1)
+	setter.SetDefault(&conf.NodeID, os.Getenv("GUBER_NODE_ID"))
+	setter.SetDefault(&conf.NodeIDFile, os.Getenv("GUBER_NODE_ID_FILE"))
+	if conf.PeerWeight < 1 {
+		env.fail(errors.New("GUBER_PEER_WEIGHT must be greater than 0"))
+	}
+	setter.SetDefault(&conf.PeerForwarder, getEnvBool(env, "GUBER_PEER_FORWARDER"))
+	setter.SetDefault(&conf.MetricFlags, getEnvMetricFlags(env, "GUBER_METRIC_FLAGS"))
 
-	choices := []string{"member-list", "k8s", "etcd", "dns"}
+	choices := []string{"member-list", "k8s", "etcd", "dns", "aws"}
 	setter.SetDefault(&conf.PeerDiscoveryType, os.Getenv("GUBER_PEER_DISCOVERY_TYPE"), "member-list")
 	if !slice.ContainsString(conf.PeerDiscoveryType, choices, nil) {
-		return conf, fmt.Errorf("GUBER_PEER_DISCOVERY_TYPE is invalid; choices are [%s]`", strings.Join(choices, ","))
+		env.fail(fmt.Errorf("GUBER_PEER_DISCOVERY_TYPE is invalid; choices are [%s]`", strings.Join(choices, ",")))
+	}
+	if conf.ReadyMinPeersPercent != 0 && conf.PeerDiscoveryType != "k8s" {
+		env.fail(errors.New("GUBER_READY_MIN_PEERS_PERCENT requires GUBER_PEER_DISCOVERY_TYPE=k8s"))
+	}
+
+	setter.SetDefault(&conf.LoadSheddingCPUPercent, getEnvInteger(env, "GUBER_LOAD_SHEDDING_CPU_PERCENT"))
+	setter.SetDefault(&conf.LoadSheddingWeightPercent, getEnvInteger(env, "GUBER_LOAD_SHEDDING_WEIGHT_PERCENT"), 50)
+	setter.SetDefault(&conf.LoadSheddingInterval, getEnvDuration(env, "GUBER_LOAD_SHEDDING_INTERVAL"), clock.Second*10)
+	setter.SetDefault(&conf.LoadSheddingCooldown, getEnvDuration(env, "GUBER_LOAD_SHEDDING_COOLDOWN"), clock.Minute)
+	if conf.LoadSheddingCPUPercent != 0 {
+		if conf.LoadSheddingCPUPercent < 0 || conf.LoadSheddingCPUPercent > 100 {
+			env.fail(errors.New("GUBER_LOAD_SHEDDING_CPU_PERCENT must be between 1 and 100"))
+		}
+		if conf.LoadSheddingWeightPercent < 1 || conf.LoadSheddingWeightPercent > 99 {
+			env.fail(errors.New("GUBER_LOAD_SHEDDING_WEIGHT_PERCENT must be between 1 and 99"))
+		}
+		if shedWeight(conf) >= conf.PeerWeight {
+			env.fail(errors.New("GUBER_LOAD_SHEDDING_CPU_PERCENT requires a GUBER_PEER_WEIGHT which " +
+				"GUBER_LOAD_SHEDDING_WEIGHT_PERCENT reduces, IE: GUBER_PEER_WEIGHT=4"))
+		}
+		if conf.PeerDiscoveryType != "etcd" && conf.PeerDiscoveryType != "member-list" {
+			env.fail(errors.New("GUBER_LOAD_SHEDDING_CPU_PERCENT requires GUBER_PEER_DISCOVERY_TYPE=etcd or member-list"))
+		}
 	}
 
 	// AdvertiseAddress is not used in k8s discovery method. Skip processing and auto-discovery
 	if conf.PeerDiscoveryType != "k8s" {
 		advAddr, advPort, err = net.SplitHostPort(conf.AdvertiseAddress)
 		if err != nil {
-			return conf, errors.Wrap(err, "GUBER_ADVERTISE_ADDRESS is invalid; expected format is `address:port`")
+			env.fail(errors.Wrap(err, "GUBER_ADVERTISE_ADDRESS is invalid; expected format is `address:port`"))
 		}
 		advAddr, err = ResolveHostIP(advAddr)
 		if err != nil {
-			return conf, errors.Wrap(err, "failed to discover host ip for GUBER_ADVERTISE_ADDRESS")
+			env.fail(errors.Wrap(err, "failed to discover host ip for GUBER_ADVERTISE_ADDRESS"))
 		}
 		conf.AdvertiseAddress = net.JoinHostPort(advAddr, advPort)
+		if advPort == "0" {
+			env.fail(errors.New("GUBER_ADVERTISE_ADDRESS cannot have port 0, as peers could not connect to it; " +
+				"set GUBER_ADVERTISE_ADDRESS to the address and port of GUBER_GRPC_ADDRESS which peers can reach"))
+		}
+		// Peers discovered through etcd, DNS or AWS are expected to run on other hosts
+		if ip := net.ParseIP(advAddr); (advAddr == "localhost" || (ip != nil && ip.IsLoopback())) &&
+			slice.ContainsString(conf.PeerDiscoveryType, []string{"etcd", "dns", "aws"}, nil) {
+			log.Warnf("GUBER_ADVERTISE_ADDRESS '%s' is a loopback address, peers on other hosts cannot "+
+				"reach this instance; set GUBER_ADVERTISE_ADDRESS to an address they can reach", conf.AdvertiseAddress)
+		}
 	}
 
 	// Behaviors
-	setter.SetDefault(&conf.Behaviors.BatchTimeout, getEnvDuration(log, "GUBER_BATCH_TIMEOUT"))
-	setter.SetDefault(&conf.Behaviors.BatchLimit, getEnvInteger(log, "GUBER_BATCH_LIMIT"))
-	setter.SetDefault(&conf.Behaviors.BatchWait, getEnvDuration(log, "GUBER_BATCH_WAIT"))
-	setter.SetDefault(&conf.Behaviors.DisableBatching, getEnvBool(log, "GUBER_DISABLE_BATCHING"))
-
-	setter.SetDefault(&conf.Behaviors.GlobalTimeout, getEnvDuration(log, "GUBER_GLOBAL_TIMEOUT"))
-	setter.SetDefault(&conf.Behaviors.GlobalBatchLimit, getEnvInteger(log, "GUBER_GLOBAL_BATCH_LIMIT"))
-	setter.SetDefault(&conf.Behaviors.GlobalSyncWait, getEnvDuration(log, "GUBER_GLOBAL_SYNC_WAIT"))
-	setter.SetDefault(&conf.Behaviors.ForceGlobal, getEnvBool(log, "GUBER_FORCE_GLOBAL"))
+	setter.SetDefault(&conf.Behaviors.BatchTimeout, getEnvDuration(env, "GUBER_BATCH_TIMEOUT"))
+	setter.SetDefault(&conf.Behaviors.BatchLimit, getEnvInteger(env, "GUBER_BATCH_LIMIT"))
+	setter.SetDefault(&conf.Behaviors.BatchWait, getEnvDuration(env, "GUBER_BATCH_WAIT"))
+	setter.SetDefault(&conf.Behaviors.BatchQueueLimit, getEnvInteger(env, "GUBER_BATCH_QUEUE_LIMIT"))
+	setter.SetDefault(&conf.Behaviors.BatchQueueWait, getEnvDuration(env, "GUBER_BATCH_QUEUE_WAIT"))
+	setter.SetDefault(&conf.Behaviors.BatchOverflowPolicy, os.Getenv("GUBER_BATCH_OVERFLOW_POLICY"))
+	if conf.Behaviors.BatchOverflowPolicy != "" {
+		if err := validateBatchOverflowPolicy(conf.Behaviors.BatchOverflowPolicy); err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_BATCH_OVERFLOW_POLICY"))
+		}
+	}
+	setter.SetDefault(&conf.Behaviors.PeerReconnectBaseDelay, getEnvDuration(env, "GUBER_PEER_RECONNECT_BASE_DELAY"))
+	setter.SetDefault(&conf.Behaviors.PeerReconnectMaxDelay, getEnvDuration(env, "GUBER_PEER_RECONNECT_MAX_DELAY"))
+	setter.SetDefault(&conf.Behaviors.DisableBatching, getEnvBool(env, "GUBER_DISABLE_BATCHING"))
+	setter.SetDefault(&conf.Behaviors.CoalesceRequests, getEnvBool(env, "GUBER_COALESCE_REQUESTS"))
+
+	setter.SetDefault(&conf.Behaviors.GlobalTimeout, getEnvDuration(env, "GUBER_GLOBAL_TIMEOUT"))
+	setter.SetDefault(&conf.Behaviors.GlobalBatchLimit, getEnvInteger(env, "GUBER_GLOBAL_BATCH_LIMIT"))
+	setter.SetDefault(&conf.Behaviors.GlobalSyncWait, getEnvDuration(env, "GUBER_GLOBAL_SYNC_WAIT"))
+	setter.SetDefault(&conf.Behaviors.ForceGlobal, getEnvBool(env, "GUBER_FORCE_GLOBAL"))
+	setter.SetDefault(&conf.Behaviors.DryRunNames, getEnvSlice("GUBER_DRY_RUN_NAMES"))
+	setter.SetDefault(&conf.Behaviors.GreedyRefillNames, getEnvSlice("GUBER_GREEDY_REFILL_NAMES"))
+	setter.SetDefault(&conf.Behaviors.VerifyPeerOwnership, getEnvBool(env, "GUBER_VERIFY_PEER_OWNERSHIP"))
+	setter.SetDefault(&conf.Behaviors.ResetJitterPercent, getEnvInteger(env, "GUBER_RESET_JITTER_PERCENT"))
+	setter.SetDefault(&conf.Behaviors.GracePercent, getEnvInteger(env, "GUBER_GRACE_PERCENT"))
+	setter.SetDefault(&conf.Behaviors.RefundWindow, getEnvDuration(env, "GUBER_REFUND_WINDOW"))
+	setter.SetDefault(&conf.Behaviors.IdempotencyWindow, getEnvDuration(env, "GUBER_IDEMPOTENCY_WINDOW"))
+	setter.SetDefault(&conf.Behaviors.OverLimitRetention, getEnvDuration(env, "GUBER_OVER_LIMIT_RETENTION"))
+	setter.SetDefault(&conf.Behaviors.DegradedErrorPercent, getEnvInteger(env, "GUBER_DEGRADED_ERROR_PERCENT"))
+	setter.SetDefault(&conf.Behaviors.DegradedWindow, getEnvDuration(env, "GUBER_DEGRADED_WINDOW"))
+	setter.SetDefault(&conf.Behaviors.SlowPeerThreshold, getEnvDuration(env, "GUBER_SLOW_PEER_THRESHOLD"))
+	setter.SetDefault(&conf.Behaviors.SlowRequestThreshold, getEnvDuration(env, "GUBER_SLOW_REQUEST_THRESHOLD"))
+
+	// Fault injection config
+	if anyHasPrefix("GUBER_FAULT_", os.Environ()) {
+		conf.Faults = &FaultConfig{}
+		setter.SetDefault(&conf.Faults.PeerLatency, getEnvDuration(env, "GUBER_FAULT_PEER_LATENCY"))
+		setter.SetDefault(&conf.Faults.PeerDropPercent, getEnvInteger(env, "GUBER_FAULT_PEER_DROP_PERCENT"))
+		setter.SetDefault(&conf.Faults.OwnershipFlapPercent, getEnvInteger(env, "GUBER_FAULT_OWNERSHIP_FLAP_PERCENT"))
+		if err := conf.Faults.validate(); err != nil {
+			env.fail(err)
+		}
+	}
 
 	// TLS Config
 	if anyHasPrefix("GUBER_TLS_", os.Environ()) {
 		conf.TLS = &TLSConfig{}
-		setter.SetDefault(&conf.TLS.CaFile, os.Getenv("GUBER_TLS_CA"))
-		setter.SetDefault(&conf.TLS.CaKeyFile, os.Getenv("GUBER_TLS_CA_KEY"))
-		setter.SetDefault(&conf.TLS.KeyFile, os.Getenv("GUBER_TLS_KEY"))
-		setter.SetDefault(&conf.TLS.CertFile, os.Getenv("GUBER_TLS_CERT"))
-		setter.SetDefault(&conf.TLS.AutoTLS, getEnvBool(log, "GUBER_TLS_AUTO"))
-		setter.SetDefault(&conf.TLS.MinVersion, getEnvMinVersion(log, "GUBER_TLS_MIN_VERSION"))
+		setter.SetDefault(&conf.TLS.CaFile, getEnvFile(env, "GUBER_TLS_CA"))
+		setter.SetDefault(&conf.TLS.CaKeyFile, getEnvFile(env, "GUBER_TLS_CA_KEY"))
+		setter.SetDefault(&conf.TLS.KeyFile, getEnvFile(env, "GUBER_TLS_KEY"))
+		setter.SetDefault(&conf.TLS.CertFile, getEnvFile(env, "GUBER_TLS_CERT"))
+		setter.SetDefault(&conf.TLS.AutoTLS, getEnvBool(env, "GUBER_TLS_AUTO"))
+		setter.SetDefault(&conf.TLS.MinVersion, getEnvMinVersion(env, "GUBER_TLS_MIN_VERSION"))
 
 		clientAuth := os.Getenv("GUBER_TLS_CLIENT_AUTH")
 		if clientAuth != "" {
@@ -373,32 +1719,53 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 			}
 			t, ok := clientAuthTypes[clientAuth]
 			if !ok {
-				return conf, errors.Errorf("'GUBER_TLS_CLIENT_AUTH=%s' is invalid; choices are [%s]",
-					clientAuth, validClientAuthTypes(clientAuthTypes))
+				env.fail(errors.Errorf("'GUBER_TLS_CLIENT_AUTH=%s' is invalid; choices are [%s]",
+					clientAuth, validClientAuthTypes(clientAuthTypes)))
 			}
 			conf.TLS.ClientAuth = t
 		}
-		setter.SetDefault(&conf.TLS.ClientAuthKeyFile, os.Getenv("GUBER_TLS_CLIENT_AUTH_KEY"))
-		setter.SetDefault(&conf.TLS.ClientAuthCertFile, os.Getenv("GUBER_TLS_CLIENT_AUTH_CERT"))
-		setter.SetDefault(&conf.TLS.ClientAuthCaFile, os.Getenv("GUBER_TLS_CLIENT_AUTH_CA_CERT"))
-		setter.SetDefault(&conf.TLS.InsecureSkipVerify, getEnvBool(log, "GUBER_TLS_INSECURE_SKIP_VERIFY"))
+		setter.SetDefault(&conf.TLS.ClientAuthKeyFile, getEnvFile(env, "GUBER_TLS_CLIENT_AUTH_KEY"))
+		setter.SetDefault(&conf.TLS.ClientAuthCertFile, getEnvFile(env, "GUBER_TLS_CLIENT_AUTH_CERT"))
+		setter.SetDefault(&conf.TLS.ClientAuthCaFile, getEnvFile(env, "GUBER_TLS_CLIENT_AUTH_CA_CERT"))
+		setter.SetDefault(&conf.TLS.InsecureSkipVerify, getEnvBool(env, "GUBER_TLS_INSECURE_SKIP_VERIFY"))
 		setter.SetDefault(&conf.TLS.ClientAuthServerName, os.Getenv("GUBER_TLS_CLIENT_AUTH_SERVER_NAME"))
+		for _, v := range getEnvSlice("GUBER_TLS_SNI_CERTS") {
+			f, err := ParseCertificateFiles(v)
+			if err != nil {
+				env.fail(errors.Wrap(err, "invalid GUBER_TLS_SNI_CERTS"))
+				continue
+			}
+			conf.TLS.SNICertificates = append(conf.TLS.SNICertificates, f)
+		}
+		setter.SetDefault(&conf.TLS.ReloadInterval, getEnvDuration(env, "GUBER_TLS_RELOAD_INTERVAL"))
+	}
+	for _, v := range getEnvSlice("GUBER_PEER_TLS_OVERRIDES") {
+		o, err := ParsePeerTLSOverride(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_PEER_TLS_OVERRIDES"))
+			continue
+		}
+		conf.PeerTLSOverrides = append(conf.PeerTLSOverrides, o)
 	}
 
 	// ETCD Config
 	setter.SetDefault(&conf.EtcdPoolConf.KeyPrefix, os.Getenv("GUBER_ETCD_KEY_PREFIX"), "/gubernator-peers")
 	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig, &etcd.Config{})
 	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig.Endpoints, getEnvSlice("GUBER_ETCD_ENDPOINTS"), []string{"localhost:2379"})
-	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig.DialTimeout, getEnvDuration(log, "GUBER_ETCD_DIAL_TIMEOUT"), clock.Second*5)
+	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig.DialTimeout, getEnvDuration(env, "GUBER_ETCD_DIAL_TIMEOUT"), clock.Second*5)
 	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig.Username, os.Getenv("GUBER_ETCD_USER"))
 	setter.SetDefault(&conf.EtcdPoolConf.EtcdConfig.Password, os.Getenv("GUBER_ETCD_PASSWORD"))
 	setter.SetDefault(&conf.EtcdPoolConf.Advertise.GRPCAddress, os.Getenv("GUBER_ETCD_ADVERTISE_ADDRESS"), conf.AdvertiseAddress)
 	setter.SetDefault(&conf.EtcdPoolConf.Advertise.DataCenter, os.Getenv("GUBER_ETCD_DATA_CENTER"), conf.DataCenter)
+	setter.SetDefault(&conf.EtcdPoolConf.Advertise.Weight, conf.PeerWeight)
+	setter.SetDefault(&conf.EtcdPoolConf.Advertise.Forwarder, conf.PeerForwarder)
 
 	setter.SetDefault(&conf.MemberListPoolConf.Advertise.GRPCAddress, os.Getenv("GUBER_MEMBERLIST_ADVERTISE_ADDRESS"), conf.AdvertiseAddress)
 	setter.SetDefault(&conf.MemberListPoolConf.MemberListAddress, os.Getenv("GUBER_MEMBERLIST_ADDRESS"), fmt.Sprintf("%s:7946", advAddr))
 	setter.SetDefault(&conf.MemberListPoolConf.KnownNodes, getEnvSlice("GUBER_MEMBERLIST_KNOWN_NODES"), []string{})
 	setter.SetDefault(&conf.MemberListPoolConf.Advertise.DataCenter, conf.DataCenter)
+	setter.SetDefault(&conf.MemberListPoolConf.Advertise.Weight, conf.PeerWeight)
+	setter.SetDefault(&conf.MemberListPoolConf.Advertise.Forwarder, conf.PeerForwarder)
 
 	// Kubernetes Config
 	setter.SetDefault(&conf.K8PoolConf.Namespace, os.Getenv("GUBER_K8S_NAMESPACE"), "default")
@@ -408,8 +1775,8 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 	var assignErr error
 	conf.K8PoolConf.Mechanism, assignErr = WatchMechanismFromString(os.Getenv("GUBER_K8S_WATCH_MECHANISM"))
 	if assignErr != nil {
-		return conf, errors.New("invalid value for watch mechanism " +
-			"`GUBER_K8S_WATCH_MECHANISM` needs to be either 'endpoints' or 'pods' (defaults to 'endpoints')")
+		env.fail(errors.New("invalid value for watch mechanism " +
+			"`GUBER_K8S_WATCH_MECHANISM` needs to be either 'endpoints' or 'pods' (defaults to 'endpoints')"))
 	}
 
 	// DNS Config
@@ -417,6 +1784,32 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 	setter.SetDefault(&conf.DNSPoolConf.ResolvConf, os.Getenv("GUBER_RESOLV_CONF"), "/etc/resolv.conf")
 	setter.SetDefault(&conf.DNSPoolConf.OwnAddress, conf.AdvertiseAddress)
 
+	// AWS Config
+	for _, v := range getEnvSlice("GUBER_AWS_TAGS") {
+		key, value, err := ParseAWSTag(v)
+		if err != nil {
+			env.fail(errors.Wrap(err, "invalid GUBER_AWS_TAGS"))
+			continue
+		}
+		if conf.AWSPoolConf.Tags == nil {
+			conf.AWSPoolConf.Tags = make(map[string]string)
+		}
+		conf.AWSPoolConf.Tags[key] = value
+	}
+	setter.SetDefault(&conf.AWSPoolConf.AutoScalingGroups, getEnvSlice("GUBER_AWS_AUTOSCALING_GROUPS"))
+	setter.SetDefault(&conf.AWSPoolConf.Region, os.Getenv("GUBER_AWS_REGION"))
+	setter.SetDefault(&conf.AWSPoolConf.RefreshInterval, getEnvDuration(env, "GUBER_AWS_REFRESH_INTERVAL"), clock.Second*30)
+	setter.SetDefault(&conf.AWSPoolConf.GRPCPort, os.Getenv("GUBER_AWS_GRPC_PORT"), advPort)
+	_, httpPort, _ := net.SplitHostPort(conf.HTTPListenAddress)
+	setter.SetDefault(&conf.AWSPoolConf.HTTPPort, os.Getenv("GUBER_AWS_HTTP_PORT"), httpPort)
+	setter.SetDefault(&conf.AWSPoolConf.OwnAddress, conf.AdvertiseAddress)
+	setter.SetDefault(&conf.AWSPoolConf.DataCenter, conf.DataCenter)
+	conf.AWSPoolConf.RefreshOnSignal = true
+	if conf.PeerDiscoveryType == "aws" && len(conf.AWSPoolConf.Tags) == 0 && len(conf.AWSPoolConf.AutoScalingGroups) == 0 {
+		env.fail(errors.New("when using `aws` for peer discovery, you MUST provide " +
+			"`GUBER_AWS_TAGS` or `GUBER_AWS_AUTOSCALING_GROUPS` to select the gubernator instances"))
+	}
+
 	// PeerPicker Config
 	if pp := os.Getenv("GUBER_PEER_PICKER"); pp != "" {
 		var replicas int
@@ -424,7 +1817,7 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 
 		switch pp {
 		case "replicated-hash":
-			setter.SetDefault(&replicas, getEnvInteger(log, "GUBER_REPLICATED_HASH_REPLICAS"), defaultReplicas)
+			setter.SetDefault(&replicas, getEnvInteger(env, "GUBER_REPLICATED_HASH_REPLICAS"), defaultReplicas)
 			conf.Picker = NewReplicatedConsistentHash(nil, replicas)
 			setter.SetDefault(&hash, os.Getenv("GUBER_PEER_PICKER_HASH"), "fnv1a")
 			hashFuncs := map[string]HashString64{
@@ -433,28 +1826,44 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 			}
 			fn, ok := hashFuncs[hash]
 			if !ok {
-				return conf, errors.Errorf("'GUBER_PEER_PICKER_HASH=%s' is invalid; choices are [%s]",
-					hash, validHash64Keys(hashFuncs))
+				env.fail(errors.Errorf("'GUBER_PEER_PICKER_HASH=%s' is invalid; choices are [%s]",
+					hash, validHash64Keys(hashFuncs)))
 			}
 			conf.Picker = NewReplicatedConsistentHash(fn, replicas)
 		default:
-			return conf, errors.Errorf("'GUBER_PEER_PICKER=%s' is invalid; choices are ['replicated-hash', 'consistent-hash']", pp)
+			env.fail(errors.Errorf("'GUBER_PEER_PICKER=%s' is invalid; choices are ['replicated-hash', 'consistent-hash']", pp))
 		}
 	}
 
+	// HashKey Config
+	switch hk := os.Getenv("GUBER_HASH_KEY"); hk {
+	case "", "legacy":
+		conf.HashKey = LegacyHashKey
+	case "delimited":
+		conf.HashKey = DelimitedHashKey
+	case "salted":
+		salt := os.Getenv("GUBER_HASH_KEY_SALT")
+		if salt == "" {
+			env.fail(errors.New("when using 'GUBER_HASH_KEY=salted', you MUST provide a `GUBER_HASH_KEY_SALT`"))
+		}
+		conf.HashKey = NewSaltedHashKey(func(string) []byte { return []byte(salt) })
+	default:
+		env.fail(errors.Errorf("'GUBER_HASH_KEY=%s' is invalid; choices are ['legacy', 'delimited', 'salted']", hk))
+	}
+
 	if anyHasPrefix("GUBER_K8S_", os.Environ()) {
 		log.Debug("K8s peer pool config found")
 		if conf.K8PoolConf.Selector == "" {
-			return conf, errors.New("when using k8s for peer discovery, you MUST provide a " +
-				"`GUBER_K8S_ENDPOINTS_SELECTOR` to select the gubernator peers from the endpoints listing")
+			env.fail(errors.New("when using k8s for peer discovery, you MUST provide a " +
+				"`GUBER_K8S_ENDPOINTS_SELECTOR` to select the gubernator peers from the endpoints listing"))
 		}
 	}
 
 	if anyHasPrefix("GUBER_MEMBERLIST_", os.Environ()) {
 		log.Debug("Memberlist pool config found")
 		if len(conf.MemberListPoolConf.KnownNodes) == 0 {
-			return conf, errors.New("when using `member-list` for peer discovery, you MUST provide a " +
-				"hostname of a known host in the cluster via `GUBER_MEMBERLIST_KNOWN_NODES`")
+			env.fail(errors.New("when using `member-list` for peer discovery, you MUST provide a " +
+				"hostname of a known host in the cluster via `GUBER_MEMBERLIST_KNOWN_NODES`"))
 		}
 	}
 
@@ -465,7 +1874,7 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 	// If env contains any TLS configuration
 	if anyHasPrefix("GUBER_ETCD_TLS_", os.Environ()) {
 		if err := setupEtcdTLS(conf.EtcdPoolConf.EtcdConfig); err != nil {
-			return conf, err
+			env.fail(err)
 		}
 	}
 
@@ -475,7 +1884,7 @@ func SetupDaemonConfig(logger *logrus.Logger, configFile io.Reader) (DaemonConfi
 
 	setter.SetDefault(&conf.TraceLevel, GetTracingLevel())
 
-	return conf, nil
+	return conf, env.err()
 }
 
 // LocalHost returns the local IPV interface which Gubernator should bind to by default.
@@ -564,20 +1973,50 @@ func anyHasPrefix(prefix string, items []string) bool {
 	return false
 }
 
-func getEnvBool(log logrus.FieldLogger, name string) bool {
+// envParser collects the errors found while parsing the environment, such that every invalid
+// variable is reported at once. Variables which cannot be parsed are only logged unless strict.
+type envParser struct {
+	log    logrus.FieldLogger
+	strict bool
+	errs   []string
+}
+
+// invalid records a variable which could not be parsed
+func (e *envParser) invalid(err error, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if e.strict {
+		e.errs = append(e.errs, fmt.Sprintf("%s: %s", msg, err))
+		return
+	}
+	e.log.WithError(err).Error(msg)
+}
+
+// fail records an invalid config
+func (e *envParser) fail(err error) {
+	e.errs = append(e.errs, err.Error())
+}
+
+func (e *envParser) err() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(e.errs, "; "))
+}
+
+func getEnvBool(env *envParser, name string) bool {
 	v := os.Getenv(name)
 	if v == "" {
 		return false
 	}
 	b, err := strconv.ParseBool(v)
 	if err != nil {
-		log.WithError(err).Errorf("while parsing '%s' as an boolean", name)
+		env.invalid(err, "while parsing '%s' as an boolean", name)
 		return false
 	}
 	return b
 }
 
-func getEnvMinVersion(log logrus.FieldLogger, name string) uint16 {
+func getEnvMinVersion(env *envParser, name string) uint16 {
 	v := os.Getenv(name)
 	if v == "" {
 		return tls.VersionTLS13
@@ -590,38 +2029,50 @@ func getEnvMinVersion(log logrus.FieldLogger, name string) uint16 {
 	}
 	version, ok := minVersion[v]
 	if !ok {
-		log.WithError(fmt.Errorf("unknown tls version: %s", v)).Errorf("while parsing '%s' as an min tls version, defaulting to 1.3", name)
+		env.invalid(fmt.Errorf("unknown tls version: %s", v), "while parsing '%s' as an min tls version, defaulting to 1.3", name)
 		return tls.VersionTLS13
 	}
 	return version
 }
 
-func getEnvInteger(log logrus.FieldLogger, name string) int {
+func getEnvInteger(env *envParser, name string) int {
 	v := os.Getenv(name)
 	if v == "" {
 		return 0
 	}
 	i, err := strconv.ParseInt(v, 10, 64)
 	if err != nil {
-		log.WithError(err).Errorf("while parsing '%s' as an integer", name)
+		env.invalid(err, "while parsing '%s' as an integer", name)
 		return 0
 	}
 	return int(i)
 }
 
-func getEnvDuration(log logrus.FieldLogger, name string) time.Duration {
+func getEnvDuration(env *envParser, name string) time.Duration {
 	v := os.Getenv(name)
 	if v == "" {
 		return 0
 	}
 	d, err := time.ParseDuration(v)
 	if err != nil {
-		log.WithError(err).Errorf("while parsing '%s' as a duration", name)
+		env.invalid(err, "while parsing '%s' as a duration", name)
 		return 0
 	}
 	return d
 }
 
+// getEnvFile returns the path in the variable. If strict, the file must exist.
+func getEnvFile(env *envParser, name string) string {
+	v := os.Getenv(name)
+	if v == "" || !env.strict {
+		return v
+	}
+	if _, err := os.Stat(v); err != nil {
+		env.invalid(err, "while checking the file in '%s'", name)
+	}
+	return v
+}
+
 func getEnvSlice(name string) []string {
 	v := os.Getenv(name)
 	if v == "" {
@@ -741,6 +2192,9 @@ var TraceLevelInfoFilter = otelgrpc.Filter(func(info *otelgrpc.InterceptorInfo)
 		if info.UnaryServerInfo.FullMethod == "/pb.gubernator.V1/HealthCheck" {
 			return false
 		}
+		if info.UnaryServerInfo.FullMethod == grpc_health_v1.Health_Check_FullMethodName {
+			return false
+		}
 	}
 	if info.Method == "/pb.gubernator.PeersV1/GetPeerRateLimits" {
 		return false
@@ -748,5 +2202,8 @@ var TraceLevelInfoFilter = otelgrpc.Filter(func(info *otelgrpc.InterceptorInfo)
 	if info.Method == "/pb.gubernator.V1/HealthCheck" {
 		return false
 	}
+	if info.Method == grpc_health_v1.Health_Check_FullMethodName {
+		return false
+	}
 	return true
 })
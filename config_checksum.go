@@ -0,0 +1,137 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+)
+
+// configSkewInterval is how often the config checksum of every peer is compared with our own
+const configSkewInterval = time.Minute
+
+// checksumConfig is the configuration which changes how rate limits are evaluated, such that peers
+// which disagree evaluate the same rate limit differently depending on which peer owns it. Settings
+// which only affect the instance itself, IE: the cache size, are left out.
+type checksumConfig struct {
+	Behaviors                  BehaviorConfig
+	Templates                  []RateLimitTemplate
+	AlgorithmChangePolicy      string
+	CacheFullPolicy            string
+	MaxNameLength              int
+	MaxKeyLength               int
+	LongKeyPolicy              string
+	ForwardingOverridesEnabled bool
+	PeerTransport              string
+	SignedRequestValues        bool
+	// The functions are compared by their result for a fixed request
+	NormalizedName string
+	NormalizedKey  string
+	HashKey        string
+}
+
+// newConfigChecksum returns the encoding of the configuration the checksum covers, other than the
+// namespace policies which may be reloaded from Config.PolicyFile
+func newConfigChecksum(conf *Config) []byte {
+	c := checksumConfig{
+		Behaviors:                  conf.Behaviors,
+		Templates:                  conf.Templates,
+		AlgorithmChangePolicy:      conf.AlgorithmChangePolicy,
+		CacheFullPolicy:            conf.CacheFullPolicy,
+		MaxNameLength:              conf.MaxNameLength,
+		MaxKeyLength:               conf.MaxKeyLength,
+		LongKeyPolicy:              conf.LongKeyPolicy,
+		ForwardingOverridesEnabled: conf.ForwardingOverridesEnabled,
+		PeerTransport:              conf.PeerTransport,
+		SignedRequestValues:        conf.RequestValueSigning != nil,
+	}
+	// The order of the names does not change the behavior
+	c.Behaviors.DryRunNames = sortedCopy(c.Behaviors.DryRunNames)
+	c.Behaviors.GreedyRefillNames = sortedCopy(c.Behaviors.GreedyRefillNames)
+
+	probe := &RateLimitReq{Name: " Checksum_Probe ", UniqueKey: " Account:1234 "}
+	for _, normalize := range conf.Normalizers {
+		normalize(probe)
+	}
+	c.NormalizedName, c.NormalizedKey = probe.Name, probe.UniqueKey
+	c.HashKey = conf.HashKey(probe)
+
+	b, _ := json.Marshal(c)
+	return b
+}
+
+func sortedCopy(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	c := make([]string, len(s))
+	copy(c, s)
+	sort.Strings(c)
+	return c
+}
+
+// configChecksum returns the checksum of the configuration which changes how this instance
+// evaluates rate limits, see HealthCheckResp.ConfigChecksum
+func (s *V1Instance) configChecksum() string {
+	h := sha256.New()
+	h.Write(s.checksumConfig)
+	policies, _ := json.Marshal(s.policies.Load().policies)
+	h.Write(policies)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// checkConfigSkew warns about the peers whose config checksum differs from our own
+func (s *V1Instance) checkConfigSkew(ctx context.Context) {
+	own := s.configChecksum()
+	var skewed []string
+	for _, v := range s.peerVersions(ctx) {
+		// Peers which cannot be reached or predate the checksum are not counted
+		if v.ConfigChecksum != "" && v.ConfigChecksum != own {
+			skewed = append(skewed, v.GrpcAddress)
+		}
+	}
+	metricConfigSkew.Set(float64(len(skewed)))
+	if len(skewed) != 0 {
+		s.log.WithField("config_checksum", own).
+			WithField("peers", skewed).
+			Warn("peers are configured to evaluate rate limits differently than this instance; " +
+				"rate limits are enforced inconsistently until the configuration of every peer matches")
+	}
+}
+
+// runConfigSkewCheck calls checkConfigSkew() every `configSkewInterval` until the instance is closed
+func (s *V1Instance) runConfigSkewCheck() {
+	tick := clock.NewTicker(configSkewInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			ctx, cancel := context.WithTimeout(context.Background(), configSkewInterval/2)
+			s.checkConfigSkew(ctx)
+			cancel()
+		case <-s.configSkewDone:
+			return
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package gubernator
 
 import (
+	"crypto/tls"
 	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/OneOfOne/xxhash"
 	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,3 +42,474 @@ func TestDefaultInstanceId(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, daemonConfig.InstanceID)
 }
+
+func TestHashKeyConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_HASH_KEY", "salted")
+	_, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+
+	_ = os.Setenv("GUBER_HASH_KEY_SALT", "secret")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.NotEqual(t, "name_key", daemonConfig.HashKey(&RateLimitReq{Name: "name", UniqueKey: "key"}))
+
+	_ = os.Setenv("GUBER_HASH_KEY", "unknown")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+	os.Clearenv()
+}
+
+func TestFaultConfig(t *testing.T) {
+	os.Clearenv()
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Nil(t, daemonConfig.Faults)
+
+	_ = os.Setenv("GUBER_FAULT_PEER_LATENCY", "50ms")
+	_ = os.Setenv("GUBER_FAULT_PEER_DROP_PERCENT", "10")
+	daemonConfig, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Equal(t, &FaultConfig{PeerLatency: 50 * time.Millisecond, PeerDropPercent: 10}, daemonConfig.Faults)
+
+	_ = os.Setenv("GUBER_FAULT_OWNERSHIP_FLAP_PERCENT", "101")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+	os.Clearenv()
+}
+
+func TestCacheTenantConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_CACHE_TENANT_SEPARATOR", ":")
+	_ = os.Setenv("GUBER_CACHE_TENANT_PERCENT", "20")
+	_ = os.Setenv("GUBER_CACHE_TENANT_PERCENTS", "acme=50, globex=5")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, ":", daemonConfig.CacheTenantSeparator)
+	assert.Equal(t, 20, daemonConfig.CacheTenantPercent)
+	assert.Equal(t, map[string]int{"acme": 50, "globex": 5}, daemonConfig.CacheTenantPercents)
+
+	_ = os.Setenv("GUBER_CACHE_TENANT_PERCENTS", "acme")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+
+	conf := Config{CacheTenantSeparator: ":", CacheTenantPercents: map[string]int{"acme": 101}}
+	require.Error(t, conf.SetDefaults())
+	os.Clearenv()
+}
+
+func TestCacheKeyHashConfig(t *testing.T) {
+	os.Clearenv()
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, daemonConfig.CacheKeyHash)
+
+	_ = os.Setenv("GUBER_CACHE_KEY_HASH", "xxhash")
+	daemonConfig, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.NotNil(t, daemonConfig.CacheKeyHash)
+	assert.Equal(t, xxhash.ChecksumString64("test_key"), daemonConfig.CacheKeyHash("test_key"))
+
+	_ = os.Setenv("GUBER_CACHE_KEY_HASH", "md5")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+	os.Clearenv()
+}
+
+func TestAWSPoolConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_PEER_DISCOVERY_TYPE", "aws")
+	_ = os.Setenv("GUBER_ADVERTISE_ADDRESS", "10.0.0.2:1051")
+	_ = os.Setenv("GUBER_AWS_TAGS", "gubernator-cluster=production, team=edge")
+	_ = os.Setenv("GUBER_AWS_AUTOSCALING_GROUPS", "gubernator-a,gubernator-b")
+	_ = os.Setenv("GUBER_AWS_REFRESH_INTERVAL", "10s")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"gubernator-cluster": "production", "team": "edge"}, daemonConfig.AWSPoolConf.Tags)
+	assert.Equal(t, []string{"gubernator-a", "gubernator-b"}, daemonConfig.AWSPoolConf.AutoScalingGroups)
+	assert.Equal(t, 10*time.Second, daemonConfig.AWSPoolConf.RefreshInterval)
+	assert.Equal(t, "1051", daemonConfig.AWSPoolConf.GRPCPort)
+	assert.Equal(t, "80", daemonConfig.AWSPoolConf.HTTPPort)
+	assert.Equal(t, "10.0.0.2:1051", daemonConfig.AWSPoolConf.OwnAddress)
+
+	_ = os.Setenv("GUBER_AWS_TAGS", "production")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+
+	os.Clearenv()
+	_ = os.Setenv("GUBER_PEER_DISCOVERY_TYPE", "aws")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+	os.Clearenv()
+}
+
+func TestNamespacePolicyConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_NAMESPACE_POLICIES", "public-api;max_limit=10000;max_duration=1h;algorithms=token_bucket;clamp,internal;max_duration=24h,login;ipv4_prefix=24;ipv6_prefix=64;strict")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []NamespacePolicy{
+		{
+			Prefix:      "public-api",
+			MaxLimit:    10000,
+			MaxDuration: time.Hour,
+			Algorithms:  []Algorithm{Algorithm_TOKEN_BUCKET},
+			Clamp:       true,
+		},
+		{Prefix: "internal", MaxDuration: 24 * time.Hour},
+		{Prefix: "login", IPv4Prefix: 24, IPv6Prefix: 64, Strict: true},
+	}, daemonConfig.NamespacePolicies)
+
+	for _, v := range []string{"public-api;max_limit=lots", "public-api;algorithms=fifo", "public-api;unknown", ";max_limit=1",
+		"login;ipv4_prefix=33", "login;ipv6_prefix=-1", "login;ipv6_prefix=big"} {
+		_ = os.Setenv("GUBER_NAMESPACE_POLICIES", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+	os.Clearenv()
+}
+
+func TestRateLimitTemplateConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_RATE_LIMIT_TEMPLATES", "per-user-login: 5 per 60s token_bucket,uploads: 100 per 1h leaky_bucket global burst=20")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []RateLimitTemplate{
+		{Name: "per-user-login", Limit: 5, Duration: time.Minute, Algorithm: Algorithm_TOKEN_BUCKET},
+		{
+			Name:      "uploads",
+			Limit:     100,
+			Duration:  time.Hour,
+			Algorithm: Algorithm_LEAKY_BUCKET,
+			Behavior:  Behavior_GLOBAL,
+			Burst:     20,
+		},
+	}, daemonConfig.Templates)
+
+	for _, v := range []string{"login 5 per 60s", "login: lots per 60s", "login: 5 per forever", "login: 5 each 60s", "login: 5 per 60s fifo", ": 5 per 60s"} {
+		_ = os.Setenv("GUBER_RATE_LIMIT_TEMPLATES", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+
+	_ = os.Setenv("GUBER_RATE_LIMIT_TEMPLATES", "api: 1000 per 1m")
+	_ = os.Setenv("GUBER_RATE_LIMIT_SCHEDULES", "api: 200 during * 0-7,22-23 * * *; api: 500 during TZ=America/New_York * * * * 0,6")
+	daemonConfig, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Len(t, daemonConfig.Templates, 1)
+	schedules := daemonConfig.Templates[0].Schedules
+	require.Len(t, schedules, 2)
+	assert.Equal(t, "* 0-7,22-23 * * *", schedules[0].Schedule)
+	assert.Equal(t, int64(200), schedules[0].Limit)
+	assert.Equal(t, "TZ=America/New_York * * * * 0,6", schedules[1].Schedule)
+	assert.Equal(t, int64(500), schedules[1].Limit)
+
+	for _, v := range []string{"api: 200 * 0-7 * * *", "api: lots during * * * * *", "api: 200 during * 25 * * *", "other: 200 during * * * * *"} {
+		_ = os.Setenv("GUBER_RATE_LIMIT_SCHEDULES", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+	os.Clearenv()
+}
+
+func TestOverLimitAlertConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_OVER_LIMIT_ALERTS", "public-api;threshold=100;for=5m,login;threshold=20")
+	_ = os.Setenv("GUBER_ALERT_SLACK_URL", "https://hooks.slack.com/services/T000/B000/XXXX")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []OverLimitAlert{
+		{Prefix: "public-api", Threshold: 100, For: 5 * time.Minute},
+		{Prefix: "login", Threshold: 20},
+	}, daemonConfig.OverLimitAlerts)
+
+	for _, v := range []string{"public-api", "public-api;threshold=lots", "public-api;threshold=1;for=-1m", "public-api;unknown", ";threshold=1"} {
+		_ = os.Setenv("GUBER_OVER_LIMIT_ALERTS", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+
+	_ = os.Setenv("GUBER_OVER_LIMIT_ALERTS", "login;threshold=20")
+	_ = os.Unsetenv("GUBER_ALERT_SLACK_URL")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_OVER_LIMIT_ALERTS requires")
+	os.Clearenv()
+}
+
+func TestNormalizeConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_NORMALIZE", "trim,lowercase")
+	_ = os.Setenv("GUBER_NAME_ALIASES", "login=user_login")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Len(t, daemonConfig.Normalizers, 3)
+
+	// Aliases match the name after the other normalizers were applied
+	r := &RateLimitReq{Name: " Login", UniqueKey: "Account:1234 "}
+	for _, n := range daemonConfig.Normalizers {
+		n(r)
+	}
+	assert.Equal(t, "user_login", r.Name)
+	assert.Equal(t, "account:1234", r.UniqueKey)
+
+	_ = os.Setenv("GUBER_NORMALIZE", "uppercase")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "normalizer 'uppercase' is invalid")
+
+	_ = os.Unsetenv("GUBER_NORMALIZE")
+	for _, v := range []string{"login", "=user_login", "login="} {
+		_ = os.Setenv("GUBER_NAME_ALIASES", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+	os.Clearenv()
+}
+
+func TestPeerAuthConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_PEER_AUTH_TOKEN", "new-secret")
+	_ = os.Setenv("GUBER_PEER_AUTH_ACCEPT_TOKENS", "old-secret,older-secret")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Equal(t, &PeerAuthConfig{
+		Token:        "new-secret",
+		AcceptTokens: []string{"old-secret", "older-secret"},
+	}, daemonConfig.PeerAuth)
+
+	_ = os.Setenv("GUBER_PEER_AUTH_ACCEPT_TOKENS", "old-secret,")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_PEER_AUTH_ACCEPT_TOKENS")
+
+	_ = os.Unsetenv("GUBER_PEER_AUTH_TOKEN")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_PEER_AUTH_ACCEPT_TOKENS requires GUBER_PEER_AUTH_TOKEN")
+	os.Clearenv()
+}
+
+func TestRequestLimitConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_GRPC_MAX_CONCURRENT_STREAMS", "1000")
+	_ = os.Setenv("GUBER_MAX_IN_FLIGHT_REQUESTS", "5000")
+	_ = os.Setenv("GUBER_MAX_QUEUED_REQUESTS", "100")
+	_ = os.Setenv("GUBER_MAX_REQUESTS_PER_SECOND", "20000")
+	_ = os.Setenv("GUBER_MAX_IN_FLIGHT_PEER_REQUESTS", "2000")
+	_ = os.Setenv("GUBER_MAX_PEER_REQUESTS_PER_SECOND", "50000")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1000), daemonConfig.GRPCMaxConcurrentStreams)
+	assert.Equal(t, 5000, daemonConfig.MaxInFlightRequests)
+	assert.Equal(t, 100, daemonConfig.MaxQueuedRequests)
+	assert.Equal(t, time.Second, daemonConfig.QueueTimeout)
+	assert.Equal(t, 20000, daemonConfig.MaxRequestsPerSecond)
+	assert.Equal(t, 2000, daemonConfig.MaxInFlightPeerRequests)
+	assert.Equal(t, 50000, daemonConfig.MaxPeerRequestsPerSecond)
+	assert.Nil(t, daemonConfig.CompatModes)
+
+	_ = os.Setenv("GUBER_MAX_IN_FLIGHT_PEER_REQUESTS", "-1")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_MAX_IN_FLIGHT_PEER_REQUESTS and GUBER_MAX_PEER_REQUESTS_PER_SECOND cannot be negative")
+	_ = os.Unsetenv("GUBER_MAX_IN_FLIGHT_PEER_REQUESTS")
+
+	_ = os.Setenv("GUBER_GRPC_MAX_CONCURRENT_STREAMS", "-1")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_GRPC_MAX_CONCURRENT_STREAMS cannot be negative")
+
+	_ = os.Unsetenv("GUBER_GRPC_MAX_CONCURRENT_STREAMS")
+	_ = os.Unsetenv("GUBER_MAX_IN_FLIGHT_REQUESTS")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_MAX_QUEUED_REQUESTS requires GUBER_MAX_IN_FLIGHT_REQUESTS")
+	os.Clearenv()
+}
+
+func TestCompatModesConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_COMPAT_MODES", "v0=reset_seconds, v1=reset_after_seconds,v1=reset_seconds")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Len(t, daemonConfig.CompatModes["v0"], 1)
+	assert.Len(t, daemonConfig.CompatModes["v1"], 2)
+
+	_ = os.Setenv("GUBER_COMPAT_MODES", "v0")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "invalid GUBER_COMPAT_MODES")
+	os.Clearenv()
+}
+
+func TestLoadSheddingConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_LOAD_SHEDDING_CPU_PERCENT", "80")
+	_ = os.Setenv("GUBER_PEER_WEIGHT", "4")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 80, daemonConfig.LoadSheddingCPUPercent)
+	assert.Equal(t, 50, daemonConfig.LoadSheddingWeightPercent)
+	assert.Equal(t, 10*time.Second, daemonConfig.LoadSheddingInterval)
+	assert.Equal(t, time.Minute, daemonConfig.LoadSheddingCooldown)
+
+	// The default weight of 1 cannot be reduced
+	_ = os.Unsetenv("GUBER_PEER_WEIGHT")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_LOAD_SHEDDING_CPU_PERCENT requires a GUBER_PEER_WEIGHT")
+
+	_ = os.Setenv("GUBER_PEER_WEIGHT", "4")
+	_ = os.Setenv("GUBER_PEER_DISCOVERY_TYPE", "dns")
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_LOAD_SHEDDING_CPU_PERCENT requires GUBER_PEER_DISCOVERY_TYPE")
+	os.Clearenv()
+}
+
+func TestSetupFromEnv(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_GRPC_ADDRESS", "127.0.0.1:9000")
+	_ = os.Setenv("GUBER_CACHE_SIZE", "1000")
+	_ = os.Setenv("GUBER_NAMESPACE_GC_AFTER", "720h")
+	_ = os.Setenv("GUBER_COLD_KEY_FILTER_INTERVAL", "1s")
+	_ = os.Setenv("GUBER_LOG_SAMPLING_BURST", "10")
+	_ = os.Setenv("GUBER_NODE_ID_FILE", "/var/lib/gubernator/node-id")
+	daemonConfig, err := SetupFromEnv(logrus.StandardLogger())
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:9000", daemonConfig.GRPCListenAddress)
+	require.Equal(t, 1000, daemonConfig.CacheSize)
+	require.Equal(t, 720*time.Hour, daemonConfig.NamespaceGCAfter)
+	require.Equal(t, time.Second, daemonConfig.ColdKeyFilterInterval)
+	require.Equal(t, LogSamplingConfig{Burst: 10, Interval: 10 * time.Second}, daemonConfig.LogSampling)
+	require.Equal(t, "/var/lib/gubernator/node-id", daemonConfig.NodeIDFile)
+
+	_ = os.Setenv("GUBER_CACHE_SIZE", "lots")
+	_ = os.Setenv("GUBER_BATCH_TIMEOUT", "soon")
+	_ = os.Setenv("GUBER_PEER_WEIGHT", "-1")
+	_ = os.Setenv("GUBER_TLS_CA", "/does/not/exist.pem")
+	_, err = SetupFromEnv(logrus.StandardLogger())
+	require.Error(t, err)
+	for _, name := range []string{"GUBER_CACHE_SIZE", "GUBER_BATCH_TIMEOUT", "GUBER_PEER_WEIGHT", "GUBER_TLS_CA"} {
+		assert.Contains(t, err.Error(), name)
+	}
+
+	// SetupDaemonConfig ignores variables which cannot be parsed
+	_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GUBER_PEER_WEIGHT")
+	assert.NotContains(t, err.Error(), "GUBER_CACHE_SIZE")
+	os.Clearenv()
+}
+
+func TestKeyRedaction(t *testing.T) {
+	const key = "alice@example.com"
+	assert.Equal(t, "sha256:ff8d9819fc0e12bf", redactKey(KeyRedactionHash, key))
+	assert.Equal(t, redactKey(KeyRedactionHash, key), redactKey("", key))
+	assert.Equal(t, "[redacted]", redactKey(KeyRedactionMask, key))
+	assert.Equal(t, key, redactKey(KeyRedactionNone, key))
+	assert.Equal(t, "", redactKey(KeyRedactionHash, ""))
+
+	var conf Config
+	require.NoError(t, conf.SetDefaults())
+	assert.Equal(t, KeyRedactionHash, conf.KeyRedaction)
+	conf = Config{KeyRedaction: "encrypt"}
+	assert.ErrorContains(t, conf.SetDefaults(), "KeyRedaction 'encrypt' is invalid")
+
+	os.Clearenv()
+	_ = os.Setenv("GUBER_KEY_REDACTION", "none")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, KeyRedactionNone, daemonConfig.KeyRedaction)
+	os.Clearenv()
+}
+
+func TestPeerTLSOverrides(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_PEER_TLS_OVERRIDES", "10.0.0.5:1051;server_name=gubernator-0.internal,10.0.0.5;ca=contrib/certs/ca.cert")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	require.Len(t, daemonConfig.PeerTLSOverrides, 2)
+	assert.Equal(t, "gubernator-0.internal", daemonConfig.PeerTLSOverrides[0].ServerName)
+	assert.NotNil(t, daemonConfig.PeerTLSOverrides[1].RootCAs)
+
+	for _, v := range []string{";server_name=x", "10.0.0.5;unknown", "10.0.0.5;ca=contrib/certs/missing.cert", "10.0.0.5;ca=README.md"} {
+		_ = os.Setenv("GUBER_PEER_TLS_OVERRIDES", v)
+		_, err = SetupDaemonConfig(logrus.StandardLogger(), nil)
+		assert.Error(t, err, v)
+	}
+	os.Clearenv()
+
+	// Overrides require PeerTLS and may not repeat an address
+	conf := Config{PeerTLSOverrides: daemonConfig.PeerTLSOverrides}
+	assert.ErrorContains(t, conf.SetDefaults(), "requires PeerTLS")
+	conf = Config{PeerTLS: &tls.Config{}, PeerTLSOverrides: []PeerTLSOverride{{Address: "a"}, {Address: "a"}}}
+	assert.ErrorContains(t, conf.SetDefaults(), "more than one override")
+
+	conf = Config{PeerTLS: &tls.Config{ServerName: "gubernator"}, PeerTLSOverrides: daemonConfig.PeerTLSOverrides}
+	require.NoError(t, conf.SetDefaults())
+
+	// The override of the address wins over the override of its host
+	tlsConf := conf.peerTLS("10.0.0.5:1051")
+	assert.Equal(t, "gubernator-0.internal", tlsConf.ServerName)
+	assert.Nil(t, tlsConf.RootCAs)
+
+	tlsConf = conf.peerTLS("10.0.0.5:1052")
+	assert.Equal(t, "gubernator", tlsConf.ServerName)
+	assert.Same(t, daemonConfig.PeerTLSOverrides[1].RootCAs, tlsConf.RootCAs)
+
+	// Peers without an override use PeerTLS, which is never modified
+	assert.Same(t, conf.PeerTLS, conf.peerTLS("10.0.0.6:1051"))
+	assert.Equal(t, "gubernator", conf.PeerTLS.ServerName)
+}
+
+func TestShadowConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_SHADOW_ADDRESS", "gubernator-next.example.com:1051")
+	_ = os.Setenv("GUBER_SHADOW_PERCENT", "10")
+	_ = os.Setenv("GUBER_SHADOW_TIMEOUT", "1s")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "gubernator-next.example.com:1051", daemonConfig.Shadow.Address)
+	assert.Equal(t, 10, daemonConfig.Shadow.Percent)
+	assert.Equal(t, time.Second, daemonConfig.Shadow.Timeout)
+
+	conf := Config{Shadow: daemonConfig.Shadow}
+	require.NoError(t, conf.SetDefaults())
+	assert.Equal(t, 100, conf.Shadow.MaxInFlight)
+	os.Clearenv()
+}
+
+func TestStandbyConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_STANDBY_NAMES", "billing,payouts")
+	daemonConfig, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing", "payouts"}, daemonConfig.Standby.Names)
+
+	conf := Config{Standby: daemonConfig.Standby}
+	require.NoError(t, conf.SetDefaults())
+	assert.Equal(t, 500*time.Millisecond, conf.Standby.Timeout)
+	os.Clearenv()
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := Config{
+		CacheSize:                  2,
+		Workers:                    4,
+		MaxNameLength:              -1,
+		ForwardingOverridesEnabled: true,
+	}
+	err := conf.SetDefaults()
+	var errs ConfigErrors
+	require.ErrorAs(t, err, &errs)
+	// Every problem is reported at once
+	require.Len(t, errs, 3)
+	assert.Contains(t, errs[0].Error(), "CacheSize '2' cannot be less than Workers '4'")
+	assert.EqualError(t, errs[1], "MaxNameLength cannot be negative")
+	assert.EqualError(t, errs[2], "ForwardingOverridesEnabled requires AdminEnabled")
+	assert.Equal(t, errs[0].Error()+"; "+errs[1].Error()+"; "+errs[2].Error(), err.Error())
+
+	conf = Config{CacheSize: 4, Workers: 4}
+	assert.NoError(t, conf.SetDefaults())
+	assert.NoError(t, conf.Validate())
+}
+
+func TestAdvertiseAddressConfig(t *testing.T) {
+	os.Clearenv()
+	_ = os.Setenv("GUBER_ADVERTISE_ADDRESS", "10.0.0.2:0")
+	_, err := SetupDaemonConfig(logrus.StandardLogger(), nil)
+	assert.ErrorContains(t, err, "GUBER_ADVERTISE_ADDRESS cannot have port 0")
+	os.Clearenv()
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+)
+
+// MetadataCostCenter is the metadata of a RateLimitReq which attributes its hits and the time spent
+// evaluating it to a cost center, IE: the team or service which made the request. If the metadata
+// is not set, the request value of the same name is used, such that a GRPC interceptor may
+// attribute requests by the identity of the client. See Config.MaxCostCenters
+const MetadataCostCenter = "cost_center"
+
+const (
+	// costCenterUntagged is the cost center of the requests without MetadataCostCenter
+	costCenterUntagged = "untagged"
+	// costCenterOther is the cost center of the requests whose cost center was seen after
+	// Config.MaxCostCenters others
+	costCenterOther = "other"
+	// The longest cost center, longer cost centers are truncated
+	maxCostCenterLen = 64
+)
+
+// CostCenterUsage is the consumption of a cost center, see MetadataCostCenter
+type CostCenterUsage struct {
+	Name string `json:"name"`
+	// The hits of the rate limits tagged with the cost center
+	Hits int64 `json:"hits"`
+	// The seconds spent evaluating the rate limits tagged with the cost center
+	Seconds float64 `json:"seconds"`
+}
+
+// costCenters bounds the number of distinct cost centers, such that clients cannot create an
+// unbounded number of metric labels.
+type costCenters struct {
+	mutex sync.Mutex
+	max   int
+	seen  map[string]struct{}
+}
+
+func newCostCenters(max int) *costCenters {
+	return &costCenters{max: max, seen: make(map[string]struct{})}
+}
+
+// name returns the cost center the request `r` is attributed to
+func (c *costCenters) name(ctx context.Context, r *RateLimitReq) string {
+	name := r.Metadata[MetadataCostCenter]
+	if name == "" {
+		name, _ = RequestValue(ctx, MetadataCostCenter)
+	}
+	if name == "" {
+		return costCenterUntagged
+	}
+	if len(name) > maxCostCenterLen {
+		name = name[:maxCostCenterLen]
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.seen[name]; ok {
+		return name
+	}
+	if len(c.seen) >= c.max {
+		return costCenterOther
+	}
+	c.seen[name] = struct{}{}
+	return name
+}
@@ -36,11 +36,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/quic-go/quic-go/http3"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -53,6 +55,7 @@ type Daemon struct {
 	PeerInfo      PeerInfo
 
 	log           FieldLogger
+	logSampler    *logSampler
 	logWriter     *io.PipeWriter
 	pool          PoolInterface
 	conf          DaemonConfig
@@ -65,11 +68,15 @@ type Daemon struct {
 	gwCancel      context.CancelFunc
 	instanceConf  Config
 	client        V1Client
+	auditSink     AuditSink
+	sqliteStore   *SQLiteStore
+	quicSrv       *http3.Server
+	errs          chan error
 }
 
 // SpawnDaemon starts a new gubernator daemon according to the provided DaemonConfig.
 // This function will block until the daemon responds to connections as specified
-// by GRPCListenAddress and HTTPListenAddress
+// by GRPCListenAddress and HTTPListenAddress, or GRPCListener and HTTPListener
 func SpawnDaemon(ctx context.Context, conf DaemonConfig) (*Daemon, error) {
 
 	s := &Daemon{
@@ -87,9 +94,37 @@ func (s *Daemon) Start(ctx context.Context) error {
 		"instance": s.conf.InstanceID,
 		"category": "gubernator",
 	}))
+	if err := s.conf.LogSampling.validate(); err != nil {
+		return err
+	}
+	if s.conf.LogSampling.Burst > 0 {
+		s.log, s.logSampler = newSampledLogger(s.log, s.conf.LogSampling)
+	}
 
+	s.errs = make(chan error, 10)
 	s.promRegister = prometheus.NewRegistry()
 
+	// The addresses of provided listeners are used to dial the gateway and to recognize ourselves
+	// in the peer list
+	if s.conf.GRPCListener != nil {
+		if s.grpcListeners() > 1 {
+			return errors.New("GRPCListener cannot be used with GRPCListeners greater than 1")
+		}
+		s.conf.GRPCListenAddress = s.conf.GRPCListener.Addr().String()
+	}
+	if s.conf.HTTPListener != nil {
+		s.conf.HTTPListenAddress = s.conf.HTTPListener.Addr().String()
+	}
+
+	// The node ID is loaded before this instance is advertised to its peers
+	if s.conf.NodeID == "" && s.conf.NodeIDFile != "" {
+		if s.conf.NodeID, err = loadNodeID(s.conf.NodeIDFile); err != nil {
+			return err
+		}
+	}
+	setter.SetDefault(&s.conf.EtcdPoolConf.Advertise.NodeID, s.conf.NodeID)
+	setter.SetDefault(&s.conf.MemberListPoolConf.Advertise.NodeID, s.conf.NodeID)
+
 	// The LRU cache for storing rate limits.
 	cacheCollector := NewLRUCacheCollector()
 	if err := s.promRegister.Register(cacheCollector); err != nil {
@@ -104,6 +139,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 
 	// Handler to collect duration and API access metrics for GRPC
 	s.statsHandler = NewGRPCStatsHandler()
+	s.statsHandler.collectInternals = s.conf.MetricFlags.Has(FlagGRPCMetrics)
 	_ = s.promRegister.Register(s.statsHandler)
 
 	var filters []otelgrpc.Option
@@ -125,6 +161,31 @@ func (s *Daemon) Start(ctx context.Context) error {
 		grpc.StatsHandler(otelgrpc.NewServerHandler(filters...)),
 	}
 
+	if s.conf.GRPCMaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(s.conf.GRPCMaxConcurrentStreams))
+	}
+
+	if len(s.conf.CompatModes) != 0 {
+		opts = append(opts, grpc.ChainUnaryInterceptor(compatModes(s.conf.CompatModes).unaryInterceptor()))
+	}
+
+	if b := newServiceBudget(V1_ServiceDesc, 0, s.conf.MaxRequestsPerSecond); b != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(b.unaryInterceptor()))
+	}
+
+	if l := newRequestLimiter(s.conf.MaxInFlightRequests, s.conf.MaxQueuedRequests, s.conf.QueueTimeout); l != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(l.unaryInterceptor()))
+	}
+
+	if s.conf.PeerAuth != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.conf.PeerAuth.UnaryServerInterceptor()))
+	}
+
+	// Chained after PeerAuth such that requests which are not from a peer do not count against the budget
+	if b := newServiceBudget(PeersV1_ServiceDesc, s.conf.MaxInFlightPeerRequests, s.conf.MaxPeerRequestsPerSecond); b != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(b.unaryInterceptor()))
+	}
+
 	if s.conf.GRPCMaxConnectionAgeSeconds > 0 {
 		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionAge:      time.Second * time.Duration(s.conf.GRPCMaxConnectionAgeSeconds),
@@ -136,26 +197,134 @@ func (s *Daemon) Start(ctx context.Context) error {
 		return err
 	}
 
+	// One GRPC server instance for each listener, followed by a server for the API Gateway when TLS is
+	// enabled, all of which serve the same gubernator instance
+	listeners := s.grpcListeners()
+	listenerOpts := opts
+	if s.conf.ServerTLS() != nil {
+		listenerOpts = append(opts, grpc.Creds(credentials.NewTLS(s.conf.ServerTLS())))
+	}
+	for i := 0; i < listeners; i++ {
+		s.grpcSrvs = append(s.grpcSrvs, grpc.NewServer(listenerOpts...))
+	}
 	if s.conf.ServerTLS() != nil {
-		// Create two GRPC server instances, one for TLS and the other for the API Gateway
-		opts2 := append(opts, grpc.Creds(credentials.NewTLS(s.conf.ServerTLS())))
-		s.grpcSrvs = append(s.grpcSrvs, grpc.NewServer(opts2...))
+		s.grpcSrvs = append(s.grpcSrvs, grpc.NewServer(opts...))
+	}
+
+	switch {
+	case s.conf.AuditFile != "":
+		s.auditSink, err = NewFileAuditSink(s.conf.AuditFile, s.log)
+		if err != nil {
+			return err
+		}
+	case s.conf.AuditWebhookURL != "":
+		s.auditSink = NewWebhookAuditSink(s.conf.AuditWebhookURL, s.log)
+	}
+
+	if s.conf.SQLiteStoreFile != "" {
+		s.sqliteStore, err = NewSQLiteStore(SQLiteStoreConfig{
+			Path:         s.conf.SQLiteStoreFile,
+			HashKey:      s.conf.HashKey,
+			KeyRedaction: s.conf.KeyRedaction,
+			Logger:       s.log,
+		})
+		if err != nil {
+			return err
+		}
 	}
-	s.grpcSrvs = append(s.grpcSrvs, grpc.NewServer(opts...))
 
 	// Registers a new gubernator instance with the GRPC server
 	s.instanceConf = Config{
-		PeerTraceGRPC: s.conf.TraceLevel >= tracing.DebugLevel,
-		PeerTLS:       s.conf.ClientTLS(),
-		DataCenter:    s.conf.DataCenter,
-		LocalPicker:   s.conf.Picker,
-		GRPCServers:   s.grpcSrvs,
-		Logger:        s.log,
-		CacheFactory:  cacheFactory,
-		Behaviors:     s.conf.Behaviors,
-		CacheSize:     s.conf.CacheSize,
-		Workers:       s.conf.Workers,
-		InstanceID:    s.conf.InstanceID,
+		PeerTraceGRPC:              s.conf.TraceLevel >= tracing.DebugLevel,
+		PeerTLS:                    s.conf.ClientTLS(),
+		PeerTLSOverrides:           s.conf.PeerTLSOverrides,
+		PeerCompression:            s.conf.PeerCompression,
+		PeerCompressionMinBytes:    s.conf.PeerCompressionMinBytes,
+		ReadyMinPeers:              s.conf.ReadyMinPeers,
+		ReadyMinPeersPercent:       s.conf.ReadyMinPeersPercent,
+		UnavailableUntilReady:      s.conf.UnavailableUntilReady,
+		DefaultRequestTimeout:      s.conf.DefaultRequestTimeout,
+		Faults:                     s.conf.Faults,
+		AdminEnabled:               s.conf.AdminEnabled,
+		ForwardingOverridesEnabled: s.conf.ForwardingOverridesEnabled,
+		AuditSink:                  s.auditSink,
+		PeerTransport:              s.conf.PeerTransport,
+		PeerAuth:                   s.conf.PeerAuth,
+		RequestValueSigning:        s.conf.RequestValueSigning,
+		UsageWindow:                s.conf.UsageWindow,
+		UsagePrivacy:               s.conf.UsagePrivacy,
+		JournalDir:                 s.conf.JournalDir,
+		JournalRetention:           s.conf.JournalRetention,
+		UsageExportInterval:        s.conf.UsageExportInterval,
+		MaxCostCenters:             s.conf.MaxCostCenters,
+		KeyHistorySize:             s.conf.KeyHistorySize,
+		KeyHistoryMaxKeys:          s.conf.KeyHistoryMaxKeys,
+		NamespaceGCAfter:           s.conf.NamespaceGCAfter,
+		ColdKeyFilterInterval:      s.conf.ColdKeyFilterInterval,
+		OverLimitAlerts:            s.conf.OverLimitAlerts,
+		Federation:                 s.conf.Federation,
+		Shadow:                     s.conf.Shadow,
+		Standby:                    s.conf.Standby,
+		Normalizers:                s.conf.Normalizers,
+		DataCenter:                 s.conf.DataCenter,
+		LocalPicker:                s.conf.Picker,
+		GRPCServers:                s.grpcSrvs,
+		Logger:                     s.log,
+		CacheFactory:               cacheFactory,
+		HashKey:                    s.conf.HashKey,
+		Behaviors:                  s.conf.Behaviors,
+		NamespacePolicies:          s.conf.NamespacePolicies,
+		PolicyFile:                 s.conf.PolicyFile,
+		Templates:                  s.conf.Templates,
+		CacheSize:                  s.conf.CacheSize,
+		MaxCacheBytes:              s.conf.MaxCacheBytes,
+		CacheFullPolicy:            s.conf.CacheFullPolicy,
+		CacheClasses:               s.conf.CacheClasses,
+		CacheTenantSeparator:       s.conf.CacheTenantSeparator,
+		CacheTenantPercent:         s.conf.CacheTenantPercent,
+		CacheTenantPercents:        s.conf.CacheTenantPercents,
+		CacheKeyHash:               s.conf.CacheKeyHash,
+		AlgorithmChangePolicy:      s.conf.AlgorithmChangePolicy,
+		MaxNameLength:              s.conf.MaxNameLength,
+		MaxKeyLength:               s.conf.MaxKeyLength,
+		LongKeyPolicy:              s.conf.LongKeyPolicy,
+		KeyRedaction:               s.conf.KeyRedaction,
+		Workers:                    s.conf.Workers,
+		FanOutWorkers:              s.conf.FanOutWorkers,
+		FanOutQueueSize:            s.conf.FanOutQueueSize,
+		LockFreeReads:              s.conf.LockFreeReads,
+		InstanceID:                 s.conf.InstanceID,
+		NodeID:                     s.conf.NodeID,
+	}
+
+	if s.instanceConf.Federation.HomeAddress != "" && s.instanceConf.Federation.TLS == nil {
+		s.instanceConf.Federation.TLS = s.conf.ClientTLS()
+	}
+	if s.instanceConf.Shadow.Address != "" && s.instanceConf.Shadow.TLS == nil {
+		s.instanceConf.Shadow.TLS = s.conf.ClientTLS()
+	}
+	if s.conf.UsageExportURL != "" {
+		s.instanceConf.UsageExporter = NewWebhookUsageExporter(s.conf.UsageExportURL)
+	}
+	switch {
+	case s.conf.AlertWebhookURL != "":
+		s.instanceConf.AlertNotifier = NewWebhookAlertNotifier(s.conf.AlertWebhookURL)
+	case s.conf.AlertSlackURL != "":
+		s.instanceConf.AlertNotifier = NewSlackAlertNotifier(s.conf.AlertSlackURL)
+	}
+	if s.sqliteStore != nil {
+		s.instanceConf.Store = s.sqliteStore
+	}
+	if s.conf.SnapshotFile != "" {
+		s.instanceConf.Loader = NewSnapshotLoader(s.conf.SnapshotFile, s.log)
+		s.instanceConf.SnapshotInterval = s.conf.SnapshotInterval
+	}
+	if s.conf.DecisionCalloutURL != "" {
+		s.instanceConf.Middleware = append(s.instanceConf.Middleware, NewDecisionCallout(DecisionCalloutConfig{
+			URL:     s.conf.DecisionCalloutURL,
+			Timeout: s.conf.DecisionCalloutTimeout,
+			Names:   s.conf.DecisionCalloutNames,
+		}))
 	}
 
 	s.V1Server, err = NewV1Instance(s.instanceConf)
@@ -163,22 +332,57 @@ func (s *Daemon) Start(ctx context.Context) error {
 		return errors.Wrap(err, "while creating new gubernator instance")
 	}
 
+	health := newHealthServer(s.V1Server)
+	for _, srv := range s.grpcSrvs {
+		grpc_health_v1.RegisterHealthServer(srv, health)
+	}
+
 	// V1Server instance also implements prometheus.Collector interface
 	_ = s.promRegister.Register(s.V1Server)
 
-	l, err := net.Listen("tcp", s.conf.GRPCListenAddress)
+	var grpcListeners []net.Listener
+	if s.conf.GRPCListener != nil {
+		grpcListeners = []net.Listener{s.conf.GRPCListener}
+	} else if listeners > 1 {
+		grpcListeners, err = listenReusePort(s.conf.GRPCListenAddress, listeners)
+	} else {
+		var l net.Listener
+		l, err = net.Listen("tcp", s.conf.GRPCListenAddress)
+		grpcListeners = []net.Listener{l}
+	}
 	if err != nil {
 		return errors.Wrap(err, "while starting GRPC listener")
 	}
-	s.GRPCListeners = append(s.GRPCListeners, l)
+	grpcListeners = newLimitListeners(grpcListeners, s.conf.MaxConnections, s.conf.MaxConnectionsPerIP)
+	s.GRPCListeners = append(s.GRPCListeners, grpcListeners...)
 
 	// Start serving GRPC Requests
-	s.wg.Go(func() {
-		s.log.Infof("GRPC Listening on %s ...", l.Addr().String())
-		if err := s.grpcSrvs[0].Serve(l); err != nil {
-			s.log.WithError(err).Error("while starting GRPC server")
+	for i, l := range grpcListeners {
+		srv, l := s.grpcSrvs[i], l
+		s.wg.Go(func() {
+			s.log.Infof("GRPC Listening on %s ...", l.Addr().String())
+			if err := srv.Serve(l); err != nil {
+				s.fail(err, "while serving GRPC")
+			}
+		})
+	}
+
+	if s.conf.PeerTransport == PeerTransportQUIC {
+		if s.conf.ServerTLS() == nil {
+			return errors.New("GUBER_PEER_TRANSPORT=quic requires TLS")
+		}
+		pc, err := net.ListenPacket("udp", s.conf.GRPCListenAddress)
+		if err != nil {
+			return errors.Wrap(err, "while starting QUIC peer listener")
 		}
-	})
+		s.quicSrv = NewQUICPeerServer(s.V1Server, s.conf.GRPCListenAddress, s.conf.ServerTLS(), s.conf.PeerAuth)
+		s.wg.Go(func() {
+			s.log.Infof("QUIC Peer Listening on %s ...", pc.LocalAddr().String())
+			if err := s.quicSrv.Serve(pc); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.fail(err, "while serving QUIC peers")
+			}
+		})
+	}
 
 	var gatewayAddr string
 	if s.conf.ServerTLS() != nil {
@@ -193,8 +397,8 @@ func (s *Daemon) Start(ctx context.Context) error {
 
 		s.wg.Go(func() {
 			s.log.Infof("GRPC Gateway Listening on %s ...", l.Addr())
-			if err := s.grpcSrvs[1].Serve(l); err != nil {
-				s.log.WithError(err).Error("while starting GRPC Gateway server")
+			if err := s.grpcSrvs[listeners].Serve(l); err != nil {
+				s.fail(err, "while serving the GRPC Gateway")
 			}
 		})
 		gatewayAddr = l.Addr().String()
@@ -209,12 +413,14 @@ func (s *Daemon) Start(ctx context.Context) error {
 	case "k8s":
 		// Source our list of peers from kubernetes endpoint API
 		s.conf.K8PoolConf.OnUpdate = s.V1Server.SetPeers
+		s.conf.K8PoolConf.OnClusterSize = s.V1Server.SetClusterSize
 		s.pool, err = NewK8sPool(s.conf.K8PoolConf)
 		if err != nil {
 			return errors.Wrap(err, "while querying kubernetes API")
 		}
 	case "etcd":
 		s.conf.EtcdPoolConf.OnUpdate = s.V1Server.SetPeers
+		s.conf.EtcdPoolConf.OnError = s.sendErr
 		// Register ourselves with other peers via ETCD
 		s.conf.EtcdPoolConf.Client, err = etcdutil.NewClient(s.conf.EtcdPoolConf.EtcdConfig)
 		if err != nil {
@@ -231,8 +437,16 @@ func (s *Daemon) Start(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrap(err, "while creating the DNS pool")
 		}
+	case "aws":
+		s.conf.AWSPoolConf.OnUpdate = s.V1Server.SetPeers
+		s.conf.AWSPoolConf.Logger = s.log
+		s.pool, err = NewAWSPool(s.conf.AWSPoolConf)
+		if err != nil {
+			return errors.Wrap(err, "while creating the AWS pool")
+		}
 	case "member-list":
 		s.conf.MemberListPoolConf.OnUpdate = s.V1Server.SetPeers
+		s.conf.MemberListPoolConf.OnDelta = s.V1Server.UpdatePeers
 		s.conf.MemberListPoolConf.Logger = s.log
 
 		// Register peer on the member list
@@ -242,6 +456,12 @@ func (s *Daemon) Start(ctx context.Context) error {
 		}
 	}
 
+	if s.conf.LoadSheddingCPUPercent != 0 {
+		if err := s.runLoadShedder(); err != nil {
+			return errors.Wrap(err, "while starting load shedding")
+		}
+	}
+
 	// We override the default Marshaller to enable the `UseProtoNames` option.
 	// We do this is because the default JSONPb in 2.5.0 marshals proto structs using
 	// `camelCase`, while all the JSON annotations are `under_score`.
@@ -249,7 +469,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 	// https://developers.google.com/protocol-buffers/docs/style#message-and-field-names
 	// Camel case breaks unmarshalling our GRPC gateway responses with protobuf structs.
 	gateway := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, gatewayMarshaler{&runtime.JSONPb{
 			MarshalOptions: protojson.MarshalOptions{
 				UseProtoNames:   true,
 				EmitUnpopulated: true,
@@ -257,7 +477,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 			UnmarshalOptions: protojson.UnmarshalOptions{
 				DiscardUnknown: true,
 			},
-		}),
+		}}),
 	)
 
 	// Set up an JSON Gateway API for our GRPC methods
@@ -268,6 +488,13 @@ func (s *Daemon) Start(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "while registering GRPC gateway handler")
 	}
+	if s.conf.AdminEnabled {
+		err = RegisterAdminV1HandlerFromEndpoint(gwCtx, gateway, gatewayAddr,
+			[]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())})
+		if err != nil {
+			return errors.Wrap(err, "while registering GRPC gateway admin handler")
+		}
+	}
 
 	// Serve the JSON Gateway and metrics handlers via standard HTTP/1
 	mux := http.NewServeMux()
@@ -287,17 +514,57 @@ func (s *Daemon) Start(ctx context.Context) error {
 	}
 
 	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
-		s.promRegister, promhttp.HandlerFor(s.promRegister, promhttp.HandlerOpts{}),
+		s.promRegister, promhttp.HandlerFor(s.promRegister, promhttp.HandlerOpts{
+			// Exposes the exemplars of the latency histograms to scrapers which accept OpenMetrics
+			EnableOpenMetrics: true,
+		}),
 	))
+	setter.SetDefault(&s.conf.ServiceConfig, DefaultServiceConfig)
+	if err := validateServiceConfig(s.conf.ServiceConfig); err != nil {
+		return errors.Wrap(err, "invalid DaemonConfig.ServiceConfig")
+	}
+	mux.Handle("/v1/ServiceConfig", newServiceConfigHandler(s.conf.ServiceConfig))
+	spec, err := OpenAPISpec(s.conf.AdminEnabled)
+	if err != nil {
+		return errors.Wrap(err, "while generating the OpenAPI document")
+	}
+	mux.Handle("/openapi.json", newOpenAPIHandler(spec))
+	if s.conf.OpenAPIUIEnabled {
+		mux.Handle("/openapi/", newOpenAPIUIHandler())
+	}
+	mux.Handle("/healthz", livenessHandler())
+	mux.Handle("/readyz", readinessHandler(s.V1Server))
+	if s.conf.UIEnabled {
+		if !s.conf.AdminEnabled {
+			return errors.New("DaemonConfig.UIEnabled requires AdminEnabled")
+		}
+		mux.Handle("/ui/", newUIHandler())
+	}
 	mux.Handle("/", gateway)
+	if len(s.conf.HTTPKeyExtractors) != 0 {
+		mux.Handle("/v1/GetRateLimits", newKeyExtractorHandler(s.conf.HTTPKeyExtractors, s.conf.HTTPTrustedProxies, gateway))
+	}
+
+	// Optionally serve gRPC-Web requests on the HTTP listener, such that browsers
+	// may make GRPC calls directly without a proxy.
+	var handler http.Handler = mux
+	if s.conf.GRPCWebEnabled {
+		handler = newGRPCWebHandler(s.grpcSrvs[0], s.conf.GRPCWebAllowedOrigins, mux)
+	}
+
 	s.logWriter = newLogWriter(s.log)
 	log := log.New(s.logWriter, "", 0)
-	s.httpSrv = &http.Server{Addr: s.conf.HTTPListenAddress, Handler: mux, ErrorLog: log}
+	s.httpSrv = &http.Server{Addr: s.conf.HTTPListenAddress, Handler: handler, ErrorLog: log}
 
-	s.HTTPListener, err = net.Listen("tcp", s.conf.HTTPListenAddress)
-	if err != nil {
-		return errors.Wrap(err, "while starting HTTP listener")
+	if s.conf.HTTPListener != nil {
+		s.HTTPListener = s.conf.HTTPListener
+	} else {
+		s.HTTPListener, err = net.Listen("tcp", s.conf.HTTPListenAddress)
+		if err != nil {
+			return errors.Wrap(err, "while starting HTTP listener")
+		}
 	}
+	s.HTTPListener = newLimitListener(s.HTTPListener, s.conf.MaxConnections, s.conf.MaxConnectionsPerIP)
 
 	httpListenerAddr := s.HTTPListener.Addr().String()
 	addrs := []string{httpListenerAddr}
@@ -309,6 +576,8 @@ func (s *Daemon) Start(ctx context.Context) error {
 		if s.conf.HTTPStatusListenAddress != "" {
 			muxNoMTLS := http.NewServeMux()
 			muxNoMTLS.Handle("/v1/HealthCheck", gateway)
+			muxNoMTLS.Handle("/healthz", livenessHandler())
+			muxNoMTLS.Handle("/readyz", readinessHandler(s.V1Server))
 			s.httpSrvNoMTLS = &http.Server{
 				Addr:      s.conf.HTTPStatusListenAddress,
 				Handler:   muxNoMTLS,
@@ -326,7 +595,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 				s.log.Infof("HTTPS Status Handler Listening on %s ...", httpAddr)
 				if err := s.httpSrvNoMTLS.ServeTLS(httpListener, "", ""); err != nil {
 					if !errors.Is(err, http.ErrServerClosed) {
-						s.log.WithError(err).Error("while starting TLS Status HTTP server")
+						s.fail(err, "while serving TLS Status HTTP")
 					}
 				}
 			})
@@ -339,7 +608,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 			s.log.Infof("HTTPS Gateway Listening on %s ...", httpListenerAddr)
 			if err := s.httpSrv.ServeTLS(s.HTTPListener, "", ""); err != nil {
 				if !errors.Is(err, http.ErrServerClosed) {
-					s.log.WithError(err).Error("while starting TLS HTTP server")
+					s.fail(err, "while serving TLS HTTP")
 				}
 			}
 		})
@@ -348,7 +617,7 @@ func (s *Daemon) Start(ctx context.Context) error {
 			s.log.Infof("HTTP Gateway Listening on %s ...", httpListenerAddr)
 			if err := s.httpSrv.Serve(s.HTTPListener); err != nil {
 				if !errors.Is(err, http.ErrServerClosed) {
-					s.log.WithError(err).Error("while starting HTTP server")
+					s.fail(err, "while serving HTTP")
 				}
 			}
 		})
@@ -365,6 +634,37 @@ func (s *Daemon) Start(ctx context.Context) error {
 	return nil
 }
 
+// Err returns a channel which receives the errors which stop the daemon from serving requests
+// after Start() returns, IE: a listener which fails to accept connections or the loss of the etcd
+// registration. The daemon is not closed when an error is sent, such that the caller may decide to
+// Close() it and exit, or alert and keep running. The channel is closed by Close().
+func (s *Daemon) Err() <-chan error {
+	return s.errs
+}
+
+// fail logs the error and sends it to Err()
+func (s *Daemon) fail(err error, msg string) {
+	s.log.WithError(err).Error(msg)
+	s.sendErr(errors.Wrap(err, msg))
+}
+
+// sendErr sends the error to Err(), the error is dropped if Err() is not read
+func (s *Daemon) sendErr(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
+
+// grpcListeners returns the number of GRPC listeners, a DaemonConfig which was not created by
+// SetupDaemonConfig() may not set GRPCListeners
+func (s *Daemon) grpcListeners() int {
+	if s.conf.GRPCListeners < 1 {
+		return 1
+	}
+	return s.conf.GRPCListeners
+}
+
 // Close gracefully closes all server connections and listening sockets
 func (s *Daemon) Close() {
 	if s.httpSrv == nil && s.httpSrvNoMTLS == nil {
@@ -385,11 +685,28 @@ func (s *Daemon) Close() {
 		s.log.Infof("GRPC close for %s ...", s.GRPCListeners[i].Addr())
 		srv.GracefulStop()
 	}
+	if s.quicSrv != nil {
+		s.log.Infof("QUIC Peer close for %s ...", s.conf.GRPCListenAddress)
+		_ = s.quicSrv.Close()
+		s.quicSrv = nil
+	}
 	s.logWriter.Close()
 	_ = s.V1Server.Close()
+	if s.auditSink != nil {
+		_ = s.auditSink.Close()
+	}
+	if s.sqliteStore != nil {
+		_ = s.sqliteStore.Close()
+	}
 	s.wg.Stop()
+	close(s.errs)
 	s.statsHandler.Close()
 	s.gwCancel()
+	if s.logSampler != nil {
+		// Restarting the daemon samples the logger it was started with
+		s.logSampler.close()
+		s.log, s.logSampler = s.logSampler.unsampled, nil
+	}
 	s.httpSrv = nil
 	s.httpSrvNoMTLS = nil
 	s.grpcSrvs = nil
@@ -401,7 +718,7 @@ func (s *Daemon) SetPeers(in []PeerInfo) {
 	copy(peers, in)
 
 	for i, p := range peers {
-		if s.conf.GRPCListenAddress == p.GRPCAddress {
+		if canonicalAddress(s.conf.GRPCListenAddress) == canonicalAddress(p.GRPCAddress) {
 			peers[i].IsOwner = true
 		}
 	}
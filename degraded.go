@@ -0,0 +1,118 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataDegraded is set to the reduced limit in the metadata of a RateLimitResp which was
+// evaluated locally because the owning peer could not be reached, see BehaviorConfig.DegradedErrorPercent.
+const MetadataDegraded = "degraded_limit"
+
+// The number of forwarded requests a window must have before its error rate is considered
+const degradedMinRequests = 10
+
+// degradedTracker measures the error rate of requests forwarded to other peers over
+// `BehaviorConfig.DegradedWindow` sized windows.
+type degradedTracker struct {
+	mutex   sync.Mutex
+	percent int64
+	window  time.Duration
+	log     FieldLogger
+
+	start    time.Time
+	total    int64
+	failed   int64
+	exceeded bool // The error rate of the previous window exceeded the threshold
+	active   bool
+}
+
+func newDegradedTracker(conf BehaviorConfig, log FieldLogger) *degradedTracker {
+	return &degradedTracker{
+		percent: int64(conf.DegradedErrorPercent),
+		window:  conf.DegradedWindow,
+		log:     log,
+		start:   clock.Now(),
+	}
+}
+
+// record counts a forwarded request and returns true if degraded mode is active
+func (t *degradedTracker) record(failed bool) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := clock.Now()
+	if elapsed := now.Sub(t.start); elapsed >= t.window {
+		// A window without any requests does not carry the previous error rate forward
+		t.exceeded = elapsed < 2*t.window && t.exceeds()
+		t.start, t.total, t.failed = now, 0, 0
+	}
+	t.total++
+	if failed {
+		t.failed++
+	}
+
+	active := t.exceeded || t.exceeds()
+	if active != t.active {
+		t.active = active
+		if active {
+			t.log.WithField("error_percent", t.failed*100/t.total).
+				Warn("peer error rate exceeded the threshold; entering degraded mode")
+		} else {
+			t.log.Info("peer error rate recovered; leaving degraded mode")
+		}
+	}
+	return active
+}
+
+func (t *degradedTracker) exceeds() bool {
+	return t.total >= degradedMinRequests && t.failed*100 > t.total*t.percent
+}
+
+// getDegradedRateLimit evaluates a rate limit owned by a peer which could not be reached on
+// this instance, with the limit divided by the number of peers such that the cluster as a whole
+// approximately enforces the original limit. Returns nil if the rate limit could not be evaluated.
+func (s *V1Instance) getDegradedRateLimit(ctx context.Context, c *RateLimitCheck) *RateLimitResp {
+	r := proto.Clone(c.Req).(*RateLimitReq)
+	if peers := int64(len(s.GetPeerList())); peers > 1 {
+		r.Limit /= peers
+	}
+	if r.Limit < 1 {
+		r.Limit = 1
+	}
+	SetBehavior(&r.Behavior, Behavior_GLOBAL, false)
+
+	rl, err := s.getLocalRateLimit(ctx, r, RateLimitReqState{IsOwner: false})
+	if err != nil {
+		s.log.WithContext(ctx).WithError(err).WithField("key", redactKey(s.conf.KeyRedaction, c.Key)).
+			Error("while evaluating rate limit in degraded mode")
+		return nil
+	}
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata[MetadataDegraded] = strconv.FormatInt(r.Limit, 10)
+	metricDegradedCounter.Inc()
+	return rl
+}
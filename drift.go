@@ -0,0 +1,184 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// The max number of rate limits whose drift is tracked by each instance, once full the
+	// rate limit which changed least recently is forgotten.
+	maxDriftKeys = 1_000
+	// Drift is forgotten once the definition of the rate limit has not changed for this long
+	driftRetention = time.Hour
+)
+
+// driftTracker records the rate limits whose definition changed between requests. As the
+// definition in the cache is replaced by the most recent request, conflicting clients
+// otherwise silently take turns deciding the definition of the rate limit.
+type driftTracker struct {
+	mutex  sync.Mutex
+	drifts map[string]*LimitDrift
+}
+
+func newDriftTracker() *driftTracker {
+	return &driftTracker{drifts: make(map[string]*LimitDrift)}
+}
+
+// observe compares the definition of the request with the definition of the cached item and
+// records the drift if they differ. It must be called before the algorithm updates the item.
+func (d *driftTracker) observe(key string, r *RateLimitReq, item *CacheItem) {
+	if !definitionChanged(r, item) {
+		return
+	}
+	previous := itemDefinition(item)
+	current := requestDefinition(r)
+	metricLimitDrift.WithLabelValues(r.Name).Inc()
+	now := epochMillis(clock.Now())
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	drift, ok := d.drifts[key]
+	if !ok {
+		if len(d.drifts) >= maxDriftKeys {
+			d.evictOldest()
+		}
+		drift = &LimitDrift{Name: r.Name, UniqueKey: r.UniqueKey}
+		d.drifts[key] = drift
+	}
+	drift.Previous = previous
+	drift.Current = current
+	drift.Changes++
+	drift.ChangedAt = now
+}
+
+func (d *driftTracker) evictOldest() {
+	var oldest string
+	for key, drift := range d.drifts {
+		if oldest == "" || drift.ChangedAt < d.drifts[oldest].ChangedAt {
+			oldest = key
+		}
+	}
+	delete(d.drifts, oldest)
+}
+
+// list returns a copy of the drift of the rate limits whose name begins with `prefix`
+func (d *driftTracker) list(prefix string) []*LimitDrift {
+	expired := epochMillis(clock.Now().Add(-driftRetention))
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var result []*LimitDrift
+	for key, drift := range d.drifts {
+		if drift.ChangedAt <= expired {
+			delete(d.drifts, key)
+			continue
+		}
+		if strings.HasPrefix(drift.Name, prefix) {
+			result = append(result, proto.Clone(drift).(*LimitDrift))
+		}
+	}
+	return result
+}
+
+// definitionChanged reports whether requestDefinition(r) differs from itemDefinition(item), without
+// allocating either, as it is called on every request
+func definitionChanged(r *RateLimitReq, item *CacheItem) bool {
+	switch v := item.Value.(type) {
+	case *TokenBucketItem:
+		return r.Algorithm != Algorithm_TOKEN_BUCKET || r.Limit != v.Limit || r.Duration != v.Duration
+	case *LeakyBucketItem:
+		return r.Algorithm != Algorithm_LEAKY_BUCKET || r.Limit != v.Limit || r.Duration != v.Duration || r.Burst != v.Burst
+	}
+	return false
+}
+
+func requestDefinition(r *RateLimitReq) *LimitDefinition {
+	def := &LimitDefinition{Limit: r.Limit, Duration: r.Duration, Algorithm: r.Algorithm}
+	if r.Algorithm == Algorithm_LEAKY_BUCKET {
+		def.Burst = r.Burst
+	}
+	return def
+}
+
+func itemDefinition(item *CacheItem) *LimitDefinition {
+	switch v := item.Value.(type) {
+	case *TokenBucketItem:
+		return &LimitDefinition{Limit: v.Limit, Duration: v.Duration, Algorithm: Algorithm_TOKEN_BUCKET}
+	case *LeakyBucketItem:
+		return &LimitDefinition{Limit: v.Limit, Duration: v.Duration, Algorithm: Algorithm_LEAKY_BUCKET, Burst: v.Burst}
+	}
+	return nil
+}
+
+// GetLimitDrift returns the drift of the rate limits owned by every peer in the local data center.
+func (s *V1Instance) GetLimitDrift(ctx context.Context, r *GetLimitDriftReq) (*GetLimitDriftResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetLimitDrift")).ObserveDuration()
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	resp := &GetLimitDriftResp{}
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *GetLimitDriftResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.GetPeerLimitDrift(ctx, r)
+			} else {
+				peerResp, err = peer.GetPeerLimitDrift(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			// Each rate limit is owned by a single peer, as such the drifts do not overlap
+			resp.Drifts = append(resp.Drifts, peerResp.Drifts...)
+		}(peer)
+	}
+	wg.Wait()
+
+	sort.Slice(resp.Drifts, func(i, j int) bool {
+		if resp.Drifts[i].Name != resp.Drifts[j].Name {
+			return resp.Drifts[i].Name < resp.Drifts[j].Name
+		}
+		return resp.Drifts[i].UniqueKey < resp.Drifts[j].UniqueKey
+	})
+	return resp, nil
+}
+
+// GetPeerLimitDrift is called by other peers to collect the drift of the rate limits owned by this peer.
+func (s *V1Instance) GetPeerLimitDrift(ctx context.Context, r *GetLimitDriftReq) (*GetLimitDriftResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeerLimitDrift")).ObserveDuration()
+	return &GetLimitDriftResp{Drifts: s.drift.list(r.NamePrefix)}, nil
+}
@@ -19,6 +19,7 @@ package gubernator
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/errors"
@@ -48,6 +49,11 @@ type EtcdPool struct {
 	log       FieldLogger
 	watcher   etcd.Watcher
 	conf      EtcdPoolConfig
+
+	// The advertised peer and the lease of its key, updated by SetWeight()
+	advertiseMutex sync.Mutex
+	advertise      PeerInfo
+	leaseID        etcd.LeaseID
 }
 
 type EtcdPoolConfig struct {
@@ -68,6 +74,10 @@ type EtcdPoolConfig struct {
 
 	// (Optional) An interface through which logging will occur (Usually *logrus.Entry)
 	Logger FieldLogger
+
+	// (Optional) Called each time the peer fails to register again after its keep alive was lost,
+	// other peers remove it from the pool until it registers again.
+	OnError func(error)
 }
 
 func NewEtcdPool(conf EtcdPoolConfig) (*EtcdPool, error) {
@@ -89,13 +99,14 @@ func NewEtcdPool(conf EtcdPoolConfig) (*EtcdPool, error) {
 		cancelCtx: cancel,
 		conf:      conf,
 		ctx:       ctx,
+		advertise: conf.Advertise,
 	}
-	return pool, pool.run(conf.Advertise)
+	return pool, pool.run()
 }
 
-func (e *EtcdPool) run(peer PeerInfo) error {
+func (e *EtcdPool) run() error {
 	// Register our instance with etcd
-	if err := e.register(peer); err != nil {
+	if err := e.register(); err != nil {
 		return err
 	}
 
@@ -218,14 +229,9 @@ func (e *EtcdPool) watch() error {
 	return nil
 }
 
-func (e *EtcdPool) register(peer PeerInfo) error {
-	instanceKey := e.conf.KeyPrefix + peer.GRPCAddress
-	e.log.Infof("Registering peer '%#v' with etcd", peer)
-
-	b, err := json.Marshal(peer)
-	if err != nil {
-		return errors.Wrap(err, "while marshalling PeerInfo")
-	}
+func (e *EtcdPool) register() error {
+	instanceKey := e.conf.KeyPrefix + e.conf.Advertise.GRPCAddress
+	e.log.Infof("Registering peer '%#v' with etcd", e.conf.Advertise)
 
 	var keepAlive <-chan *etcd.LeaseKeepAliveResponse
 	var lease *etcd.LeaseGrantResponse
@@ -240,10 +246,18 @@ func (e *EtcdPool) register(peer PeerInfo) error {
 			return errors.Wrapf(err, "during grant lease")
 		}
 
+		// Hold the mutex until the key is put, such that SetWeight() cannot put a stale lease
+		e.advertiseMutex.Lock()
+		defer e.advertiseMutex.Unlock()
+		b, err := json.Marshal(e.advertise)
+		if err != nil {
+			return errors.Wrap(err, "while marshalling PeerInfo")
+		}
 		_, err = e.conf.Client.Put(ctx, instanceKey, string(b), etcd.WithLease(lease.ID))
 		if err != nil {
 			return errors.Wrap(err, "during put")
 		}
+		e.leaseID = lease.ID
 
 		if keepAlive, err = e.conf.Client.KeepAlive(e.ctx, lease.ID); err != nil {
 			return err
@@ -254,16 +268,19 @@ func (e *EtcdPool) register(peer PeerInfo) error {
 	var lastKeepAlive clock.Time
 
 	// Attempt to register our instance with etcd
-	if err = register(); err != nil {
+	if err := register(); err != nil {
 		return errors.Wrap(err, "during initial peer registration")
 	}
 
 	e.wg.Until(func(done chan struct{}) bool {
 		// If we have lost our keep alive, register again
 		if keepAlive == nil {
-			if err = register(); err != nil {
+			if err := register(); err != nil {
 				e.log.WithError(err).
 					Error("while attempting to re-register peer")
+				if e.conf.OnError != nil {
+					e.conf.OnError(errors.Wrap(err, "while attempting to re-register peer"))
+				}
 				select {
 				case <-clock.After(backOffTimeout):
 					return true
@@ -314,6 +331,27 @@ func (e *EtcdPool) register(peer PeerInfo) error {
 	return nil
 }
 
+// SetWeight advertises a new weight of this instance to the other peers, which move a share of
+// the key space to or from this instance accordingly. See DaemonConfig.LoadSheddingCPUPercent
+func (e *EtcdPool) SetWeight(weight int) error {
+	e.advertiseMutex.Lock()
+	defer e.advertiseMutex.Unlock()
+
+	e.advertise.Weight = weight
+	b, err := json.Marshal(e.advertise)
+	if err != nil {
+		return errors.Wrap(err, "while marshalling PeerInfo")
+	}
+	ctx, cancel := context.WithTimeout(e.ctx, etcdTimeout)
+	defer cancel()
+	_, err = e.conf.Client.Put(ctx, e.conf.KeyPrefix+e.advertise.GRPCAddress, string(b), etcd.WithLease(e.leaseID))
+	if err != nil {
+		// The key is put with the new weight when the peer registers again
+		return errors.Wrap(err, "during put")
+	}
+	return nil
+}
+
 func (e *EtcdPool) Close() {
 	e.cancelCtx()
 	e.wg.Stop()
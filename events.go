@@ -0,0 +1,172 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+)
+
+type ClusterEventType int
+
+const (
+	// PeerAdded is sent for each peer which joined the cluster, including this instance
+	PeerAdded ClusterEventType = iota
+	// PeerRemoved is sent for each peer which left the cluster
+	PeerRemoved
+	// BecameOwnerOf is sent with the keys of the rate limits in the cache which this instance now owns
+	BecameOwnerOf
+	// LostOwnershipOf is sent with the keys of the rate limits in the cache which another peer now owns
+	LostOwnershipOf
+)
+
+func (t ClusterEventType) String() string {
+	switch t {
+	case PeerAdded:
+		return "PeerAdded"
+	case PeerRemoved:
+		return "PeerRemoved"
+	case BecameOwnerOf:
+		return "BecameOwnerOf"
+	case LostOwnershipOf:
+		return "LostOwnershipOf"
+	}
+	return "Unknown"
+}
+
+// ClusterEvent describes a change to the peers of the cluster, see V1Instance.OnClusterEvent()
+type ClusterEvent struct {
+	Type ClusterEventType
+
+	// The peer which was added or removed. Set for PeerAdded and PeerRemoved
+	Peer PeerInfo
+
+	// The hash keys of the rate limits whose owner changed, see `Config.HashKey`.
+	// Set for BecameOwnerOf and LostOwnershipOf
+	Keys []string
+}
+
+// clusterEvents holds the callbacks registered with OnClusterEvent()
+type clusterEvents struct {
+	mutex     sync.Mutex
+	callbacks map[int]func(ClusterEvent)
+	next      int
+}
+
+func (e *clusterEvents) subscribed() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return len(e.callbacks) != 0
+}
+
+func (e *clusterEvents) send(events []ClusterEvent) {
+	e.mutex.Lock()
+	callbacks := make([]func(ClusterEvent), 0, len(e.callbacks))
+	for _, fn := range e.callbacks {
+		callbacks = append(callbacks, fn)
+	}
+	e.mutex.Unlock()
+
+	for _, ev := range events {
+		for _, fn := range callbacks {
+			fn(ev)
+		}
+	}
+}
+
+// OnClusterEvent registers a callback which is called each time SetPeers() adds or removes a peer,
+// and each time the owner of a rate limit in the cache of this instance changes, such that embedding
+// applications can react to topology changes, IE: pre-warm caches or update their own routing.
+// Ownership is only evaluated for the rate limits in the cache, as such rate limits which are
+// not cached are never reported.
+//
+// Callbacks are called in order from the goroutine which called SetPeers(), as such the callback
+// MUST NOT block or call SetPeers(). Call the returned function to unregister the callback.
+func (s *V1Instance) OnClusterEvent(fn func(ClusterEvent)) (unregister func()) {
+	s.events.mutex.Lock()
+	defer s.events.mutex.Unlock()
+	if s.events.callbacks == nil {
+		s.events.callbacks = make(map[int]func(ClusterEvent))
+	}
+	id := s.events.next
+	s.events.next++
+	s.events.callbacks[id] = fn
+
+	return func() {
+		s.events.mutex.Lock()
+		defer s.events.mutex.Unlock()
+		delete(s.events.callbacks, id)
+	}
+}
+
+// sendClusterEvents compares the previous pickers with the current pickers and sends the
+// resulting events to the callbacks registered with OnClusterEvent()
+func (s *V1Instance) sendClusterEvents(oldLocal PeerPicker, oldRegion RegionPeerPicker) {
+	if !s.events.subscribed() {
+		return
+	}
+
+	current := s.pickers.Load()
+	newLocal, newRegion := current.local, current.region
+
+	var events []ClusterEvent
+	oldPeers := peersByAddress(oldLocal, oldRegion)
+	newPeers := peersByAddress(newLocal, newRegion)
+	for addr, info := range newPeers {
+		if _, ok := oldPeers[addr]; !ok {
+			events = append(events, ClusterEvent{Type: PeerAdded, Peer: info})
+		}
+	}
+	for addr, info := range oldPeers {
+		if _, ok := newPeers[addr]; !ok {
+			events = append(events, ClusterEvent{Type: PeerRemoved, Peer: info})
+		}
+	}
+
+	var gained, lost []string
+	for item := range s.workerPool.Each(context.Background()) {
+		wasOwner, isOwner := ownedBy(oldLocal, item.Key), ownedBy(newLocal, item.Key)
+		switch {
+		case isOwner && !wasOwner:
+			gained = append(gained, item.Key)
+		case wasOwner && !isOwner:
+			lost = append(lost, item.Key)
+		}
+	}
+	if len(gained) != 0 {
+		events = append(events, ClusterEvent{Type: BecameOwnerOf, Keys: gained})
+	}
+	if len(lost) != 0 {
+		events = append(events, ClusterEvent{Type: LostOwnershipOf, Keys: lost})
+	}
+
+	s.events.send(events)
+}
+
+func peersByAddress(local PeerPicker, region RegionPeerPicker) map[string]PeerInfo {
+	peers := make(map[string]PeerInfo)
+	for _, p := range append(local.Peers(), region.Peers()...) {
+		peers[p.Info().GRPCAddress] = p.Info()
+	}
+	return peers
+}
+
+// ownedBy returns true if the picker chooses this instance as the owner of the key
+func ownedBy(picker PeerPicker, key string) bool {
+	peer, err := picker.Get(key)
+	return err == nil && peer.Info().IsOwner
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestObserveWithExemplar(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	span := func(flags trace.TraceFlags) context.Context {
+		return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: flags,
+		}))
+	}
+	// exemplars returns the exemplars of the histogram buckets
+	exemplars := func(h prometheus.Histogram) []*dto.Exemplar {
+		var m dto.Metric
+		require.NoError(t, h.Write(&m))
+		var result []*dto.Exemplar
+		for _, b := range m.GetHistogram().GetBucket() {
+			if b.Exemplar != nil {
+				result = append(result, b.Exemplar)
+			}
+		}
+		return result
+	}
+
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test", Buckets: []float64{1, 2}})
+	observeWithExemplar(context.Background(), h, 0.5)
+	observeWithExemplar(span(0), h, 0.5)
+	assert.Empty(t, exemplars(h))
+
+	observeWithExemplar(span(trace.FlagsSampled), h, 1.5)
+	e := exemplars(h)
+	require.Len(t, e, 1)
+	assert.Equal(t, 1.5, e[0].GetValue())
+	require.Len(t, e[0].Label, 1)
+	assert.Equal(t, "trace_id", e[0].Label[0].GetName())
+	assert.Equal(t, traceID.String(), e[0].Label[0].GetValue())
+
+	// Summaries do not support exemplars, the value is still observed
+	s := prometheus.NewSummary(prometheus.SummaryOpts{Name: "test"})
+	observeWithExemplar(span(trace.FlagsSampled), s, 1.5)
+	var m dto.Metric
+	require.NoError(t, s.Write(&m))
+	assert.Equal(t, uint64(1), m.GetSummary().GetSampleCount())
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// fanOutPool evaluates the rate limits of the batches forwarded by other peers on long-lived
+// goroutines, `Config.FanOutWorkers` for each worker of the WorkerPool. A rate limit is queued to
+// the goroutines of the worker which owns its key, such that a shard's rate limits, its worker
+// channel and its metrics are only touched by the same few goroutines instead of by every core,
+// as happens when each batch starts goroutines of its own.
+//
+// Go does not pin goroutines to cores, the affinity is between goroutines and cache shards. The
+// scheduler tends to keep a goroutine on the core it last ran on, which is where the benefit on
+// machines with many cores comes from.
+type fanOutPool struct {
+	shards []*fanOutShard
+	pick   func(key string) int
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+type fanOutShard struct {
+	queue  chan func()
+	busy   prometheus.Gauge
+	queued prometheus.Gauge
+}
+
+func newFanOutPool(workers *WorkerPool, perShard, queueSize int) *fanOutPool {
+	p := &fanOutPool{
+		shards: make([]*fanOutShard, len(workers.workers)),
+		pick:   workers.shard,
+		done:   make(chan struct{}),
+	}
+	for i, w := range workers.workers {
+		shard := &fanOutShard{
+			queue:  make(chan func(), queueSize),
+			busy:   metricFanOutBusy.WithLabelValues(w.name),
+			queued: metricFanOutQueue.WithLabelValues(w.name),
+		}
+		p.shards[i] = shard
+		metricFanOutWorkers.WithLabelValues(w.name).Set(float64(perShard))
+		for j := 0; j < perShard; j++ {
+			p.wg.Add(1)
+			go p.run(shard)
+		}
+	}
+	return p
+}
+
+func (p *fanOutPool) run(shard *fanOutShard) {
+	defer p.wg.Done()
+	for {
+		select {
+		case fn := <-shard.queue:
+			shard.queued.Dec()
+			shard.busy.Inc()
+			fn()
+			shard.busy.Dec()
+		case <-p.done:
+			// Run what was queued before the pool closed, such that no caller waits forever
+			for {
+				select {
+				case fn := <-shard.queue:
+					shard.queued.Dec()
+					fn()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// do queues `fn` to the goroutines of the shard which owns `key`, waiting for room in the queue.
+// Returns an error without calling `fn` if the context is cancelled or the pool closed first.
+func (p *fanOutPool) do(ctx context.Context, key string, fn func()) error {
+	shard := p.shards[p.pick(key)]
+	shard.queued.Inc()
+	select {
+	case shard.queue <- fn:
+		return nil
+	case <-ctx.Done():
+		shard.queued.Dec()
+		return ctx.Err()
+	case <-p.done:
+		shard.queued.Dec()
+		return errors.New("fan out pool is closed")
+	}
+}
+
+func (p *fanOutPool) close() {
+	close(p.done)
+	p.wg.Wait()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FaultConfig injects faults into a running instance such that client behavior can be validated
+// under partial failure, IE: during a game day against a staging cluster.
+// DO NOT enable in production.
+type FaultConfig struct {
+	// (Optional) Artificial latency added to every RPC made to other peers
+	PeerLatency time.Duration
+
+	// (Optional) The percentage (0-100) of RPCs made to other peers which fail with codes.Unavailable
+	// without being sent
+	PeerDropPercent int
+
+	// (Optional) The percentage (0-100) of peer lookups which return a random peer instead of the
+	// owner of the rate limit, simulating peers which disagree on ownership
+	OwnershipFlapPercent int
+}
+
+func (f *FaultConfig) validate() error {
+	if f.PeerLatency < 0 {
+		return errors.New("Faults.PeerLatency cannot be negative")
+	}
+	if f.PeerDropPercent < 0 || f.PeerDropPercent > 100 {
+		return errors.New("Faults.PeerDropPercent must be between 0 and 100")
+	}
+	if f.OwnershipFlapPercent < 0 || f.OwnershipFlapPercent > 100 {
+		return errors.New("Faults.OwnershipFlapPercent must be between 0 and 100")
+	}
+	return nil
+}
+
+// flapOwnership returns true if a peer lookup should return a random peer
+func (f *FaultConfig) flapOwnership() bool {
+	return f != nil && chance(f.OwnershipFlapPercent)
+}
+
+// unaryInterceptor returns a client interceptor which injects latency and dropped RPCs into
+// requests made to peers.
+func (f *FaultConfig) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+
+		if f.PeerLatency > 0 {
+			select {
+			case <-clock.After(f.PeerLatency):
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+
+		if chance(f.PeerDropPercent) {
+			metricCheckErrorCounter.WithLabelValues("Fault injected").Inc()
+			return status.Errorf(codes.Unavailable, "fault injection: dropped RPC '%s' to '%s'", method, cc.Target())
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func chance(percent int) bool {
+	return percent > 0 && rand.Intn(100) < percent
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// FederationConfig proxies the rate limits of designated namespaces from an isolated cluster to a
+// home cluster, such that a handful of limits, IE: a partner's worldwide API quota, are enforced
+// once across every cluster while every other rate limit remains local to the cluster.
+type FederationConfig struct {
+	// (Required) The GRPC address of the home cluster, IE: a load balancer in front of its instances.
+	// Federation is disabled if empty. The instances of the home cluster must not set this address.
+	HomeAddress string
+
+	// (Required) Rate limits whose name begins with one of these prefixes are proxied to the home cluster
+	Prefixes []string
+
+	// (Optional) The TLS config used to connect to the home cluster. Defaults to no TLS
+	TLS *tls.Config
+
+	// (Optional) The max time to wait for the home cluster to respond. Defaults to 500ms
+	Timeout time.Duration
+}
+
+// The metadata key set on requests proxied to the home cluster, such that the home
+// cluster answers them itself even if it was mistakenly configured to federate them.
+const federatedMetadataKey = "gubernator-federated"
+
+func (f *FederationConfig) validate() error {
+	if f.HomeAddress == "" {
+		return nil
+	}
+	if len(f.Prefixes) == 0 {
+		return errors.New("Federation.HomeAddress requires Federation.Prefixes")
+	}
+	for _, p := range f.Prefixes {
+		if p == "" {
+			return errors.New("Federation.Prefixes cannot be empty")
+		}
+	}
+	if f.Timeout < 0 {
+		return errors.New("Federation.Timeout cannot be negative")
+	}
+	setter.SetDefault(&f.Timeout, 500*time.Millisecond)
+	return nil
+}
+
+type federation struct {
+	conf   FederationConfig
+	conn   *grpc.ClientConn
+	client V1Client
+}
+
+// newFederation connects to the home cluster in a non-blocking fashion
+func newFederation(conf FederationConfig) (*federation, error) {
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if conf.TLS != nil {
+		creds = credentials.NewTLS(conf.TLS)
+	}
+	conn, err := grpc.Dial(conf.HomeAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "while dialing home cluster '%s'", conf.HomeAddress)
+	}
+	return &federation{conf: conf, conn: conn, client: NewV1Client(conn)}, nil
+}
+
+// proxies returns true if the rate limit must be proxied to the home cluster. Requests which
+// were proxied by another cluster are never proxied again.
+func (f *federation) proxies(ctx context.Context, name string) bool {
+	if f == nil {
+		return false
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(federatedMetadataKey)) != 0 {
+		return false
+	}
+	for _, p := range f.conf.Prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxy sends the requests to the home cluster in a single batch, sending the response for each
+// request to `asyncCh` with the index from `idxs`.
+func (f *federation) proxy(ctx context.Context, reqs []*RateLimitReq, idxs []int, asyncCh chan AsyncResp, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ctx, cancel := context.WithTimeout(metadata.AppendToOutgoingContext(ctx, federatedMetadataKey, "true"), f.conf.Timeout)
+	defer cancel()
+
+	resp, err := f.client.GetRateLimits(ctx, &GetRateLimitsReq{Requests: reqs})
+	if err == nil && len(resp.Responses) != len(reqs) {
+		err = errors.Errorf("expected %d responses but got %d", len(reqs), len(resp.Responses))
+	}
+	if err != nil {
+		metricFederationCounter.WithLabelValues("error").Add(float64(len(reqs)))
+		err = errors.Wrapf(err, "while proxying to home cluster '%s'", f.conf.HomeAddress)
+		for _, idx := range idxs {
+			asyncCh <- AsyncResp{Idx: idx, Resp: &RateLimitResp{Error: err.Error()}}
+		}
+		return
+	}
+
+	metricFederationCounter.WithLabelValues("proxied").Add(float64(len(reqs)))
+	for i, idx := range idxs {
+		asyncCh <- AsyncResp{Idx: idx, Resp: resp.Responses[i]}
+	}
+}
+
+func (f *federation) close() {
+	if f != nil {
+		_ = f.conn.Close()
+	}
+}
@@ -16,9 +16,12 @@ limitations under the License.
 
 package gubernator
 
+import "fmt"
+
 const (
 	FlagOSMetrics MetricFlags = 1 << iota
 	FlagGolangMetrics
+	FlagGRPCMetrics
 )
 
 type MetricFlags int64
@@ -36,7 +39,7 @@ func (f *MetricFlags) Has(flag MetricFlags) bool {
 	return *f&flag != 0
 }
 
-func getEnvMetricFlags(log FieldLogger, name string) MetricFlags {
+func getEnvMetricFlags(env *envParser, name string) MetricFlags {
 	flags := getEnvSlice(name)
 	if len(flags) == 0 {
 		return 0
@@ -49,8 +52,10 @@ func getEnvMetricFlags(log FieldLogger, name string) MetricFlags {
 			result.Set(FlagOSMetrics, true)
 		case "golang":
 			result.Set(FlagGolangMetrics, true)
+		case "grpc":
+			result.Set(FlagGRPCMetrics, true)
 		default:
-			log.Errorf("invalid flag '%s' for '%s' valid options are ['os', 'golang']", f, name)
+			env.invalid(fmt.Errorf("invalid flag '%s'", f), "while parsing '%s'; valid options are ['os', 'golang', 'grpc']", name)
 		}
 	}
 	return result
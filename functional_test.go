@@ -19,11 +19,19 @@ package gubernator_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"flag"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -39,16 +47,30 @@ import (
 	"github.com/mailgun/holster/v4/testutil"
 	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/maps"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+	"google.golang.org/grpc/status"
 	json "google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 // Setup and shutdown the mock gubernator cluster for the entire test suite
 func TestMain(m *testing.M) {
+	// The fuzzing workers only run the fuzz targets, which do not need the cluster, and would
+	// fail to listen on the ports of the cluster started by the coordinating process.
+	flag.Parse()
+	if f := flag.Lookup("test.fuzzworker"); f != nil && f.Value.String() == "true" {
+		os.Exit(m.Run())
+	}
+
 	err := startGubernator()
 	if err != nil {
 		fmt.Println(err)
@@ -474,1279 +496,5051 @@ func TestTokenBucketRequestMoreThanAvailable(t *testing.T) {
 	sendHit(guber.Status_OVER_LIMIT, 0, 1)
 }
 
-func TestLeakyBucket(t *testing.T) {
+func TestRateLimitGroup(t *testing.T) {
 	defer clock.Freeze(clock.Now()).Unfreeze()
 
-	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
 	require.NoError(t, err)
 
-	tests := []struct {
-		Name      string
-		Hits      int64
-		Remaining int64
-		Status    guber.Status
-		Sleep     clock.Duration
-	}{
-		{
-			Name:      "first hit",
-			Hits:      1,
-			Remaining: 9,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
-		{
-			Name:      "second hit; no leak",
-			Hits:      1,
-			Remaining: 8,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
-		{
-			Name:      "third hit; no leak",
-			Hits:      1,
-			Remaining: 7,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Millisecond * 1500,
-		},
-		{
-			Name:      "should leak one hit 3 seconds after first hit",
-			Hits:      0,
-			Remaining: 8,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 3,
-		},
-		{
-			Name:      "3 Seconds later we should have leaked another hit",
-			Hits:      0,
-			Remaining: 9,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "max out our bucket and sleep for 3 seconds",
-			Hits:      9,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "should be over the limit",
-			Hits:      1,
-			Remaining: 0,
-			Status:    guber.Status_OVER_LIMIT,
-			Sleep:     clock.Second * 3,
-		},
-		{
-			Name:      "should have leaked 1 hit",
-			Hits:      0,
-			Remaining: 1,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 60,
-		},
-		{
-			Name:      "should max out the limit",
-			Hits:      0,
-			Remaining: 10,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 60,
-		},
-		{
-			Name:      "should use up the limit and wait until 1 second before duration period",
-			Hits:      10,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 29,
-		},
-		{
-			Name:      "should use up all hits one second before duration period",
-			Hits:      9,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 3,
-		},
-		{
-			Name:      "only have 1 hit remaining",
-			Hits:      1,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
+	sendGroup := func(status guber.Status, userRemain, orgRemain int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimitGroup(ctx, &guber.GetRateLimitGroupReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_rate_limit_group",
+					UniqueKey: "user:1234",
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Duration:  guber.Minute,
+					Hits:      1,
+					Limit:     5,
+				},
+				{
+					Name:      "test_rate_limit_group",
+					UniqueKey: "org:5678",
+					Algorithm: guber.Algorithm_LEAKY_BUCKET,
+					Duration:  guber.Minute,
+					Hits:      1,
+					Limit:     2,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Responses, 2)
+		assert.Equal(t, status, resp.Status)
+		assert.Equal(t, "", resp.Responses[0].Error)
+		assert.Equal(t, "", resp.Responses[1].Error)
+		assert.Equal(t, userRemain, resp.Responses[0].Remaining)
+		assert.Equal(t, orgRemain, resp.Responses[1].Remaining)
 	}
 
-	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      "test_leaky_bucket",
-						UniqueKey: "account:1234",
-						Algorithm: guber.Algorithm_LEAKY_BUCKET,
-						Duration:  guber.Second * 30,
-						Hits:      test.Hits,
-						Limit:     10,
-					},
-				},
-			})
-			require.NoError(t, err)
-			require.Len(t, resp.Responses, 1)
+	sendGroup(guber.Status_UNDER_LIMIT, 4, 1)
+	sendGroup(guber.Status_UNDER_LIMIT, 3, 0)
 
-			rl := resp.Responses[0]
+	// The org limit is exhausted, the user limit must not be consumed.
+	sendGroup(guber.Status_OVER_LIMIT, 3, 0)
+	sendGroup(guber.Status_OVER_LIMIT, 3, 0)
 
-			assert.Equal(t, test.Status, rl.Status)
-			assert.Equal(t, test.Remaining, rl.Remaining)
-			assert.Equal(t, int64(10), rl.Limit)
-			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
-			clock.Advance(test.Sleep)
-		})
-	}
+	t.Run("Empty group", func(t *testing.T) {
+		_, err := client.GetRateLimitGroup(context.Background(), &guber.GetRateLimitGroupReq{})
+		require.Error(t, err)
+	})
 }
 
-func TestLeakyBucketWithBurst(t *testing.T) {
-	defer clock.Freeze(clock.Now()).Unfreeze()
-
-	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+func TestReservation(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
 	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	tests := []struct {
-		Name      string
-		Hits      int64
-		Remaining int64
-		Status    guber.Status
-		Sleep     clock.Duration
-	}{
-		{
-			Name:      "first hit",
-			Hits:      1,
-			Remaining: 19,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
-		{
-			Name:      "second hit; no leak",
-			Hits:      1,
-			Remaining: 18,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
-		{
-			Name:      "third hit; no leak",
-			Hits:      1,
-			Remaining: 17,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Millisecond * 1500,
-		},
-		{
-			Name:      "should leak one hit 3 seconds after first hit",
-			Hits:      0,
-			Remaining: 18,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 3,
-		},
-		{
-			Name:      "3 Seconds later we should have leaked another hit",
-			Hits:      0,
-			Remaining: 19,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "max out our bucket and sleep for 3 seconds",
-			Hits:      19,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "should be over the limit",
-			Hits:      1,
-			Remaining: 0,
-			Status:    guber.Status_OVER_LIMIT,
-			Sleep:     clock.Second * 3,
-		},
-		{
-			Name:      "should have leaked 1 hit",
-			Hits:      0,
-			Remaining: 1,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second * 60,
-		},
-		{
-			Name:      "should max out remaining",
-			Hits:      0,
-			Remaining: 20,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Second,
-		},
+	rateLimit := func(hits int64) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      "test_reservation",
+			UniqueKey: "account:1234",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Hits:      hits,
+			Limit:     10,
+		}
 	}
-
-	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      "test_leaky_bucket_with_burst",
-						UniqueKey: "account:1234",
-						Algorithm: guber.Algorithm_LEAKY_BUCKET,
-						Duration:  guber.Second * 30,
-						Hits:      test.Hits,
-						Limit:     10,
-						Burst:     20,
-					},
-				},
-			})
-			require.NoError(t, err)
-			require.Len(t, resp.Responses, 1)
-
-			rl := resp.Responses[0]
-
-			assert.Equal(t, test.Status, rl.Status)
-			assert.Equal(t, test.Remaining, rl.Remaining)
-			assert.Equal(t, int64(10), rl.Limit)
-			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
-			clock.Advance(test.Sleep)
+	reserve := func(hits, ttl int64) *guber.ReserveRateLimitResp {
+		resp, err := client.ReserveRateLimit(ctx, &guber.ReserveRateLimitReq{RateLimit: rateLimit(hits), Ttl: ttl})
+		require.NoError(t, err)
+		return resp
+	}
+	release := func(id string) *guber.ReservationReq {
+		return &guber.ReservationReq{Name: "test_reservation", UniqueKey: "account:1234", ReservationId: id}
+	}
+	remaining := func() int64 {
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{rateLimit(0)},
 		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0].Remaining
 	}
-}
 
-func TestLeakyBucketGregorian(t *testing.T) {
-	defer clock.Freeze(clock.Now()).Unfreeze()
-
-	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	// Committed hits are not returned
+	resp := reserve(3, guber.Minute)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.RateLimit.Status)
+	assert.Equal(t, int64(7), resp.RateLimit.Remaining)
+	require.NotEmpty(t, resp.ReservationId)
+	commit, err := client.CommitReservation(ctx, release(resp.ReservationId))
 	require.NoError(t, err)
+	assert.Equal(t, int64(3), commit.Hits)
+	assert.Equal(t, int64(7), remaining())
 
-	tests := []struct {
-		Name      string
-		Hits      int64
-		Remaining int64
-		Status    guber.Status
-		Sleep     clock.Duration
-	}{
-		{
-			Name:      "first hit",
-			Hits:      1,
-			Remaining: 59,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Millisecond * 500,
-		},
-		{
-			Name:      "second hit; no leak",
-			Hits:      1,
-			Remaining: 58,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Millisecond * 1200,
-		},
-		{
-			Name:      "third hit; leak one hit",
-			Hits:      1,
-			Remaining: 58,
-			Status:    guber.Status_UNDER_LIMIT,
-		},
-	}
+	// A reservation may only be released once
+	_, err = client.CancelReservation(ctx, release(resp.ReservationId))
+	assert.Equal(t, codes.NotFound, status.Code(err))
 
-	// Truncate to the nearest minute
-	now := clock.Now()
-	now = now.Truncate(1 * time.Minute)
-	// So we don't start on the minute boundary
-	now = now.Add(time.Millisecond * 100)
-	name := t.Name()
-	key := guber.RandomString(10)
+	// Canceled hits are returned
+	resp = reserve(5, guber.Minute)
+	assert.Equal(t, int64(2), resp.RateLimit.Remaining)
+	_, err = client.CancelReservation(ctx, release(resp.ReservationId))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), remaining())
 
-	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      name,
-						UniqueKey: key,
-						Behavior:  guber.Behavior_DURATION_IS_GREGORIAN,
-						Algorithm: guber.Algorithm_LEAKY_BUCKET,
-						Duration:  guber.GregorianMinutes,
-						Hits:      test.Hits,
-						Limit:     60,
-					},
-				},
-			})
-			require.NoError(t, err)
+	// Nothing is reserved when over the limit
+	resp = reserve(8, guber.Minute)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.RateLimit.Status)
+	assert.Empty(t, resp.ReservationId)
 
-			rl := resp.Responses[0]
-			assert.Equal(t, test.Status, rl.Status)
-			assert.Equal(t, test.Remaining, rl.Remaining)
-			assert.Equal(t, int64(60), rl.Limit)
-			assert.Greater(t, rl.ResetTime, now.Unix())
-			clock.Advance(test.Sleep)
-		})
-	}
+	// Expired hits are returned
+	resp = reserve(7, 100)
+	assert.Equal(t, int64(0), resp.RateLimit.Remaining)
+	testutil.UntilPass(t, 20, clock.Millisecond*200, func(t testutil.TestingT) {
+		assert.Equal(t, int64(7), remaining())
+	})
+	_, err = client.CommitReservation(ctx, release(resp.ReservationId))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := client.ReserveRateLimit(ctx, &guber.ReserveRateLimitReq{RateLimit: rateLimit(1)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		global := rateLimit(1)
+		global.Behavior = guber.Behavior_GLOBAL
+		_, err = client.ReserveRateLimit(ctx, &guber.ReserveRateLimitReq{RateLimit: global, Ttl: guber.Minute})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.CommitReservation(ctx, release(""))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
 }
 
-func TestLeakyBucketNegativeHits(t *testing.T) {
-	defer clock.Freeze(clock.Now()).Unfreeze()
+func TestTransactRateLimits(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	requests := func(sourceHits, destHits int64) []*guber.RateLimitReq {
+		return []*guber.RateLimitReq{
+			{
+				Name:      "test_transact_rate_limits",
+				UniqueKey: "source:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Hits:      sourceHits,
+				Limit:     5,
+			},
+			{
+				Name:      "test_transact_rate_limits",
+				UniqueKey: "destination:5678",
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Minute,
+				Hits:      destHits,
+				Limit:     3,
+			},
+		}
+	}
+	remaining := func() (int64, int64) {
+		reqs := requests(0, 0)
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		return resp.Responses[0].Remaining, resp.Responses[1].Remaining
+	}
+
+	resp, err := client.TransactRateLimits(ctx, &guber.TransactRateLimitsReq{Requests: requests(2, 2)})
 	require.NoError(t, err)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Status)
+	require.Len(t, resp.Responses, 2)
+	assert.Equal(t, "", resp.Responses[0].Error)
+	assert.Equal(t, "", resp.Responses[1].Error)
+	source, dest := remaining()
+	assert.Equal(t, int64(3), source)
+	assert.Equal(t, int64(1), dest)
 
-	tests := []struct {
-		Name      string
-		Hits      int64
-		Remaining int64
-		Status    guber.Status
-		Sleep     clock.Duration
-	}{
-		{
-			Name:      "first hit",
-			Hits:      1,
-			Remaining: 9,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "can increase remaining",
-			Hits:      -1,
-			Remaining: 10,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "remaining should be zero",
-			Hits:      10,
-			Remaining: 0,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
-		{
-			Name:      "can append one to remaining when remaining is zero",
-			Hits:      -1,
-			Remaining: 1,
-			Status:    guber.Status_UNDER_LIMIT,
-			Sleep:     clock.Duration(0),
-		},
+	// The destination has no room, the hits held on the source must be returned
+	resp, err = client.TransactRateLimits(ctx, &guber.TransactRateLimitsReq{Requests: requests(2, 2)})
+	require.NoError(t, err)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Status)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Responses[1].Status)
+	source, dest = remaining()
+	assert.Equal(t, int64(3), source)
+	assert.Equal(t, int64(1), dest)
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := client.TransactRateLimits(ctx, &guber.TransactRateLimitsReq{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = client.TransactRateLimits(ctx, &guber.TransactRateLimitsReq{Requests: requests(1, 0)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		global := requests(1, 1)
+		global[0].Behavior = guber.Behavior_GLOBAL
+		_, err = client.TransactRateLimits(ctx, &guber.TransactRateLimitsReq{Requests: global})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetRateLimitHierarchy(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
+
+	level := func(key string, limit int64) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      "test_rate_limit_hierarchy",
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     limit,
+		}
+	}
+	hierarchy := func(project string, order guber.HierarchyOrder, projectLimit int64) *guber.GetRateLimitHierarchyReq {
+		return &guber.GetRateLimitHierarchyReq{
+			Levels: []*guber.RateLimitReq{level("org:acme", 5), level(project, projectLimit)},
+			Hits:   2,
+			Order:  order,
+		}
+	}
+	remaining := func(key string, limit int64) int64 {
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{Requests: []*guber.RateLimitReq{level(key, limit)}})
+		require.NoError(t, err)
+		return resp.Responses[0].Remaining
 	}
 
-	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      "test_leaky_bucket_negative",
-						UniqueKey: "account:12345",
-						Algorithm: guber.Algorithm_LEAKY_BUCKET,
-						Duration:  guber.Second * 30,
-						Hits:      test.Hits,
-						Limit:     10,
-					},
-				},
-			})
-			require.NoError(t, err)
-			require.Len(t, resp.Responses, 1)
+	// The projects share the cap of the organization
+	resp, err := client.GetRateLimitHierarchy(ctx, hierarchy("org:acme/project:web", guber.HierarchyOrder_PARENT_FIRST, 10))
+	require.NoError(t, err)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Status)
+	require.Len(t, resp.Responses, 2)
+	assert.Equal(t, int64(3), resp.Responses[0].Remaining)
+	assert.Equal(t, int64(8), resp.Responses[1].Remaining)
 
-			rl := resp.Responses[0]
+	resp, err = client.GetRateLimitHierarchy(ctx, hierarchy("org:acme/project:api", guber.HierarchyOrder_PARENT_FIRST, 10))
+	require.NoError(t, err)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Status)
+	assert.Equal(t, int64(1), resp.Responses[0].Remaining)
 
-			assert.Equal(t, test.Status, rl.Status)
-			assert.Equal(t, test.Remaining, rl.Remaining)
-			assert.Equal(t, int64(10), rl.Limit)
-			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
-			clock.Advance(test.Sleep)
+	// The organization has no room, the project is not consumed from but reports its state
+	resp, err = client.GetRateLimitHierarchy(ctx, hierarchy("org:acme/project:api", guber.HierarchyOrder_PARENT_FIRST, 10))
+	require.NoError(t, err)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Status)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Responses[0].Status)
+	assert.Equal(t, int64(8), resp.Responses[1].Remaining)
+	assert.Equal(t, int64(1), remaining("org:acme", 5))
+	assert.Equal(t, int64(8), remaining("org:acme/project:api", 10))
+
+	// The project has no room, the hits are never held on the organization
+	resp, err = client.GetRateLimitHierarchy(ctx, hierarchy("org:acme/project:batch", guber.HierarchyOrder_CHILD_FIRST, 1))
+	require.NoError(t, err)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Status)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Responses[1].Status)
+	assert.Equal(t, int64(1), resp.Responses[0].Remaining)
+	assert.Equal(t, int64(1), remaining("org:acme", 5))
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := client.GetRateLimitHierarchy(ctx, &guber.GetRateLimitHierarchyReq{Hits: 1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		r := hierarchy("org:acme/project:web", guber.HierarchyOrder_PARENT_FIRST, 10)
+		r.Hits = 0
+		_, err = client.GetRateLimitHierarchy(ctx, r)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		r = hierarchy("org:acme/project:web", guber.HierarchyOrder_PARENT_FIRST, 10)
+		r.Levels[0].Behavior = guber.Behavior_GLOBAL
+		_, err = client.GetRateLimitHierarchy(ctx, r)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestRefundRateLimit(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
+
+	hit := func(client guber.V1Client, hits int64) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_refund",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Behavior:  guber.Behavior_REFUNDABLE,
+				Duration:  guber.Minute,
+				Hits:      hits,
+				Limit:     10,
+			}},
 		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+	refund := func(id string) *guber.RefundReq {
+		return &guber.RefundReq{Name: "test_refund", UniqueKey: "account:1234", RefundId: id}
 	}
-}
 
-func TestLeakyBucketRequestMoreThanAvailable(t *testing.T) {
-	// Freeze time so we don't leak during the test
-	defer clock.Freeze(clock.Now()).Unfreeze()
+	rl := hit(client, 4)
+	assert.Equal(t, int64(6), rl.Remaining)
+	id := rl.Metadata[guber.MetadataRefundID]
+	require.NotEmpty(t, id)
+	rl = hit(client, 3)
+	assert.Equal(t, int64(3), rl.Remaining)
+
+	// The refunded hits are returned to the rate limit
+	resp, err := client.RefundRateLimit(ctx, refund(id))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), resp.Hits)
+	assert.Equal(t, int64(7), hit(client, 0).Remaining)
+
+	// Hits may only be refunded once
+	_, err = client.RefundRateLimit(ctx, refund(id))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Nothing is recorded when over the limit or when no hits are applied
+	rl = hit(client, 8)
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Empty(t, rl.Metadata[guber.MetadataRefundID])
+	assert.Empty(t, hit(client, 0).Metadata[guber.MetadataRefundID])
+
+	// A refund id is not a reservation id
+	rl = hit(client, 1)
+	_, err = client.CancelReservation(ctx, &guber.ReservationReq{
+		Name:          "test_refund",
+		UniqueKey:     "account:1234",
+		ReservationId: rl.Metadata[guber.MetadataRefundID],
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	t.Run("Refund window", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{
+			Behaviors: guber.BehaviorConfig{RefundWindow: clock.Millisecond * 100},
+		})
+		defer srv.Close()
+		srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
 
+		rl := hit(client, 5)
+		require.NotEmpty(t, rl.Metadata[guber.MetadataRefundID])
+		clock.Sleep(clock.Millisecond * 200)
+
+		// The hits are kept once the refund window has passed
+		_, err = client.RefundRateLimit(ctx, refund(rl.Metadata[guber.MetadataRefundID]))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, int64(5), hit(client, 0).Remaining)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := client.RefundRateLimit(ctx, refund(""))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
 	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
 	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	sendHit := func(status guber.Status, remain int64, hits int64) *guber.RateLimitResp {
-		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-		defer cancel()
+	hit := func(client guber.V1Client, key, idempotencyKey string, hits int64) *guber.RateLimitResp {
 		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      "test_leaky_more_than_available",
-					UniqueKey: "account:123456",
-					Algorithm: guber.Algorithm_LEAKY_BUCKET,
-					Duration:  guber.Millisecond * 1000,
-					Hits:      hits,
-					Limit:     2000,
-				},
-			},
+			Requests: []*guber.RateLimitReq{{
+				Name:           "test_idempotency_key",
+				UniqueKey:      key,
+				Algorithm:      guber.Algorithm_TOKEN_BUCKET,
+				Duration:       guber.Minute,
+				Hits:           hits,
+				Limit:          10,
+				IdempotencyKey: idempotencyKey,
+			}},
 		})
 		require.NoError(t, err)
-		assert.Equal(t, "", resp.Responses[0].Error)
-		assert.Equal(t, status, resp.Responses[0].Status)
-		assert.Equal(t, remain, resp.Responses[0].Remaining)
-		assert.Equal(t, int64(2000), resp.Responses[0].Limit)
+		require.Equal(t, "", resp.Responses[0].Error)
 		return resp.Responses[0]
 	}
 
-	// Use half of the bucket
-	sendHit(guber.Status_UNDER_LIMIT, 1000, 1000)
+	rl := hit(client, "account:1234", "req-1", 3)
+	assert.Equal(t, int64(7), rl.Remaining)
+	assert.Empty(t, rl.Metadata[guber.MetadataIdempotentReplay])
 
-	// Ask for more than the rate limit has and the remainder is still 1000.
-	// See NOTE in algorithms.go
-	sendHit(guber.Status_OVER_LIMIT, 1000, 1500)
+	// A retry returns the original response without applying the hits again
+	rl = hit(client, "account:1234", "req-1", 3)
+	assert.Equal(t, int64(7), rl.Remaining)
+	assert.Equal(t, "true", rl.Metadata[guber.MetadataIdempotentReplay])
 
-	// Now other clients can ask for some of the remaining until we hit our limit
-	sendHit(guber.Status_UNDER_LIMIT, 500, 500)
-	sendHit(guber.Status_UNDER_LIMIT, 100, 400)
-	sendHit(guber.Status_UNDER_LIMIT, 0, 100)
-	sendHit(guber.Status_OVER_LIMIT, 0, 1)
+	// Other idempotency keys and requests without one apply their hits
+	assert.Equal(t, int64(5), hit(client, "account:1234", "req-2", 2).Remaining)
+	assert.Equal(t, int64(4), hit(client, "account:1234", "", 1).Remaining)
+	assert.Equal(t, int64(3), hit(client, "account:1234", "", 1).Remaining)
+
+	// Idempotency keys are scoped to the rate limit
+	rl = hit(client, "account:5678", "req-1", 1)
+	assert.Equal(t, int64(9), rl.Remaining)
+	assert.Empty(t, rl.Metadata[guber.MetadataIdempotentReplay])
+
+	t.Run("Idempotency window", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{
+			Behaviors: guber.BehaviorConfig{IdempotencyWindow: clock.Millisecond * 100},
+		})
+		defer srv.Close()
+		srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), hit(client, "account:1234", "req-1", 5).Remaining)
+		clock.Sleep(clock.Millisecond * 200)
+
+		// The hits are applied again once the idempotency window has passed
+		rl := hit(client, "account:1234", "req-1", 5)
+		assert.Equal(t, int64(0), rl.Remaining)
+		assert.Empty(t, rl.Metadata[guber.MetadataIdempotentReplay])
+	})
 }
 
-func TestMissingFields(t *testing.T) {
-	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+// overLimitCountStore is an OverLimitStore which keeps the counts in memory
+type overLimitCountStore struct {
+	mutex  sync.Mutex
+	counts map[string]int64
+}
+
+func (s *overLimitCountStore) OnChange(context.Context, *guber.RateLimitReq, *guber.CacheItem) {}
+func (s *overLimitCountStore) Get(context.Context, *guber.RateLimitReq) (*guber.CacheItem, bool) {
+	return nil, false
+}
+func (s *overLimitCountStore) Remove(context.Context, string) {}
+
+func (s *overLimitCountStore) GetOverLimitCount(_ context.Context, key string) (int64, int64, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	count, ok := s.counts[key]
+	return count, epochMillis(clock.Now().Add(clock.Hour)), ok
+}
+
+func (s *overLimitCountStore) OnOverLimit(_ context.Context, key string, count int64, _ int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.counts[key] = count
+}
+
+func TestOverLimitCount(t *testing.T) {
+	store := &overLimitCountStore{counts: map[string]int64{"test_over_limit_count_account:5678": 41}}
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{OverLimitRetention: clock.Minute},
+		Store:     store,
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	tests := []struct {
-		Req    *guber.RateLimitReq
-		Status guber.Status
-		Error  string
-	}{
-		{
-			Req: &guber.RateLimitReq{
-				Name:      "test_missing_fields",
-				UniqueKey: "account:1234",
-				Hits:      1,
-				Limit:     10,
-				Duration:  0,
-			},
-			Error:  "", // No Error
-			Status: guber.Status_UNDER_LIMIT,
-		},
-		{
-			Req: &guber.RateLimitReq{
-				Name:      "test_missing_fields",
-				UniqueKey: "account:12345",
-				Hits:      1,
-				Duration:  10000,
-				Limit:     0,
-			},
-			Error:  "", // No Error
-			Status: guber.Status_OVER_LIMIT,
-		},
-		{
-			Req: &guber.RateLimitReq{
-				UniqueKey: "account:1234",
-				Hits:      1,
-				Duration:  10000,
-				Limit:     5,
-			},
-			Error:  "field 'namespace' cannot be empty",
-			Status: guber.Status_UNDER_LIMIT,
-		},
-		{
-			Req: &guber.RateLimitReq{
-				Name:     "test_missing_fields",
-				Hits:     1,
-				Duration: 10000,
-				Limit:    5,
-			},
-			Error:  "field 'unique_key' cannot be empty",
-			Status: guber.Status_UNDER_LIMIT,
-		},
+	hit := func(key string, hits int64, count bool) *guber.RateLimitResp {
+		req := &guber.RateLimitReq{
+			Name:      "test_over_limit_count",
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  200,
+			Hits:      hits,
+			Limit:     2,
+		}
+		if count {
+			req.Metadata = map[string]string{guber.MetadataOverLimitCount: "true"}
+		}
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{Requests: []*guber.RateLimitReq{req}})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
 	}
 
-	for i, test := range tests {
-		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{test.Req},
-		})
-		require.NoError(t, err)
-		assert.Equal(t, test.Error, resp.Responses[0].Error, i)
-		assert.Equal(t, test.Status, resp.Responses[0].Status, i)
+	rl := hit("account:1234", 2, true)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, "0", rl.Metadata[guber.MetadataOverLimitCount])
+
+	for i := 1; i <= 3; i++ {
+		rl = hit("account:1234", 1, true)
+		assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+		assert.Equal(t, fmt.Sprint(i), rl.Metadata[guber.MetadataOverLimitCount])
 	}
-}
 
-func TestGlobalRateLimits(t *testing.T) {
-	name := t.Name()
-	key := guber.RandomString(10)
-	owner, err := cluster.FindOwningDaemon(name, key)
-	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
-	require.NoError(t, err)
-	var firstResetTime int64
+	// Only returned on request, queries are not counted
+	assert.Empty(t, hit("account:1234", 1, false).Metadata[guber.MetadataOverLimitCount])
+	assert.Equal(t, "4", hit("account:1234", 0, true).Metadata[guber.MetadataOverLimitCount])
 
-	sendHit := func(client guber.V1Client, status guber.Status, hits, remain int64) {
-		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-		defer cancel()
-		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_TOKEN_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  guber.Minute * 3,
-					Hits:      hits,
-					Limit:     5,
-				},
-			},
-		})
+	// The count is kept when the rate limit resets
+	clock.Sleep(clock.Millisecond * 300)
+	rl = hit("account:1234", 1, true)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, "4", rl.Metadata[guber.MetadataOverLimitCount])
+
+	// Counts are loaded from and saved to the store
+	hit("account:5678", 2, false)
+	assert.Equal(t, "42", hit("account:5678", 1, true).Metadata[guber.MetadataOverLimitCount])
+	store.mutex.Lock()
+	assert.Equal(t, int64(42), store.counts["test_over_limit_count_account:5678"])
+	assert.Equal(t, int64(4), store.counts["test_over_limit_count_account:1234"])
+	store.mutex.Unlock()
+
+	t.Run("Disabled", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{})
+		defer srv.Close()
+		client, err = guber.DialV1Server(srv.listener.Addr().String(), nil)
 		require.NoError(t, err)
-		item := resp.Responses[0]
-		assert.Equal(t, "", item.Error)
-		assert.Equal(t, remain, item.Remaining)
-		assert.Equal(t, status, item.Status)
-		assert.Equal(t, int64(5), item.Limit)
+		hit("account:1234", 3, true)
+		assert.Empty(t, hit("account:1234", 1, true).Metadata[guber.MetadataOverLimitCount])
+	})
+}
 
-		// ResetTime should not change during test.
-		if firstResetTime == 0 {
-			firstResetTime = item.ResetTime
+func TestStrictMode(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		NamespacePolicies: []guber.NamespacePolicy{{Prefix: "test_strict_login", Strict: true}},
+	})
+	defer srv.Close()
+	// The second peer is never reachable
+	srv.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: srv.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: "127.0.0.1:1"},
+	})
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
+
+	// Choose a key of each name owned by the unreachable peer
+	forwardedKey := func(name string) string {
+		for i := 0; ; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			peer, err := srv.srv.GetPeer(ctx, name+"_"+key)
+			require.NoError(t, err)
+			if !peer.Info().IsOwner {
+				return key
+			}
 		}
-		assert.Equal(t, firstResetTime, item.ResetTime)
+	}
+	newReq := func(name string, metadata map[string]string) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      name,
+			UniqueKey: forwardedKey(name),
+			Behavior:  guber.Behavior_NO_BATCHING,
+			Duration:  guber.Minute,
+			Hits:      1,
+			Limit:     10,
+			Metadata:  metadata,
+		}
+	}
 
-		// ensure that we have a canonical host
-		assert.NotEmpty(t, item.Metadata["owner"])
+	resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{Requests: []*guber.RateLimitReq{
+		newReq("test_strict_other", nil),
+		newReq("test_strict_login", nil),
+		newReq("test_strict_other", map[string]string{guber.MetadataStrict: "true"}),
+	}})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 3)
+
+	// Rate limits which are not strict return the error with an UNKNOWN status
+	assert.NotEqual(t, "", resp.Responses[0].Error)
+	assert.Equal(t, guber.Status_UNKNOWN, resp.Responses[0].Status)
+	assert.Equal(t, guber.ErrorCodePeerUnavailable, resp.Responses[0].Metadata[guber.MetadataErrorCode])
+
+	// Strict rate limits, by policy or by request, fail closed
+	for _, rl := range resp.Responses[1:] {
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+		assert.Equal(t, int64(0), rl.Remaining)
+		assert.Equal(t, guber.ErrorCodePeerUnavailable, rl.Metadata[guber.MetadataErrorCode])
+		assert.NotEqual(t, "", rl.Metadata[guber.MetadataStrictError])
 	}
+}
 
-	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+// logHook collects the entries logged by a logrus.Logger
+type logHook struct {
+	mutex   sync.Mutex
+	entries []*logrus.Entry
+}
 
-	// Our first hit should create the request on the peer and queue for async forward
-	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, 1, 4)
+func (h *logHook) Levels() []logrus.Level { return logrus.AllLevels }
 
-	// Our second should be processed as if we own it since the async forward hasn't occurred yet
-	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, 2, 2)
+func (h *logHook) Fire(e *logrus.Entry) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.entries = append(h.entries, e)
+	return nil
+}
 
-	testutil.UntilPass(t, 20, clock.Millisecond*200, func(t testutil.TestingT) {
-		// Inspect peers metrics, ensure the peer sent the global rate limit to the owner
-		metricsURL := fmt.Sprintf("http://%s/metrics", peers[0].Config().HTTPListenAddress)
-		m, err := getMetricRequest(metricsURL, "gubernator_global_send_duration_count")
-		assert.NoError(t, err)
-		assert.Equal(t, 1, int(m.Value))
+// find returns the first entry with the message
+func (h *logHook) find(msg string) *logrus.Entry {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, e := range h.entries {
+		if e.Message == msg {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestSlowRequestLog(t *testing.T) {
+	hook := &logHook{}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(hook)
+
+	owner := newV1Server(t, "localhost:0", guber.Config{})
+	defer owner.Close()
+	// Every request is slower than a nanosecond
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{SlowRequestThreshold: time.Nanosecond},
+		Logger:    logger,
+	})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: srv.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: owner.listener.Addr().String()},
 	})
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	require.NoError(t, waitForBroadcast(clock.Second*3, owner, 1))
+	// Choose a key owned by this instance and a key owned by the other peer
+	var local, forwarded string
+	for i := 0; local == "" || forwarded == ""; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		peer, err := srv.srv.GetPeer(ctx, "test_slow_request_"+key)
+		require.NoError(t, err)
+		if peer.Info().IsOwner {
+			local = key
+		} else {
+			forwarded = key
+		}
+	}
+	req := &guber.GetRateLimitsReq{}
+	for _, key := range []string{local, forwarded} {
+		req.Requests = append(req.Requests, &guber.RateLimitReq{
+			Name:      "test_slow_request",
+			UniqueKey: key,
+			Behavior:  guber.Behavior_NO_BATCHING,
+			Duration:  guber.Minute,
+			Hits:      1,
+			Limit:     10,
+		})
+	}
+	resp, err := client.GetRateLimits(ctx, req)
+	require.NoError(t, err)
+	for _, rl := range resp.Responses {
+		require.Equal(t, "", rl.Error)
+	}
 
-	// Check different peers, they should have gotten the broadcast from the owner
-	sendHit(peers[1].MustClient(), guber.Status_UNDER_LIMIT, 0, 2)
-	sendHit(peers[2].MustClient(), guber.Status_UNDER_LIMIT, 0, 2)
+	e := hook.find("slow GetRateLimits request")
+	require.NotNil(t, e)
+	assert.Equal(t, 2, e.Data["batch_size"])
+	assert.Equal(t, []string{owner.listener.Addr().String()}, e.Data["peers"])
+	assert.Contains(t, []string{"pick", "forward", "algorithm"}, e.Data["slowest_phase"])
+	for _, phase := range []string{"duration", "pick", "forward", "algorithm"} {
+		assert.NotEmpty(t, e.Data[phase], phase)
+	}
 
-	// Non owning peer should calculate the rate limit remaining before forwarding
-	// to the owner.
-	sendHit(peers[3].MustClient(), guber.Status_UNDER_LIMIT, 2, 0)
+	e = hook.find("slow GetPeerRateLimits RPC")
+	require.NotNil(t, e)
+	assert.Equal(t, 1, e.Data["batch_size"])
+	assert.Equal(t, owner.listener.Addr().String(), e.Data["peer"])
+}
 
-	require.NoError(t, waitForBroadcast(clock.Second*3, owner, 2))
+func TestDegradedMode(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{
+			DegradedErrorPercent: 50,
+			DegradedWindow:       clock.Minute,
+		},
+	})
+	defer srv.Close()
+	// The second peer is never reachable
+	srv.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: srv.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: "127.0.0.1:1"},
+	})
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	sendHit(peers[4].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
-}
+	// Choose keys such that half are owned by the unreachable peer
+	var owned, forwarded []string
+	for i := 0; len(owned) < 25 || len(forwarded) < 25; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		peer, err := srv.srv.GetPeer(ctx, "test_degraded_"+key)
+		require.NoError(t, err)
+		if peer.Info().IsOwner {
+			owned = append(owned, key)
+		} else {
+			forwarded = append(forwarded, key)
+		}
+	}
 
-// Ensure global broadcast updates all peers when GetRateLimits is called on
-// either owner or non-owner peer.
-func TestGlobalRateLimitsWithLoadBalancing(t *testing.T) {
-	ctx := context.Background()
-	name := t.Name()
-	key := guber.RandomString(10)
+	req := &guber.GetRateLimitsReq{}
+	for _, key := range append(owned[:25], forwarded[:25]...) {
+		req.Requests = append(req.Requests, &guber.RateLimitReq{
+			Name:      "test_degraded",
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Behavior:  guber.Behavior_NO_BATCHING,
+			Duration:  guber.Minute,
+			Hits:      1,
+			Limit:     10,
+		})
+	}
 
-	// Determine owner and non-owner peers.
-	owner, err := cluster.FindOwningDaemon(name, key)
-	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
+	// Until enough forwarded requests have failed, the errors are returned to the client
+	resp, err := client.GetRateLimits(ctx, req)
 	require.NoError(t, err)
-	nonOwner := peers[0]
+	var failed int
+	for _, rl := range resp.Responses {
+		if rl.Error != "" {
+			failed++
+		}
+	}
+	assert.NotZero(t, failed)
 
-	// Connect to owner and non-owner peers in round robin.
-	dialOpts := []grpc.DialOption{
-		grpc.WithResolvers(guber.NewStaticBuilder()),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin":{}}]}`),
+	// Once the error rate exceeds the threshold, rate limits owned by the unreachable peer are
+	// evaluated locally with the limit divided by the number of peers
+	resp, err = client.GetRateLimits(ctx, req)
+	require.NoError(t, err)
+	var degraded int
+	for _, rl := range resp.Responses {
+		require.Equal(t, "", rl.Error)
+		if rl.Metadata[guber.MetadataDegraded] != "" {
+			degraded++
+			assert.Equal(t, "5", rl.Metadata[guber.MetadataDegraded])
+			assert.Equal(t, int64(5), rl.Limit)
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+		}
 	}
-	address := fmt.Sprintf("static:///%s,%s", owner.PeerInfo.GRPCAddress, nonOwner.PeerInfo.GRPCAddress)
-	conn, err := grpc.DialContext(ctx, address, dialOpts...)
+	assert.Equal(t, 25, degraded)
+}
+
+// slowStore delays every lookup, such that the peer which owns the rate limits responds slowly
+type slowStore struct {
+	delay time.Duration
+}
+
+func (s slowStore) OnChange(context.Context, *guber.RateLimitReq, *guber.CacheItem) {}
+func (s slowStore) Remove(context.Context, string)                                  {}
+func (s slowStore) Get(context.Context, *guber.RateLimitReq) (*guber.CacheItem, bool) {
+	clock.Sleep(s.delay)
+	return nil, false
+}
+
+func TestSlowPeerFallback(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{SlowPeerThreshold: 20 * clock.Millisecond},
+	})
+	defer srv.Close()
+	slow := newV1Server(t, "localhost:0", guber.Config{Store: slowStore{delay: 50 * clock.Millisecond}})
+	defer slow.Close()
+	next := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{VerifyPeerOwnership: true},
+	})
+	defer next.Close()
+
+	// The first instance owns no rate limits, every rate limit is forwarded to the other two
+	slowAddr, nextAddr := slow.listener.Addr().String(), next.listener.Addr().String()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: slowAddr}, {GRPCAddress: nextAddr}})
+	slow.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: slowAddr, IsOwner: true}, {GRPCAddress: nextAddr}})
+	next.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: slowAddr}, {GRPCAddress: nextAddr, IsOwner: true}})
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
-	client := guber.NewV1Client(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
-	sendHit := func(client guber.V1Client, status guber.Status, i int) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*clock.Second)
-		defer cancel()
+	var keys []string
+	for i := 0; len(keys) < 6; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		peer, err := srv.srv.GetPeer(ctx, "test_slow_peer_"+key)
+		require.NoError(t, err)
+		if peer.Info().GRPCAddress == slowAddr {
+			keys = append(keys, key)
+		}
+	}
+	send := func(key string) *guber.RateLimitResp {
 		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_TOKEN_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  5 * guber.Minute,
-					Hits:      1,
-					Limit:     2,
-				},
-			},
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_slow_peer",
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Behavior:  guber.Behavior_NO_BATCHING,
+				Duration:  guber.Minute,
+				Hits:      1,
+				Limit:     10,
+			}},
 		})
-		require.NoError(t, err, i)
-		item := resp.Responses[0]
-		assert.Equal(t, "", item.Error, fmt.Sprintf("unexpected error, iteration %d", i))
-		assert.Equal(t, status, item.Status, fmt.Sprintf("mismatch status, iteration %d", i))
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
 	}
 
-	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+	// Each lookup of a new rate limit by the owner is slow
+	for _, key := range keys[:5] {
+		rl := send(key)
+		assert.Equal(t, slowAddr, rl.Metadata["owner"])
+		assert.Empty(t, rl.Metadata[guber.MetadataSlowOwner])
+	}
 
-	// Send two hits that should be processed by the owner and non-owner and
-	// deplete the limit consistently.
-	sendHit(client, guber.Status_UNDER_LIMIT, 1)
-	sendHit(client, guber.Status_UNDER_LIMIT, 2)
-	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 1))
+	// Once the latency is recalculated, rate limits owned by the slow peer are evaluated by the next peer
+	clock.Sleep(clock.Second)
+	rl := send(keys[5])
+	assert.Equal(t, slowAddr, rl.Metadata[guber.MetadataSlowOwner])
+	assert.Equal(t, nextAddr, rl.Metadata["owner"])
+	assert.Equal(t, int64(9), rl.Remaining)
 
-	// All successive hits should return OVER_LIMIT.
-	for i := 2; i <= 10; i++ {
-		sendHit(client, guber.Status_OVER_LIMIT, i)
-	}
+	rl = send(keys[5])
+	assert.Equal(t, nextAddr, rl.Metadata["owner"])
+	assert.Equal(t, int64(8), rl.Remaining)
+
+	_, err = guber.NewV1Instance(guber.Config{
+		GRPCServers: []*grpc.Server{grpc.NewServer()},
+		Behaviors:   guber.BehaviorConfig{SlowPeerThreshold: -1},
+	})
+	assert.Error(t, err)
 }
 
-func TestGlobalRateLimitsPeerOverLimit(t *testing.T) {
-	name := t.Name()
-	key := guber.RandomString(10)
-	owner, err := cluster.FindOwningDaemon(name, key)
-	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
+func TestNamespacePolicies(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		NamespacePolicies: []guber.NamespacePolicy{
+			{Prefix: "public-api", MaxLimit: 100, MaxDuration: clock.Hour, Algorithms: []guber.Algorithm{guber.Algorithm_TOKEN_BUCKET}},
+			{Prefix: "public-api-clamped", MaxLimit: 100, MaxDuration: clock.Hour, Clamp: true},
+		},
+	})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	sendHit := func(expectedStatus guber.Status, hits, expectedRemaining int64) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*clock.Second)
-		defer cancel()
-		resp, err := peers[0].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_TOKEN_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  5 * guber.Minute,
-					Hits:      hits,
-					Limit:     2,
-				},
-			},
+	for _, test := range []struct {
+		Name      string
+		Req       *guber.RateLimitReq
+		Error     string
+		Limit     int64
+		Remaining int64
+	}{
+		{
+			Name:  "within the policy",
+			Req:   &guber.RateLimitReq{Name: "public-api", Limit: 100, Duration: guber.Minute},
+			Limit: 100, Remaining: 99,
+		},
+		{
+			Name:  "limit is rejected",
+			Req:   &guber.RateLimitReq{Name: "public-api", Limit: 101, Duration: guber.Minute},
+			Error: "limit '101' exceeds the max limit '100' of namespace 'public-api'",
+		},
+		{
+			Name:  "duration is rejected",
+			Req:   &guber.RateLimitReq{Name: "public-api", Limit: 100, Duration: guber.Minute * 120},
+			Error: "duration '7200000' exceeds the max duration '1h0m0s' of namespace 'public-api'",
+		},
+		{
+			Name:  "gregorian duration is rejected",
+			Req:   &guber.RateLimitReq{Name: "public-api", Limit: 100, Duration: guber.GregorianDays, Behavior: guber.Behavior_DURATION_IS_GREGORIAN},
+			Error: "gregorian duration exceeds the max duration '1h0m0s' of namespace 'public-api'",
+		},
+		{
+			Name:  "algorithm is rejected",
+			Req:   &guber.RateLimitReq{Name: "public-api", Limit: 100, Duration: guber.Minute, Algorithm: guber.Algorithm_LEAKY_BUCKET},
+			Error: "algorithm 'LEAKY_BUCKET' is not allowed in namespace 'public-api'",
+		},
+		{
+			Name:  "longest prefix clamps",
+			Req:   &guber.RateLimitReq{Name: "public-api-clamped", Limit: 1000, Duration: guber.Minute * 120},
+			Limit: 100, Remaining: 99,
+		},
+		{
+			Name:  "other namespaces are not capped",
+			Req:   &guber.RateLimitReq{Name: "internal", Limit: 1000, Duration: guber.Minute * 120},
+			Limit: 1000, Remaining: 999,
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Req.UniqueKey = guber.RandomString(10)
+			test.Req.Hits = 1
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{test.Req},
+			})
+			require.NoError(t, err)
+			rl := resp.Responses[0]
+			assert.Equal(t, test.Error, rl.Error)
+			assert.Equal(t, test.Limit, rl.Limit)
+			assert.Equal(t, test.Remaining, rl.Remaining)
 		})
-		assert.NoError(t, err)
-		item := resp.Responses[0]
-		assert.Equal(t, "", item.Error, "unexpected error")
-		assert.Equal(t, expectedStatus, item.Status, "mismatch status")
-		assert.Equal(t, expectedRemaining, item.Remaining, "mismatch remaining")
 	}
-
-	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
-
-	// Send two hits that should be processed by the owner and the broadcast to
-	// peer, depleting the remaining.
-	sendHit(guber.Status_UNDER_LIMIT, 1, 1)
-	sendHit(guber.Status_UNDER_LIMIT, 1, 0)
-
-	// Wait for the broadcast from the owner to the peer
-	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 1))
-
-	// Since the remainder is 0, the peer should return OVER_LIMIT on next hit.
-	sendHit(guber.Status_OVER_LIMIT, 1, 0)
-
-	// Wait for the broadcast from the owner to the peer.
-	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 2))
-
-	// The status should still be OVER_LIMIT.
-	sendHit(guber.Status_OVER_LIMIT, 0, 0)
 }
 
-func TestGlobalRequestMoreThanAvailable(t *testing.T) {
-	name := t.Name()
-	key := guber.RandomString(10)
-	owner, err := cluster.FindOwningDaemon(name, key)
-	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
+func TestPolicyFile(t *testing.T) {
+	dir := t.TempDir()
+	policyFile := filepath.Join(dir, "policies.yaml")
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	writeFile("common.yaml", `
+policies:
+  - prefix: public-api
+    max_limit: 10
+  - prefix: login
+    max_limit: 5
+    max_duration: 1m
+`)
+	writeFile("policies.yaml", `
+version: v1
+include: [common.yaml]
+policies:
+  - prefix: public-api
+    max_limit: 100
+    algorithms: [token_bucket]
+`)
+
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		NamespacePolicies: []guber.NamespacePolicy{{Prefix: "internal", MaxLimit: 1000}},
+		PolicyFile:        policyFile,
+	})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
+	ctx := context.Background()
 
-	sendHit := func(client guber.V1Client, expectedStatus guber.Status, hits int64, remaining int64) {
-		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-		defer cancel()
+	check := func(name string, limit int64) string {
 		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_LEAKY_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  guber.Minute * 1_000,
-					Hits:      hits,
-					Limit:     100,
-				},
-			},
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: guber.RandomString(10),
+				Hits:      1,
+				Limit:     limit,
+				Duration:  guber.Minute,
+			}},
 		})
-		assert.NoError(t, err)
-		assert.Equal(t, "", resp.Responses[0].GetError())
-		assert.Equal(t, expectedStatus, resp.Responses[0].GetStatus())
+		require.NoError(t, err)
+		return resp.Responses[0].Error
 	}
-
-	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
-	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
-
-	// Ensure GRPC has connections to each peer before we start, as we want
-	// the actual test requests to happen quite fast.
-	for _, p := range peers {
-		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 0, 100)
+	policyVersion := func() string {
+		health, err := client.HealthCheck(ctx, &guber.HealthCheckReq{})
+		require.NoError(t, err)
+		return health.PolicyVersion
 	}
 
-	// Send a request for 50 hits from each non owning peer in the cluster. These requests
-	// will be queued and sent to the owner as accumulated hits. As a result of the async nature
-	// of `Behavior_GLOBAL` rate limit requests spread across peers like this will be allowed to
-	// over-consume their resource within the rate limit window until the owner is updated and
-	// a broadcast to all peers is received.
-	//
-	// The maximum number of resources that can be over-consumed can be calculated by multiplying
-	// the remainder by the number of peers in the cluster. For example: If you have a remainder of 100
-	// and a cluster of 10 instances, then the maximum over-consumed resource is 1,000. If you need
-	// a more accurate remaining calculation, and wish to avoid over consuming a resource, then do
-	// not use `Behavior_GLOBAL`.
-	for _, p := range peers {
-		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 50, 50)
+	assert.Equal(t, "v1", policyVersion())
+	// The policy of the file replaces the policy it includes with the same prefix
+	assert.Equal(t, "", check("public-api", 100))
+	assert.Equal(t, "limit '101' exceeds the max limit '100' of namespace 'public-api'", check("public-api", 101))
+	assert.Equal(t, "limit '6' exceeds the max limit '5' of namespace 'login'", check("login", 6))
+	assert.Equal(t, "limit '1001' exceeds the max limit '1000' of namespace 'internal'", check("internal", 1001))
+
+	// Changes to an included file are reloaded
+	writeFile("common.yaml", `
+policies:
+  - prefix: login
+    max_limit: 50
+`)
+	assert.Eventually(t, func() bool { return check("login", 6) == "" }, clock.Second*5, clock.Millisecond*50)
+	assert.Equal(t, "v1", policyVersion())
+
+	writeFile("policies.yaml", `
+version: v2
+include: [common.yaml]
+`)
+	assert.Eventually(t, func() bool { return policyVersion() == "v2" }, clock.Second*5, clock.Millisecond*50)
+	assert.Equal(t, "", check("public-api", 1000))
+
+	// An invalid file does not replace the policies in effect
+	writeFile("policies.yaml", `
+version: v3
+policies:
+  - prefix: public-api
+    max_limt: 1
+`)
+	clock.Sleep(clock.Millisecond * 500)
+	assert.Equal(t, "v2", policyVersion())
+	assert.Equal(t, "limit '51' exceeds the max limit '50' of namespace 'login'", check("login", 51))
+
+	for _, test := range []struct {
+		Name    string
+		Content string
+		Error   string
+	}{
+		{
+			Name:    "unknown field",
+			Content: "policies:\n  - prefix: a\n    max_limt: 1\n",
+			Error:   "field max_limt not found",
+		},
+		{
+			Name:    "invalid duration",
+			Content: "policies:\n  - prefix: a\n    max_duration: 1 hour\n",
+			Error:   "invalid max_duration of 'a'",
+		},
+		{
+			Name:    "invalid algorithm",
+			Content: "policies:\n  - prefix: a\n    algorithms: [fixed_window]\n",
+			Error:   "invalid algorithm 'fixed_window' of 'a'",
+		},
+		{
+			Name:    "empty prefix",
+			Content: "policies:\n  - max_limit: 1\n",
+			Error:   "NamespacePolicies.Prefix cannot be empty",
+		},
+		{
+			Name:    "duplicate prefix",
+			Content: "policies:\n  - prefix: a\n  - prefix: a\n",
+			Error:   "duplicate prefix 'a'",
+		},
+		{
+			Name:    "include cycle",
+			Content: "include: [invalid.yaml]\n",
+			Error:   "includes itself",
+		},
+		{
+			Name:    "missing include",
+			Content: "include: [missing.yaml]\n",
+			Error:   "while reading policy file",
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			writeFile("invalid.yaml", test.Content)
+			_, err := guber.NewV1Instance(guber.Config{
+				GRPCServers: []*grpc.Server{grpc.NewServer()},
+				PolicyFile:  filepath.Join(dir, "invalid.yaml"),
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.Error)
+		})
 	}
-
-	// Wait for the broadcast from the owner to the peers
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
-
-	// We should be over the limit
-	sendHit(peers[0].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
 }
 
-func TestGlobalNegativeHits(t *testing.T) {
-	name := t.Name()
-	key := guber.RandomString(10)
-	owner, err := cluster.FindOwningDaemon(name, key)
-	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
+func TestThrottle(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	sendHit := func(client guber.V1Client, status guber.Status, hits int64, remaining int64) {
-		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-		defer cancel()
-		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_TOKEN_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  guber.Minute * 100,
-					Hits:      hits,
-					Limit:     2,
-				},
-			},
-		})
-		assert.NoError(t, err)
-		assert.Equal(t, "", resp.Responses[0].GetError())
-		assert.Equal(t, status, resp.Responses[0].GetStatus())
-		assert.Equal(t, remaining, resp.Responses[0].Remaining)
+	req := &guber.RateLimitReq{
+		Name:      "test_throttle",
+		UniqueKey: guber.RandomString(10),
+		Hits:      1,
+		Limit:     1,
+		Duration:  200 * guber.Millisecond,
+	}
+	var calls int
+	fn := func(context.Context) error {
+		calls++
+		return nil
 	}
 
-	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
-
-	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
-	require.NoError(t, err)
+	t.Run("Under the limit calls immediately", func(t *testing.T) {
+		require.NoError(t, guber.Throttle(context.Background(), client, req, fn))
+		assert.Equal(t, 1, calls)
+	})
 
-	// Send a negative hit on a rate limit with no hits
-	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, -1, 3)
+	t.Run("Over the limit waits for the reset", func(t *testing.T) {
+		start := time.Now()
+		require.NoError(t, guber.Throttle(context.Background(), client, req, fn))
+		assert.Equal(t, 2, calls)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
 
-	// Wait for the negative remaining to propagate
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
+	t.Run("Context cancels the wait", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		req.Duration = guber.Minute
+		req.UniqueKey = guber.RandomString(10)
+		require.NoError(t, guber.Throttle(ctx, client, req, fn))
+		err := guber.Throttle(ctx, client, req, fn)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 3, calls)
+	})
 
-	// Send another negative hit to a different peer
-	sendHit(peers[1].MustClient(), guber.Status_UNDER_LIMIT, -1, 4)
+	t.Run("Errors are returned", func(t *testing.T) {
+		err := guber.Throttle(context.Background(), client, &guber.RateLimitReq{Name: "test_throttle"}, fn)
+		assert.EqualError(t, err, "field 'unique_key' cannot be empty")
+		assert.Equal(t, 3, calls)
+	})
+}
 
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+2))
+func TestBatchSplitting(t *testing.T) {
+	addr := cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress
+	requests := make([]*guber.RateLimitReq, 2500)
+	for i := range requests {
+		requests[i] = &guber.RateLimitReq{
+			Name:      "test_batch_splitting",
+			UniqueKey: guber.RandomString(10),
+			Hits:      1,
+			Limit:     int64(i + 1),
+			Duration:  guber.Minute,
+		}
+	}
 
-	// Should have 4 in the remainder
-	sendHit(peers[2].MustClient(), guber.Status_UNDER_LIMIT, 4, 0)
+	for _, tc := range []struct {
+		name string
+		opts []grpc.DialOption
+	}{
+		{name: "Default"},
+		{name: "Parallel", opts: []grpc.DialOption{grpc.WithChainUnaryInterceptor(guber.BatchSplitInterceptor(300, 4))}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := guber.DialV1Server(addr, nil, tc.opts...)
+			require.NoError(t, err)
 
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+3))
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: requests})
+			require.NoError(t, err)
+			require.Len(t, resp.Responses, len(requests))
+			for i, rl := range resp.Responses {
+				assert.Equal(t, "", rl.Error)
+				assert.Equal(t, int64(i+1), rl.Limit)
+			}
+			assert.Equal(t, int32(len(requests)), resp.Summary.UnderLimit+resp.Summary.OverLimit)
+		})
+	}
 
-	sendHit(peers[3].MustClient(), guber.Status_UNDER_LIMIT, 0, 0)
+	t.Run("Without splitting", func(t *testing.T) {
+		conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = guber.NewV1Client(conn).GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: requests})
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	})
 }
 
-func TestGlobalResetRemaining(t *testing.T) {
-	name := t.Name()
+func TestBatchSummary(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
 	key := guber.RandomString(10)
-	owner, err := cluster.FindOwningDaemon(name, key)
+
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{Name: "test_batch_summary", UniqueKey: key, Hits: 1, Limit: 1, Duration: guber.Minute},
+			{Name: "test_batch_summary", UniqueKey: key, Hits: 1, Limit: 1, Duration: guber.Minute, Behavior: guber.Behavior_NO_BATCHING},
+			{Name: "test_batch_summary_b", Hits: 1, Limit: 1, Duration: guber.Minute},
+			{Name: "test_batch_summary_a", Hits: 1, Limit: 1, Duration: guber.Minute},
+			{Name: "test_batch_summary_a", Hits: 1, Limit: 1, Duration: guber.Minute},
+		},
+	})
 	require.NoError(t, err)
-	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.Len(t, resp.Responses, 5)
+	assert.Equal(t, int32(2), resp.Summary.UnderLimit+resp.Summary.OverLimit)
+	assert.Equal(t, int32(3), resp.Summary.Errors)
+	assert.Equal(t, []string{"test_batch_summary_a", "test_batch_summary_b"}, resp.Summary.ErrorNamespaces)
+}
+
+func TestIPKeyAggregation(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		NamespacePolicies: []guber.NamespacePolicy{
+			{Prefix: "ip-aggregated", IPv4Prefix: 24, IPv6Prefix: 64},
+		},
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	sendHit := func(client guber.V1Client, expectedStatus guber.Status, hits int64, remaining int64) {
-		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-		defer cancel()
-		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-			Requests: []*guber.RateLimitReq{
-				{
-					Name:      name,
-					UniqueKey: key,
-					Algorithm: guber.Algorithm_LEAKY_BUCKET,
-					Behavior:  guber.Behavior_GLOBAL,
-					Duration:  guber.Minute * 1_000,
-					Hits:      hits,
-					Limit:     100,
-				},
-			},
+	send := func(name, key string) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{Name: name, UniqueKey: key, Hits: 1, Limit: 10, Duration: guber.Minute}},
 		})
-		assert.NoError(t, err)
-		assert.Equal(t, "", resp.Responses[0].GetError())
-		assert.Equal(t, expectedStatus, resp.Responses[0].GetStatus())
-		assert.Equal(t, remaining, resp.Responses[0].Remaining)
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
 	}
 
-	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+	// Every address in the /24 shares the rate limit, with or without a label
+	assert.Equal(t, int64(9), send("ip-aggregated", "10.2.10.7").Remaining)
+	assert.Equal(t, int64(8), send("ip-aggregated", "10.2.10.200").Remaining)
+	assert.Equal(t, int64(9), send("ip-aggregated", "ip:10.2.10.7").Remaining)
+	assert.Equal(t, int64(8), send("ip-aggregated", "ip:10.2.10.8").Remaining)
+	assert.Equal(t, int64(9), send("ip-aggregated", "10.2.11.7").Remaining)
+
+	// Every address in the /64 shares the rate limit
+	assert.Equal(t, int64(9), send("ip-aggregated", "ip:2001:db8:0:1::1").Remaining)
+	assert.Equal(t, int64(8), send("ip-aggregated", "ip:2001:db8:0:1:ffff::2").Remaining)
+	assert.Equal(t, int64(9), send("ip-aggregated", "ip:2001:db8:0:2::1").Remaining)
+
+	// Keys which are not addresses and other namespaces are not aggregated
+	assert.Equal(t, int64(9), send("ip-aggregated", "account:1234").Remaining)
+	assert.Equal(t, int64(9), send("other", "10.2.10.7").Remaining)
+	assert.Equal(t, int64(9), send("other", "10.2.10.8").Remaining)
+}
 
-	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
+func TestRateLimitTemplates(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Templates: []guber.RateLimitTemplate{
+			{Name: "per-user-login", Limit: 5, Duration: clock.Minute},
+			{Name: "uploads", Limit: 10, Duration: clock.Hour, Algorithm: guber.Algorithm_LEAKY_BUCKET, Burst: 20},
+		},
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	for _, p := range peers {
-		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 50, 50)
-	}
-
-	// Wait for the broadcast from the owner to the peers
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
-
-	// We should be over the limit and remaining should be zero
-	sendHit(peers[0].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
-
-	// Now reset the remaining
-	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
-	defer cancel()
-	resp, err := peers[0].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{
-			{
-				Name:      name,
-				UniqueKey: key,
-				Algorithm: guber.Algorithm_LEAKY_BUCKET,
-				Behavior:  guber.Behavior_GLOBAL | guber.Behavior_RESET_REMAINING,
-				Duration:  guber.Minute * 1_000,
-				Hits:      0,
-				Limit:     100,
-			},
-		},
-	})
-	require.NoError(t, err)
-	assert.NotEqual(t, 100, resp.Responses[0].Remaining)
-
-	// Wait for the reset to propagate.
-	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+2))
-
-	// Check a different peer to ensure remaining has been reset
-	resp, err = peers[1].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{
-			{
-				Name:      name,
-				UniqueKey: key,
-				Algorithm: guber.Algorithm_LEAKY_BUCKET,
-				Behavior:  guber.Behavior_GLOBAL,
-				Duration:  guber.Minute * 1_000,
-				Hits:      0,
-				Limit:     100,
-			},
-		},
-	})
-	require.NoError(t, err)
-	assert.NotEqual(t, 100, resp.Responses[0].Remaining)
-}
-
-func TestChangeLimit(t *testing.T) {
-	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
-	require.NoError(t, err)
-
-	tests := []struct {
-		Remaining int64
-		Algorithm guber.Algorithm
-		Status    guber.Status
+	for _, test := range []struct {
 		Name      string
+		Req       *guber.RateLimitReq
 		Limit     int64
+		Remaining int64
 	}{
 		{
-			Name:      "Should subtract 1 from remaining",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 99,
-			Limit:     100,
-		},
-		{
-			Name:      "Should subtract 1 from remaining",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 98,
-			Limit:     100,
-		},
-		{
-			Name:      "Should subtract 1 from remaining and change limit to 10",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 7,
-			Limit:     10,
-		},
-		{
-			Name:      "Should subtract 1 from remaining with new limit of 10",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 6,
-			Limit:     10,
-		},
-		{
-			Name:      "Should subtract 1 from remaining with new limit of 200",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 195,
-			Limit:     200,
+			Name:  "template without numbers",
+			Req:   &guber.RateLimitReq{Name: "per-user-login"},
+			Limit: 5, Remaining: 4,
 		},
 		{
-			Name:      "Should subtract 1 from remaining for leaky bucket",
-			Algorithm: guber.Algorithm_LEAKY_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 99,
-			Limit:     100,
+			Name:  "template replaces the numbers of the client",
+			Req:   &guber.RateLimitReq{Name: "per-user-login", Limit: 1000, Duration: guber.Second},
+			Limit: 5, Remaining: 4,
 		},
 		{
-			Name:      "Should subtract 1 from remaining for leaky bucket after limit change",
-			Algorithm: guber.Algorithm_LEAKY_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 9,
-			Limit:     10,
+			Name:  "template algorithm and burst",
+			Req:   &guber.RateLimitReq{Name: "uploads"},
+			Limit: 10, Remaining: 19,
 		},
 		{
-			Name:      "Should subtract 1 from remaining for leaky bucket with new limit",
-			Algorithm: guber.Algorithm_LEAKY_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Remaining: 8,
-			Limit:     10,
+			Name:  "other names are unchanged",
+			Req:   &guber.RateLimitReq{Name: "per-user-logout", Limit: 1000, Duration: guber.Minute},
+			Limit: 1000, Remaining: 999,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.Name, func(t *testing.T) {
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Req.UniqueKey = guber.RandomString(10)
+			test.Req.Hits = 1
 			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      "test_change_limit",
-						UniqueKey: "account:1234",
-						Algorithm: tt.Algorithm,
-						Duration:  guber.Millisecond * 9000,
-						Limit:     tt.Limit,
-						Hits:      1,
-					},
-				},
+				Requests: []*guber.RateLimitReq{test.Req},
 			})
 			require.NoError(t, err)
-
 			rl := resp.Responses[0]
-
-			assert.Equal(t, tt.Status, rl.Status)
-			assert.Equal(t, tt.Remaining, rl.Remaining)
-			assert.Equal(t, tt.Limit, rl.Limit)
-			assert.True(t, rl.ResetTime != 0)
+			assert.Equal(t, "", rl.Error)
+			assert.Equal(t, test.Limit, rl.Limit)
+			assert.Equal(t, test.Remaining, rl.Remaining)
 		})
 	}
 }
 
-func TestResetRemaining(t *testing.T) {
-	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+func TestRateLimitSchedules(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Templates: []guber.RateLimitTemplate{
+			{
+				Name: "test_schedules", Limit: 1000, Duration: clock.Minute,
+				Schedules: []guber.LimitSchedule{
+					{Schedule: "* 0-7,22-23 * * *", Limit: 200},
+					{Schedule: "* * * * 0,6", Limit: 500},
+				},
+			},
+		},
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	tests := []struct {
-		Remaining int64
-		Algorithm guber.Algorithm
-		Behavior  guber.Behavior
-		Status    guber.Status
-		Name      string
-		Limit     int64
+	for _, test := range []struct {
+		Name  string
+		At    string
+		Limit int64
 	}{
-		{
-			Name:      "Should subtract 1 from remaining",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Behavior:  guber.Behavior_BATCHING,
-			Remaining: 99,
-			Limit:     100,
-		},
-		{
-			Name:      "Should subtract 2 from remaining",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Behavior:  guber.Behavior_BATCHING,
-			Remaining: 98,
-			Limit:     100,
-		},
-		{
-			Name:      "Should reset the remaining",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Behavior:  guber.Behavior_RESET_REMAINING,
-			Remaining: 100,
-			Limit:     100,
-		},
-		{
-			Name:      "Should subtract 1 from remaining after reset",
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Status:    guber.Status_UNDER_LIMIT,
-			Behavior:  guber.Behavior_BATCHING,
-			Remaining: 99,
-			Limit:     100,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.Name, func(t *testing.T) {
+		{Name: "business hours", At: "2024-01-01T12:00:00Z", Limit: 1000},
+		{Name: "overnight", At: "2024-01-01T23:00:00Z", Limit: 200},
+		{Name: "weekend", At: "2024-01-06T12:00:00Z", Limit: 500},
+		{Name: "first matching schedule wins", At: "2024-01-06T23:00:00Z", Limit: 200},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			at, err := time.Parse(time.RFC3339, test.At)
+			require.NoError(t, err)
 			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-				Requests: []*guber.RateLimitReq{
-					{
-						Name:      "test_reset_remaining",
-						UniqueKey: "account:1234",
-						Algorithm: tt.Algorithm,
-						Duration:  guber.Millisecond * 9000,
-						Behavior:  tt.Behavior,
-						Limit:     tt.Limit,
-						Hits:      1,
-					},
-				},
+				Requests: []*guber.RateLimitReq{{
+					Name:      "test_schedules",
+					UniqueKey: guber.RandomString(10),
+					Hits:      1,
+					CreatedAt: proto.Int64(at.UnixMilli()),
+				}},
 			})
 			require.NoError(t, err)
-
 			rl := resp.Responses[0]
-
-			assert.Equal(t, tt.Status, rl.Status)
-			assert.Equal(t, tt.Remaining, rl.Remaining)
-			assert.Equal(t, tt.Limit, rl.Limit)
+			assert.Equal(t, "", rl.Error)
+			assert.Equal(t, test.Limit, rl.Limit)
+			assert.Equal(t, test.Limit-1, rl.Remaining)
 		})
 	}
 }
 
-func TestHealthCheck(t *testing.T) {
-	// Check that the cluster is healthy to start with.
-	for _, peer := range cluster.GetDaemons() {
-		healthResp, err := peer.MustClient().HealthCheck(context.Background(), &guber.HealthCheckReq{})
-		require.NoError(t, err)
-		assert.Equal(t, "healthy", healthResp.Status)
+func TestRegisterLimits(t *testing.T) {
+	conf := guber.Config{
+		Templates: []guber.RateLimitTemplate{{Name: "test_register_template", Limit: 5, Duration: clock.Minute}},
 	}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
 
-	// Stop the cluster to ensure errors occur on our instance.
-	cluster.Stop()
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
 
-	// Check the health again to get back the connection error.
-	testutil.UntilPass(t, 20, 300*clock.Millisecond, func(t testutil.TestingT) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		for _, peer := range cluster.GetDaemons() {
-			_, err := peer.MustClient().HealthCheck(ctx, &guber.HealthCheckReq{})
-			assert.Error(t, err, "connect: connection refused")
+	hit := func(t testutil.TestingT, addr, name string) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(addr, nil)
+		require.NoError(t, err)
+		// Only the name, unique key and hits, the rest is defined by the registered limit
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{Name: name, UniqueKey: guber.RandomString(10), Hits: 1}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Responses, 1)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	t.Run("Invalid request", func(t *testing.T) {
+		for _, r := range []*guber.RegisterLimitsReq{
+			{},
+			{Limits: []*guber.RegisteredLimit{{Limit: 10, Duration: guber.Minute}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register", Limit: -1, Duration: guber.Minute}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register", Limit: 10}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register", Limit: 10, Duration: guber.Minute, Algorithm: 5}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register_template", Limit: 10, Duration: guber.Minute}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register", Limit: 10, Duration: guber.Minute, RolloutDuration: -1}}},
+			{Limits: []*guber.RegisteredLimit{{Name: "test_register", Limit: 10, Duration: guber.Minute, RolloutStartPercent: 101}}},
+		} {
+			_, err := client.RegisterLimits(context.Background(), r)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), r.String())
 		}
 	})
 
-	// Restart so cluster is ready for next test.
-	require.NoError(t, startGubernator())
-}
+	t.Run("Register on every peer", func(t *testing.T) {
+		resp, err := client.RegisterLimits(context.Background(), &guber.RegisterLimitsReq{
+			Limits: []*guber.RegisteredLimit{
+				{Name: "test_register_login", Limit: 10, Duration: guber.Minute},
+				{Name: "test_register_uploads", Limit: 10, Duration: guber.Minute * 60, Algorithm: guber.Algorithm_LEAKY_BUCKET, Burst: 20},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
 
-func TestLeakyBucketDivBug(t *testing.T) {
-	defer clock.Freeze(clock.Now()).Unfreeze()
-	name := t.Name()
-	key := guber.RandomString(10)
-	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
-	require.NoError(t, err)
+		for _, addr := range []string{a.listener.Addr().String(), b.listener.Addr().String()} {
+			rl := hit(t, addr, "test_register_login")
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, int64(9), rl.Remaining)
 
-	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{
-			{
-				Name:      name,
-				UniqueKey: key,
-				Algorithm: guber.Algorithm_LEAKY_BUCKET,
-				Duration:  guber.Millisecond * 1000,
-				Hits:      1,
-				Limit:     2000,
-			},
-		},
+			rl = hit(t, addr, "test_register_uploads")
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, int64(19), rl.Remaining)
+		}
 	})
-	require.NoError(t, err)
-	assert.Equal(t, "", resp.Responses[0].Error)
-	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Responses[0].Status)
-	assert.Equal(t, int64(1999), resp.Responses[0].Remaining)
-	assert.Equal(t, int64(2000), resp.Responses[0].Limit)
 
-	// Should result in a rate of 0.5
-	resp, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{
-			{
-				Name:      name,
-				UniqueKey: key,
-				Algorithm: guber.Algorithm_LEAKY_BUCKET,
-				Duration:  guber.Millisecond * 1000,
-				Hits:      100,
-				Limit:     2000,
-			},
-		},
+	t.Run("Register again replaces the limit", func(t *testing.T) {
+		_, err := client.RegisterLimits(context.Background(), &guber.RegisterLimitsReq{
+			Limits: []*guber.RegisteredLimit{{Name: "test_register_login", Limit: 3, Duration: guber.Minute}},
+		})
+		require.NoError(t, err)
+		rl := hit(t, b.listener.Addr().String(), "test_register_login")
+		assert.Equal(t, int64(3), rl.Limit)
+		assert.Equal(t, int64(2), rl.Remaining)
+		// Limits which were not in the request are unchanged
+		rl = hit(t, b.listener.Addr().String(), "test_register_uploads")
+		assert.Equal(t, int64(10), rl.Limit)
 	})
-	require.NoError(t, err)
-	assert.Equal(t, int64(1899), resp.Responses[0].Remaining)
-	assert.Equal(t, int64(2000), resp.Responses[0].Limit)
-}
 
-func TestMultiRegion(t *testing.T) {
+	t.Run("New peer copies registered limits", func(t *testing.T) {
+		c := newV1Server(t, "localhost:0", conf)
+		defer c.Close()
+		c.srv.SetPeers([]guber.PeerInfo{
+			{GRPCAddress: a.listener.Addr().String()},
+			{GRPCAddress: c.listener.Addr().String(), IsOwner: true},
+		})
 
-	// TODO: Queue a rate limit with multi region behavior on the DataCenterNone cluster
-	// TODO: Check the immediate response is correct
-	// TODO: Wait until the rate limit count shows up on the DataCenterOne and DataCenterTwo cluster
+		testutil.UntilPass(t, 20, 100*clock.Millisecond, func(t testutil.TestingT) {
+			rl := hit(t, c.listener.Addr().String(), "test_register_login")
+			assert.Equal(t, int64(3), rl.Limit)
+		})
+	})
 
-	// TODO: Increment the counts on the DataCenterTwo and DataCenterOne clusters
-	// TODO: Wait until both rate limit count show up on all datacenters
+	t.Run("Rollout", func(t *testing.T) {
+		register := func(l *guber.RegisteredLimit) {
+			_, err := client.RegisterLimits(context.Background(), &guber.RegisterLimitsReq{Limits: []*guber.RegisteredLimit{l}})
+			require.NoError(t, err)
+		}
+		// Count the unique keys which use each limit
+		limits := func(addr string) map[int64]int {
+			counts := make(map[int64]int)
+			for i := 0; i < 200; i++ {
+				counts[hit(t, addr, "test_register_rollout").Limit]++
+			}
+			return counts
+		}
+
+		// The first limit registered is never rolled out
+		register(&guber.RegisteredLimit{Name: "test_register_rollout", Limit: 10, Duration: guber.Minute, RolloutDuration: guber.Minute * 60})
+		assert.Equal(t, map[int64]int{10: 200}, limits(b.listener.Addr().String()))
+
+		register(&guber.RegisteredLimit{Name: "test_register_rollout", Limit: 20, Duration: guber.Minute,
+			RolloutDuration: guber.Minute * 60, RolloutStartPercent: 50})
+		for _, addr := range []string{a.listener.Addr().String(), b.listener.Addr().String()} {
+			counts := limits(addr)
+			assert.Len(t, counts, 2)
+			assert.InDelta(t, 100, counts[20], 40)
+		}
+
+		resp, err := b.srv.ListPeerLimits(context.Background(), &guber.ListPeerLimitsReq{})
+		require.NoError(t, err)
+		for _, l := range resp.Limits {
+			if l.Name == "test_register_rollout" {
+				assert.Equal(t, int64(10), l.RolloutFrom.Limit)
+				assert.NotZero(t, l.RolloutStart)
+			}
+		}
+
+		// Without a rollout the limit applies to every unique key
+		register(&guber.RegisteredLimit{Name: "test_register_rollout", Limit: 30, Duration: guber.Minute})
+		assert.Equal(t, map[int64]int{30: 200}, limits(b.listener.Addr().String()))
+	})
 }
 
-func TestGRPCGateway(t *testing.T) {
-	name := t.Name()
-	key := guber.RandomString(10)
-	address := cluster.GetRandomPeer(cluster.DataCenterNone).HTTPAddress
-	resp, err := http.DefaultClient.Get("http://" + address + "/v1/HealthCheck")
+func TestUpdatePeers(t *testing.T) {
+	a := newV1Server(t, "localhost:0", guber.Config{})
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", guber.Config{})
+	defer b.Close()
+	addrA, addrB := a.listener.Addr().String(), b.listener.Addr().String()
+
+	peerAddrs := func() []string {
+		var addrs []string
+		for _, p := range a.srv.GetPeerList() {
+			addrs = append(addrs, p.Info().GRPCAddress)
+		}
+		return addrs
+	}
+	hit := func(t *testing.T) {
+		client, err := guber.DialV1Server(addrA, nil)
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{{
+					Name:      "test_update_peers",
+					UniqueKey: guber.RandomString(10),
+					Limit:     10,
+					Duration:  guber.Minute,
+					Hits:      1,
+				}},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "", resp.Responses[0].Error)
+		}
+	}
+
+	a.srv.UpdatePeers([]guber.PeerInfo{{GRPCAddress: addrB}}, nil)
+	assert.ElementsMatch(t, []string{addrA, addrB}, peerAddrs())
+	hit(t)
+
+	// Adding a known peer keeps its PeerClient
+	peer, err := a.srv.GetPeer(context.Background(), "test_update_peers")
 	require.NoError(t, err)
-	defer resp.Body.Close()
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	b, err := io.ReadAll(resp.Body)
+	a.srv.UpdatePeers([]guber.PeerInfo{{GRPCAddress: peer.Info().GRPCAddress, IsOwner: peer.Info().IsOwner}}, nil)
+	same, err := a.srv.GetPeer(context.Background(), "test_update_peers")
+	require.NoError(t, err)
+	assert.Same(t, peer, same)
 
-	// This test ensures future upgrades don't accidentally change `under_score` to `camelCase` again.
-	assert.Contains(t, string(b), "peer_count")
+	a.srv.UpdatePeers(nil, []guber.PeerInfo{{GRPCAddress: addrB}})
+	assert.Equal(t, []string{addrA}, peerAddrs())
+	hit(t)
+}
 
-	var hc guber.HealthCheckResp
-	require.NoError(t, json.Unmarshal(b, &hc))
-	assert.Equal(t, int32(10), hc.PeerCount)
+func TestPeerWeightChange(t *testing.T) {
+	a := newV1Server(t, "localhost:0", guber.Config{})
+	defer a.Close()
+	self := guber.PeerInfo{GRPCAddress: a.listener.Addr().String(), IsOwner: true, Weight: 4}
+	// Never dialed, the peer is only added to the hash ring
+	other := guber.PeerInfo{GRPCAddress: "127.0.0.1:1", Weight: 4}
+
+	ownedBySelf := func() int {
+		var owned int
+		for i := 0; i < 1000; i++ {
+			peer, err := a.srv.GetPeer(context.Background(), fmt.Sprintf("%d:account", i))
+			require.NoError(t, err)
+			if peer.Info().IsOwner {
+				owned++
+			}
+		}
+		return owned
+	}
+	a.srv.SetPeers([]guber.PeerInfo{self, other})
+	peer := a.srv.GetPeerList()
+	before := ownedBySelf()
+
+	// The instance sheds load by advertising a reduced weight
+	self.Weight = 1
+	a.srv.SetPeers([]guber.PeerInfo{self, other})
+	assert.Less(t, ownedBySelf(), before)
+	assert.ElementsMatch(t, peer, a.srv.GetPeerList())
+	for _, p := range a.srv.GetPeerList() {
+		if p.Info().IsOwner {
+			assert.Equal(t, 1, p.Info().Weight)
+		}
+	}
+
+	// Member-list reports the change as the peer leaving and joining again
+	restored := self
+	restored.Weight = 4
+	a.srv.UpdatePeers([]guber.PeerInfo{restored}, []guber.PeerInfo{self})
+	assert.Equal(t, before, ownedBySelf())
+}
+
+func TestPeerChurnDuringRequests(t *testing.T) {
+	a := newV1Server(t, "localhost:0", guber.Config{})
+	defer a.Close()
+	self := guber.PeerInfo{GRPCAddress: a.listener.Addr().String(), IsOwner: true}
+	// Never dialed, the peer is only added to and removed from the hash ring
+	churn := guber.PeerInfo{GRPCAddress: "127.0.0.1:1"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			a.srv.UpdatePeers([]guber.PeerInfo{churn}, nil)
+			a.srv.UpdatePeers(nil, []guber.PeerInfo{churn})
+			a.srv.SetPeers([]guber.PeerInfo{self, churn})
+			a.srv.SetPeers([]guber.PeerInfo{self})
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			assert.Len(t, a.srv.GetPeerList(), 1)
+			return
+		default:
+		}
+		peer, err := a.srv.GetPeer(context.Background(), guber.RandomString(10))
+		require.NoError(t, err)
+		require.NotNil(t, peer)
+	}
+}
+
+func TestLease(t *testing.T) {
+	peers := cluster.GetPeers()
+	clientA, err := guber.DialV1Server(peers[0].GRPCAddress, nil)
+	require.NoError(t, err)
+	clientB, err := guber.DialV1Server(peers[1].GRPCAddress, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
 
+	resp, err := clientA.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-a", Ttl: guber.Minute})
 	require.NoError(t, err)
+	assert.True(t, resp.Acquired)
+	assert.Equal(t, "host-a", resp.Holder)
 
-	payload, err := json.Marshal(&guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{
-			{
-				Name:      name,
-				UniqueKey: key,
-				Duration:  guber.Millisecond * 1000,
-				Hits:      1,
-				Limit:     10,
-			},
-		},
-	})
+	// Another holder is denied, whichever peer it asks
+	resp, err = clientB.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-b", Ttl: guber.Minute})
 	require.NoError(t, err)
+	assert.False(t, resp.Acquired)
+	assert.Equal(t, "host-a", resp.Holder)
 
-	resp, err = http.DefaultClient.Post("http://"+address+"/v1/GetRateLimits",
-		"application/json", bytes.NewReader(payload))
+	// The holder renews the lease
+	renew, err := clientB.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-a", Ttl: guber.Minute})
 	require.NoError(t, err)
-	defer resp.Body.Close()
+	assert.True(t, renew.Acquired)
+	assert.GreaterOrEqual(t, renew.ExpireAt, resp.ExpireAt)
 
-	assert.Equal(t, http.StatusOK, resp.StatusCode)
-	b, err = io.ReadAll(resp.Body)
+	// Only the holder may release the lease
+	resp, err = clientA.ReleaseLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-b"})
 	require.NoError(t, err)
-	var r guber.GetRateLimitsResp
+	assert.Equal(t, "host-a", resp.Holder)
+	resp, err = clientA.ReleaseLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-a"})
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Holder)
 
-	// NOTE: It is important to use 'protojson' instead of the standard 'json' package
-	//  else the enums will not be converted properly and json.Unmarshal() will return an
-	//  error.
-	require.NoError(t, json.Unmarshal(b, &r))
-	require.Equal(t, 1, len(r.Responses))
-	assert.Equal(t, guber.Status_UNDER_LIMIT, r.Responses[0].Status)
+	resp, err = clientB.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-b", Ttl: 100})
+	require.NoError(t, err)
+	assert.True(t, resp.Acquired)
+
+	// An expired lease may be acquired by another holder
+	testutil.UntilPass(t, 20, clock.Millisecond*100, func(t testutil.TestingT) {
+		resp, err := clientA.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-a", Ttl: guber.Minute})
+		require.NoError(t, err)
+		assert.True(t, resp.Acquired)
+	})
+
+	_, err = clientA.AcquireLease(ctx, &guber.LeaseReq{Name: "test_lease", Holder: "host-a"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
 }
 
-func TestGetPeerRateLimits(t *testing.T) {
-	name := t.Name()
-	ctx := context.Background()
-	peerClient, err := guber.NewPeerClient(guber.PeerConfig{
-		Info: cluster.GetRandomPeer(cluster.DataCenterNone),
+func TestResetJitter(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{
+			ResetJitterPercent: 50,
+		},
 	})
+	defer srv.Close()
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
 	require.NoError(t, err)
 
-	t.Run("Stable rate check request order", func(t *testing.T) {
-		// Ensure response order matches rate check request order.
-		// Try various batch sizes.
-		createdAt := epochMillis(clock.Now())
-		testCases := []int{1, 2, 5, 10, 100, 1000}
+	sendHit := func(key string) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_reset_jitter",
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Duration:  guber.Minute,
+					Limit:     10,
+					Hits:      1,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
 
-		for _, n := range testCases {
-			t.Run(fmt.Sprintf("Batch size %d", n), func(t *testing.T) {
-				// Build request.
-				req := &guber.GetPeerRateLimitsReq{
-					Requests: make([]*guber.RateLimitReq, n),
-				}
-				for i := 0; i < n; i++ {
-					req.Requests[i] = &guber.RateLimitReq{
-						Name:      name,
-						UniqueKey: guber.RandomString(10),
-						Hits:      0,
-						Limit:     1000 + int64(i),
-						Duration:  1000,
-						Algorithm: guber.Algorithm_TOKEN_BUCKET,
-						Behavior:  guber.Behavior_BATCHING,
-						CreatedAt: &createdAt,
-					}
-				}
+	now := clock.Now().UnixNano() / 1000000
+	resets := make(map[int64]struct{})
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		rl := sendHit(key)
+		assert.GreaterOrEqual(t, rl.ResetTime, now+guber.Minute/2)
+		assert.LessOrEqual(t, rl.ResetTime, now+guber.Minute+guber.Minute/2)
+		resets[rl.ResetTime] = struct{}{}
+
+		// The reset time of an existing rate limit does not change.
+		assert.Equal(t, rl.ResetTime, sendHit(key).ResetTime)
+	}
+	assert.Greater(t, len(resets), 1)
+}
 
-				// Send request.
-				resp, err := peerClient.GetPeerRateLimits(ctx, req)
+func TestDryRun(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{
+			DryRunNames: []string{"test_dry_run_config"},
+		},
+	})
+	defer srv.Close()
 
-				// Verify.
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		behavior guber.Behavior
+	}{
+		{name: "test_dry_run_behavior", behavior: guber.Behavior_DRY_RUN},
+		{name: "test_dry_run_config"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sendHit := func() *guber.RateLimitResp {
+				resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+					Requests: []*guber.RateLimitReq{
+						{
+							Name:      tc.name,
+							UniqueKey: "account:1234",
+							Algorithm: guber.Algorithm_TOKEN_BUCKET,
+							Behavior:  tc.behavior,
+							Duration:  guber.Minute,
+							Limit:     2,
+							Hits:      1,
+						},
+					},
+				})
 				require.NoError(t, err)
-				require.NotNil(t, resp)
-				assert.Len(t, resp.RateLimits, n)
+				require.Equal(t, "", resp.Responses[0].Error)
+				return resp.Responses[0]
+			}
 
-				for i, item := range resp.RateLimits {
-					// Identify response by its unique limit.
-					assert.Equal(t, req.Requests[i].Limit, item.Limit)
-				}
-			})
-		}
+			for i := int64(1); i <= 2; i++ {
+				rl := sendHit()
+				assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+				assert.Equal(t, 2-i, rl.Remaining)
+				assert.Empty(t, rl.Metadata["dry_run_status"])
+			}
+
+			// Over the limit, but reported as under the limit.
+			rl := sendHit()
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, int64(0), rl.Remaining)
+			assert.Equal(t, "OVER_LIMIT", rl.Metadata["dry_run_status"])
+		})
+	}
+}
+
+func TestTokenBucketGreedyRefill(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{
+			GreedyRefillNames: []string{"test_greedy_refill_config"},
+		},
 	})
+	defer srv.Close()
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		behavior guber.Behavior
+	}{
+		{name: "test_greedy_refill_behavior", behavior: guber.Behavior_GREEDY_REFILL},
+		{name: "test_greedy_refill_config"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sendHit := func(hits int64) *guber.RateLimitResp {
+				resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+					Requests: []*guber.RateLimitReq{
+						{
+							Name:      tc.name,
+							UniqueKey: "account:1234",
+							Algorithm: guber.Algorithm_TOKEN_BUCKET,
+							Behavior:  tc.behavior,
+							// One token is refilled every second
+							Duration: guber.Second * 10,
+							Limit:    10,
+							Hits:     hits,
+						},
+					},
+				})
+				require.NoError(t, err)
+				require.Equal(t, "", resp.Responses[0].Error)
+				return resp.Responses[0]
+			}
+
+			now := clock.Now().UnixNano() / 1000000
+			rl := sendHit(10)
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, int64(0), rl.Remaining)
+			assert.Equal(t, now+guber.Second*10, rl.ResetTime)
+
+			rl = sendHit(1)
+			assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+			assert.Equal(t, int64(0), rl.Remaining)
+
+			// A single token is refilled after a second
+			clock.Advance(clock.Second)
+			rl = sendHit(1)
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, int64(0), rl.Remaining)
+			assert.Equal(t, now+guber.Second*11, rl.ResetTime)
+
+			// Partial tokens are not lost between requests
+			clock.Advance(clock.Millisecond * 2500)
+			assert.Equal(t, int64(2), sendHit(0).Remaining)
+			clock.Advance(clock.Millisecond * 500)
+			assert.Equal(t, int64(3), sendHit(0).Remaining)
+
+			// The bucket never holds more than the limit
+			clock.Advance(clock.Minute)
+			rl = sendHit(0)
+			assert.Equal(t, int64(10), rl.Remaining)
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+		})
+	}
 }
 
-// TODO: Add a test for sending no rate limits RateLimitReqList.RateLimits = nil
+func TestTokenBucketMaxCapacity(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
 
-func TestGlobalBehavior(t *testing.T) {
-	const limit = 1000
-	broadcastTimeout := 400 * time.Millisecond
-	createdAt := epochMillis(clock.Now())
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
 
-	makeReq := func(name, key string, hits int64) *guber.RateLimitReq {
-		return &guber.RateLimitReq{
-			Name:      name,
-			UniqueKey: key,
-			Algorithm: guber.Algorithm_TOKEN_BUCKET,
-			Behavior:  guber.Behavior_GLOBAL,
-			Duration:  guber.Minute * 3,
-			Hits:      hits,
-			Limit:     limit,
-			CreatedAt: &createdAt,
-		}
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	sendHit := func(hits int64) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_token_bucket_max_capacity",
+					UniqueKey: "account:1234",
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					// One token is refilled every second
+					Duration:    guber.Second * 10,
+					Limit:       10,
+					MaxCapacity: 30,
+					Hits:        hits,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
 	}
 
-	t.Run("Hits on owner peer", func(t *testing.T) {
-		testCases := []struct {
-			Name string
-			Hits int64
-		}{
-			{Name: "Single hit", Hits: 1},
-			{Name: "Multiple hits", Hits: 10},
-		}
+	// A new bucket starts with max capacity
+	now := clock.Now().UnixNano() / 1000000
+	rl := sendHit(25)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(10), rl.Limit)
+	assert.Equal(t, int64(5), rl.Remaining)
+	assert.Equal(t, now+guber.Second*25, rl.ResetTime)
 
-		for _, testCase := range testCases {
-			t.Run(testCase.Name, func(t *testing.T) {
-				name := t.Name()
-				key := fmt.Sprintf("account:%08x", rand.Int())
-				peers, err := cluster.ListNonOwningDaemons(name, key)
-				require.NoError(t, err)
-				owner, err := cluster.FindOwningDaemon(name, key)
-				require.NoError(t, err)
-				t.Logf("Owner peer: %s", owner.InstanceID)
+	rl = sendHit(10)
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Equal(t, int64(5), rl.Remaining)
 
-				require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+	// Tokens are refilled at the rate of the limit
+	clock.Advance(clock.Second)
+	assert.Equal(t, int64(6), sendHit(0).Remaining)
 
-				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
-				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
-				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+	// The bucket accumulates more than the limit while idle, but never more than max capacity
+	clock.Advance(clock.Second * 20)
+	assert.Equal(t, int64(26), sendHit(0).Remaining)
+	clock.Advance(clock.Minute)
+	rl = sendHit(30)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(0), rl.Remaining)
+}
 
-				// When
-				for i := int64(0); i < testCase.Hits; i++ {
-					sendHit(t, owner, makeReq(name, key, 1), guber.Status_UNDER_LIMIT, 999-i)
-				}
+func TestTokenBucketRollover(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
 
-				// Then
-				// Expect a single global broadcast to all non-owner peers.
-				t.Log("Waiting for global broadcasts")
-				var wg sync.WaitGroup
-				var didOwnerBroadcast, didNonOwnerBroadcast int
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := broadcastCounters[owner.InstanceID] + 1
-					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
-						didOwnerBroadcast++
-						t.Log("Global broadcast from owner")
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	sendHit := func(maxCapacity, hits int64) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:        "test_token_bucket_rollover",
+					UniqueKey:   "account:1234",
+					Algorithm:   guber.Algorithm_TOKEN_BUCKET,
+					Behavior:    guber.Behavior_ROLLOVER,
+					Duration:    guber.Minute,
+					Limit:       10,
+					MaxCapacity: maxCapacity,
+					Hits:        hits,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	// A new bucket starts with the limit and resets at the end of its window
+	now := clock.Now().UnixNano() / 1000000
+	rl := sendHit(25, 4)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(6), rl.Remaining)
+	assert.Equal(t, now+guber.Minute, rl.ResetTime)
+	rl = sendHit(25, 7)
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Equal(t, int64(6), rl.Remaining)
+
+	// The 6 unused tokens are added to the next window
+	clock.Advance(clock.Minute + clock.Second*30)
+	now = clock.Now().UnixNano() / 1000000
+	rl = sendHit(25, 1)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(15), rl.Remaining)
+	assert.Equal(t, now+guber.Minute, rl.ResetTime)
+
+	// Up to max capacity
+	clock.Advance(clock.Minute + clock.Second)
+	assert.Equal(t, int64(25), sendHit(25, 0).Remaining)
+
+	// A bucket idle for an entire window starts from the limit
+	clock.Advance(clock.Minute * 3)
+	assert.Equal(t, int64(9), sendHit(25, 1).Remaining)
+
+	// Without max capacity the unused tokens are dropped at the end of the window
+	clock.Advance(clock.Minute + clock.Second)
+	assert.Equal(t, int64(10), sendHit(0, 0).Remaining)
+}
+
+func TestTokenBucketGrace(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{GracePercent: 10},
+	})
+	defer srv.Close()
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	sendHit := func(key string, hits int64) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_token_bucket_grace",
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Duration:  guber.Minute,
+					Limit:     100,
+					Hits:      hits,
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	for _, tc := range []struct {
+		hits      int64
+		status    guber.Status
+		remaining int64
+	}{
+		{hits: 100, status: guber.Status_UNDER_LIMIT},
+		{hits: 5, status: guber.Status_UNDER_LIMIT},
+		// Only 5 hits of the grace allowance of 10 remain
+		{hits: 6, status: guber.Status_OVER_LIMIT},
+		{hits: 5, status: guber.Status_UNDER_LIMIT},
+		{hits: 1, status: guber.Status_OVER_LIMIT},
+	} {
+		rl := sendHit("account:1", tc.hits)
+		assert.Equal(t, tc.status, rl.Status, "hits %d", tc.hits)
+		assert.Equal(t, tc.remaining, rl.Remaining, "hits %d", tc.hits)
+	}
+
+	// The grace allowance also applies to the first request
+	rl := sendHit("account:2", 110)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(0), rl.Remaining)
+	assert.Equal(t, guber.Status_OVER_LIMIT, sendHit("account:2", 1).Status)
+
+	rl = sendHit("account:3", 111)
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Equal(t, int64(100), rl.Remaining)
+}
+
+func TestLeakyBucket(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name      string
+		Hits      int64
+		Remaining int64
+		Status    guber.Status
+		Sleep     clock.Duration
+	}{
+		{
+			Name:      "first hit",
+			Hits:      1,
+			Remaining: 9,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+		{
+			Name:      "second hit; no leak",
+			Hits:      1,
+			Remaining: 8,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+		{
+			Name:      "third hit; no leak",
+			Hits:      1,
+			Remaining: 7,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Millisecond * 1500,
+		},
+		{
+			Name:      "should leak one hit 3 seconds after first hit",
+			Hits:      0,
+			Remaining: 8,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 3,
+		},
+		{
+			Name:      "3 Seconds later we should have leaked another hit",
+			Hits:      0,
+			Remaining: 9,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "max out our bucket and sleep for 3 seconds",
+			Hits:      9,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "should be over the limit",
+			Hits:      1,
+			Remaining: 0,
+			Status:    guber.Status_OVER_LIMIT,
+			Sleep:     clock.Second * 3,
+		},
+		{
+			Name:      "should have leaked 1 hit",
+			Hits:      0,
+			Remaining: 1,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 60,
+		},
+		{
+			Name:      "should max out the limit",
+			Hits:      0,
+			Remaining: 10,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 60,
+		},
+		{
+			Name:      "should use up the limit and wait until 1 second before duration period",
+			Hits:      10,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 29,
+		},
+		{
+			Name:      "should use up all hits one second before duration period",
+			Hits:      9,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 3,
+		},
+		{
+			Name:      "only have 1 hit remaining",
+			Hits:      1,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      "test_leaky_bucket",
+						UniqueKey: "account:1234",
+						Algorithm: guber.Algorithm_LEAKY_BUCKET,
+						Duration:  guber.Second * 30,
+						Hits:      test.Hits,
+						Limit:     10,
+					},
+				},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Responses, 1)
+
+			rl := resp.Responses[0]
+
+			assert.Equal(t, test.Status, rl.Status)
+			assert.Equal(t, test.Remaining, rl.Remaining)
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
+			clock.Advance(test.Sleep)
+		})
+	}
+}
+
+func TestLeakyBucketWithBurst(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name      string
+		Hits      int64
+		Remaining int64
+		Status    guber.Status
+		Sleep     clock.Duration
+	}{
+		{
+			Name:      "first hit",
+			Hits:      1,
+			Remaining: 19,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+		{
+			Name:      "second hit; no leak",
+			Hits:      1,
+			Remaining: 18,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+		{
+			Name:      "third hit; no leak",
+			Hits:      1,
+			Remaining: 17,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Millisecond * 1500,
+		},
+		{
+			Name:      "should leak one hit 3 seconds after first hit",
+			Hits:      0,
+			Remaining: 18,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 3,
+		},
+		{
+			Name:      "3 Seconds later we should have leaked another hit",
+			Hits:      0,
+			Remaining: 19,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "max out our bucket and sleep for 3 seconds",
+			Hits:      19,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "should be over the limit",
+			Hits:      1,
+			Remaining: 0,
+			Status:    guber.Status_OVER_LIMIT,
+			Sleep:     clock.Second * 3,
+		},
+		{
+			Name:      "should have leaked 1 hit",
+			Hits:      0,
+			Remaining: 1,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second * 60,
+		},
+		{
+			Name:      "should max out remaining",
+			Hits:      0,
+			Remaining: 20,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      "test_leaky_bucket_with_burst",
+						UniqueKey: "account:1234",
+						Algorithm: guber.Algorithm_LEAKY_BUCKET,
+						Duration:  guber.Second * 30,
+						Hits:      test.Hits,
+						Limit:     10,
+						Burst:     20,
+					},
+				},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Responses, 1)
+
+			rl := resp.Responses[0]
+
+			assert.Equal(t, test.Status, rl.Status)
+			assert.Equal(t, test.Remaining, rl.Remaining)
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
+			clock.Advance(test.Sleep)
+		})
+	}
+}
+
+func TestLeakyBucketGregorian(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name      string
+		Hits      int64
+		Remaining int64
+		Status    guber.Status
+		Sleep     clock.Duration
+	}{
+		{
+			Name:      "first hit",
+			Hits:      1,
+			Remaining: 59,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Millisecond * 500,
+		},
+		{
+			Name:      "second hit; no leak",
+			Hits:      1,
+			Remaining: 58,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Millisecond * 1200,
+		},
+		{
+			Name:      "third hit; leak one hit",
+			Hits:      1,
+			Remaining: 58,
+			Status:    guber.Status_UNDER_LIMIT,
+		},
+	}
+
+	// Truncate to the nearest minute
+	now := clock.Now()
+	now = now.Truncate(1 * time.Minute)
+	// So we don't start on the minute boundary
+	now = now.Add(time.Millisecond * 100)
+	name := t.Name()
+	key := guber.RandomString(10)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      name,
+						UniqueKey: key,
+						Behavior:  guber.Behavior_DURATION_IS_GREGORIAN,
+						Algorithm: guber.Algorithm_LEAKY_BUCKET,
+						Duration:  guber.GregorianMinutes,
+						Hits:      test.Hits,
+						Limit:     60,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			rl := resp.Responses[0]
+			assert.Equal(t, test.Status, rl.Status)
+			assert.Equal(t, test.Remaining, rl.Remaining)
+			assert.Equal(t, int64(60), rl.Limit)
+			assert.Greater(t, rl.ResetTime, now.Unix())
+			clock.Advance(test.Sleep)
+		})
+	}
+}
+
+func TestLeakyBucketQueueDepth(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	// One hit leaks out every 6 seconds
+	hit := func(hits int64) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_leaky_bucket_queue_depth",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	rl := hit(4)
+	assert.Equal(t, int64(4), rl.QueueDepth)
+	assert.Equal(t, int64(24_000), rl.DrainTime)
+
+	clock.Advance(6 * clock.Second)
+	rl = hit(0)
+	assert.Equal(t, int64(3), rl.QueueDepth)
+	assert.Equal(t, int64(18_000), rl.DrainTime)
+
+	// An over the limit request reports the queue without the rejected hits
+	rl = hit(8)
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Equal(t, int64(3), rl.QueueDepth)
+	assert.Equal(t, int64(18_000), rl.DrainTime)
+}
+
+func TestLeakyBucketNegativeHits(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.PeerAt(0).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name      string
+		Hits      int64
+		Remaining int64
+		Status    guber.Status
+		Sleep     clock.Duration
+	}{
+		{
+			Name:      "first hit",
+			Hits:      1,
+			Remaining: 9,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "can increase remaining",
+			Hits:      -1,
+			Remaining: 10,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "remaining should be zero",
+			Hits:      10,
+			Remaining: 0,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+		{
+			Name:      "can append one to remaining when remaining is zero",
+			Hits:      -1,
+			Remaining: 1,
+			Status:    guber.Status_UNDER_LIMIT,
+			Sleep:     clock.Duration(0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      "test_leaky_bucket_negative",
+						UniqueKey: "account:12345",
+						Algorithm: guber.Algorithm_LEAKY_BUCKET,
+						Duration:  guber.Second * 30,
+						Hits:      test.Hits,
+						Limit:     10,
+					},
+				},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Responses, 1)
+
+			rl := resp.Responses[0]
+
+			assert.Equal(t, test.Status, rl.Status)
+			assert.Equal(t, test.Remaining, rl.Remaining)
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, clock.Now().Unix()+(rl.Limit-rl.Remaining)*3, rl.ResetTime/1000)
+			clock.Advance(test.Sleep)
+		})
+	}
+}
+
+func TestLeakyBucketRequestMoreThanAvailable(t *testing.T) {
+	// Freeze time so we don't leak during the test
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	sendHit := func(status guber.Status, remain int64, hits int64) *guber.RateLimitResp {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_leaky_more_than_available",
+					UniqueKey: "account:123456",
+					Algorithm: guber.Algorithm_LEAKY_BUCKET,
+					Duration:  guber.Millisecond * 1000,
+					Hits:      hits,
+					Limit:     2000,
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "", resp.Responses[0].Error)
+		assert.Equal(t, status, resp.Responses[0].Status)
+		assert.Equal(t, remain, resp.Responses[0].Remaining)
+		assert.Equal(t, int64(2000), resp.Responses[0].Limit)
+		return resp.Responses[0]
+	}
+
+	// Use half of the bucket
+	sendHit(guber.Status_UNDER_LIMIT, 1000, 1000)
+
+	// Ask for more than the rate limit has and the remainder is still 1000.
+	// See NOTE in algorithms.go
+	sendHit(guber.Status_OVER_LIMIT, 1000, 1500)
+
+	// Now other clients can ask for some of the remaining until we hit our limit
+	sendHit(guber.Status_UNDER_LIMIT, 500, 500)
+	sendHit(guber.Status_UNDER_LIMIT, 100, 400)
+	sendHit(guber.Status_UNDER_LIMIT, 0, 100)
+	sendHit(guber.Status_OVER_LIMIT, 0, 1)
+}
+
+func TestMissingFields(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Req    *guber.RateLimitReq
+		Status guber.Status
+		Error  string
+	}{
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Limit:     10,
+				Duration:  0,
+			},
+			Error:  "", // No Error
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:12345",
+				Hits:      1,
+				Duration:  10000,
+				Limit:     0,
+			},
+			Error:  "", // No Error
+			Status: guber.Status_OVER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  10000,
+				Limit:     5,
+			},
+			Error:  "field 'namespace' cannot be empty",
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:     "test_missing_fields",
+				Hits:     1,
+				Duration: 10000,
+				Limit:    5,
+			},
+			Error:  "field 'unique_key' cannot be empty",
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  -1,
+				Limit:     5,
+			},
+			Error:  "field 'duration' cannot be negative",
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  math.MaxInt64,
+				Limit:     5,
+			},
+			Error:  fmt.Sprintf("field 'duration' of '%d' exceeds the max duration '%d'", int64(math.MaxInt64), guber.MaxDuration),
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  10000,
+				Limit:     -5,
+			},
+			Error:  "field 'limit' cannot be negative",
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  10000,
+				Limit:     math.MaxInt64,
+			},
+			Error:  fmt.Sprintf("field 'limit' of '%d' exceeds the max limit '%d'", int64(math.MaxInt64), guber.MaxLimit),
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      1,
+				Duration:  1,
+				Limit:     guber.MaxLimit,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			},
+			Error:  "", // The smallest duration with the largest limit
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:      "test_missing_fields",
+				UniqueKey: "account:1234",
+				Hits:      math.MinInt64,
+				Duration:  10000,
+				Limit:     5,
+			},
+			Error:  fmt.Sprintf("field 'hits' of '%d' exceeds the max hits of '%d' or '-%d'", int64(math.MinInt64), guber.MaxLimit, guber.MaxLimit),
+			Status: guber.Status_UNDER_LIMIT,
+		},
+		{
+			Req: &guber.RateLimitReq{
+				Name:        "test_missing_fields",
+				UniqueKey:   "account:1234",
+				Hits:        1,
+				Duration:    10000,
+				Limit:       5,
+				MaxCapacity: -1,
+				Behavior:    guber.Behavior_GREEDY_REFILL,
+			},
+			Error:  "field 'max_capacity' cannot be negative",
+			Status: guber.Status_UNDER_LIMIT,
+		},
+	}
+
+	for i, test := range tests {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{test.Req},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, test.Error, resp.Responses[0].Error, i)
+		assert.Equal(t, test.Status, resp.Responses[0].Status, i)
+	}
+}
+
+func TestGlobalRateLimits(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+	var firstResetTime int64
+
+	sendHit := func(client guber.V1Client, status guber.Status, hits, remain int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  guber.Minute * 3,
+					Hits:      hits,
+					Limit:     5,
+				},
+			},
+		})
+		require.NoError(t, err)
+		item := resp.Responses[0]
+		assert.Equal(t, "", item.Error)
+		assert.Equal(t, remain, item.Remaining)
+		assert.Equal(t, status, item.Status)
+		assert.Equal(t, int64(5), item.Limit)
+
+		// ResetTime should not change during test.
+		if firstResetTime == 0 {
+			firstResetTime = item.ResetTime
+		}
+		assert.Equal(t, firstResetTime, item.ResetTime)
+
+		// ensure that we have a canonical host
+		assert.NotEmpty(t, item.Metadata["owner"])
+	}
+
+	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+
+	// Our first hit should create the request on the peer and queue for async forward
+	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, 1, 4)
+
+	// Our second should be processed as if we own it since the async forward hasn't occurred yet
+	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, 2, 2)
+
+	testutil.UntilPass(t, 20, clock.Millisecond*200, func(t testutil.TestingT) {
+		// Inspect peers metrics, ensure the peer sent the global rate limit to the owner
+		metricsURL := fmt.Sprintf("http://%s/metrics", peers[0].Config().HTTPListenAddress)
+		m, err := getMetricRequest(metricsURL, "gubernator_global_send_duration_count")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, int(m.Value))
+	})
+
+	require.NoError(t, waitForBroadcast(clock.Second*3, owner, 1))
+
+	// Check different peers, they should have gotten the broadcast from the owner
+	sendHit(peers[1].MustClient(), guber.Status_UNDER_LIMIT, 0, 2)
+	sendHit(peers[2].MustClient(), guber.Status_UNDER_LIMIT, 0, 2)
+
+	// Non owning peer should calculate the rate limit remaining before forwarding
+	// to the owner.
+	sendHit(peers[3].MustClient(), guber.Status_UNDER_LIMIT, 2, 0)
+
+	require.NoError(t, waitForBroadcast(clock.Second*3, owner, 2))
+
+	sendHit(peers[4].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
+}
+
+// Ensure global broadcast updates all peers when GetRateLimits is called on
+// either owner or non-owner peer.
+func TestGlobalRateLimitsWithLoadBalancing(t *testing.T) {
+	ctx := context.Background()
+	name := t.Name()
+	key := guber.RandomString(10)
+
+	// Determine owner and non-owner peers.
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+	nonOwner := peers[0]
+
+	// Connect to owner and non-owner peers in round robin.
+	dialOpts := []grpc.DialOption{
+		grpc.WithResolvers(guber.NewStaticBuilder()),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin":{}}]}`),
+	}
+	address := fmt.Sprintf("static:///%s,%s", owner.PeerInfo.GRPCAddress, nonOwner.PeerInfo.GRPCAddress)
+	conn, err := grpc.DialContext(ctx, address, dialOpts...)
+	require.NoError(t, err)
+	client := guber.NewV1Client(conn)
+
+	sendHit := func(client guber.V1Client, status guber.Status, i int) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*clock.Second)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  5 * guber.Minute,
+					Hits:      1,
+					Limit:     2,
+				},
+			},
+		})
+		require.NoError(t, err, i)
+		item := resp.Responses[0]
+		assert.Equal(t, "", item.Error, fmt.Sprintf("unexpected error, iteration %d", i))
+		assert.Equal(t, status, item.Status, fmt.Sprintf("mismatch status, iteration %d", i))
+	}
+
+	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+
+	// Send two hits that should be processed by the owner and non-owner and
+	// deplete the limit consistently.
+	sendHit(client, guber.Status_UNDER_LIMIT, 1)
+	sendHit(client, guber.Status_UNDER_LIMIT, 2)
+	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 1))
+
+	// All successive hits should return OVER_LIMIT.
+	for i := 2; i <= 10; i++ {
+		sendHit(client, guber.Status_OVER_LIMIT, i)
+	}
+}
+
+func TestGlobalRateLimitsPeerOverLimit(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+
+	sendHit := func(expectedStatus guber.Status, hits, expectedRemaining int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*clock.Second)
+		defer cancel()
+		resp, err := peers[0].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  5 * guber.Minute,
+					Hits:      hits,
+					Limit:     2,
+				},
+			},
+		})
+		assert.NoError(t, err)
+		item := resp.Responses[0]
+		assert.Equal(t, "", item.Error, "unexpected error")
+		assert.Equal(t, expectedStatus, item.Status, "mismatch status")
+		assert.Equal(t, expectedRemaining, item.Remaining, "mismatch remaining")
+	}
+
+	require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+
+	// Send two hits that should be processed by the owner and the broadcast to
+	// peer, depleting the remaining.
+	sendHit(guber.Status_UNDER_LIMIT, 1, 1)
+	sendHit(guber.Status_UNDER_LIMIT, 1, 0)
+
+	// Wait for the broadcast from the owner to the peer
+	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 1))
+
+	// Since the remainder is 0, the peer should return OVER_LIMIT on next hit.
+	sendHit(guber.Status_OVER_LIMIT, 1, 0)
+
+	// Wait for the broadcast from the owner to the peer.
+	require.NoError(t, waitForBroadcast(3*clock.Second, owner, 2))
+
+	// The status should still be OVER_LIMIT.
+	sendHit(guber.Status_OVER_LIMIT, 0, 0)
+}
+
+func TestGlobalRequestMoreThanAvailable(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+
+	sendHit := func(client guber.V1Client, expectedStatus guber.Status, hits int64, remaining int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_LEAKY_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  guber.Minute * 1_000,
+					Hits:      hits,
+					Limit:     100,
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", resp.Responses[0].GetError())
+		assert.Equal(t, expectedStatus, resp.Responses[0].GetStatus())
+	}
+
+	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
+
+	// Ensure GRPC has connections to each peer before we start, as we want
+	// the actual test requests to happen quite fast.
+	for _, p := range peers {
+		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 0, 100)
+	}
+
+	// Send a request for 50 hits from each non owning peer in the cluster. These requests
+	// will be queued and sent to the owner as accumulated hits. As a result of the async nature
+	// of `Behavior_GLOBAL` rate limit requests spread across peers like this will be allowed to
+	// over-consume their resource within the rate limit window until the owner is updated and
+	// a broadcast to all peers is received.
+	//
+	// The maximum number of resources that can be over-consumed can be calculated by multiplying
+	// the remainder by the number of peers in the cluster. For example: If you have a remainder of 100
+	// and a cluster of 10 instances, then the maximum over-consumed resource is 1,000. If you need
+	// a more accurate remaining calculation, and wish to avoid over consuming a resource, then do
+	// not use `Behavior_GLOBAL`.
+	for _, p := range peers {
+		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 50, 50)
+	}
+
+	// Wait for the broadcast from the owner to the peers
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
+
+	// We should be over the limit
+	sendHit(peers[0].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
+}
+
+func TestGlobalNegativeHits(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+
+	sendHit := func(client guber.V1Client, status guber.Status, hits int64, remaining int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  guber.Minute * 100,
+					Hits:      hits,
+					Limit:     2,
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", resp.Responses[0].GetError())
+		assert.Equal(t, status, resp.Responses[0].GetStatus())
+		assert.Equal(t, remaining, resp.Responses[0].Remaining)
+	}
+
+	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+
+	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
+	require.NoError(t, err)
+
+	// Send a negative hit on a rate limit with no hits
+	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT, -1, 3)
+
+	// Wait for the negative remaining to propagate
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
+
+	// Send another negative hit to a different peer
+	sendHit(peers[1].MustClient(), guber.Status_UNDER_LIMIT, -1, 4)
+
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+2))
+
+	// Should have 4 in the remainder
+	sendHit(peers[2].MustClient(), guber.Status_UNDER_LIMIT, 4, 0)
+
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+3))
+
+	sendHit(peers[3].MustClient(), guber.Status_UNDER_LIMIT, 0, 0)
+}
+
+func TestGlobalResetRemaining(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+
+	sendHit := func(client guber.V1Client, expectedStatus guber.Status, hits int64, remaining int64) {
+		ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+		defer cancel()
+		resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      name,
+					UniqueKey: key,
+					Algorithm: guber.Algorithm_LEAKY_BUCKET,
+					Behavior:  guber.Behavior_GLOBAL,
+					Duration:  guber.Minute * 1_000,
+					Hits:      hits,
+					Limit:     100,
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "", resp.Responses[0].GetError())
+		assert.Equal(t, expectedStatus, resp.Responses[0].GetStatus())
+		assert.Equal(t, remaining, resp.Responses[0].Remaining)
+	}
+
+	require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+
+	prev := getMetricValue(t, owner, "gubernator_broadcast_duration_count")
+	require.NoError(t, err)
+
+	for _, p := range peers {
+		sendHit(p.MustClient(), guber.Status_UNDER_LIMIT, 50, 50)
+	}
+
+	// Wait for the broadcast from the owner to the peers
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+1))
+
+	// We should be over the limit and remaining should be zero
+	sendHit(peers[0].MustClient(), guber.Status_OVER_LIMIT, 1, 0)
+
+	// Now reset the remaining
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
+	resp, err := peers[0].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Behavior:  guber.Behavior_GLOBAL | guber.Behavior_RESET_REMAINING,
+				Duration:  guber.Minute * 1_000,
+				Hits:      0,
+				Limit:     100,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, 100, resp.Responses[0].Remaining)
+
+	// Wait for the reset to propagate.
+	require.NoError(t, waitForBroadcast(clock.Second*10, owner, prev+2))
+
+	// Check a different peer to ensure remaining has been reset
+	resp, err = peers[1].MustClient().GetRateLimits(ctx, &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Behavior:  guber.Behavior_GLOBAL,
+				Duration:  guber.Minute * 1_000,
+				Hits:      0,
+				Limit:     100,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, 100, resp.Responses[0].Remaining)
+}
+
+func TestChangeLimit(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Remaining int64
+		Algorithm guber.Algorithm
+		Status    guber.Status
+		Name      string
+		Limit     int64
+	}{
+		{
+			Name:      "Should subtract 1 from remaining",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 99,
+			Limit:     100,
+		},
+		{
+			Name:      "Should subtract 1 from remaining",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 98,
+			Limit:     100,
+		},
+		{
+			Name:      "Should subtract 1 from remaining and change limit to 10",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 7,
+			Limit:     10,
+		},
+		{
+			Name:      "Should subtract 1 from remaining with new limit of 10",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 6,
+			Limit:     10,
+		},
+		{
+			Name:      "Should subtract 1 from remaining with new limit of 200",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 195,
+			Limit:     200,
+		},
+		{
+			Name:      "Should subtract 1 from remaining for leaky bucket",
+			Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 99,
+			Limit:     100,
+		},
+		{
+			Name:      "Should subtract 1 from remaining for leaky bucket after limit change",
+			Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 9,
+			Limit:     10,
+		},
+		{
+			Name:      "Should subtract 1 from remaining for leaky bucket with new limit",
+			Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Remaining: 8,
+			Limit:     10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      "test_change_limit",
+						UniqueKey: "account:1234",
+						Algorithm: tt.Algorithm,
+						Duration:  guber.Millisecond * 9000,
+						Limit:     tt.Limit,
+						Hits:      1,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			rl := resp.Responses[0]
+
+			assert.Equal(t, tt.Status, rl.Status)
+			assert.Equal(t, tt.Remaining, rl.Remaining)
+			assert.Equal(t, tt.Limit, rl.Limit)
+			assert.True(t, rl.ResetTime != 0)
+		})
+	}
+}
+
+func TestResetRemaining(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Remaining int64
+		Algorithm guber.Algorithm
+		Behavior  guber.Behavior
+		Status    guber.Status
+		Name      string
+		Limit     int64
+	}{
+		{
+			Name:      "Should subtract 1 from remaining",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Behavior:  guber.Behavior_BATCHING,
+			Remaining: 99,
+			Limit:     100,
+		},
+		{
+			Name:      "Should subtract 2 from remaining",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Behavior:  guber.Behavior_BATCHING,
+			Remaining: 98,
+			Limit:     100,
+		},
+		{
+			Name:      "Should reset the remaining",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Behavior:  guber.Behavior_RESET_REMAINING,
+			Remaining: 100,
+			Limit:     100,
+		},
+		{
+			Name:      "Should subtract 1 from remaining after reset",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Status:    guber.Status_UNDER_LIMIT,
+			Behavior:  guber.Behavior_BATCHING,
+			Remaining: 99,
+			Limit:     100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      "test_reset_remaining",
+						UniqueKey: "account:1234",
+						Algorithm: tt.Algorithm,
+						Duration:  guber.Millisecond * 9000,
+						Behavior:  tt.Behavior,
+						Limit:     tt.Limit,
+						Hits:      1,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			rl := resp.Responses[0]
+
+			assert.Equal(t, tt.Status, rl.Status)
+			assert.Equal(t, tt.Remaining, rl.Remaining)
+			assert.Equal(t, tt.Limit, rl.Limit)
+		})
+	}
+}
+
+func TestHitsZeroQueryOnly(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	send := func(r *guber.RateLimitReq) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{r},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	t.Run("Token bucket", func(t *testing.T) {
+		req := func(hits, duration int64) *guber.RateLimitReq {
+			return &guber.RateLimitReq{
+				Name:      "test_hits_zero",
+				UniqueKey: "account:1",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  duration,
+				Limit:     10,
+				Hits:      hits,
+			}
+		}
+
+		// Should not create the rate limit
+		rl := send(req(0, guber.Minute))
+		assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+		assert.Equal(t, int64(10), rl.Remaining)
+
+		// The window should begin with the first hit
+		clock.Advance(30 * clock.Second)
+		rl = send(req(1, guber.Minute))
+		assert.Equal(t, int64(9), rl.Remaining)
+		expire := clock.Now().UnixNano()/1_000_000 + guber.Minute
+		assert.Equal(t, expire, rl.ResetTime)
+
+		// A shorter duration expires the rate limit, which should only apply to the response
+		clock.Advance(30 * clock.Second)
+		rl = send(req(0, 10*guber.Second))
+		assert.Equal(t, clock.Now().UnixNano()/1_000_000+10*guber.Second, rl.ResetTime)
+
+		rl = send(req(0, guber.Minute))
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Equal(t, expire, rl.ResetTime)
+	})
+
+	t.Run("Leaky bucket", func(t *testing.T) {
+		req := func(hits, burst int64) *guber.RateLimitReq {
+			return &guber.RateLimitReq{
+				Name:      "test_hits_zero",
+				UniqueKey: "account:2",
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Burst:     burst,
+				Hits:      hits,
+			}
+		}
+
+		rl := send(req(0, 10))
+		assert.Equal(t, int64(10), rl.Remaining)
+
+		rl = send(req(1, 10))
+		assert.Equal(t, int64(9), rl.Remaining)
+
+		// A larger burst refills the bucket, which should only apply to the response
+		rl = send(req(0, 20))
+		assert.Equal(t, int64(20), rl.Remaining)
+
+		rl = send(req(0, 10))
+		assert.Equal(t, int64(9), rl.Remaining)
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	// Check that the cluster is healthy to start with.
+	for _, peer := range cluster.GetDaemons() {
+		healthResp, err := peer.MustClient().HealthCheck(context.Background(), &guber.HealthCheckReq{})
+		require.NoError(t, err)
+		assert.Equal(t, "healthy", healthResp.Status)
+	}
+
+	// Stop the cluster to ensure errors occur on our instance.
+	cluster.Stop()
+
+	// Check the health again to get back the connection error.
+	testutil.UntilPass(t, 20, 300*clock.Millisecond, func(t testutil.TestingT) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, peer := range cluster.GetDaemons() {
+			_, err := peer.MustClient().HealthCheck(ctx, &guber.HealthCheckReq{})
+			assert.Error(t, err, "connect: connection refused")
+		}
+	})
+
+	// Restart so cluster is ready for next test.
+	require.NoError(t, startGubernator())
+}
+
+func TestHealthCheckEndpoints(t *testing.T) {
+	client := cluster.GetDaemons()[0].MustClient()
+
+	// Endpoints are only included when requested
+	health, err := client.HealthCheck(context.Background(), &guber.HealthCheckReq{})
+	require.NoError(t, err)
+	assert.Empty(t, health.Endpoints)
+
+	health, err = client.HealthCheck(context.Background(), &guber.HealthCheckReq{IncludeEndpoints: true})
+	require.NoError(t, err)
+	require.Len(t, health.Endpoints, int(health.PeerCount))
+
+	expected := make(map[string]guber.PeerInfo)
+	for _, peer := range cluster.GetPeers() {
+		expected[peer.GRPCAddress] = peer
+	}
+	ownership := make(map[string]float64)
+	for _, ep := range health.Endpoints {
+		peer, ok := expected[ep.GrpcAddress]
+		require.True(t, ok, "unexpected endpoint '%s'", ep.GrpcAddress)
+		assert.Equal(t, peer.HTTPAddress, ep.HttpAddress)
+		assert.Equal(t, peer.DataCenter, ep.DataCenter)
+		assert.Equal(t, int32(1), ep.Weight)
+		ownership[ep.DataCenter] += ep.Ownership
+	}
+	assert.Len(t, ownership, 2)
+	// The peers of each data center own the entire hash ring of the data center
+	for dc, o := range ownership {
+		assert.InDelta(t, 1.0, o, 0.0001, "data center '%s'", dc)
+	}
+}
+
+func TestHealthCheckVersions(t *testing.T) {
+	client := cluster.GetDaemons()[0].MustClient()
+
+	// Versions are only included when requested
+	health, err := client.HealthCheck(context.Background(), &guber.HealthCheckReq{})
+	require.NoError(t, err)
+	assert.Empty(t, health.Versions)
+
+	health, err = client.HealthCheck(context.Background(), &guber.HealthCheckReq{IncludeVersions: true})
+	require.NoError(t, err)
+	assert.Equal(t, guber.Healthy, health.Status)
+	require.Len(t, health.Versions, int(health.PeerCount))
+
+	expected := make(map[string]guber.PeerInfo)
+	for _, peer := range cluster.GetPeers() {
+		expected[peer.GRPCAddress] = peer
+	}
+	for _, v := range health.Versions {
+		peer, ok := expected[v.GrpcAddress]
+		require.True(t, ok, "unexpected peer '%s'", v.GrpcAddress)
+		assert.Equal(t, peer.DataCenter, v.DataCenter)
+		assert.Empty(t, v.Error)
+		assert.Equal(t, guber.Version, v.Version)
+		assert.Equal(t, guber.Commit, v.Commit)
+		// Every daemon of the cluster is configured alike
+		assert.NotEmpty(t, v.ConfigChecksum)
+		assert.Equal(t, health.ConfigChecksum, v.ConfigChecksum)
+	}
+}
+
+func TestConfigChecksum(t *testing.T) {
+	checksum := func(conf guber.Config) string {
+		srv := newV1Server(t, "localhost:0", conf)
+		defer srv.Close()
+		health, err := srv.srv.HealthCheck(context.Background(), &guber.HealthCheckReq{})
+		require.NoError(t, err)
+		require.NotEmpty(t, health.ConfigChecksum)
+		return health.ConfigChecksum
+	}
+
+	base := checksum(guber.Config{Behaviors: guber.BehaviorConfig{DryRunNames: []string{"a", "b"}}})
+	// Settings which only affect the instance itself, and the order of names, do not matter
+	assert.Equal(t, base, checksum(guber.Config{
+		Behaviors: guber.BehaviorConfig{DryRunNames: []string{"b", "a"}},
+		CacheSize: 10,
+		Workers:   1,
+	}))
+
+	for name, conf := range map[string]guber.Config{
+		"behaviors":   {Behaviors: guber.BehaviorConfig{DryRunNames: []string{"a", "b"}, GracePercent: 5}},
+		"policies":    {Behaviors: guber.BehaviorConfig{DryRunNames: []string{"a", "b"}}, NamespacePolicies: []guber.NamespacePolicy{{Prefix: "a", MaxLimit: 10}}},
+		"normalizers": {Behaviors: guber.BehaviorConfig{DryRunNames: []string{"a", "b"}}, Normalizers: []guber.NormalizeFunc{guber.NormalizeLowercase}},
+		"hash key":    {Behaviors: guber.BehaviorConfig{DryRunNames: []string{"a", "b"}}, HashKey: guber.DelimitedHashKey},
+	} {
+		assert.NotEqual(t, base, checksum(conf), name)
+	}
+}
+
+type resolverClientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+}
+
+func (cc *resolverClientConn) UpdateState(state resolver.State) error {
+	cc.states <- state
+	return nil
+}
+
+func (cc *resolverClientConn) ReportError(error) {}
+
+func (cc *resolverClientConn) ParseServiceConfig(string) *serviceconfig.ParseResult {
+	return nil
+}
+
+func TestResolverBuilder(t *testing.T) {
+	peer := cluster.GetRandomPeer(cluster.DataCenterOne)
+
+	t.Run("Resolves every peer in the data center", func(t *testing.T) {
+		builder := guber.NewResolverBuilder(guber.ResolverConfig{DataCenter: cluster.DataCenterOne})
+		cc := &resolverClientConn{states: make(chan resolver.State, 10)}
+		r, err := builder.Build(resolver.Target{URL: url.URL{Scheme: guber.ResolverScheme, Path: "/" + peer.GRPCAddress}}, cc, resolver.BuildOptions{})
+		require.NoError(t, err)
+		defer r.Close()
+
+		var state resolver.State
+		select {
+		case state = <-cc.states:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for resolver state")
+		}
+
+		var expected, addrs []string
+		for _, p := range cluster.GetPeers() {
+			if p.DataCenter == cluster.DataCenterOne {
+				expected = append(expected, p.GRPCAddress)
+			}
+		}
+		for _, addr := range state.Addresses {
+			addrs = append(addrs, addr.Addr)
+			dc, weight := guber.AddressEndpoint(addr)
+			assert.Equal(t, cluster.DataCenterOne, dc)
+			assert.Equal(t, int32(1), weight)
+		}
+		assert.ElementsMatch(t, expected, addrs)
+	})
+
+	t.Run("Dial", func(t *testing.T) {
+		conn, err := grpc.Dial(guber.ResolverScheme+":///"+peer.GRPCAddress,
+			grpc.WithResolvers(guber.NewResolverBuilder(guber.ResolverConfig{})),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		client := guber.NewV1Client(conn)
+
+		for i := 0; i < 10; i++ {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{
+					{
+						Name:      t.Name(),
+						UniqueKey: guber.RandomString(10),
+						Duration:  guber.Minute,
+						Hits:      1,
+						Limit:     10,
+					},
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "", resp.Responses[0].Error)
+			assert.Equal(t, int64(9), resp.Responses[0].Remaining)
+		}
+	})
+
+	t.Run("Invalid service config", func(t *testing.T) {
+		builder := guber.NewResolverBuilder(guber.ResolverConfig{ServiceConfig: "round_robin"})
+		cc := &resolverClientConn{states: make(chan resolver.State, 10)}
+		_, err := builder.Build(resolver.Target{URL: url.URL{Scheme: guber.ResolverScheme, Path: "/" + peer.GRPCAddress}}, cc, resolver.BuildOptions{})
+		assert.Error(t, err)
+	})
+}
+
+func TestServiceConfig(t *testing.T) {
+	d := cluster.DaemonAt(0)
+	get := func(query string) string {
+		resp, err := http.Get(fmt.Sprintf("http://%s/v1/ServiceConfig%s", d.Config().HTTPListenAddress, query))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	config := get("")
+	assert.Contains(t, config, `"methodConfig":`)
+	assert.Equal(t, `grpc_config=[{"serviceConfig":`+config+`}]`, get("?format=dns"))
+
+	// The GRPC client rejects an invalid service config when dialing
+	conn, err := grpc.Dial(d.Config().GRPCListenAddress,
+		grpc.WithDefaultServiceConfig(config),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = guber.NewV1Client(conn).HealthCheck(context.Background(), &guber.HealthCheckReq{})
+	require.NoError(t, err)
+}
+
+func TestLeakyBucketDivBug(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	name := t.Name()
+	key := guber.RandomString(10)
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Millisecond * 1000,
+				Hits:      1,
+				Limit:     2000,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Responses[0].Error)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Responses[0].Status)
+	assert.Equal(t, int64(1999), resp.Responses[0].Remaining)
+	assert.Equal(t, int64(2000), resp.Responses[0].Limit)
+
+	// Should result in a rate of 0.5
+	resp, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Millisecond * 1000,
+				Hits:      100,
+				Limit:     2000,
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1899), resp.Responses[0].Remaining)
+	assert.Equal(t, int64(2000), resp.Responses[0].Limit)
+}
+
+func TestMultiRegion(t *testing.T) {
+
+	// TODO: Queue a rate limit with multi region behavior on the DataCenterNone cluster
+	// TODO: Check the immediate response is correct
+	// TODO: Wait until the rate limit count shows up on the DataCenterOne and DataCenterTwo cluster
+
+	// TODO: Increment the counts on the DataCenterTwo and DataCenterOne clusters
+	// TODO: Wait until both rate limit count show up on all datacenters
+}
+
+func TestGRPCGateway(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	address := cluster.GetRandomPeer(cluster.DataCenterNone).HTTPAddress
+	resp, err := http.DefaultClient.Get("http://" + address + "/v1/HealthCheck")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err := io.ReadAll(resp.Body)
+
+	// This test ensures future upgrades don't accidentally change `under_score` to `camelCase` again.
+	assert.Contains(t, string(b), "peer_count")
+
+	var hc guber.HealthCheckResp
+	require.NoError(t, json.Unmarshal(b, &hc))
+	assert.Equal(t, int32(10), hc.PeerCount)
+
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(&guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      name,
+				UniqueKey: key,
+				Duration:  guber.Millisecond * 1000,
+				Hits:      1,
+				Limit:     10,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	resp, err = http.DefaultClient.Post("http://"+address+"/v1/GetRateLimits",
+		"application/json", bytes.NewReader(payload))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var r guber.GetRateLimitsResp
+
+	// NOTE: It is important to use 'protojson' instead of the standard 'json' package
+	//  else the enums will not be converted properly and json.Unmarshal() will return an
+	//  error.
+	require.NoError(t, json.Unmarshal(b, &r))
+	require.Equal(t, 1, len(r.Responses))
+	assert.Equal(t, guber.Status_UNDER_LIMIT, r.Responses[0].Status)
+}
+
+func TestGRPCGatewayHumanValues(t *testing.T) {
+	address := cluster.GetRandomPeer(cluster.DataCenterNone).HTTPAddress
+	post := func(body string) (int, string) {
+		resp, err := http.DefaultClient.Post("http://"+address+"/v1/GetRateLimits",
+			"application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	code, body := post(fmt.Sprintf(`{"requests": [{"name": "%s", "unique_key": "%s", "hits": 1,
+		"limit": "10k", "duration": "1m"}]}`, t.Name(), guber.RandomString(10)))
+	require.Equal(t, http.StatusOK, code, body)
+	var r guber.GetRateLimitsResp
+	require.NoError(t, json.Unmarshal([]byte(body), &r))
+	require.Len(t, r.Responses, 1)
+	assert.Equal(t, "", r.Responses[0].Error)
+	assert.Equal(t, int64(10_000), r.Responses[0].Limit)
+	assert.Equal(t, int64(9_999), r.Responses[0].Remaining)
+	assert.InDelta(t, clock.Now().Add(clock.Minute).UnixMilli(), r.Responses[0].ResetTime, 5000)
+
+	// Ambiguous values are rejected
+	code, body = post(fmt.Sprintf(`{"requests": [{"name": "%s", "unique_key": "account:1234",
+		"limit": "1m", "duration": "1m"}]}`, t.Name()))
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "field 'limit': '1m' is ambiguous")
+
+	code, body = post(fmt.Sprintf(`{"requests": [{"name": "%s", "unique_key": "account:1234",
+		"limit": 10, "duration": "1m", "behavior": "DURATION_IS_GREGORIAN"}]}`, t.Name()))
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "ambiguous for a DURATION_IS_GREGORIAN rate limit")
+}
+
+func TestGRPCWeb(t *testing.T) {
+	conf := guber.DaemonConfig{
+		GRPCListenAddress:     "127.0.0.1:9696",
+		HTTPListenAddress:     "127.0.0.1:9686",
+		GRPCWebEnabled:        true,
+		GRPCWebAllowedOrigins: []string{"https://dashboard.example.com"},
+	}
+	d := spawnDaemon(t, conf)
+	defer d.Close()
+
+	// Encode the request as a single length prefixed gRPC-Web frame.
+	msg, err := proto.Marshal(&guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      t.Name(),
+				UniqueKey: guber.RandomString(10),
+				Duration:  guber.Millisecond * 1000,
+				Hits:      1,
+				Limit:     10,
+			},
+		},
+	})
+	require.NoError(t, err)
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+conf.HTTPListenAddress+"/pb.gubernator.V1/GetRateLimits",
+		bytes.NewReader(frame))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	req.Header.Set("Origin", "https://dashboard.example.com")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "https://dashboard.example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	// The first frame is the response message, followed by the trailer frame.
+	require.Greater(t, len(b), 5)
+	require.Equal(t, byte(0), b[0])
+	size := binary.BigEndian.Uint32(b[1:5])
+	var r guber.GetRateLimitsResp
+	require.NoError(t, proto.Unmarshal(b[5:5+size], &r))
+	require.Equal(t, 1, len(r.Responses))
+	assert.Equal(t, guber.Status_UNDER_LIMIT, r.Responses[0].Status)
+	assert.Equal(t, int64(9), r.Responses[0].Remaining)
+	assert.Contains(t, string(b[5+size:]), "grpc-status: 0")
+
+	// Origins not in the allowed list are rejected.
+	req, err = http.NewRequest(http.MethodOptions, "http://"+conf.HTTPListenAddress+"/pb.gubernator.V1/GetRateLimits", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	// The base64 encoded text format is also supported.
+	req, err = http.NewRequest(http.MethodPost, "http://"+conf.HTTPListenAddress+"/pb.gubernator.V1/HealthCheck",
+		strings.NewReader(base64.StdEncoding.EncodeToString(make([]byte, 5))))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc-web-text")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	b, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	b, err = base64.StdEncoding.DecodeString(string(b))
+	require.NoError(t, err)
+	size = binary.BigEndian.Uint32(b[1:5])
+	var hc guber.HealthCheckResp
+	require.NoError(t, proto.Unmarshal(b[5:5+size], &hc))
+	assert.Equal(t, guber.Healthy, hc.Status)
+
+	// The HTTP gateway continues to work on the same listener.
+	resp, err = http.DefaultClient.Get("http://" + conf.HTTPListenAddress + "/v1/HealthCheck")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestOperatorDashboard(t *testing.T) {
+	conf := guber.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9697",
+		HTTPListenAddress: "127.0.0.1:9687",
+		AdminEnabled:      true,
+		UIEnabled:         true,
+	}
+	d := spawnDaemon(t, conf)
+	defer d.Close()
+
+	resp, err := http.Get("http://" + conf.HTTPListenAddress + "/ui/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "/v1/admin/GetNamespaceUsage")
+}
+
+func TestCompression(t *testing.T) {
+	for _, compression := range []string{guber.CompressionGzip, guber.CompressionSnappy} {
+		t.Run(compression, func(t *testing.T) {
+			conf := guber.Config{PeerCompression: compression}
+			a := newV1Server(t, "localhost:0", conf)
+			defer a.Close()
+			b := newV1Server(t, "localhost:0", conf)
+			defer b.Close()
+
+			a.srv.SetPeers([]guber.PeerInfo{
+				{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+				{GRPCAddress: b.listener.Addr().String()},
+			})
+			b.srv.SetPeers([]guber.PeerInfo{
+				{GRPCAddress: a.listener.Addr().String()},
+				{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+			})
+
+			client, err := guber.DialV1Server(a.listener.Addr().String(), nil,
+				grpc.WithDefaultCallOptions(grpc.UseCompressor(compression)))
+			require.NoError(t, err)
+
+			// Enough keys that some are forwarded to the other peer
+			req := &guber.GetRateLimitsReq{}
+			for i := 0; i < 100; i++ {
+				req.Requests = append(req.Requests, &guber.RateLimitReq{
+					Name:      "test_compression",
+					UniqueKey: fmt.Sprintf("account:%d", i),
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Duration:  guber.Minute,
+					Limit:     10,
+					Hits:      1,
+				})
+			}
+
+			resp, err := client.GetRateLimits(context.Background(), req)
+			require.NoError(t, err)
+			require.Len(t, resp.Responses, 100)
+			for _, rl := range resp.Responses {
+				assert.Equal(t, "", rl.Error)
+				assert.Equal(t, int64(9), rl.Remaining)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers:     []*grpc.Server{grpc.NewServer()},
+			PeerCompression: "lz4",
+		})
+		assert.ErrorContains(t, err, "compression 'lz4' is invalid")
+	})
+}
+
+func TestFederation(t *testing.T) {
+	// Requests proxied to the home cluster are answered by the home cluster, even if it
+	// mistakenly federates the same namespace to an unreachable cluster.
+	home := newV1Server(t, "localhost:0", guber.Config{
+		Federation: guber.FederationConfig{
+			HomeAddress: "localhost:1",
+			Prefixes:    []string{"global_"},
+		},
+	})
+	defer home.Close()
+	spoke := newV1Server(t, "localhost:0", guber.Config{
+		Federation: guber.FederationConfig{
+			HomeAddress: home.listener.Addr().String(),
+			Prefixes:    []string{"global_"},
+		},
+	})
+	defer spoke.Close()
+
+	hit := func(srv *v1Server, name string, hits int64) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	assert.Equal(t, int64(9), hit(spoke, "global_partner_quota", 1).Remaining)
+	assert.Equal(t, int64(8), hit(spoke, "global_partner_quota", 1).Remaining)
+	client, err := guber.DialV1Server(home.listener.Addr().String(), nil,
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+			cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(metadata.AppendToOutgoingContext(ctx, "gubernator-federated", "true"), method, req, reply, cc, opts...)
+		}))
+	require.NoError(t, err)
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{{
+			Name:      "global_partner_quota",
+			UniqueKey: "account:1234",
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      1,
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.Responses[0].Remaining)
+
+	// Other namespaces remain local to each cluster
+	assert.Equal(t, int64(9), hit(spoke, "local_quota", 1).Remaining)
+	assert.Equal(t, int64(10), hit(home, "local_quota", 0).Remaining)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers: []*grpc.Server{grpc.NewServer()},
+			Federation:  guber.FederationConfig{HomeAddress: "localhost:1051"},
+		})
+		assert.EqualError(t, err, "Federation.HomeAddress requires Federation.Prefixes")
+	})
+}
+
+func TestShadow(t *testing.T) {
+	// The clusters shadow each other, the requests mirrored to a cluster must not be mirrored back
+	secondary := newV1Server(t, "localhost:0", guber.Config{
+		Shadow: guber.ShadowConfig{Address: "localhost:1"},
+	})
+	defer secondary.Close()
+	primary := newV1Server(t, "localhost:0", guber.Config{
+		Shadow: guber.ShadowConfig{Address: secondary.listener.Addr().String()},
+	})
+	defer primary.Close()
+
+	hit := func(srv *v1Server, hits int64) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_shadow",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	assert.Equal(t, int64(9), hit(primary, 1).Remaining)
+	assert.Eventually(t, func() bool {
+		return hit(secondary, 0).Remaining == 9
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(9), hit(primary, 0).Remaining)
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers: []*grpc.Server{grpc.NewServer()},
+			Shadow:      guber.ShadowConfig{Address: "localhost:1051", Percent: 101},
+		})
+		assert.EqualError(t, err, "Shadow.Percent must be between 1 and 100")
+	})
+}
+
+func TestStandby(t *testing.T) {
+	conf := guber.Config{Standby: guber.StandbyConfig{Names: []string{"test_standby"}}}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+	addrA, addrB := a.listener.Addr().String(), b.listener.Addr().String()
+
+	a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA, IsOwner: true}, {GRPCAddress: addrB}})
+	b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA}, {GRPCAddress: addrB, IsOwner: true}})
+
+	hit := func(addr, name string, hits int64) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(addr, nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses[0]
+	}
+
+	// The standby of a rate limit is the peer which does not own it
+	standbys := make(map[string]*v1Server)
+	for _, name := range []string{"test_standby", "test_standby_other"} {
+		for i := 0; i < 3; i++ {
+			rl := hit(addrA, name, 1)
+			require.Equal(t, "", rl.Error)
+			standbys[name] = b
+			if rl.Metadata["owner"] == addrB {
+				standbys[name] = a
+			}
+		}
+	}
+
+	// The owner of each rate limit crashes, the standby becomes the owner
+	for _, test := range []struct {
+		name      string
+		remaining int64
+	}{
+		{"test_standby", 7},
+		{"test_standby_other", 10},
+	} {
+		standby := standbys[test.name]
+		addr := standby.listener.Addr().String()
+		standby.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr, IsOwner: true}})
+		rl := hit(addr, test.name, 0)
+		assert.Equal(t, "", rl.Error, test.name)
+		assert.Equal(t, test.remaining, rl.Remaining, test.name)
+	}
+
+	t.Run("standby unreachable", func(t *testing.T) {
+		a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA, IsOwner: true}, {GRPCAddress: "127.0.0.1:1"}})
+		// Either the owner or the standby of the rate limit cannot be reached
+		assert.NotEqual(t, "", hit(addrA, "test_standby", 1).Error)
+	})
+}
+
+func TestColdKeyFilter(t *testing.T) {
+	conf := guber.Config{
+		ColdKeyFilterInterval: 50 * time.Millisecond,
+		Behaviors:             guber.BehaviorConfig{GlobalSyncWait: 500 * time.Millisecond},
+	}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+	addrA, addrB := a.listener.Addr().String(), b.listener.Addr().String()
+
+	a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA, IsOwner: true}, {GRPCAddress: addrB}})
+	b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA}, {GRPCAddress: addrB, IsOwner: true}})
+
+	hit := func(addr, key string, hits int64) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(addr, nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_cold_key_filter",
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	// Find a key owned by `b`, queries are always forwarded
+	var key string
+	for i := 0; key == ""; i++ {
+		k := fmt.Sprintf("account:%d", i)
+		if hit(addrA, k, 0).Metadata["owner"] == addrB {
+			key = k
+		}
+	}
+	// Wait for `a` to pull the filter of `b`
+	time.Sleep(200 * time.Millisecond)
+
+	// The share of `a` is answered without `b`, which receives the hits later
+	rl := hit(addrA, key, 5)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	assert.Equal(t, int64(5), rl.Remaining)
+	assert.Equal(t, int64(10), hit(addrB, key, 0).Remaining)
+
+	// More than the share of `a` is forwarded
+	assert.Equal(t, int64(9), hit(addrA, key, 1).Remaining)
+
+	// The hits answered by `a` arrive at `b`, once the key is in the filter of `b` it is forwarded
+	require.Eventually(t, func() bool {
+		return hit(addrB, key, 0).Remaining == 4
+	}, 2*time.Second, 50*time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(3), hit(addrA, key, 1).Remaining)
+}
+
+func TestStreamHits(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	req := func(key string, hits int64) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      "test_stream_hits",
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      hits,
+		}
+	}
+
+	stream, err := client.StreamHits(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&guber.StreamHitsReq{
+		Interval: 50,
+		Requests: []*guber.RateLimitReq{req("account:1", 2)},
+	}))
+	require.NoError(t, stream.Send(&guber.StreamHitsReq{
+		Requests: []*guber.RateLimitReq{req("account:1", 3), req("account:2", 1)},
+	}))
+	require.NoError(t, stream.CloseSend())
+
+	// The hits received before the stream was closed are applied, in one or more intervals
+	hits := make(map[string]int64)
+	last := make(map[string]*guber.RateLimitResp)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		for _, s := range resp.Statuses {
+			require.Equal(t, "", s.RateLimit.Error)
+			hits[s.UniqueKey] += s.Hits
+			last[s.UniqueKey] = s.RateLimit
+		}
+	}
+	assert.Equal(t, map[string]int64{"account:1": 5, "account:2": 1}, hits)
+	assert.Equal(t, int64(5), last["account:1"].Remaining)
+	assert.Equal(t, int64(9), last["account:2"].Remaining)
+
+	t.Run("interval too short", func(t *testing.T) {
+		stream, err := client.StreamHits(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&guber.StreamHitsReq{Interval: 1}))
+		_, err = stream.Recv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestNormalizers(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Normalizers: []guber.NormalizeFunc{
+			guber.NormalizeTrimSpace,
+			guber.NormalizeLowercase,
+			guber.NewNameAliases(map[string]string{"login": "test_normalizers"}),
+		},
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	// Every spelling of the same rate limit shares one bucket
+	for i, r := range []struct{ name, key string }{
+		{"test_normalizers", "account:1234"},
+		{"Test_Normalizers", "Account:1234"},
+		{"login", " account:1234 "},
+	} {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      r.name,
+				UniqueKey: r.key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		assert.Equal(t, int64(9-i), resp.Responses[0].Remaining, r)
+	}
+
+	// A key which is only white space is empty once normalized
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{{Name: "test_normalizers", UniqueKey: "  ", Limit: 10, Duration: guber.Minute}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "field 'unique_key' cannot be empty", resp.Responses[0].Error)
+}
+
+func TestReadinessGate(t *testing.T) {
+	conf := guber.Config{ReadyMinPeers: 2, UnavailableUntilReady: true}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	req := &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      "test_readiness_gate",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			},
+		},
+	}
+
+	// Only a single peer is known, so the instance is not ready
+	assert.False(t, a.srv.Ready())
+	health, err := client.HealthCheck(context.Background(), &guber.HealthCheckReq{})
+	require.NoError(t, err)
+	assert.Equal(t, guber.UnHealthy, health.Status)
+	assert.Contains(t, health.Message, "not ready")
+
+	_, err = client.GetRateLimits(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	peers := []guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	}
+	a.srv.SetPeers(peers)
+	assert.True(t, a.srv.Ready())
+
+	health, err = client.HealthCheck(context.Background(), &guber.HealthCheckReq{})
+	require.NoError(t, err)
+	assert.Equal(t, guber.Healthy, health.Status)
+
+	// Once ready, the instance stays ready
+	a.srv.SetPeers(peers[:1])
+	assert.True(t, a.srv.Ready())
+
+	resp, err := client.GetRateLimits(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Responses[0].Error)
+	assert.Equal(t, int64(9), resp.Responses[0].Remaining)
+}
+
+func TestReadinessQuorumPercent(t *testing.T) {
+	conf := guber.Config{ReadyMinPeersPercent: 60, UnavailableUntilReady: true}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	peers := []guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	}
+
+	// The instance is not ready until discovery reports the cluster size
+	assert.False(t, a.srv.Ready())
+	assert.EqualError(t, a.srv.CheckReady(context.Background()), "waiting for peer discovery to report the cluster size")
+
+	// Discovery expects 4 peers, of which only this instance was discovered
+	a.srv.SetClusterSize(4)
+	a.srv.SetPeers(peers[:1])
+	assert.False(t, a.srv.Ready())
+	assert.EqualError(t, a.srv.CheckReady(context.Background()), "waiting for at least '3' peers")
+
+	// The cluster shrank while this instance was waiting
+	a.srv.SetPeers(peers)
+	assert.False(t, a.srv.Ready())
+	a.srv.SetClusterSize(3)
+	assert.True(t, a.srv.Ready())
+}
+
+func TestMinimalResponse(t *testing.T) {
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil)
+	require.NoError(t, err)
+
+	for _, minimal := range []bool{false, true} {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			MinimalResponse: minimal,
+			Requests: []*guber.RateLimitReq{
+				{
+					Name:      "test_minimal_response",
+					UniqueKey: "account:1234",
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Behavior:  guber.Behavior_DRY_RUN,
+					Duration:  guber.Minute,
+					Limit:     0,
+					Hits:      1,
+				},
+			},
+		})
+		require.NoError(t, err)
+		rl := resp.Responses[0]
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+		if minimal {
+			assert.Nil(t, rl.Metadata)
+		} else {
+			assert.Equal(t, "OVER_LIMIT", rl.Metadata["dry_run_status"])
+		}
+	}
+}
+
+func TestRequestDefaults(t *testing.T) {
+	// Split the batch to verify the defaults are sent with every part
+	client, err := guber.DialV1Server(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress, nil,
+		grpc.WithChainUnaryInterceptor(guber.BatchSplitInterceptor(1, 2)))
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Second*10)
+	defer cancel()
+
+	resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+		Defaults: &guber.RateLimitDefaults{
+			Name:      "test_request_defaults",
+			Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			Behavior:  guber.Behavior_DRY_RUN,
+		},
+		Requests: []*guber.RateLimitReq{
+			{UniqueKey: "account:1234", Duration: guber.Minute, Limit: 2, Hits: 1},
+			{UniqueKey: "account:5678", Duration: guber.Minute, Limit: 2, Hits: 3},
+			{Name: "test_request_defaults_override", UniqueKey: "account:1234", Duration: guber.Minute, Limit: 2, Hits: 3},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 3)
+	for _, rl := range resp.Responses {
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	}
+	assert.Equal(t, "OVER_LIMIT", resp.Responses[1].Metadata["dry_run_status"])
+	assert.Equal(t, "OVER_LIMIT", resp.Responses[2].Metadata["dry_run_status"])
+
+	// The rate limits were created with the default name and algorithm
+	resp, err = client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{{
+			Name:      "test_request_defaults",
+			UniqueKey: "account:1234",
+			Algorithm: guber.Algorithm_LEAKY_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     2,
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "", resp.Responses[0].Error)
+	assert.Equal(t, int64(1), resp.Responses[0].Remaining)
+}
+
+func TestRequestID(t *testing.T) {
+	// Connect to a peer which does not own the first key, such that it is forwarded
+	peers, err := cluster.ListNonOwningDaemons("test_request_id", "account:0")
+	require.NoError(t, err)
+	client, err := guber.DialV1Server(peers[0].PeerInfo.GRPCAddress, nil)
+	require.NoError(t, err)
+
+	req := &guber.GetRateLimitsReq{}
+	for i := 0; i < 10; i++ {
+		req.Requests = append(req.Requests, &guber.RateLimitReq{
+			Name:      "test_request_id",
+			UniqueKey: fmt.Sprintf("account:%d", i),
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Behavior:  guber.Behavior_BATCHING,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      1,
+			RequestId: fmt.Sprintf("request-%d", i),
+		})
+	}
+	// Requests without a request id are assigned one
+	req.Requests = append(req.Requests, &guber.RateLimitReq{
+		Name:      "test_request_id",
+		UniqueKey: "account:generated",
+		Algorithm: guber.Algorithm_TOKEN_BUCKET,
+		Duration:  guber.Minute,
+		Limit:     10,
+		Hits:      1,
+	}, &guber.RateLimitReq{
+		Name:      "test_request_id",
+		RequestId: "request-invalid",
+	})
+
+	resp, err := client.GetRateLimits(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 12)
+
+	var forwarded int
+	for i, rl := range resp.Responses[:10] {
+		assert.Empty(t, rl.Error)
+		assert.Equal(t, fmt.Sprintf("request-%d", i), rl.RequestId)
+		if rl.Metadata["owner"] != "" {
+			forwarded++
+		}
+	}
+	assert.NotZero(t, forwarded)
+	assert.Len(t, resp.Responses[10].RequestId, 32)
+	assert.NotEmpty(t, resp.Responses[11].Error)
+	assert.Equal(t, "request-invalid", resp.Responses[11].RequestId)
+}
+
+func TestGetPeerRateLimits(t *testing.T) {
+	name := t.Name()
+	ctx := context.Background()
+	peerClient, err := guber.NewPeerClient(guber.PeerConfig{
+		Info: cluster.GetRandomPeer(cluster.DataCenterNone),
+	})
+	require.NoError(t, err)
+
+	t.Run("Stable rate check request order", func(t *testing.T) {
+		// Ensure response order matches rate check request order.
+		// Try various batch sizes.
+		createdAt := epochMillis(clock.Now())
+		testCases := []int{1, 2, 5, 10, 100, 1000}
+
+		for _, n := range testCases {
+			t.Run(fmt.Sprintf("Batch size %d", n), func(t *testing.T) {
+				// Build request.
+				req := &guber.GetPeerRateLimitsReq{
+					Requests: make([]*guber.RateLimitReq, n),
+				}
+				for i := 0; i < n; i++ {
+					req.Requests[i] = &guber.RateLimitReq{
+						Name:      name,
+						UniqueKey: guber.RandomString(10),
+						Hits:      0,
+						Limit:     1000 + int64(i),
+						Duration:  1000,
+						Algorithm: guber.Algorithm_TOKEN_BUCKET,
+						Behavior:  guber.Behavior_BATCHING,
+						CreatedAt: &createdAt,
+					}
+				}
+
+				// Send request.
+				resp, err := peerClient.GetPeerRateLimits(ctx, req)
+
+				// Verify.
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				assert.Len(t, resp.RateLimits, n)
+
+				for i, item := range resp.RateLimits {
+					// Identify response by its unique limit.
+					assert.Equal(t, req.Requests[i].Limit, item.Limit)
+				}
+			})
+		}
+	})
+}
+
+// TODO: Add a test for sending no rate limits RateLimitReqList.RateLimits = nil
+
+func TestFaultInjection(t *testing.T) {
+	createdAt := epochMillis(clock.Now())
+	req := &guber.GetPeerRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			{
+				Name:      "test_fault_injection",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+				CreatedAt: &createdAt,
+			},
+		},
+	}
+
+	t.Run("Peer latency", func(t *testing.T) {
+		peerClient, err := guber.NewPeerClient(guber.PeerConfig{
+			Info:   cluster.GetRandomPeer(cluster.DataCenterNone),
+			Faults: &guber.FaultConfig{PeerLatency: 100 * time.Millisecond},
+		})
+		require.NoError(t, err)
+		defer func() { _ = peerClient.Shutdown(context.Background()) }()
+
+		start := time.Now()
+		_, err = peerClient.GetPeerRateLimits(context.Background(), req)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("Dropped peer RPC", func(t *testing.T) {
+		peerClient, err := guber.NewPeerClient(guber.PeerConfig{
+			Info:   cluster.GetRandomPeer(cluster.DataCenterNone),
+			Faults: &guber.FaultConfig{PeerDropPercent: 100},
+		})
+		require.NoError(t, err)
+		defer func() { _ = peerClient.Shutdown(context.Background()) }()
+
+		_, err = peerClient.GetPeerRateLimits(context.Background(), req)
+		require.Error(t, err)
+		assert.Equal(t, codes.Unavailable, status.Code(errors.Cause(err)))
+		assert.Contains(t, err.Error(), "fault injection")
+	})
+
+	t.Run("Ownership flaps", func(t *testing.T) {
+		conf := guber.Config{Faults: &guber.FaultConfig{OwnershipFlapPercent: 100}}
+		a := newV1Server(t, "localhost:0", conf)
+		defer a.Close()
+
+		peers := []guber.PeerInfo{
+			{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+			{GRPCAddress: cluster.PeerAt(0).GRPCAddress},
+			{GRPCAddress: cluster.PeerAt(1).GRPCAddress},
+		}
+		a.srv.SetPeers(peers)
+
+		owners := make(map[string]struct{})
+		for i := 0; i < 100; i++ {
+			peer, err := a.srv.GetPeer(context.Background(), "test_fault_injection_account:1234")
+			require.NoError(t, err)
+			owners[peer.Info().GRPCAddress] = struct{}{}
+		}
+		assert.Greater(t, len(owners), 1)
+	})
+
+	t.Run("Invalid config", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers: []*grpc.Server{grpc.NewServer()},
+			Faults:      &guber.FaultConfig{PeerDropPercent: 200},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifyPeerOwnership(t *testing.T) {
+	conf := guber.Config{Behaviors: guber.BehaviorConfig{VerifyPeerOwnership: true}}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	// Simulate a split-brain where `a` believes `b` owns the rate limit
+	// while `b` believes `a` owns the rate limit.
+	a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: b.listener.Addr().String()}})
+	b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: a.listener.Addr().String()}})
+
+	createdAt := epochMillis(clock.Now())
+	req := &guber.RateLimitReq{
+		Name:      "test_verify_peer_ownership",
+		UniqueKey: "account:1234",
+		Algorithm: guber.Algorithm_TOKEN_BUCKET,
+		Behavior:  guber.Behavior_NO_BATCHING,
+		Duration:  guber.Minute,
+		Limit:     10,
+		Hits:      1,
+		CreatedAt: &createdAt,
+	}
+
+	t.Run("Peer rejects rate limits it does not own", func(t *testing.T) {
+		peerClient, err := guber.NewPeerClient(guber.PeerConfig{
+			Info: guber.PeerInfo{GRPCAddress: b.listener.Addr().String()},
+		})
+		require.NoError(t, err)
+		defer func() { _ = peerClient.Shutdown(context.Background()) }()
+
+		resp, err := peerClient.GetPeerRateLimits(context.Background(), &guber.GetPeerRateLimitsReq{
+			Requests: []*guber.RateLimitReq{req},
+		})
+		require.NoError(t, err)
+		rl := resp.RateLimits[0]
+		assert.Contains(t, rl.Error, "is owned by")
+		assert.Equal(t, "true", rl.Metadata[guber.MetadataNotOwner])
+		assert.Equal(t, a.listener.Addr().String(), rl.Metadata["owner"])
+	})
+
+	t.Run("Forwarding peer reports the rejection", func(t *testing.T) {
+		client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{req},
+		})
+		require.NoError(t, err)
+		rl := resp.Responses[0]
+		assert.Contains(t, rl.Error, "is owned by")
+		assert.Equal(t, "true", rl.Metadata[guber.MetadataNotOwner])
+	})
+
+	t.Run("Peer evaluates rate limits it owns", func(t *testing.T) {
+		b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: b.listener.Addr().String(), IsOwner: true}})
+
+		client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{req},
+		})
+		require.NoError(t, err)
+		rl := resp.Responses[0]
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Equal(t, b.listener.Addr().String(), rl.Metadata["owner"])
+	})
+}
+
+func TestForwarderPeer(t *testing.T) {
+	owner := newV1Server(t, "localhost:0", guber.Config{})
+	defer owner.Close()
+	forwarder := newV1Server(t, "localhost:0", guber.Config{})
+	defer forwarder.Close()
+
+	ownerAddr, forwarderAddr := owner.listener.Addr().String(), forwarder.listener.Addr().String()
+	owner.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: ownerAddr, IsOwner: true},
+		{GRPCAddress: forwarderAddr, Forwarder: true},
+	})
+	forwarder.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: ownerAddr},
+		{GRPCAddress: forwarderAddr, IsOwner: true, Forwarder: true},
+	})
+
+	client, err := guber.DialV1Server(forwarderAddr, nil)
+	require.NoError(t, err)
+	ownerClient, err := guber.DialV1Server(ownerAddr, nil)
+	require.NoError(t, err)
+
+	for _, behavior := range []guber.Behavior{guber.Behavior_BATCHING, guber.Behavior_GLOBAL} {
+		t.Run(behavior.String(), func(t *testing.T) {
+			var reqs []*guber.RateLimitReq
+			for i := 0; i < 10; i++ {
+				reqs = append(reqs, &guber.RateLimitReq{
+					Name:      "test_forwarder_peer",
+					UniqueKey: guber.RandomString(10),
+					Behavior:  behavior,
+					Duration:  guber.Minute,
+					Limit:     10,
+					Hits:      1,
+				})
+			}
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+			require.NoError(t, err)
+			for _, rl := range resp.Responses {
+				assert.Equal(t, "", rl.Error)
+				assert.Equal(t, int64(9), rl.Remaining)
+				// Every rate limit is owned by the owner, no key hashes to the forwarder
+				assert.Equal(t, ownerAddr, rl.Metadata["owner"])
+			}
+
+			// The hits were applied by the owner before the forwarder responded
+			for _, req := range reqs {
+				req.Hits = 0
+			}
+			resp, err = ownerClient.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+			require.NoError(t, err)
+			for _, rl := range resp.Responses {
+				assert.Equal(t, int64(9), rl.Remaining)
+			}
+		})
+	}
+
+	// The owner never forwards to the forwarder
+	peer, err := owner.srv.GetPeer(context.Background(), "test_forwarder_peer_account:1234")
+	require.NoError(t, err)
+	assert.Equal(t, ownerAddr, peer.Info().GRPCAddress)
+}
+
+func TestSelfPeer(t *testing.T) {
+	// Listens on every address, such that the instance is reachable under addresses it does not know
+	srv := newV1Server(t, "0.0.0.0:0", guber.Config{
+		Behaviors: guber.BehaviorConfig{VerifyPeerOwnership: true},
+	})
+	defer srv.Close()
+	_, port, err := net.SplitHostPort(srv.listener.Addr().String())
+	require.NoError(t, err)
+	addr := "127.0.0.1:" + port
+	client, err := guber.DialV1Server(addr, nil)
+	require.NoError(t, err)
+
+	hit := func(key string) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_self_peer",
+				UniqueKey: key,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	// An address this instance does not know as its own is detected once a rate limit is forwarded
+	// to it, after which rate limits are evaluated locally
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr}})
+	rl := hit("account:1234")
+	assert.Equal(t, int64(9), rl.Remaining)
+	assert.Equal(t, addr, rl.Metadata["owner"])
+	assert.Equal(t, "", rl.Metadata["self_forward"])
+
+	rl = hit("account:1234")
+	assert.Equal(t, int64(8), rl.Remaining)
+	assert.Equal(t, "", rl.Metadata["owner"])
+
+	// The detected address is recognized in another form
+	srv.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: srv.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: "LOCALHOST:0" + port},
+	})
+	for i := 0; i < 10; i++ {
+		rl := hit(guber.RandomString(10))
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Equal(t, "", rl.Metadata["owner"])
+	}
+}
+
+func TestForwardingOverrides(t *testing.T) {
+	conf := guber.Config{AdminEnabled: true, ForwardingOverridesEnabled: true}
+	srv1 := newV1Server(t, "localhost:0", conf)
+	defer srv1.Close()
+	srv2 := newV1Server(t, "localhost:0", conf)
+	defer srv2.Close()
+	// The second peer owns every rate limit
+	addr1, addr2 := srv1.listener.Addr().String(), srv2.listener.Addr().String()
+	srv1.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr2}})
+	srv2.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr2, IsOwner: true}})
+
+	send := func(addr string, behavior guber.Behavior) *guber.RateLimitResp {
+		client, err := guber.DialV1Server(addr, nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_forwarding_overrides",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Behavior:  behavior,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses[0]
+	}
+
+	t.Run("FORCE_LOCAL", func(t *testing.T) {
+		rl := send(addr1, guber.Behavior_FORCE_LOCAL)
+		require.Empty(t, rl.Error)
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Empty(t, rl.Metadata["owner"])
+
+		// The owner did not see the hit applied locally
+		rl = send(addr1, 0)
+		require.Empty(t, rl.Error)
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Equal(t, addr2, rl.Metadata["owner"])
+	})
+
+	t.Run("FORCE_PEER", func(t *testing.T) {
+		// The owner forwards the rate limit to itself
+		rl := send(addr2, guber.Behavior_FORCE_PEER)
+		require.Empty(t, rl.Error)
+		assert.Equal(t, int64(8), rl.Remaining)
+		assert.Equal(t, addr2, rl.Metadata["owner"])
+
+		rl = send(addr2, 0)
+		require.Empty(t, rl.Error)
+		assert.Equal(t, int64(7), rl.Remaining)
+		assert.Empty(t, rl.Metadata["owner"])
+	})
+
+	t.Run("Both", func(t *testing.T) {
+		rl := send(addr1, guber.Behavior_FORCE_LOCAL|guber.Behavior_FORCE_PEER)
+		assert.Contains(t, rl.Error, "cannot be used together")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{})
+		defer srv.Close()
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_forwarding_overrides",
+				UniqueKey: "account:1",
+				Behavior:  guber.Behavior_FORCE_LOCAL,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, resp.Responses[0].Error, "not enabled")
+
+		_, err = guber.NewV1Instance(guber.Config{
+			GRPCServers:                []*grpc.Server{grpc.NewServer()},
+			ForwardingOverridesEnabled: true,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestPeerAuth(t *testing.T) {
+	auth := &guber.PeerAuthConfig{Token: "new-secret", AcceptTokens: []string{"old-secret"}}
+	owner := newV1Server(t, "localhost:0", guber.Config{
+		GRPCServers: []*grpc.Server{grpc.NewServer(grpc.UnaryInterceptor(auth.UnaryServerInterceptor()))},
+		PeerAuth:    auth,
+	})
+	defer owner.Close()
+	ownerAddr := owner.listener.Addr().String()
+
+	conn, err := grpc.Dial(ownerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	peerClient := guber.NewPeersV1Client(conn)
+	req := &guber.GetPeerRateLimitsReq{Requests: []*guber.RateLimitReq{
+		{Name: "test_peer_auth", UniqueKey: "account:1", Duration: guber.Minute, Limit: 10, Hits: 1},
+	}}
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		code   codes.Code
+	}{
+		{name: "missing", code: codes.Unauthenticated},
+		{name: "invalid", header: "Bearer wrong-secret", code: codes.Unauthenticated},
+		{name: "not bearer", header: "new-secret", code: codes.Unauthenticated},
+		{name: "current", header: "Bearer new-secret", code: codes.OK},
+		{name: "accepted", header: "Bearer old-secret", code: codes.OK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.header != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.header)
+			}
+			_, err := peerClient.GetPeerRateLimits(ctx, req)
+			assert.Equal(t, tc.code, status.Code(err))
+		})
+	}
+
+	// The V1 service is not authenticated
+	client, err := guber.DialV1Server(ownerAddr, nil)
+	require.NoError(t, err)
+	_, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: req.Requests})
+	require.NoError(t, err)
+
+	// Requests forwarded by a peer carry its token
+	forward := func(auth *guber.PeerAuthConfig) *guber.RateLimitResp {
+		forwarder := newV1Server(t, "localhost:0", guber.Config{PeerAuth: auth})
+		defer forwarder.Close()
+		forwarderAddr := forwarder.listener.Addr().String()
+		forwarder.srv.SetPeers([]guber.PeerInfo{
+			{GRPCAddress: ownerAddr},
+			{GRPCAddress: forwarderAddr, IsOwner: true, Forwarder: true},
+		})
+		client, err := guber.DialV1Server(forwarderAddr, nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: req.Requests})
+		require.NoError(t, err)
+		return resp.Responses[0]
+	}
+	assert.Equal(t, "", forward(&guber.PeerAuthConfig{Token: "old-secret"}).Error)
+	assert.Contains(t, forward(nil).Error, "peer authentication failed")
+}
+
+func TestGlobalBehavior(t *testing.T) {
+	const limit = 1000
+	broadcastTimeout := 400 * time.Millisecond
+	createdAt := epochMillis(clock.Now())
+
+	makeReq := func(name, key string, hits int64) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      name,
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Behavior:  guber.Behavior_GLOBAL,
+			Duration:  guber.Minute * 3,
+			Hits:      hits,
+			Limit:     limit,
+			CreatedAt: &createdAt,
+		}
+	}
+
+	t.Run("Hits on owner peer", func(t *testing.T) {
+		testCases := []struct {
+			Name string
+			Hits int64
+		}{
+			{Name: "Single hit", Hits: 1},
+			{Name: "Multiple hits", Hits: 10},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				name := t.Name()
+				key := fmt.Sprintf("account:%08x", rand.Int())
+				peers, err := cluster.ListNonOwningDaemons(name, key)
+				require.NoError(t, err)
+				owner, err := cluster.FindOwningDaemon(name, key)
+				require.NoError(t, err)
+				t.Logf("Owner peer: %s", owner.InstanceID)
+
+				require.NoError(t, waitForIdle(1*time.Minute, cluster.GetDaemons()...))
+
+				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
+				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
+				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+
+				// When
+				for i := int64(0); i < testCase.Hits; i++ {
+					sendHit(t, owner, makeReq(name, key, 1), guber.Status_UNDER_LIMIT, 999-i)
+				}
+
+				// Then
+				// Expect a single global broadcast to all non-owner peers.
+				t.Log("Waiting for global broadcasts")
+				var wg sync.WaitGroup
+				var didOwnerBroadcast, didNonOwnerBroadcast int
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := broadcastCounters[owner.InstanceID] + 1
+					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
+						didOwnerBroadcast++
+						t.Log("Global broadcast from owner")
+					}
+					wg.Done()
+				}()
+				for _, peer := range peers {
+					go func(peer *guber.Daemon) {
+						expected := broadcastCounters[peer.InstanceID] + 1
+						if err := waitForBroadcast(broadcastTimeout, peer, expected); err == nil {
+							didNonOwnerBroadcast++
+							t.Logf("Global broadcast from peer %s", peer.InstanceID)
+						}
+						wg.Done()
+					}(peer)
+				}
+				wg.Wait()
+				assert.Equal(t, 1, didOwnerBroadcast)
+				assert.Zero(t, didNonOwnerBroadcast)
+
+				// Check for global hits update from non-owner to owner peer.
+				// Expect no global hits update because the hits were given
+				// directly to the owner peer.
+				t.Log("Waiting for global broadcasts")
+				var didOwnerUpdate, didNonOwnerUpdate int
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := updateCounters[owner.InstanceID] + 1
+					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
+						didOwnerUpdate++
+						t.Log("Global hits update from owner")
+					}
+					wg.Done()
+				}()
+				for _, peer := range peers {
+					go func(peer *guber.Daemon) {
+						expected := updateCounters[peer.InstanceID] + 1
+						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
+							didNonOwnerUpdate++
+							t.Logf("Global hits update from peer %s", peer.InstanceID)
+						}
+						wg.Done()
+
+					}(peer)
+				}
+				wg.Wait()
+				assert.Zero(t, didOwnerUpdate)
+				assert.Zero(t, didNonOwnerUpdate)
+
+				// Assert UpdatePeerGlobals endpoint called once on each peer except owner.
+				// Used by global broadcast.
+				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := upgCounters[peer.InstanceID]
+					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
+						expected++
+					}
+					assert.Equal(t, expected, upgCounters2[peer.InstanceID])
+				}
+
+				// Assert PeerGetRateLimits endpoint not called.
+				// Used by global hits update.
+				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := gprlCounters[peer.InstanceID]
+					assert.Equal(t, expected, gprlCounters2[peer.InstanceID])
+				}
+
+				// Verify all peers report consistent remaining value value.
+				for _, peer := range cluster.GetDaemons() {
+					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
+						continue
+					}
+					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, limit-testCase.Hits)
+				}
+			})
+		}
+	})
+
+	t.Run("Hits on non-owner peer", func(t *testing.T) {
+		testCases := []struct {
+			Name string
+			Hits int64
+		}{
+			{Name: "Single hit", Hits: 1},
+			{Name: "Multiple htis", Hits: 10},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				name := t.Name()
+				key := fmt.Sprintf("account:%08x", rand.Int())
+				peers, err := cluster.ListNonOwningDaemons(name, key)
+				require.NoError(t, err)
+				owner, err := cluster.FindOwningDaemon(name, key)
+				require.NoError(t, err)
+				t.Logf("Owner peer: %s", owner.InstanceID)
+
+				require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+
+				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
+				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
+				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+
+				// When
+				for i := int64(0); i < testCase.Hits; i++ {
+					sendHit(t, peers[0], makeReq(name, key, 1), guber.Status_UNDER_LIMIT, 999-i)
+				}
+
+				// Then
+				// Check for global hits update from non-owner to owner peer.
+				// Expect single global hits update from non-owner peer that received hits.
+				t.Log("Waiting for global hits updates")
+				var wg sync.WaitGroup
+				var didOwnerUpdate int
+				var didNonOwnerUpdate []string
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := updateCounters[owner.InstanceID] + 1
+					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
+						didOwnerUpdate++
+						t.Log("Global hits update from owner")
+					}
+					wg.Done()
+				}()
+				for _, peer := range peers {
+					go func(peer *guber.Daemon) {
+						expected := updateCounters[peer.InstanceID] + 1
+						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
+							didNonOwnerUpdate = append(didNonOwnerUpdate, peer.InstanceID)
+							t.Logf("Global hits update from peer %s", peer.InstanceID)
+						}
+						wg.Done()
+
+					}(peer)
+				}
+				wg.Wait()
+				assert.Zero(t, didOwnerUpdate)
+				assert.Len(t, didNonOwnerUpdate, 1)
+				assert.Equal(t, []string{peers[0].InstanceID}, didNonOwnerUpdate)
+
+				// Expect a single global broadcast to all non-owner peers.
+				t.Log("Waiting for global broadcasts")
+				var didOwnerBroadcast, didNonOwnerBroadcast int
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := broadcastCounters[owner.InstanceID] + 1
+					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
+						didOwnerBroadcast++
+						t.Log("Global broadcast from owner")
+					}
+					wg.Done()
+				}()
+				for _, peer := range peers {
+					go func(peer *guber.Daemon) {
+						expected := broadcastCounters[peer.InstanceID] + 1
+						if err := waitForBroadcast(broadcastTimeout, peer, expected); err == nil {
+							didNonOwnerBroadcast++
+							t.Logf("Global broadcast from peer %s", peer.InstanceID)
+						}
+						wg.Done()
+					}(peer)
+				}
+				wg.Wait()
+				assert.Equal(t, 1, didOwnerBroadcast)
+				assert.Empty(t, didNonOwnerBroadcast)
+
+				// Assert UpdatePeerGlobals endpoint called once on each peer except owner.
+				// Used by global broadcast.
+				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := upgCounters[peer.InstanceID]
+					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
+						expected++
+					}
+					assert.Equal(t, expected, upgCounters2[peer.InstanceID], "upgCounter %s", peer.InstanceID)
+				}
+
+				// Assert PeerGetRateLimits endpoint called once on owner.
+				// Used by global hits update.
+				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := gprlCounters[peer.InstanceID]
+					if peer.InstanceID == owner.InstanceID {
+						expected++
+					}
+					assert.Equal(t, expected, gprlCounters2[peer.InstanceID], "gprlCounter %s", peer.InstanceID)
+				}
+
+				// Verify all peers report consistent remaining value value.
+				for _, peer := range cluster.GetDaemons() {
+					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
+						continue
+					}
+					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, limit-testCase.Hits)
+				}
+			})
+		}
+	})
+
+	t.Run("Distributed hits", func(t *testing.T) {
+		testCases := []struct {
+			Name string
+			Hits int
+		}{
+			{Name: "2 hits", Hits: 2},
+			{Name: "10 hits", Hits: 10},
+			{Name: "100 hits", Hits: 100},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.Name, func(t *testing.T) {
+				name := t.Name()
+				key := fmt.Sprintf("account:%08x", rand.Int())
+				peers, err := cluster.ListNonOwningDaemons(name, key)
+				require.NoError(t, err)
+				owner, err := cluster.FindOwningDaemon(name, key)
+				require.NoError(t, err)
+				var localPeers []*guber.Daemon
+				for _, peer := range cluster.GetDaemons() {
+					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
+						localPeers = append(localPeers, peer)
+					}
+				}
+				t.Logf("Owner peer: %s", owner.InstanceID)
+
+				require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+
+				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
+				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
+				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+				expectUpdate := make(map[string]struct{})
+				var wg sync.WaitGroup
+				var mutex sync.Mutex
+
+				// When
+				wg.Add(testCase.Hits)
+				for i := 0; i < testCase.Hits; i++ {
+					peer := localPeers[i%len(localPeers)]
+					go func(peer *guber.Daemon) {
+						sendHit(t, peer, makeReq(name, key, 1), guber.Status_UNDER_LIMIT, -1)
+						if peer.InstanceID != owner.InstanceID {
+							mutex.Lock()
+							expectUpdate[peer.InstanceID] = struct{}{}
+							mutex.Unlock()
+						}
+						wg.Done()
+					}(peer)
+				}
+				wg.Wait()
+
+				// Then
+				// Check for global hits update from non-owner to owner peer.
+				// Expect single update from each non-owner peer that received
+				// hits.
+				t.Log("Waiting for global hits updates")
+				var didOwnerUpdate int64
+				var didNonOwnerUpdate []string
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := updateCounters[owner.InstanceID] + 1
+					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
+						atomic.AddInt64(&didOwnerUpdate, 1)
+						t.Log("Global hits update from owner")
+					}
+					wg.Done()
+				}()
+				for _, peer := range peers {
+					go func(peer *guber.Daemon) {
+						expected := updateCounters[peer.InstanceID] + 1
+						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
+							mutex.Lock()
+							didNonOwnerUpdate = append(didNonOwnerUpdate, peer.InstanceID)
+							mutex.Unlock()
+							t.Logf("Global hits update from peer %s", peer.InstanceID)
+						}
+						wg.Done()
+
+					}(peer)
+				}
+				wg.Wait()
+				assert.Zero(t, didOwnerUpdate)
+				assert.Len(t, didNonOwnerUpdate, len(expectUpdate))
+				expectedNonOwnerUpdate := maps.Keys(expectUpdate)
+				sort.Strings(expectedNonOwnerUpdate)
+				sort.Strings(didNonOwnerUpdate)
+				assert.Equal(t, expectedNonOwnerUpdate, didNonOwnerUpdate)
+
+				// Expect a single global broadcast to all non-owner peers.
+				t.Log("Waiting for global broadcasts")
+				var didOwnerBroadcast, didNonOwnerBroadcast int64
+				wg.Add(len(peers) + 1)
+				go func() {
+					expected := broadcastCounters[owner.InstanceID] + 1
+					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
+						atomic.AddInt64(&didOwnerBroadcast, 1)
+						t.Log("Global broadcast from owner")
 					}
 					wg.Done()
 				}()
@@ -1754,596 +5548,1990 @@ func TestGlobalBehavior(t *testing.T) {
 					go func(peer *guber.Daemon) {
 						expected := broadcastCounters[peer.InstanceID] + 1
 						if err := waitForBroadcast(broadcastTimeout, peer, expected); err == nil {
-							didNonOwnerBroadcast++
+							atomic.AddInt64(&didNonOwnerBroadcast, 1)
 							t.Logf("Global broadcast from peer %s", peer.InstanceID)
 						}
 						wg.Done()
 					}(peer)
 				}
 				wg.Wait()
-				assert.Equal(t, 1, didOwnerBroadcast)
-				assert.Zero(t, didNonOwnerBroadcast)
+				assert.Equal(t, int64(1), didOwnerBroadcast)
+				assert.Empty(t, didNonOwnerBroadcast)
+
+				// Assert UpdatePeerGlobals endpoint called at least
+				// once on each peer except owner.
+				// Used by global broadcast.
+				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := upgCounters[peer.InstanceID]
+					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
+						expected++
+					}
+					assert.GreaterOrEqual(t, upgCounters2[peer.InstanceID], expected, "upgCounter %s", peer.InstanceID)
+				}
+
+				// Assert PeerGetRateLimits endpoint called on owner
+				// for each non-owner that received hits.
+				// Used by global hits update.
+				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+				for _, peer := range cluster.GetDaemons() {
+					expected := gprlCounters[peer.InstanceID]
+					if peer.InstanceID == owner.InstanceID {
+						expected += float64(len(expectUpdate))
+					}
+					assert.Equal(t, expected, gprlCounters2[peer.InstanceID], "gprlCounter %s", peer.InstanceID)
+				}
+
+				// Verify all peers report consistent remaining value value.
+				for _, peer := range cluster.GetDaemons() {
+					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
+						continue
+					}
+					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, int64(limit-testCase.Hits))
+				}
+			})
+		}
+	})
+}
+
+// Request metrics and parse into map.
+// Optionally pass names to filter metrics by name.
+func getMetrics(HTTPAddr string, names ...string) (map[string]*model.Sample, error) {
+	url := fmt.Sprintf("http://%s/metrics", HTTPAddr)
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP error requesting metrics: %s", resp.Status)
+	}
+	decoder := expfmt.SampleDecoder{
+		Dec: expfmt.NewDecoder(resp.Body, expfmt.FmtText),
+		Opts: &expfmt.DecodeOptions{
+			Timestamp: model.Now(),
+		},
+	}
+	nameSet := make(map[string]struct{})
+	for _, name := range names {
+		nameSet[name] = struct{}{}
+	}
+	metrics := make(map[string]*model.Sample)
+
+	for {
+		var smpls model.Vector
+		err := decoder.Decode(&smpls)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, smpl := range smpls {
+			name := smpl.Metric.String()
+			if _, ok := nameSet[name]; ok || len(nameSet) == 0 {
+				metrics[name] = smpl
+			}
+		}
+	}
+
+	return metrics, nil
+}
+
+func getMetricRequest(url string, name string) (*model.Sample, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return getMetric(resp.Body, name)
+}
+
+func getMetric(in io.Reader, name string) (*model.Sample, error) {
+	dec := expfmt.SampleDecoder{
+		Dec: expfmt.NewDecoder(in, expfmt.FmtText),
+		Opts: &expfmt.DecodeOptions{
+			Timestamp: model.Now(),
+		},
+	}
+
+	var all model.Vector
+	for {
+		var smpls model.Vector
+		err := dec.Decode(&smpls)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, smpls...)
+	}
+
+	for _, s := range all {
+		if strings.Contains(s.Metric.String(), name) {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+// waitForBroadcast waits until the broadcast count for the daemon changes to
+// at least the expected value and the broadcast queue is empty.
+// Returns an error if timeout waiting for conditions to be met.
+func waitForBroadcast(timeout clock.Duration, d *guber.Daemon, expect float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		metrics, err := getMetrics(d.Config().HTTPListenAddress,
+			"gubernator_broadcast_duration_count", "gubernator_global_queue_length")
+		if err != nil {
+			return err
+		}
+		gbdc := metrics["gubernator_broadcast_duration_count"]
+		ggql := metrics["gubernator_global_queue_length"]
+
+		// It's possible a broadcast occurred twice if waiting for multiple
+		// peers to forward updates to non-owners.
+		if float64(gbdc.Value) >= expect && ggql.Value == 0 {
+			return nil
+		}
+
+		select {
+		case <-clock.After(100 * clock.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// waitForUpdate waits until the global hits update count for the daemon
+// changes to at least the expected value and the global update queue is empty.
+// Returns an error if timeout waiting for conditions to be met.
+func waitForUpdate(timeout clock.Duration, d *guber.Daemon, expect float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		metrics, err := getMetrics(d.Config().HTTPListenAddress,
+			"gubernator_global_send_duration_count", "gubernator_global_send_queue_length")
+		if err != nil {
+			return err
+		}
+		gsdc := metrics["gubernator_global_send_duration_count"]
+		gsql := metrics["gubernator_global_send_queue_length"]
+
+		// It's possible a hit occurred twice if waiting for multiple peers to
+		// forward updates to the owner.
+		if float64(gsdc.Value) >= expect && gsql.Value == 0 {
+			return nil
+		}
+
+		select {
+		case <-clock.After(100 * clock.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// waitForIdle waits until both global broadcast and global hits queues are
+// empty.
+func waitForIdle(timeout clock.Duration, daemons ...*guber.Daemon) error {
+	var wg syncutil.WaitGroup
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, d := range daemons {
+		wg.Run(func(raw any) error {
+			d := raw.(*guber.Daemon)
+			for {
+				metrics, err := getMetrics(d.Config().HTTPListenAddress,
+					"gubernator_global_queue_length", "gubernator_global_send_queue_length")
+				if err != nil {
+					return err
+				}
+				ggql := metrics["gubernator_global_queue_length"]
+				gsql := metrics["gubernator_global_send_queue_length"]
+
+				if ggql.Value == 0 && gsql.Value == 0 {
+					return nil
+				}
+
+				select {
+				case <-clock.After(100 * clock.Millisecond):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}, d)
+	}
+	errs := wg.Wait()
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
 
-				// Check for global hits update from non-owner to owner peer.
-				// Expect no global hits update because the hits were given
-				// directly to the owner peer.
-				t.Log("Waiting for global broadcasts")
-				var didOwnerUpdate, didNonOwnerUpdate int
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := updateCounters[owner.InstanceID] + 1
-					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
-						didOwnerUpdate++
-						t.Log("Global hits update from owner")
-					}
-					wg.Done()
-				}()
-				for _, peer := range peers {
-					go func(peer *guber.Daemon) {
-						expected := updateCounters[peer.InstanceID] + 1
-						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
-							didNonOwnerUpdate++
-							t.Logf("Global hits update from peer %s", peer.InstanceID)
-						}
-						wg.Done()
+func getMetricValue(t *testing.T, d *guber.Daemon, name string) float64 {
+	m, err := getMetricRequest(fmt.Sprintf("http://%s/metrics", d.Config().HTTPListenAddress),
+		name)
+	require.NoError(t, err)
+	if m == nil {
+		return 0
+	}
+	return float64(m.Value)
+}
+
+// Get metric counter values on each peer.
+func getPeerCounters(t *testing.T, peers []*guber.Daemon, name string) map[string]float64 {
+	counters := make(map[string]float64)
+	for _, peer := range peers {
+		counters[peer.InstanceID] = getMetricValue(t, peer, name)
+	}
+	return counters
+}
+
+func sendHit(t *testing.T, d *guber.Daemon, req *guber.RateLimitReq, expectStatus guber.Status, expectRemaining int64) {
+	if req.Hits != 0 {
+		t.Logf("Sending %d hits to peer %s", req.Hits, d.InstanceID)
+	}
+	client := d.MustClient()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{req},
+	})
+	require.NoError(t, err)
+	item := resp.Responses[0]
+	assert.Equal(t, "", item.Error)
+	if expectRemaining >= 0 {
+		assert.Equal(t, expectRemaining, item.Remaining)
+	}
+	assert.Equal(t, expectStatus, item.Status)
+	assert.Equal(t, req.Limit, item.Limit)
+}
+
+func epochMillis(t time.Time) int64 {
+	return t.UnixNano() / 1_000_000
+}
+
+func startGubernator() error {
+	err := cluster.StartWith([]guber.PeerInfo{
+		{GRPCAddress: "127.0.0.1:9990", HTTPAddress: "127.0.0.1:9980", DataCenter: cluster.DataCenterNone},
+		{GRPCAddress: "127.0.0.1:9991", HTTPAddress: "127.0.0.1:9981", DataCenter: cluster.DataCenterNone},
+		{GRPCAddress: "127.0.0.1:9992", HTTPAddress: "127.0.0.1:9982", DataCenter: cluster.DataCenterNone},
+		{GRPCAddress: "127.0.0.1:9993", HTTPAddress: "127.0.0.1:9983", DataCenter: cluster.DataCenterNone},
+		{GRPCAddress: "127.0.0.1:9994", HTTPAddress: "127.0.0.1:9984", DataCenter: cluster.DataCenterNone},
+		{GRPCAddress: "127.0.0.1:9995", HTTPAddress: "127.0.0.1:9985", DataCenter: cluster.DataCenterNone},
+
+		// DataCenterOne
+		{GRPCAddress: "127.0.0.1:9890", HTTPAddress: "127.0.0.1:9880", DataCenter: cluster.DataCenterOne},
+		{GRPCAddress: "127.0.0.1:9891", HTTPAddress: "127.0.0.1:9881", DataCenter: cluster.DataCenterOne},
+		{GRPCAddress: "127.0.0.1:9892", HTTPAddress: "127.0.0.1:9882", DataCenter: cluster.DataCenterOne},
+		{GRPCAddress: "127.0.0.1:9893", HTTPAddress: "127.0.0.1:9883", DataCenter: cluster.DataCenterOne},
+	})
+	if err != nil {
+		return errors.Wrap(err, "while starting cluster")
+	}
+
+	// Populate peer clients. Avoids data races when goroutines conflict trying
+	// to instantiate client singletons.
+	for _, peer := range cluster.GetDaemons() {
+		_, err = peer.Client()
+		if err != nil {
+			return errors.Wrap(err, "while connecting client")
+		}
+	}
+	return nil
+}
+
+func TestResetRateLimits(t *testing.T) {
+	conf := guber.Config{AdminEnabled: true}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
+
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	const keys = 20
+	names := []string{"test_reset_incident_a", "test_reset_incident_b", "test_reset_keep"}
+	hit := func(t *testing.T, name string, hits int64) []*guber.RateLimitResp {
+		var reqs []*guber.RateLimitReq
+		for i := 0; i < keys; i++ {
+			reqs = append(reqs, &guber.RateLimitReq{
+				Name:      name,
+				UniqueKey: fmt.Sprintf("account:%d", i),
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			})
+		}
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		for _, rl := range resp.Responses {
+			require.Equal(t, "", rl.Error)
+		}
+		return resp.Responses
+	}
+	for _, name := range names {
+		hit(t, name, 10)
+	}
+
+	conn, err := grpc.Dial(a.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := admin.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = admin.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{NameGlob: "["})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Reset by prefix", func(t *testing.T) {
+		resp, err := admin.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{
+			NamePrefix: "test_reset_incident_",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, int64(2*keys), resp.Removed)
+
+		for _, rl := range hit(t, "test_reset_incident_a", 0) {
+			assert.Equal(t, int64(10), rl.Remaining)
+		}
+		for _, rl := range hit(t, "test_reset_keep", 0) {
+			assert.Equal(t, int64(0), rl.Remaining)
+		}
+	})
+
+	t.Run("Reset by glob", func(t *testing.T) {
+		hit(t, "test_reset_incident_b", 10)
+		resp, err := admin.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{
+			NameGlob: "test_reset_*_b",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, int64(keys), resp.Removed)
+
+		for _, rl := range hit(t, "test_reset_incident_b", 0) {
+			assert.Equal(t, int64(10), rl.Remaining)
+		}
+	})
+
+	t.Run("Admin API is disabled by default", func(t *testing.T) {
+		conn, err := grpc.Dial(cluster.GetRandomPeer(cluster.DataCenterNone).GRPCAddress,
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = guber.NewAdminV1Client(conn).ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{
+			NamePrefix: "test_reset_",
+		})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestReplayJournal(t *testing.T) {
+	newServer := func() *v1Server {
+		return newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true, JournalDir: t.TempDir()})
+	}
+	a := newServer()
+	defer a.Close()
+	b := newServer()
+	defer b.Close()
+
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
+
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(a.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	const keys = 20
+	hit := func(t testutil.TestingT, hits int64) []*guber.RateLimitResp {
+		var reqs []*guber.RateLimitReq
+		for i := 0; i < keys; i++ {
+			reqs = append(reqs, &guber.RateLimitReq{
+				Name:      "test_replay_journal",
+				UniqueKey: fmt.Sprintf("account:%d", i),
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			})
+		}
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		return resp.Responses
+	}
+	// resetAndReplay loses the rate limits of every peer, then replays the journals
+	resetAndReplay := func(t testutil.TestingT, r *guber.ReplayJournalReq) *guber.ReplayJournalResp {
+		_, err := admin.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{NamePrefix: "test_replay_"})
+		require.NoError(t, err)
+		resp, err := admin.ReplayJournal(context.Background(), r)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
+		return resp
+	}
+
+	for i := 0; i < 3; i++ {
+		hit(t, 1)
+	}
+
+	// The journal is written at least once a second
+	testutil.UntilPass(t, 20, 100*clock.Millisecond, func(t testutil.TestingT) {
+		resp := resetAndReplay(t, &guber.ReplayJournalReq{})
+		assert.Equal(t, int64(3*keys), resp.Replayed)
+	})
+	for _, rl := range hit(t, 0) {
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, int64(7), rl.Remaining)
+	}
+
+	t.Run("Replayed hits are not journaled again", func(t *testing.T) {
+		clock.Sleep(1100 * clock.Millisecond)
+		resp := resetAndReplay(t, &guber.ReplayJournalReq{})
+		assert.Equal(t, int64(3*keys), resp.Replayed)
+	})
+
+	t.Run("Since and name prefix", func(t *testing.T) {
+		resp := resetAndReplay(t, &guber.ReplayJournalReq{Since: guber.MillisecondNow() + guber.Minute})
+		assert.Equal(t, int64(0), resp.Replayed)
+
+		resp = resetAndReplay(t, &guber.ReplayJournalReq{NamePrefix: "test_replay_other"})
+		assert.Equal(t, int64(0), resp.Replayed)
+		for _, rl := range hit(t, 0) {
+			assert.Equal(t, int64(10), rl.Remaining)
+		}
+	})
+
+	t.Run("Journal is disabled", func(t *testing.T) {
+		c := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+		defer c.Close()
+		conn, err := grpc.Dial(c.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+
+		_, err = guber.NewAdminV1Client(conn).ReplayJournal(context.Background(), &guber.ReplayJournalReq{})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
+
+func TestPrepareShutdown(t *testing.T) {
+	newServer := func() *v1Server {
+		return newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+	}
+	a := newServer()
+	defer a.Close()
+	b := newServer()
+	defer b.Close()
+
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
+
+	conn, err := grpc.Dial(a.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	const keys = 20
+	hit := func(server *v1Server, hits int64) []*guber.RateLimitResp {
+		client, err := guber.DialV1Server(server.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		var reqs []*guber.RateLimitReq
+		for i := 0; i < keys; i++ {
+			reqs = append(reqs, &guber.RateLimitReq{
+				Name: "test_prepare_shutdown",
+				// The FNV-1 hash of the ring spreads keys which only differ in their last bytes poorly
+				UniqueKey: fmt.Sprintf("%d:account", i),
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      hits,
+			})
+		}
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		return resp.Responses
+	}
+	hit(a, 3)
+
+	resp, err := admin.PrepareShutdown(context.Background(), &guber.PrepareShutdownReq{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Errors)
+	assert.NotZero(t, resp.Transferred)
+	assert.Zero(t, resp.Existing)
+	assert.Zero(t, resp.Failed)
+
+	// Rate limits the next owner already holds are kept
+	again, err := admin.PrepareShutdown(context.Background(), &guber.PrepareShutdownReq{})
+	require.NoError(t, err)
+	assert.Zero(t, again.Transferred)
+	assert.Equal(t, resp.Transferred, again.Existing)
+
+	// Once `a` leaves the cluster, `b` owns every rate limit with the hits applied by `a`
+	b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: b.listener.Addr().String(), IsOwner: true}})
+	for _, rl := range hit(b, 0) {
+		assert.Equal(t, "", rl.Error)
+		assert.Equal(t, int64(7), rl.Remaining)
+	}
+
+	// An instance without other peers has no one to transfer to
+	a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: a.listener.Addr().String(), IsOwner: true}})
+	_, err = admin.PrepareShutdown(context.Background(), &guber.PrepareShutdownReq{})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestListRateLimits(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	var reqs []*guber.RateLimitReq
+	for i := 0; i < 5; i++ {
+		reqs = append(reqs, &guber.RateLimitReq{
+			Name:      "test_list_rate_limits",
+			UniqueKey: fmt.Sprintf("account:%d", i),
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      int64(i + 1),
+		})
+	}
+	reqs = append(reqs, &guber.RateLimitReq{
+		Name:      "test_list_other",
+		UniqueKey: "account:0",
+		Algorithm: guber.Algorithm_LEAKY_BUCKET,
+		Duration:  guber.Minute,
+		Limit:     10,
+		Burst:     20,
+		Hits:      1,
+	})
+	_, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+	require.NoError(t, err)
+
+	conn, err := grpc.Dial(srv.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	// Page through the rate limits of one name
+	var keys []string
+	req := &guber.ListRateLimitsReq{NamePrefix: "test_list_rate_", PageSize: 2}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		resp, err := admin.ListRateLimits(context.Background(), req)
+		require.NoError(t, err)
+		for _, rl := range resp.RateLimits {
+			keys = append(keys, rl.UniqueKey)
+			assert.Equal(t, "test_list_rate_limits", rl.Name)
+			assert.Equal(t, guber.Algorithm_TOKEN_BUCKET, rl.Algorithm)
+			assert.Equal(t, int64(10), rl.Limit)
+			assert.Equal(t, 10-int64(len(keys)), rl.Remaining)
+			assert.True(t, rl.Owned)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		req.Cursor = resp.NextCursor
+	}
+	assert.Equal(t, []string{"account:0", "account:1", "account:2", "account:3", "account:4"}, keys)
+
+	// Every rate limit of one customer
+	resp, err := admin.ListRateLimits(context.Background(), &guber.ListRateLimitsReq{UniqueKeyPrefix: "account:0"})
+	require.NoError(t, err)
+	require.Len(t, resp.RateLimits, 2)
+	assert.Empty(t, resp.NextCursor)
+	assert.Equal(t, "test_list_other", resp.RateLimits[0].Name)
+	assert.Equal(t, guber.Algorithm_LEAKY_BUCKET, resp.RateLimits[0].Algorithm)
+	assert.Equal(t, int64(20), resp.RateLimits[0].Burst)
+	assert.Equal(t, "test_list_rate_limits", resp.RateLimits[1].Name)
+
+	_, err = admin.ListRateLimits(context.Background(), &guber.ListRateLimitsReq{Cursor: "!"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = admin.ListRateLimits(context.Background(), &guber.ListRateLimitsReq{PageSize: 1001})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetKeyHistory(t *testing.T) {
+	conf := guber.Config{AdminEnabled: true, KeyHistorySize: 3}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+	addrA, addrB := a.listener.Addr().String(), b.listener.Addr().String()
+	a.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA, IsOwner: true}, {GRPCAddress: addrB}})
+	b.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addrA}, {GRPCAddress: addrB, IsOwner: true}})
+
+	client, err := guber.DialV1Server(addrA, nil)
+	require.NoError(t, err)
+	owner := addrA
+	for i := 0; i < 5; i++ {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_key_history",
+				UniqueKey: "account:1234",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     4,
+				Hits:      1,
+				RequestId: fmt.Sprintf("request-%d", i),
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		if addr := resp.Responses[0].Metadata["owner"]; addr != "" {
+			owner = addr
+		}
+	}
+
+	conn, err := grpc.Dial(addrB, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	resp, err := admin.GetKeyHistory(context.Background(), &guber.GetKeyHistoryReq{
+		Name:      "test_key_history",
+		UniqueKey: "account:1234",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Errors)
+	require.NotNil(t, resp.RateLimit)
+	assert.True(t, resp.RateLimit.Owned)
+	assert.Equal(t, int64(0), resp.RateLimit.Remaining)
+
+	// Only the most recent hits are kept
+	require.Len(t, resp.Transitions, 3)
+	for i, tr := range resp.Transitions {
+		assert.Equal(t, fmt.Sprintf("request-%d", i+2), tr.RequestId)
+		assert.Equal(t, int64(1), tr.Hits)
+		assert.Equal(t, owner, tr.Owner)
+		assert.Equal(t, owner, tr.Peer)
+		assert.NotZero(t, tr.Time)
+	}
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Transitions[0].Status)
+	assert.Equal(t, int64(1), resp.Transitions[0].Remaining)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Transitions[1].Status)
+	assert.Equal(t, int64(0), resp.Transitions[1].Remaining)
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Transitions[2].Status)
+
+	resp, err = admin.GetKeyHistory(context.Background(), &guber.GetKeyHistoryReq{
+		Name:      "test_key_history",
+		UniqueKey: "account:unknown",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, resp.RateLimit)
+	assert.Empty(t, resp.Transitions)
+
+	_, err = admin.GetKeyHistory(context.Background(), &guber.GetKeyHistoryReq{Name: "test_key_history"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBehaviorMiddleware(t *testing.T) {
+	var calls []string
+	var mutex sync.Mutex
+	record := func(name string) guber.BehaviorMiddleware {
+		return func(next guber.RateLimitHandler) guber.RateLimitHandler {
+			return func(ctx context.Context, c *guber.RateLimitCheck) (*guber.RateLimitResp, error) {
+				mutex.Lock()
+				calls = append(calls, name)
+				mutex.Unlock()
+				return next(ctx, c)
+			}
+		}
+	}
+	// Denies blocked keys without evaluating the rate limit
+	deny := func(next guber.RateLimitHandler) guber.RateLimitHandler {
+		return func(ctx context.Context, c *guber.RateLimitCheck) (*guber.RateLimitResp, error) {
+			if strings.HasPrefix(c.Req.UniqueKey, "blocked:") {
+				return &guber.RateLimitResp{Status: guber.Status_OVER_LIMIT, Limit: c.Req.Limit}, nil
+			}
+			rl, err := next(ctx, c)
+			if err == nil {
+				rl.Metadata = map[string]string{"middleware": "true"}
+			}
+			return rl, err
+		}
+	}
+	fail := func(next guber.RateLimitHandler) guber.RateLimitHandler {
+		return func(ctx context.Context, c *guber.RateLimitCheck) (*guber.RateLimitResp, error) {
+			if c.Req.UniqueKey == "fail" {
+				return nil, errors.New("middleware failed")
+			}
+			return next(ctx, c)
+		}
+	}
+
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Middleware: []guber.BehaviorMiddleware{record("first"), deny, fail, record("last")},
+	})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	rateLimit := func(key string, behavior guber.Behavior) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      "test_behavior_middleware",
+			UniqueKey: key,
+			Behavior:  behavior,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Hits:      1,
+			Limit:     10,
+		}
+	}
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{
+			rateLimit("account:1", 0),
+			rateLimit("blocked:1", 0),
+			rateLimit("fail", 0),
+			rateLimit("blocked:2", guber.Behavior_DRY_RUN),
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 4)
 
-					}(peer)
-				}
-				wg.Wait()
-				assert.Zero(t, didOwnerUpdate)
-				assert.Zero(t, didNonOwnerUpdate)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Responses[0].Status)
+	assert.Equal(t, int64(9), resp.Responses[0].Remaining)
+	assert.Equal(t, "true", resp.Responses[0].Metadata["middleware"])
 
-				// Assert UpdatePeerGlobals endpoint called once on each peer except owner.
-				// Used by global broadcast.
-				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := upgCounters[peer.InstanceID]
-					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
-						expected++
-					}
-					assert.Equal(t, expected, upgCounters2[peer.InstanceID])
-				}
+	assert.Equal(t, guber.Status_OVER_LIMIT, resp.Responses[1].Status)
+	assert.Equal(t, "middleware failed", resp.Responses[2].Error)
 
-				// Assert PeerGetRateLimits endpoint not called.
-				// Used by global hits update.
-				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := gprlCounters[peer.InstanceID]
-					assert.Equal(t, expected, gprlCounters2[peer.InstanceID])
-				}
+	// DRY_RUN wraps the middleware
+	assert.Equal(t, guber.Status_UNDER_LIMIT, resp.Responses[3].Status)
+	assert.Equal(t, guber.Status_OVER_LIMIT.String(), resp.Responses[3].Metadata["dry_run_status"])
 
-				// Verify all peers report consistent remaining value value.
-				for _, peer := range cluster.GetDaemons() {
-					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
-						continue
-					}
-					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, limit-testCase.Hits)
-				}
-			})
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{"first", "last", "first", "first", "first"}, calls)
+}
+
+func TestDecisionCallout(t *testing.T) {
+	var calls atomic.Int64
+	callout := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		b, _ := io.ReadAll(r.Body)
+		switch {
+		case bytes.Contains(b, []byte("account:night")):
+			_, _ = w.Write([]byte(`{"status": "OVER_LIMIT", "metadata": {"reason": "outside business hours"}}`))
+		case bytes.Contains(b, []byte("account:slow")):
+			clock.Sleep(clock.Millisecond * 200)
+			_, _ = w.Write([]byte(`{"status": "OVER_LIMIT"}`))
+		case bytes.Contains(b, []byte("account:error")):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{}`))
 		}
+	}))
+	defer callout.Close()
+
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		Middleware: []guber.BehaviorMiddleware{guber.NewDecisionCallout(guber.DecisionCalloutConfig{
+			URL:     callout.URL,
+			Timeout: clock.Millisecond * 50,
+			Names:   []string{"test_callout"},
+		})},
 	})
+	defer srv.Close()
+	srv.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
 
-	t.Run("Hits on non-owner peer", func(t *testing.T) {
-		testCases := []struct {
-			Name string
-			Hits int64
-		}{
-			{Name: "Single hit", Hits: 1},
-			{Name: "Multiple htis", Hits: 10},
-		}
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	hit := func(name, key string) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Hits:      1,
+				Limit:     10,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
 
-		for _, testCase := range testCases {
-			t.Run(testCase.Name, func(t *testing.T) {
-				name := t.Name()
-				key := fmt.Sprintf("account:%08x", rand.Int())
-				peers, err := cluster.ListNonOwningDaemons(name, key)
-				require.NoError(t, err)
-				owner, err := cluster.FindOwningDaemon(name, key)
-				require.NoError(t, err)
-				t.Logf("Owner peer: %s", owner.InstanceID)
+	rl := hit("test_callout", "account:night")
+	assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	assert.Equal(t, "outside business hours", rl.Metadata["reason"])
+	// The hits were still applied by the algorithm
+	assert.Equal(t, int64(9), rl.Remaining)
 
-				require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+	rl = hit("test_callout", "account:day")
+	assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
 
-				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
-				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
-				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
+	// The decision of the algorithm is returned if the callout fails or times out
+	assert.Equal(t, guber.Status_UNDER_LIMIT, hit("test_callout", "account:error").Status)
+	assert.Equal(t, guber.Status_UNDER_LIMIT, hit("test_callout", "account:slow").Status)
+	assert.Equal(t, int64(4), calls.Load())
 
-				// When
-				for i := int64(0); i < testCase.Hits; i++ {
-					sendHit(t, peers[0], makeReq(name, key, 1), guber.Status_UNDER_LIMIT, 999-i)
-				}
+	// Other names are not sent to the callout
+	assert.Equal(t, guber.Status_UNDER_LIMIT, hit("test_callout_other", "account:night").Status)
+	assert.Equal(t, int64(4), calls.Load())
+}
 
-				// Then
-				// Check for global hits update from non-owner to owner peer.
-				// Expect single global hits update from non-owner peer that received hits.
-				t.Log("Waiting for global hits updates")
-				var wg sync.WaitGroup
-				var didOwnerUpdate int
-				var didNonOwnerUpdate []string
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := updateCounters[owner.InstanceID] + 1
-					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
-						didOwnerUpdate++
-						t.Log("Global hits update from owner")
-					}
-					wg.Done()
-				}()
-				for _, peer := range peers {
-					go func(peer *guber.Daemon) {
-						expected := updateCounters[peer.InstanceID] + 1
-						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
-							didNonOwnerUpdate = append(didNonOwnerUpdate, peer.InstanceID)
-							t.Logf("Global hits update from peer %s", peer.InstanceID)
-						}
-						wg.Done()
+func TestOverrides(t *testing.T) {
+	conf := guber.Config{AdminEnabled: true}
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
 
-					}(peer)
-				}
-				wg.Wait()
-				assert.Zero(t, didOwnerUpdate)
-				assert.Len(t, didNonOwnerUpdate, 1)
-				assert.Equal(t, []string{peers[0].InstanceID}, didNonOwnerUpdate)
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
 
-				// Expect a single global broadcast to all non-owner peers.
-				t.Log("Waiting for global broadcasts")
-				var didOwnerBroadcast, didNonOwnerBroadcast int
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := broadcastCounters[owner.InstanceID] + 1
-					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
-						didOwnerBroadcast++
-						t.Log("Global broadcast from owner")
-					}
-					wg.Done()
-				}()
-				for _, peer := range peers {
-					go func(peer *guber.Daemon) {
-						expected := broadcastCounters[peer.InstanceID] + 1
-						if err := waitForBroadcast(broadcastTimeout, peer, expected); err == nil {
-							didNonOwnerBroadcast++
-							t.Logf("Global broadcast from peer %s", peer.InstanceID)
-						}
-						wg.Done()
-					}(peer)
-				}
-				wg.Wait()
-				assert.Equal(t, 1, didOwnerBroadcast)
-				assert.Empty(t, didNonOwnerBroadcast)
+	conn, err := grpc.Dial(a.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
 
-				// Assert UpdatePeerGlobals endpoint called once on each peer except owner.
-				// Used by global broadcast.
-				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := upgCounters[peer.InstanceID]
-					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
-						expected++
-					}
-					assert.Equal(t, expected, upgCounters2[peer.InstanceID], "upgCounter %s", peer.InstanceID)
-				}
+	hit := func(t *testing.T, addr, key string) *guber.RateLimitResp {
+		t.Helper()
+		client, err := guber.DialV1Server(addr, nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_overrides",
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Responses, 1)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
 
-				// Assert PeerGetRateLimits endpoint called once on owner.
-				// Used by global hits update.
-				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := gprlCounters[peer.InstanceID]
-					if peer.InstanceID == owner.InstanceID {
-						expected++
-					}
-					assert.Equal(t, expected, gprlCounters2[peer.InstanceID], "gprlCounter %s", peer.InstanceID)
-				}
+	t.Run("Invalid request", func(t *testing.T) {
+		_, err := admin.SetOverride(context.Background(), &guber.SetOverrideReq{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 
-				// Verify all peers report consistent remaining value value.
-				for _, peer := range cluster.GetDaemons() {
-					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
-						continue
-					}
-					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, limit-testCase.Hits)
-				}
-			})
-		}
+		_, err = admin.SetOverride(context.Background(), &guber.SetOverrideReq{
+			Override: &guber.Override{Name: "test_overrides", Action: guber.OverrideAction(5)},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 
-	t.Run("Distributed hits", func(t *testing.T) {
-		testCases := []struct {
-			Name string
-			Hits int
-		}{
-			{Name: "2 hits", Hits: 2},
-			{Name: "10 hits", Hits: 10},
-			{Name: "100 hits", Hits: 100},
-		}
+	t.Run("Deny key on every peer", func(t *testing.T) {
+		resp, err := admin.SetOverride(context.Background(), &guber.SetOverrideReq{
+			Override: &guber.Override{
+				Name:      "test_overrides",
+				UniqueKey: "account:banned",
+				Action:    guber.OverrideAction_DENY,
+				Reason:    "abuse",
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
 
-		for _, testCase := range testCases {
-			t.Run(testCase.Name, func(t *testing.T) {
-				name := t.Name()
-				key := fmt.Sprintf("account:%08x", rand.Int())
-				peers, err := cluster.ListNonOwningDaemons(name, key)
-				require.NoError(t, err)
-				owner, err := cluster.FindOwningDaemon(name, key)
-				require.NoError(t, err)
-				var localPeers []*guber.Daemon
-				for _, peer := range cluster.GetDaemons() {
-					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
-						localPeers = append(localPeers, peer)
-					}
-				}
-				t.Logf("Owner peer: %s", owner.InstanceID)
+		for _, addr := range []string{a.listener.Addr().String(), b.listener.Addr().String()} {
+			rl := hit(t, addr, "account:banned")
+			assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+			assert.Equal(t, "deny", rl.Metadata[guber.MetadataOverride])
+			assert.Equal(t, "abuse", rl.Metadata[guber.MetadataOverrideReason])
+		}
+		// Other keys are unaffected
+		rl := hit(t, a.listener.Addr().String(), "account:other")
+		assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+		assert.Equal(t, int64(9), rl.Remaining)
+	})
 
-				require.NoError(t, waitForIdle(1*clock.Minute, cluster.GetDaemons()...))
+	t.Run("Allow namespace", func(t *testing.T) {
+		_, err := admin.SetOverride(context.Background(), &guber.SetOverrideReq{
+			Override: &guber.Override{Name: "test_overrides", Action: guber.OverrideAction_ALLOW},
+		})
+		require.NoError(t, err)
 
-				broadcastCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_broadcast_duration_count")
-				updateCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_global_send_duration_count")
-				upgCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				gprlCounters := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
-				expectUpdate := make(map[string]struct{})
-				var wg sync.WaitGroup
-				var mutex sync.Mutex
+		for i := 0; i < 20; i++ {
+			rl := hit(t, b.listener.Addr().String(), "account:vip")
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, int64(10), rl.Remaining)
+			assert.Equal(t, "allow", rl.Metadata[guber.MetadataOverride])
+		}
+		// The override for the unique key takes precedence
+		rl := hit(t, b.listener.Addr().String(), "account:banned")
+		assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+	})
 
-				// When
-				wg.Add(testCase.Hits)
-				for i := 0; i < testCase.Hits; i++ {
-					peer := localPeers[i%len(localPeers)]
-					go func(peer *guber.Daemon) {
-						sendHit(t, peer, makeReq(name, key, 1), guber.Status_UNDER_LIMIT, -1)
-						if peer.InstanceID != owner.InstanceID {
-							mutex.Lock()
-							expectUpdate[peer.InstanceID] = struct{}{}
-							mutex.Unlock()
-						}
-						wg.Done()
-					}(peer)
-				}
-				wg.Wait()
+	t.Run("List and delete", func(t *testing.T) {
+		list, err := admin.ListOverrides(context.Background(), &guber.ListOverridesReq{})
+		require.NoError(t, err)
+		require.Len(t, list.Overrides, 2)
+		assert.Equal(t, "", list.Overrides[0].UniqueKey)
+		assert.Equal(t, "account:banned", list.Overrides[1].UniqueKey)
 
-				// Then
-				// Check for global hits update from non-owner to owner peer.
-				// Expect single update from each non-owner peer that received
-				// hits.
-				t.Log("Waiting for global hits updates")
-				var didOwnerUpdate int64
-				var didNonOwnerUpdate []string
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := updateCounters[owner.InstanceID] + 1
-					if err := waitForUpdate(broadcastTimeout, owner, expected); err == nil {
-						atomic.AddInt64(&didOwnerUpdate, 1)
-						t.Log("Global hits update from owner")
-					}
-					wg.Done()
-				}()
-				for _, peer := range peers {
-					go func(peer *guber.Daemon) {
-						expected := updateCounters[peer.InstanceID] + 1
-						if err := waitForUpdate(broadcastTimeout, peer, expected); err == nil {
-							mutex.Lock()
-							didNonOwnerUpdate = append(didNonOwnerUpdate, peer.InstanceID)
-							mutex.Unlock()
-							t.Logf("Global hits update from peer %s", peer.InstanceID)
-						}
-						wg.Done()
+		_, err = admin.DeleteOverride(context.Background(), &guber.DeleteOverrideReq{Name: "test_overrides"})
+		require.NoError(t, err)
+		rl := hit(t, b.listener.Addr().String(), "account:vip")
+		assert.Equal(t, int64(9), rl.Remaining)
+		assert.Empty(t, rl.Metadata[guber.MetadataOverride])
+	})
 
-					}(peer)
-				}
-				wg.Wait()
-				assert.Zero(t, didOwnerUpdate)
-				assert.Len(t, didNonOwnerUpdate, len(expectUpdate))
-				expectedNonOwnerUpdate := maps.Keys(expectUpdate)
-				sort.Strings(expectedNonOwnerUpdate)
-				sort.Strings(didNonOwnerUpdate)
-				assert.Equal(t, expectedNonOwnerUpdate, didNonOwnerUpdate)
+	t.Run("Expired override", func(t *testing.T) {
+		_, err := admin.SetOverride(context.Background(), &guber.SetOverrideReq{
+			Override: &guber.Override{
+				Name:      "test_overrides",
+				UniqueKey: "account:expired",
+				Action:    guber.OverrideAction_DENY,
+				ExpireAt:  epochMillis(clock.Now().Add(-clock.Second)),
+			},
+		})
+		require.NoError(t, err)
+		rl := hit(t, a.listener.Addr().String(), "account:expired")
+		assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+	})
 
-				// Expect a single global broadcast to all non-owner peers.
-				t.Log("Waiting for global broadcasts")
-				var didOwnerBroadcast, didNonOwnerBroadcast int64
-				wg.Add(len(peers) + 1)
-				go func() {
-					expected := broadcastCounters[owner.InstanceID] + 1
-					if err := waitForBroadcast(broadcastTimeout, owner, expected); err == nil {
-						atomic.AddInt64(&didOwnerBroadcast, 1)
-						t.Log("Global broadcast from owner")
-					}
-					wg.Done()
-				}()
-				for _, peer := range peers {
-					go func(peer *guber.Daemon) {
-						expected := broadcastCounters[peer.InstanceID] + 1
-						if err := waitForBroadcast(broadcastTimeout, peer, expected); err == nil {
-							atomic.AddInt64(&didNonOwnerBroadcast, 1)
-							t.Logf("Global broadcast from peer %s", peer.InstanceID)
-						}
-						wg.Done()
-					}(peer)
-				}
-				wg.Wait()
-				assert.Equal(t, int64(1), didOwnerBroadcast)
-				assert.Empty(t, didNonOwnerBroadcast)
+	t.Run("Pause namespace", func(t *testing.T) {
+		_, err := admin.PauseNamespace(context.Background(), &guber.PauseNamespaceReq{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 
-				// Assert UpdatePeerGlobals endpoint called at least
-				// once on each peer except owner.
-				// Used by global broadcast.
-				upgCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/UpdatePeerGlobals\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := upgCounters[peer.InstanceID]
-					if peer.PeerInfo.DataCenter == cluster.DataCenterNone && peer.InstanceID != owner.InstanceID {
-						expected++
-					}
-					assert.GreaterOrEqual(t, upgCounters2[peer.InstanceID], expected, "upgCounter %s", peer.InstanceID)
-				}
+		resp, err := admin.PauseNamespace(context.Background(), &guber.PauseNamespaceReq{
+			Name:   "test_overrides",
+			Reason: "incident",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
+
+		// Hits are applied but never over the limit
+		var rl *guber.RateLimitResp
+		for i := 0; i < 12; i++ {
+			rl = hit(t, b.listener.Addr().String(), "account:paused")
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, "pause", rl.Metadata[guber.MetadataOverride])
+			assert.Equal(t, "incident", rl.Metadata[guber.MetadataOverrideReason])
+		}
+		assert.Equal(t, int64(0), rl.Remaining)
+		assert.Equal(t, guber.Status_OVER_LIMIT.String(), rl.Metadata["dry_run_status"])
+		// The override for the unique key takes precedence
+		rl = hit(t, a.listener.Addr().String(), "account:banned")
+		assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
 
-				// Assert PeerGetRateLimits endpoint called on owner
-				// for each non-owner that received hits.
-				// Used by global hits update.
-				gprlCounters2 := getPeerCounters(t, cluster.GetDaemons(), "gubernator_grpc_request_duration_count{method=\"/pb.gubernator.PeersV1/GetPeerRateLimits\"}")
-				for _, peer := range cluster.GetDaemons() {
-					expected := gprlCounters[peer.InstanceID]
-					if peer.InstanceID == owner.InstanceID {
-						expected += float64(len(expectUpdate))
-					}
-					assert.Equal(t, expected, gprlCounters2[peer.InstanceID], "gprlCounter %s", peer.InstanceID)
-				}
+		_, err = admin.ResumeNamespace(context.Background(), &guber.ResumeNamespaceReq{Name: "test_overrides"})
+		require.NoError(t, err)
+		for _, addr := range []string{a.listener.Addr().String(), b.listener.Addr().String()} {
+			rl = hit(t, addr, "account:paused")
+			assert.Equal(t, guber.Status_OVER_LIMIT, rl.Status)
+			assert.Empty(t, rl.Metadata[guber.MetadataOverride])
+		}
 
-				// Verify all peers report consistent remaining value value.
-				for _, peer := range cluster.GetDaemons() {
-					if peer.PeerInfo.DataCenter != cluster.DataCenterNone {
-						continue
-					}
-					sendHit(t, peer, makeReq(name, key, 0), guber.Status_UNDER_LIMIT, int64(limit-testCase.Hits))
-				}
+		// Resuming never removes another override for the namespace
+		_, err = admin.SetOverride(context.Background(), &guber.SetOverrideReq{
+			Override: &guber.Override{Name: "test_overrides_allowed", Action: guber.OverrideAction_ALLOW},
+		})
+		require.NoError(t, err)
+		_, err = admin.ResumeNamespace(context.Background(), &guber.ResumeNamespaceReq{Name: "test_overrides_allowed"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		_, err = admin.DeleteOverride(context.Background(), &guber.DeleteOverrideReq{Name: "test_overrides_allowed"})
+		require.NoError(t, err)
+	})
+
+	t.Run("New peer copies overrides", func(t *testing.T) {
+		c := newV1Server(t, "localhost:0", conf)
+		defer c.Close()
+		c.srv.SetPeers([]guber.PeerInfo{
+			{GRPCAddress: a.listener.Addr().String()},
+			{GRPCAddress: c.listener.Addr().String(), IsOwner: true},
+		})
+
+		testutil.UntilPass(t, 20, 100*clock.Millisecond, func(t testutil.TestingT) {
+			client, err := guber.DialV1Server(c.listener.Addr().String(), nil)
+			require.NoError(t, err)
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{{
+					Name:      "test_overrides",
+					UniqueKey: "account:banned",
+					Duration:  guber.Minute,
+					Limit:     10,
+					Hits:      1,
+				}},
 			})
-		}
+			require.NoError(t, err)
+			assert.Equal(t, "deny", resp.Responses[0].Metadata[guber.MetadataOverride])
+		})
 	})
 }
 
-// Request metrics and parse into map.
-// Optionally pass names to filter metrics by name.
-func getMetrics(HTTPAddr string, names ...string) (map[string]*model.Sample, error) {
-	url := fmt.Sprintf("http://%s/metrics", HTTPAddr)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+type mockAuditSink struct {
+	mutex   sync.Mutex
+	records []guber.AuditRecord
+}
+
+func (m *mockAuditSink) Record(r guber.AuditRecord) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.records = append(m.records, r)
+}
+
+func (m *mockAuditSink) Close() error { return nil }
+
+func (m *mockAuditSink) Records() []guber.AuditRecord {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]guber.AuditRecord(nil), m.records...)
+}
+
+func TestNamespaceUsage(t *testing.T) {
+	reports := make(chan guber.UsageReport, 10)
+	conf := guber.Config{
+		AdminEnabled: true,
+		UsageWindow:  clock.Minute,
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error requesting metrics: %s", resp.Status)
+	a := newV1Server(t, "localhost:0", conf)
+	defer a.Close()
+	conf.UsageExporter = func(ctx context.Context, report guber.UsageReport) error {
+		select {
+		case reports <- report:
+		default:
+		}
+		return nil
 	}
-	decoder := expfmt.SampleDecoder{
-		Dec: expfmt.NewDecoder(resp.Body, expfmt.FmtText),
-		Opts: &expfmt.DecodeOptions{
-			Timestamp: model.Now(),
+	conf.UsageExportInterval = clock.Millisecond * 50
+	conf.InstanceID = "usage-instance"
+	b := newV1Server(t, "localhost:0", conf)
+	defer b.Close()
+
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+	b.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String(), IsOwner: true},
+	})
+
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	const keys = 20
+	var reqs []*guber.RateLimitReq
+	for i := 0; i < keys; i++ {
+		reqs = append(reqs, &guber.RateLimitReq{
+			Name:      "test_usage_a",
+			UniqueKey: fmt.Sprintf("account:%d", i),
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Duration:  guber.Minute,
+			Limit:     2,
+			Hits:      1,
+		})
+	}
+	reqs = append(reqs, &guber.RateLimitReq{
+		Name:      "test_usage_b",
+		UniqueKey: "account:1",
+		Algorithm: guber.Algorithm_TOKEN_BUCKET,
+		Duration:  guber.Minute,
+		Limit:     2,
+		Hits:      5,
+	})
+	for i := 0; i < 3; i++ {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		for _, rl := range resp.Responses {
+			require.Equal(t, "", rl.Error)
+		}
+	}
+
+	conn, err := grpc.Dial(a.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	resp, err := admin.GetNamespaceUsage(context.Background(), &guber.GetNamespaceUsageReq{NamePrefix: "test_usage_"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, clock.Minute.Milliseconds(), resp.Window)
+	require.Len(t, resp.Namespaces, 2)
+	assert.Equal(t, "test_usage_a", resp.Namespaces[0].Name)
+	assert.Equal(t, int64(keys*3), resp.Namespaces[0].Hits)
+	assert.Equal(t, int64(keys), resp.Namespaces[0].OverLimit)
+	assert.Equal(t, int64(keys), resp.Namespaces[0].DistinctKeys)
+	assert.Equal(t, "test_usage_b", resp.Namespaces[1].Name)
+	assert.Equal(t, int64(15), resp.Namespaces[1].Hits)
+	assert.Equal(t, int64(3), resp.Namespaces[1].OverLimit)
+	assert.Equal(t, int64(1), resp.Namespaces[1].DistinctKeys)
+	assert.Empty(t, resp.Namespaces[0].TopKeys)
+
+	// The top keys of every peer are merged
+	resp, err = admin.GetNamespaceUsage(context.Background(), &guber.GetNamespaceUsageReq{NamePrefix: "test_usage_", TopKeys: 2})
+	require.NoError(t, err)
+	require.Len(t, resp.Namespaces, 2)
+	require.Len(t, resp.Namespaces[0].TopKeys, 2)
+	assert.Equal(t, "account:0", resp.Namespaces[0].TopKeys[0].UniqueKey)
+	assert.Equal(t, int64(3), resp.Namespaces[0].TopKeys[0].Hits)
+	assert.Equal(t, "account:1", resp.Namespaces[0].TopKeys[1].UniqueKey)
+	require.Len(t, resp.Namespaces[1].TopKeys, 1)
+	assert.Equal(t, "account:1", resp.Namespaces[1].TopKeys[0].UniqueKey)
+	assert.Equal(t, int64(15), resp.Namespaces[1].TopKeys[0].Hits)
+
+	// Each instance exports only the usage of the rate limits it owns
+	var owned int64
+	for _, req := range reqs[:keys] {
+		peer, err := b.srv.GetPeer(context.Background(), guber.LegacyHashKey(req))
+		require.NoError(t, err)
+		if peer.Info().IsOwner {
+			owned++
+		}
+	}
+	testutil.UntilPass(t, 50, clock.Millisecond*100, func(t testutil.TestingT) {
+		report := <-reports
+		assert.Equal(t, "usage-instance", report.InstanceID)
+		var distinct int64
+		for _, n := range report.Namespaces {
+			if n.Name == "test_usage_a" {
+				distinct = n.DistinctKeys
+			}
+		}
+		assert.Equal(t, owned, distinct)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+		defer srv.Close()
+		conn, err := grpc.Dial(srv.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = guber.NewAdminV1Client(conn).GetNamespaceUsage(context.Background(), &guber.GetNamespaceUsageReq{})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("Privacy", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{
+			AdminEnabled: true,
+			UsageWindow:  clock.Hour,
+			UsagePrivacy: guber.UsagePrivacyConfig{NoisePercent: 10, Bucket: 10},
+		})
+		defer srv.Close()
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		_, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_usage_privacy",
+				UniqueKey: "account:1",
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     1000,
+				Hits:      95,
+			}},
+		})
+		require.NoError(t, err)
+
+		conn, err := grpc.Dial(srv.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer conn.Close()
+		usage := func() *guber.NamespaceUsage {
+			resp, err := guber.NewAdminV1Client(conn).GetNamespaceUsage(context.Background(),
+				&guber.GetNamespaceUsageReq{NamePrefix: "test_usage_privacy", TopKeys: 1})
+			require.NoError(t, err)
+			require.Len(t, resp.Namespaces, 1)
+			return resp.Namespaces[0]
+		}
+
+		n := usage()
+		// 95 hits moved by up to 9 then rounded up to a multiple of 10
+		assert.Contains(t, []int64{90, 100, 110}, n.Hits)
+		assert.Contains(t, []int64{90, 100, 110}, n.TopKeys[0].Hits)
+		assert.Equal(t, int64(10), n.DistinctKeys)
+		assert.Equal(t, int64(0), n.OverLimit)
+		// The noise does not change until the window moves forward
+		assert.Equal(t, n.Hits, usage().Hits)
+	})
+}
+
+func TestCostCenters(t *testing.T) {
+	reports := make(chan guber.UsageReport, 10)
+	s := newV1Server(t, "localhost:0", guber.Config{
+		UsageWindow:         clock.Minute,
+		UsageExportInterval: clock.Millisecond * 50,
+		MaxCostCenters:      2,
+		UsageExporter: func(ctx context.Context, report guber.UsageReport) error {
+			select {
+			case reports <- report:
+			default:
+			}
+			return nil
 		},
+	})
+	defer s.Close()
+
+	client, err := guber.DialV1Server(s.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	hit := func(costCenter string, hits int64) *guber.RateLimitReq {
+		r := &guber.RateLimitReq{
+			Name:      "test_cost_centers",
+			UniqueKey: "account:" + costCenter,
+			Duration:  guber.Minute,
+			Limit:     100,
+			Hits:      hits,
+		}
+		if costCenter != "" {
+			r.Metadata = map[string]string{guber.MetadataCostCenter: costCenter}
+		}
+		return r
 	}
-	nameSet := make(map[string]struct{})
-	for _, name := range names {
-		nameSet[name] = struct{}{}
+	for _, reqs := range [][]*guber.RateLimitReq{
+		{hit("search", 3), hit("billing", 2)},
+		// Cost centers beyond MaxCostCenters are attributed to 'other'
+		{hit("search", 1), hit("ads", 4), hit("reports", 5), hit("", 6)},
+	} {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		for _, rl := range resp.Responses {
+			require.Equal(t, "", rl.Error)
+		}
 	}
-	metrics := make(map[string]*model.Sample)
 
-	for {
-		var smpls model.Vector
-		err := decoder.Decode(&smpls)
-		if err == io.EOF {
-			break
+	testutil.UntilPass(t, 50, clock.Millisecond*100, func(t testutil.TestingT) {
+		report := <-reports
+		hits := make(map[string]int64)
+		for _, cc := range report.CostCenters {
+			hits[cc.Name] = cc.Hits
+			assert.Greater(t, cc.Seconds, float64(0))
 		}
-		if err != nil {
-			return nil, err
+		assert.Equal(t, map[string]int64{"billing": 2, "other": 9, "search": 4, "untagged": 6}, hits)
+	})
+}
+func TestOverLimitAlerts(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	events := make(chan guber.AlertEvent, 10)
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		InstanceID: "alert-instance",
+		OverLimitAlerts: []guber.OverLimitAlert{
+			{Prefix: "test_alert", Threshold: 2, For: 2 * clock.Minute},
+		},
+		AlertNotifier: func(_ context.Context, event guber.AlertEvent) error {
+			events <- event
+			return nil
+		},
+	})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	// Each call is over the limit 3 times, once with DRY_RUN which is not counted
+	overLimit := func() {
+		for _, req := range []*guber.RateLimitReq{
+			{Name: "test_alert", UniqueKey: "account:1", Hits: 2, Limit: 1, Duration: guber.Minute},
+			{Name: "test_alert", UniqueKey: "account:2", Hits: 2, Limit: 1, Duration: guber.Minute},
+			{Name: "test_alert", UniqueKey: "account:3", Hits: 2, Limit: 1, Duration: guber.Minute},
+			{Name: "test_alert", UniqueKey: "account:4", Hits: 2, Limit: 1, Duration: guber.Minute, Behavior: guber.Behavior_DRY_RUN},
+			{Name: "other", UniqueKey: "account:1", Hits: 2, Limit: 1, Duration: guber.Minute},
+		} {
+			_, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: []*guber.RateLimitReq{req}})
+			require.NoError(t, err)
 		}
-		for _, smpl := range smpls {
-			name := smpl.Metric.String()
-			if _, ok := nameSet[name]; ok || len(nameSet) == 0 {
-				metrics[name] = smpl
+	}
+	// Advances to the next evaluation and waits for the alert to be evaluated
+	nextMinute := func() []guber.AlertEvent {
+		clock.Advance(clock.Minute)
+		var result []guber.AlertEvent
+		for {
+			select {
+			case e := <-events:
+				result = append(result, e)
+			case <-time.After(100 * time.Millisecond):
+				return result
 			}
 		}
 	}
 
-	return metrics, nil
+	overLimit()
+	assert.Empty(t, nextMinute(), "must exceed the threshold for 2 minutes")
+
+	overLimit()
+	fired := nextMinute()
+	require.Len(t, fired, 1)
+	assert.Equal(t, "test_alert", fired[0].Prefix)
+	assert.Equal(t, "alert-instance", fired[0].InstanceID)
+	assert.Equal(t, int64(3), fired[0].OverLimit)
+	assert.Equal(t, int64(2), fired[0].Threshold)
+	assert.False(t, fired[0].Resolved)
+
+	overLimit()
+	assert.Empty(t, nextMinute(), "already firing")
+
+	resolved := nextMinute()
+	require.Len(t, resolved, 1)
+	assert.True(t, resolved[0].Resolved)
+	assert.Equal(t, int64(0), resolved[0].OverLimit)
+
+	t.Run("Requires a notifier", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers:     []*grpc.Server{grpc.NewServer()},
+			OverLimitAlerts: []guber.OverLimitAlert{{Prefix: "test_alert", Threshold: 1}},
+		})
+		assert.Error(t, err)
+	})
 }
 
-func getMetricRequest(url string, name string) (*model.Sample, error) {
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+func TestLimitDrift(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+	defer srv.Close()
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(srv.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	send := func(key string, algorithm guber.Algorithm, limit int64) {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_limit_drift",
+				UniqueKey: key,
+				Algorithm: algorithm,
+				Duration:  guber.Minute,
+				Limit:     limit,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
 	}
-	defer resp.Body.Close()
-	return getMetric(resp.Body, name)
+
+	// Two clients disagree on the limit of the same key
+	send("account:1", guber.Algorithm_TOKEN_BUCKET, 100)
+	send("account:1", guber.Algorithm_TOKEN_BUCKET, 50)
+	send("account:1", guber.Algorithm_TOKEN_BUCKET, 100)
+	// A client switched algorithms
+	send("account:2", guber.Algorithm_TOKEN_BUCKET, 10)
+	send("account:2", guber.Algorithm_LEAKY_BUCKET, 10)
+	// Clients which agree do not drift
+	send("account:3", guber.Algorithm_TOKEN_BUCKET, 10)
+	send("account:3", guber.Algorithm_TOKEN_BUCKET, 10)
+
+	resp, err := admin.GetLimitDrift(context.Background(), &guber.GetLimitDriftReq{NamePrefix: "test_limit_"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Errors)
+	require.Len(t, resp.Drifts, 2)
+
+	assert.Equal(t, "test_limit_drift", resp.Drifts[0].Name)
+	assert.Equal(t, "account:1", resp.Drifts[0].UniqueKey)
+	assert.Equal(t, int64(2), resp.Drifts[0].Changes)
+	assert.Equal(t, int64(50), resp.Drifts[0].Previous.Limit)
+	assert.Equal(t, int64(100), resp.Drifts[0].Current.Limit)
+	assert.NotZero(t, resp.Drifts[0].ChangedAt)
+
+	assert.Equal(t, "account:2", resp.Drifts[1].UniqueKey)
+	assert.Equal(t, int64(1), resp.Drifts[1].Changes)
+	assert.Equal(t, guber.Algorithm_TOKEN_BUCKET, resp.Drifts[1].Previous.Algorithm)
+	assert.Equal(t, guber.Algorithm_LEAKY_BUCKET, resp.Drifts[1].Current.Algorithm)
+	assert.Equal(t, int64(10), resp.Drifts[1].Current.Burst)
+
+	resp, err = admin.GetLimitDrift(context.Background(), &guber.GetLimitDriftReq{NamePrefix: "other_"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Drifts)
 }
 
-func getMetric(in io.Reader, name string) (*model.Sample, error) {
-	dec := expfmt.SampleDecoder{
-		Dec: expfmt.NewDecoder(in, expfmt.FmtText),
-		Opts: &expfmt.DecodeOptions{
-			Timestamp: model.Now(),
-		},
+func TestAlgorithmChangePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		policy    string
+		from, to  guber.Algorithm
+		remaining int64
+		err       string
+	}{
+		{guber.AlgorithmChangeReset, guber.Algorithm_TOKEN_BUCKET, guber.Algorithm_LEAKY_BUCKET, 9, ""},
+		{guber.AlgorithmChangeTranslate, guber.Algorithm_TOKEN_BUCKET, guber.Algorithm_LEAKY_BUCKET, 1, ""},
+		{guber.AlgorithmChangeTranslate, guber.Algorithm_LEAKY_BUCKET, guber.Algorithm_TOKEN_BUCKET, 1, ""},
+		{guber.AlgorithmChangeReject, guber.Algorithm_TOKEN_BUCKET, guber.Algorithm_LEAKY_BUCKET, 0, guber.ErrAlgorithmChanged},
+	} {
+		t.Run(fmt.Sprintf("%s %s", tc.policy, tc.to), func(t *testing.T) {
+			srv := newV1Server(t, "localhost:0", guber.Config{AlgorithmChangePolicy: tc.policy})
+			defer srv.Close()
+			client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+			require.NoError(t, err)
+
+			send := func(algorithm guber.Algorithm, hits int64) *guber.RateLimitResp {
+				resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+					Requests: []*guber.RateLimitReq{{
+						Name:      "test_algorithm_change",
+						UniqueKey: "account:1",
+						Algorithm: algorithm,
+						Duration:  guber.Minute,
+						Limit:     10,
+						Hits:      hits,
+					}},
+				})
+				require.NoError(t, err)
+				return resp.Responses[0]
+			}
+
+			rl := send(tc.from, 8)
+			require.Empty(t, rl.Error)
+			require.Equal(t, int64(2), rl.Remaining)
+
+			rl = send(tc.to, 1)
+			if tc.err != "" {
+				assert.True(t, strings.HasPrefix(rl.Error, tc.err), rl.Error)
+				return
+			}
+			require.Empty(t, rl.Error)
+			assert.Equal(t, guber.Status_UNDER_LIMIT, rl.Status)
+			assert.Equal(t, tc.remaining, rl.Remaining)
+		})
 	}
 
-	var all model.Vector
-	for {
-		var smpls model.Vector
-		err := dec.Decode(&smpls)
-		if err == io.EOF {
-			break
+	t.Run("Invalid policy", func(t *testing.T) {
+		_, err := guber.NewV1Instance(guber.Config{
+			GRPCServers:           []*grpc.Server{grpc.NewServer()},
+			AlgorithmChangePolicy: "migrate",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestLongKeyPolicy(t *testing.T) {
+	longKey := strings.Repeat("k", 100)
+	send := func(t *testing.T, client guber.V1Client, name, key string) *guber.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses[0]
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{MaxKeyLength: 100, MaxNameLength: 20})
+		defer srv.Close()
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, send(t, client, "test_long_key", longKey).Error)
+		rl := send(t, client, "test_long_key", longKey+"k")
+		assert.True(t, strings.HasPrefix(rl.Error, guber.ErrKeyTooLong), rl.Error)
+		rl = send(t, client, "test_long_key_policy_name", "account:1")
+		assert.True(t, strings.HasPrefix(rl.Error, guber.ErrKeyTooLong), rl.Error)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{MaxKeyLength: 80, LongKeyPolicy: guber.LongKeyHash})
+		defer srv.Close()
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		// Each long key has its own rate limit
+		for i, key := range []string{longKey, longKey, longKey + "2"} {
+			rl := send(t, client, "test_long_key", key)
+			require.Empty(t, rl.Error)
+			assert.Equal(t, []int64{9, 8, 9}[i], rl.Remaining)
 		}
-		if err != nil {
-			return nil, err
+	})
+
+	t.Run("Invalid config", func(t *testing.T) {
+		for _, conf := range []guber.Config{
+			{LongKeyPolicy: "truncate"},
+			{LongKeyPolicy: guber.LongKeyHash, MaxKeyLength: 32},
+			{MaxKeyLength: -1},
+		} {
+			conf.GRPCServers = []*grpc.Server{grpc.NewServer()}
+			_, err := guber.NewV1Instance(conf)
+			assert.Error(t, err)
 		}
-		all = append(all, smpls...)
+	})
+}
+
+func TestGetTraffic(t *testing.T) {
+	srv1 := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+	defer srv1.Close()
+	srv2 := newV1Server(t, "localhost:0", guber.Config{AdminEnabled: true})
+	defer srv2.Close()
+	addr1, addr2 := srv1.listener.Addr().String(), srv2.listener.Addr().String()
+	srv1.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr1, IsOwner: true}, {GRPCAddress: addr2}})
+	srv2.srv.SetPeers([]guber.PeerInfo{{GRPCAddress: addr1}, {GRPCAddress: addr2, IsOwner: true}})
+
+	client, err := guber.DialV1Server(addr1, nil)
+	require.NoError(t, err)
+	conn, err := grpc.Dial(addr1, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := guber.NewAdminV1Client(conn)
+
+	// Some of the keys are owned by the second peer, as such are forwarded by the first
+	for i := 0; i < 20; i++ {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      "test_get_traffic",
+				UniqueKey: fmt.Sprintf("account:%d", i),
+				Algorithm: guber.Algorithm_TOKEN_BUCKET,
+				Behavior:  guber.Behavior_NO_BATCHING,
+				Duration:  guber.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
 	}
 
-	for _, s := range all {
-		if strings.Contains(s.Metric.String(), name) {
-			return s, nil
-		}
+	resp, err := admin.GetTraffic(context.Background(), &guber.GetTrafficReq{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Errors)
+	require.Len(t, resp.Traffic, 2)
+
+	traffic := make(map[string]*guber.PeerTraffic)
+	for _, tr := range resp.Traffic {
+		traffic[tr.From] = tr
 	}
-	return nil, nil
+	sent := traffic[addr1]
+	require.NotNil(t, sent)
+	assert.Equal(t, addr2, sent.To)
+	assert.NotZero(t, sent.Rpcs)
+	assert.Zero(t, sent.Errors)
+	assert.NotZero(t, sent.BytesSent)
+	assert.NotZero(t, sent.BytesReceived)
+	assert.Greater(t, sent.P99LatencyMs, 0.0)
+
+	require.NotNil(t, traffic[addr2])
+	assert.Equal(t, addr1, traffic[addr2].To)
 }
 
-// waitForBroadcast waits until the broadcast count for the daemon changes to
-// at least the expected value and the broadcast queue is empty.
-// Returns an error if timeout waiting for conditions to be met.
-func waitForBroadcast(timeout clock.Duration, d *guber.Daemon, expect float64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+func TestAuditSink(t *testing.T) {
+	sink := &mockAuditSink{}
+	srv := newV1Server(t, "localhost:0", guber.Config{
+		InstanceID:   "audit-instance",
+		AuditSink:    sink,
+		AdminEnabled: true,
+	})
+	defer srv.Close()
 
-	for {
-		metrics, err := getMetrics(d.Config().HTTPListenAddress,
-			"gubernator_broadcast_duration_count", "gubernator_global_queue_length")
-		if err != nil {
-			return err
+	client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	t.Run("Over limit", func(t *testing.T) {
+		for i, status := range []guber.Status{guber.Status_UNDER_LIMIT, guber.Status_OVER_LIMIT} {
+			resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+				Requests: []*guber.RateLimitReq{{
+					Name:      "test_audit_sink",
+					UniqueKey: "account:1234",
+					Algorithm: guber.Algorithm_TOKEN_BUCKET,
+					Duration:  guber.Minute,
+					Limit:     1,
+					Hits:      1,
+				}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, status, resp.Responses[0].Status, i)
 		}
-		gbdc := metrics["gubernator_broadcast_duration_count"]
-		ggql := metrics["gubernator_global_queue_length"]
 
-		// It's possible a broadcast occurred twice if waiting for multiple
-		// peers to forward updates to non-owners.
-		if float64(gbdc.Value) >= expect && ggql.Value == 0 {
-			return nil
+		records := sink.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, guber.AuditOverLimit, records[0].Type)
+		assert.Equal(t, "audit-instance", records[0].InstanceID)
+		assert.Equal(t, "test_audit_sink", records[0].Name)
+		assert.Equal(t, "account:1234", records[0].UniqueKey)
+		assert.Equal(t, int64(1), records[0].Hits)
+		assert.Equal(t, int64(1), records[0].Limit)
+		assert.False(t, records[0].DryRun)
+		assert.NotEmpty(t, records[0].RequestID)
+	})
+
+	t.Run("Reset rate limits", func(t *testing.T) {
+		_, err := srv.srv.ResetRateLimits(context.Background(), &guber.ResetRateLimitsReq{NamePrefix: "test_audit_"})
+		require.NoError(t, err)
+
+		records := sink.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, guber.AuditResetRateLimits, records[1].Type)
+		assert.Equal(t, "test_audit_", records[1].NamePrefix)
+		assert.Equal(t, int64(1), records[1].Removed)
+	})
+
+	t.Run("File sink", func(t *testing.T) {
+		path := t.TempDir() + "/audit.log"
+		fileSink, err := guber.NewFileAuditSink(path, logrus.StandardLogger())
+		require.NoError(t, err)
+		for _, r := range sink.Records() {
+			fileSink.Record(r)
 		}
+		require.NoError(t, fileSink.Close())
 
-		select {
-		case <-clock.After(100 * clock.Millisecond):
-		case <-ctx.Done():
-			return ctx.Err()
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], `"type":"over_limit"`)
+		assert.Contains(t, lines[1], `"type":"reset_rate_limits"`)
+	})
+
+	t.Run("Webhook sink", func(t *testing.T) {
+		received := make(chan string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			received <- string(b)
+		}))
+		defer server.Close()
+
+		webhookSink := guber.NewWebhookAuditSink(server.URL, logrus.StandardLogger())
+		webhookSink.Record(sink.Records()[0])
+		require.NoError(t, webhookSink.Close())
+
+		body := <-received
+		assert.True(t, strings.HasPrefix(body, "[{"))
+		assert.Contains(t, body, `"name":"test_audit_sink"`)
+	})
+}
+
+func TestCheckDurationMetric(t *testing.T) {
+	name := t.Name()
+	key := guber.RandomString(10)
+	owner, err := cluster.FindOwningDaemon(name, key)
+	require.NoError(t, err)
+	peers, err := cluster.ListNonOwningDaemons(name, key)
+	require.NoError(t, err)
+
+	const metric = `gubernator_check_duration_count{algorithm="leaky_bucket", calltype="%s", status="%s"}`
+	count := func(d *guber.Daemon, callType, status string) float64 {
+		m := fmt.Sprintf(metric, callType, status)
+		metrics, err := getMetrics(d.Config().HTTPListenAddress, m)
+		require.NoError(t, err)
+		if s, ok := metrics[m]; ok {
+			return float64(s.Value)
 		}
+		return 0
+	}
+	sendHit := func(client guber.V1Client, expect guber.Status) {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: guber.Algorithm_LEAKY_BUCKET,
+				Duration:  guber.Minute,
+				Hits:      1,
+				Limit:     1,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, expect, resp.Responses[0].Status)
 	}
+
+	localOver := count(owner, "local", "over_limit")
+	forwardUnder := count(peers[0], "forward", "under_limit")
+	forwardOver := count(peers[0], "forward", "over_limit")
+
+	sendHit(peers[0].MustClient(), guber.Status_UNDER_LIMIT)
+	sendHit(peers[0].MustClient(), guber.Status_OVER_LIMIT)
+	sendHit(owner.MustClient(), guber.Status_OVER_LIMIT)
+
+	assert.Equal(t, forwardUnder+1, count(peers[0], "forward", "under_limit"))
+	assert.Equal(t, forwardOver+1, count(peers[0], "forward", "over_limit"))
+	assert.Equal(t, localOver+1, count(owner, "local", "over_limit"))
 }
 
-// waitForUpdate waits until the global hits update count for the daemon
-// changes to at least the expected value and the global update queue is empty.
-// Returns an error if timeout waiting for conditions to be met.
-func waitForUpdate(timeout clock.Duration, d *guber.Daemon, expect float64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+func TestBehaviorCounter(t *testing.T) {
+	name := t.Name()
+	d := cluster.DaemonAt(0)
+	client := d.MustClient()
 
-	for {
-		metrics, err := getMetrics(d.Config().HTTPListenAddress,
-			"gubernator_global_send_duration_count", "gubernator_global_send_queue_length")
-		if err != nil {
-			return err
+	const metric = `gubernator_behavior_counter{behavior="%s", name="TestBehaviorCounter"}`
+	count := func(behavior string) float64 {
+		m := fmt.Sprintf(metric, behavior)
+		metrics, err := getMetrics(d.Config().HTTPListenAddress, m)
+		require.NoError(t, err)
+		if s, ok := metrics[m]; ok {
+			return float64(s.Value)
 		}
-		gsdc := metrics["gubernator_global_send_duration_count"]
-		gsql := metrics["gubernator_global_send_queue_length"]
+		return 0
+	}
 
-		// It's possible a hit occurred twice if waiting for multiple peers to
-		// forward updates to the owner.
-		if float64(gsdc.Value) >= expect && gsql.Value == 0 {
-			return nil
+	for _, b := range []guber.Behavior{
+		guber.Behavior_NO_BATCHING | guber.Behavior_DRY_RUN,
+		guber.Behavior_NO_BATCHING,
+		guber.Behavior_BATCHING,
+	} {
+		resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+			Requests: []*guber.RateLimitReq{{
+				Name:      name,
+				UniqueKey: guber.RandomString(10),
+				Duration:  guber.Minute,
+				Behavior:  b,
+				Hits:      1,
+				Limit:     10,
+			}},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Responses[0].Error)
+	}
+
+	assert.Equal(t, 2.0, count("NO_BATCHING"))
+	assert.Equal(t, 1.0, count("DRY_RUN"))
+	assert.Equal(t, 0.0, count("BATCHING"))
+	assert.Equal(t, 0.0, count("GLOBAL"))
+}
+
+func TestFailFast(t *testing.T) {
+	// Requests to peers take longer than the test is willing to wait
+	a := newV1Server(t, "localhost:0", guber.Config{Faults: &guber.FaultConfig{PeerLatency: clock.Second * 5}})
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", guber.Config{})
+	defer b.Close()
+	a.srv.SetPeers([]guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String(), IsOwner: true},
+		{GRPCAddress: b.listener.Addr().String()},
+	})
+
+	// Find a rate limit owned by `a` and one owned by `b`
+	name := "test_fail_fast"
+	var localKey, remoteKey string
+	for i := 0; localKey == "" || remoteKey == ""; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		peer, err := a.srv.GetPeer(context.Background(), name+"_"+key)
+		require.NoError(t, err)
+		if peer.Info().IsOwner {
+			localKey = key
+		} else {
+			remoteKey = key
 		}
+	}
 
-		select {
-		case <-clock.After(100 * clock.Millisecond):
-		case <-ctx.Done():
-			return ctx.Err()
+	client, err := guber.DialV1Server(a.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	newReq := func(key string) *guber.RateLimitReq {
+		return &guber.RateLimitReq{
+			Name:      name,
+			UniqueKey: key,
+			Behavior:  guber.Behavior_NO_BATCHING,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      1,
 		}
 	}
+
+	// The invalid request aborts the request in flight to `b` and the requests after it
+	start := clock.Now()
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		FailFast: true,
+		Requests: []*guber.RateLimitReq{newReq(remoteKey), newReq(""), newReq(localKey)},
+	})
+	require.NoError(t, err)
+	assert.Less(t, clock.Since(start), clock.Second*5)
+	require.Len(t, resp.Responses, 3)
+	assert.Contains(t, resp.Responses[0].Error, "fail_fast")
+	assert.Contains(t, resp.Responses[1].Error, "unique_key")
+	assert.Contains(t, resp.Responses[2].Error, "fail_fast")
+
+	// Without fail fast, the rate limits after the invalid request are applied
+	resp, err = client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{newReq(""), newReq(localKey)},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Responses[0].Error, "unique_key")
+	assert.Equal(t, "", resp.Responses[1].Error)
 }
 
-// waitForIdle waits until both global broadcast and global hits queues are
-// empty.
-func waitForIdle(timeout clock.Duration, daemons ...*guber.Daemon) error {
-	var wg syncutil.WaitGroup
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	for _, d := range daemons {
-		wg.Run(func(raw any) error {
-			d := raw.(*guber.Daemon)
-			for {
-				metrics, err := getMetrics(d.Config().HTTPListenAddress,
-					"gubernator_global_queue_length", "gubernator_global_send_queue_length")
-				if err != nil {
-					return err
-				}
-				ggql := metrics["gubernator_global_queue_length"]
-				gsql := metrics["gubernator_global_send_queue_length"]
+func TestClusterEvents(t *testing.T) {
+	srv := newV1Server(t, "localhost:0", guber.Config{})
+	defer srv.Close()
+	self := guber.PeerInfo{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}
+	other := guber.PeerInfo{GRPCAddress: "127.0.0.1:1"}
 
-				if ggql.Value == 0 && gsql.Value == 0 {
-					return nil
-				}
+	var events []guber.ClusterEvent
+	unregister := srv.srv.OnClusterEvent(func(ev guber.ClusterEvent) {
+		events = append(events, ev)
+	})
 
-				select {
-				case <-clock.After(100 * clock.Millisecond):
-				case <-ctx.Done():
-					return ctx.Err()
-				}
-			}
-		}, d)
-	}
-	errs := wg.Wait()
-	if len(errs) > 0 {
-		return errs[0]
+	// newV1Server() already added this instance
+	srv.srv.SetPeers([]guber.PeerInfo{self})
+	assert.Empty(t, events)
+
+	// Choose rate limits such that some are owned by the other peer
+	srv.srv.SetPeers([]guber.PeerInfo{self, other})
+	require.Len(t, events, 1)
+	assert.Equal(t, guber.PeerAdded, events[0].Type)
+	assert.Equal(t, other.GRPCAddress, events[0].Peer.GRPCAddress)
+	var reqs []*guber.RateLimitReq
+	var otherKeys []string
+	for i := 0; len(otherKeys) < 5 || len(reqs) < 10; i++ {
+		req := &guber.RateLimitReq{
+			Name:      "test_cluster_events",
+			UniqueKey: fmt.Sprintf("account:%d", i),
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      1,
+		}
+		peer, err := srv.srv.GetPeer(context.Background(), req.HashKey())
+		require.NoError(t, err)
+		if !peer.Info().IsOwner {
+			otherKeys = append(otherKeys, req.HashKey())
+		}
+		reqs = append(reqs, req)
 	}
-	return nil
-}
 
-func getMetricValue(t *testing.T, d *guber.Daemon, name string) float64 {
-	m, err := getMetricRequest(fmt.Sprintf("http://%s/metrics", d.Config().HTTPListenAddress),
-		name)
+	events = nil
+	srv.srv.SetPeers([]guber.PeerInfo{self})
+	require.Len(t, events, 1)
+	assert.Equal(t, guber.PeerRemoved, events[0].Type)
+	assert.Equal(t, other.GRPCAddress, events[0].Peer.GRPCAddress)
+
+	client, err := guber.DialV1Server(self.GRPCAddress, nil)
 	require.NoError(t, err)
-	if m == nil {
-		return 0
+	resp, err := client.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{Requests: reqs})
+	require.NoError(t, err)
+	for _, rl := range resp.Responses {
+		require.Equal(t, "", rl.Error)
 	}
-	return float64(m.Value)
+
+	// The other peer takes ownership of its rate limits in the cache
+	events = nil
+	srv.srv.SetPeers([]guber.PeerInfo{self, other})
+	require.Len(t, events, 2)
+	assert.Equal(t, guber.PeerAdded, events[0].Type)
+	assert.Equal(t, guber.LostOwnershipOf, events[1].Type)
+	assert.ElementsMatch(t, otherKeys, events[1].Keys)
+
+	// Once the other peer leaves, this instance owns the rate limits again
+	events = nil
+	srv.srv.SetPeers([]guber.PeerInfo{self})
+	require.Len(t, events, 2)
+	assert.Equal(t, guber.PeerRemoved, events[0].Type)
+	assert.Equal(t, guber.BecameOwnerOf, events[1].Type)
+	assert.ElementsMatch(t, otherKeys, events[1].Keys)
+
+	// No events once unregistered
+	unregister()
+	events = nil
+	srv.srv.SetPeers([]guber.PeerInfo{self, other})
+	assert.Empty(t, events)
 }
 
-// Get metric counter values on each peer.
-func getPeerCounters(t *testing.T, peers []*guber.Daemon, name string) map[string]float64 {
-	counters := make(map[string]float64)
-	for _, peer := range peers {
-		counters[peer.InstanceID] = getMetricValue(t, peer, name)
+func TestDefaultRequestTimeout(t *testing.T) {
+	a := newV1Server(t, "localhost:0", guber.Config{
+		DefaultRequestTimeout: 100 * time.Millisecond,
+		Faults:                &guber.FaultConfig{PeerLatency: 5 * time.Second},
+	})
+	defer a.Close()
+	b := newV1Server(t, "localhost:0", guber.Config{})
+	defer b.Close()
+	peers := []guber.PeerInfo{
+		{GRPCAddress: a.listener.Addr().String()},
+		{GRPCAddress: b.listener.Addr().String()},
+	}
+	for _, s := range []*v1Server{a, b} {
+		infos := make([]guber.PeerInfo, len(peers))
+		copy(infos, peers)
+		for i := range infos {
+			infos[i].IsOwner = infos[i].GRPCAddress == s.listener.Addr().String()
+		}
+		s.srv.SetPeers(infos)
 	}
-	return counters
-}
 
-func sendHit(t *testing.T, d *guber.Daemon, req *guber.RateLimitReq, expectStatus guber.Status, expectRemaining int64) {
-	if req.Hits != 0 {
-		t.Logf("Sending %d hits to peer %s", req.Hits, d.InstanceID)
+	// A rate limit owned by `b`, which `a` forwards to `b` without a response
+	var key string
+	for i := 0; key == ""; i++ {
+		peer, err := a.srv.GetPeer(context.Background(), fmt.Sprintf("test_default_request_timeout_%d", i))
+		require.NoError(t, err)
+		if !peer.Info().IsOwner {
+			key = fmt.Sprint(i)
+		}
 	}
-	client := d.MustClient()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
-	defer cancel()
-	resp, err := client.GetRateLimits(ctx, &guber.GetRateLimitsReq{
-		Requests: []*guber.RateLimitReq{req},
+
+	start := time.Now()
+	resp, err := a.srv.GetRateLimits(context.Background(), &guber.GetRateLimitsReq{
+		Requests: []*guber.RateLimitReq{{
+			Name:      "test_default_request_timeout",
+			UniqueKey: key,
+			Behavior:  guber.Behavior_NO_BATCHING,
+			Duration:  guber.Minute,
+			Limit:     10,
+			Hits:      1,
+		}},
 	})
 	require.NoError(t, err)
-	item := resp.Responses[0]
-	assert.Equal(t, "", item.Error)
-	if expectRemaining >= 0 {
-		assert.Equal(t, expectRemaining, item.Remaining)
-	}
-	assert.Equal(t, expectStatus, item.Status)
-	assert.Equal(t, req.Limit, item.Limit)
-}
-
-func epochMillis(t time.Time) int64 {
-	return t.UnixNano() / 1_000_000
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Contains(t, resp.Responses[0].Error, "deadline exceeded")
 }
 
-func startGubernator() error {
-	err := cluster.StartWith([]guber.PeerInfo{
-		{GRPCAddress: "127.0.0.1:9990", HTTPAddress: "127.0.0.1:9980", DataCenter: cluster.DataCenterNone},
-		{GRPCAddress: "127.0.0.1:9991", HTTPAddress: "127.0.0.1:9981", DataCenter: cluster.DataCenterNone},
-		{GRPCAddress: "127.0.0.1:9992", HTTPAddress: "127.0.0.1:9982", DataCenter: cluster.DataCenterNone},
-		{GRPCAddress: "127.0.0.1:9993", HTTPAddress: "127.0.0.1:9983", DataCenter: cluster.DataCenterNone},
-		{GRPCAddress: "127.0.0.1:9994", HTTPAddress: "127.0.0.1:9984", DataCenter: cluster.DataCenterNone},
-		{GRPCAddress: "127.0.0.1:9995", HTTPAddress: "127.0.0.1:9985", DataCenter: cluster.DataCenterNone},
+func TestFanOutWorkers(t *testing.T) {
+	a := newV1Server(t, "localhost:0", guber.Config{
+		Workers:         4,
+		FanOutWorkers:   2,
+		FanOutQueueSize: 1,
+	})
+	defer a.Close()
 
-		// DataCenterOne
-		{GRPCAddress: "127.0.0.1:9890", HTTPAddress: "127.0.0.1:9880", DataCenter: cluster.DataCenterOne},
-		{GRPCAddress: "127.0.0.1:9891", HTTPAddress: "127.0.0.1:9881", DataCenter: cluster.DataCenterOne},
-		{GRPCAddress: "127.0.0.1:9892", HTTPAddress: "127.0.0.1:9882", DataCenter: cluster.DataCenterOne},
-		{GRPCAddress: "127.0.0.1:9893", HTTPAddress: "127.0.0.1:9883", DataCenter: cluster.DataCenterOne},
+	peerClient, err := guber.NewPeerClient(guber.PeerConfig{
+		Info: guber.PeerInfo{GRPCAddress: a.listener.Addr().String()},
 	})
-	if err != nil {
-		return errors.Wrap(err, "while starting cluster")
-	}
+	require.NoError(t, err)
+	defer func() { _ = peerClient.Shutdown(context.Background()) }()
 
-	// Populate peer clients. Avoids data races when goroutines conflict trying
-	// to instantiate client singletons.
-	for _, peer := range cluster.GetDaemons() {
-		_, err = peer.Client()
-		if err != nil {
-			return errors.Wrap(err, "while connecting client")
-		}
+	createdAt := epochMillis(clock.Now())
+	req := &guber.GetPeerRateLimitsReq{}
+	for i := 0; i < 100; i++ {
+		req.Requests = append(req.Requests, &guber.RateLimitReq{
+			Name:      "test_fanout_workers",
+			UniqueKey: fmt.Sprintf("%d:account", i%10),
+			Duration:  guber.Minute,
+			Limit:     100,
+			Hits:      1,
+			RequestId: fmt.Sprint(i),
+			CreatedAt: &createdAt,
+		})
+	}
+	resp, err := peerClient.GetPeerRateLimits(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.RateLimits, 100)
+
+	// Responses are in the order of the requests, and every hit of each key was applied once
+	remaining := make(map[string][]int64)
+	for i, rl := range resp.RateLimits {
+		require.Empty(t, rl.Error)
+		assert.Equal(t, fmt.Sprint(i), rl.RequestId)
+		key := req.Requests[i].UniqueKey
+		remaining[key] = append(remaining[key], rl.Remaining)
+	}
+	for key, values := range remaining {
+		assert.ElementsMatch(t, []int64{99, 98, 97, 96, 95, 94, 93, 92, 91, 90}, values, key)
 	}
-	return nil
 }
@@ -0,0 +1,202 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// gregorianNames are the durations of a DURATION_IS_GREGORIAN rate limit in the JSON API
+var gregorianNames = map[string]int64{
+	"minutes": GregorianMinutes,
+	"hours":   GregorianHours,
+	"days":    GregorianDays,
+	"weeks":   GregorianWeeks,
+	"months":  GregorianMonths,
+	"years":   GregorianYears,
+}
+
+// gatewayMarshaler is the marshaler of the JSON gateway, which accepts human friendly durations and
+// limits in requests, IE: `"duration": "1m"` and `"limit": "10k"`, see humanizeJSON()
+type gatewayMarshaler struct {
+	*runtime.JSONPb
+}
+
+func (m gatewayMarshaler) Unmarshal(data []byte, v interface{}) error {
+	data, err := humanizeJSON(data)
+	if err != nil {
+		return err
+	}
+	return m.JSONPb.Unmarshal(data, v)
+}
+
+func (m gatewayMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
+	d := json.NewDecoder(r)
+	return runtime.DecoderFunc(func(v interface{}) error {
+		var raw json.RawMessage
+		if err := d.Decode(&raw); err != nil {
+			return err
+		}
+		return m.Unmarshal(raw, v)
+	})
+}
+
+// humanizeJSON replaces the durations and limits given as human friendly strings in the JSON
+// request `data` with the integers expected by the protobuf message. Strings of integers are
+// left as is, protojson accepts them for int64 fields.
+func humanizeJSON(data []byte) ([]byte, error) {
+	// Most requests only have integers, which need no conversion
+	if !bytes.Contains(data, []byte(`"duration"`)) && !bytes.Contains(data, []byte(`"limit"`)) &&
+		!bytes.Contains(data, []byte(`"burst"`)) {
+		return data, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		// Leave the error to protojson, which reports it in terms of the message
+		return data, nil
+	}
+	changed, err := humanizeValue(v)
+	if err != nil || !changed {
+		return data, err
+	}
+	return json.Marshal(v)
+}
+
+// humanizeValue converts the human friendly fields of every object within `v`
+func humanizeValue(v interface{}) (bool, error) {
+	var changed bool
+	switch v := v.(type) {
+	case []interface{}:
+		for _, e := range v {
+			c, err := humanizeValue(e)
+			if err != nil {
+				return false, err
+			}
+			changed = changed || c
+		}
+	case map[string]interface{}:
+		for key, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				c, err := humanizeValue(e)
+				if err != nil {
+					return false, err
+				}
+				changed = changed || c
+				continue
+			}
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				continue
+			}
+			var n int64
+			var err error
+			switch key {
+			case "duration", "rollout_duration":
+				n, err = parseHumanDuration(s, key == "duration" && isGregorian(v["behavior"]))
+			case "limit", "burst":
+				n, err = parseHumanLimit(s)
+			default:
+				continue
+			}
+			if err != nil {
+				return false, fmt.Errorf("field '%s': %w", key, err)
+			}
+			v[key] = json.Number(strconv.FormatInt(n, 10))
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// isGregorian returns true if the JSON `behavior` of a rate limit includes DURATION_IS_GREGORIAN
+func isGregorian(behavior interface{}) bool {
+	switch b := behavior.(type) {
+	case string:
+		if n, err := strconv.ParseInt(b, 10, 32); err == nil {
+			return Behavior(n)&Behavior_DURATION_IS_GREGORIAN != 0
+		}
+		return b == Behavior_DURATION_IS_GREGORIAN.String()
+	case json.Number:
+		n, err := b.Int64()
+		return err == nil && Behavior(n)&Behavior_DURATION_IS_GREGORIAN != 0
+	}
+	return false
+}
+
+// parseHumanDuration returns the milliseconds of the duration `s`, IE: "1m" or "1h30m", or the
+// interval of a gregorian rate limit, IE: "months".
+func parseHumanDuration(s string, gregorian bool) (int64, error) {
+	if g, ok := gregorianNames[strings.ToLower(s)]; ok {
+		if !gregorian {
+			return 0, fmt.Errorf("'%s' is only a duration of a DURATION_IS_GREGORIAN rate limit", s)
+		}
+		return g, nil
+	}
+	if gregorian {
+		return 0, fmt.Errorf("'%s' is ambiguous for a DURATION_IS_GREGORIAN rate limit; "+
+			"expected one of minutes, hours, days, weeks, months or years", s)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a duration, IE: '30s', '1m' or '24h'", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("'%s' cannot be negative", s)
+	}
+	if d%time.Millisecond != 0 {
+		return 0, fmt.Errorf("'%s' is not a whole number of milliseconds", s)
+	}
+	return d.Milliseconds(), nil
+}
+
+// parseHumanLimit returns the limit `s`, which is an integer optionally followed by 'k' for
+// thousands or 'M' for millions, IE: "10k". A lower case 'm' is rejected, as it could mean milli,
+// minutes or millions.
+func parseHumanLimit(s string) (int64, error) {
+	var multiplier int64 = 1
+	number := s
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		multiplier, number = 1_000, s[:len(s)-1]
+	case strings.HasSuffix(s, "M"):
+		multiplier, number = 1_000_000, s[:len(s)-1]
+	case strings.HasSuffix(s, "m"):
+		return 0, fmt.Errorf("'%s' is ambiguous; use 'M' for millions", s)
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a limit, IE: '100', '10k' or '1M'", s)
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("'%s' cannot be negative", s)
+	}
+	if n > MaxLimit/multiplier {
+		return 0, fmt.Errorf("'%s' is too large", s)
+	}
+	return n * multiplier, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanizeJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		out  string
+		err  string
+	}{
+		{
+			name: "Integers are unchanged",
+			in:   `{"requests":[{"limit":10,"duration":"60000"}]}`,
+			out:  `{"requests":[{"limit":10,"duration":"60000"}]}`,
+		},
+		{
+			name: "Durations and limits",
+			in:   `{"requests":[{"limit":"10k","burst":"2M","duration":"1h30m"}]}`,
+			out:  `{"requests":[{"burst":2000000,"duration":5400000,"limit":10000}]}`,
+		},
+		{
+			name: "Registered limit rollout",
+			in:   `{"limits":[{"limit":"1K","duration":"1s","rollout_duration":"24h"}]}`,
+			out:  `{"limits":[{"duration":1000,"limit":1000,"rollout_duration":86400000}]}`,
+		},
+		{
+			name: "Gregorian",
+			in:   `{"requests":[{"duration":"months","behavior":"DURATION_IS_GREGORIAN"}]}`,
+			out:  `{"requests":[{"behavior":"DURATION_IS_GREGORIAN","duration":4}]}`,
+		},
+		{
+			name: "Gregorian behavior as a number",
+			in:   `{"requests":[{"duration":"Days","behavior":5}]}`,
+			out:  `{"requests":[{"behavior":5,"duration":2}]}`,
+		},
+		{
+			name: "Gregorian duration without the behavior",
+			in:   `{"requests":[{"duration":"months"}]}`,
+			err:  "field 'duration': 'months' is only a duration of a DURATION_IS_GREGORIAN rate limit",
+		},
+		{
+			name: "Duration of a gregorian rate limit",
+			in:   `{"requests":[{"duration":"1h","behavior":"DURATION_IS_GREGORIAN"}]}`,
+			err:  "ambiguous for a DURATION_IS_GREGORIAN rate limit",
+		},
+		{
+			name: "Fraction of a millisecond",
+			in:   `{"requests":[{"duration":"1500us"}]}`,
+			err:  "field 'duration': '1500us' is not a whole number of milliseconds",
+		},
+		{
+			name: "Negative duration",
+			in:   `{"requests":[{"duration":"-1m"}]}`,
+			err:  "field 'duration': '-1m' cannot be negative",
+		},
+		{
+			name: "Lower case m limit",
+			in:   `{"requests":[{"limit":"1m"}]}`,
+			err:  "field 'limit': '1m' is ambiguous; use 'M' for millions",
+		},
+		{
+			name: "Limit too large",
+			in:   `{"requests":[{"limit":"9999999999999M"}]}`,
+			err:  "field 'limit': '9999999999999M' is too large",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := humanizeJSON([]byte(tt.in))
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.out, string(out))
+		})
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// The sources of the unique key found by a KeyExtractor
+const (
+	// KeyFromHeader uses the value of the header `KeyExtractor.Name`, IE: an API key
+	KeyFromHeader = "header"
+	// KeyFromCookie uses the value of the cookie `KeyExtractor.Name`, IE: a session id
+	KeyFromCookie = "cookie"
+	// KeyFromJWTClaim uses the claim `KeyExtractor.Name` of the bearer token in the Authorization
+	// header. The signature of the token is NOT verified, as such the token must be verified by a
+	// proxy in front of the gateway.
+	KeyFromJWTClaim = "jwt"
+	// KeyFromClientIP uses the IP address of the client, see DaemonConfig.HTTPTrustedProxies
+	KeyFromClientIP = "ip"
+)
+
+// KeyExtractor finds the unique key of a rate limit in the HTTP request made to the JSON gateway,
+// see DaemonConfig.HTTPKeyExtractors
+type KeyExtractor struct {
+	// One of KeyFromHeader, KeyFromCookie, KeyFromJWTClaim or KeyFromClientIP
+	Source string
+	// The name of the header, cookie or JWT claim. Unused by KeyFromClientIP
+	Name string
+}
+
+// ParseKeyExtractor parses a key extractor in the format used by `GUBER_HTTP_KEY_EXTRACTORS`,
+// IE: "header:X-Api-Key", "cookie:session", "jwt:sub" or "ip".
+func ParseKeyExtractor(s string) (KeyExtractor, error) {
+	source, name, _ := strings.Cut(strings.TrimSpace(s), ":")
+	e := KeyExtractor{Source: strings.ToLower(source), Name: strings.TrimSpace(name)}
+	switch e.Source {
+	case KeyFromHeader, KeyFromCookie, KeyFromJWTClaim:
+		if e.Name == "" {
+			return KeyExtractor{}, errors.Errorf("key extractor '%s' is invalid; expected '%s:<name>'", s, e.Source)
+		}
+	case KeyFromClientIP:
+		if e.Name != "" {
+			return KeyExtractor{}, errors.Errorf("key extractor '%s' is invalid; expected 'ip'", s)
+		}
+	default:
+		return KeyExtractor{}, errors.Errorf("key extractor '%s' is invalid; choices are "+
+			"['header:<name>', 'cookie:<name>', 'jwt:<claim>', 'ip']", s)
+	}
+	return e, nil
+}
+
+// ParseTrustedProxy parses an IP address or CIDR in the format used by `GUBER_HTTP_TRUSTED_PROXIES`,
+// IE: "10.0.0.0/8" or "192.168.1.10"
+func ParseTrustedProxy(s string) (*net.IPNet, error) {
+	s = strings.TrimSpace(s)
+	if _, network, err := net.ParseCIDR(s); err == nil {
+		return network, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.Errorf("trusted proxy '%s' is neither an IP address nor a CIDR", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}, nil
+}
+
+// keyExtractorHandler sets the empty unique keys of the rate limits requested from the JSON gateway
+// to the key found in the HTTP request by the first KeyExtractor which finds one, such that clients
+// need not construct keys, IE: `{"requests":[{"name":"requests_per_second", ...}]}`.
+type keyExtractorHandler struct {
+	extractors []KeyExtractor
+	trusted    []*net.IPNet
+	next       http.Handler
+}
+
+func newKeyExtractorHandler(extractors []KeyExtractor, trusted []*net.IPNet, next http.Handler) http.Handler {
+	return &keyExtractorHandler{extractors: extractors, trusted: trusted, next: next}
+}
+
+func (h *keyExtractorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, "while reading request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if key := h.key(r); key != "" {
+		body = setEmptyUniqueKeys(body, key)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	h.next.ServeHTTP(w, r)
+}
+
+// key returns the key found by the first extractor which finds one, or an empty string
+func (h *keyExtractorHandler) key(r *http.Request) string {
+	for _, e := range h.extractors {
+		var key string
+		switch e.Source {
+		case KeyFromHeader:
+			key = r.Header.Get(e.Name)
+		case KeyFromCookie:
+			if c, err := r.Cookie(e.Name); err == nil {
+				key = c.Value
+			}
+		case KeyFromJWTClaim:
+			key = jwtClaim(r, e.Name)
+		case KeyFromClientIP:
+			key = clientIP(r, h.trusted)
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			return key
+		}
+	}
+	return ""
+}
+
+// setEmptyUniqueKeys sets the unique key of each rate limit of the JSON GetRateLimitsReq `data`
+// which has none to `key`. Requests which are not valid JSON are left for the gateway to reject.
+func setEmptyUniqueKeys(data []byte, key string) []byte {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var req map[string]interface{}
+	if err := d.Decode(&req); err != nil {
+		return data
+	}
+	requests, _ := req["requests"].([]interface{})
+	var changed bool
+	for _, v := range requests {
+		rl, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// protojson accepts both the proto and the JSON name of the field
+		if k, _ := rl["unique_key"].(string); k != "" {
+			continue
+		}
+		if k, _ := rl["uniqueKey"].(string); k != "" {
+			continue
+		}
+		rl["unique_key"] = key
+		delete(rl, "uniqueKey")
+		changed = true
+	}
+	if !changed {
+		return data
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return data
+	}
+	return b
+}
+
+// jwtClaim returns the claim `name` of the bearer token in the Authorization header of `r`, or an
+// empty string if the token has no such claim or is not a JWT. The signature is not verified.
+func jwtClaim(r *http.Request, name string) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return ""
+	}
+	d := json.NewDecoder(bytes.NewReader(payload))
+	d.UseNumber()
+	var claims map[string]interface{}
+	if err := d.Decode(&claims); err != nil {
+		return ""
+	}
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+// clientIP returns the IP address of the client which made the request `r`. If the request was
+// made by one of the `trusted` proxies, the X-Forwarded-For header is walked from the last address,
+// which was added by the nearest proxy, to the first address which is not a trusted proxy. Addresses
+// before it were set by the client and cannot be trusted.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+	var forwarded []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(v, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(addr) == nil {
+			// The header is malformed, the proxy is the last address known to be genuine
+			return ip
+		}
+		ip = addr
+		if !isTrustedProxy(ip, trusted) {
+			return ip
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
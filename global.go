@@ -73,6 +73,12 @@ func newGlobalManager(conf BehaviorConfig, instance *V1Instance) *globalManager
 
 func (gm *globalManager) QueueHit(r *RateLimitReq) {
 	if r.Hits != 0 {
+		// The hits are aggregated before they are sent to the owner, as such the owner
+		// must not mistake them for a retry of the request with this idempotency key.
+		if r.IdempotencyKey != "" {
+			r = proto.Clone(r).(*RateLimitReq)
+			r.IdempotencyKey = ""
+		}
 		gm.hitsQueue <- r
 	}
 }
@@ -97,7 +103,7 @@ func (gm *globalManager) runAsyncHits() {
 		select {
 		case r := <-gm.hitsQueue:
 			// Aggregate the hits into a single request
-			key := r.HashKey()
+			key := gm.instance.conf.HashKey(r)
 			_, ok := hits[key]
 			if ok {
 				// If any of our hits includes a request to RESET_REMAINING
@@ -151,9 +157,10 @@ func (gm *globalManager) sendHits(hits map[string]*RateLimitReq) {
 
 	// Assign each request to a peer
 	for _, r := range hits {
-		peer, err := gm.instance.GetPeer(context.Background(), r.HashKey())
+		peer, err := gm.instance.GetPeer(context.Background(), gm.instance.conf.HashKey(r))
 		if err != nil {
-			gm.log.WithError(err).Errorf("while getting peer for hash key '%s'", r.HashKey())
+			gm.log.WithError(err).Errorf("while getting peer for hash key '%s'",
+				redactKey(gm.instance.conf.KeyRedaction, gm.instance.conf.HashKey(r)))
 			continue
 		}
 		p, ok := peerRequests[peer.Info().GRPCAddress]
@@ -197,7 +204,7 @@ func (gm *globalManager) runBroadcasts() {
 	gm.wg.Until(func(done chan struct{}) bool {
 		select {
 		case update := <-gm.broadcastQueue:
-			updates[update.HashKey()] = update
+			updates[gm.instance.conf.HashKey(update)] = update
 			gm.metricGlobalQueueLength.Set(float64(len(updates)))
 
 			// Send the hits if we reached our batch limit
@@ -248,7 +255,8 @@ func (gm *globalManager) broadcastPeers(ctx context.Context, updates map[string]
 			continue
 		}
 		updateReq := &UpdatePeerGlobal{
-			Key:       update.HashKey(),
+			Key:       gm.instance.conf.HashKey(update),
+			Name:      update.Name,
 			Algorithm: update.Algorithm,
 			Duration:  update.Duration,
 			Status:    status,
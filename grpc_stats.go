@@ -44,6 +44,11 @@ type GRPCStatsHandler struct {
 
 	grpcRequestCount    *prometheus.CounterVec
 	grpcRequestDuration *prometheus.SummaryVec
+
+	// The open connections and in-flight streams are only collected if true, see FlagGRPCMetrics
+	collectInternals  bool
+	grpcConnections   prometheus.Gauge
+	grpcActiveStreams *prometheus.GaugeVec
 }
 
 func NewGRPCStatsHandler() *GRPCStatsHandler {
@@ -60,6 +65,14 @@ func NewGRPCStatsHandler() *GRPCStatsHandler {
 				0.99: 0.001,
 			},
 		}, []string{"method"}),
+		grpcConnections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "gubernator_grpc_connections",
+			Help: "The number of open gRPC connections.",
+		}),
+		grpcActiveStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gubernator_grpc_active_streams",
+			Help: "The number of in-flight gRPC streams, IE: requests which have not completed.",
+		}, []string{"method"}),
 	}
 	c.run()
 	return c
@@ -84,11 +97,19 @@ func (c *GRPCStatsHandler) run() {
 func (c *GRPCStatsHandler) Describe(ch chan<- *prometheus.Desc) {
 	c.grpcRequestCount.Describe(ch)
 	c.grpcRequestDuration.Describe(ch)
+	if c.collectInternals {
+		c.grpcConnections.Describe(ch)
+		c.grpcActiveStreams.Describe(ch)
+	}
 }
 
 func (c *GRPCStatsHandler) Collect(ch chan<- prometheus.Metric) {
 	c.grpcRequestCount.Collect(ch)
 	c.grpcRequestDuration.Collect(ch)
+	if c.collectInternals {
+		c.grpcConnections.Collect(ch)
+		c.grpcActiveStreams.Collect(ch)
+	}
 }
 
 func (c *GRPCStatsHandler) Close() {
@@ -102,7 +123,8 @@ func (c *GRPCStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
 	}
 
 	switch t := s.(type) {
-	// case *stats.Begin:
+	case *stats.Begin:
+		c.grpcActiveStreams.WithLabelValues(rs.Method).Inc()
 	// case *stats.InPayload:
 	// case *stats.InHeader:
 	// case *stats.InTrailer:
@@ -110,6 +132,7 @@ func (c *GRPCStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
 	// case *stats.OutHeader:
 	// case *stats.OutTrailer:
 	case *stats.End:
+		c.grpcActiveStreams.WithLabelValues(rs.Method).Dec()
 		rs.Duration = t.EndTime.Sub(t.BeginTime)
 		if t.Error != nil {
 			rs.Failed = 1
@@ -120,7 +143,14 @@ func (c *GRPCStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
 	}
 }
 
-func (c *GRPCStatsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {}
+func (c *GRPCStatsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		c.grpcConnections.Inc()
+	case *stats.ConnEnd:
+		c.grpcConnections.Dec()
+	}
+}
 
 func (c *GRPCStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
 	return ctx
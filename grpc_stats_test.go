@@ -0,0 +1,48 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/stats"
+)
+
+func TestGRPCStatsInternals(t *testing.T) {
+	h := NewGRPCStatsHandler()
+	defer h.Close()
+	const method = "/pb.gubernator.V1/GetRateLimits"
+
+	h.HandleConn(context.Background(), &stats.ConnBegin{})
+	h.HandleConn(context.Background(), &stats.ConnBegin{})
+	h.HandleConn(context.Background(), &stats.ConnEnd{})
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.grpcConnections))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: method})
+	h.HandleRPC(ctx, &stats.Begin{})
+	assert.Equal(t, 1.0, testutil.ToFloat64(h.grpcActiveStreams.WithLabelValues(method)))
+	h.HandleRPC(ctx, &stats.End{})
+	assert.Equal(t, 0.0, testutil.ToFloat64(h.grpcActiveStreams.WithLabelValues(method)))
+
+	// The internals are only collected when enabled by FlagGRPCMetrics
+	assert.Equal(t, 0, testutil.CollectAndCount(h, "gubernator_grpc_connections", "gubernator_grpc_active_streams"))
+	h.collectInternals = true
+	assert.Equal(t, 2, testutil.CollectAndCount(h, "gubernator_grpc_connections", "gubernator_grpc_active_streams"))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package grpclimit provides a grpc.UnaryServerInterceptor which rate limits the inbound RPCs of
+// each method and caller with gubernator, either by calling a gubernator cluster or an instance
+// embedded in the service.
+//
+//	client, _ := gubernator.DialV1Server("gubernator:1051", nil)
+//	limit := grpclimit.UnaryServerInterceptor(grpclimit.Config{
+//		Limiter: grpclimit.FromClient(client),
+//		Methods: map[string]*gubernator.RateLimitReq{
+//			"/shop.Orders/Create": {Name: "orders_create", Limit: 10, Duration: gubernator.Second},
+//		},
+//		Default: &gubernator.RateLimitReq{Name: "rpcs", Limit: 1000, Duration: gubernator.Second},
+//	})
+//	server := grpc.NewServer(grpc.UnaryInterceptor(limit))
+package grpclimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	gubernator "github.com/gubernator-io/gubernator/v2/proto"
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Limiter evaluates rate limits. An embedded *gubernator.V1Instance is a Limiter, use FromClient()
+// to evaluate rate limits with a gubernator.V1Client.
+type Limiter interface {
+	GetRateLimits(context.Context, *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error)
+}
+
+type clientLimiter struct {
+	client gubernator.V1Client
+	opts   []grpc.CallOption
+}
+
+func (c clientLimiter) GetRateLimits(ctx context.Context, r *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error) {
+	return c.client.GetRateLimits(ctx, r, c.opts...)
+}
+
+// FromClient returns a Limiter which evaluates rate limits with `client`
+func FromClient(client gubernator.V1Client, opts ...grpc.CallOption) Limiter {
+	return clientLimiter{client: client, opts: opts}
+}
+
+// KeyFunc returns the identity of the caller of an RPC, which together with the method is the
+// unique key of the rate limit the RPC is counted against. RPCs for which the KeyFunc returns an
+// empty identity are not rate limited.
+type KeyFunc func(ctx context.Context) string
+
+// ByPeer is a KeyFunc which identifies the caller by the common name of its verified TLS client
+// certificate, or by the IP address of the connection when the caller did not present one.
+func ByPeer(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		if chains := tlsInfo.State.VerifiedChains; len(chains) != 0 && len(chains[0]) != 0 {
+			if cn := chains[0][0].Subject.CommonName; cn != "" {
+				return cn
+			}
+		}
+	}
+	if p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// ByMetadata returns a KeyFunc which identifies the caller by the value of the request metadata
+// `key`, IE: an API key
+func ByMetadata(key string) KeyFunc {
+	return func(ctx context.Context) string {
+		if values := metadata.ValueFromIncomingContext(ctx, key); len(values) != 0 {
+			return values[0]
+		}
+		return ""
+	}
+}
+
+// Config configures the interceptor returned by UnaryServerInterceptor()
+type Config struct {
+	// (Required) Evaluates the rate limits, see FromClient()
+	Limiter Limiter
+
+	// (Optional) The rate limit the RPCs of each method are counted against, keyed by the full
+	// method name IE: "/package.Service/Method". The UniqueKey is set to the method and the identity
+	// of the caller, and Hits defaults to 1.
+	Methods map[string]*gubernator.RateLimitReq
+
+	// (Optional) The rate limit the RPCs of the methods which are not in `Methods` are counted
+	// against. Defaults to nil, such that those methods are not rate limited
+	Default *gubernator.RateLimitReq
+
+	// (Optional) Returns the identity of the caller of each RPC. Defaults to ByPeer
+	Key KeyFunc
+
+	// (Optional) If true, RPCs whose rate limit could not be evaluated, IE: gubernator is
+	// unavailable, fail with codes.Unavailable. Defaults to false (such RPCs are allowed)
+	FailClosed bool
+}
+
+// UnaryServerInterceptor returns an interceptor which counts each RPC against the rate limit of
+// its method and caller described by `conf`. RPCs over the limit fail with
+// codes.ResourceExhausted; the status includes the time until the rate limit resets as
+// errdetails.RetryInfo, which is also sent as the `retry-after` response header in seconds.
+func UnaryServerInterceptor(conf Config) grpc.UnaryServerInterceptor {
+	if conf.Key == nil {
+		conf.Key = ByPeer
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		policy, ok := conf.Methods[info.FullMethod]
+		if !ok {
+			policy = conf.Default
+		}
+		if policy == nil {
+			return handler(ctx, req)
+		}
+		key := conf.Key(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		r := proto.Clone(policy).(*gubernator.RateLimitReq)
+		r.UniqueKey = info.FullMethod + "_" + key
+		if r.Hits == 0 {
+			r.Hits = 1
+		}
+		resp, err := conf.Limiter.GetRateLimits(ctx, &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{r},
+		})
+		if err == nil && len(resp.Responses) == 1 && resp.Responses[0].Error != "" {
+			err = fmt.Errorf("while evaluating rate limit: %s", resp.Responses[0].Error)
+		}
+		if err != nil {
+			if conf.FailClosed {
+				return nil, status.Errorf(codes.Unavailable, "rate limit unavailable: %s", err)
+			}
+			return handler(ctx, req)
+		}
+
+		rl := resp.Responses[0]
+		if rl.Status != gubernator.Status_OVER_LIMIT {
+			return handler(ctx, req)
+		}
+		retry := retryAfter(rl.ResetTime)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after",
+			strconv.FormatInt(int64((retry+time.Second-1)/time.Second), 10)))
+		s, err := status.New(codes.ResourceExhausted, "rate limit exceeded").
+			WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retry)})
+		if err != nil {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return nil, s.Err()
+	}
+}
+
+// retryAfter returns the duration until `resetTime` in unix epoch milliseconds, which is at least
+// 1 millisecond.
+func retryAfter(resetTime int64) time.Duration {
+	ms := resetTime - clock.Now().UnixMilli()
+	if ms < 1 {
+		ms = 1
+	}
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpclimit_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gubernator-io/gubernator/v2"
+	"github.com/gubernator-io/gubernator/v2/grpclimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeLimiter allows `limit` hits of each rate limit, which reset 1.5 seconds from now
+type fakeLimiter struct {
+	mutex sync.Mutex
+	limit int64
+	hits  map[string]int64
+	err   error
+}
+
+func (f *fakeLimiter) GetRateLimits(_ context.Context, r *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	req := r.Requests[0]
+	f.hits[req.Name+"/"+req.UniqueKey] += req.Hits
+	rl := &gubernator.RateLimitResp{
+		Limit:     f.limit,
+		Remaining: f.limit - f.hits[req.Name+"/"+req.UniqueKey],
+		ResetTime: time.Now().Add(1500 * time.Millisecond).UnixMilli(),
+	}
+	if rl.Remaining < 0 {
+		rl.Status = gubernator.Status_OVER_LIMIT
+		rl.Remaining = 0
+	}
+	return &gubernator.GetRateLimitsResp{Responses: []*gubernator.RateLimitResp{rl}}, nil
+}
+
+// serve starts a health server which rate limits RPCs with `conf`
+func serve(t *testing.T, conf grpclimit.Config) grpc_health_v1.HealthClient {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpclimit.UnaryServerInterceptor(conf)))
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	const check = "/grpc.health.v1.Health/Check"
+	ctx := context.Background()
+
+	t.Run("Over the limit", func(t *testing.T) {
+		limiter := &fakeLimiter{limit: 1, hits: map[string]int64{}}
+		client := serve(t, grpclimit.Config{
+			Limiter: limiter,
+			Methods: map[string]*gubernator.RateLimitReq{
+				check: {Name: "health_check", Limit: 1, Duration: gubernator.Second},
+			},
+		})
+
+		_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		var header metadata.MD
+		_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.Header(&header))
+		s := status.Convert(err)
+		require.Equal(t, codes.ResourceExhausted, s.Code())
+		assert.Equal(t, []string{"2"}, header.Get("retry-after"))
+		require.Len(t, s.Details(), 1)
+		retry := s.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration()
+		assert.True(t, retry > time.Second && retry <= 1500*time.Millisecond, retry)
+
+		// The rate limit is keyed by the method and the IP address of the caller
+		assert.Equal(t, map[string]int64{"health_check/" + check + "_127.0.0.1": 2}, limiter.hits)
+	})
+
+	t.Run("Default policy", func(t *testing.T) {
+		limiter := &fakeLimiter{limit: 0, hits: map[string]int64{}}
+		client := serve(t, grpclimit.Config{Limiter: limiter})
+		_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, limiter.hits)
+
+		client = serve(t, grpclimit.Config{
+			Limiter: limiter,
+			Default: &gubernator.RateLimitReq{Name: "rpcs", Limit: 0, Duration: gubernator.Second},
+		})
+		_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("Metadata key", func(t *testing.T) {
+		limiter := &fakeLimiter{limit: 1, hits: map[string]int64{}}
+		client := serve(t, grpclimit.Config{
+			Limiter: limiter,
+			Default: &gubernator.RateLimitReq{Name: "rpcs", Limit: 1, Duration: gubernator.Second},
+			Key:     grpclimit.ByMetadata("x-api-key"),
+		})
+		for _, key := range []string{"account:1", "account:2"} {
+			_, err := client.Check(metadata.AppendToOutgoingContext(ctx, "x-api-key", key), &grpc_health_v1.HealthCheckRequest{})
+			require.NoError(t, err)
+		}
+		// Callers without a key are not limited
+		for i := 0; i < 3; i++ {
+			_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+			require.NoError(t, err)
+		}
+		assert.Len(t, limiter.hits, 2)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		conf := grpclimit.Config{
+			Limiter: &fakeLimiter{err: errors.New("connection refused")},
+			Default: &gubernator.RateLimitReq{Name: "rpcs", Limit: 1, Duration: gubernator.Second},
+		}
+		_, err := serve(t, conf).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		conf.FailClosed = true
+		_, err = serve(t, conf).Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+	// The flag which identifies the trailer frame in a gRPC-Web response body.
+	grpcWebTrailerFlag = 0x80
+)
+
+// grpcWebHandler serves gRPC-Web requests (https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-WEB.md)
+// by translating them into GRPC requests handled by grpc.Server.ServeHTTP(). Only unary methods
+// are supported, which is all gubernator provides.
+type grpcWebHandler struct {
+	srv            *grpc.Server
+	next           http.Handler
+	services       map[string]struct{}
+	allowedOrigins []string
+}
+
+// newGRPCWebHandler returns a handler which serves gRPC-Web requests using the provided GRPC server
+// and hands all other requests to `next`. Cross-origin requests are only allowed from `allowedOrigins`,
+// the origin '*' allows requests from any origin.
+func newGRPCWebHandler(srv *grpc.Server, allowedOrigins []string, next http.Handler) http.Handler {
+	h := &grpcWebHandler{
+		srv:            srv,
+		next:           next,
+		services:       make(map[string]struct{}),
+		allowedOrigins: allowedOrigins,
+	}
+	for name := range srv.GetServiceInfo() {
+		h.services[name] = struct{}{}
+	}
+	return h
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebContentType):
+		h.serveGRPCWeb(w, r)
+	case r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" && h.isGRPCMethod(r.URL.Path):
+		h.servePreflight(w, r)
+	default:
+		h.next.ServeHTTP(w, r)
+	}
+}
+
+// isGRPCMethod returns true if the path is in the form `/<service>/<method>` of a registered service
+func (h *grpcWebHandler) isGRPCMethod(path string) bool {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 {
+		return false
+	}
+	_, ok := h.services[parts[0]]
+	return ok
+}
+
+// allowOrigin sets the CORS headers if the origin of the request is allowed. Returns false if the
+// request is cross-origin and the origin is not allowed.
+func (h *grpcWebHandler) allowOrigin(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	allowed := false
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		allowed = true
+	}
+	for _, o := range h.allowedOrigins {
+		if o == "*" || o == origin {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Credentials", "true")
+	w.Header().Add("Vary", "Origin")
+	return true
+}
+
+func (h *grpcWebHandler) servePreflight(w http.ResponseWriter, r *http.Request) {
+	if !h.allowOrigin(w, r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+	w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *grpcWebHandler) serveGRPCWeb(w http.ResponseWriter, r *http.Request) {
+	if !h.allowOrigin(w, r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	w.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message, grpc-status-details-bin")
+
+	contentType := r.Header.Get("Content-Type")
+	isText := strings.HasPrefix(contentType, grpcWebTextContentType)
+
+	// Make the request look like a GRPC request over HTTP/2
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+	if isText {
+		req.Header.Set("Content-Type", "application/grpc"+strings.TrimPrefix(contentType, grpcWebTextContentType))
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	} else {
+		req.Header.Set("Content-Type", "application/grpc"+strings.TrimPrefix(contentType, grpcWebContentType))
+	}
+
+	resp := &grpcWebResponse{
+		w:      w,
+		header: make(http.Header),
+		isText: isText,
+	}
+	h.srv.ServeHTTP(resp, req)
+	resp.writeTrailers()
+}
+
+// grpcWebResponse receives the GRPC response from grpc.Server.ServeHTTP() and writes the
+// response and the trailers in the gRPC-Web format.
+type grpcWebResponse struct {
+	w           http.ResponseWriter
+	header      http.Header
+	isText      bool
+	wroteHeader bool
+	// Holds the body of a text response until it is base64 encoded by writeTrailers()
+	text bytes.Buffer
+}
+
+func (r *grpcWebResponse) Header() http.Header {
+	return r.header
+}
+
+func (r *grpcWebResponse) WriteHeader(code int) {
+	if r.wroteHeader {
+		return
+	}
+	r.wroteHeader = true
+
+	trailers := r.trailerKeys()
+	for k, v := range r.header {
+		if k == "Trailer" || k == "Content-Type" || trailers[k] || strings.HasPrefix(k, http.TrailerPrefix) {
+			continue
+		}
+		r.w.Header()[k] = v
+	}
+	if r.isText {
+		r.w.Header().Set("Content-Type", grpcWebTextContentType+"+proto")
+	} else {
+		r.w.Header().Set("Content-Type", grpcWebContentType+"+proto")
+	}
+	r.w.WriteHeader(code)
+}
+
+func (r *grpcWebResponse) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if r.isText {
+		return r.text.Write(b)
+	}
+	return r.w.Write(b)
+}
+
+func (r *grpcWebResponse) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if f, ok := r.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// trailerKeys returns the header keys which were pre-declared as trailers
+func (r *grpcWebResponse) trailerKeys() map[string]bool {
+	keys := make(map[string]bool)
+	for _, v := range r.header["Trailer"] {
+		for _, k := range strings.Split(v, ",") {
+			keys[http.CanonicalHeaderKey(strings.TrimSpace(k))] = true
+		}
+	}
+	return keys
+}
+
+// writeTrailers writes the GRPC trailers as the final frame of the response body
+func (r *grpcWebResponse) writeTrailers() {
+	var buf bytes.Buffer
+	trailers := r.trailerKeys()
+	for k, vv := range r.header {
+		name := strings.TrimPrefix(k, http.TrailerPrefix)
+		if !trailers[k] && name == k {
+			continue
+		}
+		for _, v := range vv {
+			buf.WriteString(strings.ToLower(name) + ": " + v + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+buf.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(buf.Len()))
+	_, _ = r.Write(append(frame, buf.Bytes()...))
+
+	if r.isText {
+		_, _ = r.w.Write([]byte(base64.StdEncoding.EncodeToString(r.text.Bytes())))
+	}
+	r.Flush()
+}
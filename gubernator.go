@@ -18,9 +18,13 @@ package gubernator
 
 import (
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mailgun/errors"
@@ -36,28 +40,135 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// errFailFast is the cause of the cancellation when `GetRateLimitsReq.fail_fast` aborts a batch
+var errFailFast = errors.New("aborted by 'fail_fast'; another rate limit in the batch returned an error")
+
 const (
-	maxBatchSize = 1000
-	Healthy      = "healthy"
-	UnHealthy    = "unhealthy"
+	Healthy   = "healthy"
+	UnHealthy = "unhealthy"
+
+	// MetadataNotOwner is set to "true" in the metadata of a RateLimitResp returned by
+	// GetPeerRateLimits when `BehaviorConfig.VerifyPeerOwnership` is enabled and the peer
+	// does not own the rate limit. The "owner" metadata holds the owner according to that peer.
+	MetadataNotOwner = "not_owner"
 )
 
 type V1Instance struct {
 	UnimplementedV1Server
 	UnimplementedPeersV1Server
-	global     *globalManager
-	peerMutex  sync.RWMutex
+	global *globalManager
+	// The current pickers, replaced as a whole by SetPeers() and UpdatePeers() such that
+	// requests never wait on a change of peers
+	pickers atomic.Pointer[peerPickers]
+	// Serializes SetPeers() and UpdatePeers()
+	peerUpdateMutex sync.Mutex
+	// The canonical addresses this instance is known by in the peer list. GUARDED_BY(peerUpdateMutex)
+	selfAddresses map[string]bool
+	// Config.NodeID or a random ID, sent with the rate limits this instance forwards, see metadataNodeID
+	nodeID     string
 	log        FieldLogger
 	conf       Config
 	isClosed   bool
 	workerPool *WorkerPool
+	// Is nil unless Config.FanOutWorkers is set
+	fanOut *fanOutPool
+	// Set if Config.Store implements BatchStore
+	storeBatcher *storeBatcher
+	// Behaviors forced on rate limit names by `BehaviorConfig`, IE: `DryRunNames`
+	nameBehaviors map[string]Behavior
+	// Is true once SetPeers() was called with at least readyMinPeers() peers
+	ready atomic.Bool
+	// The number of peers peer discovery expects in the cluster, see SetClusterSize()
+	clusterSize atomic.Int64
+	// Is true if this instance is a forwarder which never owns rate limits, see `PeerInfo.Forwarder`
+	forwarder atomic.Bool
+	// Is nil unless `Config.UsageWindow` is set
+	usage     *usageTracker
+	usageDone chan struct{}
+	// Is nil unless `Config.KeyHistorySize` is set
+	history *keyHistory
+	// The encoding of the configuration covered by configChecksum(), see newConfigChecksum()
+	checksumConfig []byte
+	// Closed to stop runConfigSkewCheck()
+	configSkewDone chan struct{}
+	// Is nil unless `Config.MaxCostCenters` is set
+	costCenters *costCenters
+	// The most recent access of each rate limit name, see AdminV1.ListNamespaces
+	namespaces *namespaceTracker
+	// `Config.NamespacePolicies` merged with the policies of `Config.PolicyFile`
+	policies atomic.Pointer[policySet]
+	// Is nil unless `Config.PolicyFile` is set
+	policyWatcher *policyWatcher
+	// `Config.Templates` indexed by name
+	templates map[string]*RateLimitTemplate
+	// Is nil unless `BehaviorConfig.DegradedErrorPercent` is set
+	degraded *degradedTracker
+	// Is closed to stop the periodic snapshots, see `Config.SnapshotInterval`
+	snapshotDone chan struct{}
+	// A copy of the overrides set via AdminV1.SetOverride
+	overrides        *overrideTable
+	overridesSynced  atomic.Bool
+	overridesSyncing atomic.Bool
+	// A copy of the limits registered via RegisterLimits
+	registeredLimits *limitRegistry
+	limitsSynced     atomic.Bool
+	limitsSyncing    atomic.Bool
+	// Decides each rate limit received by GetRateLimits, see newPipeline()
+	pipeline RateLimitHandler
+	// The callbacks registered with OnClusterEvent()
+	events clusterEvents
+	// The rate limits whose definition changed between requests, see GetLimitDrift()
+	drift *driftTracker
+	// Is nil unless `Config.OverLimitAlerts` is set
+	alerts *alertTracker
+	// Is nil unless `Config.Federation.HomeAddress` is set
+	federation *federation
+	// Is nil unless `Config.Shadow.Address` is set
+	shadow *shadow
+	// Is nil unless `Config.Standby.Names` is set
+	standby *standby
+	// Is nil unless `Config.JournalDir` is set
+	journal *hitJournal
+	// Is nil unless `Config.ColdKeyFilterInterval` is set
+	coldKeys *coldKeys
 }
 
 type RateLimitReqState struct {
 	IsOwner bool
+	// Records changes to the definition of rate limits owned by this instance, see GetLimitDrift()
+	drift *driftTracker
+	// Is true while a query only request is evaluated against a copy of the rate limit, which skips
+	// the timings whose summaries would serialize the readers, see readRateLimit()
+	readCopy bool
+	// The HashKey of the request, set by WorkerPool.GetRateLimit such that it is computed once
+	hashKey string
+}
+
+// keyOf returns the HashKey of the request, computing it unless WorkerPool.GetRateLimit already did
+func (s RateLimitReqState) keyOf(conf *Config, r *RateLimitReq) string {
+	if s.hashKey != "" {
+		return s.hashKey
+	}
+	return conf.HashKey(r)
 }
 
 var (
+	metricLongKeyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_long_key_counter",
+		Help: "The count of rate limits whose name or unique key exceeded the max length.  Label \"action\" may be \"hashed\" or \"rejected\".",
+	}, []string{"action"})
+	metricAlgorithmChangeCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_algorithm_change_counter",
+		Help: "The count of rate limits requested with a different algorithm than they were created with.  Label \"action\" may be \"reset\", \"translated\" or \"rejected\".",
+	}, []string{"action"})
+	metricBehaviorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_behavior_counter",
+		Help: "The count of rate limits requested by clients with each behavior flag set.  Label \"name\" is the rate limit name and label \"behavior\" is the flag, IE: \"GLOBAL\".",
+	}, []string{"name", "behavior"})
+	metricFederationCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_federation_counter",
+		Help: "The count of rate limits proxied to the home cluster.  Label \"result\" may be \"proxied\" or \"error\".",
+	}, []string{"result"})
 	metricGetRateLimitCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "gubernator_getratelimit_counter",
 		Help: "The count of getLocalRateLimit() calls.  Label \"calltype\" may be \"local\" for calls handled by the same peer, or \"global\" for global rate limits.",
@@ -71,6 +182,14 @@ var (
 			0.5:  0.01,
 		},
 	}, []string{"name"})
+	metricLeaseCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_lease_counter",
+		Help: "The count of lease operations.  Label \"result\" may be \"acquired\", \"renewed\", \"denied\", \"released\" or \"expired\".",
+	}, []string{"result"})
+	metricOverrideCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_override_counter",
+		Help: "The count of rate limit checks decided by an override.  Label \"action\" may be \"deny\", \"allow\" or \"pause\".",
+	}, []string{"action"})
 	metricOverLimitCounter = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "gubernator_over_limit_counter",
 		Help: "The number of rate limit checks that are over the limit.",
@@ -79,6 +198,47 @@ var (
 		Name: "gubernator_concurrent_checks_counter",
 		Help: "The number of concurrent GetRateLimits API calls.",
 	})
+	metricHitStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gubernator_hit_streams",
+		Help: "The number of open StreamHits calls.",
+	})
+	metricGroupCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_ratelimit_group_counter",
+		Help: "The count of GetRateLimitGroup() calls.  Label \"result\" may be \"applied\" when the hits were applied, or \"over_limit\" when no hits were applied.",
+	}, []string{"result"})
+	metricDryRunCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_dry_run_over_limit_counter",
+		Help: "The number of DRY_RUN rate limit checks that would have been over the limit.",
+	}, []string{"name"})
+	metricCheckDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gubernator_check_duration",
+		Help:    "The timings of rate limit checks in seconds.  Label \"calltype\" may be \"local\", \"forward\", \"global\" or \"cold\" and label \"status\" may be \"under_limit\", \"over_limit\" or \"error\".",
+		Buckets: []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"algorithm", "calltype", "status"})
+	metricLimitDrift = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_limit_drift_counter",
+		Help: "The count of requests whose limit, duration, algorithm or burst differ from the previous request for the same rate limit.",
+	}, []string{"name"})
+	metricCalloutCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_decision_callout_counter",
+		Help: "The count of decision callouts.  Label \"result\" may be \"unchanged\", \"overridden\" or \"error\" when the callout failed or timed out.",
+	}, []string{"result"})
+	metricStrictCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_strict_fail_closed_counter",
+		Help: "The number of strict rate limits which returned OVER_LIMIT because the owning peer could not be reached.",
+	}, []string{"name"})
+	metricDegradedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_degraded_counter",
+		Help: "The number of rate limits evaluated locally with a reduced limit because the peer error rate exceeded the degraded mode threshold.",
+	})
+	metricSlowRequestCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_slow_request_counter",
+		Help: "The count of requests which took longer than the slow request threshold.  Label \"type\" may be \"GetRateLimits\" or \"GetPeerRateLimits\".",
+	}, []string{"type"})
+	metricSlowPeerCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_slow_peer_counter",
+		Help: "The number of rate limits evaluated by the next peer on the hash ring because the p99 latency of the owning peer exceeded the threshold.  Label \"peer\" is the owning peer.",
+	}, []string{"peer"})
 	metricCheckErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "gubernator_check_error_counter",
 		Help: "The number of errors while checking rate limits.",
@@ -87,6 +247,115 @@ var (
 		Name: "gubernator_command_counter",
 		Help: "The count of commands processed by each worker in WorkerPool.",
 	}, []string{"worker", "method"})
+	metricAuditDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_audit_dropped_counter",
+		Help: "The number of audit records dropped because the AuditSink buffer was full or the write failed.",
+	})
+	metricJournalDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_journal_dropped_counter",
+		Help: "The number of hits not recorded in the journal because the buffer was full or the write failed.",
+	})
+	metricCostCenterHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_cost_center_hits_counter",
+		Help: "The hits of the rate limits owned by this instance, by the cost_center metadata of the request.",
+	}, []string{"cost_center"})
+	metricCostCenterSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_cost_center_seconds_counter",
+		Help: "The seconds spent evaluating the rate limits owned by this instance, by the cost_center metadata of the request.",
+	}, []string{"cost_center"})
+	metricPolicyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_namespace_policy_counter",
+		Help: "The count of requests which exceeded a namespace policy.  Label \"result\" may be \"clamped\" or \"rejected\".",
+	}, []string{"result"})
+	metricPeerConnectionState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gubernator_peer_connection_state",
+		Help: "The state of the GRPC connection to each peer. 0 = IDLE, 1 = CONNECTING, 2 = READY, 3 = TRANSIENT_FAILURE, 4 = SHUTDOWN.",
+	}, []string{"peerAddr"})
+	metricTransactionCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_transaction_counter",
+		Help: "The count of TransactRateLimits() calls.  Label \"result\" may be \"committed\" when the hits were applied, \"aborted\" when no hits were applied, or \"partial\" when some of the reservations failed to commit.",
+	}, []string{"result"})
+	metricHierarchyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_hierarchy_counter",
+		Help: "The count of GetRateLimitHierarchy() calls.  Label \"result\" may be \"applied\" when the hits were consumed from every level, \"over_limit\" when no hits were consumed, or \"partial\" when some of the reservations failed to commit.",
+	}, []string{"result"})
+	metricShadowCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_shadow_counter",
+		Help: "The count of rate limits mirrored to the secondary cluster.  Label \"result\" may be \"match\" or \"mismatch\" when the secondary cluster made the same or a different decision, \"error\" when it failed to respond, or \"dropped\" when too many requests were in flight.",
+	}, []string{"result"})
+	metricColdKeyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_cold_key_counter",
+		Help: "The count of rate limits owned by another peer which has a key filter, see ColdKeyFilterInterval.  Label \"result\" may be \"answered\" when the owner has never seen the key and the hits were answered locally, or \"forwarded\" when they were not.",
+	}, []string{"result"})
+	metricStandbyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_standby_counter",
+		Help: "The count of hits replicated to the standby peer of the rate limit.  Label \"result\" may be \"replicated\" when the standby acknowledged the hits, \"failed\" when it did not, or \"skipped\" when there is no other peer.",
+	}, []string{"result"})
+	metricReservationCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_reservation_counter",
+		Help: "The count of reservations.  Label \"result\" may be \"reserved\", \"committed\", \"canceled\" or \"expired\".",
+	}, []string{"result"})
+	metricRefundCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_refund_counter",
+		Help: "The count of REFUNDABLE hits.  Label \"result\" may be \"recorded\", \"refunded\" or \"expired\".",
+	}, []string{"result"})
+	metricCacheFullCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_cache_full_counter",
+		Help: "The count of new rate limits requested while the cache was full of unexpired rate limits.  Label \"action\" may be \"evicted\", \"spilled\" or \"rejected\".",
+	}, []string{"action"})
+	metricIdempotentReplayCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_idempotent_replay_counter",
+		Help: "The count of requests whose idempotency key was already evaluated, which returned the original response.",
+	})
+	metricPeerRPCCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_peer_rpc_counter",
+		Help: "The count of RPCs sent to each peer.  Label \"status\" may be \"success\" or \"failed\".",
+	}, []string{"peer", "status"})
+	metricPeerBytesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_peer_bytes_counter",
+		Help: "The count of bytes sent to and received from each peer.  Label \"direction\" may be \"sent\" or \"received\".",
+	}, []string{"peer", "direction"})
+	metricPeerRPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gubernator_peer_rpc_duration",
+		Help:    "The timings of RPCs sent to each peer in seconds.",
+		Buckets: []float64{0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	}, []string{"peer"})
+	metricNamespaceGCCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_namespace_gc_counter",
+		Help: "The number of rate limits removed because their name was not accessed within the namespace GC duration.",
+	})
+	metricRejectedConnections = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_rejected_connections_counter",
+		Help: "The number of connections closed because the remote IP exceeded the per IP connection limit.",
+	})
+	metricRejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_rejected_requests_counter",
+		Help: "The number of requests rejected because of too many concurrent requests.  Label \"reason\" may be \"queue_full\" or \"queue_timeout\".",
+	}, []string{"reason"})
+	metricBudgetRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_budget_rejected_counter",
+		Help: "The number of requests rejected because their service was over its budget.  Label \"service\" may be \"V1\" or \"PeersV1\", label \"reason\" may be \"in_flight\" or \"rate\".",
+	}, []string{"service", "reason"})
+	metricBudgetInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gubernator_budget_in_flight",
+		Help: "The number of requests executing which count against the budget of their service.  Label \"service\" may be \"V1\" or \"PeersV1\".",
+	}, []string{"service"})
+	metricCompatResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_compat_counter",
+		Help: "The number of rate limit responses translated for legacy clients.  Label \"mode\" is the compat mode selected by the client or \"unknown\" if the mode is not configured.",
+	}, []string{"mode"})
+	metricFanOutWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gubernator_fanout_workers",
+		Help: "The number of goroutines evaluating the rate limits forwarded by other peers for each worker, see Config.FanOutWorkers.",
+	}, []string{"worker"})
+	metricFanOutBusy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gubernator_fanout_busy_workers",
+		Help: "The number of fan out goroutines of each worker evaluating a rate limit, divide by gubernator_fanout_workers for the utilization.",
+	}, []string{"worker"})
+	metricFanOutQueue = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gubernator_fanout_queue_length",
+		Help: "The number of forwarded rate limits waiting for a fan out goroutine of each worker.",
+	}, []string{"worker"})
 	metricWorkerQueue = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "gubernator_worker_queue_length",
 		Help: "The count of requests queued up in WorkerPool.",
@@ -97,6 +366,26 @@ var (
 		Name: "gubernator_batch_send_retries",
 		Help: "The count of retries occurred in asyncRequest() forwarding a request to another peer.",
 	}, []string{"name"})
+	metricLoadShedding = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gubernator_load_shedding",
+		Help: "1 while this instance advertises a reduced weight because its CPU utilization exceeded the load shedding threshold, otherwise 0.",
+	})
+	metricLoadSheddingCPUPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gubernator_load_shedding_cpu_percent",
+		Help: "The CPU utilization of this instance as a percentage of GOMAXPROCS, as of the last load shedding check.",
+	})
+	metricConfigSkew = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gubernator_config_skew_peers",
+		Help: "The number of peers whose config checksum differs from this instance, as of the last check.",
+	})
+	metricCoalescedRequests = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "gubernator_coalesced_requests_count",
+		Help: "The count of requests forwarded to another peer which were merged into an identical request.",
+	})
+	metricBatchOverflowCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gubernator_batch_overflow_counter",
+		Help: "The count of requests which found no room in the batch queue of a peer.",
+	}, []string{"policy"})
 	metricBatchQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "gubernator_batch_queue_length",
 		Help: "The getRateLimitsBatch() queue length in PeerClient.  This represents rate checks queued by for batching to a remote peer.",
@@ -122,17 +411,122 @@ func NewV1Instance(conf Config) (s *V1Instance, err error) {
 	}
 
 	s = &V1Instance{
-		log:  conf.Logger,
-		conf: conf,
+		log:              conf.Logger,
+		conf:             conf,
+		nameBehaviors:    make(map[string]Behavior),
+		overrides:        newOverrideTable(),
+		registeredLimits: newLimitRegistry(),
+		selfAddresses:    make(map[string]bool),
+		nodeID:           conf.NodeID,
+	}
+	if s.nodeID == "" {
+		s.nodeID = RandomString(16)
+	}
+	for _, name := range conf.Behaviors.DryRunNames {
+		s.nameBehaviors[name] |= Behavior_DRY_RUN
+	}
+	for _, name := range conf.Behaviors.GreedyRefillNames {
+		s.nameBehaviors[name] |= Behavior_GREEDY_REFILL
+	}
+	s.pickers.Store(&peerPickers{local: conf.LocalPicker, region: conf.RegionPicker})
+	s.ready.Store(conf.ReadyMinPeers == 0 && conf.ReadyMinPeersPercent == 0)
+	if conf.Faults != nil {
+		s.log.WithField("faults", *conf.Faults).Warn("fault injection is enabled; DO NOT use in production")
 	}
 
+	if store, ok := conf.Store.(BatchStore); ok {
+		// Only the workers report changes through the batcher, s.conf keeps the BatchStore
+		s.storeBatcher = newStoreBatcher(store, conf.StoreBatchLimit, conf.StoreBatchWait)
+		conf.Store = s.storeBatcher
+	}
 	s.workerPool = NewWorkerPool(&conf)
+	if conf.FanOutWorkers > 0 {
+		s.fanOut = newFanOutPool(s.workerPool, conf.FanOutWorkers, conf.FanOutQueueSize)
+	}
 	s.global = newGlobalManager(conf.Behaviors, s)
+	if conf.PolicyFile != "" {
+		set, err := loadPolicySet(conf.NamespacePolicies, conf.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.policies.Store(set)
+		if s.policyWatcher, err = newPolicyWatcher(conf, set, s.policies.Store); err != nil {
+			return nil, err
+		}
+	} else {
+		s.policies.Store(&policySet{policies: sortPolicies(conf.NamespacePolicies)})
+	}
+	s.templates = templatesByName(conf.Templates)
+	s.drift = newDriftTracker()
+	s.pipeline = s.newPipeline()
+	if conf.Behaviors.DegradedErrorPercent > 0 {
+		s.degraded = newDegradedTracker(conf.Behaviors, s.log)
+	}
+	if conf.MaxCostCenters > 0 {
+		s.costCenters = newCostCenters(conf.MaxCostCenters)
+	}
+	if conf.KeyHistorySize > 0 {
+		s.history = newKeyHistory(conf.KeyHistorySize, conf.KeyHistoryMaxKeys)
+	}
+	if conf.UsageWindow > 0 {
+		s.usage = newUsageTracker(conf.UsageWindow)
+		if conf.UsageExporter != nil {
+			s.usageDone = make(chan struct{})
+			go s.exportUsage()
+		}
+	}
+
+	if conf.JournalDir != "" {
+		if s.journal, err = newHitJournal(conf); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Federation.HomeAddress != "" {
+		if s.federation, err = newFederation(conf.Federation); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.Shadow.Address != "" {
+		if s.shadow, err = newShadow(conf.Shadow); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(conf.Standby.Names) != 0 {
+		s.standby = newStandby(conf.Standby)
+	}
+
+	if conf.ColdKeyFilterInterval > 0 {
+		s.coldKeys = newColdKeys(conf.ColdKeyFilterInterval)
+		s.coldKeys.wg.Add(1)
+		go s.runColdKeyFilters()
+	}
+
+	if len(conf.OverLimitAlerts) != 0 {
+		s.alerts = newAlertTracker(conf.OverLimitAlerts)
+		s.alerts.wg.Add(1)
+		go s.runAlerts()
+	}
+
+	s.namespaces = newNamespaceTracker()
+	if conf.NamespaceGCAfter > 0 {
+		s.namespaces.wg.Add(1)
+		go s.runNamespaceGC()
+	}
+
+	s.checksumConfig = newConfigChecksum(&conf)
+	s.configSkewDone = make(chan struct{})
+	go s.runConfigSkewCheck()
 
 	// Register our instance with all GRPC servers
 	for _, srv := range conf.GRPCServers {
 		RegisterV1Server(srv, s)
 		RegisterPeersV1Server(srv, s)
+		if conf.AdminEnabled {
+			RegisterAdminV1Server(srv, s)
+		}
 	}
 
 	if s.conf.Loader == nil {
@@ -145,9 +539,34 @@ func NewV1Instance(conf Config) (s *V1Instance, err error) {
 		return nil, errors.Wrap(err, "Error in workerPool.Load")
 	}
 
+	if conf.SnapshotInterval > 0 {
+		s.snapshotDone = make(chan struct{})
+		go s.saveSnapshots()
+	}
+
 	return s, nil
 }
 
+// saveSnapshots saves the contents of the cache with `Config.Loader` every
+// `Config.SnapshotInterval` until the instance is closed.
+func (s *V1Instance) saveSnapshots() {
+	tick := clock.NewTicker(s.conf.SnapshotInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			ctx, cancel := context.WithTimeout(context.Background(), s.conf.SnapshotInterval)
+			if err := s.workerPool.Store(ctx); err != nil {
+				s.log.WithError(err).Error("while saving cache snapshot")
+			}
+			cancel()
+		case <-s.snapshotDone:
+			return
+		}
+	}
+}
+
 func (s *V1Instance) Close() (err error) {
 	ctx := context.Background()
 
@@ -156,6 +575,28 @@ func (s *V1Instance) Close() (err error) {
 	}
 
 	s.global.Close()
+	if s.usageDone != nil {
+		close(s.usageDone)
+	}
+	close(s.configSkewDone)
+	if s.alerts != nil {
+		s.alerts.stop()
+	}
+	if s.coldKeys != nil {
+		s.coldKeys.stop()
+	}
+	s.namespaces.stop()
+	s.federation.close()
+	s.shadow.close()
+	if s.journal != nil {
+		s.journal.close()
+	}
+	if s.policyWatcher != nil {
+		s.policyWatcher.stop()
+	}
+	if s.snapshotDone != nil {
+		close(s.snapshotDone)
+	}
 
 	if s.conf.Loader != nil {
 		err = s.workerPool.Store(ctx)
@@ -166,12 +607,18 @@ func (s *V1Instance) Close() (err error) {
 		}
 	}
 
+	if s.fanOut != nil {
+		s.fanOut.close()
+	}
 	err = s.workerPool.Close()
 	if err != nil {
 		s.log.WithError(err).
 			Error("Error in workerPool.Close")
 		return errors.Wrap(err, "Error in workerPool.Close")
 	}
+	if s.storeBatcher != nil {
+		s.storeBatcher.close()
+	}
 
 	s.isClosed = true
 	return nil
@@ -185,6 +632,10 @@ func (s *V1Instance) GetRateLimits(ctx context.Context, r *GetRateLimitsReq) (*G
 	defer funcTimer.ObserveDuration()
 	metricConcurrentChecks.Inc()
 	defer metricConcurrentChecks.Dec()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	ctx, timings := s.withRequestTimings(ctx)
+	defer s.observeSlowRequest(ctx, timings, clock.Now(), len(r.Requests))
 
 	if len(r.Requests) > maxBatchSize {
 		metricCheckErrorCounter.WithLabelValues("Request too large").Inc()
@@ -192,16 +643,55 @@ func (s *V1Instance) GetRateLimits(ctx context.Context, r *GetRateLimitsReq) (*G
 			"Requests.RateLimits list too large; max size is '%d'", maxBatchSize)
 	}
 
-	createdAt := epochMillis(clock.Now())
+	if s.conf.UnavailableUntilReady && !s.Ready() {
+		metricCheckErrorCounter.WithLabelValues("Not ready").Inc()
+		return nil, status.Errorf(codes.Unavailable, "not ready; %s", s.waitingFor())
+	}
+
+	// Copied before the requests are modified below
+	shadowed := s.shadow.sample(ctx, r)
+
+	ctx = withClientCN(ctx)
+	createdAt := MillisecondNow()
+	// The reset times of the rate limits created at `createdAt` are converted to the wall clock
+	wallOffset := monotonic.offset()
 	resp := GetRateLimitsResp{
 		Responses: make([]*RateLimitResp, len(r.Requests)),
 	}
 	var wg sync.WaitGroup
 	asyncCh := make(chan AsyncResp, len(r.Requests))
+	// The requests proxied to the home cluster and their index in `r.Requests`
+	var federated []*RateLimitReq
+	var federatedIdx []int
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	failFast := func(rl *RateLimitResp) {
+		if r.FailFast && rl != nil && rl.Error != "" {
+			cancel(errFailFast)
+		}
+	}
 
 	// For each item in the request body
 	for i, req := range r.Requests {
-		key := req.Name + "_" + req.UniqueKey
+		// Abort the rest of the batch if the previous rate limit returned an error
+		if i > 0 {
+			failFast(resp.Responses[i-1])
+		}
+		applyRequestDefaults(r.Defaults, req)
+		assignRequestID(req)
+		stripRequestValues(req)
+		// Only set by peers forwarding the rate limits of a slow owner or replaying their journal
+		delete(req.Metadata, MetadataSlowOwner)
+		delete(req.Metadata, MetadataJournalReplay)
+		s.normalize(req)
+		s.aggregateIPKey(req)
+		if err := limitKeyLength(&s.conf, req); err != nil {
+			metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+			resp.Responses[i] = &RateLimitResp{Error: err.Error()}
+			continue
+		}
+		key := s.conf.HashKey(req)
 		var peer *PeerClient
 		var err error
 
@@ -218,9 +708,10 @@ func (s *V1Instance) GetRateLimits(ctx context.Context, r *GetRateLimitsReq) (*G
 		if req.CreatedAt == nil || *req.CreatedAt == 0 {
 			req.CreatedAt = &createdAt
 		}
+		countBehaviors(req)
 
 		if ctx.Err() != nil {
-			err = errors.Wrap(ctx.Err(), "Error while iterating request items")
+			err = errors.Wrap(context.Cause(ctx), "Error while iterating request items")
 			span := trace.SpanFromContext(ctx)
 			span.RecordError(err)
 			resp.Responses[i] = &RateLimitResp{
@@ -229,71 +720,256 @@ func (s *V1Instance) GetRateLimits(ctx context.Context, r *GetRateLimitsReq) (*G
 			continue
 		}
 
+		if s.federation.proxies(ctx, req.Name) {
+			federated = append(federated, req)
+			federatedIdx = append(federatedIdx, i)
+			continue
+		}
+
+		if t := s.template(req); t != nil {
+			t.apply(req)
+		}
 		if s.conf.Behaviors.ForceGlobal {
 			SetBehavior(&req.Behavior, Behavior_GLOBAL, true)
 		}
+		if b, ok := s.nameBehaviors[req.Name]; ok {
+			SetBehavior(&req.Behavior, b, true)
+		}
 
+		if err := validateBounds(req); err != nil {
+			metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+			resp.Responses[i] = &RateLimitResp{Error: err.Error()}
+			continue
+		}
+
+		if rl := s.validateForwardingOverride(req); rl != nil {
+			resp.Responses[i] = rl
+			continue
+		}
+
+		pickStart := clock.Now()
 		peer, err = s.GetPeer(ctx, key)
+		if timings != nil {
+			recordPhase(ctx, phasePick, clock.Since(pickStart))
+		}
 		if err != nil {
 			countError(err, "Error in GetPeer")
 			err = errors.Wrapf(err, "Error in GetPeer, looking up peer that owns rate limit '%s'", key)
-			resp.Responses[i] = &RateLimitResp{
-				Error: err.Error(),
-			}
+			resp.Responses[i] = s.peerUnavailable(req, err.Error())
 			continue
 		}
 
-		// If our server instance is the owner of this rate limit
-		reqState := RateLimitReqState{IsOwner: peer.Info().IsOwner}
-		if reqState.IsOwner {
-			// Apply our rate limit algorithm to the request
-			resp.Responses[i], err = s.getLocalRateLimit(ctx, req, reqState)
-			if err != nil {
-				err = errors.Wrapf(err, "Error while apply rate limit for '%s'", key)
-				span := trace.SpanFromContext(ctx)
-				span.RecordError(err)
-				resp.Responses[i] = &RateLimitResp{Error: err.Error()}
-			}
-		} else {
-			if HasBehavior(req.Behavior, Behavior_GLOBAL) {
-				resp.Responses[i], err = s.getGlobalRateLimit(ctx, req)
-				if err != nil {
-					err = errors.Wrap(err, "Error in getGlobalRateLimit")
-					span := trace.SpanFromContext(ctx)
-					span.RecordError(err)
-					resp.Responses[i] = &RateLimitResp{Error: err.Error()}
-				}
+		// Rate limits owned by this instance and GLOBAL rate limits are answered without a round
+		// trip to another peer, evaluate them inline instead of launching a goroutine. A forwarder
+		// receives no GLOBAL broadcasts, as such it forwards GLOBAL rate limits to the owner.
+		if !HasBehavior(req.Behavior, Behavior_FORCE_PEER) && (peer.Info().IsOwner ||
+			HasBehavior(req.Behavior, Behavior_FORCE_LOCAL) ||
+			(HasBehavior(req.Behavior, Behavior_GLOBAL) && !s.forwarder.Load())) {
+			resp.Responses[i] = s.check(ctx, &RateLimitCheck{Req: req, Key: key, Peer: peer})
+			continue
+		}
 
-				// Inform the client of the owner key of the key
-				resp.Responses[i].Metadata = map[string]string{"owner": peer.Info().GRPCAddress}
-				continue
-			}
+		// Request must be forwarded to peer that owns the key.
+		// Launch remote peer request in goroutine.
+		wg.Add(1)
+		go s.asyncRequest(ctx, &AsyncReq{
+			AsyncCh: asyncCh,
+			Peer:    peer,
+			Req:     req,
+			WG:      &wg,
+			Key:     key,
+			Idx:     i,
+		})
+	}
 
-			// Request must be forwarded to peer that owns the key.
-			// Launch remote peer request in goroutine.
-			wg.Add(1)
-			go s.asyncRequest(ctx, &AsyncReq{
-				AsyncCh: asyncCh,
-				Peer:    peer,
-				Req:     req,
-				WG:      &wg,
-				Key:     key,
-				Idx:     i,
-			})
-		}
+	if len(r.Requests) > 0 {
+		failFast(resp.Responses[len(r.Requests)-1])
+	}
+	if len(federated) != 0 {
+		wg.Add(1)
+		go s.federation.proxy(ctx, federated, federatedIdx, asyncCh, &wg)
 	}
 
 	// Wait for any async responses if any
-	wg.Wait()
-
-	close(asyncCh)
+	go func() {
+		wg.Wait()
+		close(asyncCh)
+	}()
 	for a := range asyncCh {
 		resp.Responses[a.Idx] = a.Resp
+		failFast(a.Resp)
+	}
+
+	for i, rl := range resp.Responses {
+		if rl == nil {
+			continue
+		}
+		rl.RequestId = r.Requests[i].RequestId
+		if r.Requests[i].CreatedAt == &createdAt && rl.ResetTime != 0 {
+			rl.ResetTime = addInt64(rl.ResetTime, wallOffset)
+		}
+		if r.MinimalResponse {
+			rl.Metadata = nil
+		}
 	}
+	resp.Summary = NewBatchSummary(r, resp.Responses)
 
+	s.shadow.mirror(shadowed, resp.Responses)
 	return &resp, nil
 }
 
+// validateForwardingOverride returns an error response if the request uses the FORCE_LOCAL or
+// FORCE_PEER behaviors and they are not enabled by `Config.ForwardingOverridesEnabled`, else nil.
+func (s *V1Instance) validateForwardingOverride(req *RateLimitReq) *RateLimitResp {
+	local, peer := HasBehavior(req.Behavior, Behavior_FORCE_LOCAL), HasBehavior(req.Behavior, Behavior_FORCE_PEER)
+	switch {
+	case !local && !peer:
+		return nil
+	case !s.conf.ForwardingOverridesEnabled:
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return &RateLimitResp{Error: "behaviors FORCE_LOCAL and FORCE_PEER are not enabled on this instance"}
+	case local && peer:
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return &RateLimitResp{Error: "behaviors FORCE_LOCAL and FORCE_PEER cannot be used together"}
+	}
+	return nil
+}
+
+// applyRequestDefaults sets the values of `d` on the rate limit if the rate limit omits them
+func applyRequestDefaults(d *RateLimitDefaults, req *RateLimitReq) {
+	if d == nil {
+		return
+	}
+	if req.Name == "" {
+		req.Name = d.Name
+	}
+	if req.Algorithm == Algorithm_TOKEN_BUCKET {
+		req.Algorithm = d.Algorithm
+	}
+	if req.Behavior == Behavior_BATCHING {
+		req.Behavior = d.Behavior
+	}
+}
+
+// assignRequestID generates a request id for requests which were not given one by the client
+func assignRequestID(req *RateLimitReq) {
+	if req.RequestId != "" {
+		return
+	}
+	token := make([]byte, 16)
+	_, _ = crand.Read(token)
+	req.RequestId = hex.EncodeToString(token)
+}
+
+// observeCheck records the duration of a rate limit check by algorithm, call type and outcome
+func observeCheck(ctx context.Context, start time.Time, callType string, req *RateLimitReq, rl *RateLimitResp) {
+	outcome := "error"
+	if rl != nil && rl.Error == "" {
+		outcome = strings.ToLower(rl.Status.String())
+	}
+	observeWithExemplar(ctx, metricCheckDuration.WithLabelValues(strings.ToLower(req.Algorithm.String()), callType, outcome),
+		clock.Since(start).Seconds())
+}
+
+// applyDryRun reports an over the limit response as under the limit, recording the
+// status it would have had in the response metadata.
+func applyDryRun(req *RateLimitReq, rl *RateLimitResp) {
+	if rl == nil || rl.Status != Status_OVER_LIMIT {
+		return
+	}
+	metricDryRunCounter.WithLabelValues(req.Name).Inc()
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata["dry_run_status"] = Status_OVER_LIMIT.String()
+	rl.Status = Status_UNDER_LIMIT
+}
+
+// withDefaultTimeout returns a context which expires after Config.DefaultRequestTimeout if `ctx`
+// has no deadline, such that a caller which set no timeout cannot hold a request forever, IE: while
+// the owning peer does not respond.
+func (s *V1Instance) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || s.conf.DefaultRequestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.conf.DefaultRequestTimeout)
+}
+
+// GetRateLimitGroup applies the hits of every rate limit in the group only if all of them are
+// under the limit. The group is first checked with `Hits = 0` against the owning peers, if any
+// rate limit in the group does not have enough remaining to cover the requested hits, the current
+// state of each rate limit is returned and no hits are applied.
+//
+// NOTE: The check and the apply are two separate round trips to the owning peers, as such hits
+// from requests outside the group which arrive between the check and the apply may still cause
+// one or more rate limits in the group to go over the limit.
+func (s *V1Instance) GetRateLimitGroup(ctx context.Context, r *GetRateLimitGroupReq) (*GetRateLimitGroupResp, error) {
+	funcTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetRateLimitGroup"))
+	defer funcTimer.ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+
+	if len(r.Requests) == 0 {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, status.Error(codes.InvalidArgument, "Requests list cannot be empty")
+	}
+
+	// Check the current state of each rate limit without applying any hits.
+	checks := make([]*RateLimitReq, len(r.Requests))
+	for i, req := range r.Requests {
+		// The check and the apply share the request id, such that both can be traced
+		assignRequestID(req)
+		checks[i] = proto.Clone(req).(*RateLimitReq)
+		checks[i].Hits = 0
+		SetBehavior(&checks[i].Behavior, Behavior_RESET_REMAINING, false)
+	}
+
+	checkResp, err := s.GetRateLimits(ctx, &GetRateLimitsReq{Requests: checks})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &GetRateLimitGroupResp{
+		Status:    Status_UNDER_LIMIT,
+		Responses: checkResp.Responses,
+	}
+	for i, rl := range checkResp.Responses {
+		if rl.Error != "" {
+			resp.Status = Status_OVER_LIMIT
+			continue
+		}
+		// Dry run rate limits never prevent the group from being applied
+		if HasBehavior(checks[i].Behavior, Behavior_DRY_RUN) {
+			continue
+		}
+		if !HasBehavior(r.Requests[i].Behavior, Behavior_RESET_REMAINING) && r.Requests[i].Hits > rl.Remaining {
+			rl.Status = Status_OVER_LIMIT
+			resp.Status = Status_OVER_LIMIT
+		}
+	}
+
+	if resp.Status == Status_OVER_LIMIT {
+		metricGroupCounter.WithLabelValues("over_limit").Inc()
+		return resp, nil
+	}
+
+	// Every rate limit in the group has room, apply the hits.
+	applyResp, err := s.GetRateLimits(ctx, &GetRateLimitsReq{Requests: r.Requests})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Responses = applyResp.Responses
+	for _, rl := range applyResp.Responses {
+		if rl.Error != "" || rl.Status == Status_OVER_LIMIT {
+			resp.Status = Status_OVER_LIMIT
+		}
+	}
+
+	metricGroupCounter.WithLabelValues("applied").Inc()
+	return resp, nil
+}
+
 type AsyncResp struct {
 	Idx  int
 	Resp *RateLimitResp
@@ -308,30 +984,47 @@ type AsyncReq struct {
 	Idx     int
 }
 
+// asyncRequest evaluates a rate limit owned by another peer through the behavior pipeline
+// and sends the response to `req.AsyncCh`.
 func (s *V1Instance) asyncRequest(ctx context.Context, req *AsyncReq) {
-	var attempts int
-	var err error
-
 	ctx = tracing.StartNamedScope(ctx, "V1Instance.asyncRequest")
 	defer tracing.EndScope(ctx, nil)
 
 	funcTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.asyncRequest"))
 	defer funcTimer.ObserveDuration()
 
-	reqState := RateLimitReqState{IsOwner: req.Peer.Info().IsOwner}
-	resp := AsyncResp{
-		Idx: req.Idx,
+	rl := s.check(ctx, &RateLimitCheck{Req: req.Req, Key: req.Key, Peer: req.Peer})
+	req.AsyncCh <- AsyncResp{Idx: req.Idx, Resp: rl}
+	req.WG.Done()
+
+	if isDeadlineExceeded(ctx.Err()) {
+		metricCheckErrorCounter.WithLabelValues("Timeout forwarding to peer").Inc()
 	}
+}
+
+// forwardRequest sends the rate limit to the peer which owns it, retrying with the new owner
+// if the peer was removed or disagrees that it owns the rate limit.
+func (s *V1Instance) forwardRequest(ctx context.Context, req *RateLimitCheck) *RateLimitResp {
+	var attempts int
+	var err error
+	start := clock.Now()
+
+	reqState := RateLimitReqState{IsOwner: req.Peer.Info().IsOwner}
+	var resp AsyncResp
+	// Is true if the owning peer could not be reached
+	var peerErr bool
 
 	for {
 		if attempts > 5 {
 			s.log.WithContext(ctx).
 				WithError(err).
-				WithField("key", req.Key).
+				WithField("key", redactKey(s.conf.KeyRedaction, req.Key)).
+				WithField("request_id", req.Req.RequestId).
 				Error("GetPeer() returned peer that is not connected")
 			countError(err, "Peer not connected")
 			err = errors.Wrapf(err, "GetPeer() keeps returning peers that are not connected for '%s'", req.Key)
 			resp.Resp = &RateLimitResp{Error: err.Error()}
+			peerErr = true
 			break
 		}
 
@@ -342,7 +1035,8 @@ func (s *V1Instance) asyncRequest(ctx context.Context, req *AsyncReq) {
 				if err != nil {
 					s.log.WithContext(ctx).
 						WithError(err).
-						WithField("key", req.Key).
+						WithField("key", redactKey(s.conf.KeyRedaction, req.Key)).
+						WithField("request_id", req.Req.RequestId).
 						Error("Error applying rate limit")
 					err = errors.Wrapf(err, "Error in getLocalRateLimit for '%s'", req.Key)
 					resp.Resp = &RateLimitResp{Error: err.Error()}
@@ -352,20 +1046,35 @@ func (s *V1Instance) asyncRequest(ctx context.Context, req *AsyncReq) {
 		}
 
 		// Make an RPC call to the peer that owns this rate limit
+		rpcStart := clock.Now()
 		r, err := req.Peer.GetPeerRateLimit(ctx, req.Req)
+		recordForward(ctx, req.Peer.Info().GRPCAddress, clock.Since(rpcStart))
 		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// The request was canceled, IE: by `fail_fast`, trying another peer would not help.
+			if ctx.Err() != nil {
+				err = errors.Wrap(context.Cause(ctx), fmt.Sprintf("Error while fetching rate limit '%s' from peer", req.Key))
+				resp.Resp = &RateLimitResp{Error: err.Error()}
+				break
+			}
+
+			// If the peer was removed from the picker while we were using it, try the new owner.
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errPeerClosing) {
 				attempts++
 				metricBatchSendRetries.WithLabelValues(req.Req.Name).Inc()
 				req.Peer, err = s.GetPeer(ctx, req.Key)
 				if err != nil {
 					errPart := fmt.Sprintf("Error finding peer that owns rate limit '%s'", req.Key)
-					s.log.WithContext(ctx).WithError(err).WithField("key", req.Key).Error(errPart)
+					s.log.WithContext(ctx).WithError(err).
+						WithField("key", redactKey(s.conf.KeyRedaction, req.Key)).
+						WithField("request_id", req.Req.RequestId).
+						Error("Error finding peer that owns rate limit")
 					countError(err, "Error in GetPeer")
 					err = errors.Wrap(err, errPart)
 					resp.Resp = &RateLimitResp{Error: err.Error()}
+					peerErr = true
 					break
 				}
+				reqState.IsOwner = req.Peer.Info().IsOwner
 				continue
 			}
 
@@ -373,33 +1082,67 @@ func (s *V1Instance) asyncRequest(ctx context.Context, req *AsyncReq) {
 			// report this error.
 			err = errors.Wrap(err, fmt.Sprintf("Error while fetching rate limit '%s' from peer", req.Key))
 			resp.Resp = &RateLimitResp{Error: err.Error()}
+			peerErr = true
+			break
+		}
+
+		// The peer is this instance under another address, the rate limit was evaluated locally
+		if r.Metadata[metadataSelfForward] == "true" {
+			s.selfForwarded(req.Peer)
+			delete(r.Metadata, metadataSelfForward)
+		}
+
+		// The peer disagrees that it owns the rate limit, our peer list might have changed since.
+		if r.Metadata[MetadataNotOwner] == "true" {
+			if attempts < 5 {
+				attempts++
+				metricBatchSendRetries.WithLabelValues(req.Req.Name).Inc()
+				if p, err := s.GetPeer(ctx, req.Key); err == nil {
+					req.Peer = p
+					reqState.IsOwner = p.Info().IsOwner
+				}
+				continue
+			}
+			resp.Resp = r
 			break
 		}
 
 		// Inform the client of the owner key of the key
 		resp.Resp = r
-		resp.Resp.Metadata = map[string]string{"owner": req.Peer.Info().GRPCAddress}
+		if resp.Resp.Metadata == nil {
+			resp.Resp.Metadata = make(map[string]string)
+		}
+		resp.Resp.Metadata["owner"] = req.Peer.Info().GRPCAddress
 		break
 	}
 
-	req.AsyncCh <- resp
-	req.WG.Done()
+	// Approximate the rate limit locally rather than failing while the error rate is high
+	if s.degraded != nil && s.degraded.record(peerErr) && peerErr {
+		if rl := s.getDegradedRateLimit(ctx, req); rl != nil {
+			resp.Resp = rl
+		}
+	}
 
-	if isDeadlineExceeded(ctx.Err()) {
-		metricCheckErrorCounter.WithLabelValues("Timeout forwarding to peer").Inc()
+	// A rate limit which could not be answered by its owner, nor approximated, fails closed if
+	// it is strict, or is reported as UNKNOWN otherwise
+	if (peerErr || ctx.Err() != nil) && resp.Resp.Error != "" {
+		resp.Resp = s.peerUnavailable(req.Req, resp.Resp.Error)
 	}
+
+	observeCheck(ctx, start, "forward", req.Req, resp.Resp)
+	return resp.Resp
 }
 
 // getGlobalRateLimit handles rate limits that are marked as `Behavior = GLOBAL`. Rate limit responses
 // are returned from the local cache and the hits are queued to be sent to the owning peer.
 func (s *V1Instance) getGlobalRateLimit(ctx context.Context, req *RateLimitReq) (resp *RateLimitResp, err error) {
 	ctx = tracing.StartNamedScope(ctx, "V1Instance.getGlobalRateLimit", trace.WithAttributes(
-		attribute.String("ratelimit.key", req.UniqueKey),
+		attribute.String("ratelimit.key", redactKey(s.conf.KeyRedaction, req.UniqueKey)),
 		attribute.String("ratelimit.name", req.Name),
 	))
 	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.getGlobalRateLimit")).ObserveDuration()
 	defer func() {
-		if err == nil {
+		if err == nil && !isIdempotentReplay(resp) {
 			s.global.QueueHit(req)
 		}
 		tracing.EndScope(ctx, err)
@@ -430,6 +1173,7 @@ func (s *V1Instance) UpdatePeerGlobals(ctx context.Context, r *UpdatePeerGlobals
 			ExpireAt:  g.Status.ResetTime,
 			Algorithm: g.Algorithm,
 			Key:       g.Key,
+			Name:      g.Name,
 		}
 		switch g.Algorithm {
 		case Algorithm_LEAKY_BUCKET:
@@ -494,61 +1238,129 @@ func (s *V1Instance) GetPeerRateLimits(ctx context.Context, r *GetPeerRateLimits
 		respWg.Done()
 	}()
 
-	// Fan out requests.
-	fan := syncutil.NewFanOut(s.conf.Workers)
-	for idx, req := range r.Requests {
-		fan.Run(func(in interface{}) error {
-			rin := in.(reqIn)
-			// Extract the propagated context from the metadata in the request
-			prop := propagation.TraceContext{}
-			ctx := prop.Extract(ctx, &MetadataCarrier{Map: rin.req.Metadata})
-
-			// Forwarded global requests must have DRAIN_OVER_LIMIT set so token and leaky algorithms
-			// drain the remaining in the event a peer asks for more than is remaining.
-			// This is needed because with GLOBAL behavior peers will accumulate hits, which could
-			// result in requesting more hits than is remaining.
-			if HasBehavior(rin.req.Behavior, Behavior_GLOBAL) {
-				SetBehavior(&rin.req.Behavior, Behavior_DRAIN_OVER_LIMIT, true)
+	evaluate := func(rin reqIn) {
+		// Extract the propagated context from the metadata in the request
+		prop := propagation.TraceContext{}
+		ctx := prop.Extract(ctx, &MetadataCarrier{Map: rin.req.Metadata})
+		ctx, err := extractRequestValues(ctx, rin.req, s.conf.RequestValueSigning)
+		if err != nil {
+			metricCheckErrorCounter.WithLabelValues("Invalid request values").Inc()
+			respChan <- respOut{rin.idx, &RateLimitResp{Error: err.Error(), RequestId: rin.req.RequestId}}
+			return
+		}
+
+		// Forwarded global requests must have DRAIN_OVER_LIMIT set so token and leaky algorithms
+		// drain the remaining in the event a peer asks for more than is remaining.
+		// This is needed because with GLOBAL behavior peers will accumulate hits, which could
+		// result in requesting more hits than is remaining.
+		if HasBehavior(rin.req.Behavior, Behavior_GLOBAL) {
+			SetBehavior(&rin.req.Behavior, Behavior_DRAIN_OVER_LIMIT, true)
+		}
+
+		// Assign default to CreatedAt for backwards compatibility.
+		if rin.req.CreatedAt == nil || *rin.req.CreatedAt == 0 {
+			createdAt := MillisecondNow()
+			rin.req.CreatedAt = &createdAt
+		}
+
+		// A rate limit this instance forwarded to itself under another address is owned by it
+		self := rin.req.Metadata[metadataNodeID] == s.nodeID
+
+		// Rate limits evaluated in place of a slow owner are not owned by this instance
+		if s.conf.Behaviors.VerifyPeerOwnership && rin.req.Metadata[MetadataSlowOwner] == "" && !self {
+			if rl := s.verifyOwnership(ctx, rin.req); rl != nil {
+				rl.RequestId = rin.req.RequestId
+				respChan <- respOut{rin.idx, rl}
+				return
 			}
+		}
 
-			// Assign default to CreatedAt for backwards compatibility.
-			if rin.req.CreatedAt == nil || *rin.req.CreatedAt == 0 {
-				createdAt := epochMillis(clock.Now())
-				rin.req.CreatedAt = &createdAt
+		rl, err := s.getLocalRateLimit(ctx, rin.req, reqState)
+		if err != nil {
+			// Return the error for this request
+			err = errors.Wrap(err, "Error in getLocalRateLimit")
+			rl = &RateLimitResp{Error: err.Error()}
+			// metricCheckErrorCounter is updated within getLocalRateLimit(), not in GetPeerRateLimits.
+		}
+		rl.RequestId = rin.req.RequestId
+		if self {
+			if rl.Metadata == nil {
+				rl.Metadata = make(map[string]string)
 			}
+			rl.Metadata[metadataSelfForward] = "true"
+		}
+
+		respChan <- respOut{rin.idx, rl}
+	}
 
-			rl, err := s.getLocalRateLimit(ctx, rin.req, reqState)
+	if s.fanOut != nil {
+		var wg sync.WaitGroup
+		for idx, req := range r.Requests {
+			rin := reqIn{idx, req}
+			wg.Add(1)
+			err := s.fanOut.do(ctx, s.conf.HashKey(req), func() {
+				defer wg.Done()
+				evaluate(rin)
+			})
 			if err != nil {
-				// Return the error for this request
-				err = errors.Wrap(err, "Error in getLocalRateLimit")
-				rl = &RateLimitResp{Error: err.Error()}
-				// metricCheckErrorCounter is updated within getLocalRateLimit(), not in GetPeerRateLimits.
+				wg.Done()
+				respChan <- respOut{idx, &RateLimitResp{Error: err.Error(), RequestId: req.RequestId}}
 			}
-
-			respChan <- respOut{rin.idx, rl}
-			return nil
-		}, reqIn{idx, req})
+		}
+		wg.Wait()
+	} else {
+		// Fan out requests.
+		fan := syncutil.NewFanOut(s.conf.Workers)
+		for idx, req := range r.Requests {
+			fan.Run(func(in interface{}) error {
+				evaluate(in.(reqIn))
+				return nil
+			}, reqIn{idx, req})
+		}
+		_ = fan.Wait()
 	}
 
 	// Wait for all requests to be handled, then clean up.
-	_ = fan.Wait()
 	close(respChan)
 	respWg.Wait()
 
 	return resp, nil
 }
 
+// verifyOwnership returns a response flagged with MetadataNotOwner if the rate limit is owned
+// by another peer according to our picker, else it returns nil. If our picker is unable to
+// provide an owner we assume ownership, as was the case before ownership was verified.
+func (s *V1Instance) verifyOwnership(ctx context.Context, r *RateLimitReq) *RateLimitResp {
+	key := s.conf.HashKey(r)
+	peer, err := s.GetPeer(ctx, key)
+	if err != nil || peer.Info().IsOwner {
+		return nil
+	}
+	metricCheckErrorCounter.WithLabelValues("Not owner").Inc()
+	return &RateLimitResp{
+		Error: fmt.Sprintf("rate limit '%s' is owned by '%s'", key, peer.Info().GRPCAddress),
+		Metadata: map[string]string{
+			MetadataNotOwner: "true",
+			"owner":          peer.Info().GRPCAddress,
+		},
+	}
+}
+
 // HealthCheck Returns the health of our instance.
 func (s *V1Instance) HealthCheck(ctx context.Context, r *HealthCheckReq) (health *HealthCheckResp, err error) {
 	span := trace.SpanFromContext(ctx)
 
 	var errs []string
 
-	s.peerMutex.RLock()
-	defer s.peerMutex.RUnlock()
+	var versions []*PeerVersion
+	if r.IncludeVersions {
+		versions = s.peerVersions(ctx)
+	}
+
+	pickers := s.pickers.Load()
 
 	// Iterate through local peers and get their last errors
-	localPeers := s.conf.LocalPicker.Peers()
+	localPeers := pickers.local.Peers()
 	for _, peer := range localPeers {
 		for _, errMsg := range peer.GetLastErr() {
 			err := fmt.Errorf("error returned from local peer.GetLastErr: %s", errMsg)
@@ -558,7 +1370,7 @@ func (s *V1Instance) HealthCheck(ctx context.Context, r *HealthCheckReq) (health
 	}
 
 	// Do the same for region peers
-	regionPeers := s.conf.RegionPicker.Peers()
+	regionPeers := pickers.region.Peers()
 	for _, peer := range regionPeers {
 		for _, errMsg := range peer.GetLastErr() {
 			err := fmt.Errorf("error returned from region peer.GetLastErr: %s", errMsg)
@@ -568,8 +1380,24 @@ func (s *V1Instance) HealthCheck(ctx context.Context, r *HealthCheckReq) (health
 	}
 
 	health = &HealthCheckResp{
-		PeerCount: int32(len(localPeers) + len(regionPeers)),
-		Status:    Healthy,
+		PeerCount:      int32(len(localPeers) + len(regionPeers)),
+		Status:         Healthy,
+		Versions:       versions,
+		PolicyVersion:  s.policies.Load().version,
+		ConfigChecksum: s.configChecksum(),
+	}
+
+	if r.IncludeEndpoints {
+		ownership := pickers.ringOwnership()
+		for _, peer := range append(localPeers, regionPeers...) {
+			ep := newEndpoint(peer.Info())
+			ep.Ownership = ownership[peer.Info().GRPCAddress]
+			health.Endpoints = append(health.Endpoints, ep)
+		}
+	}
+
+	if !s.Ready() {
+		errs = append([]string{"not ready; " + s.waitingFor()}, errs...)
 	}
 
 	if len(errs) != 0 {
@@ -585,28 +1413,117 @@ func (s *V1Instance) HealthCheck(ctx context.Context, r *HealthCheckReq) (health
 	return health, nil
 }
 
+// peerPickers is the local and region pickers in effect. A picker is never changed once it is
+// stored, such that the pickers may be read without a lock.
+type peerPickers struct {
+	local  PeerPicker
+	region RegionPeerPicker
+}
+
+// ringOwnership returns the fraction of the hash ring of its data center owned by each peer.
+func (pp *peerPickers) ringOwnership() map[string]float64 {
+	result := make(map[string]float64)
+	pickers := []PeerPicker{pp.local}
+	for _, p := range pp.region.Pickers() {
+		pickers = append(pickers, p)
+	}
+	for _, p := range pickers {
+		if o, ok := p.(RingOwnershipPicker); ok {
+			for addr, v := range o.RingOwnership() {
+				result[addr] = v
+			}
+		}
+	}
+	return result
+}
+
+// newEndpoint returns the endpoint clients use to reach the peer
+func newEndpoint(info PeerInfo) *Endpoint {
+	weight := int32(info.Weight)
+	if weight <= 0 {
+		weight = 1
+	}
+	return &Endpoint{
+		GrpcAddress: info.GRPCAddress,
+		HttpAddress: info.HTTPAddress,
+		DataCenter:  info.DataCenter,
+		Weight:      weight,
+	}
+}
+
 func (s *V1Instance) getLocalRateLimit(ctx context.Context, r *RateLimitReq, reqState RateLimitReqState) (_ *RateLimitResp, err error) {
-	ctx = tracing.StartNamedScope(ctx, "V1Instance.getLocalRateLimit", trace.WithAttributes(
-		attribute.String("ratelimit.key", r.UniqueKey),
-		attribute.String("ratelimit.name", r.Name),
-		attribute.Int64("ratelimit.limit", r.Limit),
-		attribute.Int64("ratelimit.hits", r.Hits),
-	))
+	ctx = tracing.StartNamedScope(ctx, "V1Instance.getLocalRateLimit")
 	defer func() { tracing.EndScope(ctx, err) }()
+	// Redacting the key hashes it, which is only worth doing if the span is sampled
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.SetAttributes(
+			attribute.String("ratelimit.key", redactKey(s.conf.KeyRedaction, r.UniqueKey)),
+			attribute.String("ratelimit.name", r.Name),
+			attribute.String("ratelimit.request_id", r.RequestId),
+			attribute.Int64("ratelimit.limit", r.Limit),
+			attribute.Int64("ratelimit.hits", r.Hits),
+		)
+	}
 	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.getLocalRateLimit")).ObserveDuration()
 
+	if reqState.IsOwner {
+		reqState.drift = s.drift
+	}
+	s.namespaces.touch(r.Name)
+	var standbyKey string
+	if s.standby.replicates(r, reqState) {
+		// The standby must receive the states of the rate limit in the order they are applied
+		standbyKey = s.conf.HashKey(r)
+		defer s.standby.lock(standbyKey)()
+	}
+	start := clock.Now()
 	resp, err := s.workerPool.GetRateLimit(ctx, r, reqState)
 	if err != nil {
 		return nil, errors.Wrap(err, "during workerPool.GetRateLimit")
 	}
+	elapsed := clock.Since(start)
+	recordPhase(ctx, phaseAlgorithm, elapsed)
+	if isIdempotentReplay(resp) {
+		return resp, nil
+	}
+
+	// Respond once the standby has the hits, such that the crash of this instance cannot lose them
+	if standbyKey != "" {
+		if err := s.replicateToStandby(ctx, standbyKey); err != nil {
+			return nil, err
+		}
+	}
 
 	// If global behavior, then broadcast update to all peers.
 	if HasBehavior(r.Behavior, Behavior_GLOBAL) {
 		s.global.QueueUpdate(r)
 	}
 
+	if s.history != nil && !isQueryOnly(r) {
+		s.recordHistory(ctx, r, resp)
+	}
+
 	if reqState.IsOwner {
 		metricGetRateLimitCounter.WithLabelValues("local").Inc()
+		var costCenter string
+		if s.costCenters != nil {
+			costCenter = s.costCenters.name(ctx, r)
+			metricCostCenterHits.WithLabelValues(costCenter).Add(float64(r.Hits))
+			metricCostCenterSeconds.WithLabelValues(costCenter).Add(elapsed.Seconds())
+		}
+		if s.usage != nil {
+			s.usage.record(r, resp, costCenter, elapsed)
+		}
+		if resp.Status == Status_OVER_LIMIT && s.conf.AuditSink != nil {
+			s.auditOverLimit(ctx, r, resp)
+		}
+		if s.journal != nil && !isQueryOnly(r) && r.Metadata[MetadataJournalReplay] == "" {
+			s.journal.record(r)
+		}
+		// Rate limits shadowed with DRY_RUN are not enforced, as such they never alert
+		if resp.Status == Status_OVER_LIMIT && s.alerts != nil && !HasBehavior(r.Behavior, Behavior_DRY_RUN) {
+			s.alerts.record(r.Name)
+		}
 	}
 	return resp, nil
 }
@@ -614,60 +1531,190 @@ func (s *V1Instance) getLocalRateLimit(ctx context.Context, r *RateLimitReq, req
 // SetPeers replaces the peers and shuts down all the previous peers.
 // TODO this should return an error if we failed to connect to any of the new peers
 func (s *V1Instance) SetPeers(peerInfo []PeerInfo) {
-	localPicker := s.conf.LocalPicker.New()
-	regionPicker := s.conf.RegionPicker.New()
+	s.peerUpdateMutex.Lock()
+	defer s.peerUpdateMutex.Unlock()
+
+	peerInfo = s.markSelf(peerInfo)
+	current := s.pickers.Load()
+	localPicker := current.local.New()
+	regionPicker := current.region.New()
 
+	var forwarder bool
 	for _, info := range peerInfo {
+		// Forwarders never own rate limits, as such no key may hash to them
+		if info.Forwarder {
+			if info.IsOwner {
+				forwarder = true
+			}
+			continue
+		}
 		// Add peers that are not in our local DC to the RegionPicker
 		if info.DataCenter != s.conf.DataCenter {
-			peer := s.conf.RegionPicker.GetByPeerInfo(info)
+			peer := current.region.GetByPeerInfo(info)
 			// If we don't have an existing PeerClient create a new one
 			if peer == nil {
 				var err error
-				peer, err = NewPeerClient(PeerConfig{
-					TraceGRPC: s.conf.PeerTraceGRPC,
-					Behavior:  s.conf.Behaviors,
-					TLS:       s.conf.PeerTLS,
-					Log:       s.log,
-					Info:      info,
-				})
-				if err != nil {
+				if peer, err = s.newPeerClient(info); err != nil {
 					s.log.Errorf("error connecting to peer %s: %s", info.GRPCAddress, err)
 					return
 				}
+			} else {
+				peer.setWeight(info.Weight)
 			}
 			regionPicker.Add(peer)
 			continue
 		}
 		// If we don't have an existing PeerClient create a new one
-		peer := s.conf.LocalPicker.GetByPeerInfo(info)
+		peer := current.local.GetByPeerInfo(info)
 		if peer == nil {
 			var err error
-			peer, err = NewPeerClient(PeerConfig{
-				TraceGRPC: s.conf.PeerTraceGRPC,
-				Behavior:  s.conf.Behaviors,
-				TLS:       s.conf.PeerTLS,
-				Log:       s.log,
-				Info:      info,
-			})
-			if err != nil {
+			if peer, err = s.newPeerClient(info); err != nil {
+				s.log.Errorf("error connecting to peer %s: %s", info.GRPCAddress, err)
+				return
+			}
+		} else {
+			peer.setWeight(info.Weight)
+		}
+		localPicker.Add(peer)
+	}
+
+	s.log.WithField("peers", peerInfo).Debug("peers updated")
+	s.replacePickers(localPicker, regionPicker, forwarder, len(peerInfo))
+}
+
+// UpdatePeers adds and removes peers instead of replacing every peer like SetPeers(), such that a
+// large cluster only sends the peers which joined or left. Pickers which implement DeltaPeerPicker
+// are changed incrementally, other pickers are rebuilt from the current peers. Added peers which
+// are already known are ignored.
+func (s *V1Instance) UpdatePeers(added, removed []PeerInfo) {
+	s.peerUpdateMutex.Lock()
+	defer s.peerUpdateMutex.Unlock()
+
+	added = s.markSelf(added)
+	current := s.pickers.Load()
+	oldLocalPicker, oldRegionPicker := current.local, current.region
+	forwarder := s.forwarder.Load()
+
+	removedPeers := make(map[string]bool, len(removed))
+	var regionChanged bool
+	for _, info := range removed {
+		if info.IsOwner && info.Forwarder {
+			forwarder = false
+		}
+		if oldRegionPicker.GetByPeerInfo(info) != nil {
+			regionChanged = true
+		}
+		removedPeers[info.GRPCAddress] = true
+	}
+
+	var localPeers, regionPeers []*PeerClient
+	for _, info := range added {
+		// Forwarders never own rate limits, as such no key may hash to them
+		if info.Forwarder {
+			if info.IsOwner {
+				forwarder = true
+			}
+			continue
+		}
+		peer := oldLocalPicker.GetByPeerInfo(info)
+		if peer == nil {
+			peer = oldRegionPicker.GetByPeerInfo(info)
+		}
+		if peer != nil {
+			// A peer which is removed and added again keeps its PeerClient
+			if !removedPeers[info.GRPCAddress] {
+				continue
+			}
+			peer.setWeight(info.Weight)
+		} else {
+			var err error
+			if peer, err = s.newPeerClient(info); err != nil {
 				s.log.Errorf("error connecting to peer %s: %s", info.GRPCAddress, err)
 				return
 			}
 		}
+		if info.DataCenter != s.conf.DataCenter {
+			regionPeers = append(regionPeers, peer)
+			regionChanged = true
+			continue
+		}
+		localPeers = append(localPeers, peer)
+	}
+
+	var localPicker PeerPicker
+	if delta, ok := oldLocalPicker.(DeltaPeerPicker); ok {
+		localPicker = delta.Clone()
+		for _, info := range removed {
+			localPicker.(DeltaPeerPicker).Remove(info)
+		}
+	} else {
+		localPicker = oldLocalPicker.New()
+		for _, peer := range oldLocalPicker.Peers() {
+			if !removedPeers[peer.Info().GRPCAddress] {
+				localPicker.Add(peer)
+			}
+		}
+	}
+	for _, peer := range localPeers {
 		localPicker.Add(peer)
 	}
 
-	s.peerMutex.Lock()
+	// Region peers rarely change, the region picker is only rebuilt if they did
+	regionPicker := oldRegionPicker
+	if regionChanged {
+		regionPicker = oldRegionPicker.New()
+		for _, peer := range oldRegionPicker.Peers() {
+			if !removedPeers[peer.Info().GRPCAddress] {
+				regionPicker.Add(peer)
+			}
+		}
+		for _, peer := range regionPeers {
+			regionPicker.Add(peer)
+		}
+	}
+
+	s.log.WithField("added", added).WithField("removed", removed).Debug("peers updated")
+	s.replacePickers(localPicker, regionPicker, forwarder, len(localPicker.Peers())+len(regionPicker.Peers()))
+}
 
+// newPeerClient returns a PeerClient for the peer configured like every other PeerClient of the instance
+func (s *V1Instance) newPeerClient(info PeerInfo) (*PeerClient, error) {
+	return NewPeerClient(PeerConfig{
+		TraceGRPC:           s.conf.PeerTraceGRPC,
+		Behavior:            s.conf.Behaviors,
+		TLS:                 s.conf.peerTLS(info.GRPCAddress),
+		Compression:         s.conf.PeerCompression,
+		CompressionMinBytes: s.conf.PeerCompressionMinBytes,
+		Faults:              s.conf.Faults,
+		Auth:                s.conf.PeerAuth,
+		ValueSigning:        s.conf.RequestValueSigning,
+		NodeID:              s.nodeID,
+		Transport:           s.conf.PeerTransport,
+		KeyRedaction:        s.conf.KeyRedaction,
+		Log:                 s.log,
+		Info:                info,
+	})
+}
+
+// replacePickers swaps the current pickers for the pickers provided and shuts down the peers which
+// are no longer in either picker. `peers` is the number of peers counted towards readyMinPeers()
+func (s *V1Instance) replacePickers(localPicker PeerPicker, regionPicker RegionPeerPicker, forwarder bool, peers int) {
 	// Replace our current pickers
-	oldLocalPicker := s.conf.LocalPicker
-	oldRegionPicker := s.conf.RegionPicker
-	s.conf.LocalPicker = localPicker
-	s.conf.RegionPicker = regionPicker
-	s.peerMutex.Unlock()
+	old := s.pickers.Swap(&peerPickers{local: localPicker, region: regionPicker})
+	oldLocalPicker, oldRegionPicker := old.local, old.region
+	s.forwarder.Store(forwarder)
 
-	s.log.WithField("peers", peerInfo).Debug("peers updated")
+	s.sendClusterEvents(oldLocalPicker, oldRegionPicker)
+
+	s.checkReady(peers)
+
+	// Copy the overrides and registered limits of an existing peer if we have just joined the cluster
+	if !s.overridesSynced.Load() && s.overridesSyncing.CompareAndSwap(false, true) {
+		go s.syncOverrides()
+	}
+	if !s.limitsSynced.Load() && s.limitsSyncing.CompareAndSwap(false, true) {
+		go s.syncLimits()
+	}
 
 	// Shutdown any old peers we no longer need
 	ctx, cancel := context.WithTimeout(context.Background(), s.conf.Behaviors.BatchTimeout)
@@ -675,14 +1722,14 @@ func (s *V1Instance) SetPeers(peerInfo []PeerInfo) {
 
 	var shutdownPeers []*PeerClient
 	for _, peer := range oldLocalPicker.Peers() {
-		if peerInfo := s.conf.LocalPicker.GetByPeerInfo(peer.Info()); peerInfo == nil {
+		if peerInfo := localPicker.GetByPeerInfo(peer.Info()); peerInfo == nil {
 			shutdownPeers = append(shutdownPeers, peer)
 		}
 	}
 
 	for _, regionPicker := range oldRegionPicker.Pickers() {
 		for _, peer := range regionPicker.Peers() {
-			if peerInfo := s.conf.RegionPicker.GetByPeerInfo(peer.Info()); peerInfo == nil {
+			if peerInfo := regionPicker.GetByPeerInfo(peer.Info()); peerInfo == nil {
 				shutdownPeers = append(shutdownPeers, peer)
 			}
 		}
@@ -710,13 +1757,66 @@ func (s *V1Instance) SetPeers(peerInfo []PeerInfo) {
 	}
 }
 
+// Ready returns true once SetPeers() has been called with at least `Config.ReadyMinPeers` peers,
+// and at least `Config.ReadyMinPeersPercent` of the peers reported by SetClusterSize(). Once ready,
+// the instance remains ready even if the number of peers drops.
+func (s *V1Instance) Ready() bool {
+	return s.ready.Load()
+}
+
+// SetClusterSize is called by peer discovery with the number of peers it expects in the cluster,
+// including the peers which are starting and not yet passed to SetPeers(). The instance is not
+// ready until it has `Config.ReadyMinPeersPercent` of them, such that instances which start at the
+// same time do not each serve rate limits from a ring which only contains themselves. If
+// `Config.ReadyMinPeersPercent` is set, the instance is not ready until SetClusterSize() is called.
+func (s *V1Instance) SetClusterSize(size int) {
+	s.peerUpdateMutex.Lock()
+	defer s.peerUpdateMutex.Unlock()
+
+	s.clusterSize.Store(int64(size))
+	pickers := s.pickers.Load()
+	s.checkReady(len(pickers.local.Peers()) + len(pickers.region.Peers()))
+}
+
+// readyMinPeers returns the number of peers the instance must have before it is ready
+func (s *V1Instance) readyMinPeers() int {
+	quorum := int((int64(s.conf.ReadyMinPeersPercent)*s.clusterSize.Load() + 99) / 100)
+	if quorum < s.conf.ReadyMinPeers {
+		return s.conf.ReadyMinPeers
+	}
+	return quorum
+}
+
+// waitingFor returns what the instance waits for before it is ready
+func (s *V1Instance) waitingFor() string {
+	if s.conf.ReadyMinPeersPercent != 0 && s.clusterSize.Load() == 0 {
+		return "waiting for peer discovery to report the cluster size"
+	}
+	return fmt.Sprintf("waiting for at least '%d' peers", s.readyMinPeers())
+}
+
+// checkReady marks the instance ready if `peers` is at least readyMinPeers()
+func (s *V1Instance) checkReady(peers int) {
+	if s.conf.ReadyMinPeersPercent != 0 && s.clusterSize.Load() == 0 {
+		return
+	}
+	if peers >= s.readyMinPeers() && !s.ready.Swap(true) {
+		s.log.WithField("peers", peers).Info("instance is ready")
+	}
+}
+
 // GetPeer returns a peer client for the hash key provided
 func (s *V1Instance) GetPeer(ctx context.Context, key string) (p *PeerClient, err error) {
 	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeer")).ObserveDuration()
 
-	s.peerMutex.RLock()
-	defer s.peerMutex.RUnlock()
-	p, err = s.conf.LocalPicker.Get(key)
+	picker := s.pickers.Load().local
+	if s.conf.Faults.flapOwnership() {
+		if peers := picker.Peers(); len(peers) != 0 {
+			return peers[rand.Intn(len(peers))], nil
+		}
+	}
+
+	p, err = picker.Get(key)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error in conf.LocalPicker.Get")
 	}
@@ -725,28 +1825,72 @@ func (s *V1Instance) GetPeer(ctx context.Context, key string) (p *PeerClient, er
 }
 
 func (s *V1Instance) GetPeerList() []*PeerClient {
-	s.peerMutex.RLock()
-	defer s.peerMutex.RUnlock()
-	return s.conf.LocalPicker.Peers()
+	return s.pickers.Load().local.Peers()
 }
 
 func (s *V1Instance) GetRegionPickers() map[string]PeerPicker {
-	s.peerMutex.RLock()
-	defer s.peerMutex.RUnlock()
-	return s.conf.RegionPicker.Pickers()
+	return s.pickers.Load().region.Pickers()
 }
 
 // Describe fetches prometheus metrics to be registered
 func (s *V1Instance) Describe(ch chan<- *prometheus.Desc) {
+	metricAlgorithmChangeCounter.Describe(ch)
+	metricLongKeyCounter.Describe(ch)
+	metricAuditDropped.Describe(ch)
+	metricJournalDropped.Describe(ch)
+	metricCostCenterHits.Describe(ch)
+	metricCostCenterSeconds.Describe(ch)
+	metricBatchOverflowCounter.Describe(ch)
 	metricBatchQueueLength.Describe(ch)
 	metricBatchSendDuration.Describe(ch)
 	metricBatchSendRetries.Describe(ch)
+	metricCoalescedRequests.Describe(ch)
+	metricConfigSkew.Describe(ch)
+	metricBehaviorCounter.Describe(ch)
+	metricCacheFullCounter.Describe(ch)
+	metricCalloutCounter.Describe(ch)
+	metricCheckDuration.Describe(ch)
 	metricCheckErrorCounter.Describe(ch)
 	metricCommandCounter.Describe(ch)
 	metricConcurrentChecks.Describe(ch)
+	metricHitStreams.Describe(ch)
+	metricDegradedCounter.Describe(ch)
+	metricStrictCounter.Describe(ch)
+	metricDryRunCounter.Describe(ch)
+	metricFanOutBusy.Describe(ch)
+	metricFanOutQueue.Describe(ch)
+	metricFanOutWorkers.Describe(ch)
+	metricFederationCounter.Describe(ch)
 	metricFuncTimeDuration.Describe(ch)
 	metricGetRateLimitCounter.Describe(ch)
+	metricGroupCounter.Describe(ch)
+	metricIdempotentReplayCounter.Describe(ch)
+	metricLeaseCounter.Describe(ch)
+	metricLimitDrift.Describe(ch)
+	metricLoadShedding.Describe(ch)
+	metricLoadSheddingCPUPercent.Describe(ch)
+	metricNamespaceGCCounter.Describe(ch)
 	metricOverLimitCounter.Describe(ch)
+	metricOverrideCounter.Describe(ch)
+	metricPeerBytesCounter.Describe(ch)
+	metricPeerConnectionState.Describe(ch)
+	metricPeerRPCCounter.Describe(ch)
+	metricPeerRPCDuration.Describe(ch)
+	metricPolicyCounter.Describe(ch)
+	metricRefundCounter.Describe(ch)
+	metricRejectedConnections.Describe(ch)
+	metricRejectedRequests.Describe(ch)
+	metricBudgetRejected.Describe(ch)
+	metricBudgetInFlight.Describe(ch)
+	metricCompatResponses.Describe(ch)
+	metricReservationCounter.Describe(ch)
+	metricTransactionCounter.Describe(ch)
+	metricHierarchyCounter.Describe(ch)
+	metricShadowCounter.Describe(ch)
+	metricStandbyCounter.Describe(ch)
+	metricColdKeyCounter.Describe(ch)
+	metricSlowPeerCounter.Describe(ch)
+	metricSlowRequestCounter.Describe(ch)
 	metricWorkerQueue.Describe(ch)
 	s.global.metricBroadcastDuration.Describe(ch)
 	s.global.metricGlobalQueueLength.Describe(ch)
@@ -756,15 +1900,63 @@ func (s *V1Instance) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect fetches metrics from the server for use by prometheus
 func (s *V1Instance) Collect(ch chan<- prometheus.Metric) {
+	metricAlgorithmChangeCounter.Collect(ch)
+	metricLongKeyCounter.Collect(ch)
+	metricAuditDropped.Collect(ch)
+	metricJournalDropped.Collect(ch)
+	metricCostCenterHits.Collect(ch)
+	metricCostCenterSeconds.Collect(ch)
+	metricBatchOverflowCounter.Collect(ch)
 	metricBatchQueueLength.Collect(ch)
 	metricBatchSendDuration.Collect(ch)
 	metricBatchSendRetries.Collect(ch)
+	metricCoalescedRequests.Collect(ch)
+	metricConfigSkew.Collect(ch)
+	metricBehaviorCounter.Collect(ch)
+	metricCacheFullCounter.Collect(ch)
+	metricCalloutCounter.Collect(ch)
+	metricCheckDuration.Collect(ch)
 	metricCheckErrorCounter.Collect(ch)
 	metricCommandCounter.Collect(ch)
 	metricConcurrentChecks.Collect(ch)
+	metricHitStreams.Collect(ch)
+	metricDegradedCounter.Collect(ch)
+	metricStrictCounter.Collect(ch)
+	metricDryRunCounter.Collect(ch)
+	metricFanOutBusy.Collect(ch)
+	metricFanOutQueue.Collect(ch)
+	metricFanOutWorkers.Collect(ch)
+	metricFederationCounter.Collect(ch)
 	metricFuncTimeDuration.Collect(ch)
 	metricGetRateLimitCounter.Collect(ch)
+	metricGroupCounter.Collect(ch)
+	metricIdempotentReplayCounter.Collect(ch)
+	metricLeaseCounter.Collect(ch)
+	metricLimitDrift.Collect(ch)
+	metricLoadShedding.Collect(ch)
+	metricLoadSheddingCPUPercent.Collect(ch)
+	metricNamespaceGCCounter.Collect(ch)
 	metricOverLimitCounter.Collect(ch)
+	metricOverrideCounter.Collect(ch)
+	metricPeerBytesCounter.Collect(ch)
+	metricPeerConnectionState.Collect(ch)
+	metricPeerRPCCounter.Collect(ch)
+	metricPeerRPCDuration.Collect(ch)
+	metricPolicyCounter.Collect(ch)
+	metricRefundCounter.Collect(ch)
+	metricRejectedConnections.Collect(ch)
+	metricRejectedRequests.Collect(ch)
+	metricBudgetRejected.Collect(ch)
+	metricBudgetInFlight.Collect(ch)
+	metricCompatResponses.Collect(ch)
+	metricReservationCounter.Collect(ch)
+	metricTransactionCounter.Collect(ch)
+	metricHierarchyCounter.Collect(ch)
+	metricShadowCounter.Collect(ch)
+	metricStandbyCounter.Collect(ch)
+	metricColdKeyCounter.Collect(ch)
+	metricSlowPeerCounter.Collect(ch)
+	metricSlowRequestCounter.Collect(ch)
 	metricWorkerQueue.Collect(ch)
 	s.global.metricBroadcastDuration.Collect(ch)
 	s.global.metricGlobalQueueLength.Collect(ch)
@@ -777,6 +1969,16 @@ func HasBehavior(b Behavior, flag Behavior) bool {
 	return b&flag != 0
 }
 
+// countBehaviors counts each behavior flag set by the client in metricBehaviorCounter, such that
+// clients which use expensive behaviors can be found. Behaviors set by templates or config are not counted.
+func countBehaviors(r *RateLimitReq) {
+	for flag, name := range Behavior_name {
+		if flag != 0 && HasBehavior(r.Behavior, Behavior(flag)) {
+			metricBehaviorCounter.WithLabelValues(r.Name, name).Inc()
+		}
+	}
+}
+
 // SetBehavior sets or clears the behavior depending on the boolean `set`
 func SetBehavior(b *Behavior, flag Behavior, set bool) {
 	if set {
@@ -0,0 +1,67 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// HashKeyFunc returns the key which identifies a rate limit in the cache, in the persistent
+// store and when choosing the peer which owns the rate limit. Every peer in the cluster MUST
+// use the same HashKeyFunc, else peers will disagree on who owns a rate limit.
+type HashKeyFunc func(r *RateLimitReq) string
+
+// LegacyHashKey joins the name and unique key with an underscore. This is the default and
+// compatible with previous versions of gubernator, however it is possible for two different
+// rate limits to produce the same key. IE: name 'a_b' with key 'c' and name 'a' with key 'b_c'
+func LegacyHashKey(r *RateLimitReq) string {
+	return r.HashKey()
+}
+
+// DelimitedHashKey joins the name and unique key with an underscore after escaping any
+// underscores in the name, such that two different rate limits never produce the same key.
+func DelimitedHashKey(r *RateLimitReq) string {
+	return escapeKeyName(r.Name) + "_" + r.UniqueKey
+}
+
+// NewSaltedHashKey returns a HashKeyFunc which replaces the unique key with a HMAC-SHA256
+// of the unique key using the salt returned by `salt` for the rate limit name. This avoids
+// storing or logging the raw unique key, and allows each tenant (name) to use a different salt.
+// The escaped name remains as the prefix of the key.
+//
+//	conf.HashKey = gubernator.NewSaltedHashKey(func(name string) []byte {
+//		return tenantSalts[name]
+//	})
+func NewSaltedHashKey(salt func(name string) []byte) HashKeyFunc {
+	return func(r *RateLimitReq) string {
+		mac := hmac.New(sha256.New, salt(r.Name))
+		mac.Write([]byte(r.UniqueKey))
+		return escapeKeyName(r.Name) + "_" + hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+}
+
+var keyNameEscaper = strings.NewReplacer(`\`, `\\`, `_`, `\_`)
+
+func escapeKeyName(name string) string {
+	if !strings.ContainsAny(name, `\_`) {
+		return name
+	}
+	return keyNameEscaper.Replace(name)
+}
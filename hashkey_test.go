@@ -0,0 +1,67 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gubernator-io/gubernator/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashKey(t *testing.T) {
+	a := &gubernator.RateLimitReq{Name: "a_b", UniqueKey: "c"}
+	b := &gubernator.RateLimitReq{Name: "a", UniqueKey: "b_c"}
+
+	t.Run("Legacy", func(t *testing.T) {
+		assert.Equal(t, "a_b_c", gubernator.LegacyHashKey(a))
+		// Known collision, retained for compatibility.
+		assert.Equal(t, gubernator.LegacyHashKey(a), gubernator.LegacyHashKey(b))
+	})
+
+	t.Run("Delimited", func(t *testing.T) {
+		assert.Equal(t, `a\_b_c`, gubernator.DelimitedHashKey(a))
+		assert.Equal(t, "a_b_c", gubernator.DelimitedHashKey(b))
+		assert.Equal(t, "requests-per-sec_account:1234", gubernator.DelimitedHashKey(&gubernator.RateLimitReq{
+			Name:      "requests-per-sec",
+			UniqueKey: "account:1234",
+		}))
+		assert.NotEqual(t,
+			gubernator.DelimitedHashKey(&gubernator.RateLimitReq{Name: `a\`, UniqueKey: "_b"}),
+			gubernator.DelimitedHashKey(&gubernator.RateLimitReq{Name: `a\_`, UniqueKey: "b"}))
+	})
+
+	t.Run("Salted", func(t *testing.T) {
+		salts := map[string][]byte{"tenant1": []byte("salt1"), "tenant2": []byte("salt2")}
+		hashKey := gubernator.NewSaltedHashKey(func(name string) []byte {
+			return salts[name]
+		})
+
+		key := hashKey(&gubernator.RateLimitReq{Name: "tenant1", UniqueKey: "account:1234"})
+		assert.True(t, strings.HasPrefix(key, "tenant1_"))
+		assert.NotContains(t, key, "account:1234")
+		assert.Equal(t, key, hashKey(&gubernator.RateLimitReq{Name: "tenant1", UniqueKey: "account:1234"}))
+		assert.NotEqual(t, key, hashKey(&gubernator.RateLimitReq{Name: "tenant1", UniqueKey: "account:5678"}))
+
+		// Different tenants produce different hashes for the same unique key.
+		other := hashKey(&gubernator.RateLimitReq{Name: "tenant2", UniqueKey: "account:1234"})
+		assert.NotEqual(t, strings.TrimPrefix(key, "tenant1_"), strings.TrimPrefix(other, "tenant2_"))
+
+		assert.NotEqual(t, hashKey(a), hashKey(b))
+	})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// HealthServiceLiveness is the GRPC health service which is serving as long as the instance
+	// answers requests, IE: for a Kubernetes liveness probe. The empty service name is the same.
+	HealthServiceLiveness = "liveness"
+	// HealthServiceReadiness is the GRPC health service which is serving once the instance is
+	// ready to serve rate limits, see V1Instance.CheckReady()
+	HealthServiceReadiness = "readiness"
+)
+
+// CheckReady returns nil if the instance is ready to serve rate limits, else the reason it is not.
+// The instance is ready once it has the peers required by `Config.ReadyMinPeers` and
+// `Config.ReadyMinPeersPercent`, has copied the overrides
+// and registered limits of another peer, if any, and the `Config.Store` is reachable if it
+// implements PingStore. Unlike HealthCheck, which reports errors of any peer in the cluster,
+// CheckReady only reports on this instance.
+func (s *V1Instance) CheckReady(ctx context.Context) error {
+	if !s.Ready() {
+		return errors.New(s.waitingFor())
+	}
+	for _, peer := range s.GetPeerList() {
+		if peer.Info().IsOwner {
+			continue
+		}
+		if !s.overridesSynced.Load() || !s.limitsSynced.Load() {
+			return errors.New("waiting to copy the overrides and registered limits of a peer")
+		}
+		break
+	}
+	if p, ok := s.conf.Store.(PingStore); ok {
+		if err := p.Ping(ctx); err != nil {
+			return errors.Wrap(err, "store is unreachable")
+		}
+	}
+	return nil
+}
+
+// healthServer implements the GRPC health protocol with separate liveness and readiness services
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	instance *V1Instance
+}
+
+func newHealthServer(instance *V1Instance) *healthServer {
+	return &healthServer{instance: instance}
+}
+
+func (h *healthServer) Check(ctx context.Context, r *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch r.Service {
+	case "", HealthServiceLiveness:
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	case HealthServiceReadiness:
+		if err := h.instance.CheckReady(ctx); err != nil {
+			return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}, nil
+		}
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "unknown health service '%s'", r.Service)
+}
+
+// livenessHandler answers '200 OK' as long as the HTTP listener is serving
+func livenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
+
+// readinessHandler answers '200 OK' if the instance is ready, else '503 Service Unavailable'
+// with the reason it is not ready.
+func readinessHandler(instance *V1Instance) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := instance.CheckReady(r.Context()); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// The max number of levels of a hierarchy, each level is a round trip to its owner
+const maxHierarchyLevels = 10
+
+// GetRateLimitHierarchy consumes the hits from every level of the hierarchy or from none of them.
+// Unlike a transaction, the levels are reserved one after the other in the enforcement order and
+// the first level which is over the limit stops the evaluation, such that the levels after it are
+// never consumed from, not even briefly. Once every level is reserved the reservations are
+// committed, should this instance fail before then the owning peers return the held hits to the
+// rate limits once the reservations expire.
+func (s *V1Instance) GetRateLimitHierarchy(ctx context.Context, r *GetRateLimitHierarchyReq) (*GetRateLimitHierarchyResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetRateLimitHierarchy")).ObserveDuration()
+	if err := validateHierarchy(r); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = defaultTransactionTimeout
+	}
+	// Reservations made after the deadline would expire before they could be committed
+	deadline := clock.Now().Add(clock.Duration(timeout) * clock.Millisecond)
+	prepareCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	levels := make([]*RateLimitReq, len(r.Levels))
+	for i, level := range r.Levels {
+		levels[i] = proto.Clone(level).(*RateLimitReq)
+		levels[i].Hits = r.Hits
+	}
+	resp := &GetRateLimitHierarchyResp{
+		Status:    Status_UNDER_LIMIT,
+		Responses: make([]*RateLimitResp, len(levels)),
+	}
+	reservations := make([]*ReservationReq, len(levels))
+
+	// Prepare: hold the hits of each level on its owner, stopping at the first level over the limit
+	commit := true
+	for _, i := range hierarchyOrder(r.Order, len(levels)) {
+		reserved, err := s.ReserveRateLimit(prepareCtx, &ReserveRateLimitReq{
+			RateLimit: proto.Clone(levels[i]).(*RateLimitReq),
+			Ttl:       timeout,
+		})
+		if err != nil {
+			resp.Responses[i] = &RateLimitResp{Error: err.Error()}
+			commit = false
+			break
+		}
+		resp.Responses[i] = reserved.RateLimit
+		if reserved.ReservationId == "" {
+			commit = false
+			break
+		}
+		reservations[i] = &ReservationReq{
+			Name:          levels[i].Name,
+			UniqueKey:     levels[i].UniqueKey,
+			ReservationId: reserved.ReservationId,
+		}
+	}
+	if !clock.Now().Before(deadline) {
+		commit = false
+	}
+
+	s.releaseReservations(deadline, levels, reservations, resp.Responses, commit)
+	if commit {
+		resp.Status = committedStatus(resp.Responses)
+		if resp.Status == Status_UNKNOWN {
+			metricHierarchyCounter.WithLabelValues("partial").Inc()
+			return resp, nil
+		}
+		metricHierarchyCounter.WithLabelValues("applied").Inc()
+		return resp, nil
+	}
+
+	resp.Status = Status_OVER_LIMIT
+	metricHierarchyCounter.WithLabelValues("over_limit").Inc()
+	s.fillSkippedLevels(ctx, levels, resp.Responses)
+	return resp, nil
+}
+
+// fillSkippedLevels sets the current state of the levels which were not evaluated
+// because an earlier level was over the limit.
+func (s *V1Instance) fillSkippedLevels(ctx context.Context, levels []*RateLimitReq, responses []*RateLimitResp) {
+	var checks []*RateLimitReq
+	var idx []int
+	for i, rl := range responses {
+		if rl != nil {
+			continue
+		}
+		check := proto.Clone(levels[i]).(*RateLimitReq)
+		check.Hits = 0
+		checks = append(checks, check)
+		idx = append(idx, i)
+	}
+	if len(checks) == 0 {
+		return
+	}
+
+	checkResp, err := s.GetRateLimits(ctx, &GetRateLimitsReq{Requests: checks})
+	for j, i := range idx {
+		if err != nil {
+			responses[i] = &RateLimitResp{Error: err.Error()}
+			continue
+		}
+		responses[i] = checkResp.Responses[j]
+	}
+}
+
+// hierarchyOrder returns the indexes of the levels in the order they are evaluated
+func hierarchyOrder(order HierarchyOrder, n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		if order == HierarchyOrder_CHILD_FIRST {
+			idx[i] = n - 1 - i
+		} else {
+			idx[i] = i
+		}
+	}
+	return idx
+}
+
+func validateHierarchy(r *GetRateLimitHierarchyReq) error {
+	switch {
+	case len(r.Levels) == 0:
+		return status.Error(codes.InvalidArgument, "Levels list cannot be empty")
+	case len(r.Levels) > maxHierarchyLevels:
+		return status.Errorf(codes.OutOfRange, "Levels list too large; max size is '%d'", maxHierarchyLevels)
+	case r.Hits <= 0:
+		return status.Error(codes.InvalidArgument, "field 'hits' must be greater than zero")
+	case r.Timeout < 0:
+		return status.Error(codes.InvalidArgument, "field 'timeout' cannot be negative")
+	case r.Order != HierarchyOrder_PARENT_FIRST && r.Order != HierarchyOrder_CHILD_FIRST:
+		return status.Errorf(codes.InvalidArgument, "unknown order '%d'", r.Order)
+	}
+	for i, level := range r.Levels {
+		if level == nil {
+			return status.Errorf(codes.InvalidArgument, "level %d cannot be empty", i)
+		}
+		check := proto.Clone(level).(*RateLimitReq)
+		check.Hits = r.Hits
+		if err := validateReserve(&ReserveRateLimitReq{RateLimit: check, Ttl: 1}); err != nil {
+			return status.Errorf(codes.InvalidArgument, "level %d: %s", i, status.Convert(err).Message())
+		}
+	}
+	return nil
+}
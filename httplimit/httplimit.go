@@ -0,0 +1,188 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httplimit provides net/http middleware which rate limits requests with gubernator,
+// either by calling a gubernator cluster or an instance embedded in the service.
+//
+//	client, _ := gubernator.DialV1Server("gubernator:1051", nil)
+//	limit := httplimit.New(httplimit.Config{
+//		Limiter: httplimit.FromClient(client),
+//		RateLimit: &gubernator.RateLimitReq{
+//			Name:     "requests_per_minute",
+//			Limit:    100,
+//			Duration: gubernator.Minute,
+//		},
+//		Key: httplimit.ByIP,
+//	})
+//	http.ListenAndServe(":8080", limit(mux))
+package httplimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	gubernator "github.com/gubernator-io/gubernator/v2/proto"
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Limiter evaluates rate limits. An embedded *gubernator.V1Instance is a Limiter, use FromClient()
+// to evaluate rate limits with a gubernator.V1Client.
+type Limiter interface {
+	GetRateLimits(context.Context, *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error)
+}
+
+type clientLimiter struct {
+	client gubernator.V1Client
+	opts   []grpc.CallOption
+}
+
+func (c clientLimiter) GetRateLimits(ctx context.Context, r *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error) {
+	return c.client.GetRateLimits(ctx, r, c.opts...)
+}
+
+// FromClient returns a Limiter which evaluates rate limits with `client`
+func FromClient(client gubernator.V1Client, opts ...grpc.CallOption) Limiter {
+	return clientLimiter{client: client, opts: opts}
+}
+
+// KeyFunc returns the unique key of the rate limit a request is counted against. Requests for
+// which the KeyFunc returns an empty key are not rate limited.
+type KeyFunc func(r *http.Request) string
+
+// ByIP is a KeyFunc which rate limits each client IP address. The address is the remote address of
+// the connection, as such requests which arrive through a proxy must be keyed by a header the proxy
+// sets, IE: ByHeader("X-Real-IP").
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ByHeader returns a KeyFunc which rate limits each value of the header `name`, IE: an API key
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// ByBearerToken is a KeyFunc which rate limits each bearer token of the Authorization header. The
+// token is hashed, such that it is never sent to gubernator or recorded as a unique key.
+func ByBearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Config configures the middleware returned by New()
+type Config struct {
+	// (Required) Evaluates the rate limits, see FromClient()
+	Limiter Limiter
+
+	// (Required) The rate limit each request is counted against. The UniqueKey is set to the key
+	// returned by `Key` for each request, and Hits defaults to 1.
+	RateLimit *gubernator.RateLimitReq
+
+	// (Optional) Returns the unique key of each request. Defaults to ByIP
+	Key KeyFunc
+
+	// (Optional) Serves the requests which are over the limit, after the Retry-After header is set.
+	// Defaults to responding with `429 Too Many Requests`
+	OverLimit http.Handler
+
+	// (Optional) If true, requests whose rate limit could not be evaluated, IE: gubernator is
+	// unavailable, are rejected with `503 Service Unavailable`. Defaults to false (such requests
+	// are allowed)
+	FailClosed bool
+}
+
+// New returns middleware which counts each request against the rate limit described by `conf`,
+// and responds with `429 Too Many Requests` and a Retry-After header once the rate limit is over
+// the limit. Every response includes the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers.
+func New(conf Config) func(http.Handler) http.Handler {
+	if conf.Key == nil {
+		conf.Key = ByIP
+	}
+	if conf.OverLimit == nil {
+		conf.OverLimit = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		})
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := conf.Key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			req := proto.Clone(conf.RateLimit).(*gubernator.RateLimitReq)
+			req.UniqueKey = key
+			if req.Hits == 0 {
+				req.Hits = 1
+			}
+			resp, err := conf.Limiter.GetRateLimits(r.Context(), &gubernator.GetRateLimitsReq{
+				Requests: []*gubernator.RateLimitReq{req},
+			})
+			var rl *gubernator.RateLimitResp
+			if err == nil && len(resp.Responses) == 1 && resp.Responses[0].Error == "" {
+				rl = resp.Responses[0]
+			}
+			if rl == nil {
+				if conf.FailClosed {
+					http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("X-RateLimit-Limit", strconv.FormatInt(rl.Limit, 10))
+			h.Set("X-RateLimit-Remaining", strconv.FormatInt(rl.Remaining, 10))
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(rl.ResetTime/1000, 10))
+			if rl.Status == gubernator.Status_OVER_LIMIT {
+				h.Set("Retry-After", strconv.FormatInt(retryAfter(rl.ResetTime), 10))
+				conf.OverLimit.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// retryAfter returns the seconds until `resetTime` in unix epoch milliseconds, rounded up and at
+// least 1 second.
+func retryAfter(resetTime int64) int64 {
+	ms := resetTime - clock.Now().UnixMilli()
+	if ms <= 1000 {
+		return 1
+	}
+	return (ms + 999) / 1000
+}
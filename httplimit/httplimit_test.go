@@ -0,0 +1,167 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httplimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gubernator-io/gubernator/v2"
+	"github.com/gubernator-io/gubernator/v2/httplimit"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLimiter allows `limit` hits of each unique key, which reset 1.5 seconds from now
+type fakeLimiter struct {
+	limit int64
+	hits  map[string]int64
+	err   error
+}
+
+func (f *fakeLimiter) GetRateLimits(_ context.Context, r *gubernator.GetRateLimitsReq) (*gubernator.GetRateLimitsResp, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	req := r.Requests[0]
+	f.hits[req.UniqueKey] += req.Hits
+	rl := &gubernator.RateLimitResp{
+		Limit:     f.limit,
+		Remaining: f.limit - f.hits[req.UniqueKey],
+		ResetTime: clock.Now().Add(1500 * time.Millisecond).UnixMilli(),
+	}
+	if rl.Remaining < 0 {
+		rl.Status = gubernator.Status_OVER_LIMIT
+		rl.Remaining = 0
+	}
+	return &gubernator.GetRateLimitsResp{Responses: []*gubernator.RateLimitResp{rl}}, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func(conf httplimit.Config, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		httplimit.New(conf)(ok).ServeHTTP(w, r)
+		return w
+	}
+	newConfig := func(l httplimit.Limiter) httplimit.Config {
+		return httplimit.Config{
+			Limiter:   l,
+			RateLimit: &gubernator.RateLimitReq{Name: "requests", Limit: 2, Duration: gubernator.Second},
+		}
+	}
+
+	t.Run("Over the limit", func(t *testing.T) {
+		conf := newConfig(&fakeLimiter{limit: 2, hits: map[string]int64{}})
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "10.0.0.1:4321"
+
+		for _, remaining := range []string{"1", "0"} {
+			w := serve(conf, r)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+			assert.Equal(t, remaining, w.Header().Get("X-RateLimit-Remaining"))
+			assert.Empty(t, w.Header().Get("Retry-After"))
+		}
+		w := serve(conf, r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+		// Another IP has its own limit
+		r.RemoteAddr = "10.0.0.2:4321"
+		assert.Equal(t, http.StatusOK, serve(conf, r).Code)
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		limiter := &fakeLimiter{limit: 1, hits: map[string]int64{}}
+		conf := newConfig(limiter)
+		conf.Key = httplimit.ByBearerToken
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		assert.Equal(t, http.StatusOK, serve(conf, r).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(conf, r).Code)
+		for key := range limiter.hits {
+			assert.NotContains(t, key, "secret")
+		}
+
+		conf.Key = httplimit.ByHeader("X-Api-Key")
+		r.Header.Set("X-Api-Key", "account:1234")
+		assert.Equal(t, http.StatusOK, serve(conf, r).Code)
+		assert.Equal(t, int64(1), limiter.hits["account:1234"])
+
+		// Requests without a key are not limited
+		r.Header.Del("X-Api-Key")
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, serve(conf, r).Code)
+		}
+		assert.NotContains(t, limiter.hits, "")
+	})
+
+	t.Run("Over limit handler", func(t *testing.T) {
+		conf := newConfig(&fakeLimiter{limit: 0, hits: map[string]int64{}})
+		conf.OverLimit = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		w := serve(conf, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		conf := newConfig(&fakeLimiter{err: errors.New("connection refused")})
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		assert.Equal(t, http.StatusOK, serve(conf, r).Code)
+
+		conf.FailClosed = true
+		assert.Equal(t, http.StatusServiceUnavailable, serve(conf, r).Code)
+	})
+}
+
+func TestMiddlewareEmbedded(t *testing.T) {
+	d, err := gubernator.SpawnDaemon(context.Background(), gubernator.DaemonConfig{
+		GRPCListenAddress: "localhost:0",
+		HTTPListenAddress: "localhost:0",
+	})
+	require.NoError(t, err)
+	defer d.Close()
+	d.PeerInfo = gubernator.PeerInfo{GRPCAddress: d.GRPCListeners[0].Addr().String(), IsOwner: true}
+	d.SetPeers([]gubernator.PeerInfo{d.PeerInfo})
+
+	limit := httplimit.New(httplimit.Config{
+		Limiter:   d.V1Server,
+		RateLimit: &gubernator.RateLimitReq{Name: "embedded", Limit: 1, Duration: gubernator.Minute},
+	})(http.NotFoundHandler())
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	limit.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	limit.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
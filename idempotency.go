@@ -0,0 +1,83 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataIdempotentReplay is set to "true" in the metadata of a RateLimitResp which was returned
+// for a request whose idempotency key was already evaluated, see RateLimitReq.IdempotencyKey.
+const MetadataIdempotentReplay = "idempotent_replay"
+
+// idempotentResult is the response to a request with an idempotency key, which is returned
+// instead of applying the hits again if the request is retried before `expireAt`.
+type idempotentResult struct {
+	resp     *RateLimitResp
+	expireAt int64
+}
+
+func idempotencyKey(hashKey string, r *RateLimitReq) string {
+	return hashKey + "_" + r.IdempotencyKey
+}
+
+// replayIdempotent returns a copy of the response to a previous request with the same
+// idempotency key, if it was evaluated within `BehaviorConfig.IdempotencyWindow`.
+func (worker *Worker) replayIdempotent(r *RateLimitReq) (*RateLimitResp, bool) {
+	if r.IdempotencyKey == "" {
+		return nil, false
+	}
+	res, ok := worker.idempotent[idempotencyKey(worker.conf.HashKey(r), r)]
+	if !ok || res.expireAt <= epochMillis(clock.Now()) {
+		return nil, false
+	}
+	resp := proto.Clone(res.resp).(*RateLimitResp)
+	if resp.Metadata == nil {
+		resp.Metadata = make(map[string]string)
+	}
+	resp.Metadata[MetadataIdempotentReplay] = "true"
+	metricIdempotentReplayCounter.Inc()
+	return resp, true
+}
+
+// addIdempotent remembers the response to a request with an idempotency key for
+// `BehaviorConfig.IdempotencyWindow`.
+func (worker *Worker) addIdempotent(r *RateLimitReq, rl *RateLimitResp) {
+	if r.IdempotencyKey == "" {
+		return
+	}
+	worker.idempotent[idempotencyKey(worker.conf.HashKey(r), r)] = &idempotentResult{
+		resp:     proto.Clone(rl).(*RateLimitResp),
+		expireAt: epochMillis(clock.Now().Add(worker.conf.Behaviors.IdempotencyWindow)),
+	}
+}
+
+func (worker *Worker) expireIdempotent() {
+	now := epochMillis(clock.Now())
+	for key, res := range worker.idempotent {
+		if res.expireAt <= now {
+			delete(worker.idempotent, key)
+		}
+	}
+}
+
+// isIdempotentReplay returns true if the response was returned by replayIdempotent, such
+// hits were already accounted for and must not be sent to other peers again.
+func isIdempotentReplay(rl *RateLimitResp) bool {
+	return rl != nil && rl.Metadata[MetadataIdempotentReplay] == "true"
+}
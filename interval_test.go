@@ -127,6 +127,17 @@ func TestGregorianExpirationYear(t *testing.T) {
 	assert.Equal(t, int64(1577836799999), expire)
 }
 
+func TestGregorianDuration(t *testing.T) {
+	now := clock.Date(2019, clock.February, 11, 22, 2, 23, 0, clock.UTC)
+	d, err := gubernator.GregorianDuration(now, gubernator.GregorianMonths)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(28*24*clock.Hour/clock.Millisecond), d)
+
+	d, err = gubernator.GregorianDuration(now, gubernator.GregorianYears)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(365*24*clock.Hour/clock.Millisecond), d)
+}
+
 func TestGregorianExpirationInvalid(t *testing.T) {
 	now := clock.Date(2019, clock.January, 1, 00, 00, 00, 00, clock.UTC)
 	expire, err := gubernator.GregorianExpiration(now, 99)
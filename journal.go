@@ -0,0 +1,434 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataJournalReplay is set in the metadata of the hits replayed by AdminV1.ReplayJournal, such
+// that the owner does not record them in its journal a second time.
+const MetadataJournalReplay = "journal_replay"
+
+// Identifies a journal segment and the version of the encoding. Hits in a segment are prefixed by
+// their length and CRC-32 and encoded as a RateLimitReq.
+var journalMagic = []byte("GUBJRNL\x01")
+
+const (
+	// The longest a segment is appended to before the journal starts a new segment
+	journalSegmentDuration = time.Hour
+	journalSegmentPrefix   = "journal-"
+	journalSegmentSuffix   = ".log"
+)
+
+// hitJournal appends every hit applied by the owner of a rate limit to segment files in
+// `Config.JournalDir`, such that the rate limits lost with an instance can be approximately
+// reconstructed by AdminV1.ReplayJournal. Hits are buffered and written at least once a second,
+// as such the last second of hits before a crash may be lost. Hits are dropped and counted by the
+// `gubernator_journal_dropped_counter` metric if the buffer is full or the write fails. Segments
+// older than `Config.JournalRetention` are removed.
+type hitJournal struct {
+	dir       string
+	retention time.Duration
+	segment   time.Duration
+	log       FieldLogger
+	hits      chan *RateLimitReq
+	// The segment being appended to and the time it was started
+	file    *os.File
+	started time.Time
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+func newHitJournal(conf Config) (*hitJournal, error) {
+	if err := os.MkdirAll(conf.JournalDir, 0o700); err != nil {
+		return nil, errors.Wrapf(err, "while creating journal directory '%s'", conf.JournalDir)
+	}
+	j := &hitJournal{
+		dir:       conf.JournalDir,
+		retention: conf.JournalRetention,
+		segment:   journalSegmentDuration,
+		log:       conf.Logger,
+		hits:      make(chan *RateLimitReq, 10_000),
+	}
+	if j.retention < j.segment {
+		j.segment = j.retention
+	}
+	j.wg.Add(1)
+	go j.run()
+	return j, nil
+}
+
+// record queues a hit to be appended to the journal, it never blocks
+func (j *hitJournal) record(r *RateLimitReq) {
+	hit := &RateLimitReq{
+		Name:        r.Name,
+		UniqueKey:   r.UniqueKey,
+		Hits:        r.Hits,
+		Limit:       r.Limit,
+		Duration:    r.Duration,
+		Algorithm:   r.Algorithm,
+		Behavior:    r.Behavior,
+		Burst:       r.Burst,
+		MaxCapacity: r.MaxCapacity,
+	}
+	if r.CreatedAt != nil {
+		createdAt := *r.CreatedAt
+		hit.CreatedAt = &createdAt
+	}
+	select {
+	case j.hits <- hit:
+	default:
+		metricJournalDropped.Inc()
+	}
+}
+
+func (j *hitJournal) run() {
+	defer j.wg.Done()
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+	var batch []*RateLimitReq
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := j.write(batch); err != nil {
+			metricJournalDropped.Add(float64(len(batch)))
+			j.log.WithError(err).Errorf("while writing '%d' hits to the journal", len(batch))
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case hit, ok := <-j.hits:
+			if !ok {
+				flush()
+				if j.file != nil {
+					_ = j.file.Close()
+				}
+				return
+			}
+			batch = append(batch, hit)
+			if len(batch) >= 1_000 {
+				flush()
+			}
+		case <-ticker.C():
+			flush()
+		}
+	}
+}
+
+// write appends the hits to the current segment and syncs it to disk
+func (j *hitJournal) write(hits []*RateLimitReq) error {
+	if err := j.rotate(); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(j.file)
+	header := make([]byte, 8)
+	for _, hit := range hits {
+		b, err := proto.Marshal(hit)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(header[0:4], uint32(len(b)))
+		binary.LittleEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(b))
+		if _, err := w.Write(header); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return j.file.Sync()
+}
+
+// rotate starts a new segment once the current segment is older than `segment` and removes the
+// segments which are older than the retention.
+func (j *hitJournal) rotate() error {
+	now := clock.Now()
+	if j.file != nil && now.Sub(j.started) < j.segment {
+		return nil
+	}
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+
+	path := filepath.Join(j.dir, fmt.Sprintf("%s%d%s", journalSegmentPrefix, epochMillis(now), journalSegmentSuffix))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "while creating journal segment '%s'", path)
+	}
+	if _, err := f.Write(journalMagic); err != nil {
+		_ = f.Close()
+		return errors.Wrapf(err, "while writing journal segment '%s'", path)
+	}
+	j.file, j.started = f, now
+	j.prune(now)
+	return nil
+}
+
+// prune removes the segments whose last hit is older than the retention
+func (j *hitJournal) prune(now time.Time) {
+	segments, err := j.segments()
+	if err != nil {
+		j.log.WithError(err).Warn("while removing expired journal segments")
+		return
+	}
+	expired := epochMillis(now.Add(-j.retention - j.segment))
+	for _, s := range segments {
+		if s.started >= expired {
+			continue
+		}
+		if err := os.Remove(s.path); err != nil {
+			j.log.WithError(err).Warnf("while removing expired journal segment '%s'", s.path)
+		}
+	}
+}
+
+type journalSegment struct {
+	path    string
+	started int64
+}
+
+// segments returns the segments in the journal directory, oldest first
+func (j *hitJournal) segments() ([]journalSegment, error) {
+	entries, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading journal directory '%s'", j.dir)
+	}
+	var segments []journalSegment
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, journalSegmentPrefix) || !strings.HasSuffix(name, journalSegmentSuffix) {
+			continue
+		}
+		started, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, journalSegmentPrefix), journalSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, journalSegment{path: filepath.Join(j.dir, name), started: started})
+	}
+	sort.Slice(segments, func(a, b int) bool { return segments[a].started < segments[b].started })
+	return segments, nil
+}
+
+// read calls `fn` with each hit in the journal in the order they were recorded. A segment which
+// ends with a partially written or corrupt hit, IE: the instance crashed while writing, is read up
+// to the corrupt hit.
+func (j *hitJournal) read(fn func(*RateLimitReq)) error {
+	segments, err := j.segments()
+	if err != nil {
+		return err
+	}
+	for _, s := range segments {
+		if err := readJournalSegment(s.path, fn); err != nil {
+			j.log.WithError(err).Warnf("ignoring the remainder of journal segment '%s'", s.path)
+		}
+	}
+	return nil
+}
+
+func readJournalSegment(path string, fn func(*RateLimitReq)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Removed by prune() since the directory was read
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(journalMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, journalMagic) {
+		return errors.New("not a journal segment")
+	}
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "truncated hit")
+		}
+		b := make([]byte, binary.LittleEndian.Uint32(header[0:4]))
+		if _, err := io.ReadFull(r, b); err != nil {
+			return errors.Wrap(err, "truncated hit")
+		}
+		if crc32.ChecksumIEEE(b) != binary.LittleEndian.Uint32(header[4:8]) {
+			return errors.New("corrupt hit; checksum mismatch")
+		}
+		var hit RateLimitReq
+		if err := proto.Unmarshal(b, &hit); err != nil {
+			return errors.Wrap(err, "corrupt hit")
+		}
+		fn(&hit)
+	}
+}
+
+// close writes the buffered hits. record() must not be called after close()
+func (j *hitJournal) close() {
+	j.once.Do(func() {
+		close(j.hits)
+		j.wg.Wait()
+	})
+}
+
+// ReplayJournal replays the journal of every peer in the local data center to the peers which
+// currently own the rate limits.
+func (s *V1Instance) ReplayJournal(ctx context.Context, r *ReplayJournalReq) (*ReplayJournalResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReplayJournal")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if s.journal == nil {
+		return nil, status.Error(codes.FailedPrecondition, "the journal is disabled; see Config.JournalDir")
+	}
+
+	var (
+		resp  ReplayJournalResp
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *ReplayJournalResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.ReplayPeerJournal(ctx, r)
+			} else {
+				peerResp, err = peer.ReplayPeerJournal(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			resp.Replayed += peerResp.Replayed
+			resp.Expired += peerResp.Expired
+			resp.Failed += peerResp.Failed
+			resp.Errors = append(resp.Errors, peerResp.Errors...)
+		}(peer)
+	}
+	wg.Wait()
+
+	s.log.WithField("since", r.Since).
+		WithField("name_prefix", r.NamePrefix).
+		WithField("replayed", resp.Replayed).
+		WithField("errors", len(resp.Errors)).
+		Warn("journal replayed via admin API")
+	return &resp, nil
+}
+
+// ReplayPeerJournal is called by other peers to replay the journal of this peer. The hits are sent
+// to their owners in the order they were recorded, with the time they were originally created.
+// Hits whose rate limit duration has elapsed are skipped as they no longer count against the limit.
+func (s *V1Instance) ReplayPeerJournal(ctx context.Context, r *ReplayJournalReq) (*ReplayJournalResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReplayPeerJournal")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	resp := &ReplayJournalResp{}
+	// A peer which does not journal has nothing to replay
+	if s.journal == nil {
+		return resp, nil
+	}
+
+	now := MillisecondNow()
+	batches := make(map[*PeerClient][]*RateLimitReq)
+	send := func(peer *PeerClient) {
+		reqs := batches[peer]
+		delete(batches, peer)
+		var peerResp *GetPeerRateLimitsResp
+		var err error
+		if peer.Info().IsOwner {
+			peerResp, err = s.GetPeerRateLimits(ctx, &GetPeerRateLimitsReq{Requests: reqs})
+		} else {
+			peerResp, err = peer.GetPeerRateLimits(ctx, &GetPeerRateLimitsReq{Requests: reqs})
+		}
+		if err != nil {
+			resp.Failed += int64(len(reqs))
+			resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+			return
+		}
+		for _, rl := range peerResp.RateLimits {
+			if rl.Error != "" {
+				resp.Failed++
+				continue
+			}
+			resp.Replayed++
+		}
+	}
+
+	err := s.journal.read(func(hit *RateLimitReq) {
+		if hit.GetCreatedAt() < r.Since || !strings.HasPrefix(hit.Name, r.NamePrefix) {
+			return
+		}
+		if !HasBehavior(hit.Behavior, Behavior_DURATION_IS_GREGORIAN) && addInt64(hit.GetCreatedAt(), hit.Duration) <= now {
+			resp.Expired++
+			return
+		}
+		peer, err := s.GetPeer(ctx, s.conf.HashKey(hit))
+		if err != nil {
+			resp.Failed++
+			return
+		}
+		hit.Metadata = map[string]string{MetadataJournalReplay: "true"}
+		batches[peer] = append(batches[peer], hit)
+		if len(batches[peer]) >= maxBatchSize {
+			send(peer)
+		}
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for peer := range batches {
+		send(peer)
+	}
+	return resp, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHitJournal(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+
+	j := &hitJournal{
+		dir:       t.TempDir(),
+		retention: 2 * time.Hour,
+		segment:   time.Hour,
+		log:       logrus.WithField("category", "gubernator"),
+	}
+	defer func() { _ = j.file.Close() }()
+	hits := func(from, to int) []*RateLimitReq {
+		var reqs []*RateLimitReq
+		for i := from; i < to; i++ {
+			reqs = append(reqs, &RateLimitReq{Name: "journal", UniqueKey: fmt.Sprintf("account:%d", i), Hits: 1})
+		}
+		return reqs
+	}
+	read := func() []string {
+		var keys []string
+		require.NoError(t, j.read(func(r *RateLimitReq) { keys = append(keys, r.UniqueKey) }))
+		return keys
+	}
+
+	require.NoError(t, j.write(hits(0, 2)))
+	clock.Advance(time.Hour)
+	require.NoError(t, j.write(hits(2, 4)))
+	segments, err := j.segments()
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.Equal(t, []string{"account:0", "account:1", "account:2", "account:3"}, read())
+
+	t.Run("Partially written hit", func(t *testing.T) {
+		_, err := j.file.Write([]byte{0xff, 0xff, 0, 0, 1, 2})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"account:0", "account:1", "account:2", "account:3"}, read())
+	})
+
+	t.Run("Corrupt hit", func(t *testing.T) {
+		b, err := os.ReadFile(segments[0].path)
+		require.NoError(t, err)
+		// Flip a byte of the second hit
+		b[len(b)-1] ^= 0xff
+		require.NoError(t, os.WriteFile(segments[0].path, b, 0o600))
+		assert.Equal(t, []string{"account:0", "account:2", "account:3"}, read())
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		// The first segment is kept until its last hit is older than the retention
+		clock.Advance(2 * time.Hour)
+		require.NoError(t, j.write(hits(4, 5)))
+		assert.Equal(t, []string{"account:0", "account:2", "account:3", "account:4"}, read())
+
+		clock.Advance(time.Hour)
+		require.NoError(t, j.write(hits(5, 6)))
+		assert.Equal(t, []string{"account:2", "account:3", "account:4", "account:5"}, read())
+	})
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/collections"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// keyHistory records the most recent hits applied to each rate limit, such that the decisions of
+// a rate limit can be explained after the fact. Only the rate limits hit most recently are kept.
+type keyHistory struct {
+	mutex sync.Mutex
+	size  int
+	keys  *collections.LRUCache
+}
+
+// keyTransitions is a ring buffer of the most recent hits applied to a rate limit
+type keyTransitions struct {
+	entries []*KeyTransition
+	next    int
+}
+
+func newKeyHistory(size, maxKeys int) *keyHistory {
+	return &keyHistory{
+		size: size,
+		keys: collections.NewLRUCache(maxKeys),
+	}
+}
+
+// record adds the hits applied to the rate limit, replacing the oldest hits once the ring is full
+func (h *keyHistory) record(key string, r *RateLimitReq, resp *RateLimitResp, owner string) {
+	t := &KeyTransition{
+		Time:      epochMillis(clock.Now()),
+		Hits:      r.Hits,
+		Status:    resp.Status,
+		Remaining: resp.Remaining,
+		Limit:     resp.Limit,
+		Owner:     owner,
+		RequestId: r.RequestId,
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var ring *keyTransitions
+	if v, ok := h.keys.Get(key); ok {
+		ring = v.(*keyTransitions)
+	} else {
+		ring = &keyTransitions{entries: make([]*KeyTransition, 0, h.size)}
+		h.keys.Add(key, ring)
+	}
+	if len(ring.entries) < h.size {
+		ring.entries = append(ring.entries, t)
+		return
+	}
+	ring.entries[ring.next] = t
+	ring.next = (ring.next + 1) % h.size
+}
+
+// list returns the hits recorded for the rate limit, oldest first
+func (h *keyHistory) list(key string) []*KeyTransition {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	v, ok := h.keys.Peek(key)
+	if !ok {
+		return nil
+	}
+	ring := v.(*keyTransitions)
+	result := make([]*KeyTransition, 0, len(ring.entries))
+	for i := range ring.entries {
+		result = append(result, ring.entries[(ring.next+i)%len(ring.entries)])
+	}
+	return result
+}
+
+// recordHistory records the hits applied to the rate limit with the peer which owns it at the time
+func (s *V1Instance) recordHistory(ctx context.Context, r *RateLimitReq, resp *RateLimitResp) {
+	key := s.conf.HashKey(r)
+	var owner string
+	if peer, err := s.GetPeer(ctx, key); err == nil {
+		owner = peer.Info().GRPCAddress
+	}
+	s.history.record(key, r, resp, owner)
+}
+
+// GetKeyHistory returns the state of the rate limit and the hits recorded by every peer in the
+// local data center, as the rate limit may have moved between peers.
+func (s *V1Instance) GetKeyHistory(ctx context.Context, r *GetKeyHistoryReq) (*GetKeyHistoryResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetKeyHistory")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if err := validateKeyHistory(r); err != nil {
+		return nil, err
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	resp := &GetKeyHistoryResp{}
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *GetKeyHistoryResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.GetPeerKeyHistory(ctx, r)
+			} else {
+				peerResp, err = peer.GetPeerKeyHistory(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			// Prefer the state of the owner over the copies of GLOBAL rate limits
+			if rl := peerResp.RateLimit; rl != nil && (resp.RateLimit == nil || rl.Owned) {
+				resp.RateLimit = rl
+			}
+			for _, t := range peerResp.Transitions {
+				t.Peer = peer.Info().GRPCAddress
+				resp.Transitions = append(resp.Transitions, t)
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	sort.SliceStable(resp.Transitions, func(i, j int) bool {
+		return resp.Transitions[i].Time < resp.Transitions[j].Time
+	})
+	return resp, nil
+}
+
+// GetPeerKeyHistory is called by other peers to collect the state and the hits recorded by this peer.
+func (s *V1Instance) GetPeerKeyHistory(ctx context.Context, r *GetKeyHistoryReq) (*GetKeyHistoryResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeerKeyHistory")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if err := validateKeyHistory(r); err != nil {
+		return nil, err
+	}
+
+	key := s.conf.HashKey(&RateLimitReq{Name: r.Name, UniqueKey: r.UniqueKey})
+	resp := &GetKeyHistoryResp{}
+	item, found, err := s.workerPool.GetCacheItem(ctx, key)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if found && !item.IsExpired() {
+		resp.RateLimit = s.rateLimitState(ctx, item)
+	}
+	if s.history != nil {
+		for _, t := range s.history.list(key) {
+			resp.Transitions = append(resp.Transitions, proto.Clone(t).(*KeyTransition))
+		}
+	}
+	return resp, nil
+}
+
+func validateKeyHistory(r *GetKeyHistoryReq) error {
+	switch {
+	case r.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case r.UniqueKey == "":
+		return status.Error(codes.InvalidArgument, "field 'unique_key' cannot be empty")
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Policies for a unique key longer than Config.MaxKeyLength, see Config.LongKeyPolicy
+const (
+	// LongKeyReject responds with ErrKeyTooLong
+	LongKeyReject = "reject"
+	// LongKeyHash replaces the unique key with the SHA-256 digest of the key, IE: "sha256:9f86d0..."
+	LongKeyHash = "hash"
+)
+
+// ErrKeyTooLong prefixes the error of a rate limit whose name is longer than Config.MaxNameLength,
+// or whose unique key is longer than Config.MaxKeyLength when Config.LongKeyPolicy is LongKeyReject
+const ErrKeyTooLong = "KEY_TOO_LONG"
+
+// hashedKeyPrefix prefixes the unique keys replaced by LongKeyHash
+const hashedKeyPrefix = "sha256:"
+
+// hashedKeyLength is the length of the unique keys replaced by LongKeyHash
+const hashedKeyLength = len(hashedKeyPrefix) + sha256.Size*2
+
+// limitKeyLength enforces Config.MaxNameLength and Config.MaxKeyLength on the request. It must be
+// called before the key is hashed, such that the rate limit of a hashed key is owned by the peer
+// which owns the digest.
+func limitKeyLength(conf *Config, r *RateLimitReq) error {
+	if conf.MaxNameLength != 0 && len(r.Name) > conf.MaxNameLength {
+		metricLongKeyCounter.WithLabelValues("rejected").Inc()
+		return fmt.Errorf("%s; field 'namespace' of length '%d' exceeds the max length '%d'",
+			ErrKeyTooLong, len(r.Name), conf.MaxNameLength)
+	}
+	if conf.MaxKeyLength == 0 || len(r.UniqueKey) <= conf.MaxKeyLength {
+		return nil
+	}
+	if conf.LongKeyPolicy == LongKeyHash {
+		r.UniqueKey = hashLongKey(conf, r.UniqueKey)
+		return nil
+	}
+	metricLongKeyCounter.WithLabelValues("rejected").Inc()
+	return fmt.Errorf("%s; field 'unique_key' of length '%d' exceeds the max length '%d'",
+		ErrKeyTooLong, len(r.UniqueKey), conf.MaxKeyLength)
+}
+
+// hashLongKey returns the digest of `key` if it is longer than Config.MaxKeyLength and
+// Config.LongKeyPolicy is LongKeyHash, else returns `key`.
+func hashLongKey(conf *Config, key string) string {
+	if conf.MaxKeyLength == 0 || len(key) <= conf.MaxKeyLength || conf.LongKeyPolicy != LongKeyHash {
+		return key
+	}
+	metricLongKeyCounter.WithLabelValues("hashed").Inc()
+	sum := sha256.Sum256([]byte(key))
+	return hashedKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -66,10 +66,13 @@ type K8sPoolConfig struct {
 	Logger    FieldLogger
 	Mechanism WatchMechanism
 	OnUpdate  UpdateFunc
-	Namespace string
-	Selector  string
-	PodIP     string
-	PodPort   string
+	// (Optional) Called before OnUpdate with the number of pods selected, including the pods which
+	// are not ready and as such not passed to OnUpdate
+	OnClusterSize func(size int)
+	Namespace     string
+	Selector      string
+	PodIP         string
+	PodPort       string
 }
 
 func NewK8sPool(conf K8sPoolConfig) (*K8sPool, error) {
@@ -188,8 +191,12 @@ func (e *K8sPool) startEndpointWatch() error {
 func (e *K8sPool) updatePeersFromPods() {
 	e.log.Debug("Fetching peer list from pods API")
 	var peers []PeerInfo
+	objs := e.informer.GetStore().List()
+	if e.conf.OnClusterSize != nil {
+		e.conf.OnClusterSize(len(objs))
+	}
 main:
-	for _, obj := range e.informer.GetStore().List() {
+	for _, obj := range objs {
 		pod, ok := obj.(*api_v1.Pod)
 		if !ok {
 			e.log.Errorf("expected type v1.Endpoints got '%s' instead", reflect.TypeOf(obj).String())
@@ -217,6 +224,7 @@ main:
 func (e *K8sPool) updatePeersFromEndpoints() {
 	e.log.Debug("Fetching peer list from endpoints API")
 	var peers []PeerInfo
+	var size int
 	for _, obj := range e.informer.GetStore().List() {
 		endpoint, ok := obj.(*api_v1.Endpoints)
 		if !ok {
@@ -224,6 +232,7 @@ func (e *K8sPool) updatePeersFromEndpoints() {
 		}
 
 		for _, s := range endpoint.Subsets {
+			size += len(s.Addresses) + len(s.NotReadyAddresses)
 			for _, addr := range s.Addresses {
 				// TODO(thrawn01): Might consider using the `namespace` as the `DataCenter`. We should
 				//  do what ever k8s convention is for identifying a k8s cluster within a federated multi-data
@@ -238,6 +247,9 @@ func (e *K8sPool) updatePeersFromEndpoints() {
 			}
 		}
 	}
+	if e.conf.OnClusterSize != nil {
+		e.conf.OnClusterSize(size)
+	}
 	e.conf.OnUpdate(peers)
 }
 
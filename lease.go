@@ -0,0 +1,148 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lease is held in memory by the worker which owns the lease name. Like reservations, leases
+// are lost if the owning peer restarts or ownership of the lease name moves to another peer,
+// in which case another holder may acquire the lease before the previous lease expired.
+type lease struct {
+	holder   string
+	expireAt int64
+}
+
+// AcquireLease acquires or renews the named lease on the peer which owns the lease name.
+func (s *V1Instance) AcquireLease(ctx context.Context, r *LeaseReq) (*LeaseResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.AcquireLease")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	return s.forwardLease(ctx, r, false)
+}
+
+// ReleaseLease releases the named lease if it is held by the holder.
+func (s *V1Instance) ReleaseLease(ctx context.Context, r *LeaseReq) (*LeaseResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReleaseLease")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	return s.forwardLease(ctx, r, true)
+}
+
+// AcquirePeerLease is called by other peers to acquire a lease owned by this peer.
+func (s *V1Instance) AcquirePeerLease(ctx context.Context, r *LeaseReq) (*LeaseResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.AcquirePeerLease")).ObserveDuration()
+	return s.leaseLocal(ctx, r, false)
+}
+
+// ReleasePeerLease is called by other peers to release a lease owned by this peer.
+func (s *V1Instance) ReleasePeerLease(ctx context.Context, r *LeaseReq) (*LeaseResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReleasePeerLease")).ObserveDuration()
+	return s.leaseLocal(ctx, r, true)
+}
+
+func (s *V1Instance) forwardLease(ctx context.Context, r *LeaseReq, release bool) (*LeaseResp, error) {
+	if err := validateLease(r, release); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+
+	peer, err := s.GetPeer(ctx, r.Name)
+	if err != nil {
+		countError(err, "Error in GetPeer")
+		return nil, status.Errorf(codes.Unavailable, "while looking up peer that owns lease '%s': %s", r.Name, err)
+	}
+	if peer.Info().IsOwner {
+		return s.leaseLocal(ctx, r, release)
+	}
+	if release {
+		return peer.ReleasePeerLease(ctx, r)
+	}
+	return peer.AcquirePeerLease(ctx, r)
+}
+
+func (s *V1Instance) leaseLocal(ctx context.Context, r *LeaseReq, release bool) (*LeaseResp, error) {
+	if err := validateLease(r, release); err != nil {
+		return nil, err
+	}
+	resp, err := s.workerPool.Lease(ctx, r, release)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	return resp, nil
+}
+
+func validateLease(r *LeaseReq, release bool) error {
+	switch {
+	case r.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case r.Holder == "":
+		return status.Error(codes.InvalidArgument, "field 'holder' cannot be empty")
+	case !release && r.Ttl <= 0:
+		return status.Error(codes.InvalidArgument, "field 'ttl' must be greater than zero")
+	}
+	return nil
+}
+
+func (worker *Worker) acquireLease(r *LeaseReq) *LeaseResp {
+	now := epochMillis(clock.Now())
+	l, ok := worker.leases[r.Name]
+	switch {
+	case ok && l.expireAt > now && l.holder != r.Holder:
+		metricLeaseCounter.WithLabelValues("denied").Inc()
+		return &LeaseResp{Holder: l.holder, ExpireAt: l.expireAt}
+	case ok && l.expireAt > now:
+		metricLeaseCounter.WithLabelValues("renewed").Inc()
+	default:
+		l = &lease{holder: r.Holder}
+		worker.leases[r.Name] = l
+		metricLeaseCounter.WithLabelValues("acquired").Inc()
+	}
+
+	l.expireAt = now + r.Ttl
+	return &LeaseResp{Acquired: true, Holder: l.holder, ExpireAt: l.expireAt}
+}
+
+func (worker *Worker) releaseLease(r *LeaseReq) *LeaseResp {
+	l, ok := worker.leases[r.Name]
+	if !ok || l.expireAt <= epochMillis(clock.Now()) {
+		return &LeaseResp{}
+	}
+	if l.holder != r.Holder {
+		return &LeaseResp{Holder: l.holder, ExpireAt: l.expireAt}
+	}
+
+	delete(worker.leases, r.Name)
+	metricLeaseCounter.WithLabelValues("released").Inc()
+	return &LeaseResp{}
+}
+
+func (worker *Worker) expireLeases() {
+	now := epochMillis(clock.Now())
+	for name, l := range worker.leases {
+		if l.expireAt <= now {
+			delete(worker.leases, name)
+			metricLeaseCounter.WithLabelValues("expired").Inc()
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"container/heap"
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultListPageSize = 100
+	maxListPageSize     = 1000
+)
+
+// ListRateLimits returns one page of the rate limits in the cache of this instance, sorted by key.
+// The cursor is the key of the last rate limit of the previous page, such that rate limits added
+// or removed between pages do not cause the following pages to skip or repeat rate limits.
+func (s *V1Instance) ListRateLimits(ctx context.Context, r *ListRateLimitsReq) (*ListRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListRateLimits")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+
+	pageSize := int(r.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultListPageSize
+	case pageSize < 0 || pageSize > maxListPageSize:
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxListPageSize)
+	}
+	after, err := base64.RawURLEncoding.DecodeString(r.Cursor)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+	}
+
+	// Keep the `pageSize + 1` smallest keys after the cursor, the extra key tells us whether
+	// there is a next page without holding every rate limit in the cache.
+	var page keyHeap
+	// The channel must be read until closed
+	for item := range s.workerPool.Each(ctx) {
+		if item.IsExpired() || item.Key <= string(after) {
+			continue
+		}
+		if !strings.HasPrefix(item.Name, r.NamePrefix) ||
+			!strings.HasPrefix(uniqueKeyOf(item), r.UniqueKeyPrefix) {
+			continue
+		}
+		if len(page) <= pageSize {
+			heap.Push(&page, item)
+			continue
+		}
+		if item.Key < page[0].Key {
+			page[0] = item
+			heap.Fix(&page, 0)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	sort.Slice(page, func(i, j int) bool { return page[i].Key < page[j].Key })
+	resp := &ListRateLimitsResp{}
+	if len(page) > pageSize {
+		page = page[:pageSize]
+		resp.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(page[pageSize-1].Key))
+	}
+	for _, item := range page {
+		resp.RateLimits = append(resp.RateLimits, s.rateLimitState(ctx, item))
+	}
+	return resp, nil
+}
+
+func (s *V1Instance) rateLimitState(ctx context.Context, item *CacheItem) *RateLimitState {
+	state := &RateLimitState{
+		Name:      item.Name,
+		UniqueKey: uniqueKeyOf(item),
+		Key:       item.Key,
+		Algorithm: item.Algorithm,
+		ExpireAt:  item.ExpireAt,
+	}
+	if owner, err := s.GetPeer(ctx, item.Key); err == nil {
+		state.Owned = owner.Info().IsOwner
+	}
+
+	switch t := item.Value.(type) {
+	case *TokenBucketItem:
+		state.Status = t.Status
+		state.Limit = t.Limit
+		state.Duration = t.Duration
+		state.Remaining = t.Remaining
+	case *LeakyBucketItem:
+		state.Limit = t.Limit
+		state.Duration = t.Duration
+		state.Remaining = int64(t.Remaining)
+		state.Burst = t.Burst
+	}
+	return state
+}
+
+// uniqueKeyOf returns the unique key of the rate limit, which is only known if the item has a name
+func uniqueKeyOf(item *CacheItem) string {
+	if item.Name == "" {
+		return ""
+	}
+	return strings.TrimPrefix(item.Key, item.Name+"_")
+}
+
+// keyHeap is a max heap of cache items ordered by key
+type keyHeap []*CacheItem
+
+func (h keyHeap) Len() int            { return len(h) }
+func (h keyHeap) Less(i, j int) bool  { return h[i].Key > h[j].Key }
+func (h keyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *keyHeap) Push(x interface{}) { *h = append(*h, x.(*CacheItem)) }
+func (h *keyHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"net"
+	"sync"
+)
+
+// limitListener limits the number of connections accepted from a listener. Once `maxConns`
+// connections are open, Accept() blocks until one of them is closed, leaving new connections in
+// the kernel backlog instead of consuming file descriptors. Connections from a remote IP which
+// already has `maxPerIP` connections open are closed immediately.
+type limitListener struct {
+	net.Listener
+	*connLimits
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// connLimits are the connection limits shared by the listeners of the same address
+type connLimits struct {
+	sem      chan struct{}
+	maxPerIP int
+	mutex    sync.Mutex
+	conns    map[string]int
+}
+
+// newLimitListener returns a listener which limits the total number of connections to
+// `maxConns` and the connections per remote IP to `maxPerIP`. A limit of 0 means no limit.
+func newLimitListener(l net.Listener, maxConns, maxPerIP int) net.Listener {
+	return newLimitListeners([]net.Listener{l}, maxConns, maxPerIP)[0]
+}
+
+// newLimitListeners is newLimitListener for listeners which accept connections on the same
+// address, IE: with SO_REUSEPORT. The limits apply to the connections of all the listeners.
+func newLimitListeners(ls []net.Listener, maxConns, maxPerIP int) []net.Listener {
+	if maxConns <= 0 && maxPerIP <= 0 {
+		return ls
+	}
+	limits := &connLimits{
+		maxPerIP: maxPerIP,
+		conns:    make(map[string]int),
+	}
+	if maxConns > 0 {
+		limits.sem = make(chan struct{}, maxConns)
+	}
+	result := make([]net.Listener, len(ls))
+	for i, l := range ls {
+		result[i] = &limitListener{
+			Listener:   l,
+			connLimits: limits,
+			done:       make(chan struct{}),
+		}
+	}
+	return result
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		if !l.acquire(l.done) {
+			return nil, net.ErrClosed
+		}
+
+		c, err := l.Listener.Accept()
+		if err != nil {
+			l.release()
+			return nil, err
+		}
+
+		ip := remoteIP(c)
+		if !l.acquireIP(ip) {
+			l.release()
+			metricRejectedConnections.Inc()
+			_ = c.Close()
+			continue
+		}
+
+		return &limitConn{Conn: c, release: func() {
+			l.releaseIP(ip)
+			l.release()
+		}}, nil
+	}
+}
+
+func (l *limitListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { close(l.done) })
+	return err
+}
+
+// acquire blocks until a connection may be accepted, or returns false once `done` is closed
+func (l *connLimits) acquire(done chan struct{}) bool {
+	if l.sem == nil {
+		return true
+	}
+	select {
+	case <-done:
+		return false
+	case l.sem <- struct{}{}:
+		return true
+	}
+}
+
+func (l *connLimits) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+func (l *connLimits) acquireIP(ip string) bool {
+	if l.maxPerIP <= 0 {
+		return true
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.conns[ip] >= l.maxPerIP {
+		return false
+	}
+	l.conns[ip]++
+	return true
+}
+
+func (l *connLimits) releaseIP(ip string) {
+	if l.maxPerIP <= 0 {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.conns[ip]--
+	if l.conns[ip] <= 0 {
+		delete(l.conns, ip)
+	}
+}
+
+func remoteIP(c net.Conn) string {
+	host, _, err := net.SplitHostPort(c.RemoteAddr().String())
+	if err != nil {
+		return c.RemoteAddr().String()
+	}
+	return host
+}
+
+type limitConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitListener(t *testing.T) {
+	accept := func(l net.Listener) chan net.Conn {
+		conns := make(chan net.Conn, 10)
+		go func() {
+			for {
+				c, err := l.Accept()
+				if err != nil {
+					close(conns)
+					return
+				}
+				conns <- c
+			}
+		}()
+		return conns
+	}
+
+	t.Run("MaxConnectionsPerIP", func(t *testing.T) {
+		nl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l := newLimitListener(nl, 0, 1)
+		defer l.Close()
+		conns := accept(l)
+
+		first, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer first.Close()
+		accepted := <-conns
+
+		// The second connection from the same IP is closed by the listener
+		second, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer second.Close()
+		_ = second.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, err = second.Read(make([]byte, 1))
+		assert.ErrorIs(t, err, io.EOF)
+
+		// Once the first connection closes, the IP may connect again
+		require.NoError(t, accepted.Close())
+		third, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer third.Close()
+		select {
+		case c := <-conns:
+			_ = c.Close()
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for connection to be accepted")
+		}
+	})
+
+	t.Run("MaxConnections", func(t *testing.T) {
+		nl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		l := newLimitListener(nl, 1, 0)
+		conns := accept(l)
+
+		first, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer first.Close()
+		accepted := <-conns
+
+		// The second connection waits in the backlog until the first is closed
+		second, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+		defer second.Close()
+		select {
+		case <-conns:
+			t.Fatal("connection accepted while at MaxConnections")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		require.NoError(t, accepted.Close())
+		select {
+		case c := <-conns:
+			_ = c.Close()
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for connection to be accepted")
+		}
+
+		// Close unblocks a pending Accept()
+		require.NoError(t, l.Close())
+		for range conns {
+		}
+	})
+
+	t.Run("ReusePort", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("SO_REUSEPORT is not supported on windows")
+		}
+		nls, err := listenReusePort("127.0.0.1:0", 3)
+		require.NoError(t, err)
+		ls := newLimitListeners(nls, 0, 1)
+		conns := make(chan net.Conn, 10)
+		for _, l := range ls {
+			assert.Equal(t, ls[0].Addr().String(), l.Addr().String())
+			defer l.Close()
+			go func(accepted chan net.Conn) {
+				for c := range accepted {
+					conns <- c
+				}
+			}(accept(l))
+		}
+
+		first, err := net.Dial("tcp", ls[0].Addr().String())
+		require.NoError(t, err)
+		defer first.Close()
+		accepted := <-conns
+		defer accepted.Close()
+
+		// The listeners share the limit, whichever listener accepts the second connection closes it
+		for i := 0; i < 5; i++ {
+			c, err := net.Dial("tcp", ls[0].Addr().String())
+			require.NoError(t, err)
+			_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+			_, err = c.Read(make([]byte, 1))
+			assert.ErrorIs(t, err, io.EOF)
+			_ = c.Close()
+		}
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+// weightAdvertiser is implemented by the pools which advertise the weight of this instance to the
+// other peers, IE: EtcdPool and MemberListPool
+type weightAdvertiser interface {
+	SetWeight(weight int) error
+}
+
+// loadShedder advertises a reduced weight while the CPU utilization of this instance exceeds
+// DaemonConfig.LoadSheddingCPUPercent, such that the peers move a share of the rate limits this
+// instance owns to other peers. The weight is restored once the utilization stayed below the
+// threshold for DaemonConfig.LoadSheddingCooldown, which keeps a hot spot near the threshold from
+// moving keys back and forth.
+type loadShedder struct {
+	conf       DaemonConfig
+	advertiser weightAdvertiser
+	log        FieldLogger
+
+	shedding  bool
+	calmSince time.Time // When the utilization last fell below the threshold while shedding
+	lastCPU   time.Duration
+	lastCheck time.Time
+}
+
+func newLoadShedder(conf DaemonConfig, pool PoolInterface, log FieldLogger) (*loadShedder, error) {
+	advertiser, ok := pool.(weightAdvertiser)
+	if !ok {
+		return nil, errors.Errorf("peer discovery '%s' cannot advertise the weight of the peer; "+
+			"load shedding requires 'etcd' or 'member-list'", conf.PeerDiscoveryType)
+	}
+	cpu, err := processCPUTime()
+	if err != nil {
+		return nil, err
+	}
+	return &loadShedder{
+		conf:       conf,
+		advertiser: advertiser,
+		log:        log,
+		lastCPU:    cpu,
+		lastCheck:  clock.Now(),
+	}, nil
+}
+
+// shedWeight returns the weight advertised while shedding load, which is never less than 1
+func shedWeight(conf DaemonConfig) int {
+	w := conf.PeerWeight * conf.LoadSheddingWeightPercent / 100
+	if w < 1 {
+		return 1
+	}
+	return w
+}
+
+// check measures the CPU utilization since the last check, as a percentage of GOMAXPROCS, and
+// advertises a new weight if shedding starts or stops.
+func (l *loadShedder) check() {
+	cpu, err := processCPUTime()
+	if err != nil {
+		l.log.WithError(err).Warn("while measuring CPU time for load shedding")
+		return
+	}
+	now := clock.Now()
+	elapsed := now.Sub(l.lastCheck) * time.Duration(runtime.GOMAXPROCS(0))
+	if elapsed <= 0 {
+		return
+	}
+	percent := int((cpu - l.lastCPU) * 100 / elapsed)
+	l.lastCPU, l.lastCheck = cpu, now
+	metricLoadSheddingCPUPercent.Set(float64(percent))
+
+	l.update(percent, now)
+}
+
+func (l *loadShedder) update(percent int, now time.Time) {
+	if percent > l.conf.LoadSheddingCPUPercent {
+		l.calmSince = time.Time{}
+		if !l.shedding {
+			l.setWeight(shedWeight(l.conf), percent, true)
+		}
+		return
+	}
+	if !l.shedding {
+		return
+	}
+	if l.calmSince.IsZero() {
+		l.calmSince = now
+	}
+	if now.Sub(l.calmSince) >= l.conf.LoadSheddingCooldown {
+		l.setWeight(l.conf.PeerWeight, percent, false)
+	}
+}
+
+func (l *loadShedder) setWeight(weight, percent int, shedding bool) {
+	log := l.log.WithField("cpu_percent", percent).WithField("weight", weight)
+	if err := l.advertiser.SetWeight(weight); err != nil {
+		// Try again on the next check
+		log.WithError(err).Error("while advertising the weight of the peer")
+		return
+	}
+	l.shedding, l.calmSince = shedding, time.Time{}
+	if shedding {
+		metricLoadShedding.Set(1)
+		log.Warn("CPU utilization exceeded the threshold; advertising a reduced weight to shed load")
+		return
+	}
+	metricLoadShedding.Set(0)
+	log.Info("CPU utilization recovered; advertising the configured weight")
+}
+
+// runLoadShedder checks the CPU utilization every LoadSheddingInterval until the daemon is closed
+func (s *Daemon) runLoadShedder() error {
+	l, err := newLoadShedder(s.conf, s.pool, s.log)
+	if err != nil {
+		return err
+	}
+	s.wg.Until(func(done chan struct{}) bool {
+		select {
+		case <-clock.After(s.conf.LoadSheddingInterval):
+			l.check()
+			return true
+		case <-done:
+			return false
+		}
+	})
+	return nil
+}
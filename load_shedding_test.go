@@ -0,0 +1,86 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWeightAdvertiser struct {
+	weights []int
+	err     error
+}
+
+func (m *mockWeightAdvertiser) SetWeight(weight int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.weights = append(m.weights, weight)
+	return nil
+}
+
+func TestLoadShedder(t *testing.T) {
+	advertiser := &mockWeightAdvertiser{}
+	l := &loadShedder{
+		conf: DaemonConfig{
+			PeerWeight:                4,
+			LoadSheddingCPUPercent:    80,
+			LoadSheddingWeightPercent: 50,
+			LoadSheddingCooldown:      time.Minute,
+		},
+		advertiser: advertiser,
+		log:        logrus.WithField("category", "gubernator"),
+	}
+	now := time.Now()
+
+	l.update(50, now)
+	assert.Empty(t, advertiser.weights)
+
+	l.update(95, now)
+	l.update(90, now.Add(10*time.Second))
+	assert.Equal(t, []int{2}, advertiser.weights)
+
+	// A hot spot near the threshold does not restore the weight before the cooldown
+	l.update(70, now.Add(20*time.Second))
+	l.update(85, now.Add(30*time.Second))
+	l.update(70, now.Add(40*time.Second))
+	l.update(70, now.Add(90*time.Second))
+	assert.Equal(t, []int{2}, advertiser.weights)
+
+	l.update(70, now.Add(100*time.Second))
+	assert.Equal(t, []int{2, 4}, advertiser.weights)
+	assert.False(t, l.shedding)
+
+	// A weight which could not be advertised is advertised on the next check
+	advertiser.err = errors.New("etcd unavailable")
+	l.update(95, now.Add(110*time.Second))
+	assert.False(t, l.shedding)
+	advertiser.err = nil
+	l.update(95, now.Add(120*time.Second))
+	assert.Equal(t, []int{2, 4, 2}, advertiser.weights)
+}
+
+func TestShedWeight(t *testing.T) {
+	assert.Equal(t, 2, shedWeight(DaemonConfig{PeerWeight: 4, LoadSheddingWeightPercent: 50}))
+	assert.Equal(t, 1, shedWeight(DaemonConfig{PeerWeight: 2, LoadSheddingWeightPercent: 10}))
+	assert.Equal(t, 1, shedWeight(DaemonConfig{PeerWeight: 1, LoadSheddingWeightPercent: 50}))
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// The field of the summary logged for the messages suppressed by log sampling
+const logSuppressedField = "suppressed"
+
+// LogSamplingConfig limits the warnings and errors logged with the same level and message, such
+// that a storm of identical errors, IE: while a peer is down, does not flood the logs. Once Burst
+// messages were logged within Interval the rest are counted, and a single "suppressed N similar
+// messages" summary is logged for them at the end of the interval.
+type LogSamplingConfig struct {
+	// (Optional) The number of warnings and errors with the same level and message logged per
+	// Interval. Defaults to 0 (every message is logged)
+	Burst int
+
+	// (Optional) How often the count of each message is reset and the summary of the suppressed
+	// messages is logged. Defaults to 10 seconds
+	Interval time.Duration
+}
+
+func (c *LogSamplingConfig) validate() error {
+	if c.Burst < 0 {
+		return errors.New("LogSampling.Burst cannot be negative")
+	}
+	if c.Interval < 0 {
+		return errors.New("LogSampling.Interval cannot be negative")
+	}
+	setter.SetDefault(&c.Interval, 10*time.Second)
+	return nil
+}
+
+// logSampler is the formatter of a logger which drops the warnings and errors of a message logged
+// more than `Burst` times per interval, see LogSamplingConfig
+type logSampler struct {
+	formatter logrus.Formatter
+	// Logs the summaries with the fields of the logger which was copied
+	log *logrus.Entry
+	// The logger which was copied by newSampledLogger()
+	unsampled FieldLogger
+	burst     int
+	interval  time.Duration
+	mutex     sync.Mutex
+	messages  map[logSignature]*logCount
+	wg        sync.WaitGroup
+	done      chan struct{}
+}
+
+type logSignature struct {
+	level   logrus.Level
+	message string
+}
+
+type logCount struct {
+	logged     int
+	suppressed int
+}
+
+// newSampledLogger returns a copy of `log` whose warnings and errors are sampled, such that the
+// logger shared with the rest of the process is not changed. Returns `log` unchanged and a nil
+// sampler if `log` is not a logrus logger.
+func newSampledLogger(log FieldLogger, conf LogSamplingConfig) (FieldLogger, *logSampler) {
+	var base *logrus.Logger
+	var fields logrus.Fields
+	switch l := log.(type) {
+	case *logrus.Entry:
+		base, fields = l.Logger, l.Data
+	case *logrus.Logger:
+		base = l
+	default:
+		log.Warn("log sampling requires a logrus logger; every message is logged")
+		return log, nil
+	}
+
+	logger := logrus.New()
+	logger.Out = base.Out
+	logger.Hooks = base.Hooks
+	logger.Level = base.GetLevel()
+	logger.ReportCaller = base.ReportCaller
+	logger.ExitFunc = base.ExitFunc
+	s := &logSampler{
+		formatter: base.Formatter,
+		log:       logger.WithFields(fields),
+		unsampled: log,
+		burst:     conf.Burst,
+		interval:  conf.Interval,
+		messages:  make(map[logSignature]*logCount),
+		done:      make(chan struct{}),
+	}
+	logger.Formatter = s
+	s.wg.Add(1)
+	go s.run()
+	return s.log, s
+}
+
+// Format implements logrus.Formatter, a message which is dropped is formatted as nothing
+func (s *logSampler) Format(e *logrus.Entry) ([]byte, error) {
+	if e.Level != logrus.WarnLevel && e.Level != logrus.ErrorLevel {
+		return s.formatter.Format(e)
+	}
+	if _, ok := e.Data[logSuppressedField]; ok {
+		return s.formatter.Format(e)
+	}
+
+	s.mutex.Lock()
+	sig := logSignature{level: e.Level, message: e.Message}
+	c, ok := s.messages[sig]
+	if !ok {
+		c = &logCount{}
+		s.messages[sig] = c
+	}
+	if c.logged >= s.burst {
+		c.suppressed++
+		s.mutex.Unlock()
+		return nil, nil
+	}
+	c.logged++
+	s.mutex.Unlock()
+	return s.formatter.Format(e)
+}
+
+// run logs the summary of the suppressed messages every interval until close() is called
+func (s *logSampler) run() {
+	defer s.wg.Done()
+	tick := clock.NewTicker(s.interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			s.summarize()
+		case <-s.done:
+			s.summarize()
+			return
+		}
+	}
+}
+
+// summarize logs the number of each message suppressed since the previous interval and starts a
+// new interval
+func (s *logSampler) summarize() {
+	s.mutex.Lock()
+	messages := s.messages
+	s.messages = make(map[logSignature]*logCount)
+	s.mutex.Unlock()
+
+	for sig, c := range messages {
+		if c.suppressed == 0 {
+			continue
+		}
+		s.log.WithField(logSuppressedField, c.suppressed).
+			Logf(sig.level, "suppressed %d similar messages: %s", c.suppressed, sig.message)
+	}
+}
+
+// close stops run() once the summary of the messages suppressed so far is logged
+func (s *logSampler) close() {
+	close(s.done)
+	s.wg.Wait()
+}
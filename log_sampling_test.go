@@ -0,0 +1,63 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSampling(t *testing.T) {
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.Out = &buf
+	base.Formatter = &logrus.TextFormatter{DisableTimestamp: true}
+
+	log, sampler := newSampledLogger(base.WithField("category", "gubernator"),
+		LogSamplingConfig{Burst: 2, Interval: time.Hour})
+	for i := 0; i < 5; i++ {
+		log.WithField("peer", i).Error("while sending hits to peer")
+		log.Info("peer list updated")
+	}
+	log.Warn("while sending hits to peer")
+	sampler.close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	count := func(substr string) int {
+		var n int
+		for _, l := range lines {
+			if strings.Contains(l, substr) {
+				n++
+			}
+		}
+		return n
+	}
+	// Messages are identified by their level and message, not their fields
+	assert.Equal(t, 2, count(`level=error msg="while sending hits to peer"`))
+	assert.Equal(t, 1, count(`level=warning msg="while sending hits to peer"`))
+	// Info messages are never sampled
+	assert.Equal(t, 5, count("peer list updated"))
+	assert.Equal(t, 1, count(`level=error msg="suppressed 3 similar messages: while sending hits to peer" category=gubernator suppressed=3`))
+	// The logger which was copied is not changed
+	base.Info("unsampled")
+	assert.Contains(t, buf.String(), "level=info msg=unsampled")
+}
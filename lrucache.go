@@ -20,9 +20,10 @@ package gubernator
 
 import (
 	"container/list"
+	"sync"
 	"sync/atomic"
+	"unsafe"
 
-	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/setter"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -30,10 +31,42 @@ import (
 // LRUCache is an LRU cache that supports expiration and is not thread-safe
 // Be sure to use a mutex to prevent concurrent method calls.
 type LRUCache struct {
-	cache     map[string]*list.Element
-	ll        *list.List
-	cacheSize int
-	cacheLen  int64
+	cache      map[string]*list.Element
+	ll         *list.List
+	cacheSize  int
+	cacheLen   int64
+	maxBytes   int64
+	cacheBytes int64
+
+	fullPolicy string
+	onEvict    func(*CacheItem)
+	// While the cache is full, no item expires before this time in epoch milliseconds
+	fullUntil int64
+	// Is called with each removed item, see SetOnRemove()
+	onRemove func(item *CacheItem)
+
+	// Is nil unless the cache is partitioned by tenant, see SetTenantQuota()
+	tenantOf    func(*CacheItem) string
+	tenantQuota func(tenant string) int
+	// Guards `tenants` which is read by TenantSizes() while the cache is in use
+	tenantMutex sync.Mutex
+	// The elements of `ll` held by each tenant, most recently used first
+	tenants map[string]*list.List
+
+	// Is nil unless items are indexed by the hash of their key instead of `cache`, see SetKeyHash()
+	keyHash HashString64
+	hashed  map[uint64]*list.Element
+	// The items whose key hash collides with the key of another item in `hashed`
+	collided map[string]*list.Element
+}
+
+// lruEntry is an item in the LRUCache and the time it was last accessed in epoch milliseconds
+type lruEntry struct {
+	item       *CacheItem
+	accessedAt int64
+	// The tenant of the item and its element in the list of the tenant, if partitioned by tenant
+	tenant    string
+	tenantEle *list.Element
 }
 
 // LRUCacheCollector provides prometheus metrics collector for LRUCache.
@@ -43,12 +76,26 @@ type LRUCacheCollector struct {
 }
 
 var _ Cache = &LRUCache{}
+var _ ByteLimitedCache = &LRUCache{}
+var _ IdleCache = &LRUCache{}
+var _ CapacityCache = &LRUCache{}
+var _ TenantCache = &LRUCache{}
+var _ ReadCopyCache = &LRUCache{}
+var _ HashedKeyCache = &LRUCache{}
 var _ prometheus.Collector = &LRUCacheCollector{}
 
+var metricCacheIdleEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "gubernator_idle_evictions_count",
+	Help: "Count the number of cache items which were evicted because they were not accessed within the idle TTL.",
+})
 var metricCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
 	Name: "gubernator_cache_size",
 	Help: "The number of items in LRU Cache which holds the rate limits.",
 })
+var metricCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "gubernator_cache_bytes",
+	Help: "The approximate number of bytes used by the items in LRU Cache which holds the rate limits.",
+})
 var metricCacheAccess = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name: "gubernator_cache_access_count",
 	Help: "Cache access counts.  Label \"type\" = hit|miss.",
@@ -57,6 +104,18 @@ var metricCacheUnexpiredEvictions = prometheus.NewCounter(prometheus.CounterOpts
 	Name: "gubernator_unexpired_evictions_count",
 	Help: "Count the number of cache items which were evicted while unexpired.",
 })
+var metricCacheTenantEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "gubernator_tenant_quota_evictions_count",
+	Help: "Count the number of unexpired cache items which were evicted because their tenant used its share of the cache.",
+})
+var metricCacheKeyCollisions = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "gubernator_cache_key_collision_count",
+	Help: "Count the number of keys added to LRU Cache whose hash collided with the key of another item, when items are indexed by the hash of their key.",
+})
+var metricCacheTenantSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gubernator_cache_tenant_size",
+	Help: "The number of items each tenant holds in LRU Cache, when the cache is partitioned by tenant.",
+}, []string{"tenant"})
 
 // NewLRUCache creates a new Cache with a maximum size.
 func NewLRUCache(maxSize int) *LRUCache {
@@ -76,43 +135,226 @@ func NewLRUCache(maxSize int) *LRUCache {
 func (c *LRUCache) Each() chan *CacheItem {
 	out := make(chan *CacheItem)
 	go func() {
-		for _, ele := range c.cache {
-			out <- ele.Value.(*CacheItem)
+		for ele := c.ll.Front(); ele != nil; ele = ele.Next() {
+			out <- ele.Value.(*lruEntry).item
 		}
 		close(out)
 	}()
 	return out
 }
 
+// SetMaxBytes sets the approximate maximum number of bytes the items in the cache
+// may use before the oldest items are evicted. A value of zero disables the limit.
+func (c *LRUCache) SetMaxBytes(maxBytes int64) {
+	c.maxBytes = maxBytes
+}
+
+// SetFullPolicy sets how room is made for a new item while the cache is full, one of the
+// CacheFull* policies. `onEvict` is called with the unexpired items evicted by CacheFullSpill.
+func (c *LRUCache) SetFullPolicy(policy string, onEvict func(*CacheItem)) {
+	c.fullPolicy, c.onEvict = policy, onEvict
+}
+
+// SetOnRemove sets a function which is called with each item removed from the cache.
+func (c *LRUCache) SetOnRemove(onRemove func(item *CacheItem)) {
+	c.onRemove = onRemove
+}
+
+// SetKeyHash indexes the items by the 64 bit `hash` of their key instead of the key, such that the
+// index does not hold a copy of each key. Keys whose hash collides with the key of another item are
+// indexed by the key. Must be called before items are added.
+func (c *LRUCache) SetKeyHash(hash HashString64) {
+	c.keyHash = hash
+	c.cache = nil
+	c.hashed = make(map[uint64]*list.Element)
+	c.collided = make(map[string]*list.Element)
+}
+
+// lookup returns the element which holds the item stored for `key`
+func (c *LRUCache) lookup(key string) (*list.Element, bool) {
+	if c.keyHash == nil {
+		ele, ok := c.cache[key]
+		return ele, ok
+	}
+	if ele, ok := c.hashed[c.keyHash(key)]; ok && ele.Value.(*lruEntry).item.Key == key {
+		return ele, true
+	}
+	if len(c.collided) == 0 {
+		return nil, false
+	}
+	ele, ok := c.collided[key]
+	return ele, ok
+}
+
+// index adds the element of a new item to the index
+func (c *LRUCache) index(key string, ele *list.Element) {
+	if c.keyHash == nil {
+		c.cache[key] = ele
+		return
+	}
+	h := c.keyHash(key)
+	if _, ok := c.hashed[h]; ok {
+		metricCacheKeyCollisions.Add(1)
+		c.collided[key] = ele
+		return
+	}
+	c.hashed[h] = ele
+}
+
+// unindex removes the element of the item stored for `key` from the index
+func (c *LRUCache) unindex(key string) {
+	if c.keyHash == nil {
+		delete(c.cache, key)
+		return
+	}
+	if _, ok := c.collided[key]; ok {
+		delete(c.collided, key)
+		return
+	}
+	delete(c.hashed, c.keyHash(key))
+}
+
+// SetTenantQuota partitions the cache by tenant, once a tenant holds `maxItems` its least recently
+// used item is evicted to make room for its new items. Must be called before items are added.
+func (c *LRUCache) SetTenantQuota(tenantOf func(*CacheItem) string, maxItems func(tenant string) int) {
+	c.tenantOf, c.tenantQuota = tenantOf, maxItems
+	c.tenants = make(map[string]*list.List)
+}
+
+// TenantSizes returns the number of items each tenant holds, or nil if not partitioned by tenant
+func (c *LRUCache) TenantSizes() map[string]int64 {
+	c.tenantMutex.Lock()
+	defer c.tenantMutex.Unlock()
+	if c.tenants == nil {
+		return nil
+	}
+	sizes := make(map[string]int64, len(c.tenants))
+	for tenant, l := range c.tenants {
+		sizes[tenant] = int64(l.Len())
+	}
+	return sizes
+}
+
+// addToTenant adds the element to the list of its tenant, evicting the least recently used
+// item of the tenant if the tenant holds more than its quota.
+func (c *LRUCache) addToTenant(ele *list.Element) {
+	entry := ele.Value.(*lruEntry)
+	entry.tenant = c.tenantOf(entry.item)
+
+	c.tenantMutex.Lock()
+	l, ok := c.tenants[entry.tenant]
+	if !ok {
+		l = list.New()
+		c.tenants[entry.tenant] = l
+	}
+	entry.tenantEle = l.PushFront(ele)
+	var oldest *list.Element
+	if l.Len() > c.tenantQuota(entry.tenant) {
+		oldest = l.Back().Value.(*list.Element)
+	}
+	c.tenantMutex.Unlock()
+
+	if oldest != nil {
+		if MillisecondNow() < oldest.Value.(*lruEntry).item.ExpireAt {
+			metricCacheTenantEvictions.Add(1)
+		}
+		c.removeElement(oldest)
+	}
+}
+
+// touchTenant marks the element as the most recently used item of its tenant
+func (c *LRUCache) touchTenant(entry *lruEntry) {
+	if entry.tenantEle == nil {
+		return
+	}
+	c.tenantMutex.Lock()
+	c.tenants[entry.tenant].MoveToFront(entry.tenantEle)
+	c.tenantMutex.Unlock()
+}
+
+// IsFull returns true if adding an item for `key` would evict an unexpired item. Expired
+// items are removed from a full cache to make room before deciding.
+func (c *LRUCache) IsFull(key string) bool {
+	if _, ok := c.lookup(key); ok || !c.atCapacity() {
+		return false
+	}
+	now := MillisecondNow()
+	if now < c.fullUntil {
+		return true
+	}
+
+	c.fullUntil = 0
+	for ele := c.ll.Back(); ele != nil; {
+		prev, item := ele.Prev(), ele.Value.(*lruEntry).item
+		if item.ExpireAt <= now {
+			c.removeElement(ele)
+		} else if c.fullUntil == 0 || item.ExpireAt < c.fullUntil {
+			c.fullUntil = item.ExpireAt
+		}
+		ele = prev
+	}
+	return c.atCapacity()
+}
+
+func (c *LRUCache) atCapacity() bool {
+	return (c.cacheSize != 0 && c.ll.Len() >= c.cacheSize) ||
+		(c.maxBytes != 0 && c.cacheBytes+cacheEntryOverhead > c.maxBytes)
+}
+
 // Add adds a value to the cache.
 func (c *LRUCache) Add(item *CacheItem) bool {
 	// If the key already exist, set the new value
-	if ee, ok := c.cache[item.Key]; ok {
+	now := MillisecondNow()
+	if ee, ok := c.lookup(item.Key); ok {
 		c.ll.MoveToFront(ee)
-		ee.Value = item
+		entry := ee.Value.(*lruEntry)
+		c.touchTenant(entry)
+		c.addBytes(cacheItemBytes(item) - cacheItemBytes(entry.item))
+		entry.item, entry.accessedAt = item, now
+		c.evict()
 		return true
 	}
 
-	ele := c.ll.PushFront(item)
-	c.cache[item.Key] = ele
-	if c.cacheSize != 0 && c.ll.Len() > c.cacheSize {
-		c.removeOldest()
+	ele := c.ll.PushFront(&lruEntry{item: item, accessedAt: now})
+	c.index(item.Key, ele)
+	c.addBytes(cacheItemBytes(item))
+	if c.tenantOf != nil {
+		c.addToTenant(ele)
 	}
+	c.evict()
 	atomic.StoreInt64(&c.cacheLen, int64(c.ll.Len()))
 	return false
 }
 
-// MillisecondNow returns unix epoch in milliseconds
+// evict removes the oldest items until the cache is within both the max
+// number of items and the max number of bytes.
+func (c *LRUCache) evict() {
+	if c.cacheSize != 0 && c.ll.Len() > c.cacheSize {
+		c.removeOldest()
+	}
+	// Always keep the most recently added item, even if it alone exceeds the max bytes.
+	for c.maxBytes != 0 && c.cacheBytes > c.maxBytes && c.ll.Len() > 1 {
+		c.removeOldest()
+	}
+}
+
+func (c *LRUCache) addBytes(n int64) {
+	atomic.StoreInt64(&c.cacheBytes, c.cacheBytes+n)
+}
+
+// MillisecondNow returns unix epoch in milliseconds on the monotonic clock of the instance, which
+// is the wall clock unaffected by the clock jumps since the instance started. Rate limits created,
+// updated and expired by the instance are counted by this clock.
 func MillisecondNow() int64 {
-	return clock.Now().UnixNano() / 1000000
+	return epochMillis(monotonic.Now())
 }
 
 // GetItem returns the item stored in the cache
 func (c *LRUCache) GetItem(key string) (item *CacheItem, ok bool) {
-	if ele, hit := c.cache[key]; hit {
-		entry := ele.Value.(*CacheItem)
+	if ele, hit := c.lookup(key); hit {
+		entry := ele.Value.(*lruEntry)
 
-		if entry.IsExpired() {
+		if entry.item.IsExpired() {
 			c.removeElement(ele)
 			metricCacheAccess.WithLabelValues("miss").Add(1)
 			return
@@ -120,39 +362,97 @@ func (c *LRUCache) GetItem(key string) (item *CacheItem, ok bool) {
 
 		metricCacheAccess.WithLabelValues("hit").Add(1)
 		c.ll.MoveToFront(ele)
-		return entry, true
+		c.touchTenant(entry)
+		entry.accessedAt = MillisecondNow()
+		return entry.item, true
 	}
 
 	metricCacheAccess.WithLabelValues("miss").Add(1)
 	return
 }
 
+// Peek returns the item stored in the cache, even if expired, without moving it to the front.
+func (c *LRUCache) Peek(key string) (*CacheItem, bool) {
+	if ele, hit := c.lookup(key); hit {
+		return ele.Value.(*lruEntry).item, true
+	}
+	return nil, false
+}
+
 // Remove removes the provided key from the cache.
 func (c *LRUCache) Remove(key string) {
-	if ele, hit := c.cache[key]; hit {
+	if ele, hit := c.lookup(key); hit {
 		c.removeElement(ele)
 	}
 }
 
-// RemoveOldest removes the oldest item from the cache.
+// removeOldest removes an item to make room for a new item, the least recently used unless
+// the policy is CacheFullEvictOldestReset.
 func (c *LRUCache) removeOldest() {
 	ele := c.ll.Back()
-	if ele != nil {
-		entry := ele.Value.(*CacheItem)
+	if c.fullPolicy == CacheFullEvictOldestReset {
+		ele = c.firstToReset()
+	}
+	if ele == nil {
+		return
+	}
+
+	item := ele.Value.(*lruEntry).item
+	if MillisecondNow() < item.ExpireAt {
+		metricCacheUnexpiredEvictions.Add(1)
+		if c.fullPolicy == CacheFullSpill && c.onEvict != nil {
+			c.onEvict(item)
+			metricCacheFullCounter.WithLabelValues("spilled").Inc()
+		} else {
+			metricCacheFullCounter.WithLabelValues("evicted").Inc()
+		}
+	}
+	c.removeElement(ele)
+}
 
-		if MillisecondNow() < entry.ExpireAt {
-			metricCacheUnexpiredEvictions.Add(1)
+// firstToReset returns the item which expires soonest, other than the most recently added item.
+func (c *LRUCache) firstToReset() *list.Element {
+	var first *list.Element
+	for ele := c.ll.Back(); ele != nil && ele != c.ll.Front(); ele = ele.Prev() {
+		if first == nil || ele.Value.(*lruEntry).item.ExpireAt < first.Value.(*lruEntry).item.ExpireAt {
+			first = ele
 		}
+	}
+	return first
+}
 
+// RemoveIdle removes the items which were last accessed before `before` in epoch milliseconds
+// and returns the number of items removed.
+func (c *LRUCache) RemoveIdle(before int64) int {
+	var removed int
+	// The least recently accessed items are at the back of the list
+	for ele := c.ll.Back(); ele != nil && ele.Value.(*lruEntry).accessedAt < before; ele = c.ll.Back() {
 		c.removeElement(ele)
+		removed++
 	}
+	metricCacheIdleEvictions.Add(float64(removed))
+	return removed
 }
 
 func (c *LRUCache) removeElement(e *list.Element) {
 	c.ll.Remove(e)
-	kv := e.Value.(*CacheItem)
-	delete(c.cache, kv.Key)
+	entry := e.Value.(*lruEntry)
+	if entry.tenantEle != nil {
+		c.tenantMutex.Lock()
+		l := c.tenants[entry.tenant]
+		l.Remove(entry.tenantEle)
+		if l.Len() == 0 {
+			delete(c.tenants, entry.tenant)
+		}
+		c.tenantMutex.Unlock()
+	}
+	kv := entry.item
+	c.unindex(kv.Key)
+	c.addBytes(-cacheItemBytes(kv))
 	atomic.StoreInt64(&c.cacheLen, int64(c.ll.Len()))
+	if c.onRemove != nil {
+		c.onRemove(kv)
+	}
 }
 
 // Size returns the number of items in the cache.
@@ -160,10 +460,32 @@ func (c *LRUCache) Size() int64 {
 	return atomic.LoadInt64(&c.cacheLen)
 }
 
+// Bytes returns the approximate number of bytes used by the items in the cache.
+func (c *LRUCache) Bytes() int64 {
+	return atomic.LoadInt64(&c.cacheBytes)
+}
+
+// The approximate overhead of a single entry in the cache. This includes the CacheItem, the
+// list element which tracks the LRU order and the entry in the map which indexes the list.
+const cacheEntryOverhead = int64(unsafe.Sizeof(CacheItem{}) + unsafe.Sizeof(lruEntry{}) + unsafe.Sizeof(list.Element{}) +
+	unsafe.Sizeof("") + unsafe.Sizeof(&list.Element{}))
+
+// cacheItemBytes returns the approximate number of bytes the item uses while in the cache.
+func cacheItemBytes(item *CacheItem) int64 {
+	size := cacheEntryOverhead + int64(len(item.Key)) + int64(len(item.Name))
+	switch item.Value.(type) {
+	case *TokenBucketItem:
+		size += int64(unsafe.Sizeof(TokenBucketItem{}))
+	case *LeakyBucketItem:
+		size += int64(unsafe.Sizeof(LeakyBucketItem{}))
+	}
+	return size
+}
+
 // UpdateExpiration updates the expiration time for the key
 func (c *LRUCache) UpdateExpiration(key string, expireAt int64) bool {
-	if ele, hit := c.cache[key]; hit {
-		entry := ele.Value.(*CacheItem)
+	if ele, hit := c.lookup(key); hit {
+		entry := ele.Value.(*lruEntry).item
 		entry.ExpireAt = expireAt
 		return true
 	}
@@ -172,8 +494,16 @@ func (c *LRUCache) UpdateExpiration(key string, expireAt int64) bool {
 
 func (c *LRUCache) Close() error {
 	c.cache = nil
+	c.hashed = nil
+	c.collided = nil
 	c.ll = nil
+	c.tenantMutex.Lock()
+	if c.tenants != nil {
+		c.tenants = make(map[string]*list.List)
+	}
+	c.tenantMutex.Unlock()
 	c.cacheLen = 0
+	c.cacheBytes = 0
 	return nil
 }
 
@@ -193,6 +523,11 @@ func (collector *LRUCacheCollector) Describe(ch chan<- *prometheus.Desc) {
 	metricCacheSize.Describe(ch)
 	metricCacheAccess.Describe(ch)
 	metricCacheUnexpiredEvictions.Describe(ch)
+	metricCacheIdleEvictions.Describe(ch)
+	metricCacheBytes.Describe(ch)
+	metricCacheTenantEvictions.Describe(ch)
+	metricCacheTenantSize.Describe(ch)
+	metricCacheKeyCollisions.Describe(ch)
 }
 
 // Collect fetches metric counts and gauges from the cache
@@ -201,6 +536,16 @@ func (collector *LRUCacheCollector) Collect(ch chan<- prometheus.Metric) {
 	metricCacheSize.Collect(ch)
 	metricCacheAccess.Collect(ch)
 	metricCacheUnexpiredEvictions.Collect(ch)
+	metricCacheIdleEvictions.Collect(ch)
+	metricCacheBytes.Set(collector.getBytes())
+	metricCacheBytes.Collect(ch)
+	metricCacheTenantEvictions.Collect(ch)
+	metricCacheTenantSize.Reset()
+	for tenant, size := range collector.getTenantSizes() {
+		metricCacheTenantSize.WithLabelValues(tenant).Set(float64(size))
+	}
+	metricCacheTenantSize.Collect(ch)
+	metricCacheKeyCollisions.Collect(ch)
 }
 
 func (collector *LRUCacheCollector) getSize() float64 {
@@ -212,3 +557,28 @@ func (collector *LRUCacheCollector) getSize() float64 {
 
 	return size
 }
+
+// getTenantSizes returns the number of items each tenant holds across every cache
+func (collector *LRUCacheCollector) getTenantSizes() map[string]int64 {
+	sizes := make(map[string]int64)
+	for _, cache := range collector.caches {
+		if c, ok := cache.(TenantCache); ok {
+			for tenant, size := range c.TenantSizes() {
+				sizes[tenant] += size
+			}
+		}
+	}
+	return sizes
+}
+
+func (collector *LRUCacheCollector) getBytes() float64 {
+	var bytes float64
+
+	for _, cache := range collector.caches {
+		if c, ok := cache.(ByteLimitedCache); ok {
+			bytes += float64(c.Bytes())
+		}
+	}
+
+	return bytes
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -426,6 +427,194 @@ func TestLRUCache(t *testing.T) {
 		assert.Contains(t, m.Desc().String(), "gubernator_unexpired_evictions_count")
 		assert.Equal(t, 1, int(*met.Counter.Value))
 	})
+
+	t.Run("Evict by max bytes", func(t *testing.T) {
+		cache := gubernator.NewLRUCache(0)
+		newItem := func(key string) *gubernator.CacheItem {
+			return &gubernator.CacheItem{
+				Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+				Key:       key,
+				Value:     &gubernator.TokenBucketItem{Limit: 10, Remaining: 10},
+				ExpireAt:  expireAt,
+			}
+		}
+
+		// Measure the size of a single item.
+		cache.Add(newItem("key-0"))
+		itemBytes := cache.Bytes()
+		require.Greater(t, itemBytes, int64(len("key-0")))
+		cache.SetMaxBytes(itemBytes * 3)
+
+		for i := 1; i < 10; i++ {
+			cache.Add(newItem(fmt.Sprintf("key-%d", i)))
+		}
+
+		// Only the 3 most recent items fit.
+		assert.Equal(t, int64(3), cache.Size())
+		assert.Equal(t, itemBytes*3, cache.Bytes())
+		for i := 7; i < 10; i++ {
+			_, ok := cache.GetItem(fmt.Sprintf("key-%d", i))
+			assert.True(t, ok)
+		}
+
+		// An item larger than the max bytes evicts every other item.
+		cache.Add(newItem(fmt.Sprintf("key-%s", strings.Repeat("x", int(itemBytes*3)))))
+		assert.Equal(t, int64(1), cache.Size())
+
+		cache.Remove(fmt.Sprintf("key-%s", strings.Repeat("x", int(itemBytes*3))))
+		assert.Zero(t, cache.Bytes())
+	})
+
+	t.Run("Remove idle items", func(t *testing.T) {
+		defer clock.Freeze(clock.Now()).Unfreeze()
+		cache := gubernator.NewLRUCache(0)
+		for i := 0; i < 3; i++ {
+			cache.Add(&gubernator.CacheItem{Key: fmt.Sprintf("key-%d", i), Value: i, ExpireAt: expireAt})
+		}
+		clock.Advance(clock.Minute)
+		cache.Add(&gubernator.CacheItem{Key: "key-3", Value: 3, ExpireAt: expireAt})
+		// Accessing an item keeps it from being idle
+		_, ok := cache.GetItem("key-1")
+		require.True(t, ok)
+
+		assert.Equal(t, 2, cache.RemoveIdle(gubernator.MillisecondNow()-clock.Second.Milliseconds()))
+		assert.Equal(t, int64(2), cache.Size())
+		for _, key := range []string{"key-1", "key-3"} {
+			_, ok := cache.GetItem(key)
+			assert.True(t, ok, key)
+		}
+		assert.Zero(t, cache.RemoveIdle(gubernator.MillisecondNow()-clock.Second.Milliseconds()))
+	})
+
+	t.Run("Full cache policies", func(t *testing.T) {
+		defer clock.Freeze(clock.Now()).Unfreeze()
+		now := clock.Now()
+		fill := func(policy string, onEvict func(*gubernator.CacheItem)) *gubernator.LRUCache {
+			cache := gubernator.NewLRUCache(3)
+			cache.SetFullPolicy(policy, onEvict)
+			// key-0 is the least recently used, key-1 resets first
+			for i, expire := range []time.Duration{time.Hour, time.Minute, 2 * time.Hour} {
+				cache.Add(&gubernator.CacheItem{Key: fmt.Sprintf("key-%d", i), Value: i, ExpireAt: now.Add(expire).UnixMilli()})
+			}
+			return cache
+		}
+		newItem := &gubernator.CacheItem{Key: "key-3", Value: 3, ExpireAt: now.Add(time.Hour).UnixMilli()}
+
+		cache := fill(gubernator.CacheFullEvictLRU, nil)
+		assert.True(t, cache.IsFull("key-3"))
+		assert.False(t, cache.IsFull("key-0"))
+		cache.Add(newItem)
+		_, ok := cache.GetItem("key-0")
+		assert.False(t, ok)
+
+		cache = fill(gubernator.CacheFullEvictOldestReset, nil)
+		cache.Add(newItem)
+		_, ok = cache.GetItem("key-1")
+		assert.False(t, ok)
+		_, ok = cache.GetItem("key-0")
+		assert.True(t, ok)
+
+		var spilled []string
+		cache = fill(gubernator.CacheFullSpill, func(item *gubernator.CacheItem) { spilled = append(spilled, item.Key) })
+		cache.Add(newItem)
+		assert.Equal(t, []string{"key-0"}, spilled)
+		assert.Equal(t, int64(3), cache.Size())
+
+		// The cache is no longer full once an item expires
+		cache = fill(gubernator.CacheFullReject, nil)
+		assert.True(t, cache.IsFull("key-3"))
+		clock.Advance(2 * time.Minute)
+		assert.False(t, cache.IsFull("key-3"))
+		assert.Equal(t, int64(2), cache.Size())
+	})
+
+	t.Run("Tenant quotas", func(t *testing.T) {
+		expireAt := clock.Now().Add(time.Hour).UnixMilli()
+		cache := gubernator.NewLRUCache(10)
+		cache.SetTenantQuota(
+			func(item *gubernator.CacheItem) string { return strings.Split(item.Name, ":")[0] },
+			func(tenant string) int {
+				if tenant == "big" {
+					return 5
+				}
+				return 2
+			},
+		)
+		add := func(name string, i int) {
+			cache.Add(&gubernator.CacheItem{Key: fmt.Sprintf("%s_%d", name, i), Name: name, Value: i, ExpireAt: expireAt})
+		}
+
+		add("small:login", 0)
+		add("small:login", 1)
+		// The least recently used item of the tenant is evicted, not the least recently used item of the cache
+		_, ok := cache.GetItem("small:login_0")
+		require.True(t, ok)
+		for i := 0; i < 10; i++ {
+			add("noisy", i)
+		}
+		add("small:signup", 0)
+
+		_, ok = cache.GetItem("small:login_0")
+		assert.True(t, ok)
+		_, ok = cache.GetItem("small:login_1")
+		assert.False(t, ok)
+		_, ok = cache.GetItem("noisy_9")
+		assert.True(t, ok)
+		_, ok = cache.GetItem("noisy_7")
+		assert.False(t, ok)
+
+		for i := 0; i < 10; i++ {
+			add("big:search", i)
+		}
+		assert.Equal(t, map[string]int64{"small": 2, "noisy": 2, "big": 5}, cache.TenantSizes())
+		assert.Equal(t, int64(9), cache.Size())
+
+		cache.Remove("noisy_9")
+		cache.Remove("noisy_8")
+		assert.Equal(t, map[string]int64{"small": 2, "big": 5}, cache.TenantSizes())
+	})
+
+	t.Run("Hashed keys", func(t *testing.T) {
+		expireAt := clock.Now().Add(time.Hour).UnixMilli()
+		cache := gubernator.NewLRUCache(3)
+		// Keys of the same length collide, such that the collision fallback is exercised
+		cache.SetKeyHash(func(key string) uint64 { return uint64(len(key)) })
+		add := func(key string, value int) bool {
+			return cache.Add(&gubernator.CacheItem{Key: key, Value: value, ExpireAt: expireAt})
+		}
+
+		assert.False(t, add("a_1", 1))
+		assert.False(t, add("a_2", 2))
+		assert.False(t, add("long_1", 3))
+		assert.True(t, add("a_2", 4))
+		for key, value := range map[string]int{"a_1": 1, "a_2": 4, "long_1": 3} {
+			item, ok := cache.GetItem(key)
+			require.True(t, ok, key)
+			assert.Equal(t, value, item.Value)
+		}
+		_, ok := cache.GetItem("a_3")
+		assert.False(t, ok)
+
+		// Removing the key indexed by its hash does not lose the key which collided with it
+		cache.Remove("a_1")
+		_, ok = cache.GetItem("a_1")
+		assert.False(t, ok)
+		item, ok := cache.GetItem("a_2")
+		require.True(t, ok)
+		assert.Equal(t, 4, item.Value)
+		assert.False(t, add("a_3", 5))
+
+		// The least recently used item is evicted
+		assert.False(t, add("long_2", 6))
+		_, ok = cache.GetItem("long_1")
+		assert.False(t, ok)
+		var keys []string
+		for item := range cache.Each() {
+			keys = append(keys, item.Key)
+		}
+		assert.ElementsMatch(t, []string{"a_2", "a_3", "long_2"}, keys)
+		assert.Equal(t, int64(3), cache.Size())
+	})
 }
 
 func BenchmarkLRUCache(b *testing.B) {
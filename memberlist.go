@@ -26,6 +26,7 @@ import (
 	"net"
 	"runtime"
 	"strconv"
+	"sync"
 
 	ml "github.com/hashicorp/memberlist"
 	"github.com/mailgun/holster/v4/clock"
@@ -40,6 +41,7 @@ type MemberListPool struct {
 	memberList *ml.Memberlist
 	conf       MemberListPoolConfig
 	events     *memberListEventHandler
+	delegate   *memberListDelegate
 }
 
 type MemberListPoolConfig struct {
@@ -58,6 +60,10 @@ type MemberListPoolConfig struct {
 	// (Required) A callback function which is called when the member list changes
 	OnUpdate UpdateFunc
 
+	// (Optional) A callback function which is called with the peers which joined or left the
+	// member list, instead of calling OnUpdate with every peer. IE: V1Instance.UpdatePeers
+	OnDelta DeltaUpdateFunc
+
 	// (Optional) The name of the node this member list identifies itself as.
 	NodeName string
 
@@ -91,10 +97,12 @@ func NewMemberListPool(ctx context.Context, conf MemberListPoolConfig) (*MemberL
 
 	// Configure member list event handler
 	m.events = newMemberListEventHandler(m.log, conf)
+	m.delegate = &memberListDelegate{advertise: conf.Advertise}
 
 	// Configure member list
 	config := ml.DefaultWANConfig()
 	config.Events = m.events
+	config.Delegate = m.delegate
 	config.AdvertiseAddr = host
 	config.AdvertisePort = port
 
@@ -126,7 +134,7 @@ func NewMemberListPool(ctx context.Context, conf MemberListPoolConfig) (*MemberL
 func (m *MemberListPool) joinPool(ctx context.Context, conf MemberListPoolConfig) error {
 	// Get local node and set metadata
 	node := m.memberList.LocalNode()
-	b, err := json.Marshal(&conf.Advertise)
+	b, err := m.delegate.meta()
 	if err != nil {
 		return errors.Wrap(err, "error marshalling PeerInfo as JSON")
 	}
@@ -150,6 +158,15 @@ func (m *MemberListPool) joinPool(ctx context.Context, conf MemberListPoolConfig
 	return nil
 }
 
+// SetWeight advertises a new weight of this instance to the other members, which move a share of
+// the key space to or from this instance accordingly. See DaemonConfig.LoadSheddingCPUPercent
+func (m *MemberListPool) SetWeight(weight int) error {
+	m.delegate.mutex.Lock()
+	m.delegate.advertise.Weight = weight
+	m.delegate.mutex.Unlock()
+	return m.memberList.UpdateNode(clock.Second * 10)
+}
+
 func (m *MemberListPool) Close() {
 	err := m.memberList.Leave(clock.Second)
 	if err != nil {
@@ -157,6 +174,32 @@ func (m *MemberListPool) Close() {
 	}
 }
 
+// memberListDelegate provides the metadata of the local node, which memberlist gossips to the
+// other members when the node joins and each time UpdateNode() is called.
+type memberListDelegate struct {
+	mutex     sync.Mutex
+	advertise PeerInfo
+}
+
+func (d *memberListDelegate) meta() ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return json.Marshal(&d.advertise)
+}
+
+func (d *memberListDelegate) NodeMeta(limit int) []byte {
+	b, err := d.meta()
+	if err != nil || len(b) > limit {
+		return nil
+	}
+	return b
+}
+
+func (d *memberListDelegate) NotifyMsg([]byte)                           {}
+func (d *memberListDelegate) GetBroadcasts(overhead, limit int) [][]byte { return nil }
+func (d *memberListDelegate) LocalState(join bool) []byte                { return nil }
+func (d *memberListDelegate) MergeRemoteState(buf []byte, join bool)     {}
+
 type memberListEventHandler struct {
 	peers map[string]PeerInfo
 	log   FieldLogger
@@ -180,7 +223,7 @@ func (e *memberListEventHandler) addPeer(node *ml.Node) {
 		e.log.WithError(err).Warnf("while adding to peers")
 	} else {
 		e.peers[ip] = peer
-		e.callOnUpdate()
+		e.callOnUpdate([]PeerInfo{peer}, nil)
 	}
 }
 
@@ -196,16 +239,20 @@ func (e *memberListEventHandler) NotifyJoin(node *ml.Node) {
 	}
 	peer.IsOwner = false
 	e.peers[ip] = peer
-	e.callOnUpdate()
+	e.callOnUpdate([]PeerInfo{peer}, nil)
 }
 
 func (e *memberListEventHandler) NotifyLeave(node *ml.Node) {
 	ip := getIP(node.Address())
 
 	// Remove PeerInfo
+	peer, ok := e.peers[ip]
+	if !ok {
+		return
+	}
 	delete(e.peers, ip)
 
-	e.callOnUpdate()
+	e.callOnUpdate(nil, []PeerInfo{peer})
 }
 
 func (e *memberListEventHandler) NotifyUpdate(node *ml.Node) {
@@ -216,11 +263,29 @@ func (e *memberListEventHandler) NotifyUpdate(node *ml.Node) {
 		e.log.WithError(err).Warn("while unmarshalling peer info")
 	}
 	peer.IsOwner = false
+	old, ok := e.peers[ip]
 	e.peers[ip] = peer
-	e.callOnUpdate()
+	if !ok {
+		e.callOnUpdate([]PeerInfo{peer}, nil)
+		return
+	}
+	e.callOnUpdate([]PeerInfo{peer}, []PeerInfo{old})
 }
 
-func (e *memberListEventHandler) callOnUpdate() {
+// callOnUpdate calls OnDelta with the peers which joined or left, or OnUpdate with every peer
+// if OnDelta is not set.
+func (e *memberListEventHandler) callOnUpdate(added, removed []PeerInfo) {
+	if e.conf.OnDelta != nil {
+		for i := range added {
+			added[i].IsOwner = added[i].GRPCAddress == e.conf.Advertise.GRPCAddress
+		}
+		for i := range removed {
+			removed[i].IsOwner = removed[i].GRPCAddress == e.conf.Advertise.GRPCAddress
+		}
+		e.conf.OnDelta(added, removed)
+		return
+	}
+
 	var peers []PeerInfo
 
 	for _, p := range e.peers {
@@ -0,0 +1,81 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+)
+
+// monotonic is the clock the rate limits created by this instance are counted by, see MillisecondNow()
+var monotonic = newMonotonicClock()
+
+// monotonicClock is the wall clock time at which it was created, advanced by the monotonic clock.
+// Unlike the wall clock it does not jump when NTP steps the clock or a leap second is inserted, as
+// such the time elapsed between two hits of a rate limit is never negative or inflated by a jump.
+// NTP slews the monotonic clock at the same rate as the wall clock, as such the two only diverge
+// by the jumps of the wall clock since the instance started.
+type monotonicClock struct {
+	start time.Time
+	// Returns the current wall clock time
+	wall func() time.Time
+	// Returns the time elapsed since `start` on the monotonic clock
+	elapsed func() time.Duration
+}
+
+func newMonotonicClock() *monotonicClock {
+	start := clock.Now()
+	return &monotonicClock{
+		start: start,
+		wall:  clock.Now,
+		// time.Time.Sub() uses the monotonic readings of both times when present
+		elapsed: func() time.Duration { return clock.Since(start) },
+	}
+}
+
+// Now returns the current time on the monotonic clock, without a monotonic reading
+func (m *monotonicClock) Now() time.Time {
+	return m.start.Add(m.elapsed()).Round(0)
+}
+
+// offset returns the milliseconds the wall clock jumped ahead of the monotonic clock, which is
+// negative if the wall clock jumped back.
+func (m *monotonicClock) offset() int64 {
+	return epochMillis(m.wall()) - epochMillis(m.Now())
+}
+
+// gregorianExpiration returns the end of the gregorian interval of the wall clock, converted to
+// the monotonic clock such that the rate limit expires when the wall clock reaches the end.
+func gregorianExpiration(d int64) (int64, error) {
+	expire, err := GregorianExpiration(monotonic.wall(), d)
+	if err != nil {
+		return 0, err
+	}
+	return addInt64(expire, -monotonic.offset()), nil
+}
+
+// gregorianExpirationAt returns the end of the gregorian interval which contains `at` on the
+// monotonic clock, see gregorianExpiration()
+func gregorianExpirationAt(at, d int64) (int64, error) {
+	offset := monotonic.offset()
+	expire, err := GregorianExpiration(time.UnixMilli(addInt64(at, offset)), d)
+	if err != nil {
+		return 0, err
+	}
+	return addInt64(expire, -offset), nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jumpingClock replaces the monotonic clock with one whose wall clock may jump independently of
+// the time elapsed on the monotonic clock.
+type jumpingClock struct {
+	start   time.Time
+	wall    time.Time
+	elapsed time.Duration
+}
+
+func newJumpingClock(t *testing.T) *jumpingClock {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	j := &jumpingClock{start: start, wall: start}
+	previous := monotonic
+	monotonic = &monotonicClock{
+		start:   start,
+		wall:    func() time.Time { return j.wall },
+		elapsed: func() time.Duration { return j.elapsed },
+	}
+	t.Cleanup(func() { monotonic = previous })
+	return j
+}
+
+// advance moves both clocks forward by `d`, then jumps the wall clock by `jump`
+func (j *jumpingClock) advance(d, jump time.Duration) {
+	j.elapsed += d
+	j.wall = j.wall.Add(d + jump)
+}
+
+func TestMonotonicClock(t *testing.T) {
+	j := newJumpingClock(t)
+	assert.Equal(t, j.start, monotonic.Now())
+	assert.Equal(t, int64(0), monotonic.offset())
+
+	// NTP steps the wall clock forward
+	j.advance(time.Second, time.Hour)
+	assert.Equal(t, j.start.Add(time.Second), monotonic.Now())
+	assert.Equal(t, time.Hour.Milliseconds(), monotonic.offset())
+
+	// A leap second repeats the last second of the day
+	j.advance(time.Second, -time.Hour-time.Second)
+	assert.Equal(t, j.start.Add(2*time.Second), monotonic.Now())
+	assert.Equal(t, -time.Second.Milliseconds(), monotonic.offset())
+
+	// The gregorian interval ends when the wall clock, which is a second behind, reaches its end
+	expire, err := gregorianExpiration(GregorianMinutes)
+	require.NoError(t, err)
+	assert.Equal(t, epochMillis(j.start.Add(time.Minute))-1+time.Second.Milliseconds(), expire)
+}
+
+func TestAlgorithmsAcrossClockJumps(t *testing.T) {
+	conf := &Config{}
+	require.NoError(t, conf.SetDefaults())
+	hit := func(c Cache, algorithm Algorithm) *RateLimitResp {
+		createdAt := MillisecondNow()
+		r := &RateLimitReq{
+			Name:      "clock_jump",
+			UniqueKey: "account:1234",
+			Algorithm: algorithm,
+			Limit:     10,
+			Duration:  10 * Second,
+			Hits:      1,
+			CreatedAt: &createdAt,
+		}
+		var rl *RateLimitResp
+		var err error
+		if algorithm == Algorithm_LEAKY_BUCKET {
+			rl, err = leakyBucket(context.Background(), nil, c, conf, r, RateLimitReqState{IsOwner: true})
+		} else {
+			rl, err = tokenBucket(context.Background(), nil, c, conf, r, RateLimitReqState{IsOwner: true})
+		}
+		require.NoError(t, err)
+		return rl
+	}
+
+	t.Run("Token bucket survives a forward jump", func(t *testing.T) {
+		j := newJumpingClock(t)
+		c := NewLRUCache(0)
+		first := hit(c, Algorithm_TOKEN_BUCKET)
+		assert.Equal(t, int64(9), first.Remaining)
+
+		// Were the bucket counted by the wall clock it would have expired and reset
+		j.advance(time.Second, time.Hour)
+		rl := hit(c, Algorithm_TOKEN_BUCKET)
+		assert.Equal(t, int64(8), rl.Remaining)
+		assert.Equal(t, first.ResetTime, rl.ResetTime)
+		assert.Equal(t, epochMillis(j.wall.Add(9*time.Second)), addInt64(rl.ResetTime, monotonic.offset()))
+	})
+
+	t.Run("Leaky bucket leaks across a backward jump", func(t *testing.T) {
+		j := newJumpingClock(t)
+		c := NewLRUCache(0)
+		hit(c, Algorithm_LEAKY_BUCKET)
+		hit(c, Algorithm_LEAKY_BUCKET)
+		assert.Equal(t, int64(7), hit(c, Algorithm_LEAKY_BUCKET).Remaining)
+
+		// Were the bucket counted by the wall clock no time would have elapsed, and nothing leaked
+		j.advance(2*time.Second, -time.Hour)
+		rl := hit(c, Algorithm_LEAKY_BUCKET)
+		assert.Equal(t, int64(8), rl.Remaining)
+		assert.Equal(t, epochMillis(j.wall.Add(2*time.Second)), addInt64(rl.ResetTime, monotonic.offset()))
+	})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// How often the namespaces are checked for garbage collection, see Config.NamespaceGCAfter
+const namespaceGCInterval = time.Hour
+
+// namespaceTracker records the most recent time each rate limit name was accessed by this
+// instance, such that namespaces which are no longer used can be listed and removed.
+type namespaceTracker struct {
+	mutex      sync.Mutex
+	started    int64
+	lastAccess map[string]int64
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newNamespaceTracker() *namespaceTracker {
+	return &namespaceTracker{
+		started:    epochMillis(clock.Now()),
+		lastAccess: make(map[string]int64),
+		done:       make(chan struct{}),
+	}
+}
+
+func (n *namespaceTracker) touch(name string) {
+	now := epochMillis(clock.Now())
+	n.mutex.Lock()
+	n.lastAccess[name] = now
+	n.mutex.Unlock()
+}
+
+// list returns the namespaces beginning with `prefix` which were accessed or are in `entries`,
+// names which were not accessed since the instance started report the time the instance started.
+func (n *namespaceTracker) list(prefix string, entries map[string]int64) []*NamespaceInfo {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	var result []*NamespaceInfo
+	for name, count := range entries {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		last, ok := n.lastAccess[name]
+		if !ok {
+			last = n.started
+		}
+		result = append(result, &NamespaceInfo{Name: name, Entries: count, LastAccess: last})
+	}
+	for name, last := range n.lastAccess {
+		if _, ok := entries[name]; !ok && strings.HasPrefix(name, prefix) {
+			result = append(result, &NamespaceInfo{Name: name, LastAccess: last})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// forget removes the name unless it was accessed after `before`
+func (n *namespaceTracker) forget(name string, before int64) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if n.lastAccess[name] < before {
+		delete(n.lastAccess, name)
+	}
+}
+
+// stop ends runNamespaceGC and waits for it to return
+func (n *namespaceTracker) stop() {
+	close(n.done)
+	n.wg.Wait()
+}
+
+// ListNamespaces returns the namespaces of every peer in the local data center.
+func (s *V1Instance) ListNamespaces(ctx context.Context, r *ListNamespacesReq) (*ListNamespacesResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListNamespaces")).ObserveDuration()
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	resp := &ListNamespacesResp{}
+	names := make(map[string]*NamespaceInfo)
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *ListNamespacesResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.ListPeerNamespaces(ctx, r)
+			} else {
+				peerResp, err = peer.ListPeerNamespaces(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			for _, ns := range peerResp.Namespaces {
+				n, ok := names[ns.Name]
+				if !ok {
+					n = &NamespaceInfo{Name: ns.Name}
+					names[ns.Name] = n
+				}
+				n.Entries += ns.Entries
+				if ns.LastAccess > n.LastAccess {
+					n.LastAccess = ns.LastAccess
+				}
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	for _, n := range names {
+		resp.Namespaces = append(resp.Namespaces, n)
+	}
+	sort.Slice(resp.Namespaces, func(i, j int) bool { return resp.Namespaces[i].Name < resp.Namespaces[j].Name })
+	return resp, nil
+}
+
+// ListPeerNamespaces is called by other peers to collect the namespaces in the cache of this peer.
+func (s *V1Instance) ListPeerNamespaces(ctx context.Context, r *ListNamespacesReq) (*ListNamespacesResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListPeerNamespaces")).ObserveDuration()
+	return &ListNamespacesResp{Namespaces: s.listNamespaces(ctx, r.NamePrefix)}, nil
+}
+
+func (s *V1Instance) listNamespaces(ctx context.Context, prefix string) []*NamespaceInfo {
+	entries := make(map[string]int64)
+	for item := range s.workerPool.Each(ctx) {
+		// Items loaded by a Loader or Store which do not set the name cannot be attributed
+		if item.Name != "" {
+			entries[item.Name]++
+		}
+	}
+	return s.namespaces.list(prefix, entries)
+}
+
+// runNamespaceGC removes the rate limits of the namespaces which were not accessed within
+// `Config.NamespaceGCAfter` until the instance is closed
+func (s *V1Instance) runNamespaceGC() {
+	defer s.namespaces.wg.Done()
+	tick := clock.NewTicker(namespaceGCInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			s.collectNamespaces()
+		case <-s.namespaces.done:
+			return
+		}
+	}
+}
+
+func (s *V1Instance) collectNamespaces() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	before := epochMillis(clock.Now().Add(-s.conf.NamespaceGCAfter))
+
+	for _, ns := range s.listNamespaces(ctx, "") {
+		if ns.LastAccess >= before {
+			continue
+		}
+		removed, err := s.workerPool.Reset(ctx, func(name string) bool { return name == ns.Name })
+		if err != nil {
+			s.log.WithError(err).WithField("name", ns.Name).Error("while removing unused namespace")
+			return
+		}
+		if store, ok := s.conf.Store.(NamespaceStore); ok {
+			if s.storeBatcher != nil {
+				// Report the changes of the namespace before it is removed
+				s.storeBatcher.flush(ctx)
+			}
+			if err := store.RemoveNamespace(ctx, ns.Name); err != nil {
+				s.log.WithError(err).WithField("name", ns.Name).Error("while removing unused namespace from Store")
+			}
+		}
+		s.namespaces.forget(ns.Name, before)
+		metricNamespaceGCCounter.Add(float64(removed))
+		s.log.WithField("name", ns.Name).
+			WithField("removed", removed).
+			WithField("last_access", time.UnixMilli(ns.LastAccess).UTC()).
+			Info("removed unused namespace")
+	}
+}
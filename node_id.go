@@ -0,0 +1,60 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// loadNodeID returns the node ID stored in `path`. If the file does not exist a random node ID is
+// generated and written to it, such that the instance keeps its node ID across restarts. See
+// DaemonConfig.NodeIDFile
+func loadNodeID(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err == nil {
+		id := strings.TrimSpace(string(b))
+		if id == "" {
+			return "", errors.Errorf("node ID file '%s' is empty", path)
+		}
+		return id, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "while reading node ID file")
+	}
+
+	id := RandomString(16)
+	// Written to a temporary file first, such that a crash never leaves a partial node ID behind
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", errors.Wrap(err, "while creating node ID file")
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err = f.WriteString(id + "\n"); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "while writing node ID file")
+	}
+	return id, errors.Wrap(os.Rename(f.Name(), path), "while replacing node ID file")
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadNodeID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node-id")
+
+	// A node ID is generated and written if the file does not exist
+	id, err := loadNodeID(path)
+	require.NoError(t, err)
+	assert.Len(t, id, 16)
+
+	// The same node ID is loaded after a restart
+	again, err := loadNodeID(path)
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// A node ID written by an operator is used as is
+	require.NoError(t, os.WriteFile(path, []byte("  worker-7\n"), 0o600))
+	id, err = loadNodeID(path)
+	require.NoError(t, err)
+	assert.Equal(t, "worker-7", id)
+
+	require.NoError(t, os.WriteFile(path, []byte("\n"), 0o600))
+	_, err = loadNodeID(path)
+	assert.Error(t, err)
+
+	_, err = loadNodeID(filepath.Join(t.TempDir(), "missing", "node-id"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NormalizeFunc changes a rate limit request before it is hashed and evaluated, such that the
+// requests of inconsistent clients, IE: 'Account:1234 ' and 'account:1234', share one rate limit
+// instead of creating a rate limit for each spelling. See Config.Normalizers
+type NormalizeFunc func(r *RateLimitReq)
+
+// NormalizeLowercase lowercases the name and unique key of the request
+func NormalizeLowercase(r *RateLimitReq) {
+	r.Name = strings.ToLower(r.Name)
+	r.UniqueKey = strings.ToLower(r.UniqueKey)
+}
+
+// NormalizeTrimSpace removes the leading and trailing white space of the name and unique key
+func NormalizeTrimSpace(r *RateLimitReq) {
+	r.Name = strings.TrimSpace(r.Name)
+	r.UniqueKey = strings.TrimSpace(r.UniqueKey)
+}
+
+// NewNameAliases returns a NormalizeFunc which replaces each name in `aliases` with the name it
+// maps to, IE: {"login": "user_login"} such that clients using either name share the rate limit.
+func NewNameAliases(aliases map[string]string) NormalizeFunc {
+	return func(r *RateLimitReq) {
+		if name, ok := aliases[r.Name]; ok {
+			r.Name = name
+		}
+	}
+}
+
+// ParseNormalizer returns the built-in normalizer named `name`, either 'lowercase' or 'trim'
+func ParseNormalizer(name string) (NormalizeFunc, error) {
+	switch strings.TrimSpace(name) {
+	case "lowercase":
+		return NormalizeLowercase, nil
+	case "trim":
+		return NormalizeTrimSpace, nil
+	}
+	return nil, errors.Errorf("normalizer '%s' is invalid; choices are ['lowercase', 'trim']", name)
+}
+
+// ParseNameAlias parses an alias in the format used by `GUBER_NAME_ALIASES`, IE: "login=user_login"
+// returns the alias and the name it maps to.
+func ParseNameAlias(s string) (string, string, error) {
+	alias, name, ok := strings.Cut(s, "=")
+	alias, name = strings.TrimSpace(alias), strings.TrimSpace(name)
+	if !ok || alias == "" || name == "" {
+		return "", "", errors.Errorf("'%s' is invalid; expected '<alias>=<name>'", s)
+	}
+	return alias, name, nil
+}
+
+// normalize applies `Config.Normalizers` to the request in order
+func (s *V1Instance) normalize(r *RateLimitReq) {
+	for _, n := range s.conf.Normalizers {
+		n(r)
+	}
+}
+
+// normalizeKey applies `Config.Normalizers` and `Config.LongKeyPolicy` to the name and unique key
+// of requests which identify a rate limit without a RateLimitReq, IE: refunds and reservations.
+func (s *V1Instance) normalizeKey(name, uniqueKey *string) {
+	if len(s.conf.Normalizers) != 0 {
+		r := &RateLimitReq{Name: *name, UniqueKey: *uniqueKey}
+		s.normalize(r)
+		*name, *uniqueKey = r.Name, r.UniqueKey
+	}
+	*uniqueKey = hashLongKey(&s.conf, *uniqueKey)
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// openAPIStatus is the schema of the body of an error response of the HTTP gateway
+const openAPIStatus = "google.rpc.Status"
+
+// OpenAPISpec returns an OpenAPI v3 document describing the HTTP gateway, generated from the
+// `google.api.http` options of the V1 service and, if `admin` is true, the AdminV1 service. Fields
+// have their proto names, as the gateway marshals with UseProtoNames, and 64 bit integers are
+// strings as protojson encodes them. The same document is served at `/openapi.json`.
+func OpenAPISpec(admin bool) ([]byte, error) {
+	g := &openAPIGenerator{
+		paths: make(map[string]map[string]interface{}),
+		schemas: map[string]interface{}{
+			openAPIStatus: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"code":    map[string]interface{}{"type": "integer", "format": "int32"},
+					"message": map[string]interface{}{"type": "string"},
+					"details": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+				},
+			},
+		},
+	}
+	services := []protoreflect.ServiceDescriptor{File_gubernator_proto.Services().ByName("V1")}
+	if admin {
+		services = append(services, File_admin_proto.Services().ByName("AdminV1"))
+	}
+	for _, sd := range services {
+		if err := g.addService(sd); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Gubernator",
+			"description": "The JSON gateway of the Gubernator GRPC API",
+			"version":     Version,
+		},
+		"paths":      g.paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}, "", "  ")
+}
+
+// newOpenAPIHandler serves the OpenAPI document `spec` at `/openapi.json`
+func newOpenAPIHandler(spec []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(spec)
+	})
+}
+
+// openAPIUI is the Swagger UI page served at `/openapi/`, see DaemonConfig.OpenAPIUIEnabled. The
+// Swagger UI assets are loaded from a CDN, as such the browser must be able to reach it.
+const openAPIUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Gubernator API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+// newOpenAPIUIHandler serves the Swagger UI of the OpenAPI document under `/openapi/`
+func newOpenAPIUIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(openAPIUI))
+	})
+}
+
+// openAPIGenerator builds the paths and schemas of an OpenAPI document from service descriptors
+type openAPIGenerator struct {
+	paths   map[string]map[string]interface{}
+	schemas map[string]interface{}
+}
+
+// addService adds an operation for each method of `sd` which has a `google.api.http` option
+func (g *openAPIGenerator) addService(sd protoreflect.ServiceDescriptor) error {
+	for i := 0; i < sd.Methods().Len(); i++ {
+		md := sd.Methods().Get(i)
+		rule, ok := proto.GetExtension(md.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		method, path := httpRulePattern(rule)
+		if method == "" {
+			return fmt.Errorf("method '%s' has an unsupported HTTP rule", md.FullName())
+		}
+
+		op := map[string]interface{}{
+			"operationId": fmt.Sprintf("%s_%s", sd.Name(), md.Name()),
+			"tags":        []string{string(sd.Name())},
+			"responses": map[string]interface{}{
+				"200": openAPIContent("A successful response.", g.ref(md.Output())),
+				"default": openAPIContent("An error response.",
+					map[string]interface{}{"$ref": "#/components/schemas/" + openAPIStatus}),
+			},
+		}
+		switch rule.Body {
+		case "*":
+			body := openAPIContent("The request.", g.ref(md.Input()))
+			body["required"] = true
+			op["requestBody"] = body
+		case "":
+			if params := g.queryParameters(md.Input()); len(params) != 0 {
+				op["parameters"] = params
+			}
+		default:
+			return fmt.Errorf("method '%s' has an unsupported HTTP body '%s'", md.FullName(), rule.Body)
+		}
+
+		if g.paths[path] == nil {
+			g.paths[path] = make(map[string]interface{})
+		}
+		g.paths[path][method] = op
+	}
+	return nil
+}
+
+// httpRulePattern returns the lower case HTTP method and the path of the HTTP rule
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch p := rule.Pattern.(type) {
+	case *annotations.HttpRule_Get:
+		return "get", p.Get
+	case *annotations.HttpRule_Post:
+		return "post", p.Post
+	case *annotations.HttpRule_Put:
+		return "put", p.Put
+	case *annotations.HttpRule_Delete:
+		return "delete", p.Delete
+	case *annotations.HttpRule_Patch:
+		return "patch", p.Patch
+	}
+	return "", ""
+}
+
+func openAPIContent(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// queryParameters returns the query parameters of a method without a body, which are the scalar
+// fields of the request message
+func (g *openAPIGenerator) queryParameters(md protoreflect.MessageDescriptor) []interface{} {
+	var params []interface{}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if fd.IsMap() || fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+			continue
+		}
+		params = append(params, map[string]interface{}{
+			"name":   string(fd.Name()),
+			"in":     "query",
+			"schema": g.fieldSchema(fd),
+		})
+	}
+	return params
+}
+
+// ref returns a reference to the schema of the message `md`, which is added to the schemas along
+// with the schemas of the messages it refers to
+func (g *openAPIGenerator) ref(md protoreflect.MessageDescriptor) map[string]interface{} {
+	name := string(md.FullName())
+	if _, ok := g.schemas[name]; !ok {
+		// Reserve the name before the fields are visited, such that recursive messages terminate
+		g.schemas[name] = nil
+		props := make(map[string]interface{}, md.Fields().Len())
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			props[string(fd.Name())] = g.fieldSchema(fd)
+		}
+		g.schemas[name] = map[string]interface{}{"type": "object", "properties": props}
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+func (g *openAPIGenerator) fieldSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	if fd.IsMap() {
+		return map[string]interface{}{"type": "object", "additionalProperties": g.singularSchema(fd.MapValue())}
+	}
+	if fd.IsList() {
+		return map[string]interface{}{"type": "array", "items": g.singularSchema(fd)}
+	}
+	return g.singularSchema(fd)
+}
+
+// singularSchema returns the schema of a single value of the field `fd` as encoded by protojson
+func (g *openAPIGenerator) singularSchema(fd protoreflect.FieldDescriptor) map[string]interface{} {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]interface{}{"type": "boolean"}
+	case protoreflect.StringKind:
+		return map[string]interface{}{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]interface{}{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]interface{}{"type": "string", "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]interface{}{"type": "string", "enum": names}
+	}
+	return g.ref(fd.Message())
+}
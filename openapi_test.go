@@ -0,0 +1,98 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	type schema struct {
+		Ref        string             `json:"$ref"`
+		Type       string             `json:"type"`
+		Format     string             `json:"format"`
+		Enum       []string           `json:"enum"`
+		Items      *schema            `json:"items"`
+		Properties map[string]*schema `json:"properties"`
+	}
+	type content struct {
+		Content map[string]struct {
+			Schema schema `json:"schema"`
+		} `json:"content"`
+	}
+	type operation struct {
+		OperationID string `json:"operationId"`
+		RequestBody *content
+		Parameters  []struct {
+			Name string `json:"name"`
+			In   string `json:"in"`
+		} `json:"parameters"`
+		Responses map[string]content `json:"responses"`
+	}
+	var doc struct {
+		OpenAPI    string                          `json:"openapi"`
+		Paths      map[string]map[string]operation `json:"paths"`
+		Components struct {
+			Schemas map[string]*schema `json:"schemas"`
+		} `json:"components"`
+	}
+
+	b, err := guber.OpenAPISpec(false)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.NotContains(t, doc.Paths, "/v1/admin/SetOverride")
+
+	op := doc.Paths["/v1/GetRateLimits"]["post"]
+	assert.Equal(t, "V1_GetRateLimits", op.OperationID)
+	require.NotNil(t, op.RequestBody)
+	assert.Equal(t, "#/components/schemas/pb.gubernator.GetRateLimitsReq",
+		op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/pb.gubernator.GetRateLimitsResp",
+		op.Responses["200"].Content["application/json"].Schema.Ref)
+
+	// Fields are named and encoded as the gateway marshals them
+	req := doc.Components.Schemas["pb.gubernator.RateLimitReq"]
+	require.NotNil(t, req)
+	assert.Equal(t, "string", req.Properties["unique_key"].Type)
+	assert.Equal(t, "string", req.Properties["hits"].Type)
+	assert.Equal(t, "int64", req.Properties["hits"].Format)
+	assert.Contains(t, req.Properties["algorithm"].Enum, "LEAKY_BUCKET")
+	assert.Equal(t, "object", req.Properties["metadata"].Type)
+	assert.Equal(t, "#/components/schemas/pb.gubernator.RateLimitReq",
+		doc.Components.Schemas["pb.gubernator.GetRateLimitsReq"].Properties["requests"].Items.Ref)
+
+	// Methods without a body take their fields as query parameters
+	health := doc.Paths["/v1/HealthCheck"]["get"]
+	assert.Nil(t, health.RequestBody)
+	var params []string
+	for _, p := range health.Parameters {
+		assert.Equal(t, "query", p.In)
+		params = append(params, p.Name)
+	}
+	assert.Contains(t, params, "include_versions")
+
+	b, err = guber.OpenAPISpec(true)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &doc))
+	assert.Contains(t, doc.Paths, "/v1/admin/SetOverride")
+}
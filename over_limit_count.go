@@ -0,0 +1,122 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/mailgun/holster/v4/clock"
+)
+
+// MetadataOverLimitCount is set to "true" in the metadata of a RateLimitReq to request the number
+// of times the rate limit responded OVER_LIMIT within `BehaviorConfig.OverLimitRetention`, which
+// is returned in the metadata of the RateLimitResp under the same name. Unlike the status of the
+// rate limit, the count is not reset when the rate limit resets, such that a client may tell a key
+// which is over the limit for the first time from a key which is over the limit all the time.
+const MetadataOverLimitCount = "over_limit_count"
+
+// OverLimitStore is an optional interface a Store may implement to keep the over-limit counts of
+// the rate limits, such that the counts survive restarts and the rate limits moving to another
+// instance. See BehaviorConfig.OverLimitRetention
+type OverLimitStore interface {
+	Store
+	// GetOverLimitCount is called when the count of the rate limit is not in memory, returns the
+	// count and when it expires in epoch milliseconds. Should return false if the store has no
+	// unexpired count of the rate limit.
+	GetOverLimitCount(ctx context.Context, key string) (count int64, expireAt int64, ok bool)
+	// OnOverLimit is called *after* the count of the rate limit was incremented, the store should
+	// expire the count at `expireAt` in epoch milliseconds. Should avoid blocking where possible.
+	OnOverLimit(ctx context.Context, key string, count int64, expireAt int64)
+}
+
+// overLimitCount is the over-limit count of a rate limit held by the worker which owns it
+type overLimitCount struct {
+	count    int64
+	expireAt int64
+}
+
+// overLimitStoreOf returns the OverLimitStore of the store, or nil if it does not implement one
+func overLimitStoreOf(s Store) OverLimitStore {
+	if b, ok := s.(*storeBatcher); ok {
+		s = b.store
+	}
+	store, _ := s.(OverLimitStore)
+	return store
+}
+
+// countOverLimit increments the over-limit count of the rate limit if the owner responded
+// OVER_LIMIT, and returns the count in the metadata of the response if the request asked for it.
+// Counts are kept for `BehaviorConfig.OverLimitRetention` after the last OVER_LIMIT response.
+func (worker *Worker) countOverLimit(ctx context.Context, r *RateLimitReq, reqState RateLimitReqState, rl *RateLimitResp) {
+	if worker.conf.Behaviors.OverLimitRetention <= 0 || !reqState.IsOwner {
+		return
+	}
+	over := rl.Status == Status_OVER_LIMIT && r.Hits > 0
+	asked := r.Metadata[MetadataOverLimitCount] == "true"
+	if !over && !asked {
+		return
+	}
+
+	key := worker.conf.HashKey(r)
+	now := clock.Now()
+	c, ok := worker.overLimit[key]
+	if ok && c.expireAt <= epochMillis(now) {
+		delete(worker.overLimit, key)
+		ok = false
+	}
+	if !ok && worker.overLimitStore != nil {
+		count, expireAt, found := worker.overLimitStore.GetOverLimitCount(ctx, key)
+		if found && expireAt > epochMillis(now) {
+			c, ok = &overLimitCount{count: count, expireAt: expireAt}, true
+			worker.overLimit[key] = c
+		}
+	}
+
+	if over {
+		if !ok {
+			c, ok = &overLimitCount{}, true
+			worker.overLimit[key] = c
+		}
+		c.count++
+		c.expireAt = epochMillis(now.Add(worker.conf.Behaviors.OverLimitRetention))
+		if worker.overLimitStore != nil {
+			worker.overLimitStore.OnOverLimit(ctx, key, c.count, c.expireAt)
+		}
+	}
+
+	if !asked {
+		return
+	}
+	var count int64
+	if ok {
+		count = c.count
+	}
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata[MetadataOverLimitCount] = strconv.FormatInt(count, 10)
+}
+
+func (worker *Worker) expireOverLimit() {
+	now := epochMillis(clock.Now())
+	for key, c := range worker.overLimit {
+		if c.expireAt <= now {
+			delete(worker.overLimit, key)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// MetadataOverride is set to "deny" or "allow" in the metadata of a RateLimitResp
+	// which was decided by an override instead of the rate limit algorithm, or "pause" if
+	// the rate limit is paused by AdminV1.PauseNamespace.
+	MetadataOverride = "override"
+	// MetadataOverrideReason holds `Override.reason` if the override has a reason
+	MetadataOverrideReason = "override_reason"
+)
+
+type overrideKey struct {
+	name      string
+	uniqueKey string
+}
+
+// overrideTable holds a copy of every override in the cluster. Overrides are copied to every
+// peer, such that the peer which receives a request can apply the override without forwarding
+// the request to the owning peer. Overrides are held in memory only, a peer which joins an
+// existing cluster copies the overrides of another peer, see V1Instance.syncOverrides().
+type overrideTable struct {
+	mutex     sync.RWMutex
+	overrides map[overrideKey]*Override
+}
+
+func newOverrideTable() *overrideTable {
+	return &overrideTable{overrides: make(map[overrideKey]*Override)}
+}
+
+// get returns the override which applies to the request. An override for the unique key
+// takes precedence over an override for the entire name.
+func (t *overrideTable) get(r *RateLimitReq, now int64) *Override {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	if len(t.overrides) == 0 {
+		return nil
+	}
+	for _, k := range []overrideKey{{r.Name, r.UniqueKey}, {r.Name, ""}} {
+		if o, ok := t.overrides[k]; ok && !overrideExpired(o, now) {
+			return o
+		}
+	}
+	return nil
+}
+
+// update adds or replaces the overrides in `set` and removes the overrides in `del`.
+func (t *overrideTable) update(set, del []*Override) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, o := range del {
+		delete(t.overrides, overrideKey{o.Name, o.UniqueKey})
+	}
+	for _, o := range set {
+		t.overrides[overrideKey{o.Name, o.UniqueKey}] = proto.Clone(o).(*Override)
+	}
+}
+
+// merge adds the overrides which are not already in the table
+func (t *overrideTable) merge(overrides []*Override) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, o := range overrides {
+		k := overrideKey{o.Name, o.UniqueKey}
+		if _, ok := t.overrides[k]; !ok {
+			t.overrides[k] = proto.Clone(o).(*Override)
+		}
+	}
+}
+
+// list removes the expired overrides and returns the rest sorted by name then unique key
+func (t *overrideTable) list(now int64) []*Override {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := make([]*Override, 0, len(t.overrides))
+	for k, o := range t.overrides {
+		if overrideExpired(o, now) {
+			delete(t.overrides, k)
+			continue
+		}
+		result = append(result, proto.Clone(o).(*Override))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].UniqueKey < result[j].UniqueKey
+	})
+	return result
+}
+
+func overrideExpired(o *Override, now int64) bool {
+	return o.ExpireAt != 0 && o.ExpireAt <= now
+}
+
+// applyOverride returns the response decided by the override for the request. No hits are
+// applied to the rate limit when a DENY or ALLOW override applies.
+func applyOverride(r *RateLimitReq, o *Override) *RateLimitResp {
+	action := strings.ToLower(o.Action.String())
+	metricOverrideCounter.WithLabelValues(action).Inc()
+	resp := &RateLimitResp{
+		Limit:    r.Limit,
+		Metadata: map[string]string{MetadataOverride: action},
+	}
+	if o.Reason != "" {
+		resp.Metadata[MetadataOverrideReason] = o.Reason
+	}
+
+	switch o.Action {
+	case OverrideAction_DENY:
+		resp.Status = Status_OVER_LIMIT
+		resp.ResetTime = o.ExpireAt
+		if resp.ResetTime == 0 {
+			resp.ResetTime = *r.CreatedAt + r.Duration
+		}
+	case OverrideAction_ALLOW:
+		resp.Status = Status_UNDER_LIMIT
+		resp.Remaining = r.Limit
+	}
+	return resp
+}
+
+// applyPause evaluates the request as DRY_RUN, such that the hits are applied but the response
+// is UNDER_LIMIT, and adds the metadata of the PAUSE override to the response.
+func applyPause(ctx context.Context, c *RateLimitCheck, o *Override, next RateLimitHandler) (*RateLimitResp, error) {
+	metricOverrideCounter.WithLabelValues("pause").Inc()
+	SetBehavior(&c.Req.Behavior, Behavior_DRY_RUN, true)
+	rl, err := next(ctx, c)
+	if err != nil || rl == nil {
+		return rl, err
+	}
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata[MetadataOverride] = "pause"
+	if o.Reason != "" {
+		rl.Metadata[MetadataOverrideReason] = o.Reason
+	}
+	return rl, nil
+}
+
+// SetOverride sets the override on every peer in the cluster, including peers in other regions.
+func (s *V1Instance) SetOverride(ctx context.Context, r *SetOverrideReq) (*SetOverrideResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.SetOverride")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if err := validateOverride(r.Override); err != nil {
+		return nil, err
+	}
+
+	errs := s.broadcastOverrides(ctx, &UpdatePeerOverridesReq{Set: []*Override{r.Override}})
+	if s.conf.AuditSink != nil {
+		s.conf.AuditSink.Record(AuditRecord{
+			Time:       clock.Now(),
+			Type:       AuditSetOverride,
+			InstanceID: s.conf.InstanceID,
+			Name:       r.Override.Name,
+			UniqueKey:  r.Override.UniqueKey,
+			Action:     strings.ToLower(r.Override.Action.String()),
+			Reason:     r.Override.Reason,
+			ExpireAt:   r.Override.ExpireAt,
+			Errors:     errs,
+		})
+	}
+	s.log.WithField("name", r.Override.Name).
+		WithField("unique_key", redactKey(s.conf.KeyRedaction, r.Override.UniqueKey)).
+		WithField("action", r.Override.Action.String()).
+		WithField("errors", len(errs)).
+		Warn("override set via admin API")
+	return &SetOverrideResp{Errors: errs}, nil
+}
+
+// DeleteOverride removes the override from every peer in the cluster, including peers in other regions.
+func (s *V1Instance) DeleteOverride(ctx context.Context, r *DeleteOverrideReq) (*DeleteOverrideResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.DeleteOverride")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if r.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	}
+
+	del := &Override{Name: r.Name, UniqueKey: r.UniqueKey}
+	errs := s.broadcastOverrides(ctx, &UpdatePeerOverridesReq{Delete: []*Override{del}})
+	if s.conf.AuditSink != nil {
+		s.conf.AuditSink.Record(AuditRecord{
+			Time:       clock.Now(),
+			Type:       AuditDeleteOverride,
+			InstanceID: s.conf.InstanceID,
+			Name:       r.Name,
+			UniqueKey:  r.UniqueKey,
+			Errors:     errs,
+		})
+	}
+	s.log.WithField("name", r.Name).
+		WithField("unique_key", redactKey(s.conf.KeyRedaction, r.UniqueKey)).
+		WithField("errors", len(errs)).
+		Warn("override deleted via admin API")
+	return &DeleteOverrideResp{Errors: errs}, nil
+}
+
+// ListOverrides returns the overrides known by this instance
+func (s *V1Instance) ListOverrides(ctx context.Context, r *ListOverridesReq) (*ListOverridesResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListOverrides")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	return &ListOverridesResp{Overrides: s.overrides.list(epochMillis(clock.Now()))}, nil
+}
+
+// PauseNamespace sets a PAUSE override for the entire name on every peer in the cluster, including
+// peers in other regions.
+func (s *V1Instance) PauseNamespace(ctx context.Context, r *PauseNamespaceReq) (*PauseNamespaceResp, error) {
+	resp, err := s.SetOverride(ctx, &SetOverrideReq{Override: &Override{
+		Name:     r.Name,
+		Action:   OverrideAction_PAUSE,
+		ExpireAt: r.ExpireAt,
+		Reason:   r.Reason,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return &PauseNamespaceResp{Errors: resp.Errors}, nil
+}
+
+// ResumeNamespace removes the PAUSE override for the entire name from every peer in the cluster,
+// including peers in other regions. Fails if this instance holds another override for the name,
+// such that resuming a namespace never lifts a DENY or ALLOW override set with SetOverride.
+func (s *V1Instance) ResumeNamespace(ctx context.Context, r *ResumeNamespaceReq) (*ResumeNamespaceResp, error) {
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if o := s.overrides.get(&RateLimitReq{Name: r.Name}, epochMillis(clock.Now())); o != nil &&
+		o.Action != OverrideAction_PAUSE {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"namespace '%s' is not paused; it has a '%s' override, see DeleteOverride", r.Name, o.Action)
+	}
+	resp, err := s.DeleteOverride(ctx, &DeleteOverrideReq{Name: r.Name})
+	if err != nil {
+		return nil, err
+	}
+	return &ResumeNamespaceResp{Errors: resp.Errors}, nil
+}
+
+// UpdatePeerOverrides is called by other peers to update the overrides of this peer.
+func (s *V1Instance) UpdatePeerOverrides(ctx context.Context, r *UpdatePeerOverridesReq) (*UpdatePeerOverridesResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.UpdatePeerOverrides")).ObserveDuration()
+	for _, o := range r.Set {
+		if err := validateOverride(o); err != nil {
+			return nil, err
+		}
+	}
+	s.overrides.update(r.Set, r.Delete)
+	return &UpdatePeerOverridesResp{}, nil
+}
+
+// ListPeerOverrides is called by peers which joined the cluster to copy the overrides of this peer.
+func (s *V1Instance) ListPeerOverrides(ctx context.Context, r *ListOverridesReq) (*ListOverridesResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListPeerOverrides")).ObserveDuration()
+	return &ListOverridesResp{Overrides: s.overrides.list(epochMillis(clock.Now()))}, nil
+}
+
+// broadcastOverrides sends the update to every peer in the cluster and returns an
+// error for each peer which failed.
+func (s *V1Instance) broadcastOverrides(ctx context.Context, r *UpdatePeerOverridesReq) []string {
+	peers := s.GetPeerList()
+	for _, picker := range s.GetRegionPickers() {
+		peers = append(peers, picker.Peers()...)
+	}
+
+	// Always update this instance, even if it is not yet in the list of peers
+	s.overrides.update(r.Set, r.Delete)
+
+	var (
+		errs  []string
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	for _, peer := range peers {
+		if peer.Info().IsOwner {
+			continue
+		}
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			if _, err := peer.UpdatePeerOverrides(ctx, r); err != nil {
+				mutex.Lock()
+				errs = append(errs, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				mutex.Unlock()
+			}
+		}(peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// syncOverrides copies the overrides of the first peer in the local data center which
+// responds. Overrides set on this instance while syncing take precedence over the copy.
+// Until a peer responds, syncOverrides is called again each time the peers change.
+func (s *V1Instance) syncOverrides() {
+	defer s.overridesSyncing.Store(false)
+
+	var peers []*PeerClient
+	for _, peer := range s.GetPeerList() {
+		if !peer.Info().IsOwner {
+			peers = append(peers, peer)
+		}
+	}
+	for _, peer := range peers {
+		ctx, cancel := context.WithTimeout(context.Background(), s.conf.Behaviors.BatchTimeout)
+		resp, err := peer.ListPeerOverrides(ctx, &ListOverridesReq{})
+		cancel()
+		if err != nil {
+			s.log.WithError(err).WithField("peer", peer.Info().GRPCAddress).
+				Debug("while copying overrides from peer")
+			continue
+		}
+		s.overrides.merge(resp.Overrides)
+		s.overridesSynced.Store(true)
+		if len(resp.Overrides) > 0 {
+			s.log.WithField("overrides", len(resp.Overrides)).
+				WithField("peer", peer.Info().GRPCAddress).
+				Info("copied overrides from peer")
+		}
+		return
+	}
+	if len(peers) > 0 {
+		s.log.Warn("unable to copy overrides from any peer; will retry when the peers change")
+	}
+}
+
+func validateOverride(o *Override) error {
+	switch {
+	case o == nil:
+		return status.Error(codes.InvalidArgument, "field 'override' cannot be empty")
+	case o.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case o.Action != OverrideAction_DENY && o.Action != OverrideAction_ALLOW && o.Action != OverrideAction_PAUSE:
+		return status.Errorf(codes.InvalidArgument, "invalid 'action' '%d'", o.Action)
+	case o.ExpireAt < 0:
+		return status.Error(codes.InvalidArgument, "field 'expire_at' cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const peerAuthHeader = "authorization"
+
+// PeerAuthConfig authenticates the RPCs of the PeersV1 service with a shared bearer token, such
+// that only other peers may forward rate limits to this instance. Requests made to the V1 service
+// are not authenticated.
+type PeerAuthConfig struct {
+	// (Required) The token sent with every request made to other peers
+	Token string
+
+	// (Optional) Previous tokens which are still accepted from other peers. To rotate the token
+	// without downtime, first deploy the new token to AcceptTokens on every peer, then swap Token and
+	// AcceptTokens, then remove the old token once every peer has been restarted.
+	AcceptTokens []string
+}
+
+func (a *PeerAuthConfig) validate() error {
+	if a.Token == "" {
+		return errors.New("PeerAuth.Token cannot be empty")
+	}
+	for _, t := range a.AcceptTokens {
+		if t == "" {
+			return errors.New("PeerAuth.AcceptTokens cannot contain an empty token")
+		}
+	}
+	return nil
+}
+
+// authorize returns codes.Unauthenticated unless the incoming metadata of `ctx` contains an
+// accepted token.
+func (a *PeerAuthConfig) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(peerAuthHeader) {
+		token, ok := strings.CutPrefix(v, "Bearer ")
+		if !ok {
+			continue
+		}
+		for _, accept := range append([]string{a.Token}, a.AcceptTokens...) {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(accept)) == 1 {
+				return nil
+			}
+		}
+	}
+	metricCheckErrorCounter.WithLabelValues("Peer unauthenticated").Inc()
+	return status.Error(codes.Unauthenticated, "peer authentication failed; missing or invalid bearer token")
+}
+
+// UnaryServerInterceptor returns a server interceptor which rejects requests made to the PeersV1
+// service without an accepted token. Requests made to other services are passed through.
+func (a *PeerAuthConfig) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	prefix := "/" + PeersV1_ServiceDesc.ServiceName + "/"
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, prefix) {
+			if err := a.authorize(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// perRPCCredentials attaches the token to every request made to a peer
+func (a *PeerAuthConfig) perRPCCredentials() credentials.PerRPCCredentials {
+	return peerAuthCreds{header: "Bearer " + a.Token}
+}
+
+type peerAuthCreds struct {
+	header string
+}
+
+func (c peerAuthCreds) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{peerAuthHeader: c.header}, nil
+}
+
+// RequireTransportSecurity returns false as peers are not required to use TLS, however without
+// TLS the token is sent in plain text.
+func (c peerAuthCreds) RequireTransportSecurity() bool {
+	return false
+}
@@ -20,12 +20,15 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/collections"
 	"github.com/mailgun/holster/v4/errors"
+	"github.com/mailgun/holster/v4/setter"
 	"github.com/mailgun/holster/v4/tracing"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
@@ -34,7 +37,9 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
@@ -48,16 +53,58 @@ type PeerPicker interface {
 	Add(*PeerClient)
 }
 
+// DeltaPeerPicker is implemented by PeerPickers which can add and remove peers from a copy of the
+// picker, such that V1Instance.UpdatePeers() changes the ring incrementally instead of building a
+// new picker with every peer.
+type DeltaPeerPicker interface {
+	// Clone returns a copy of the picker which may be changed without changing the original
+	Clone() PeerPicker
+	// Remove removes the peer with the GRPC address of the PeerInfo from the picker
+	Remove(PeerInfo)
+}
+
+// RingOwnershipPicker is implemented by PeerPickers which can report the fraction of the key
+// space owned by each peer, IE: to show how evenly the hash ring is distributed.
+type RingOwnershipPicker interface {
+	// RingOwnership returns the fraction of the key space owned by each peer, keyed by GRPC address
+	RingOwnership() map[string]float64
+}
+
+// SecondaryPicker is implemented by PeerPickers which can pick a peer other than the owner of a key,
+// IE: to evaluate the rate limits owned by a peer which is slow to respond.
+type SecondaryPicker interface {
+	// GetSecondary returns the peer which would own the key if the owner was removed, or nil if
+	// there is no other peer
+	GetSecondary(key string) *PeerClient
+}
+
+// errPeerClosing is returned by PeerClient methods once Shutdown() has been called
+var errPeerClosing = status.Error(codes.Canceled, "grpc: the client connection is closing")
+
 type PeerClient struct {
-	client      PeersV1Client
-	conn        *grpc.ClientConn
-	conf        PeerConfig
-	queue       chan *request
-	queueClosed atomic.Bool
-	lastErrs    *collections.LRUCache
+	client   PeersV1Client
+	conn     *grpc.ClientConn
+	quic     *quicClientConn // Is nil unless PeerConfig.Transport is PeerTransportQUIC
+	conf     PeerConfig
+	queue    chan *request
+	queued   chan struct{} // Holds a slot for each request queued or being sent, see BehaviorConfig.BatchQueueLimit
+	lastErrs *collections.LRUCache
+	stats    *peerStats
 
 	wgMutex sync.RWMutex
 	wg      sync.WaitGroup // Monitor the number of in-flight requests. GUARDED_BY(wgMutex)
+	closing bool           // No new requests are accepted once true. GUARDED_BY(wgMutex)
+
+	shutdownOnce sync.Once
+	shutdown     chan struct{} // Closed once all in-flight requests finish and the connection is closed
+
+	// Set once the peer turned out to be this instance under another address
+	self atomic.Bool
+	// The weight the peer advertised after the client was created, zero if it did not change
+	weight atomic.Int64
+
+	coalesceMutex sync.Mutex
+	coalescing    map[coalesceKey]*request // Queued requests which identical requests join. GUARDED_BY(coalesceMutex)
 }
 
 type response struct {
@@ -70,6 +117,12 @@ type request struct {
 	reqState RateLimitReqState
 	resp     chan *response
 	ctx      context.Context
+	// Set if identical requests may be coalesced into the request, see BehaviorConfig.CoalesceRequests
+	coalesceKey *coalesceKey
+	// The identical requests coalesced into the request. GUARDED_BY(PeerClient.coalesceMutex)
+	coalesced []chan *response
+	// Set if the request holds a slot of PeerClient.queued
+	queued bool
 }
 
 type PeerConfig struct {
@@ -78,36 +131,56 @@ type PeerConfig struct {
 	Info      PeerInfo
 	Log       FieldLogger
 	TraceGRPC bool
+	// The name of the GRPC compressor used for requests to the peer, empty for no compression
+	Compression string
+	// Requests smaller than this number of bytes are not compressed
+	CompressionMinBytes int
+	// If not nil, faults are injected into requests to the peer
+	Faults *FaultConfig
+	// If not nil, the token is sent with every request to the peer
+	Auth *PeerAuthConfig
+	// If not nil, the request values forwarded to the peer are signed
+	ValueSigning *RequestValueSigningConfig
+	// The ID of the instance, sent with each rate limit forwarded to the peer such that an instance
+	// which is in its own peer list under another address detects it
+	NodeID string
+	// Either PeerTransportGRPC or PeerTransportQUIC, defaults to PeerTransportGRPC. Only GRPC
+	// supports TraceGRPC, Compression and Faults
+	Transport string
+	// How unique keys appear in traces, see Config.KeyRedaction. Defaults to KeyRedactionHash
+	KeyRedaction string
 }
 
 // NewPeerClient tries to establish a connection to a peer in a non-blocking fashion.
 // If batching is enabled, it also starts a goroutine where batches will be processed.
 func NewPeerClient(conf PeerConfig) (*PeerClient, error) {
+	setter.SetDefault(&conf.Behavior.PeerReconnectBaseDelay, time.Millisecond*100)
+	setter.SetDefault(&conf.Behavior.PeerReconnectMaxDelay, time.Second*5)
+	setter.SetDefault(&conf.Behavior.BatchQueueLimit, 1000)
+	setter.SetDefault(&conf.Behavior.BatchQueueWait, time.Millisecond*500)
+	setter.SetDefault(&conf.Behavior.BatchOverflowPolicy, BatchOverflowReject)
+
 	peerClient := &PeerClient{
-		queue:    make(chan *request, 1000),
+		queue:    make(chan *request, conf.Behavior.BatchQueueLimit),
+		queued:   make(chan struct{}, conf.Behavior.BatchQueueLimit),
 		conf:     conf,
 		lastErrs: collections.NewLRUCache(100),
+		stats:    newPeerStats(conf.Info.GRPCAddress),
+		shutdown: make(chan struct{}),
 	}
-	var opts []grpc.DialOption
-
-	if conf.TraceGRPC {
-		opts = []grpc.DialOption{
-			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-		}
+	if conf.Behavior.CoalesceRequests {
+		peerClient.coalescing = make(map[coalesceKey]*request)
 	}
 
-	if conf.TLS != nil {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(conf.TLS)))
+	var err error
+	if conf.Transport == PeerTransportQUIC {
+		err = peerClient.dialQUIC()
 	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		err = peerClient.dialGRPC()
 	}
-
-	var err error
-	peerClient.conn, err = grpc.Dial(conf.Info.GRPCAddress, opts...)
 	if err != nil {
 		return nil, err
 	}
-	peerClient.client = NewPeersV1Client(peerClient.conn)
 
 	if !conf.Behavior.DisableBatching {
 		go peerClient.runBatch()
@@ -115,9 +188,141 @@ func NewPeerClient(conf PeerConfig) (*PeerClient, error) {
 	return peerClient, nil
 }
 
+// dialGRPC establishes the GRPC connection to the peer in a non-blocking fashion.
+func (c *PeerClient) dialGRPC() error {
+	opts := []grpc.DialOption{
+		grpc.WithStatsHandler(c.stats),
+	}
+
+	if c.conf.TraceGRPC {
+		opts = append(opts, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	}
+
+	if c.conf.TLS != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(c.conf.TLS)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	var interceptors []grpc.UnaryClientInterceptor
+	if c.conf.Compression != "" {
+		interceptors = append(interceptors, compressionInterceptor(c.conf.Compression, c.conf.CompressionMinBytes))
+	}
+
+	if c.conf.Auth != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(c.conf.Auth.perRPCCredentials()))
+	}
+
+	if c.conf.Faults != nil {
+		interceptors = append(interceptors, c.conf.Faults.unaryInterceptor())
+	}
+	if len(interceptors) != 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(interceptors...))
+	}
+
+	// Reconnect to restarted peers quickly, with jitter such that peers do not
+	// reconnect in lock step, instead of using the GRPC default of 1 second.
+	bc := backoff.DefaultConfig
+	bc.BaseDelay = c.conf.Behavior.PeerReconnectBaseDelay
+	bc.MaxDelay = c.conf.Behavior.PeerReconnectMaxDelay
+	opts = append(opts, grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff:           bc,
+		MinConnectTimeout: time.Second * 5,
+	}))
+
+	var err error
+	c.conn, err = grpc.Dial(c.conf.Info.GRPCAddress, opts...)
+	if err != nil {
+		return err
+	}
+	c.client = NewPeersV1Client(c.conn)
+	go c.monitorConnectivity()
+	return nil
+}
+
+// dialQUIC sends peer RPCs over HTTP/3, see quic.go for details.
+func (c *PeerClient) dialQUIC() error {
+	if c.conf.TLS == nil {
+		return errors.New("the QUIC peer transport requires TLS")
+	}
+	c.quic = newQUICClientConn(c.conf.Info.GRPCAddress, c.conf.TLS, c.conf.Auth)
+	c.quic.stats = c.stats
+	c.client = NewPeersV1Client(c.quic)
+	return nil
+}
+
+// acquire holds a reference to the client for the duration of an in-flight request, such that
+// Shutdown() will not close the connection until the request has finished. Returns errPeerClosing
+// if the client is shutting down. Every successful call must be followed by a call to release().
+func (c *PeerClient) acquire() error {
+	// NOTE: This must be done within the Lock since calling Wait() in Shutdown() causes
+	// a race condition if called within a separate go routine if the internal wg is `0`
+	// when Wait() is called then Add(1) is called concurrently.
+	c.wgMutex.Lock()
+	defer c.wgMutex.Unlock()
+	if c.closing {
+		return errPeerClosing
+	}
+	c.wg.Add(1)
+	return nil
+}
+
+func (c *PeerClient) release() {
+	c.wg.Done()
+}
+
+// monitorConnectivity records the state of the connection to the peer and reconnects as soon as
+// the connection goes idle. The connection goes idle when the peer restarts and sends a GOAWAY,
+// in which case GRPC would otherwise wait for the next request before reconnecting, failing the
+// requests which arrive while the new connection is established.
+func (c *PeerClient) monitorConnectivity() {
+	peerAddr := c.conf.Info.GRPCAddress
+	defer metricPeerConnectionState.DeleteLabelValues(peerAddr)
+
+	state := c.conn.GetState()
+	for {
+		metricPeerConnectionState.WithLabelValues(peerAddr).Set(float64(state))
+		switch state {
+		case connectivity.Shutdown:
+			return
+		case connectivity.Idle:
+			// Jitter the reconnect, else every peer reconnects to a restarted peer at once
+			select {
+			case <-clock.After(time.Duration(rand.Int63n(int64(c.conf.Behavior.PeerReconnectBaseDelay) + 1))):
+				c.conn.Connect()
+			case <-c.shutdown:
+				return
+			}
+		}
+		if !c.conn.WaitForStateChange(context.Background(), state) {
+			return
+		}
+		state = c.conn.GetState()
+	}
+}
+
 // Info returns PeerInfo struct that describes this PeerClient
 func (c *PeerClient) Info() PeerInfo {
-	return c.conf.Info
+	info := c.conf.Info
+	if c.self.Load() {
+		info.IsOwner = true
+	}
+	if w := c.weight.Load(); w != 0 {
+		info.Weight = int(w)
+	}
+	return info
+}
+
+// setWeight records the weight the peer advertises, which changes when a peer sheds load, see
+// DaemonConfig.LoadSheddingCPUPercent. Pickers read the weight when the peer is added.
+func (c *PeerClient) setWeight(weight int) {
+	c.weight.Store(int64(weight))
+}
+
+// markSelf flags the peer as this instance, such that Info() reports it as the owner. Returns false
+// if the peer was already flagged.
+func (c *PeerClient) markSelf() bool {
+	return c.self.CompareAndSwap(false, true)
 }
 
 // GetPeerRateLimit forwards a rate limit request to a peer. If the rate limit has `behavior == BATCHING` configured,
@@ -125,7 +330,7 @@ func (c *PeerClient) Info() PeerInfo {
 func (c *PeerClient) GetPeerRateLimit(ctx context.Context, r *RateLimitReq) (resp *RateLimitResp, err error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
-		attribute.String("ratelimit.key", r.UniqueKey),
+		attribute.String("ratelimit.key", redactKey(c.conf.KeyRedaction, r.UniqueKey)),
 		attribute.String("ratelimit.name", r.Name),
 	)
 
@@ -139,6 +344,8 @@ func (c *PeerClient) GetPeerRateLimit(ctx context.Context, r *RateLimitReq) (res
 		// peers can continue to report traces for this rate limit.
 		prop := propagation.TraceContext{}
 		prop.Inject(ctx, &MetadataCarrier{Map: r.Metadata})
+		injectRequestValues(ctx, r, c.conf.ValueSigning)
+		c.injectNodeID(r)
 
 		// Send a single low latency rate limit request
 		resp, err := c.GetPeerRateLimits(ctx, &GetPeerRateLimitsReq{
@@ -162,15 +369,14 @@ func (c *PeerClient) GetPeerRateLimit(ctx context.Context, r *RateLimitReq) (res
 
 // GetPeerRateLimits requests a list of rate limit statuses from a peer
 func (c *PeerClient) GetPeerRateLimits(ctx context.Context, r *GetPeerRateLimitsReq) (resp *GetPeerRateLimitsResp, err error) {
-	// NOTE: This must be done within the Lock since calling Wait() in Shutdown() causes
-	// a race condition if called within a separate go routine if the internal wg is `0`
-	// when Wait() is called then Add(1) is called concurrently.
-	c.wgMutex.Lock()
-	c.wg.Add(1)
-	c.wgMutex.Unlock()
-	defer c.wg.Done()
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
 
+	start := clock.Now()
 	resp, err = c.client.GetPeerRateLimits(ctx, r)
+	c.observeSlowRPC(ctx, start, len(r.Requests))
 	if err != nil {
 		err = errors.Wrap(err, "Error in client.GetPeerRateLimits")
 		// metricCheckErrorCounter is updated within client.GetPeerRateLimits().
@@ -188,12 +394,10 @@ func (c *PeerClient) GetPeerRateLimits(ctx context.Context, r *GetPeerRateLimits
 
 // UpdatePeerGlobals sends global rate limit status updates to a peer
 func (c *PeerClient) UpdatePeerGlobals(ctx context.Context, r *UpdatePeerGlobalsReq) (resp *UpdatePeerGlobalsResp, err error) {
-
-	// See NOTE above about RLock and wg.Add(1)
-	c.wgMutex.Lock()
-	c.wg.Add(1)
-	c.wgMutex.Unlock()
-	defer c.wg.Done()
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
 
 	resp, err = c.client.UpdatePeerGlobals(ctx, r)
 	if err != nil {
@@ -203,6 +407,333 @@ func (c *PeerClient) UpdatePeerGlobals(ctx context.Context, r *UpdatePeerGlobals
 	return resp, err
 }
 
+// ResetPeerRateLimits removes the matching rate limits from the peer
+func (c *PeerClient) ResetPeerRateLimits(ctx context.Context, r *ResetPeerRateLimitsReq) (resp *ResetPeerRateLimitsResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ResetPeerRateLimits(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// RefundPeerRateLimit relays a refund to the peer which owns the rate limit
+func (c *PeerClient) RefundPeerRateLimit(ctx context.Context, r *RefundReq) (resp *RefundResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.RefundPeerRateLimit(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// UpdatePeerOverrides sets or removes overrides on the peer
+func (c *PeerClient) UpdatePeerOverrides(ctx context.Context, r *UpdatePeerOverridesReq) (resp *UpdatePeerOverridesResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.UpdatePeerOverrides(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ListPeerOverrides returns the overrides known by the peer
+func (c *PeerClient) ListPeerOverrides(ctx context.Context, r *ListOverridesReq) (resp *ListOverridesResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ListPeerOverrides(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// RegisterPeerLimits registers limits on the peer
+func (c *PeerClient) RegisterPeerLimits(ctx context.Context, r *RegisterLimitsReq) (resp *RegisterLimitsResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.RegisterPeerLimits(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ListPeerLimits returns the limits registered on the peer
+func (c *PeerClient) ListPeerLimits(ctx context.Context, r *ListPeerLimitsReq) (resp *ListPeerLimitsResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ListPeerLimits(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ReservePeerRateLimit relays a reservation to the peer which owns the rate limit
+func (c *PeerClient) ReservePeerRateLimit(ctx context.Context, r *ReserveRateLimitReq) (resp *ReserveRateLimitResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ReservePeerRateLimit(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// CommitPeerReservation commits a reservation held by the peer
+func (c *PeerClient) CommitPeerReservation(ctx context.Context, r *ReservationReq) (resp *ReservationResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.CommitPeerReservation(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// CancelPeerReservation cancels a reservation held by the peer
+func (c *PeerClient) CancelPeerReservation(ctx context.Context, r *ReservationReq) (resp *ReservationResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.CancelPeerReservation(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// AcquirePeerLease relays a lease to the peer which owns the lease
+func (c *PeerClient) AcquirePeerLease(ctx context.Context, r *LeaseReq) (resp *LeaseResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.AcquirePeerLease(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ReleasePeerLease releases a lease held by the peer
+func (c *PeerClient) ReleasePeerLease(ctx context.Context, r *LeaseReq) (resp *LeaseResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ReleasePeerLease(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerNamespaceUsage returns the usage of the rate limits owned by the peer
+func (c *PeerClient) GetPeerNamespaceUsage(ctx context.Context, r *GetNamespaceUsageReq) (resp *GetNamespaceUsageResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerNamespaceUsage(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerKeyHistory returns the state of the rate limit and the hits recorded by the peer
+func (c *PeerClient) GetPeerKeyHistory(ctx context.Context, r *GetKeyHistoryReq) (resp *GetKeyHistoryResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerKeyHistory(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerLimitDrift returns the drift of the rate limits owned by the peer
+func (c *PeerClient) GetPeerLimitDrift(ctx context.Context, r *GetLimitDriftReq) (resp *GetLimitDriftResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerLimitDrift(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ListPeerNamespaces returns the namespaces in the cache of the peer
+func (c *PeerClient) ListPeerNamespaces(ctx context.Context, r *ListNamespacesReq) (resp *ListNamespacesResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ListPeerNamespaces(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerVersion returns the build version of the peer
+func (c *PeerClient) GetPeerVersion(ctx context.Context, r *GetPeerVersionReq) (resp *GetPeerVersionResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerVersion(ctx, r)
+	// Peers running a version of gubernator which predates GetPeerVersion are not unhealthy
+	if err != nil && !isRequestError(err) && status.Code(err) != codes.Unimplemented {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerTraffic returns the traffic the peer sent to other peers
+func (c *PeerClient) GetPeerTraffic(ctx context.Context, r *GetTrafficReq) (resp *GetTrafficResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerTraffic(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ReplayPeerJournal replays the journal of the peer
+func (c *PeerClient) ReplayPeerJournal(ctx context.Context, r *ReplayJournalReq) (resp *ReplayJournalResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ReplayPeerJournal(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// TransferPeerRateLimits sends the rate limits the peer will own once this instance shuts down
+func (c *PeerClient) TransferPeerRateLimits(ctx context.Context, r *TransferPeerRateLimitsReq) (resp *TransferPeerRateLimitsResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.TransferPeerRateLimits(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// ReplicatePeerRateLimits sends the state of the rate limits this instance owns to their standby peer
+func (c *PeerClient) ReplicatePeerRateLimits(ctx context.Context, r *ReplicatePeerRateLimitsReq) (resp *ReplicatePeerRateLimitsResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.ReplicatePeerRateLimits(ctx, r)
+	if err != nil && !isRequestError(err) {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// GetPeerKeyFilter returns a filter of the keys in the cache of the peer, see Config.ColdKeyFilterInterval
+func (c *PeerClient) GetPeerKeyFilter(ctx context.Context, r *GetPeerKeyFilterReq) (resp *GetPeerKeyFilterResp, err error) {
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
+
+	resp, err = c.client.GetPeerKeyFilter(ctx, r)
+	// Peers running a version of gubernator which predates GetPeerKeyFilter are not unhealthy
+	if err != nil && !isRequestError(err) && status.Code(err) != codes.Unimplemented {
+		_ = c.setLastErr(err)
+	}
+
+	return resp, err
+}
+
+// isRequestError returns true if the peer rejected the request itself, such errors say
+// nothing about the health of the peer and are not reported by GetLastErr().
+func isRequestError(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.FailedPrecondition:
+		return true
+	}
+	return false
+}
+
 func (c *PeerClient) setLastErr(err error) error {
 	// If we get a nil error return without caching it
 	if err == nil {
@@ -244,27 +775,38 @@ func (c *PeerClient) getPeerRateLimitsBatch(ctx context.Context, r *RateLimitReq
 		request: r,
 	}
 
-	c.wgMutex.Lock()
-	c.wg.Add(1)
-	c.wgMutex.Unlock()
-	defer c.wg.Done()
-
-	// Enqueue the request to be sent
-	peerAddr := c.Info().GRPCAddress
-	metricBatchQueueLength.WithLabelValues(peerAddr).Set(float64(len(c.queue)))
+	// Holding a reference prevents Shutdown() from closing the queue while we enqueue
+	if err := c.acquire(); err != nil {
+		return nil, err
+	}
+	defer c.release()
 
-	if c.queueClosed.Load() {
-		// this check prevents "panic: send on close channel"
-		return nil, status.Error(codes.Canceled, "grpc: the client connection is closing")
+	// Join an identical request which is waiting to be sent
+	if c.coalescing != nil {
+		if resp := c.coalesce(ctx, &req); resp != nil {
+			req.resp = resp
+			goto wait
+		}
 	}
 
-	select {
-	case c.queue <- &req:
-		// Successfully enqueued request.
-	case <-ctx.Done():
-		return nil, errors.Wrap(ctx.Err(), "Context error while enqueuing request")
+	// Enqueue the request to be sent
+	metricBatchQueueLength.WithLabelValues(c.Info().GRPCAddress).Set(float64(len(c.queue)))
+
+	if ok, err := c.enqueue(ctx, &req); err != nil {
+		err := errors.Wrap(err, "Context error while enqueuing request")
+		if req.coalesceKey != nil {
+			// The requests which joined this request are not sent either
+			queue := []*request{&req}
+			c.stopCoalescing(queue)
+			req.respond(&response{err: err})
+		}
+		return nil, err
+	} else if !ok {
+		c.overflow(ctx, &req)
 	}
 
+wait:
+
 	// Wait for a response or context cancel
 	select {
 	case re := <-req.resp:
@@ -344,6 +886,11 @@ func (c *PeerClient) sendBatch(ctx context.Context, queue []*request) {
 	funcTimer := prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("PeerClient.sendBatch"))
 	defer funcTimer.ObserveDuration()
 
+	if c.coalescing != nil {
+		c.stopCoalescing(queue)
+	}
+	defer c.dequeue(queue)
+
 	var req GetPeerRateLimitsReq
 	for _, r := range queue {
 		// NOTE: This trace has the same name because it's in a separate trace than the one above.
@@ -358,12 +905,16 @@ func (c *PeerClient) sendBatch(ctx context.Context, queue []*request) {
 		// peers can continue to report traces for this rate limit.
 		prop := propagation.TraceContext{}
 		prop.Inject(r.ctx, &MetadataCarrier{Map: r.request.Metadata})
+		injectRequestValues(r.ctx, r.request, c.conf.ValueSigning)
+		c.injectNodeID(r.request)
 		req.Requests = append(req.Requests, r.request)
 		tracing.EndScope(r.ctx, nil)
 	}
 
 	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, c.conf.Behavior.BatchTimeout)
+	start := clock.Now()
 	resp, err := c.client.GetPeerRateLimits(timeoutCtx, &req)
+	c.observeSlowRPC(ctx, start, len(queue))
 	timeoutCancel()
 
 	// An error here indicates the entire request failed
@@ -381,7 +932,7 @@ func (c *PeerClient) sendBatch(ctx context.Context, queue []*request) {
 		// metricCheckErrorCounter is updated within client.GetPeerRateLimits().
 
 		for _, r := range queue {
-			r.resp <- &response{err: err}
+			r.respond(&response{err: err})
 		}
 		return
 	}
@@ -392,44 +943,64 @@ func (c *PeerClient) sendBatch(ctx context.Context, queue []*request) {
 
 		for _, r := range queue {
 			metricCheckErrorCounter.WithLabelValues("Item mismatch").Add(1)
-			r.resp <- &response{err: err}
+			r.respond(&response{err: err})
 		}
 		return
 	}
 
 	// Provide responses to channels waiting in the queue
 	for i, r := range queue {
-		r.resp <- &response{rl: resp.RateLimits[i]}
+		r.respond(&response{rl: resp.RateLimits[i]})
 	}
 }
 
-// Shutdown waits until all outstanding requests have finished or the context is cancelled.
-// Then it closes the grpc connection.
-func (c *PeerClient) Shutdown(ctx context.Context) error {
-	// ensure we don't leak goroutines, even if the Shutdown times out
-	defer c.conn.Close()
+// injectNodeID adds the ID of the instance to the metadata of a forwarded rate limit, see metadataNodeID
+func (c *PeerClient) injectNodeID(r *RateLimitReq) {
+	if c.conf.NodeID != "" {
+		r.Metadata[metadataNodeID] = c.conf.NodeID
+	}
+}
 
-	waitChan := make(chan struct{})
-	go func() {
-		// drain in-flight requests
+// Shutdown stops the client from accepting new requests and waits until all in-flight requests
+// have finished before closing the grpc connection. If the context is cancelled before the in-flight
+// requests finish, the connection is closed immediately, which fails any remaining requests.
+// It is safe to call Shutdown more than once.
+func (c *PeerClient) Shutdown(ctx context.Context) error {
+	c.shutdownOnce.Do(func() {
 		c.wgMutex.Lock()
-		defer c.wgMutex.Unlock()
-		c.wg.Wait()
+		c.closing = true
+		c.wgMutex.Unlock()
+
+		go func() {
+			// No new requests are accepted once closing, so it is safe to Wait() outside the lock
+			c.wg.Wait()
 
-		// clear errors
-		c.lastErrs = collections.NewLRUCache(100)
+			// clear errors
+			c.lastErrs = collections.NewLRUCache(100)
 
-		// signal that no more items will be sent
-		c.queueClosed.Store(true)
-		close(c.queue)
+			// signal that no more items will be sent
+			close(c.queue)
 
-		close(waitChan)
-	}()
+			c.closeConn()
+			c.stats.remove()
+			close(c.shutdown)
+		}()
+	})
 
 	select {
 	case <-ctx.Done():
+		// ensure we don't leak goroutines or connections, even if the Shutdown times out
+		c.closeConn()
 		return ctx.Err()
-	case <-waitChan:
+	case <-c.shutdown:
 		return nil
 	}
 }
+
+func (c *PeerClient) closeConn() {
+	if c.quic != nil {
+		_ = c.quic.Close()
+		return
+	}
+	_ = c.conn.Close()
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestPeerClientReconnect(t *testing.T) {
+	serve := func(t *testing.T, address string) (*grpc.Server, string) {
+		l, err := net.Listen("tcp", address)
+		require.NoError(t, err)
+		srv := grpc.NewServer()
+		go func() { _ = srv.Serve(l) }()
+		return srv, l.Addr().String()
+	}
+
+	srv, address := serve(t, "127.0.0.1:0")
+	client, err := NewPeerClient(PeerConfig{
+		Info:     PeerInfo{GRPCAddress: address},
+		Behavior: BehaviorConfig{DisableBatching: true},
+	})
+	require.NoError(t, err)
+	defer func() { _ = client.Shutdown(context.Background()) }()
+
+	state := func() connectivity.State {
+		return connectivity.State(testutil.ToFloat64(metricPeerConnectionState.WithLabelValues(address)))
+	}
+	assert.Eventually(t, func() bool { return state() == connectivity.Ready }, 5*time.Second, 10*time.Millisecond)
+
+	// GracefulStop() sends a GOAWAY, as a peer would when it restarts
+	srv.GracefulStop()
+	assert.Eventually(t, func() bool { return state() != connectivity.Ready }, 5*time.Second, 10*time.Millisecond)
+
+	// The client reconnects to the restarted peer without waiting for a request
+	srv, _ = serve(t, address)
+	defer srv.Stop()
+	assert.Eventually(t, func() bool { return state() == connectivity.Ready }, 5*time.Second, 10*time.Millisecond)
+}
@@ -101,3 +101,143 @@ func TestPeerClientShutdown(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerClientShutdownRejectsNewRequests(t *testing.T) {
+	client, err := gubernator.NewPeerClient(gubernator.PeerConfig{
+		Info: cluster.GetRandomPeer(cluster.DataCenterNone),
+		Behavior: gubernator.BehaviorConfig{
+			BatchTimeout: 250 * clock.Millisecond,
+			BatchWait:    250 * clock.Millisecond,
+			BatchLimit:   100,
+		},
+	})
+	require.NoError(t, err)
+
+	createdAt := epochMillis(clock.Now())
+	req := &gubernator.RateLimitReq{
+		Name:      "test_peer_client_shutdown",
+		UniqueKey: "account:1234",
+		Hits:      1,
+		Limit:     100,
+		Duration:  gubernator.Minute,
+		CreatedAt: &createdAt,
+	}
+
+	// Requests succeed until Shutdown() is called
+	_, err = client.GetPeerRateLimit(context.Background(), req)
+	require.NoError(t, err)
+
+	require.NoError(t, client.Shutdown(context.Background()))
+	// Calling Shutdown() again must not panic
+	require.NoError(t, client.Shutdown(context.Background()))
+
+	for _, behavior := range []gubernator.Behavior{gubernator.Behavior_BATCHING, gubernator.Behavior_NO_BATCHING} {
+		req.Behavior = behavior
+		_, err = client.GetPeerRateLimit(context.Background(), req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "client connection is closing")
+	}
+
+	_, err = client.UpdatePeerGlobals(context.Background(), &gubernator.UpdatePeerGlobalsReq{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "client connection is closing")
+}
+
+func TestPeerClientCoalesceRequests(t *testing.T) {
+	client, err := gubernator.NewPeerClient(gubernator.PeerConfig{
+		Info: cluster.GetRandomPeer(cluster.DataCenterNone),
+		Behavior: gubernator.BehaviorConfig{
+			BatchTimeout:     250 * clock.Millisecond,
+			BatchWait:        100 * clock.Millisecond,
+			BatchLimit:       100,
+			CoalesceRequests: true,
+		},
+	})
+	require.NoError(t, err)
+	defer func() { _ = client.Shutdown(context.Background()) }()
+
+	const threads = 10
+	createdAt := epochMillis(clock.Now())
+	key := gubernator.RandomString(10)
+	responses := make([]*gubernator.RateLimitResp, threads)
+	wg := errgroup.Group{}
+	for i := 0; i < threads; i++ {
+		i := i
+		wg.Go(func() error {
+			var err error
+			responses[i], err = client.GetPeerRateLimit(context.Background(), &gubernator.RateLimitReq{
+				Name:      "test_peer_client_coalesce",
+				UniqueKey: key,
+				Hits:      1,
+				Limit:     100,
+				Duration:  gubernator.Minute,
+				CreatedAt: &createdAt,
+			})
+			return err
+		})
+	}
+	require.NoError(t, wg.Wait())
+
+	// Every request received the response of a single request with the sum of their hits
+	for _, resp := range responses {
+		require.Equal(t, "", resp.Error)
+		require.Equal(t, int64(90), resp.Remaining)
+	}
+	require.NotSame(t, responses[0], responses[1])
+}
+
+func TestPeerClientBatchOverflow(t *testing.T) {
+	for _, policy := range []string{gubernator.BatchOverflowSpill, gubernator.BatchOverflowReject, gubernator.BatchOverflowDrop} {
+		t.Run(policy, func(t *testing.T) {
+			client, err := gubernator.NewPeerClient(gubernator.PeerConfig{
+				Info: cluster.GetRandomPeer(cluster.DataCenterNone),
+				Behavior: gubernator.BehaviorConfig{
+					BatchTimeout: 250 * clock.Millisecond,
+					// The first request holds the only room in the queue until the batch is sent
+					BatchWait:           500 * clock.Millisecond,
+					BatchLimit:          100,
+					BatchQueueLimit:     1,
+					BatchQueueWait:      10 * clock.Millisecond,
+					BatchOverflowPolicy: policy,
+				},
+			})
+			require.NoError(t, err)
+			defer func() { _ = client.Shutdown(context.Background()) }()
+
+			key := gubernator.RandomString(10)
+			newReq := func() *gubernator.RateLimitReq {
+				return &gubernator.RateLimitReq{
+					Name:      "test_peer_client_batch_overflow",
+					UniqueKey: key,
+					Hits:      1,
+					Limit:     10,
+					Duration:  gubernator.Minute,
+				}
+			}
+			queued := make(chan error, 1)
+			go func() {
+				_, err := client.GetPeerRateLimit(context.Background(), newReq())
+				queued <- err
+			}()
+			// Wait for the first request to enter the queue
+			clock.Sleep(100 * clock.Millisecond)
+
+			start := clock.Now()
+			resp, err := client.GetPeerRateLimit(context.Background(), newReq())
+			require.Less(t, clock.Since(start), 250*clock.Millisecond, "the overflow waited for the batch")
+			switch policy {
+			case gubernator.BatchOverflowSpill:
+				require.NoError(t, err)
+				require.Equal(t, int64(9), resp.Remaining)
+			case gubernator.BatchOverflowReject:
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "batch queue of peer")
+			case gubernator.BatchOverflowDrop:
+				require.NoError(t, err)
+				require.Equal(t, gubernator.Status_UNDER_LIMIT, resp.Status)
+				require.Equal(t, "dropped", resp.Metadata[gubernator.MetadataBatchOverflow])
+			}
+			require.NoError(t, <-queued)
+		})
+	}
+}
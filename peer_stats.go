@@ -0,0 +1,234 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc/stats"
+)
+
+// peerStats counts the RPCs, errors and bytes a PeerClient sent to its peer and the latency of
+// the RPCs, such that asymmetric traffic caused by hash skew or a hot tenant pinned to one owner
+// can be found. See AdminV1.GetTraffic
+type peerStats struct {
+	peer     string
+	rpcs     atomic.Int64
+	errors   atomic.Int64
+	sent     atomic.Int64
+	received atomic.Int64
+	// Not registered, only used to estimate the latency reported by AdminV1.GetTraffic
+	latency prometheus.Summary
+	// Not registered, the latency of the RPCs over the last minute, see recentP99()
+	recent prometheus.Summary
+
+	recentMutex sync.Mutex
+	recentAt    time.Time
+	recentValue time.Duration
+}
+
+var _ stats.Handler = &peerStats{}
+
+func newPeerStats(peer string) *peerStats {
+	return &peerStats{
+		peer: peer,
+		latency: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "peer_latency",
+			Objectives: map[float64]float64{0.99: 0.001},
+		}),
+		recent: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name:       "peer_recent_latency",
+			Objectives: map[float64]float64{0.99: 0.001},
+			MaxAge:     time.Minute,
+			AgeBuckets: 3,
+		}),
+	}
+}
+
+func (p *peerStats) addSent(n int) {
+	p.sent.Add(int64(n))
+	metricPeerBytesCounter.WithLabelValues(p.peer, "sent").Add(float64(n))
+}
+
+func (p *peerStats) addReceived(n int) {
+	p.received.Add(int64(n))
+	metricPeerBytesCounter.WithLabelValues(p.peer, "received").Add(float64(n))
+}
+
+// finish records an RPC which completed after `d`
+func (p *peerStats) finish(ctx context.Context, err error, d time.Duration) {
+	p.rpcs.Add(1)
+	p.latency.Observe(d.Seconds())
+	p.recent.Observe(d.Seconds())
+	observeWithExemplar(ctx, metricPeerRPCDuration.WithLabelValues(p.peer), d.Seconds())
+	if err != nil {
+		p.errors.Add(1)
+		metricPeerRPCCounter.WithLabelValues(p.peer, "failed").Inc()
+		return
+	}
+	metricPeerRPCCounter.WithLabelValues(p.peer, "success").Inc()
+}
+
+func (p *peerStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (p *peerStats) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	switch t := s.(type) {
+	case *stats.OutPayload:
+		p.addSent(t.WireLength)
+	case *stats.InPayload:
+		p.addReceived(t.WireLength)
+	case *stats.End:
+		p.finish(ctx, t.Error, t.EndTime.Sub(t.BeginTime))
+	}
+}
+
+func (p *peerStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (p *peerStats) HandleConn(context.Context, stats.ConnStats) {}
+
+func (p *peerStats) traffic(from string) *PeerTraffic {
+	t := &PeerTraffic{
+		From:          from,
+		To:            p.peer,
+		Rpcs:          p.rpcs.Load(),
+		Errors:        p.errors.Load(),
+		BytesSent:     p.sent.Load(),
+		BytesReceived: p.received.Load(),
+	}
+	var m dto.Metric
+	if err := p.latency.Write(&m); err == nil {
+		for _, q := range m.GetSummary().GetQuantile() {
+			// The quantile is NaN until the first RPC is observed
+			if q.GetQuantile() == 0.99 && !math.IsNaN(q.GetValue()) {
+				t.P99LatencyMs = q.GetValue() * 1000
+			}
+		}
+	}
+	return t
+}
+
+// recentP99 returns the p99 latency of the RPCs sent to the peer over the last minute. The
+// latency is calculated at most once per second as it is checked for every forwarded request.
+func (p *peerStats) recentP99() time.Duration {
+	p.recentMutex.Lock()
+	defer p.recentMutex.Unlock()
+
+	now := clock.Now()
+	if now.Sub(p.recentAt) < time.Second {
+		return p.recentValue
+	}
+	p.recentAt = now
+	p.recentValue = 0
+	var m dto.Metric
+	if err := p.recent.Write(&m); err == nil {
+		for _, q := range m.GetSummary().GetQuantile() {
+			// The quantile is NaN while no RPC was observed within the last minute
+			if q.GetQuantile() == 0.99 && !math.IsNaN(q.GetValue()) {
+				p.recentValue = time.Duration(q.GetValue() * float64(time.Second))
+			}
+		}
+	}
+	return p.recentValue
+}
+
+// remove deletes the metrics of the peer once the PeerClient is shutdown
+func (p *peerStats) remove() {
+	metricPeerRPCCounter.DeleteLabelValues(p.peer, "success")
+	metricPeerRPCCounter.DeleteLabelValues(p.peer, "failed")
+	metricPeerBytesCounter.DeleteLabelValues(p.peer, "sent")
+	metricPeerBytesCounter.DeleteLabelValues(p.peer, "received")
+	metricPeerRPCDuration.DeleteLabelValues(p.peer)
+}
+
+// GetTraffic returns the traffic every peer in the local data center sent to other peers.
+func (s *V1Instance) GetTraffic(ctx context.Context, r *GetTrafficReq) (*GetTrafficResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetTraffic")).ObserveDuration()
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	resp := &GetTrafficResp{}
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *GetTrafficResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.GetPeerTraffic(ctx, r)
+			} else {
+				peerResp, err = peer.GetPeerTraffic(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			for _, t := range peerResp.Traffic {
+				// The peer may not know the address other peers use to reach it
+				t.From = peer.Info().GRPCAddress
+				resp.Traffic = append(resp.Traffic, t)
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	sort.Slice(resp.Traffic, func(i, j int) bool {
+		if resp.Traffic[i].From != resp.Traffic[j].From {
+			return resp.Traffic[i].From < resp.Traffic[j].From
+		}
+		return resp.Traffic[i].To < resp.Traffic[j].To
+	})
+	return resp, nil
+}
+
+// GetPeerTraffic is called by other peers to collect the traffic this peer sent to the other
+// peers, including peers in other data centers.
+func (s *V1Instance) GetPeerTraffic(ctx context.Context, r *GetTrafficReq) (*GetTrafficResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeerTraffic")).ObserveDuration()
+
+	peers := s.GetPeerList()
+	for _, picker := range s.GetRegionPickers() {
+		peers = append(peers, picker.Peers()...)
+	}
+
+	resp := &GetTrafficResp{}
+	for _, peer := range peers {
+		// Requests owned by this instance are never sent to its own PeerClient
+		if peer.Info().IsOwner {
+			continue
+		}
+		resp.Traffic = append(resp.Traffic, peer.stats.traffic(""))
+	}
+	return resp, nil
+}
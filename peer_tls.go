@@ -0,0 +1,130 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PeerTLSOverride replaces the server name or the CAs which verify the certificate of a peer, IE:
+// when peers present certificates for their pod IP or an internal hostname which does not match
+// the address they advertise. See Config.PeerTLSOverrides
+type PeerTLSOverride struct {
+	// (Required) The GRPCAddress of the peer, or the host of the address without the port such that
+	// the override applies to every port of the host. An override of the address wins over an
+	// override of the host
+	Address string
+
+	// (Optional) The server name verified against the certificate of the peer. Defaults to the
+	// ServerName of Config.PeerTLS
+	ServerName string
+
+	// (Optional) The CAs which verify the certificate of the peer. Defaults to the RootCAs of
+	// Config.PeerTLS
+	RootCAs *x509.CertPool
+}
+
+// ParsePeerTLSOverride parses an override in the format used by `GUBER_PEER_TLS_OVERRIDES`, an
+// address followed by semicolon separated options, IE: "10.0.0.5:1051;server_name=gubernator-0.internal"
+// or "10.0.0.6;ca=/etc/gubernator/zone-b-ca.pem"
+func ParsePeerTLSOverride(s string) (PeerTLSOverride, error) {
+	parts := strings.Split(strings.TrimSpace(s), ";")
+	o := PeerTLSOverride{Address: parts[0]}
+	for _, part := range parts[1:] {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "server_name":
+			o.ServerName = v
+		case "ca":
+			pem, err := os.ReadFile(v)
+			if err != nil {
+				return o, errors.Wrapf(err, "while reading CA of peer TLS override '%s'", s)
+			}
+			o.RootCAs = x509.NewCertPool()
+			if !o.RootCAs.AppendCertsFromPEM(pem) {
+				return o, errors.Errorf("no certificates found in CA '%s' of peer TLS override '%s'", v, s)
+			}
+		default:
+			return o, errors.Errorf("invalid option '%s' in peer TLS override '%s'", part, s)
+		}
+	}
+	if o.Address == "" {
+		return o, errors.New("PeerTLSOverrides.Address cannot be empty")
+	}
+	return o, nil
+}
+
+func validatePeerTLSOverrides(c *Config) error {
+	if len(c.PeerTLSOverrides) == 0 {
+		return nil
+	}
+	if c.PeerTLS == nil {
+		return errors.New("PeerTLSOverrides requires PeerTLS")
+	}
+	addresses := make(map[string]bool, len(c.PeerTLSOverrides))
+	for _, o := range c.PeerTLSOverrides {
+		if o.Address == "" {
+			return errors.New("PeerTLSOverrides.Address cannot be empty")
+		}
+		if addresses[o.Address] {
+			return errors.Errorf("PeerTLSOverrides contains more than one override of '%s'", o.Address)
+		}
+		addresses[o.Address] = true
+	}
+	return nil
+}
+
+// peerTLS returns the TLS config used when connecting to the peer at `address`, which is PeerTLS
+// with the override of the address or of its host applied
+func (c *Config) peerTLS(address string) *tls.Config {
+	if len(c.PeerTLSOverrides) == 0 {
+		return c.PeerTLS
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	var match *PeerTLSOverride
+	for i := range c.PeerTLSOverrides {
+		o := &c.PeerTLSOverrides[i]
+		if o.Address == address {
+			match = o
+			break
+		}
+		if o.Address == host {
+			match = o
+		}
+	}
+	if match == nil {
+		return c.PeerTLS
+	}
+
+	conf := c.PeerTLS.Clone()
+	if match.ServerName != "" {
+		conf.ServerName = match.ServerName
+	}
+	if match.RootCAs != nil {
+		conf.RootCAs = match.RootCAs
+	}
+	return conf
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/mailgun/errors"
+	"github.com/mailgun/holster/v4/clock"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimitCheck is a single rate limit from a GetRateLimits request as it passes through
+// the behavior pipeline.
+type RateLimitCheck struct {
+	// The rate limit request. Middleware may change the request before calling the next handler,
+	// however the `Name`, `UniqueKey` and the GLOBAL behavior must not be changed as they decided
+	// which peer evaluates the rate limit.
+	Req *RateLimitReq
+	// The key which identifies the rate limit, see Config.HashKey
+	Key string
+	// The peer which owns the rate limit
+	Peer *PeerClient
+}
+
+// RateLimitHandler decides a RateLimitCheck. An error is returned to the client in `RateLimitResp.Error`.
+type RateLimitHandler func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error)
+
+// BehaviorMiddleware wraps the next handler in the behavior pipeline. A middleware may decide the
+// check without calling `next`, IE: to deny a request, or change the response returned by `next`.
+//
+// The pipeline runs on the instance which received the GetRateLimits request, before the rate
+// limit is forwarded to the owning peer. Checks owned by other peers run the pipeline in their
+// own goroutine, as such a middleware must be safe to call concurrently.
+type BehaviorMiddleware func(next RateLimitHandler) RateLimitHandler
+
+// newPipeline builds the behavior pipeline, the first middleware is the outermost.
+//
+//	DRY_RUN -> namespace policies -> overrides -> Config.Middleware... -> GLOBAL -> local or forwarded to the owner
+//
+// Behaviors which change how the algorithm counts hits, IE: GREEDY_REFILL and reset jitter, are
+// applied by the algorithm on the owning peer.
+func (s *V1Instance) newPipeline() RateLimitHandler {
+	middleware := []BehaviorMiddleware{s.dryRunMiddleware}
+	if len(s.policies.Load().policies) != 0 || s.conf.PolicyFile != "" {
+		middleware = append(middleware, s.policyMiddleware)
+	}
+	middleware = append(middleware, s.overrideMiddleware)
+	middleware = append(middleware, s.conf.Middleware...)
+	middleware = append(middleware, s.globalMiddleware)
+
+	h := RateLimitHandler(s.evaluate)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// check runs the behavior pipeline and converts any error into a response
+func (s *V1Instance) check(ctx context.Context, c *RateLimitCheck) *RateLimitResp {
+	rl, err := s.pipeline(ctx, c)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return &RateLimitResp{Error: err.Error()}
+	}
+	if rl == nil {
+		return &RateLimitResp{Error: "behavior pipeline returned no response"}
+	}
+	return rl
+}
+
+// evaluate applies the rate limit algorithm if we own the rate limit, else forwards
+// the rate limit to the owning peer. It is the last handler in the pipeline.
+func (s *V1Instance) evaluate(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+	if HasBehavior(c.Req.Behavior, Behavior_FORCE_PEER) ||
+		(!c.Peer.Info().IsOwner && !HasBehavior(c.Req.Behavior, Behavior_FORCE_LOCAL)) {
+		if !HasBehavior(c.Req.Behavior, Behavior_FORCE_PEER) {
+			if rl := s.getColdRateLimit(ctx, c); rl != nil {
+				return rl, nil
+			}
+		}
+		if rl := s.getSlowPeerRateLimit(ctx, c); rl != nil {
+			return rl, nil
+		}
+		return s.forwardRequest(ctx, c), nil
+	}
+
+	start := clock.Now()
+	rl, err := s.getLocalRateLimit(ctx, c.Req, RateLimitReqState{IsOwner: true})
+	if err != nil {
+		observeCheck(ctx, start, "local", c.Req, nil)
+		return nil, errors.Wrapf(err, "Error while apply rate limit for '%s'", c.Key)
+	}
+	observeCheck(ctx, start, "local", c.Req, rl)
+	return rl, nil
+}
+
+// globalMiddleware answers GLOBAL rate limits owned by other peers from the local cache, unless
+// this instance is a forwarder which receives no GLOBAL broadcasts or the request overrides forwarding
+// with FORCE_LOCAL or FORCE_PEER
+func (s *V1Instance) globalMiddleware(next RateLimitHandler) RateLimitHandler {
+	return func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+		if c.Peer.Info().IsOwner || !HasBehavior(c.Req.Behavior, Behavior_GLOBAL) || s.forwarder.Load() ||
+			HasBehavior(c.Req.Behavior, Behavior_FORCE_LOCAL|Behavior_FORCE_PEER) {
+			return next(ctx, c)
+		}
+
+		start := clock.Now()
+		rl, err := s.getGlobalRateLimit(ctx, c.Req)
+		if err != nil {
+			observeCheck(ctx, start, "global", c.Req, nil)
+			return nil, errors.Wrap(err, "Error in getGlobalRateLimit")
+		}
+		observeCheck(ctx, start, "global", c.Req, rl)
+
+		// Inform the client of the owner key of the key
+		rl.Metadata = map[string]string{"owner": c.Peer.Info().GRPCAddress}
+		return rl, nil
+	}
+}
+
+// overrideMiddleware decides the check by the override set via AdminV1.SetOverride, if any. A
+// PAUSE override evaluates the check as DRY_RUN, which is reported by dryRunMiddleware.
+func (s *V1Instance) overrideMiddleware(next RateLimitHandler) RateLimitHandler {
+	return func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+		o := s.overrides.get(c.Req, epochMillis(clock.Now()))
+		if o == nil {
+			return next(ctx, c)
+		}
+		if o.Action == OverrideAction_PAUSE {
+			return applyPause(ctx, c, o, next)
+		}
+		return applyOverride(c.Req, o), nil
+	}
+}
+
+// dryRunMiddleware reports DRY_RUN rate limits as under the limit
+func (s *V1Instance) dryRunMiddleware(next RateLimitHandler) RateLimitHandler {
+	return func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+		rl, err := next(ctx, c)
+		if err == nil && HasBehavior(c.Req.Behavior, Behavior_DRY_RUN) {
+			applyDryRun(c.Req, rl)
+		}
+		return rl, err
+	}
+}
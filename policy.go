@@ -0,0 +1,246 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+// NamespacePolicy caps what clients may request for the rate limits in a namespace, IE: the
+// rate limits of "public-api" may never ask for more than 10,000 hits per minute.
+type NamespacePolicy struct {
+	// (Required) The policy applies to rate limit names which start with this prefix. When more
+	// than one policy matches a name, the policy with the longest prefix applies.
+	Prefix string
+
+	// (Optional) The max limit a request may ask for. Defaults to 0 (no cap)
+	MaxLimit int64
+
+	// (Optional) The max duration a request may ask for. Defaults to 0 (no cap)
+	MaxDuration time.Duration
+
+	// (Optional) The algorithms a request may use. Defaults to every algorithm
+	Algorithms []Algorithm
+
+	// (Optional) Requests which exceed MaxLimit or MaxDuration are clamped to the cap instead of
+	// being rejected. Requests which use an algorithm not in Algorithms are always rejected.
+	Clamp bool
+
+	// (Optional) Unique keys which are an IPv4 address, or an IPv4 address after a label such as
+	// "ip:10.2.10.7", are aggregated to the network of this prefix length, IE: with 24 every address
+	// in "10.2.10.0/24" shares the same rate limit. Defaults to 0 (keys are not aggregated)
+	IPv4Prefix int
+
+	// (Optional) The same as IPv4Prefix for IPv6 addresses, IE: 64. Defaults to 0 (keys are not aggregated)
+	IPv6Prefix int
+
+	// (Optional) Rate limits whose owning peer cannot be reached return OVER_LIMIT instead of an
+	// error, IE: for password attempts where failing open is not acceptable. Requests may also ask
+	// for this with MetadataStrict. Defaults to false
+	Strict bool
+}
+
+func (p *NamespacePolicy) validate() error {
+	switch {
+	case p.Prefix == "":
+		return errors.New("NamespacePolicies.Prefix cannot be empty")
+	case p.MaxLimit < 0:
+		return errors.Errorf("NamespacePolicies.MaxLimit of '%s' cannot be negative", p.Prefix)
+	case p.MaxDuration < 0:
+		return errors.Errorf("NamespacePolicies.MaxDuration of '%s' cannot be negative", p.Prefix)
+	case p.IPv4Prefix < 0 || p.IPv4Prefix > 32:
+		return errors.Errorf("NamespacePolicies.IPv4Prefix of '%s' must be between 0 and 32", p.Prefix)
+	case p.IPv6Prefix < 0 || p.IPv6Prefix > 128:
+		return errors.Errorf("NamespacePolicies.IPv6Prefix of '%s' must be between 0 and 128", p.Prefix)
+	}
+	return nil
+}
+
+// ParseNamespacePolicy parses a policy in the format used by `GUBER_NAMESPACE_POLICIES`, a prefix
+// followed by semicolon separated options, IE: "public-api;max_limit=10000;max_duration=1h;algorithms=token_bucket;clamp"
+// or "login;ipv4_prefix=24;ipv6_prefix=64;strict"
+func ParseNamespacePolicy(s string) (NamespacePolicy, error) {
+	parts := strings.Split(strings.TrimSpace(s), ";")
+	p := NamespacePolicy{Prefix: parts[0]}
+	for _, part := range parts[1:] {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch k {
+		case "max_limit":
+			p.MaxLimit, err = strconv.ParseInt(v, 10, 64)
+		case "max_duration":
+			p.MaxDuration, err = time.ParseDuration(v)
+		case "algorithms":
+			for _, name := range strings.Split(v, "|") {
+				a, ok := Algorithm_value[strings.ToUpper(name)]
+				if !ok {
+					return p, errors.Errorf("invalid algorithm '%s' in namespace policy '%s'", name, s)
+				}
+				p.Algorithms = append(p.Algorithms, Algorithm(a))
+			}
+		case "clamp":
+			p.Clamp = true
+		case "ipv4_prefix":
+			p.IPv4Prefix, err = strconv.Atoi(v)
+		case "ipv6_prefix":
+			p.IPv6Prefix, err = strconv.Atoi(v)
+		case "strict":
+			p.Strict = true
+		default:
+			return p, errors.Errorf("invalid option '%s' in namespace policy '%s'", part, s)
+		}
+		if err != nil {
+			return p, errors.Wrapf(err, "invalid option '%s' in namespace policy '%s'", part, s)
+		}
+	}
+	return p, p.validate()
+}
+
+// sortPolicies returns a copy of the policies ordered by longest prefix first
+func sortPolicies(policies []NamespacePolicy) []NamespacePolicy {
+	result := append([]NamespacePolicy(nil), policies...)
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i].Prefix) > len(result[j].Prefix) })
+	return result
+}
+
+// policyFor returns the policy of the namespace of the rate limit name, or nil if no policy matches
+func (s *V1Instance) policyFor(name string) *NamespacePolicy {
+	policies := s.policies.Load().policies
+	for i := range policies {
+		if strings.HasPrefix(name, policies[i].Prefix) {
+			return &policies[i]
+		}
+	}
+	return nil
+}
+
+// applyPolicy clamps the request to the policy of its namespace, or returns an error if the
+// request is not allowed by the policy.
+func (s *V1Instance) applyPolicy(r *RateLimitReq) error {
+	p := s.policyFor(r.Name)
+	if p == nil {
+		return nil
+	}
+
+	if len(p.Algorithms) != 0 && !containsAlgorithm(p.Algorithms, r.Algorithm) {
+		metricPolicyCounter.WithLabelValues("rejected").Inc()
+		return fmt.Errorf("algorithm '%s' is not allowed in namespace '%s'", r.Algorithm, p.Prefix)
+	}
+
+	var clamped bool
+	if p.MaxLimit != 0 && r.Limit > p.MaxLimit {
+		if !p.Clamp {
+			metricPolicyCounter.WithLabelValues("rejected").Inc()
+			return fmt.Errorf("limit '%d' exceeds the max limit '%d' of namespace '%s'", r.Limit, p.MaxLimit, p.Prefix)
+		}
+		r.Limit, clamped = p.MaxLimit, true
+	}
+
+	if maxDuration := p.MaxDuration.Milliseconds(); maxDuration != 0 {
+		if HasBehavior(r.Behavior, Behavior_DURATION_IS_GREGORIAN) {
+			// A gregorian interval cannot be clamped. An invalid interval is reported by the algorithm.
+			if d, err := GregorianDuration(clock.Now(), r.Duration); err == nil && d > maxDuration {
+				metricPolicyCounter.WithLabelValues("rejected").Inc()
+				return fmt.Errorf("gregorian duration exceeds the max duration '%s' of namespace '%s'", p.MaxDuration, p.Prefix)
+			}
+		} else if r.Duration > maxDuration {
+			if !p.Clamp {
+				metricPolicyCounter.WithLabelValues("rejected").Inc()
+				return fmt.Errorf("duration '%d' exceeds the max duration '%s' of namespace '%s'", r.Duration, p.MaxDuration, p.Prefix)
+			}
+			r.Duration, clamped = maxDuration, true
+		}
+	}
+
+	if clamped {
+		metricPolicyCounter.WithLabelValues("clamped").Inc()
+	}
+	return nil
+}
+
+// aggregateIPKey replaces an IP address in the unique key with the network of the prefix length
+// configured by the policy of its namespace. It must be called before the key is hashed, such
+// that every address in the network is owned by the same peer.
+func (s *V1Instance) aggregateIPKey(r *RateLimitReq) {
+	p := s.policyFor(r.Name)
+	if p == nil || (p.IPv4Prefix == 0 && p.IPv6Prefix == 0) {
+		return
+	}
+	if key, ok := aggregateIP(r.UniqueKey, p.IPv4Prefix, p.IPv6Prefix); ok {
+		r.UniqueKey = key
+	}
+}
+
+// aggregateIP returns the network of the address in the key, IE: "ip:10.2.10.7" returns "ip:10.2.10.0/24"
+// with an ipv4Prefix of 24. The address is either the whole key or follows the first colon of the key.
+func aggregateIP(key string, ipv4Prefix, ipv6Prefix int) (string, bool) {
+	label, addr := "", key
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		var ok bool
+		if label, addr, ok = strings.Cut(key, ":"); !ok {
+			return key, false
+		}
+		if ip, err = netip.ParseAddr(addr); err != nil {
+			return key, false
+		}
+		label += ":"
+	}
+
+	ip = ip.Unmap().WithZone("")
+	bits := ipv6Prefix
+	if ip.Is4() {
+		bits = ipv4Prefix
+	}
+	if bits == 0 {
+		return key, false
+	}
+	prefix, err := ip.Prefix(bits)
+	if err != nil {
+		return key, false
+	}
+	return label + prefix.String(), true
+}
+
+func containsAlgorithm(algorithms []Algorithm, a Algorithm) bool {
+	for _, v := range algorithms {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
+
+// policyMiddleware clamps or rejects requests which are not allowed by `Config.NamespacePolicies`
+// or `Config.PolicyFile`
+func (s *V1Instance) policyMiddleware(next RateLimitHandler) RateLimitHandler {
+	return func(ctx context.Context, c *RateLimitCheck) (*RateLimitResp, error) {
+		if err := s.applyPolicy(c.Req); err != nil {
+			return nil, err
+		}
+		return next(ctx, c)
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// policyReloadDelay is how long the policy file must be unchanged before it is reloaded, such
+// that an editor which writes the file in several steps causes a single reload.
+const policyReloadDelay = 100 * time.Millisecond
+
+// policySet is the namespace policies in effect, it is replaced as a whole when the policy
+// file is reloaded such that a request never sees the policies of two versions of the file.
+type policySet struct {
+	// Ordered by longest prefix first
+	policies []NamespacePolicy
+	// The `version` of the policy file, or a digest of its content if the file has no version.
+	// Empty unless `Config.PolicyFile` is set.
+	version string
+	// The digest of every file the policies were loaded from
+	digest string
+	// The policy file followed by the files it includes
+	files []string
+}
+
+// policyFileSchema is the format of `Config.PolicyFile`, IE:
+//
+//	version: "2024-06-01"
+//	include:
+//	  - common.yaml
+//	policies:
+//	  - prefix: public-api
+//	    max_limit: 10000
+//	    max_duration: 1h
+//	    algorithms: [token_bucket]
+//	    clamp: true
+//	  - prefix: login
+//	    ipv4_prefix: 24
+//	    ipv6_prefix: 64
+//	    strict: true
+//
+// Included paths are relative to the file which includes them. The policies of a file replace
+// the policies of the files it includes which have the same prefix.
+type policyFileSchema struct {
+	Version  string            `yaml:"version"`
+	Include  []string          `yaml:"include"`
+	Policies []policyFileEntry `yaml:"policies"`
+}
+
+type policyFileEntry struct {
+	Prefix      string   `yaml:"prefix"`
+	MaxLimit    int64    `yaml:"max_limit"`
+	MaxDuration string   `yaml:"max_duration"`
+	Algorithms  []string `yaml:"algorithms"`
+	Clamp       bool     `yaml:"clamp"`
+	IPv4Prefix  int      `yaml:"ipv4_prefix"`
+	IPv6Prefix  int      `yaml:"ipv6_prefix"`
+	Strict      bool     `yaml:"strict"`
+}
+
+func (e policyFileEntry) policy() (NamespacePolicy, error) {
+	p := NamespacePolicy{
+		Prefix:     e.Prefix,
+		MaxLimit:   e.MaxLimit,
+		Clamp:      e.Clamp,
+		IPv4Prefix: e.IPv4Prefix,
+		IPv6Prefix: e.IPv6Prefix,
+		Strict:     e.Strict,
+	}
+	if e.MaxDuration != "" {
+		d, err := time.ParseDuration(e.MaxDuration)
+		if err != nil {
+			return p, errors.Wrapf(err, "invalid max_duration of '%s'", e.Prefix)
+		}
+		p.MaxDuration = d
+	}
+	for _, name := range e.Algorithms {
+		a, ok := Algorithm_value[strings.ToUpper(name)]
+		if !ok {
+			return p, errors.Errorf("invalid algorithm '%s' of '%s'", name, e.Prefix)
+		}
+		p.Algorithms = append(p.Algorithms, Algorithm(a))
+	}
+	return p, p.validate()
+}
+
+// loadPolicySet returns the policies in `base` merged with the policies of the policy file,
+// a policy in the file replaces a policy in `base` with the same prefix.
+func loadPolicySet(base []NamespacePolicy, path string) (*policySet, error) {
+	l := policyLoader{
+		digest:    sha256.New(),
+		loaded:    make(map[string]bool),
+		including: make(map[string]bool),
+	}
+	root, err := l.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergePolicies(base, l.policies)
+	set := &policySet{
+		policies: sortPolicies(merged),
+		digest:   hex.EncodeToString(l.digest.Sum(nil)),
+		files:    l.files,
+		version:  root.Version,
+	}
+	if set.version == "" {
+		set.version = set.digest[:12]
+	}
+	return set, nil
+}
+
+type policyLoader struct {
+	policies  []NamespacePolicy
+	files     []string
+	digest    hash.Hash
+	loaded    map[string]bool
+	including map[string]bool
+}
+
+// load reads the file and the files it includes, the policies of included files are
+// merged before the policies of the file which includes them.
+func (l *policyLoader) load(path string) (*policyFileSchema, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while resolving policy file '%s'", path)
+	}
+	if l.including[path] {
+		return nil, errors.Errorf("policy file '%s' includes itself", path)
+	}
+	if l.loaded[path] {
+		return &policyFileSchema{}, nil
+	}
+	l.including[path] = true
+	defer delete(l.including, path)
+	l.loaded[path] = true
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading policy file '%s'", path)
+	}
+	_, _ = l.digest.Write(b)
+	l.files = append(l.files, path)
+
+	var schema policyFileSchema
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&schema); err != nil && err != io.EOF {
+		return nil, errors.Wrapf(err, "while parsing policy file '%s'", path)
+	}
+
+	for _, include := range schema.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		if _, err := l.load(include); err != nil {
+			return nil, err
+		}
+	}
+
+	var policies []NamespacePolicy
+	prefixes := make(map[string]bool, len(schema.Policies))
+	for i, e := range schema.Policies {
+		p, err := e.policy()
+		if err != nil {
+			return nil, errors.Wrapf(err, "policy file '%s': policies[%d]", path, i)
+		}
+		if prefixes[p.Prefix] {
+			return nil, errors.Errorf("policy file '%s': policies[%d]: duplicate prefix '%s'", path, i, p.Prefix)
+		}
+		prefixes[p.Prefix] = true
+		policies = append(policies, p)
+	}
+	l.policies = mergePolicies(l.policies, policies)
+	return &schema, nil
+}
+
+// mergePolicies returns the policies in `base` which have no policy in `overlay` with the
+// same prefix, followed by the policies in `overlay`.
+func mergePolicies(base, overlay []NamespacePolicy) []NamespacePolicy {
+	replaced := make(map[string]bool, len(overlay))
+	for _, p := range overlay {
+		replaced[p.Prefix] = true
+	}
+	result := make([]NamespacePolicy, 0, len(base)+len(overlay))
+	for _, p := range base {
+		if !replaced[p.Prefix] {
+			result = append(result, p)
+		}
+	}
+	return append(result, overlay...)
+}
+
+// policyWatcher reloads the policy file when it or any of the files it includes change on
+// disk. The directories of the files are watched, such that files which are replaced instead
+// of written to, IE: by an editor or a Kubernetes ConfigMap, are also reloaded.
+type policyWatcher struct {
+	log     FieldLogger
+	base    []NamespacePolicy
+	path    string
+	watcher *fsnotify.Watcher
+	// Called with the policies of each successful reload
+	swap    func(*policySet)
+	current *policySet
+	dirs    map[string]bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newPolicyWatcher(conf Config, set *policySet, swap func(*policySet)) (*policyWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "while watching the policy file")
+	}
+	w := &policyWatcher{
+		log:     conf.Logger,
+		base:    conf.NamespacePolicies,
+		path:    conf.PolicyFile,
+		watcher: watcher,
+		swap:    swap,
+		current: set,
+		dirs:    make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+	if err := w.watch(set.files); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	w.wg.Add(1)
+	go w.run()
+	return w, nil
+}
+
+// watch adds the directories of the files which are not already watched
+func (w *policyWatcher) watch(files []string) error {
+	for _, f := range files {
+		dir := filepath.Dir(f)
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			return errors.Wrapf(err, "while watching policy directory '%s'", dir)
+		}
+		w.dirs[dir] = true
+	}
+	return nil
+}
+
+// affects returns true if the event may have changed one of the loaded files
+func (w *policyWatcher) affects(e fsnotify.Event) bool {
+	// Kubernetes replaces the files of a ConfigMap by swapping the '..data' symlink
+	if strings.HasPrefix(filepath.Base(e.Name), "..") {
+		return true
+	}
+	name := filepath.Clean(e.Name)
+	for _, f := range w.current.files {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *policyWatcher) run() {
+	defer w.wg.Done()
+	timer := time.NewTimer(policyReloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case e, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if w.affects(e) {
+				timer.Reset(policyReloadDelay)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.log.WithError(err).Warn("while watching the policy file")
+		case <-timer.C:
+			w.reload()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// reload loads the policy file and swaps the policies in effect if the files changed. The
+// current policies are unchanged if any file is invalid.
+func (w *policyWatcher) reload() {
+	set, err := loadPolicySet(w.base, w.path)
+	if err != nil {
+		w.log.WithError(err).Warn("while reloading the policy file; continuing with the previous policies")
+		return
+	}
+	if set.digest == w.current.digest {
+		return
+	}
+	if err := w.watch(set.files); err != nil {
+		w.log.WithError(err).Warn("while watching the policy file")
+	}
+	w.current = set
+	w.swap(set)
+	w.log.WithField("version", set.version).
+		WithField("policies", len(set.policies)).
+		Info("reloaded the policy file")
+}
+
+func (w *policyWatcher) stop() {
+	close(w.done)
+	_ = w.watcher.Close()
+	w.wg.Wait()
+}
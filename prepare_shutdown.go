@@ -0,0 +1,138 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PrepareShutdown sends the rate limits this instance owns to the peers which will own them once
+// this instance is removed from the local data center, such that a rolling restart does not lose
+// the hits applied to them. Hits applied by this instance after PrepareShutdown returns and before
+// the other peers remove it from their peer list are lost.
+func (s *V1Instance) PrepareShutdown(ctx context.Context, _ *PrepareShutdownReq) (*PrepareShutdownResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.PrepareShutdown")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+
+	// The ring of the local data center without this instance
+	current := s.pickers.Load().local
+	next := current.New()
+	for _, peer := range current.Peers() {
+		if !peer.Info().IsOwner {
+			next.Add(peer)
+		}
+	}
+	if len(next.Peers()) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "there are no other peers in the local data center")
+	}
+
+	resp := &PrepareShutdownResp{}
+	batches := make(map[*PeerClient][]*CacheItemState)
+	send := func(peer *PeerClient) {
+		items := batches[peer]
+		delete(batches, peer)
+		peerResp, err := peer.TransferPeerRateLimits(ctx, &TransferPeerRateLimitsReq{Items: items})
+		if err != nil {
+			resp.Failed += int64(len(items))
+			resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+			return
+		}
+		resp.Transferred += peerResp.Added
+		resp.Existing += peerResp.Existing
+	}
+
+	// The channel must be read until closed
+	for item := range s.workerPool.Each(ctx) {
+		if item.IsExpired() {
+			continue
+		}
+		// Copies of GLOBAL rate limits owned by other peers stay with their owner
+		if owner, err := s.GetPeer(ctx, item.Key); err != nil || !owner.Info().IsOwner {
+			continue
+		}
+		peer, err := next.Get(item.Key)
+		if err != nil {
+			resp.Failed++
+			continue
+		}
+		state, err := newCacheItemState(item)
+		if err != nil {
+			resp.Failed++
+			continue
+		}
+		batches[peer] = append(batches[peer], state)
+		if len(batches[peer]) >= maxBatchSize {
+			send(peer)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	for peer := range batches {
+		send(peer)
+	}
+
+	s.log.WithField("transferred", resp.Transferred).
+		WithField("existing", resp.Existing).
+		WithField("failed", resp.Failed).
+		Warn("rate limits transferred to their next owners via admin API")
+	return resp, nil
+}
+
+// TransferPeerRateLimits is called by a peer which is shutting down to add the rate limits this
+// instance will own to the cache. Rate limits already in the cache are kept, as they hold the hits
+// applied since this instance began to own them.
+func (s *V1Instance) TransferPeerRateLimits(ctx context.Context, r *TransferPeerRateLimitsReq) (*TransferPeerRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.TransferPeerRateLimits")).ObserveDuration()
+	if !s.conf.AdminEnabled {
+		return nil, status.Error(codes.PermissionDenied, "admin API is disabled")
+	}
+	if len(r.Items) > maxBatchSize {
+		return nil, status.Errorf(codes.OutOfRange, "'items' list too large; max size is '%d'", maxBatchSize)
+	}
+
+	resp := &TransferPeerRateLimitsResp{}
+	for _, state := range r.Items {
+		item, err := cacheItemFromState(state)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if item.IsExpired() {
+			continue
+		}
+		_, found, err := s.workerPool.GetCacheItem(ctx, item.Key)
+		if err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		if found {
+			resp.Existing++
+			continue
+		}
+		if err := s.workerPool.AddCacheItem(ctx, item.Key, item); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		resp.Added++
+	}
+	return resp, nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	pb "github.com/gubernator-io/gubernator/v2/proto"
+)
+
+// The messages and services of the API are generated in the proto package, which clients may
+// import without the dependencies of the server. The aliases below keep them available from this
+// package for compatibility, add the new messages and services here once generated.
+type (
+	AdminV1Client               = pb.AdminV1Client
+	AdminV1Server               = pb.AdminV1Server
+	Algorithm                   = pb.Algorithm
+	BatchSummary                = pb.BatchSummary
+	Behavior                    = pb.Behavior
+	CacheItemState              = pb.CacheItemState
+	CacheItemState_LeakyBucket  = pb.CacheItemState_LeakyBucket
+	CacheItemState_TokenBucket  = pb.CacheItemState_TokenBucket
+	DeleteOverrideReq           = pb.DeleteOverrideReq
+	DeleteOverrideResp          = pb.DeleteOverrideResp
+	Endpoint                    = pb.Endpoint
+	GetKeyHistoryReq            = pb.GetKeyHistoryReq
+	GetKeyHistoryResp           = pb.GetKeyHistoryResp
+	GetLimitDriftReq            = pb.GetLimitDriftReq
+	GetLimitDriftResp           = pb.GetLimitDriftResp
+	GetNamespaceUsageReq        = pb.GetNamespaceUsageReq
+	GetNamespaceUsageResp       = pb.GetNamespaceUsageResp
+	GetPeerKeyFilterReq         = pb.GetPeerKeyFilterReq
+	GetPeerKeyFilterResp        = pb.GetPeerKeyFilterResp
+	GetPeerRateLimitsReq        = pb.GetPeerRateLimitsReq
+	GetPeerRateLimitsResp       = pb.GetPeerRateLimitsResp
+	GetPeerVersionReq           = pb.GetPeerVersionReq
+	GetPeerVersionResp          = pb.GetPeerVersionResp
+	GetRateLimitGroupReq        = pb.GetRateLimitGroupReq
+	GetRateLimitGroupResp       = pb.GetRateLimitGroupResp
+	GetRateLimitHierarchyReq    = pb.GetRateLimitHierarchyReq
+	GetRateLimitHierarchyResp   = pb.GetRateLimitHierarchyResp
+	GetRateLimitsReq            = pb.GetRateLimitsReq
+	GetRateLimitsResp           = pb.GetRateLimitsResp
+	GetTrafficReq               = pb.GetTrafficReq
+	GetTrafficResp              = pb.GetTrafficResp
+	HealthCheckReq              = pb.HealthCheckReq
+	HealthCheckResp             = pb.HealthCheckResp
+	HierarchyOrder              = pb.HierarchyOrder
+	KeyTransition               = pb.KeyTransition
+	KeyUsage                    = pb.KeyUsage
+	LeakyBucketState            = pb.LeakyBucketState
+	LeaseReq                    = pb.LeaseReq
+	LeaseResp                   = pb.LeaseResp
+	LimitDefinition             = pb.LimitDefinition
+	LimitDrift                  = pb.LimitDrift
+	ListNamespacesReq           = pb.ListNamespacesReq
+	ListNamespacesResp          = pb.ListNamespacesResp
+	ListOverridesReq            = pb.ListOverridesReq
+	ListOverridesResp           = pb.ListOverridesResp
+	ListPeerLimitsReq           = pb.ListPeerLimitsReq
+	ListPeerLimitsResp          = pb.ListPeerLimitsResp
+	ListRateLimitsReq           = pb.ListRateLimitsReq
+	ListRateLimitsResp          = pb.ListRateLimitsResp
+	NamespaceInfo               = pb.NamespaceInfo
+	NamespaceUsage              = pb.NamespaceUsage
+	Override                    = pb.Override
+	OverrideAction              = pb.OverrideAction
+	PauseNamespaceReq           = pb.PauseNamespaceReq
+	PauseNamespaceResp          = pb.PauseNamespaceResp
+	PeerTraffic                 = pb.PeerTraffic
+	PeerVersion                 = pb.PeerVersion
+	PeersV1Client               = pb.PeersV1Client
+	PeersV1Server               = pb.PeersV1Server
+	PrepareShutdownReq          = pb.PrepareShutdownReq
+	PrepareShutdownResp         = pb.PrepareShutdownResp
+	RateLimitDefaults           = pb.RateLimitDefaults
+	RateLimitReq                = pb.RateLimitReq
+	RateLimitResp               = pb.RateLimitResp
+	RateLimitState              = pb.RateLimitState
+	RefundReq                   = pb.RefundReq
+	RefundResp                  = pb.RefundResp
+	RegisterLimitsReq           = pb.RegisterLimitsReq
+	RegisterLimitsResp          = pb.RegisterLimitsResp
+	RegisteredLimit             = pb.RegisteredLimit
+	ReplayJournalReq            = pb.ReplayJournalReq
+	ReplayJournalResp           = pb.ReplayJournalResp
+	ReplicatePeerRateLimitsReq  = pb.ReplicatePeerRateLimitsReq
+	ReplicatePeerRateLimitsResp = pb.ReplicatePeerRateLimitsResp
+	ReservationReq              = pb.ReservationReq
+	ReservationResp             = pb.ReservationResp
+	ReserveRateLimitReq         = pb.ReserveRateLimitReq
+	ReserveRateLimitResp        = pb.ReserveRateLimitResp
+	ResetPeerRateLimitsReq      = pb.ResetPeerRateLimitsReq
+	ResetPeerRateLimitsResp     = pb.ResetPeerRateLimitsResp
+	ResetRateLimitsReq          = pb.ResetRateLimitsReq
+	ResetRateLimitsResp         = pb.ResetRateLimitsResp
+	ResumeNamespaceReq          = pb.ResumeNamespaceReq
+	ResumeNamespaceResp         = pb.ResumeNamespaceResp
+	SetOverrideReq              = pb.SetOverrideReq
+	SetOverrideResp             = pb.SetOverrideResp
+	Status                      = pb.Status
+	StreamHitsReq               = pb.StreamHitsReq
+	StreamHitsResp              = pb.StreamHitsResp
+	StreamHitsStatus            = pb.StreamHitsStatus
+	TokenBucketState            = pb.TokenBucketState
+	TransactRateLimitsReq       = pb.TransactRateLimitsReq
+	TransactRateLimitsResp      = pb.TransactRateLimitsResp
+	TransferPeerRateLimitsReq   = pb.TransferPeerRateLimitsReq
+	TransferPeerRateLimitsResp  = pb.TransferPeerRateLimitsResp
+	UnimplementedAdminV1Server  = pb.UnimplementedAdminV1Server
+	UnimplementedPeersV1Server  = pb.UnimplementedPeersV1Server
+	UnimplementedV1Server       = pb.UnimplementedV1Server
+	UnsafeAdminV1Server         = pb.UnsafeAdminV1Server
+	UnsafePeersV1Server         = pb.UnsafePeersV1Server
+	UnsafeV1Server              = pb.UnsafeV1Server
+	UpdatePeerGlobal            = pb.UpdatePeerGlobal
+	UpdatePeerGlobalsReq        = pb.UpdatePeerGlobalsReq
+	UpdatePeerGlobalsResp       = pb.UpdatePeerGlobalsResp
+	UpdatePeerOverridesReq      = pb.UpdatePeerOverridesReq
+	UpdatePeerOverridesResp     = pb.UpdatePeerOverridesResp
+	V1Client                    = pb.V1Client
+	V1Server                    = pb.V1Server
+	V1_StreamHitsClient         = pb.V1_StreamHitsClient
+	V1_StreamHitsServer         = pb.V1_StreamHitsServer
+)
+
+const (
+	AdminV1_DeleteOverride_FullMethodName          = pb.AdminV1_DeleteOverride_FullMethodName
+	AdminV1_GetKeyHistory_FullMethodName           = pb.AdminV1_GetKeyHistory_FullMethodName
+	AdminV1_GetLimitDrift_FullMethodName           = pb.AdminV1_GetLimitDrift_FullMethodName
+	AdminV1_GetNamespaceUsage_FullMethodName       = pb.AdminV1_GetNamespaceUsage_FullMethodName
+	AdminV1_GetTraffic_FullMethodName              = pb.AdminV1_GetTraffic_FullMethodName
+	AdminV1_ListNamespaces_FullMethodName          = pb.AdminV1_ListNamespaces_FullMethodName
+	AdminV1_ListOverrides_FullMethodName           = pb.AdminV1_ListOverrides_FullMethodName
+	AdminV1_ListRateLimits_FullMethodName          = pb.AdminV1_ListRateLimits_FullMethodName
+	AdminV1_PauseNamespace_FullMethodName          = pb.AdminV1_PauseNamespace_FullMethodName
+	AdminV1_PrepareShutdown_FullMethodName         = pb.AdminV1_PrepareShutdown_FullMethodName
+	AdminV1_ReplayJournal_FullMethodName           = pb.AdminV1_ReplayJournal_FullMethodName
+	AdminV1_ResetRateLimits_FullMethodName         = pb.AdminV1_ResetRateLimits_FullMethodName
+	AdminV1_ResumeNamespace_FullMethodName         = pb.AdminV1_ResumeNamespace_FullMethodName
+	AdminV1_SetOverride_FullMethodName             = pb.AdminV1_SetOverride_FullMethodName
+	Algorithm_LEAKY_BUCKET                         = pb.Algorithm_LEAKY_BUCKET
+	Algorithm_TOKEN_BUCKET                         = pb.Algorithm_TOKEN_BUCKET
+	Behavior_BATCHING                              = pb.Behavior_BATCHING
+	Behavior_DRAIN_OVER_LIMIT                      = pb.Behavior_DRAIN_OVER_LIMIT
+	Behavior_DRY_RUN                               = pb.Behavior_DRY_RUN
+	Behavior_DURATION_IS_GREGORIAN                 = pb.Behavior_DURATION_IS_GREGORIAN
+	Behavior_FORCE_LOCAL                           = pb.Behavior_FORCE_LOCAL
+	Behavior_FORCE_PEER                            = pb.Behavior_FORCE_PEER
+	Behavior_GLOBAL                                = pb.Behavior_GLOBAL
+	Behavior_GREEDY_REFILL                         = pb.Behavior_GREEDY_REFILL
+	Behavior_MULTI_REGION                          = pb.Behavior_MULTI_REGION
+	Behavior_NO_BATCHING                           = pb.Behavior_NO_BATCHING
+	Behavior_REFUNDABLE                            = pb.Behavior_REFUNDABLE
+	Behavior_RESET_REMAINING                       = pb.Behavior_RESET_REMAINING
+	Behavior_ROLLOVER                              = pb.Behavior_ROLLOVER
+	HierarchyOrder_CHILD_FIRST                     = pb.HierarchyOrder_CHILD_FIRST
+	HierarchyOrder_PARENT_FIRST                    = pb.HierarchyOrder_PARENT_FIRST
+	OverrideAction_ALLOW                           = pb.OverrideAction_ALLOW
+	OverrideAction_DENY                            = pb.OverrideAction_DENY
+	OverrideAction_PAUSE                           = pb.OverrideAction_PAUSE
+	PeersV1_AcquirePeerLease_FullMethodName        = pb.PeersV1_AcquirePeerLease_FullMethodName
+	PeersV1_CancelPeerReservation_FullMethodName   = pb.PeersV1_CancelPeerReservation_FullMethodName
+	PeersV1_CommitPeerReservation_FullMethodName   = pb.PeersV1_CommitPeerReservation_FullMethodName
+	PeersV1_GetPeerKeyFilter_FullMethodName        = pb.PeersV1_GetPeerKeyFilter_FullMethodName
+	PeersV1_GetPeerKeyHistory_FullMethodName       = pb.PeersV1_GetPeerKeyHistory_FullMethodName
+	PeersV1_GetPeerLimitDrift_FullMethodName       = pb.PeersV1_GetPeerLimitDrift_FullMethodName
+	PeersV1_GetPeerNamespaceUsage_FullMethodName   = pb.PeersV1_GetPeerNamespaceUsage_FullMethodName
+	PeersV1_GetPeerRateLimits_FullMethodName       = pb.PeersV1_GetPeerRateLimits_FullMethodName
+	PeersV1_GetPeerTraffic_FullMethodName          = pb.PeersV1_GetPeerTraffic_FullMethodName
+	PeersV1_GetPeerVersion_FullMethodName          = pb.PeersV1_GetPeerVersion_FullMethodName
+	PeersV1_ListPeerLimits_FullMethodName          = pb.PeersV1_ListPeerLimits_FullMethodName
+	PeersV1_ListPeerNamespaces_FullMethodName      = pb.PeersV1_ListPeerNamespaces_FullMethodName
+	PeersV1_ListPeerOverrides_FullMethodName       = pb.PeersV1_ListPeerOverrides_FullMethodName
+	PeersV1_RefundPeerRateLimit_FullMethodName     = pb.PeersV1_RefundPeerRateLimit_FullMethodName
+	PeersV1_RegisterPeerLimits_FullMethodName      = pb.PeersV1_RegisterPeerLimits_FullMethodName
+	PeersV1_ReleasePeerLease_FullMethodName        = pb.PeersV1_ReleasePeerLease_FullMethodName
+	PeersV1_ReplayPeerJournal_FullMethodName       = pb.PeersV1_ReplayPeerJournal_FullMethodName
+	PeersV1_ReplicatePeerRateLimits_FullMethodName = pb.PeersV1_ReplicatePeerRateLimits_FullMethodName
+	PeersV1_ReservePeerRateLimit_FullMethodName    = pb.PeersV1_ReservePeerRateLimit_FullMethodName
+	PeersV1_ResetPeerRateLimits_FullMethodName     = pb.PeersV1_ResetPeerRateLimits_FullMethodName
+	PeersV1_TransferPeerRateLimits_FullMethodName  = pb.PeersV1_TransferPeerRateLimits_FullMethodName
+	PeersV1_UpdatePeerGlobals_FullMethodName       = pb.PeersV1_UpdatePeerGlobals_FullMethodName
+	PeersV1_UpdatePeerOverrides_FullMethodName     = pb.PeersV1_UpdatePeerOverrides_FullMethodName
+	Status_OVER_LIMIT                              = pb.Status_OVER_LIMIT
+	Status_UNDER_LIMIT                             = pb.Status_UNDER_LIMIT
+	Status_UNKNOWN                                 = pb.Status_UNKNOWN
+	V1_AcquireLease_FullMethodName                 = pb.V1_AcquireLease_FullMethodName
+	V1_CancelReservation_FullMethodName            = pb.V1_CancelReservation_FullMethodName
+	V1_CommitReservation_FullMethodName            = pb.V1_CommitReservation_FullMethodName
+	V1_GetRateLimitGroup_FullMethodName            = pb.V1_GetRateLimitGroup_FullMethodName
+	V1_GetRateLimitHierarchy_FullMethodName        = pb.V1_GetRateLimitHierarchy_FullMethodName
+	V1_GetRateLimits_FullMethodName                = pb.V1_GetRateLimits_FullMethodName
+	V1_HealthCheck_FullMethodName                  = pb.V1_HealthCheck_FullMethodName
+	V1_RefundRateLimit_FullMethodName              = pb.V1_RefundRateLimit_FullMethodName
+	V1_RegisterLimits_FullMethodName               = pb.V1_RegisterLimits_FullMethodName
+	V1_ReleaseLease_FullMethodName                 = pb.V1_ReleaseLease_FullMethodName
+	V1_ReserveRateLimit_FullMethodName             = pb.V1_ReserveRateLimit_FullMethodName
+	V1_StreamHits_FullMethodName                   = pb.V1_StreamHits_FullMethodName
+	V1_TransactRateLimits_FullMethodName           = pb.V1_TransactRateLimits_FullMethodName
+)
+
+var (
+	AdminV1_ServiceDesc                = pb.AdminV1_ServiceDesc
+	Algorithm_name                     = pb.Algorithm_name
+	Algorithm_value                    = pb.Algorithm_value
+	Behavior_name                      = pb.Behavior_name
+	Behavior_value                     = pb.Behavior_value
+	File_admin_proto                   = pb.File_admin_proto
+	File_gubernator_proto              = pb.File_gubernator_proto
+	File_peers_proto                   = pb.File_peers_proto
+	HierarchyOrder_name                = pb.HierarchyOrder_name
+	HierarchyOrder_value               = pb.HierarchyOrder_value
+	OverrideAction_name                = pb.OverrideAction_name
+	OverrideAction_value               = pb.OverrideAction_value
+	PeersV1_ServiceDesc                = pb.PeersV1_ServiceDesc
+	Status_name                        = pb.Status_name
+	Status_value                       = pb.Status_value
+	V1_ServiceDesc                     = pb.V1_ServiceDesc
+	NewAdminV1Client                   = pb.NewAdminV1Client
+	NewBatchSummary                    = pb.NewBatchSummary
+	NewPeersV1Client                   = pb.NewPeersV1Client
+	NewV1Client                        = pb.NewV1Client
+	RegisterAdminV1Handler             = pb.RegisterAdminV1Handler
+	RegisterAdminV1HandlerClient       = pb.RegisterAdminV1HandlerClient
+	RegisterAdminV1HandlerFromEndpoint = pb.RegisterAdminV1HandlerFromEndpoint
+	RegisterAdminV1HandlerServer       = pb.RegisterAdminV1HandlerServer
+	RegisterAdminV1Server              = pb.RegisterAdminV1Server
+	RegisterPeersV1Handler             = pb.RegisterPeersV1Handler
+	RegisterPeersV1HandlerClient       = pb.RegisterPeersV1HandlerClient
+	RegisterPeersV1HandlerFromEndpoint = pb.RegisterPeersV1HandlerFromEndpoint
+	RegisterPeersV1HandlerServer       = pb.RegisterPeersV1HandlerServer
+	RegisterPeersV1Server              = pb.RegisterPeersV1Server
+	RegisterV1Handler                  = pb.RegisterV1Handler
+	RegisterV1HandlerClient            = pb.RegisterV1HandlerClient
+	RegisterV1HandlerFromEndpoint      = pb.RegisterV1HandlerFromEndpoint
+	RegisterV1HandlerServer            = pb.RegisterV1HandlerServer
+	RegisterV1Server                   = pb.RegisterV1Server
+)
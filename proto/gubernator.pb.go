@@ -0,0 +1,3499 @@
+//
+//Copyright 2018-2022 Mailgun Technologies Inc
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: gubernator.proto
+
+package proto
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// The order the levels of a hierarchy are evaluated in. The levels after the first level
+// which is over the limit are not consumed from.
+type HierarchyOrder int32
+
+const (
+	// Evaluate the root first, such that a child is not consumed from while its parent is over the limit
+	HierarchyOrder_PARENT_FIRST HierarchyOrder = 0
+	// Evaluate the child first, such that a parent is not consumed from while its child is over the limit
+	HierarchyOrder_CHILD_FIRST HierarchyOrder = 1
+)
+
+// Enum value maps for HierarchyOrder.
+var (
+	HierarchyOrder_name = map[int32]string{
+		0: "PARENT_FIRST",
+		1: "CHILD_FIRST",
+	}
+	HierarchyOrder_value = map[string]int32{
+		"PARENT_FIRST": 0,
+		"CHILD_FIRST":  1,
+	}
+)
+
+func (x HierarchyOrder) Enum() *HierarchyOrder {
+	p := new(HierarchyOrder)
+	*p = x
+	return p
+}
+
+func (x HierarchyOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HierarchyOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_gubernator_proto_enumTypes[0].Descriptor()
+}
+
+func (HierarchyOrder) Type() protoreflect.EnumType {
+	return &file_gubernator_proto_enumTypes[0]
+}
+
+func (x HierarchyOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HierarchyOrder.Descriptor instead.
+func (HierarchyOrder) EnumDescriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{0}
+}
+
+type Algorithm int32
+
+const (
+	// Token bucket algorithm https://en.wikipedia.org/wiki/Token_bucket
+	Algorithm_TOKEN_BUCKET Algorithm = 0
+	// Leaky bucket algorithm https://en.wikipedia.org/wiki/Leaky_bucket
+	Algorithm_LEAKY_BUCKET Algorithm = 1
+)
+
+// Enum value maps for Algorithm.
+var (
+	Algorithm_name = map[int32]string{
+		0: "TOKEN_BUCKET",
+		1: "LEAKY_BUCKET",
+	}
+	Algorithm_value = map[string]int32{
+		"TOKEN_BUCKET": 0,
+		"LEAKY_BUCKET": 1,
+	}
+)
+
+func (x Algorithm) Enum() *Algorithm {
+	p := new(Algorithm)
+	*p = x
+	return p
+}
+
+func (x Algorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Algorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_gubernator_proto_enumTypes[1].Descriptor()
+}
+
+func (Algorithm) Type() protoreflect.EnumType {
+	return &file_gubernator_proto_enumTypes[1]
+}
+
+func (x Algorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Algorithm.Descriptor instead.
+func (Algorithm) EnumDescriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{1}
+}
+
+// A set of int32 flags used to control the behavior of a rate limit in gubernator
+type Behavior int32
+
+const (
+	// BATCHING is the default behavior. This enables batching requests which protects the
+	// service from thundering herd. IE: When a service experiences spikes of unexpected high
+	// volume requests.
+	//
+	// Using this option introduces a small amount of latency depending on
+	// the `batchWait` setting. Defaults to around 500 Microseconds of additional
+	// latency in low throughput situations. For high volume loads, batching can reduce
+	// the overall load on the system substantially.
+	Behavior_BATCHING Behavior = 0 // <-- this is here because proto requires it, but has no effect if used
+	// Disables batching. Use this for super low latency rate limit requests when
+	// thundering herd is not a concern but latency of requests is of paramount importance.
+	Behavior_NO_BATCHING Behavior = 1
+	// Enables Global caching of the rate limit. Use this if the rate limit applies globally to
+	// all ingress requests. (IE: Throttle hundreds of thousands of requests to an entire
+	// datacenter or cluster of http servers)
+	//
+	// Using this option gubernator will continue to use a single peer as the rate limit coordinator
+	// to increment and manage the state of the rate limit, however the result of the rate limit is
+	// distributed to each peer and cached locally. A rate limit request received from any peer in the
+	// cluster will first check the local cache for a rate limit answer, if it exists the peer will
+	// immediately return the answer to the client and asynchronously forward the aggregate hits to
+	// the owner peer. Because of GLOBALS async nature we lose some accuracy in rate limit
+	// reporting, which may result in allowing some requests beyond the chosen rate limit. However we
+	// gain massive performance as every request coming into the system does not have to wait for a
+	// single peer to decide if the rate limit has been reached.
+	Behavior_GLOBAL Behavior = 2
+	// Changes the behavior of the `Duration` field. When `Behavior` is set to `DURATION_IS_GREGORIAN`
+	// the `Duration` of the rate limit is reset whenever the end of selected GREGORIAN calendar
+	// interval is reached.
+	//
+	// Given the following `Duration` values
+	//
+	//	0 = Minutes
+	//	1 = Hours
+	//	2 = Days
+	//	3 = Weeks
+	//	4 = Months
+	//	5 = Years
+	//
+	// Examples when using `Behavior = DURATION_IS_GREGORIAN`
+	//
+	// If  `Duration = 2` (Days) then the rate limit will expire at the end of the current day the
+	// rate limit was created.
+	//
+	// If `Duration = 0` (Minutes) then the rate limit will expire at the end of the current minute
+	// the rate limit was created.
+	//
+	// If `Duration = 4` (Months) then the rate limit will expire at the end of the current month
+	// the rate limit was created.
+	Behavior_DURATION_IS_GREGORIAN Behavior = 4
+	// If this flag is set causes the rate limit to reset any accrued hits stored in the cache, and will
+	// ignore any `Hit` values provided in the current request. The effect this has is dependent on
+	// algorithm chosen. For instance, if used with `TOKEN_BUCKET` it will immediately expire the
+	// cache value. For `LEAKY_BUCKET` it sets the `Remaining` to `Limit`.
+	Behavior_RESET_REMAINING Behavior = 8
+	// Enables rate limits to be pushed to other regions. Currently this is only implemented when using
+	// 'member-list' peer discovery. Also requires GUBER_DATA_CENTER to be set to different values on at
+	// least 2 instances of Gubernator.
+	Behavior_MULTI_REGION Behavior = 16
+	// A GetRateLimits call drains the remaining counter on first over limit
+	// event. Then, successive GetRateLimits calls will return zero remaining
+	// counter and not any residual value.
+	Behavior_DRAIN_OVER_LIMIT Behavior = 32
+	// Evaluates the rate limit as usual, hits are applied and metrics are recorded, however the
+	// response always reports `UNDER_LIMIT`. If the rate limit would have been over the limit, the
+	// response metadata field `dry_run_status` is set to `OVER_LIMIT`. Use this to shadow new rate
+	// limits in production and measure their impact before enforcing them.
+	Behavior_DRY_RUN Behavior = 64
+	// Changes how `TOKEN_BUCKET` refills. By default every token is refilled at once when the `Duration`
+	// of the rate limit has elapsed. When `GREEDY_REFILL` is set tokens are refilled continuously as time
+	// elapses at the rate of `Limit` tokens per `Duration`. IE: If `Limit = 60` and `Duration = 60000`
+	// (1 Minute) then a token is refilled every second, and `reset_time` is the time the bucket is full.
+	// Has no effect on `LEAKY_BUCKET` or when used with `DURATION_IS_GREGORIAN`.
+	Behavior_GREEDY_REFILL Behavior = 128
+	// Records the hits applied by the request on the owning peer, such that they may be returned to the
+	// rate limit with RefundRateLimit if the operation they were consumed for fails. If the rate limit is
+	// under the limit, the response metadata field `refund_id` identifies the hits to refund. Hits which
+	// are not refunded within `BehaviorConfig.RefundWindow` can no longer be refunded. Has no effect when
+	// used with GLOBAL.
+	Behavior_REFUNDABLE Behavior = 256
+	// Evaluates the rate limit on the instance which received the request as if it owned the rate limit,
+	// instead of forwarding it to the owning peer. Useful for tests and to keep operating while the owning
+	// peer is unreachable. Hits applied locally are not seen by the owning peer. Requires the instance to
+	// enable `GUBER_FORWARDING_OVERRIDES_ENABLED`, cannot be used with FORCE_PEER.
+	Behavior_FORCE_LOCAL Behavior = 512
+	// Forwards the rate limit to the owning peer through the PeersV1 API, even if the instance which
+	// received the request believes it owns the rate limit or the rate limit is GLOBAL. The response
+	// metadata field `owner` reports the peer which evaluated the rate limit, which combined with
+	// `GUBER_VERIFY_PEER_OWNERSHIP` verifies the peers agree on the owner. Requires the instance to
+	// enable `GUBER_FORWARDING_OVERRIDES_ENABLED`, cannot be used with FORCE_LOCAL.
+	Behavior_FORCE_PEER Behavior = 1024
+	// Changes what `max_capacity` does for a `TOKEN_BUCKET`. When the window of the rate limit resets, the
+	// tokens left unused are added to the `limit` of the next window, up to `max_capacity` tokens, instead of
+	// refilling tokens continuously. IE: If `limit = 1000`, `duration = 4` (Months) with `DURATION_IS_GREGORIAN`
+	// and `max_capacity = 3000`, a client which used 400 hits in January has 1600 hits in February. Only the
+	// tokens of the previous window roll over; a rate limit idle for an entire window starts from `limit`.
+	// Has no effect if `max_capacity` is less than or equal to `limit`, on `LEAKY_BUCKET` or when used with
+	// `GREEDY_REFILL`.
+	Behavior_ROLLOVER Behavior = 2048
+)
+
+// Enum value maps for Behavior.
+var (
+	Behavior_name = map[int32]string{
+		0:    "BATCHING",
+		1:    "NO_BATCHING",
+		2:    "GLOBAL",
+		4:    "DURATION_IS_GREGORIAN",
+		8:    "RESET_REMAINING",
+		16:   "MULTI_REGION",
+		32:   "DRAIN_OVER_LIMIT",
+		64:   "DRY_RUN",
+		128:  "GREEDY_REFILL",
+		256:  "REFUNDABLE",
+		512:  "FORCE_LOCAL",
+		1024: "FORCE_PEER",
+		2048: "ROLLOVER",
+	}
+	Behavior_value = map[string]int32{
+		"BATCHING":              0,
+		"NO_BATCHING":           1,
+		"GLOBAL":                2,
+		"DURATION_IS_GREGORIAN": 4,
+		"RESET_REMAINING":       8,
+		"MULTI_REGION":          16,
+		"DRAIN_OVER_LIMIT":      32,
+		"DRY_RUN":               64,
+		"GREEDY_REFILL":         128,
+		"REFUNDABLE":            256,
+		"FORCE_LOCAL":           512,
+		"FORCE_PEER":            1024,
+		"ROLLOVER":              2048,
+	}
+)
+
+func (x Behavior) Enum() *Behavior {
+	p := new(Behavior)
+	*p = x
+	return p
+}
+
+func (x Behavior) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Behavior) Descriptor() protoreflect.EnumDescriptor {
+	return file_gubernator_proto_enumTypes[2].Descriptor()
+}
+
+func (Behavior) Type() protoreflect.EnumType {
+	return &file_gubernator_proto_enumTypes[2]
+}
+
+func (x Behavior) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Behavior.Descriptor instead.
+func (Behavior) EnumDescriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{2}
+}
+
+type Status int32
+
+const (
+	Status_UNDER_LIMIT Status = 0
+	Status_OVER_LIMIT  Status = 1
+	// The rate limit could not be evaluated, IE: the peer which owns it could not be reached. The
+	// `error` of the response is set, and its metadata `error_code` is the reason. Clients which
+	// fail open treat the request as allowed without having to parse the error.
+	Status_UNKNOWN Status = 2
+)
+
+// Enum value maps for Status.
+var (
+	Status_name = map[int32]string{
+		0: "UNDER_LIMIT",
+		1: "OVER_LIMIT",
+		2: "UNKNOWN",
+	}
+	Status_value = map[string]int32{
+		"UNDER_LIMIT": 0,
+		"OVER_LIMIT":  1,
+		"UNKNOWN":     2,
+	}
+)
+
+func (x Status) Enum() *Status {
+	p := new(Status)
+	*p = x
+	return p
+}
+
+func (x Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_gubernator_proto_enumTypes[3].Descriptor()
+}
+
+func (Status) Type() protoreflect.EnumType {
+	return &file_gubernator_proto_enumTypes[3]
+}
+
+func (x Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Status.Descriptor instead.
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{3}
+}
+
+// Must specify at least one Request
+type GetRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*RateLimitReq `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// If true, the `metadata` field is omitted from every response. Use this to reduce
+	// the size of large batch responses when the caller has no use for the metadata.
+	MinimalResponse bool `protobuf:"varint,2,opt,name=minimal_response,json=minimalResponse,proto3" json:"minimal_response,omitempty"`
+	// If true, the first rate limit in the batch which returns an error aborts the rest of the
+	// batch, including requests in flight to other peers. Every rate limit which was aborted
+	// returns an error. Use this when the batch is treated as all-or-nothing and the caller
+	// would rather retry the entire batch than wait for the remaining rate limits.
+	FailFast bool `protobuf:"varint,3,opt,name=fail_fast,json=failFast,proto3" json:"fail_fast,omitempty"`
+	// The values of the rate limits in `requests` which omit them, such that a batch of rate
+	// limits in the same namespace does not repeat the namespace in every rate limit.
+	Defaults *RateLimitDefaults `protobuf:"bytes,4,opt,name=defaults,proto3" json:"defaults,omitempty"`
+}
+
+func (x *GetRateLimitsReq) Reset() {
+	*x = GetRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsReq) ProtoMessage() {}
+
+func (x *GetRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetRateLimitsReq) GetRequests() []*RateLimitReq {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *GetRateLimitsReq) GetMinimalResponse() bool {
+	if x != nil {
+		return x.MinimalResponse
+	}
+	return false
+}
+
+func (x *GetRateLimitsReq) GetFailFast() bool {
+	if x != nil {
+		return x.FailFast
+	}
+	return false
+}
+
+func (x *GetRateLimitsReq) GetDefaults() *RateLimitDefaults {
+	if x != nil {
+		return x.Defaults
+	}
+	return nil
+}
+
+type RateLimitDefaults struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the rate limits whose `name` is empty
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The algorithm of the rate limits whose `algorithm` is TOKEN_BUCKET, which is the value of
+	// an omitted algorithm. As such a rate limit cannot ask for TOKEN_BUCKET when this is set.
+	Algorithm Algorithm `protobuf:"varint,2,opt,name=algorithm,proto3,enum=pb.gubernator.Algorithm" json:"algorithm,omitempty"`
+	// The behavior of the rate limits whose `behavior` is BATCHING, IE: no flags are set
+	Behavior Behavior `protobuf:"varint,3,opt,name=behavior,proto3,enum=pb.gubernator.Behavior" json:"behavior,omitempty"`
+}
+
+func (x *RateLimitDefaults) Reset() {
+	*x = RateLimitDefaults{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateLimitDefaults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitDefaults) ProtoMessage() {}
+
+func (x *RateLimitDefaults) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitDefaults.ProtoReflect.Descriptor instead.
+func (*RateLimitDefaults) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateLimitDefaults) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RateLimitDefaults) GetAlgorithm() Algorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return Algorithm_TOKEN_BUCKET
+}
+
+func (x *RateLimitDefaults) GetBehavior() Behavior {
+	if x != nil {
+		return x.Behavior
+	}
+	return Behavior_BATCHING
+}
+
+// RateLimits returned are in the same order as the Requests
+type GetRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*RateLimitResp `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	// The outcome of the batch as a whole, such that a client may log one line instead of
+	// inspecting every response, IE: when the owner of a namespace is down.
+	Summary *BatchSummary `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+}
+
+func (x *GetRateLimitsResp) Reset() {
+	*x = GetRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitsResp) ProtoMessage() {}
+
+func (x *GetRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*GetRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRateLimitsResp) GetResponses() []*RateLimitResp {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+func (x *GetRateLimitsResp) GetSummary() *BatchSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type BatchSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of rate limits which were under the limit
+	UnderLimit int32 `protobuf:"varint,1,opt,name=under_limit,json=underLimit,proto3" json:"under_limit,omitempty"`
+	// The number of rate limits which were over the limit
+	OverLimit int32 `protobuf:"varint,2,opt,name=over_limit,json=overLimit,proto3" json:"over_limit,omitempty"`
+	// The number of rate limits which returned an error
+	Errors int32 `protobuf:"varint,3,opt,name=errors,proto3" json:"errors,omitempty"`
+	// The sorted names of the rate limits which returned an error
+	ErrorNamespaces []string `protobuf:"bytes,4,rep,name=error_namespaces,json=errorNamespaces,proto3" json:"error_namespaces,omitempty"`
+}
+
+func (x *BatchSummary) Reset() {
+	*x = BatchSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSummary) ProtoMessage() {}
+
+func (x *BatchSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSummary.ProtoReflect.Descriptor instead.
+func (*BatchSummary) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BatchSummary) GetUnderLimit() int32 {
+	if x != nil {
+		return x.UnderLimit
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetOverLimit() int32 {
+	if x != nil {
+		return x.OverLimit
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetErrors() int32 {
+	if x != nil {
+		return x.Errors
+	}
+	return 0
+}
+
+func (x *BatchSummary) GetErrorNamespaces() []string {
+	if x != nil {
+		return x.ErrorNamespaces
+	}
+	return nil
+}
+
+// Must specify at least one Request
+type GetRateLimitGroupReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Requests []*RateLimitReq `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *GetRateLimitGroupReq) Reset() {
+	*x = GetRateLimitGroupReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitGroupReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitGroupReq) ProtoMessage() {}
+
+func (x *GetRateLimitGroupReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitGroupReq.ProtoReflect.Descriptor instead.
+func (*GetRateLimitGroupReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetRateLimitGroupReq) GetRequests() []*RateLimitReq {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type GetRateLimitGroupResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UNDER_LIMIT if every rate limit in the group was under the limit and the hits
+	// were applied, OVER_LIMIT if no hits were applied.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.gubernator.Status" json:"status,omitempty"`
+	// RateLimits returned are in the same order as the Requests
+	Responses []*RateLimitResp `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *GetRateLimitGroupResp) Reset() {
+	*x = GetRateLimitGroupResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitGroupResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitGroupResp) ProtoMessage() {}
+
+func (x *GetRateLimitGroupResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitGroupResp.ProtoReflect.Descriptor instead.
+func (*GetRateLimitGroupResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRateLimitGroupResp) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNDER_LIMIT
+}
+
+func (x *GetRateLimitGroupResp) GetResponses() []*RateLimitResp {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type ReserveRateLimitReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The rate limit to reserve `rate_limit.hits` against. `hits` must be greater
+	// than zero and the GLOBAL behavior is not supported.
+	RateLimit *RateLimitReq `protobuf:"bytes,1,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// The number of milliseconds the reservation is held before it is canceled
+	Ttl int64 `protobuf:"varint,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+}
+
+func (x *ReserveRateLimitReq) Reset() {
+	*x = ReserveRateLimitReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveRateLimitReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRateLimitReq) ProtoMessage() {}
+
+func (x *ReserveRateLimitReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRateLimitReq.ProtoReflect.Descriptor instead.
+func (*ReserveRateLimitReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReserveRateLimitReq) GetRateLimit() *RateLimitReq {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *ReserveRateLimitReq) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+type ReserveRateLimitResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The state of the rate limit after the hits were reserved. If the status is
+	// OVER_LIMIT no hits were reserved and `reservation_id` is empty.
+	RateLimit *RateLimitResp `protobuf:"bytes,1,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Identifies the reservation to CommitReservation or CancelReservation
+	ReservationId string `protobuf:"bytes,2,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	// Timestamp when the reservation is canceled in epoch milliseconds
+	ExpireAt int64 `protobuf:"varint,3,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
+}
+
+func (x *ReserveRateLimitResp) Reset() {
+	*x = ReserveRateLimitResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveRateLimitResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveRateLimitResp) ProtoMessage() {}
+
+func (x *ReserveRateLimitResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveRateLimitResp.ProtoReflect.Descriptor instead.
+func (*ReserveRateLimitResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReserveRateLimitResp) GetRateLimit() *RateLimitResp {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+func (x *ReserveRateLimitResp) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+func (x *ReserveRateLimitResp) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+type ReservationReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name and unique_key of the rate limit the reservation was made against
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UniqueKey string `protobuf:"bytes,2,opt,name=unique_key,json=uniqueKey,proto3" json:"unique_key,omitempty"`
+	// The reservation_id returned by ReserveRateLimit
+	ReservationId string `protobuf:"bytes,3,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+}
+
+func (x *ReservationReq) Reset() {
+	*x = ReservationReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReservationReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationReq) ProtoMessage() {}
+
+func (x *ReservationReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationReq.ProtoReflect.Descriptor instead.
+func (*ReservationReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReservationReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReservationReq) GetUniqueKey() string {
+	if x != nil {
+		return x.UniqueKey
+	}
+	return ""
+}
+
+func (x *ReservationReq) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+type ReservationResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of hits committed, or returned to the rate limit when canceled
+	Hits int64 `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+}
+
+func (x *ReservationResp) Reset() {
+	*x = ReservationResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReservationResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationResp) ProtoMessage() {}
+
+func (x *ReservationResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationResp.ProtoReflect.Descriptor instead.
+func (*ReservationResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReservationResp) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type TransactRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The rate limits to apply the hits of. `hits` must be greater than zero
+	// and the GLOBAL behavior is not supported.
+	Requests []*RateLimitReq `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// The number of milliseconds the hits are held before they are returned to the rate
+	// limits, should the transaction not commit in time. Defaults to 5 seconds
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *TransactRateLimitsReq) Reset() {
+	*x = TransactRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactRateLimitsReq) ProtoMessage() {}
+
+func (x *TransactRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*TransactRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TransactRateLimitsReq) GetRequests() []*RateLimitReq {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *TransactRateLimitsReq) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type TransactRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UNDER_LIMIT if the hits of every rate limit were applied, OVER_LIMIT if no hits
+	// were applied. UNKNOWN if some of the reservations failed to commit, the
+	// `error` of their responses holds the reason.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.gubernator.Status" json:"status,omitempty"`
+	// RateLimits returned are in the same order as the Requests
+	Responses []*RateLimitResp `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *TransactRateLimitsResp) Reset() {
+	*x = TransactRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransactRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactRateLimitsResp) ProtoMessage() {}
+
+func (x *TransactRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*TransactRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TransactRateLimitsResp) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNDER_LIMIT
+}
+
+func (x *TransactRateLimitsResp) GetResponses() []*RateLimitResp {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type GetRateLimitHierarchyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The levels of the hierarchy starting with the root, IE: the organization, the project
+	// then the key. The GLOBAL behavior is not supported. The `hits` of the levels are ignored.
+	Levels []*RateLimitReq `protobuf:"bytes,1,rep,name=levels,proto3" json:"levels,omitempty"`
+	// The hits consumed from every level, must be greater than zero
+	Hits int64 `protobuf:"varint,2,opt,name=hits,proto3" json:"hits,omitempty"`
+	// The order the levels are evaluated in. Defaults to PARENT_FIRST
+	Order HierarchyOrder `protobuf:"varint,3,opt,name=order,proto3,enum=pb.gubernator.HierarchyOrder" json:"order,omitempty"`
+	// The number of milliseconds the hits are held before they are returned to the rate
+	// limits, should the hierarchy not commit in time. Defaults to 5 seconds
+	Timeout int64 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (x *GetRateLimitHierarchyReq) Reset() {
+	*x = GetRateLimitHierarchyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitHierarchyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitHierarchyReq) ProtoMessage() {}
+
+func (x *GetRateLimitHierarchyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitHierarchyReq.ProtoReflect.Descriptor instead.
+func (*GetRateLimitHierarchyReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetRateLimitHierarchyReq) GetLevels() []*RateLimitReq {
+	if x != nil {
+		return x.Levels
+	}
+	return nil
+}
+
+func (x *GetRateLimitHierarchyReq) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *GetRateLimitHierarchyReq) GetOrder() HierarchyOrder {
+	if x != nil {
+		return x.Order
+	}
+	return HierarchyOrder_PARENT_FIRST
+}
+
+func (x *GetRateLimitHierarchyReq) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type GetRateLimitHierarchyResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// UNDER_LIMIT if the hits were consumed from every level, OVER_LIMIT if no hits were
+	// consumed. UNKNOWN if some of the reservations failed to commit, the `error` of their
+	// responses holds the reason.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.gubernator.Status" json:"status,omitempty"`
+	// The state of each level, in the same order as the levels of the request. The levels which
+	// were not evaluated because an earlier level was over the limit report their current state.
+	Responses []*RateLimitResp `protobuf:"bytes,2,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *GetRateLimitHierarchyResp) Reset() {
+	*x = GetRateLimitHierarchyResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRateLimitHierarchyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRateLimitHierarchyResp) ProtoMessage() {}
+
+func (x *GetRateLimitHierarchyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRateLimitHierarchyResp.ProtoReflect.Descriptor instead.
+func (*GetRateLimitHierarchyResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetRateLimitHierarchyResp) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNDER_LIMIT
+}
+
+func (x *GetRateLimitHierarchyResp) GetResponses() []*RateLimitResp {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type RefundReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name and unique_key of the rate limit the hits were applied to
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UniqueKey string `protobuf:"bytes,2,opt,name=unique_key,json=uniqueKey,proto3" json:"unique_key,omitempty"`
+	// The `refund_id` from the metadata of the RateLimitResp
+	RefundId string `protobuf:"bytes,3,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+}
+
+func (x *RefundReq) Reset() {
+	*x = RefundReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RefundReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundReq) ProtoMessage() {}
+
+func (x *RefundReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundReq.ProtoReflect.Descriptor instead.
+func (*RefundReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RefundReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RefundReq) GetUniqueKey() string {
+	if x != nil {
+		return x.UniqueKey
+	}
+	return ""
+}
+
+func (x *RefundReq) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+type RefundResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of hits returned to the rate limit
+	Hits int64 `protobuf:"varint,1,opt,name=hits,proto3" json:"hits,omitempty"`
+}
+
+func (x *RefundResp) Reset() {
+	*x = RefundResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RefundResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundResp) ProtoMessage() {}
+
+func (x *RefundResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundResp.ProtoReflect.Descriptor instead.
+func (*RefundResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RefundResp) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+type LeaseReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the lease IE: 'nightly-report'
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Uniquely identifies the holder of the lease IE: the hostname of the caller
+	Holder string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	// The number of milliseconds the lease is held before it expires, ignored by ReleaseLease
+	Ttl int64 `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+}
+
+func (x *LeaseReq) Reset() {
+	*x = LeaseReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaseReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseReq) ProtoMessage() {}
+
+func (x *LeaseReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseReq.ProtoReflect.Descriptor instead.
+func (*LeaseReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LeaseReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LeaseReq) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *LeaseReq) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+type LeaseResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// True if `holder` holds the lease
+	Acquired bool `protobuf:"varint,1,opt,name=acquired,proto3" json:"acquired,omitempty"`
+	// The current holder of the lease, empty if no one holds the lease
+	Holder string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	// Timestamp when the lease held by `holder` expires in epoch milliseconds
+	ExpireAt int64 `protobuf:"varint,3,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
+}
+
+func (x *LeaseResp) Reset() {
+	*x = LeaseResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeaseResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaseResp) ProtoMessage() {}
+
+func (x *LeaseResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaseResp.ProtoReflect.Descriptor instead.
+func (*LeaseResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LeaseResp) GetAcquired() bool {
+	if x != nil {
+		return x.Acquired
+	}
+	return false
+}
+
+func (x *LeaseResp) GetHolder() string {
+	if x != nil {
+		return x.Holder
+	}
+	return ""
+}
+
+func (x *LeaseResp) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+type RateLimitReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the rate limit IE: 'requests_per_second', 'gets_per_minute`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Uniquely identifies this rate limit IE: 'ip:10.2.10.7' or 'account:123445'
+	UniqueKey string `protobuf:"bytes,2,opt,name=unique_key,json=uniqueKey,proto3" json:"unique_key,omitempty"`
+	// Rate limit requests optionally specify the number of hits a request adds to the matched limit. If Hit
+	// is zero, the request returns the current limit, but does not increment the hit count.
+	//
+	// Requests with zero hits are query only; they never change the remaining hits, limit, burst or
+	// duration, nor extend the expiration of the rate limit, and do not create a rate limit which does
+	// not exist. Changes to the limit, burst or duration are reflected in the response but only stored
+	// by the next request with hits. The `RESET_REMAINING` behavior still resets the rate limit.
+	//
+	// Negative hits return hits to the rate limit, the remaining hits may exceed the limit but never
+	// 2^53. Hits cannot be greater than 2^53 or less than -2^53.
+	Hits int64 `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	// The number of requests that can occur for the duration of the rate limit. Cannot be negative
+	// or greater than 2^53.
+	Limit int64 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The duration of the rate limit in milliseconds. Cannot be negative or longer than about 1,000
+	// years (31,536,000,000,000 milliseconds).
+	// Second = 1000 Milliseconds
+	// Minute = 60000 Milliseconds
+	// Hour = 3600000 Milliseconds
+	Duration int64 `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	// The algorithm used to calculate the rate limit. The algorithm may change on
+	// subsequent requests, when this occurs any previous rate limit hit counts are reset.
+	Algorithm Algorithm `protobuf:"varint,6,opt,name=algorithm,proto3,enum=pb.gubernator.Algorithm" json:"algorithm,omitempty"`
+	// Behavior is a set of int32 flags that control the behavior of the rate limit in gubernator
+	Behavior Behavior `protobuf:"varint,7,opt,name=behavior,proto3,enum=pb.gubernator.Behavior" json:"behavior,omitempty"`
+	// Maximum burst size that the limit can accept.
+	Burst int64 `protobuf:"varint,8,opt,name=burst,proto3" json:"burst,omitempty"`
+	// This is metadata that is associated with this rate limit. Peer to Peer communication will use
+	// this to pass trace context to other peers. Might be useful for future clients to pass along
+	// trace information to gubernator.
+	Metadata map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The exact time this request was created in Epoch milliseconds.  Due to
+	// time drift between systems, it may be advantageous for a client to set the
+	// exact time the request was created. It possible the system clock for the
+	// client has drifted from the system clock where gubernator daemon is
+	// running.
+	//
+	// The created time is used by gubernator to calculate the reset time for
+	// both token and leaky algorithms. If it is not set by the client,
+	// gubernator will set the created time when it receives the rate limit
+	// request.
+	CreatedAt *int64 `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3,oneof" json:"created_at,omitempty"`
+	// Identifies this request when tracing it across peers. If empty, gubernator generates a
+	// request id. The request id is forwarded to the peer which owns the rate limit, included in
+	// log lines and audit records related to this request and echoed in the RateLimitResp.
+	RequestId string `protobuf:"bytes,11,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// The maximum number of tokens a `TOKEN_BUCKET` accumulates while idle. Tokens are refilled
+	// continuously at the rate of `limit` tokens per `duration` as with `GREEDY_REFILL`, but the bucket
+	// holds up to `max_capacity` tokens instead of `limit`, such that idle clients may burst more than
+	// `limit` without raising the steady state rate. IE: If `limit = 10`, `duration = 60000` and
+	// `max_capacity = 30` then 10 tokens are refilled every minute and up to 30 tokens are available
+	// after 3 idle minutes. Has no effect if less than or equal to `limit`, on `LEAKY_BUCKET` (see `burst`)
+	// or when used with `DURATION_IS_GREGORIAN`. With `ROLLOVER` it caps the tokens rolled over from the
+	// previous window instead. Cannot be negative or greater than 2^53.
+	MaxCapacity int64 `protobuf:"varint,12,opt,name=max_capacity,json=maxCapacity,proto3" json:"max_capacity,omitempty"`
+	// Identifies the hits of this request such that they are applied at most once. If a request with
+	// the same name, unique key and idempotency key was evaluated within the idempotency window
+	// (`GUBER_IDEMPOTENCY_WINDOW`), the original response is returned without applying the hits again
+	// and its metadata includes `idempotent_replay: true`. Use this to safely retry requests which
+	// timed out. For `GLOBAL` rate limits the key is only remembered by the peer which received the request.
+	IdempotencyKey string `protobuf:"bytes,13,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+}
+
+func (x *RateLimitReq) Reset() {
+	*x = RateLimitReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateLimitReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitReq) ProtoMessage() {}
+
+func (x *RateLimitReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitReq.ProtoReflect.Descriptor instead.
+func (*RateLimitReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RateLimitReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RateLimitReq) GetUniqueKey() string {
+	if x != nil {
+		return x.UniqueKey
+	}
+	return ""
+}
+
+func (x *RateLimitReq) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetAlgorithm() Algorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return Algorithm_TOKEN_BUCKET
+}
+
+func (x *RateLimitReq) GetBehavior() Behavior {
+	if x != nil {
+		return x.Behavior
+	}
+	return Behavior_BATCHING
+}
+
+func (x *RateLimitReq) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RateLimitReq) GetCreatedAt() int64 {
+	if x != nil && x.CreatedAt != nil {
+		return *x.CreatedAt
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RateLimitReq) GetMaxCapacity() int64 {
+	if x != nil {
+		return x.MaxCapacity
+	}
+	return 0
+}
+
+func (x *RateLimitReq) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type RateLimitResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The status of the rate limit.
+	Status Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.gubernator.Status" json:"status,omitempty"`
+	// The currently configured request limit (Identical to [[RateLimitReq.limit]]).
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// This is the number of requests remaining before the rate limit is hit but after subtracting the hits from the current request
+	Remaining int64 `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// This is the time when the rate limit span will be reset, provided as a unix timestamp in milliseconds.
+	ResetTime int64 `protobuf:"varint,4,opt,name=reset_time,json=resetTime,proto3" json:"reset_time,omitempty"`
+	// Contains the error; If set all other values should be ignored
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// This is additional metadata that a client might find useful. (IE: Additional headers, coordinator ownership, etc..)
+	Metadata map[string]string `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The request id provided in, or generated for, the RateLimitReq
+	RequestId string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// LEAKY_BUCKET only: The number of hits in the bucket which have yet to leak out, IE: the depth of
+	// the queue of hits. Callers smoothing their own traffic may use this to decide how long to delay.
+	QueueDepth int64 `protobuf:"varint,8,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	// LEAKY_BUCKET only: The estimated number of milliseconds until every hit in the bucket leaked out.
+	DrainTime int64 `protobuf:"varint,9,opt,name=drain_time,json=drainTime,proto3" json:"drain_time,omitempty"`
+}
+
+func (x *RateLimitResp) Reset() {
+	*x = RateLimitResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RateLimitResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitResp) ProtoMessage() {}
+
+func (x *RateLimitResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitResp.ProtoReflect.Descriptor instead.
+func (*RateLimitResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RateLimitResp) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNDER_LIMIT
+}
+
+func (x *RateLimitResp) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RateLimitResp) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *RateLimitResp) GetResetTime() int64 {
+	if x != nil {
+		return x.ResetTime
+	}
+	return 0
+}
+
+func (x *RateLimitResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RateLimitResp) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RateLimitResp) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RateLimitResp) GetQueueDepth() int64 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *RateLimitResp) GetDrainTime() int64 {
+	if x != nil {
+		return x.DrainTime
+	}
+	return 0
+}
+
+// The limit, duration, algorithm, behavior and burst of every rate limit with the name of the
+// definition. The fields of a request with the name of a definition are replaced by those of the
+// definition, as such the request only needs the name, unique key and hits.
+type RegisteredLimit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the rate limits the definition applies to, IE: "per-user-login"
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The number of hits allowed per duration. Cannot be negative or greater than 2^53.
+	Limit int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The duration of the rate limit in milliseconds. Must be greater than zero.
+	Duration int64 `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// The algorithm of the rate limit
+	Algorithm Algorithm `protobuf:"varint,4,opt,name=algorithm,proto3,enum=pb.gubernator.Algorithm" json:"algorithm,omitempty"`
+	// Behaviors added to the behaviors of the request
+	Behavior Behavior `protobuf:"varint,5,opt,name=behavior,proto3,enum=pb.gubernator.Behavior" json:"behavior,omitempty"`
+	// The burst of a LEAKY_BUCKET, the burst of the request is used if zero
+	Burst int64 `protobuf:"varint,6,opt,name=burst,proto3" json:"burst,omitempty"`
+	// (Optional) The milliseconds over which a limit which replaces a registered limit is rolled
+	// out, such that a mistaken limit does not apply to every unique key at once. The unique keys
+	// which use the new limit are chosen by the hash of the unique key. If zero the new limit
+	// applies to every unique key once registered.
+	RolloutDuration int64 `protobuf:"varint,7,opt,name=rollout_duration,json=rolloutDuration,proto3" json:"rollout_duration,omitempty"`
+	// (Optional) The percent of unique keys which use the new limit once registered, the percent
+	// increases linearly to 100 over `rollout_duration`.
+	RolloutStartPercent int32 `protobuf:"varint,8,opt,name=rollout_start_percent,json=rolloutStartPercent,proto3" json:"rollout_start_percent,omitempty"`
+	// Set by the server, the time in Epoch milliseconds the rollout began
+	RolloutStart int64 `protobuf:"varint,9,opt,name=rollout_start,json=rolloutStart,proto3" json:"rollout_start,omitempty"`
+	// Set by the server, the limit replaced by the rollout which applies to the unique keys the new
+	// limit has not yet rolled out to
+	RolloutFrom *RegisteredLimit `protobuf:"bytes,10,opt,name=rollout_from,json=rolloutFrom,proto3" json:"rollout_from,omitempty"`
+}
+
+func (x *RegisteredLimit) Reset() {
+	*x = RegisteredLimit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisteredLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisteredLimit) ProtoMessage() {}
+
+func (x *RegisteredLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisteredLimit.ProtoReflect.Descriptor instead.
+func (*RegisteredLimit) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RegisteredLimit) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisteredLimit) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetAlgorithm() Algorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return Algorithm_TOKEN_BUCKET
+}
+
+func (x *RegisteredLimit) GetBehavior() Behavior {
+	if x != nil {
+		return x.Behavior
+	}
+	return Behavior_BATCHING
+}
+
+func (x *RegisteredLimit) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetRolloutDuration() int64 {
+	if x != nil {
+		return x.RolloutDuration
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetRolloutStartPercent() int32 {
+	if x != nil {
+		return x.RolloutStartPercent
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetRolloutStart() int64 {
+	if x != nil {
+		return x.RolloutStart
+	}
+	return 0
+}
+
+func (x *RegisteredLimit) GetRolloutFrom() *RegisteredLimit {
+	if x != nil {
+		return x.RolloutFrom
+	}
+	return nil
+}
+
+type RegisterLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The definitions to add or replace, definitions registered previously which are not in the
+	// request are left unchanged.
+	Limits []*RegisteredLimit `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+}
+
+func (x *RegisterLimitsReq) Reset() {
+	*x = RegisterLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterLimitsReq) ProtoMessage() {}
+
+func (x *RegisterLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterLimitsReq.ProtoReflect.Descriptor instead.
+func (*RegisterLimitsReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RegisterLimitsReq) GetLimits() []*RegisteredLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type RegisterLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// An error for each peer which was not updated, the definitions are copied to the peer once it
+	// is reachable again.
+	Errors []string `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *RegisterLimitsResp) Reset() {
+	*x = RegisterLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterLimitsResp) ProtoMessage() {}
+
+func (x *RegisterLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterLimitsResp.ProtoReflect.Descriptor instead.
+func (*RegisterLimitsResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RegisterLimitsResp) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type StreamHitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hits to add to each rate limit. The hits of a rate limit are summed until the end of the
+	// interval, the definition of the rate limit, IE: limit and duration, is taken from the most
+	// recent request. A request with hits = 0 asks for the status of the rate limit without a hit.
+	Requests []*RateLimitReq `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// (Optional) How often the hits are applied and the status is streamed back in milliseconds.
+	// Only read from the first message of the stream. Must be at least 10ms. Defaults to 1000ms
+	Interval int64 `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"`
+}
+
+func (x *StreamHitsReq) Reset() {
+	*x = StreamHitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamHitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHitsReq) ProtoMessage() {}
+
+func (x *StreamHitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHitsReq.ProtoReflect.Descriptor instead.
+func (*StreamHitsReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StreamHitsReq) GetRequests() []*RateLimitReq {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *StreamHitsReq) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+type StreamHitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The status of each rate limit which received requests during the interval
+	Statuses []*StreamHitsStatus `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+}
+
+func (x *StreamHitsResp) Reset() {
+	*x = StreamHitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamHitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHitsResp) ProtoMessage() {}
+
+func (x *StreamHitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHitsResp.ProtoReflect.Descriptor instead.
+func (*StreamHitsResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StreamHitsResp) GetStatuses() []*StreamHitsStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type StreamHitsStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name and unique key of the rate limit
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UniqueKey string `protobuf:"bytes,2,opt,name=unique_key,json=uniqueKey,proto3" json:"unique_key,omitempty"`
+	// The sum of the hits applied to the rate limit at the end of the interval
+	Hits int64 `protobuf:"varint,3,opt,name=hits,proto3" json:"hits,omitempty"`
+	// The status of the rate limit once the hits were applied. The hits of an interval are applied
+	// as a single request, such that if the sum is more than the remaining limit none of the hits
+	// are applied unless the rate limit has the DRAIN_OVER_LIMIT behavior.
+	RateLimit *RateLimitResp `protobuf:"bytes,4,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+}
+
+func (x *StreamHitsStatus) Reset() {
+	*x = StreamHitsStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamHitsStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamHitsStatus) ProtoMessage() {}
+
+func (x *StreamHitsStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamHitsStatus.ProtoReflect.Descriptor instead.
+func (*StreamHitsStatus) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StreamHitsStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StreamHitsStatus) GetUniqueKey() string {
+	if x != nil {
+		return x.UniqueKey
+	}
+	return ""
+}
+
+func (x *StreamHitsStatus) GetHits() int64 {
+	if x != nil {
+		return x.Hits
+	}
+	return 0
+}
+
+func (x *StreamHitsStatus) GetRateLimit() *RateLimitResp {
+	if x != nil {
+		return x.RateLimit
+	}
+	return nil
+}
+
+type HealthCheckReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If true, the response includes the endpoints of every peer in the cluster, such that
+	// clients may load balance across every peer instead of sending every request to one
+	// address. See NewResolverBuilder()
+	IncludeEndpoints bool `protobuf:"varint,1,opt,name=include_endpoints,json=includeEndpoints,proto3" json:"include_endpoints,omitempty"`
+	// If true, the response includes the build version of every peer in the cluster, such that
+	// operators may verify a rollout completed and find peers which are still running an old version.
+	IncludeVersions bool `protobuf:"varint,2,opt,name=include_versions,json=includeVersions,proto3" json:"include_versions,omitempty"`
+}
+
+func (x *HealthCheckReq) Reset() {
+	*x = HealthCheckReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthCheckReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckReq) ProtoMessage() {}
+
+func (x *HealthCheckReq) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckReq.ProtoReflect.Descriptor instead.
+func (*HealthCheckReq) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *HealthCheckReq) GetIncludeEndpoints() bool {
+	if x != nil {
+		return x.IncludeEndpoints
+	}
+	return false
+}
+
+func (x *HealthCheckReq) GetIncludeVersions() bool {
+	if x != nil {
+		return x.IncludeVersions
+	}
+	return false
+}
+
+type HealthCheckResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Valid entries are 'healthy' or 'unhealthy'
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// If 'unhealthy', message indicates the problem
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The number of peers we know about
+	PeerCount int32 `protobuf:"varint,3,opt,name=peer_count,json=peerCount,proto3" json:"peer_count,omitempty"`
+	// The peers in the cluster, only set if `HealthCheckReq.include_endpoints` is true
+	Endpoints []*Endpoint `protobuf:"bytes,4,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	// The build version of each peer, only set if `HealthCheckReq.include_versions` is true
+	Versions []*PeerVersion `protobuf:"bytes,5,rep,name=versions,proto3" json:"versions,omitempty"`
+	// The version of the namespace policies in effect, empty unless `GUBER_POLICY_FILE` is set.
+	// Either the `version` of the policy file, or a digest of its content if it has no version.
+	PolicyVersion string `protobuf:"bytes,6,opt,name=policy_version,json=policyVersion,proto3" json:"policy_version,omitempty"`
+	// A checksum of the configuration of this instance which changes how rate limits are evaluated,
+	// IE: the behaviors, namespace policies and templates. Every peer should report the same checksum.
+	ConfigChecksum string `protobuf:"bytes,7,opt,name=config_checksum,json=configChecksum,proto3" json:"config_checksum,omitempty"`
+}
+
+func (x *HealthCheckResp) Reset() {
+	*x = HealthCheckResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HealthCheckResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResp) ProtoMessage() {}
+
+func (x *HealthCheckResp) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResp.ProtoReflect.Descriptor instead.
+func (*HealthCheckResp) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *HealthCheckResp) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResp) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HealthCheckResp) GetPeerCount() int32 {
+	if x != nil {
+		return x.PeerCount
+	}
+	return 0
+}
+
+func (x *HealthCheckResp) GetEndpoints() []*Endpoint {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *HealthCheckResp) GetVersions() []*PeerVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *HealthCheckResp) GetPolicyVersion() string {
+	if x != nil {
+		return x.PolicyVersion
+	}
+	return ""
+}
+
+func (x *HealthCheckResp) GetConfigChecksum() string {
+	if x != nil {
+		return x.ConfigChecksum
+	}
+	return ""
+}
+
+// PeerVersion is the build version of a peer
+type PeerVersion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GrpcAddress string `protobuf:"bytes,1,opt,name=grpc_address,json=grpcAddress,proto3" json:"grpc_address,omitempty"`
+	// The data center of the peer, empty if not using multi data center support
+	DataCenter string `protobuf:"bytes,2,opt,name=data_center,json=dataCenter,proto3" json:"data_center,omitempty"`
+	// The version the peer was built with, empty if the peer could not be reached
+	Version string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	// The VCS revision the peer was built from, empty if unknown
+	Commit string `protobuf:"bytes,4,opt,name=commit,proto3" json:"commit,omitempty"`
+	// Set if the peer could not be reached, or is running a version of gubernator which
+	// does not report its version
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// The checksum of the configuration of the peer, see HealthCheckResp.config_checksum. Empty if
+	// the peer predates the checksum
+	ConfigChecksum string `protobuf:"bytes,6,opt,name=config_checksum,json=configChecksum,proto3" json:"config_checksum,omitempty"`
+	// The stable identity of the peer, see DaemonConfig.NodeID. Empty if the peer has none
+	NodeId string `protobuf:"bytes,7,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+
+func (x *PeerVersion) Reset() {
+	*x = PeerVersion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerVersion) ProtoMessage() {}
+
+func (x *PeerVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerVersion.ProtoReflect.Descriptor instead.
+func (*PeerVersion) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PeerVersion) GetGrpcAddress() string {
+	if x != nil {
+		return x.GrpcAddress
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetDataCenter() string {
+	if x != nil {
+		return x.DataCenter
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetConfigChecksum() string {
+	if x != nil {
+		return x.ConfigChecksum
+	}
+	return ""
+}
+
+func (x *PeerVersion) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+// Endpoint is the address of a peer which clients may send requests to
+type Endpoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GrpcAddress string `protobuf:"bytes,1,opt,name=grpc_address,json=grpcAddress,proto3" json:"grpc_address,omitempty"`
+	HttpAddress string `protobuf:"bytes,2,opt,name=http_address,json=httpAddress,proto3" json:"http_address,omitempty"`
+	// The data center of the peer, empty if not using multi data center support
+	DataCenter string `protobuf:"bytes,3,opt,name=data_center,json=dataCenter,proto3" json:"data_center,omitempty"`
+	// The relative share of the key space owned by the peer, see PeerInfo.Weight
+	Weight int32 `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	// The fraction of the hash ring of its data center owned by the peer, between 0 and 1. Zero if
+	// the PeerPicker does not implement RingOwnershipPicker
+	Ownership float64 `protobuf:"fixed64,5,opt,name=ownership,proto3" json:"ownership,omitempty"`
+}
+
+func (x *Endpoint) Reset() {
+	*x = Endpoint{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gubernator_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Endpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Endpoint) ProtoMessage() {}
+
+func (x *Endpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_gubernator_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Endpoint.ProtoReflect.Descriptor instead.
+func (*Endpoint) Descriptor() ([]byte, []int) {
+	return file_gubernator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Endpoint) GetGrpcAddress() string {
+	if x != nil {
+		return x.GrpcAddress
+	}
+	return ""
+}
+
+func (x *Endpoint) GetHttpAddress() string {
+	if x != nil {
+		return x.HttpAddress
+	}
+	return ""
+}
+
+func (x *Endpoint) GetDataCenter() string {
+	if x != nil {
+		return x.DataCenter
+	}
+	return ""
+}
+
+func (x *Endpoint) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Endpoint) GetOwnership() float64 {
+	if x != nil {
+		return x.Ownership
+	}
+	return 0
+}
+
+var File_gubernator_proto protoreflect.FileDescriptor
+
+var file_gubernator_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x0d, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0xd1, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x52, 0x65, 0x71, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x61, 0x6c,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x69, 0x6c,
+	0x5f, 0x66, 0x61, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x61, 0x69,
+	0x6c, 0x46, 0x61, 0x73, 0x74, 0x12, 0x3c, 0x0a, 0x08, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x52, 0x08, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x11, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a,
+	0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f,
+	0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x33, 0x0a, 0x08, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72,
+	0x52, 0x08, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72, 0x22, 0x86, 0x01, 0x0a, 0x11, 0x47,
+	0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x3a, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x07,
+	0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d,
+	0x61, 0x72, 0x79, 0x22, 0x91, 0x01, 0x0a, 0x0c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x75, 0x6e, 0x64, 0x65, 0x72,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x29, 0x0a, 0x10,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x22, 0x4f, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x12,
+	0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x52, 0x08,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x82, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x3a, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22, 0x63, 0x0a,
+	0x13, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x12, 0x3a, 0x0a, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x52, 0x65, 0x71, 0x52, 0x09, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x74,
+	0x74, 0x6c, 0x22, 0x97, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x3b, 0x0a, 0x0a, 0x72,
+	0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x09, 0x72,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12,
+	0x1b, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x22, 0x6a, 0x0a, 0x0e,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4b, 0x65,
+	0x79, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x25, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68,
+	0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x22,
+	0x6a, 0x0a, 0x15, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x83, 0x01, 0x0a, 0x16,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3a, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x73, 0x22, 0xb2, 0x01, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79, 0x52, 0x65, 0x71, 0x12, 0x33,
+	0x0a, 0x06, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x52, 0x06, 0x6c, 0x65, 0x76,
+	0x65, 0x6c, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x12, 0x33, 0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79,
+	0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0x86, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x3a, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22,
+	0x5b, 0x0a, 0x09, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x1b, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x49, 0x64, 0x22, 0x20, 0x0a, 0x0a,
+	0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x22, 0x48,
+	0x0a, 0x08, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x22, 0x5c, 0x0a, 0x09, 0x4c, 0x65, 0x61, 0x73,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x22, 0xac, 0x04, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x75,
+	0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x69,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x68, 0x69, 0x74, 0x73, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x36, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x09, 0x61,
+	0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x33, 0x0a, 0x08, 0x62, 0x65, 0x68, 0x61,
+	0x76, 0x69, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x42, 0x65, 0x68, 0x61, 0x76,
+	0x69, 0x6f, 0x72, 0x52, 0x08, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x75,
+	0x72, 0x73, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52,
+	0x65, 0x71, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x22, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1d,
+	0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x12, 0x27, 0x0a, 0x0f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70,
+	0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x22, 0x8b, 0x03, 0x0a, 0x0d, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65,
+	0x73, 0x65, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x72, 0x65, 0x73, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x46, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x06, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x2e,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f,
+	0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x71, 0x75, 0x65,
+	0x75, 0x65, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x72, 0x61, 0x69, 0x6e,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x64, 0x72, 0x61,
+	0x69, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xa1, 0x03, 0x0a, 0x0f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x65, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a,
+	0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f,
+	0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x33, 0x0a, 0x08, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72,
+	0x52, 0x08, 0x62, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x75,
+	0x72, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x5f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x72, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x75, 0x74, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x15, 0x72,
+	0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x72, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x75, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12,
+	0x23, 0x0a, 0x0d, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x53,
+	0x74, 0x61, 0x72, 0x74, 0x12, 0x41, 0x0a, 0x0c, 0x72, 0x6f, 0x6c, 0x6c, 0x6f, 0x75, 0x74, 0x5f,
+	0x66, 0x72, 0x6f, 0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x65, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x0b, 0x72, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x75, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x22, 0x4b, 0x0a, 0x11, 0x52, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x36, 0x0a, 0x06,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67,
+	0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x06, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x22, 0x2c, 0x0a, 0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x22, 0x64, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52,
+	0x65, 0x71, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0x4d, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x3b, 0x0a, 0x08, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x08, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x22, 0x96, 0x01, 0x0a, 0x10, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x6e, 0x69, 0x71, 0x75, 0x65, 0x4b, 0x65, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x68, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x68,
+	0x69, 0x74, 0x73, 0x12, 0x3b, 0x0a, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x09, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x22, 0x68, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52,
+	0x65, 0x71, 0x12, 0x2b, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x65, 0x6e,
+	0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12,
+	0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa1, 0x02, 0x0a, 0x0f, 0x48,
+	0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x70, 0x65, 0x65, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x35, 0x0a, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x65, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25,
+	0x0a, 0x0e, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x22, 0xdb,
+	0x01, 0x0a, 0x0b, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21,
+	0x0a, 0x0c, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x67, 0x72, 0x70, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x43, 0x65, 0x6e, 0x74,
+	0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x73, 0x75, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0xa7, 0x01, 0x0a,
+	0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x67, 0x72, 0x70,
+	0x63, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x67, 0x72, 0x70, 0x63, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x21, 0x0a, 0x0c,
+	0x68, 0x74, 0x74, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x68, 0x74, 0x74, 0x70, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x64, 0x61, 0x74, 0x61, 0x43, 0x65, 0x6e, 0x74, 0x65, 0x72,
+	0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x73, 0x68, 0x69, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x2a, 0x33, 0x0a, 0x0e, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72,
+	0x63, 0x68, 0x79, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x0c, 0x50, 0x41, 0x52, 0x45,
+	0x4e, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x48,
+	0x49, 0x4c, 0x44, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x01, 0x2a, 0x2f, 0x0a, 0x09, 0x41,
+	0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x10, 0x0a, 0x0c, 0x54, 0x4f, 0x4b, 0x45,
+	0x4e, 0x5f, 0x42, 0x55, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4c, 0x45,
+	0x41, 0x4b, 0x59, 0x5f, 0x42, 0x55, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x01, 0x2a, 0xf1, 0x01, 0x0a,
+	0x08, 0x42, 0x65, 0x68, 0x61, 0x76, 0x69, 0x6f, 0x72, 0x12, 0x0c, 0x0a, 0x08, 0x42, 0x41, 0x54,
+	0x43, 0x48, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x4e, 0x4f, 0x5f, 0x42, 0x41,
+	0x54, 0x43, 0x48, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x4c, 0x4f, 0x42,
+	0x41, 0x4c, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x44, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e,
+	0x5f, 0x49, 0x53, 0x5f, 0x47, 0x52, 0x45, 0x47, 0x4f, 0x52, 0x49, 0x41, 0x4e, 0x10, 0x04, 0x12,
+	0x13, 0x0a, 0x0f, 0x52, 0x45, 0x53, 0x45, 0x54, 0x5f, 0x52, 0x45, 0x4d, 0x41, 0x49, 0x4e, 0x49,
+	0x4e, 0x47, 0x10, 0x08, 0x12, 0x10, 0x0a, 0x0c, 0x4d, 0x55, 0x4c, 0x54, 0x49, 0x5f, 0x52, 0x45,
+	0x47, 0x49, 0x4f, 0x4e, 0x10, 0x10, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x5f,
+	0x4f, 0x56, 0x45, 0x52, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x20, 0x12, 0x0b, 0x0a, 0x07,
+	0x44, 0x52, 0x59, 0x5f, 0x52, 0x55, 0x4e, 0x10, 0x40, 0x12, 0x12, 0x0a, 0x0d, 0x47, 0x52, 0x45,
+	0x45, 0x44, 0x59, 0x5f, 0x52, 0x45, 0x46, 0x49, 0x4c, 0x4c, 0x10, 0x80, 0x01, 0x12, 0x0f, 0x0a,
+	0x0a, 0x52, 0x45, 0x46, 0x55, 0x4e, 0x44, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x80, 0x02, 0x12, 0x10,
+	0x0a, 0x0b, 0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x10, 0x80, 0x04,
+	0x12, 0x0f, 0x0a, 0x0a, 0x46, 0x4f, 0x52, 0x43, 0x45, 0x5f, 0x50, 0x45, 0x45, 0x52, 0x10, 0x80,
+	0x08, 0x12, 0x0d, 0x0a, 0x08, 0x52, 0x4f, 0x4c, 0x4c, 0x4f, 0x56, 0x45, 0x52, 0x10, 0x80, 0x10,
+	0x2a, 0x36, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e,
+	0x44, 0x45, 0x52, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x4f,
+	0x56, 0x45, 0x52, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x02, 0x32, 0xd3, 0x0b, 0x0a, 0x02, 0x56, 0x31, 0x12,
+	0x70, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x12, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x1a, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x22, 0x1c, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x3a, 0x01, 0x2a, 0x22, 0x11,
+	0x2f, 0x76, 0x31, 0x2f, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x12, 0x80, 0x01, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x23, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a, 0x3a, 0x01, 0x2a, 0x22, 0x15, 0x2f,
+	0x76, 0x31, 0x2f, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x12, 0x7c, 0x0a, 0x10, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a, 0x22, 0x14, 0x2f, 0x76,
+	0x31, 0x2f, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x74, 0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a, 0x3a, 0x01,
+	0x2a, 0x22, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x74, 0x0a, 0x11, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x22, 0x20, 0x82, 0xd3,
+	0xe4, 0x93, 0x02, 0x1a, 0x3a, 0x01, 0x2a, 0x22, 0x15, 0x2f, 0x76, 0x31, 0x2f, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x84,
+	0x01, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x24, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x25, 0x2e, 0x70, 0x62,
+	0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x3a, 0x01, 0x2a, 0x22, 0x16, 0x2f,
+	0x76, 0x31, 0x2f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x90, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79, 0x12,
+	0x27, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x69, 0x65, 0x72,
+	0x61, 0x72, 0x63, 0x68, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x28, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x24, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x3a, 0x01, 0x2a, 0x22, 0x19, 0x2f,
+	0x76, 0x31, 0x2f, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x48,
+	0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x79, 0x12, 0x66, 0x0a, 0x0f, 0x52, 0x65, 0x66, 0x75,
+	0x6e, 0x64, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x62,
+	0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x66, 0x75,
+	0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x18, 0x3a, 0x01, 0x2a, 0x22, 0x13, 0x2f, 0x76, 0x31,
+	0x2f, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x12, 0x5e, 0x0a, 0x0c, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x3a, 0x01, 0x2a, 0x22, 0x10,
+	0x2f, 0x76, 0x31, 0x2f, 0x41, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x5e, 0x0a, 0x0c, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x3a, 0x01, 0x2a, 0x22, 0x10,
+	0x2f, 0x76, 0x31, 0x2f, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x74, 0x0a, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x3a,
+	0x01, 0x2a, 0x22, 0x12, 0x2f, 0x76, 0x31, 0x2f, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72,
+	0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x4f, 0x0a, 0x0a, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x48, 0x69, 0x74, 0x73, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x48, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x65, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x22, 0x17, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12, 0x0f, 0x2f,
+	0x76, 0x31, 0x2f, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x31,
+	0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2d, 0x69, 0x6f, 0x2f, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x80, 0x01,
+	0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_gubernator_proto_rawDescOnce sync.Once
+	file_gubernator_proto_rawDescData = file_gubernator_proto_rawDesc
+)
+
+func file_gubernator_proto_rawDescGZIP() []byte {
+	file_gubernator_proto_rawDescOnce.Do(func() {
+		file_gubernator_proto_rawDescData = protoimpl.X.CompressGZIP(file_gubernator_proto_rawDescData)
+	})
+	return file_gubernator_proto_rawDescData
+}
+
+var file_gubernator_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_gubernator_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_gubernator_proto_goTypes = []interface{}{
+	(HierarchyOrder)(0),               // 0: pb.gubernator.HierarchyOrder
+	(Algorithm)(0),                    // 1: pb.gubernator.Algorithm
+	(Behavior)(0),                     // 2: pb.gubernator.Behavior
+	(Status)(0),                       // 3: pb.gubernator.Status
+	(*GetRateLimitsReq)(nil),          // 4: pb.gubernator.GetRateLimitsReq
+	(*RateLimitDefaults)(nil),         // 5: pb.gubernator.RateLimitDefaults
+	(*GetRateLimitsResp)(nil),         // 6: pb.gubernator.GetRateLimitsResp
+	(*BatchSummary)(nil),              // 7: pb.gubernator.BatchSummary
+	(*GetRateLimitGroupReq)(nil),      // 8: pb.gubernator.GetRateLimitGroupReq
+	(*GetRateLimitGroupResp)(nil),     // 9: pb.gubernator.GetRateLimitGroupResp
+	(*ReserveRateLimitReq)(nil),       // 10: pb.gubernator.ReserveRateLimitReq
+	(*ReserveRateLimitResp)(nil),      // 11: pb.gubernator.ReserveRateLimitResp
+	(*ReservationReq)(nil),            // 12: pb.gubernator.ReservationReq
+	(*ReservationResp)(nil),           // 13: pb.gubernator.ReservationResp
+	(*TransactRateLimitsReq)(nil),     // 14: pb.gubernator.TransactRateLimitsReq
+	(*TransactRateLimitsResp)(nil),    // 15: pb.gubernator.TransactRateLimitsResp
+	(*GetRateLimitHierarchyReq)(nil),  // 16: pb.gubernator.GetRateLimitHierarchyReq
+	(*GetRateLimitHierarchyResp)(nil), // 17: pb.gubernator.GetRateLimitHierarchyResp
+	(*RefundReq)(nil),                 // 18: pb.gubernator.RefundReq
+	(*RefundResp)(nil),                // 19: pb.gubernator.RefundResp
+	(*LeaseReq)(nil),                  // 20: pb.gubernator.LeaseReq
+	(*LeaseResp)(nil),                 // 21: pb.gubernator.LeaseResp
+	(*RateLimitReq)(nil),              // 22: pb.gubernator.RateLimitReq
+	(*RateLimitResp)(nil),             // 23: pb.gubernator.RateLimitResp
+	(*RegisteredLimit)(nil),           // 24: pb.gubernator.RegisteredLimit
+	(*RegisterLimitsReq)(nil),         // 25: pb.gubernator.RegisterLimitsReq
+	(*RegisterLimitsResp)(nil),        // 26: pb.gubernator.RegisterLimitsResp
+	(*StreamHitsReq)(nil),             // 27: pb.gubernator.StreamHitsReq
+	(*StreamHitsResp)(nil),            // 28: pb.gubernator.StreamHitsResp
+	(*StreamHitsStatus)(nil),          // 29: pb.gubernator.StreamHitsStatus
+	(*HealthCheckReq)(nil),            // 30: pb.gubernator.HealthCheckReq
+	(*HealthCheckResp)(nil),           // 31: pb.gubernator.HealthCheckResp
+	(*PeerVersion)(nil),               // 32: pb.gubernator.PeerVersion
+	(*Endpoint)(nil),                  // 33: pb.gubernator.Endpoint
+	nil,                               // 34: pb.gubernator.RateLimitReq.MetadataEntry
+	nil,                               // 35: pb.gubernator.RateLimitResp.MetadataEntry
+}
+var file_gubernator_proto_depIdxs = []int32{
+	22, // 0: pb.gubernator.GetRateLimitsReq.requests:type_name -> pb.gubernator.RateLimitReq
+	5,  // 1: pb.gubernator.GetRateLimitsReq.defaults:type_name -> pb.gubernator.RateLimitDefaults
+	1,  // 2: pb.gubernator.RateLimitDefaults.algorithm:type_name -> pb.gubernator.Algorithm
+	2,  // 3: pb.gubernator.RateLimitDefaults.behavior:type_name -> pb.gubernator.Behavior
+	23, // 4: pb.gubernator.GetRateLimitsResp.responses:type_name -> pb.gubernator.RateLimitResp
+	7,  // 5: pb.gubernator.GetRateLimitsResp.summary:type_name -> pb.gubernator.BatchSummary
+	22, // 6: pb.gubernator.GetRateLimitGroupReq.requests:type_name -> pb.gubernator.RateLimitReq
+	3,  // 7: pb.gubernator.GetRateLimitGroupResp.status:type_name -> pb.gubernator.Status
+	23, // 8: pb.gubernator.GetRateLimitGroupResp.responses:type_name -> pb.gubernator.RateLimitResp
+	22, // 9: pb.gubernator.ReserveRateLimitReq.rate_limit:type_name -> pb.gubernator.RateLimitReq
+	23, // 10: pb.gubernator.ReserveRateLimitResp.rate_limit:type_name -> pb.gubernator.RateLimitResp
+	22, // 11: pb.gubernator.TransactRateLimitsReq.requests:type_name -> pb.gubernator.RateLimitReq
+	3,  // 12: pb.gubernator.TransactRateLimitsResp.status:type_name -> pb.gubernator.Status
+	23, // 13: pb.gubernator.TransactRateLimitsResp.responses:type_name -> pb.gubernator.RateLimitResp
+	22, // 14: pb.gubernator.GetRateLimitHierarchyReq.levels:type_name -> pb.gubernator.RateLimitReq
+	0,  // 15: pb.gubernator.GetRateLimitHierarchyReq.order:type_name -> pb.gubernator.HierarchyOrder
+	3,  // 16: pb.gubernator.GetRateLimitHierarchyResp.status:type_name -> pb.gubernator.Status
+	23, // 17: pb.gubernator.GetRateLimitHierarchyResp.responses:type_name -> pb.gubernator.RateLimitResp
+	1,  // 18: pb.gubernator.RateLimitReq.algorithm:type_name -> pb.gubernator.Algorithm
+	2,  // 19: pb.gubernator.RateLimitReq.behavior:type_name -> pb.gubernator.Behavior
+	34, // 20: pb.gubernator.RateLimitReq.metadata:type_name -> pb.gubernator.RateLimitReq.MetadataEntry
+	3,  // 21: pb.gubernator.RateLimitResp.status:type_name -> pb.gubernator.Status
+	35, // 22: pb.gubernator.RateLimitResp.metadata:type_name -> pb.gubernator.RateLimitResp.MetadataEntry
+	1,  // 23: pb.gubernator.RegisteredLimit.algorithm:type_name -> pb.gubernator.Algorithm
+	2,  // 24: pb.gubernator.RegisteredLimit.behavior:type_name -> pb.gubernator.Behavior
+	24, // 25: pb.gubernator.RegisteredLimit.rollout_from:type_name -> pb.gubernator.RegisteredLimit
+	24, // 26: pb.gubernator.RegisterLimitsReq.limits:type_name -> pb.gubernator.RegisteredLimit
+	22, // 27: pb.gubernator.StreamHitsReq.requests:type_name -> pb.gubernator.RateLimitReq
+	29, // 28: pb.gubernator.StreamHitsResp.statuses:type_name -> pb.gubernator.StreamHitsStatus
+	23, // 29: pb.gubernator.StreamHitsStatus.rate_limit:type_name -> pb.gubernator.RateLimitResp
+	33, // 30: pb.gubernator.HealthCheckResp.endpoints:type_name -> pb.gubernator.Endpoint
+	32, // 31: pb.gubernator.HealthCheckResp.versions:type_name -> pb.gubernator.PeerVersion
+	4,  // 32: pb.gubernator.V1.GetRateLimits:input_type -> pb.gubernator.GetRateLimitsReq
+	8,  // 33: pb.gubernator.V1.GetRateLimitGroup:input_type -> pb.gubernator.GetRateLimitGroupReq
+	10, // 34: pb.gubernator.V1.ReserveRateLimit:input_type -> pb.gubernator.ReserveRateLimitReq
+	12, // 35: pb.gubernator.V1.CommitReservation:input_type -> pb.gubernator.ReservationReq
+	12, // 36: pb.gubernator.V1.CancelReservation:input_type -> pb.gubernator.ReservationReq
+	14, // 37: pb.gubernator.V1.TransactRateLimits:input_type -> pb.gubernator.TransactRateLimitsReq
+	16, // 38: pb.gubernator.V1.GetRateLimitHierarchy:input_type -> pb.gubernator.GetRateLimitHierarchyReq
+	18, // 39: pb.gubernator.V1.RefundRateLimit:input_type -> pb.gubernator.RefundReq
+	20, // 40: pb.gubernator.V1.AcquireLease:input_type -> pb.gubernator.LeaseReq
+	20, // 41: pb.gubernator.V1.ReleaseLease:input_type -> pb.gubernator.LeaseReq
+	25, // 42: pb.gubernator.V1.RegisterLimits:input_type -> pb.gubernator.RegisterLimitsReq
+	27, // 43: pb.gubernator.V1.StreamHits:input_type -> pb.gubernator.StreamHitsReq
+	30, // 44: pb.gubernator.V1.HealthCheck:input_type -> pb.gubernator.HealthCheckReq
+	6,  // 45: pb.gubernator.V1.GetRateLimits:output_type -> pb.gubernator.GetRateLimitsResp
+	9,  // 46: pb.gubernator.V1.GetRateLimitGroup:output_type -> pb.gubernator.GetRateLimitGroupResp
+	11, // 47: pb.gubernator.V1.ReserveRateLimit:output_type -> pb.gubernator.ReserveRateLimitResp
+	13, // 48: pb.gubernator.V1.CommitReservation:output_type -> pb.gubernator.ReservationResp
+	13, // 49: pb.gubernator.V1.CancelReservation:output_type -> pb.gubernator.ReservationResp
+	15, // 50: pb.gubernator.V1.TransactRateLimits:output_type -> pb.gubernator.TransactRateLimitsResp
+	17, // 51: pb.gubernator.V1.GetRateLimitHierarchy:output_type -> pb.gubernator.GetRateLimitHierarchyResp
+	19, // 52: pb.gubernator.V1.RefundRateLimit:output_type -> pb.gubernator.RefundResp
+	21, // 53: pb.gubernator.V1.AcquireLease:output_type -> pb.gubernator.LeaseResp
+	21, // 54: pb.gubernator.V1.ReleaseLease:output_type -> pb.gubernator.LeaseResp
+	26, // 55: pb.gubernator.V1.RegisterLimits:output_type -> pb.gubernator.RegisterLimitsResp
+	28, // 56: pb.gubernator.V1.StreamHits:output_type -> pb.gubernator.StreamHitsResp
+	31, // 57: pb.gubernator.V1.HealthCheck:output_type -> pb.gubernator.HealthCheckResp
+	45, // [45:58] is the sub-list for method output_type
+	32, // [32:45] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
+}
+
+func init() { file_gubernator_proto_init() }
+func file_gubernator_proto_init() {
+	if File_gubernator_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_gubernator_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RateLimitDefaults); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitGroupReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitGroupResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveRateLimitReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveRateLimitResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReservationReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReservationResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransactRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitHierarchyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRateLimitHierarchyResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RefundReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RefundResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeaseResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RateLimitReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RateLimitResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisteredLimit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamHitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamHitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamHitsStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthCheckReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HealthCheckResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerVersion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gubernator_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Endpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_gubernator_proto_msgTypes[18].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_gubernator_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   32,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gubernator_proto_goTypes,
+		DependencyIndexes: file_gubernator_proto_depIdxs,
+		EnumInfos:         file_gubernator_proto_enumTypes,
+		MessageInfos:      file_gubernator_proto_msgTypes,
+	}.Build()
+	File_gubernator_proto = out.File
+	file_gubernator_proto_rawDesc = nil
+	file_gubernator_proto_goTypes = nil
+	file_gubernator_proto_depIdxs = nil
+}
@@ -0,0 +1,663 @@
+//
+//Copyright 2018-2022 Mailgun Technologies Inc
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: gubernator.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	V1_GetRateLimits_FullMethodName         = "/pb.gubernator.V1/GetRateLimits"
+	V1_GetRateLimitGroup_FullMethodName     = "/pb.gubernator.V1/GetRateLimitGroup"
+	V1_ReserveRateLimit_FullMethodName      = "/pb.gubernator.V1/ReserveRateLimit"
+	V1_CommitReservation_FullMethodName     = "/pb.gubernator.V1/CommitReservation"
+	V1_CancelReservation_FullMethodName     = "/pb.gubernator.V1/CancelReservation"
+	V1_TransactRateLimits_FullMethodName    = "/pb.gubernator.V1/TransactRateLimits"
+	V1_GetRateLimitHierarchy_FullMethodName = "/pb.gubernator.V1/GetRateLimitHierarchy"
+	V1_RefundRateLimit_FullMethodName       = "/pb.gubernator.V1/RefundRateLimit"
+	V1_AcquireLease_FullMethodName          = "/pb.gubernator.V1/AcquireLease"
+	V1_ReleaseLease_FullMethodName          = "/pb.gubernator.V1/ReleaseLease"
+	V1_RegisterLimits_FullMethodName        = "/pb.gubernator.V1/RegisterLimits"
+	V1_StreamHits_FullMethodName            = "/pb.gubernator.V1/StreamHits"
+	V1_HealthCheck_FullMethodName           = "/pb.gubernator.V1/HealthCheck"
+)
+
+// V1Client is the client API for V1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type V1Client interface {
+	// Given a list of rate limit requests, return the rate limits of each.
+	GetRateLimits(ctx context.Context, in *GetRateLimitsReq, opts ...grpc.CallOption) (*GetRateLimitsResp, error)
+	// Given a group of rate limit requests, apply the hits to every rate limit in the
+	// group only if all of them are under the limit. If any rate limit in the group
+	// would be over the limit, no hits are applied to any of them.
+	GetRateLimitGroup(ctx context.Context, in *GetRateLimitGroupReq, opts ...grpc.CallOption) (*GetRateLimitGroupResp, error)
+	// Reserve `hits` against a rate limit. The hits are held by the reservation until it is
+	// committed or canceled. A reservation which is neither committed nor canceled before the
+	// `ttl` elapses is canceled, and the hits it held are returned to the rate limit.
+	ReserveRateLimit(ctx context.Context, in *ReserveRateLimitReq, opts ...grpc.CallOption) (*ReserveRateLimitResp, error)
+	// Commit the hits held by a reservation, the hits are no longer returned to the rate limit.
+	CommitReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error)
+	// Cancel a reservation and return the hits it held to the rate limit.
+	CancelReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error)
+	// Apply the hits of every rate limit in the transaction, or none of them, even when the rate
+	// limits are owned by different peers. The peer which receives the request reserves the hits
+	// of every rate limit, then commits the reservations only if every rate limit was under the
+	// limit, otherwise it cancels them. Hits which are not committed before `timeout` elapses are
+	// returned to their rate limits by the owning peers.
+	TransactRateLimits(ctx context.Context, in *TransactRateLimitsReq, opts ...grpc.CallOption) (*TransactRateLimitsResp, error)
+	// Consume hits from a rate limit and every one of its parents, IE: a key, its project and its
+	// organization, such that the cap of a parent is shared by all of its children. The hits are
+	// reserved on each level in the enforcement order, then committed only if every level was under
+	// the limit, even when the levels are owned by different peers.
+	GetRateLimitHierarchy(ctx context.Context, in *GetRateLimitHierarchyReq, opts ...grpc.CallOption) (*GetRateLimitHierarchyResp, error)
+	// Return the hits applied by a request with the REFUNDABLE behavior to the rate limit, IE: when
+	// the operation the hits were consumed for has failed and the customer should not be charged.
+	RefundRateLimit(ctx context.Context, in *RefundReq, opts ...grpc.CallOption) (*RefundResp, error)
+	// Acquire a named lease on the peer which owns the lease name. Only one holder may hold the
+	// lease until it expires or is released. Acquiring a lease already held by the same holder
+	// renews the lease. Useful for "single execution" semantics across a fleet, IE: cron jobs.
+	AcquireLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error)
+	// Release a lease held by the holder, such that other holders may acquire it.
+	ReleaseLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error)
+	// Registers named limit definitions on every peer in the cluster, such that subsequent requests
+	// reference a definition by name and provide only the unique key and hits.
+	RegisterLimits(ctx context.Context, in *RegisterLimitsReq, opts ...grpc.CallOption) (*RegisterLimitsResp, error)
+	// Accepts a stream of hits to a few rate limits, IE: from a metering agent which emits thousands
+	// of hits per second for one key, instead of a unary call for each hit. The hits received for
+	// each rate limit are summed and applied once per interval, after which the consolidated status
+	// of each rate limit which received hits is streamed back.
+	StreamHits(ctx context.Context, opts ...grpc.CallOption) (V1_StreamHitsClient, error)
+	// This method is for round trip benchmarking and can be used by
+	// the client to determine connectivity to the server
+	HealthCheck(ctx context.Context, in *HealthCheckReq, opts ...grpc.CallOption) (*HealthCheckResp, error)
+}
+
+type v1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewV1Client(cc grpc.ClientConnInterface) V1Client {
+	return &v1Client{cc}
+}
+
+func (c *v1Client) GetRateLimits(ctx context.Context, in *GetRateLimitsReq, opts ...grpc.CallOption) (*GetRateLimitsResp, error) {
+	out := new(GetRateLimitsResp)
+	err := c.cc.Invoke(ctx, V1_GetRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) GetRateLimitGroup(ctx context.Context, in *GetRateLimitGroupReq, opts ...grpc.CallOption) (*GetRateLimitGroupResp, error) {
+	out := new(GetRateLimitGroupResp)
+	err := c.cc.Invoke(ctx, V1_GetRateLimitGroup_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) ReserveRateLimit(ctx context.Context, in *ReserveRateLimitReq, opts ...grpc.CallOption) (*ReserveRateLimitResp, error) {
+	out := new(ReserveRateLimitResp)
+	err := c.cc.Invoke(ctx, V1_ReserveRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) CommitReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error) {
+	out := new(ReservationResp)
+	err := c.cc.Invoke(ctx, V1_CommitReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) CancelReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error) {
+	out := new(ReservationResp)
+	err := c.cc.Invoke(ctx, V1_CancelReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) TransactRateLimits(ctx context.Context, in *TransactRateLimitsReq, opts ...grpc.CallOption) (*TransactRateLimitsResp, error) {
+	out := new(TransactRateLimitsResp)
+	err := c.cc.Invoke(ctx, V1_TransactRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) GetRateLimitHierarchy(ctx context.Context, in *GetRateLimitHierarchyReq, opts ...grpc.CallOption) (*GetRateLimitHierarchyResp, error) {
+	out := new(GetRateLimitHierarchyResp)
+	err := c.cc.Invoke(ctx, V1_GetRateLimitHierarchy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) RefundRateLimit(ctx context.Context, in *RefundReq, opts ...grpc.CallOption) (*RefundResp, error) {
+	out := new(RefundResp)
+	err := c.cc.Invoke(ctx, V1_RefundRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) AcquireLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error) {
+	out := new(LeaseResp)
+	err := c.cc.Invoke(ctx, V1_AcquireLease_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) ReleaseLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error) {
+	out := new(LeaseResp)
+	err := c.cc.Invoke(ctx, V1_ReleaseLease_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) RegisterLimits(ctx context.Context, in *RegisterLimitsReq, opts ...grpc.CallOption) (*RegisterLimitsResp, error) {
+	out := new(RegisterLimitsResp)
+	err := c.cc.Invoke(ctx, V1_RegisterLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *v1Client) StreamHits(ctx context.Context, opts ...grpc.CallOption) (V1_StreamHitsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &V1_ServiceDesc.Streams[0], V1_StreamHits_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &v1StreamHitsClient{stream}
+	return x, nil
+}
+
+type V1_StreamHitsClient interface {
+	Send(*StreamHitsReq) error
+	Recv() (*StreamHitsResp, error)
+	grpc.ClientStream
+}
+
+type v1StreamHitsClient struct {
+	grpc.ClientStream
+}
+
+func (x *v1StreamHitsClient) Send(m *StreamHitsReq) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *v1StreamHitsClient) Recv() (*StreamHitsResp, error) {
+	m := new(StreamHitsResp)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *v1Client) HealthCheck(ctx context.Context, in *HealthCheckReq, opts ...grpc.CallOption) (*HealthCheckResp, error) {
+	out := new(HealthCheckResp)
+	err := c.cc.Invoke(ctx, V1_HealthCheck_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// V1Server is the server API for V1 service.
+// All implementations should embed UnimplementedV1Server
+// for forward compatibility
+type V1Server interface {
+	// Given a list of rate limit requests, return the rate limits of each.
+	GetRateLimits(context.Context, *GetRateLimitsReq) (*GetRateLimitsResp, error)
+	// Given a group of rate limit requests, apply the hits to every rate limit in the
+	// group only if all of them are under the limit. If any rate limit in the group
+	// would be over the limit, no hits are applied to any of them.
+	GetRateLimitGroup(context.Context, *GetRateLimitGroupReq) (*GetRateLimitGroupResp, error)
+	// Reserve `hits` against a rate limit. The hits are held by the reservation until it is
+	// committed or canceled. A reservation which is neither committed nor canceled before the
+	// `ttl` elapses is canceled, and the hits it held are returned to the rate limit.
+	ReserveRateLimit(context.Context, *ReserveRateLimitReq) (*ReserveRateLimitResp, error)
+	// Commit the hits held by a reservation, the hits are no longer returned to the rate limit.
+	CommitReservation(context.Context, *ReservationReq) (*ReservationResp, error)
+	// Cancel a reservation and return the hits it held to the rate limit.
+	CancelReservation(context.Context, *ReservationReq) (*ReservationResp, error)
+	// Apply the hits of every rate limit in the transaction, or none of them, even when the rate
+	// limits are owned by different peers. The peer which receives the request reserves the hits
+	// of every rate limit, then commits the reservations only if every rate limit was under the
+	// limit, otherwise it cancels them. Hits which are not committed before `timeout` elapses are
+	// returned to their rate limits by the owning peers.
+	TransactRateLimits(context.Context, *TransactRateLimitsReq) (*TransactRateLimitsResp, error)
+	// Consume hits from a rate limit and every one of its parents, IE: a key, its project and its
+	// organization, such that the cap of a parent is shared by all of its children. The hits are
+	// reserved on each level in the enforcement order, then committed only if every level was under
+	// the limit, even when the levels are owned by different peers.
+	GetRateLimitHierarchy(context.Context, *GetRateLimitHierarchyReq) (*GetRateLimitHierarchyResp, error)
+	// Return the hits applied by a request with the REFUNDABLE behavior to the rate limit, IE: when
+	// the operation the hits were consumed for has failed and the customer should not be charged.
+	RefundRateLimit(context.Context, *RefundReq) (*RefundResp, error)
+	// Acquire a named lease on the peer which owns the lease name. Only one holder may hold the
+	// lease until it expires or is released. Acquiring a lease already held by the same holder
+	// renews the lease. Useful for "single execution" semantics across a fleet, IE: cron jobs.
+	AcquireLease(context.Context, *LeaseReq) (*LeaseResp, error)
+	// Release a lease held by the holder, such that other holders may acquire it.
+	ReleaseLease(context.Context, *LeaseReq) (*LeaseResp, error)
+	// Registers named limit definitions on every peer in the cluster, such that subsequent requests
+	// reference a definition by name and provide only the unique key and hits.
+	RegisterLimits(context.Context, *RegisterLimitsReq) (*RegisterLimitsResp, error)
+	// Accepts a stream of hits to a few rate limits, IE: from a metering agent which emits thousands
+	// of hits per second for one key, instead of a unary call for each hit. The hits received for
+	// each rate limit are summed and applied once per interval, after which the consolidated status
+	// of each rate limit which received hits is streamed back.
+	StreamHits(V1_StreamHitsServer) error
+	// This method is for round trip benchmarking and can be used by
+	// the client to determine connectivity to the server
+	HealthCheck(context.Context, *HealthCheckReq) (*HealthCheckResp, error)
+}
+
+// UnimplementedV1Server should be embedded to have forward compatible implementations.
+type UnimplementedV1Server struct {
+}
+
+func (UnimplementedV1Server) GetRateLimits(context.Context, *GetRateLimitsReq) (*GetRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimits not implemented")
+}
+func (UnimplementedV1Server) GetRateLimitGroup(context.Context, *GetRateLimitGroupReq) (*GetRateLimitGroupResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimitGroup not implemented")
+}
+func (UnimplementedV1Server) ReserveRateLimit(context.Context, *ReserveRateLimitReq) (*ReserveRateLimitResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveRateLimit not implemented")
+}
+func (UnimplementedV1Server) CommitReservation(context.Context, *ReservationReq) (*ReservationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedV1Server) CancelReservation(context.Context, *ReservationReq) (*ReservationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelReservation not implemented")
+}
+func (UnimplementedV1Server) TransactRateLimits(context.Context, *TransactRateLimitsReq) (*TransactRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransactRateLimits not implemented")
+}
+func (UnimplementedV1Server) GetRateLimitHierarchy(context.Context, *GetRateLimitHierarchyReq) (*GetRateLimitHierarchyResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateLimitHierarchy not implemented")
+}
+func (UnimplementedV1Server) RefundRateLimit(context.Context, *RefundReq) (*RefundResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundRateLimit not implemented")
+}
+func (UnimplementedV1Server) AcquireLease(context.Context, *LeaseReq) (*LeaseResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquireLease not implemented")
+}
+func (UnimplementedV1Server) ReleaseLease(context.Context, *LeaseReq) (*LeaseResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLease not implemented")
+}
+func (UnimplementedV1Server) RegisterLimits(context.Context, *RegisterLimitsReq) (*RegisterLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterLimits not implemented")
+}
+func (UnimplementedV1Server) StreamHits(V1_StreamHitsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamHits not implemented")
+}
+func (UnimplementedV1Server) HealthCheck(context.Context, *HealthCheckReq) (*HealthCheckResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
+}
+
+// UnsafeV1Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to V1Server will
+// result in compilation errors.
+type UnsafeV1Server interface {
+	mustEmbedUnimplementedV1Server()
+}
+
+func RegisterV1Server(s grpc.ServiceRegistrar, srv V1Server) {
+	s.RegisterService(&V1_ServiceDesc, srv)
+}
+
+func _V1_GetRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).GetRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_GetRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).GetRateLimits(ctx, req.(*GetRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_GetRateLimitGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitGroupReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).GetRateLimitGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_GetRateLimitGroup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).GetRateLimitGroup(ctx, req.(*GetRateLimitGroupReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_ReserveRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveRateLimitReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).ReserveRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_ReserveRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).ReserveRateLimit(ctx, req.(*ReserveRateLimitReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).CommitReservation(ctx, req.(*ReservationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_CancelReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).CancelReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_CancelReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).CancelReservation(ctx, req.(*ReservationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_TransactRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).TransactRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_TransactRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).TransactRateLimits(ctx, req.(*TransactRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_GetRateLimitHierarchy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRateLimitHierarchyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).GetRateLimitHierarchy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_GetRateLimitHierarchy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).GetRateLimitHierarchy(ctx, req.(*GetRateLimitHierarchyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_RefundRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).RefundRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_RefundRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).RefundRateLimit(ctx, req.(*RefundReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_AcquireLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).AcquireLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_AcquireLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).AcquireLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_ReleaseLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).ReleaseLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_ReleaseLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).ReleaseLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_RegisterLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).RegisterLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_RegisterLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).RegisterLimits(ctx, req.(*RegisterLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _V1_StreamHits_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(V1Server).StreamHits(&v1StreamHitsServer{stream})
+}
+
+type V1_StreamHitsServer interface {
+	Send(*StreamHitsResp) error
+	Recv() (*StreamHitsReq, error)
+	grpc.ServerStream
+}
+
+type v1StreamHitsServer struct {
+	grpc.ServerStream
+}
+
+func (x *v1StreamHitsServer) Send(m *StreamHitsResp) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *v1StreamHitsServer) Recv() (*StreamHitsReq, error) {
+	m := new(StreamHitsReq)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _V1_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthCheckReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(V1Server).HealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: V1_HealthCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(V1Server).HealthCheck(ctx, req.(*HealthCheckReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// V1_ServiceDesc is the grpc.ServiceDesc for V1 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var V1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.gubernator.V1",
+	HandlerType: (*V1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRateLimits",
+			Handler:    _V1_GetRateLimits_Handler,
+		},
+		{
+			MethodName: "GetRateLimitGroup",
+			Handler:    _V1_GetRateLimitGroup_Handler,
+		},
+		{
+			MethodName: "ReserveRateLimit",
+			Handler:    _V1_ReserveRateLimit_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _V1_CommitReservation_Handler,
+		},
+		{
+			MethodName: "CancelReservation",
+			Handler:    _V1_CancelReservation_Handler,
+		},
+		{
+			MethodName: "TransactRateLimits",
+			Handler:    _V1_TransactRateLimits_Handler,
+		},
+		{
+			MethodName: "GetRateLimitHierarchy",
+			Handler:    _V1_GetRateLimitHierarchy_Handler,
+		},
+		{
+			MethodName: "RefundRateLimit",
+			Handler:    _V1_RefundRateLimit_Handler,
+		},
+		{
+			MethodName: "AcquireLease",
+			Handler:    _V1_AcquireLease_Handler,
+		},
+		{
+			MethodName: "ReleaseLease",
+			Handler:    _V1_ReleaseLease_Handler,
+		},
+		{
+			MethodName: "RegisterLimits",
+			Handler:    _V1_RegisterLimits_Handler,
+		},
+		{
+			MethodName: "HealthCheck",
+			Handler:    _V1_HealthCheck_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamHits",
+			Handler:       _V1_StreamHits_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "gubernator.proto",
+}
@@ -0,0 +1,2035 @@
+//
+//Copyright 2018-2022 Mailgun Technologies Inc
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        (unknown)
+// source: peers.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetPeerRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Must specify at least one RateLimit. The peer that recives this request MUST be authoritative for
+	// each rate_limit[x].unique_key provided, as the peer will not forward the request to any other peers
+	Requests []*RateLimitReq `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *GetPeerRateLimitsReq) Reset() {
+	*x = GetPeerRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerRateLimitsReq) ProtoMessage() {}
+
+func (x *GetPeerRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*GetPeerRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetPeerRateLimitsReq) GetRequests() []*RateLimitReq {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type GetPeerRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Responses are in the same order as they appeared in the PeerRateLimitRequests
+	RateLimits []*RateLimitResp `protobuf:"bytes,1,rep,name=rate_limits,json=rateLimits,proto3" json:"rate_limits,omitempty"`
+}
+
+func (x *GetPeerRateLimitsResp) Reset() {
+	*x = GetPeerRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerRateLimitsResp) ProtoMessage() {}
+
+func (x *GetPeerRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*GetPeerRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPeerRateLimitsResp) GetRateLimits() []*RateLimitResp {
+	if x != nil {
+		return x.RateLimits
+	}
+	return nil
+}
+
+type UpdatePeerGlobalsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Must specify at least one RateLimit
+	Globals []*UpdatePeerGlobal `protobuf:"bytes,1,rep,name=globals,proto3" json:"globals,omitempty"`
+}
+
+func (x *UpdatePeerGlobalsReq) Reset() {
+	*x = UpdatePeerGlobalsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePeerGlobalsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerGlobalsReq) ProtoMessage() {}
+
+func (x *UpdatePeerGlobalsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerGlobalsReq.ProtoReflect.Descriptor instead.
+func (*UpdatePeerGlobalsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdatePeerGlobalsReq) GetGlobals() []*UpdatePeerGlobal {
+	if x != nil {
+		return x.Globals
+	}
+	return nil
+}
+
+type UpdatePeerGlobal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Uniquely identifies this rate limit IE: 'ip:10.2.10.7' or 'account:123445'
+	Key    string         `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Status *RateLimitResp `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// The algorithm used to calculate the rate limit. The algorithm may change on
+	// subsequent requests, when this occurs any previous rate limit hit counts are reset.
+	Algorithm Algorithm `protobuf:"varint,3,opt,name=algorithm,proto3,enum=pb.gubernator.Algorithm" json:"algorithm,omitempty"`
+	// The duration of the rate limit in milliseconds
+	Duration int64 `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	// The exact time the original request was created in Epoch milliseconds.
+	// Due to time drift between systems, it may be advantageous for a client to
+	// set the exact time the request was created. It possible the system clock
+	// for the client has drifted from the system clock where gubernator daemon
+	// is running.
+	//
+	// The created time is used by gubernator to calculate the reset time for
+	// both token and leaky algorithms. If it is not set by the client,
+	// gubernator will set the created time when it receives the rate limit
+	// request.
+	CreatedAt int64 `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// The name of the rate limit, used by ResetPeerRateLimits to match the rate limit
+	Name string `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *UpdatePeerGlobal) Reset() {
+	*x = UpdatePeerGlobal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePeerGlobal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerGlobal) ProtoMessage() {}
+
+func (x *UpdatePeerGlobal) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerGlobal.ProtoReflect.Descriptor instead.
+func (*UpdatePeerGlobal) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdatePeerGlobal) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *UpdatePeerGlobal) GetStatus() *RateLimitResp {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *UpdatePeerGlobal) GetAlgorithm() Algorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return Algorithm_TOKEN_BUCKET
+}
+
+func (x *UpdatePeerGlobal) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *UpdatePeerGlobal) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *UpdatePeerGlobal) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type UpdatePeerGlobalsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdatePeerGlobalsResp) Reset() {
+	*x = UpdatePeerGlobalsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePeerGlobalsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerGlobalsResp) ProtoMessage() {}
+
+func (x *UpdatePeerGlobalsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerGlobalsResp.ProtoReflect.Descriptor instead.
+func (*UpdatePeerGlobalsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{4}
+}
+
+type ResetPeerRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// See ResetRateLimitsReq.name_prefix
+	NamePrefix string `protobuf:"bytes,1,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// See ResetRateLimitsReq.name_glob
+	NameGlob string `protobuf:"bytes,2,opt,name=name_glob,json=nameGlob,proto3" json:"name_glob,omitempty"`
+}
+
+func (x *ResetPeerRateLimitsReq) Reset() {
+	*x = ResetPeerRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResetPeerRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPeerRateLimitsReq) ProtoMessage() {}
+
+func (x *ResetPeerRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPeerRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*ResetPeerRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResetPeerRateLimitsReq) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ResetPeerRateLimitsReq) GetNameGlob() string {
+	if x != nil {
+		return x.NameGlob
+	}
+	return ""
+}
+
+type ResetPeerRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of rate limits removed from this peer
+	Removed int64 `protobuf:"varint,1,opt,name=removed,proto3" json:"removed,omitempty"`
+}
+
+func (x *ResetPeerRateLimitsResp) Reset() {
+	*x = ResetPeerRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResetPeerRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetPeerRateLimitsResp) ProtoMessage() {}
+
+func (x *ResetPeerRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetPeerRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*ResetPeerRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResetPeerRateLimitsResp) GetRemoved() int64 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+type UpdatePeerOverridesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Overrides to add or replace
+	Set []*Override `protobuf:"bytes,1,rep,name=set,proto3" json:"set,omitempty"`
+	// Overrides to remove, only the name and unique key are used
+	Delete []*Override `protobuf:"bytes,2,rep,name=delete,proto3" json:"delete,omitempty"`
+}
+
+func (x *UpdatePeerOverridesReq) Reset() {
+	*x = UpdatePeerOverridesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePeerOverridesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerOverridesReq) ProtoMessage() {}
+
+func (x *UpdatePeerOverridesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerOverridesReq.ProtoReflect.Descriptor instead.
+func (*UpdatePeerOverridesReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePeerOverridesReq) GetSet() []*Override {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+func (x *UpdatePeerOverridesReq) GetDelete() []*Override {
+	if x != nil {
+		return x.Delete
+	}
+	return nil
+}
+
+type UpdatePeerOverridesResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdatePeerOverridesResp) Reset() {
+	*x = UpdatePeerOverridesResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdatePeerOverridesResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePeerOverridesResp) ProtoMessage() {}
+
+func (x *UpdatePeerOverridesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePeerOverridesResp.ProtoReflect.Descriptor instead.
+func (*UpdatePeerOverridesResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{8}
+}
+
+type ListPeerLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListPeerLimitsReq) Reset() {
+	*x = ListPeerLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPeerLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerLimitsReq) ProtoMessage() {}
+
+func (x *ListPeerLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerLimitsReq.ProtoReflect.Descriptor instead.
+func (*ListPeerLimitsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{9}
+}
+
+type ListPeerLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The limit definitions registered via V1.RegisterLimits
+	Limits []*RegisteredLimit `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+}
+
+func (x *ListPeerLimitsResp) Reset() {
+	*x = ListPeerLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPeerLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerLimitsResp) ProtoMessage() {}
+
+func (x *ListPeerLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerLimitsResp.ProtoReflect.Descriptor instead.
+func (*ListPeerLimitsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPeerLimitsResp) GetLimits() []*RegisteredLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type TransferPeerRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The rate limits which the receiving peer will own
+	Items []*CacheItemState `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *TransferPeerRateLimitsReq) Reset() {
+	*x = TransferPeerRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferPeerRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferPeerRateLimitsReq) ProtoMessage() {}
+
+func (x *TransferPeerRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferPeerRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*TransferPeerRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TransferPeerRateLimitsReq) GetItems() []*CacheItemState {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type TransferPeerRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of rate limits added to the cache
+	Added int64 `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	// The number of rate limits which were already in the cache, which are kept as is
+	Existing int64 `protobuf:"varint,2,opt,name=existing,proto3" json:"existing,omitempty"`
+}
+
+func (x *TransferPeerRateLimitsResp) Reset() {
+	*x = TransferPeerRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferPeerRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferPeerRateLimitsResp) ProtoMessage() {}
+
+func (x *TransferPeerRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferPeerRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*TransferPeerRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TransferPeerRateLimitsResp) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *TransferPeerRateLimitsResp) GetExisting() int64 {
+	if x != nil {
+		return x.Existing
+	}
+	return 0
+}
+
+type ReplicatePeerRateLimitsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The current state of the rate limits, which replaces the state held by the standby
+	Items []*CacheItemState `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *ReplicatePeerRateLimitsReq) Reset() {
+	*x = ReplicatePeerRateLimitsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplicatePeerRateLimitsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicatePeerRateLimitsReq) ProtoMessage() {}
+
+func (x *ReplicatePeerRateLimitsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicatePeerRateLimitsReq.ProtoReflect.Descriptor instead.
+func (*ReplicatePeerRateLimitsReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplicatePeerRateLimitsReq) GetItems() []*CacheItemState {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ReplicatePeerRateLimitsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReplicatePeerRateLimitsResp) Reset() {
+	*x = ReplicatePeerRateLimitsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplicatePeerRateLimitsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicatePeerRateLimitsResp) ProtoMessage() {}
+
+func (x *ReplicatePeerRateLimitsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicatePeerRateLimitsResp.ProtoReflect.Descriptor instead.
+func (*ReplicatePeerRateLimitsResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{14}
+}
+
+type GetPeerKeyFilterReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetPeerKeyFilterReq) Reset() {
+	*x = GetPeerKeyFilterReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerKeyFilterReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerKeyFilterReq) ProtoMessage() {}
+
+func (x *GetPeerKeyFilterReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerKeyFilterReq.ProtoReflect.Descriptor instead.
+func (*GetPeerKeyFilterReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{15}
+}
+
+type GetPeerKeyFilterResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The bits of a bloom filter of the hash keys in the cache of the peer. Is empty if the peer
+	// does not report a filter, IE: the rate limits missing from its cache may be in its Store
+	Bits []uint64 `protobuf:"fixed64,1,rep,packed,name=bits,proto3" json:"bits,omitempty"`
+	// The number of bits set in the filter for each hash key
+	Hashes int32 `protobuf:"varint,2,opt,name=hashes,proto3" json:"hashes,omitempty"`
+}
+
+func (x *GetPeerKeyFilterResp) Reset() {
+	*x = GetPeerKeyFilterResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerKeyFilterResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerKeyFilterResp) ProtoMessage() {}
+
+func (x *GetPeerKeyFilterResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerKeyFilterResp.ProtoReflect.Descriptor instead.
+func (*GetPeerKeyFilterResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPeerKeyFilterResp) GetBits() []uint64 {
+	if x != nil {
+		return x.Bits
+	}
+	return nil
+}
+
+func (x *GetPeerKeyFilterResp) GetHashes() int32 {
+	if x != nil {
+		return x.Hashes
+	}
+	return 0
+}
+
+type GetPeerVersionReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetPeerVersionReq) Reset() {
+	*x = GetPeerVersionReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerVersionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerVersionReq) ProtoMessage() {}
+
+func (x *GetPeerVersionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerVersionReq.ProtoReflect.Descriptor instead.
+func (*GetPeerVersionReq) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{17}
+}
+
+type GetPeerVersionResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version the peer was built with, see gubernator.Version
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The VCS revision the peer was built from, see gubernator.Commit
+	Commit string `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	// The checksum of the configuration of the peer, see HealthCheckResp.config_checksum
+	ConfigChecksum string `protobuf:"bytes,3,opt,name=config_checksum,json=configChecksum,proto3" json:"config_checksum,omitempty"`
+	// The stable identity of the peer, see DaemonConfig.NodeID. Empty if the peer has none
+	NodeId string `protobuf:"bytes,4,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+}
+
+func (x *GetPeerVersionResp) Reset() {
+	*x = GetPeerVersionResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPeerVersionResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPeerVersionResp) ProtoMessage() {}
+
+func (x *GetPeerVersionResp) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPeerVersionResp.ProtoReflect.Descriptor instead.
+func (*GetPeerVersionResp) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetPeerVersionResp) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetPeerVersionResp) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *GetPeerVersionResp) GetConfigChecksum() string {
+	if x != nil {
+		return x.ConfigChecksum
+	}
+	return ""
+}
+
+func (x *GetPeerVersionResp) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+// The encoding of a cached rate limit shared by the SnapshotLoader, the SQLiteStore and Store
+// implementations, see MarshalCacheItem() and UnmarshalCacheItem(). Fields may be added in later
+// versions of gubernator; decoders ignore the fields they do not know, such that instances of
+// different versions read each others rate limits during a rolling upgrade.
+type CacheItemState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version of the encoding, only incremented by changes which older versions can not decode
+	Version int32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// The hash key of the rate limit, see Config.HashKey
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// The name of the rate limit
+	Name      string    `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Algorithm Algorithm `protobuf:"varint,4,opt,name=algorithm,proto3,enum=pb.gubernator.Algorithm" json:"algorithm,omitempty"`
+	// Timestamp when the rate limit expires in epoch milliseconds
+	ExpireAt int64 `protobuf:"varint,5,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
+	// Timestamp when the cache should invalidate the rate limit in epoch milliseconds
+	InvalidAt int64 `protobuf:"varint,6,opt,name=invalid_at,json=invalidAt,proto3" json:"invalid_at,omitempty"`
+	// Types that are assignable to Bucket:
+	//
+	//	*CacheItemState_TokenBucket
+	//	*CacheItemState_LeakyBucket
+	Bucket isCacheItemState_Bucket `protobuf_oneof:"bucket"`
+}
+
+func (x *CacheItemState) Reset() {
+	*x = CacheItemState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CacheItemState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CacheItemState) ProtoMessage() {}
+
+func (x *CacheItemState) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CacheItemState.ProtoReflect.Descriptor instead.
+func (*CacheItemState) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CacheItemState) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *CacheItemState) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CacheItemState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CacheItemState) GetAlgorithm() Algorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return Algorithm_TOKEN_BUCKET
+}
+
+func (x *CacheItemState) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *CacheItemState) GetInvalidAt() int64 {
+	if x != nil {
+		return x.InvalidAt
+	}
+	return 0
+}
+
+func (m *CacheItemState) GetBucket() isCacheItemState_Bucket {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (x *CacheItemState) GetTokenBucket() *TokenBucketState {
+	if x, ok := x.GetBucket().(*CacheItemState_TokenBucket); ok {
+		return x.TokenBucket
+	}
+	return nil
+}
+
+func (x *CacheItemState) GetLeakyBucket() *LeakyBucketState {
+	if x, ok := x.GetBucket().(*CacheItemState_LeakyBucket); ok {
+		return x.LeakyBucket
+	}
+	return nil
+}
+
+type isCacheItemState_Bucket interface {
+	isCacheItemState_Bucket()
+}
+
+type CacheItemState_TokenBucket struct {
+	TokenBucket *TokenBucketState `protobuf:"bytes,7,opt,name=token_bucket,json=tokenBucket,proto3,oneof"`
+}
+
+type CacheItemState_LeakyBucket struct {
+	LeakyBucket *LeakyBucketState `protobuf:"bytes,8,opt,name=leaky_bucket,json=leakyBucket,proto3,oneof"`
+}
+
+func (*CacheItemState_TokenBucket) isCacheItemState_Bucket() {}
+
+func (*CacheItemState_LeakyBucket) isCacheItemState_Bucket() {}
+
+// The state of a TOKEN_BUCKET rate limit, see TokenBucketItem
+type TokenBucketState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status    Status `protobuf:"varint,1,opt,name=status,proto3,enum=pb.gubernator.Status" json:"status,omitempty"`
+	Limit     int64  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Duration  int64  `protobuf:"varint,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	Remaining int64  `protobuf:"varint,4,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	CreatedAt int64  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt int64  `protobuf:"varint,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	GraceUsed int64  `protobuf:"varint,7,opt,name=grace_used,json=graceUsed,proto3" json:"grace_used,omitempty"`
+}
+
+func (x *TokenBucketState) Reset() {
+	*x = TokenBucketState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TokenBucketState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenBucketState) ProtoMessage() {}
+
+func (x *TokenBucketState) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenBucketState.ProtoReflect.Descriptor instead.
+func (*TokenBucketState) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TokenBucketState) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_UNDER_LIMIT
+}
+
+func (x *TokenBucketState) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *TokenBucketState) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *TokenBucketState) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *TokenBucketState) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *TokenBucketState) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *TokenBucketState) GetGraceUsed() int64 {
+	if x != nil {
+		return x.GraceUsed
+	}
+	return 0
+}
+
+// The state of a LEAKY_BUCKET rate limit, see LeakyBucketItem
+type LeakyBucketState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit     int64   `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Duration  int64   `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	Remaining float64 `protobuf:"fixed64,3,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	UpdatedAt int64   `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Burst     int64   `protobuf:"varint,5,opt,name=burst,proto3" json:"burst,omitempty"`
+}
+
+func (x *LeakyBucketState) Reset() {
+	*x = LeakyBucketState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peers_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LeakyBucketState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeakyBucketState) ProtoMessage() {}
+
+func (x *LeakyBucketState) ProtoReflect() protoreflect.Message {
+	mi := &file_peers_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeakyBucketState.ProtoReflect.Descriptor instead.
+func (*LeakyBucketState) Descriptor() ([]byte, []int) {
+	return file_peers_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *LeakyBucketState) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LeakyBucketState) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *LeakyBucketState) GetRemaining() float64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *LeakyBucketState) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *LeakyBucketState) GetBurst() int64 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+var File_peers_proto protoreflect.FileDescriptor
+
+var file_peers_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x1a, 0x10, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0b,
+	0x61, 0x64, 0x6d, 0x69, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4f, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52,
+	0x65, 0x71, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x56, 0x0a, 0x15,
+	0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x3d, 0x0a, 0x0b, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x22, 0x51, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65,
+	0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x12, 0x39, 0x0a, 0x07,
+	0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x52, 0x07,
+	0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73, 0x22, 0xe1, 0x01, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x34,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x36, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x17, 0x0a, 0x15, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x22, 0x56, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x65,
+	0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x1b, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x67, 0x6c, 0x6f, 0x62, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6e, 0x61, 0x6d, 0x65, 0x47, 0x6c, 0x6f, 0x62, 0x22, 0x33, 0x0a, 0x17,
+	0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x64, 0x22, 0x74, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x4f,
+	0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x12, 0x29, 0x0a, 0x03, 0x73,
+	0x65, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
+	0x65, 0x52, 0x03, 0x73, 0x65, 0x74, 0x12, 0x2f, 0x0a, 0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x52,
+	0x06, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x22, 0x19, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x65, 0x65, 0x72, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x22, 0x4c, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x36, 0x0a,
+	0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x65, 0x64, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x06, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x22, 0x50, 0x0a, 0x19, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x12, 0x33, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x4e, 0x0a, 0x1a, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65,
+	0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65,
+	0x78, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x51, 0x0a, 0x1a, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x12, 0x33, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x65,
+	0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x15, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x22, 0x42, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x69, 0x74, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x06, 0x52, 0x04, 0x62, 0x69, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x22, 0x88, 0x01, 0x0a, 0x12, 0x47, 0x65,
+	0x74, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x6e,
+	0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x22, 0xda, 0x02, 0x0a, 0x0e, 0x43, 0x61, 0x63, 0x68, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72,
+	0x69, 0x74, 0x68, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x41, 0x6c, 0x67, 0x6f, 0x72,
+	0x69, 0x74, 0x68, 0x6d, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12,
+	0x1b, 0x0a, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x69, 0x6e, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x41, 0x74, 0x12, 0x44, 0x0a, 0x0c, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x12, 0x44, 0x0a, 0x0c, 0x6c, 0x65, 0x61, 0x6b, 0x79, 0x5f, 0x62, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x6b, 0x79, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x6b,
+	0x79, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x42, 0x08, 0x0a, 0x06, 0x62, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x22, 0xee, 0x01, 0x0a, 0x10, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x6d, 0x61,
+	0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x75, 0x73, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x67, 0x72, 0x61, 0x63, 0x65, 0x55, 0x73,
+	0x65, 0x64, 0x22, 0x97, 0x01, 0x0a, 0x10, 0x4c, 0x65, 0x61, 0x6b, 0x79, 0x42, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6d,
+	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x72, 0x65,
+	0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x32, 0xe3, 0x10, 0x0a,
+	0x07, 0x50, 0x65, 0x65, 0x72, 0x73, 0x56, 0x31, 0x12, 0x60, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x23, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65,
+	0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x24, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x60, 0x0a, 0x11, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73, 0x12,
+	0x23, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x47,
+	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x66, 0x0a, 0x13,
+	0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x26, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x00, 0x12, 0x61, 0x0a, 0x14, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x50,
+	0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71,
+	0x1a, 0x23, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a,
+	0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x22,
+	0x00, 0x12, 0x58, 0x0a, 0x15, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x50, 0x65, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x1e, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x13, 0x52,
+	0x65, 0x66, 0x75, 0x6e, 0x64, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x66,
+	0x75, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x10, 0x41, 0x63, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x17, 0x2e,
+	0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65,
+	0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x22, 0x00, 0x12, 0x47, 0x0a, 0x10, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x50, 0x65, 0x65,
+	0x72, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x17, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65,
+	0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x1a,
+	0x18, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x15, 0x47,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x55,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x23, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x24, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22,
+	0x00, 0x12, 0x66, 0x0a, 0x13, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x4f,
+	0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x25, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50,
+	0x65, 0x65, 0x72, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x26, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x4c, 0x69, 0x73,
+	0x74, 0x50, 0x65, 0x65, 0x72, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x1f,
+	0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x00, 0x12, 0x5b, 0x0a, 0x12, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50,
+	0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x70, 0x62,
+	0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00,
+	0x12, 0x57, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d,
+	0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x44, 0x72, 0x69, 0x66, 0x74, 0x12, 0x1f,
+	0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47,
+	0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65, 0x71, 0x1a,
+	0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x47, 0x65, 0x74, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x44, 0x72, 0x69, 0x66, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4b, 0x65,
+	0x79, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x79,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x5b, 0x0a,
+	0x12, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x21, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x20, 0x2e, 0x70,
+	0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x1a, 0x21,
+	0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x54, 0x72,
+	0x61, 0x66, 0x66, 0x69, 0x63, 0x12, 0x1c, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63,
+	0x52, 0x65, 0x71, 0x1a, 0x1d, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61,
+	0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x50, 0x65,
+	0x65, 0x72, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x1f, 0x2e, 0x70, 0x62, 0x2e, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79,
+	0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x1a, 0x20, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x79, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x6f,
+	0x0a, 0x16, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75,
+	0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52,
+	0x65, 0x71, 0x1a, 0x29, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74,
+	0x6f, 0x72, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x50, 0x65, 0x65, 0x72, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12,
+	0x72, 0x0a, 0x17, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72,
+	0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x70, 0x62, 0x2e,
+	0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x2a, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62, 0x65, 0x72,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x50,
+	0x65, 0x65, 0x72, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4b, 0x65,
+	0x79, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x22, 0x2e, 0x70, 0x62, 0x2e, 0x67, 0x75, 0x62,
+	0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x4b,
+	0x65, 0x79, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x71, 0x1a, 0x23, 0x2e, 0x70, 0x62,
+	0x2e, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x22, 0x00, 0x42, 0x31, 0x5a, 0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x67, 0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2d, 0x69, 0x6f, 0x2f, 0x67,
+	0x75, 0x62, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x32, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x80, 0x01, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_peers_proto_rawDescOnce sync.Once
+	file_peers_proto_rawDescData = file_peers_proto_rawDesc
+)
+
+func file_peers_proto_rawDescGZIP() []byte {
+	file_peers_proto_rawDescOnce.Do(func() {
+		file_peers_proto_rawDescData = protoimpl.X.CompressGZIP(file_peers_proto_rawDescData)
+	})
+	return file_peers_proto_rawDescData
+}
+
+var file_peers_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_peers_proto_goTypes = []interface{}{
+	(*GetPeerRateLimitsReq)(nil),        // 0: pb.gubernator.GetPeerRateLimitsReq
+	(*GetPeerRateLimitsResp)(nil),       // 1: pb.gubernator.GetPeerRateLimitsResp
+	(*UpdatePeerGlobalsReq)(nil),        // 2: pb.gubernator.UpdatePeerGlobalsReq
+	(*UpdatePeerGlobal)(nil),            // 3: pb.gubernator.UpdatePeerGlobal
+	(*UpdatePeerGlobalsResp)(nil),       // 4: pb.gubernator.UpdatePeerGlobalsResp
+	(*ResetPeerRateLimitsReq)(nil),      // 5: pb.gubernator.ResetPeerRateLimitsReq
+	(*ResetPeerRateLimitsResp)(nil),     // 6: pb.gubernator.ResetPeerRateLimitsResp
+	(*UpdatePeerOverridesReq)(nil),      // 7: pb.gubernator.UpdatePeerOverridesReq
+	(*UpdatePeerOverridesResp)(nil),     // 8: pb.gubernator.UpdatePeerOverridesResp
+	(*ListPeerLimitsReq)(nil),           // 9: pb.gubernator.ListPeerLimitsReq
+	(*ListPeerLimitsResp)(nil),          // 10: pb.gubernator.ListPeerLimitsResp
+	(*TransferPeerRateLimitsReq)(nil),   // 11: pb.gubernator.TransferPeerRateLimitsReq
+	(*TransferPeerRateLimitsResp)(nil),  // 12: pb.gubernator.TransferPeerRateLimitsResp
+	(*ReplicatePeerRateLimitsReq)(nil),  // 13: pb.gubernator.ReplicatePeerRateLimitsReq
+	(*ReplicatePeerRateLimitsResp)(nil), // 14: pb.gubernator.ReplicatePeerRateLimitsResp
+	(*GetPeerKeyFilterReq)(nil),         // 15: pb.gubernator.GetPeerKeyFilterReq
+	(*GetPeerKeyFilterResp)(nil),        // 16: pb.gubernator.GetPeerKeyFilterResp
+	(*GetPeerVersionReq)(nil),           // 17: pb.gubernator.GetPeerVersionReq
+	(*GetPeerVersionResp)(nil),          // 18: pb.gubernator.GetPeerVersionResp
+	(*CacheItemState)(nil),              // 19: pb.gubernator.CacheItemState
+	(*TokenBucketState)(nil),            // 20: pb.gubernator.TokenBucketState
+	(*LeakyBucketState)(nil),            // 21: pb.gubernator.LeakyBucketState
+	(*RateLimitReq)(nil),                // 22: pb.gubernator.RateLimitReq
+	(*RateLimitResp)(nil),               // 23: pb.gubernator.RateLimitResp
+	(Algorithm)(0),                      // 24: pb.gubernator.Algorithm
+	(*Override)(nil),                    // 25: pb.gubernator.Override
+	(*RegisteredLimit)(nil),             // 26: pb.gubernator.RegisteredLimit
+	(Status)(0),                         // 27: pb.gubernator.Status
+	(*ReserveRateLimitReq)(nil),         // 28: pb.gubernator.ReserveRateLimitReq
+	(*ReservationReq)(nil),              // 29: pb.gubernator.ReservationReq
+	(*RefundReq)(nil),                   // 30: pb.gubernator.RefundReq
+	(*LeaseReq)(nil),                    // 31: pb.gubernator.LeaseReq
+	(*GetNamespaceUsageReq)(nil),        // 32: pb.gubernator.GetNamespaceUsageReq
+	(*ListOverridesReq)(nil),            // 33: pb.gubernator.ListOverridesReq
+	(*RegisterLimitsReq)(nil),           // 34: pb.gubernator.RegisterLimitsReq
+	(*GetLimitDriftReq)(nil),            // 35: pb.gubernator.GetLimitDriftReq
+	(*GetKeyHistoryReq)(nil),            // 36: pb.gubernator.GetKeyHistoryReq
+	(*ListNamespacesReq)(nil),           // 37: pb.gubernator.ListNamespacesReq
+	(*GetTrafficReq)(nil),               // 38: pb.gubernator.GetTrafficReq
+	(*ReplayJournalReq)(nil),            // 39: pb.gubernator.ReplayJournalReq
+	(*ReserveRateLimitResp)(nil),        // 40: pb.gubernator.ReserveRateLimitResp
+	(*ReservationResp)(nil),             // 41: pb.gubernator.ReservationResp
+	(*RefundResp)(nil),                  // 42: pb.gubernator.RefundResp
+	(*LeaseResp)(nil),                   // 43: pb.gubernator.LeaseResp
+	(*GetNamespaceUsageResp)(nil),       // 44: pb.gubernator.GetNamespaceUsageResp
+	(*ListOverridesResp)(nil),           // 45: pb.gubernator.ListOverridesResp
+	(*RegisterLimitsResp)(nil),          // 46: pb.gubernator.RegisterLimitsResp
+	(*GetLimitDriftResp)(nil),           // 47: pb.gubernator.GetLimitDriftResp
+	(*GetKeyHistoryResp)(nil),           // 48: pb.gubernator.GetKeyHistoryResp
+	(*ListNamespacesResp)(nil),          // 49: pb.gubernator.ListNamespacesResp
+	(*GetTrafficResp)(nil),              // 50: pb.gubernator.GetTrafficResp
+	(*ReplayJournalResp)(nil),           // 51: pb.gubernator.ReplayJournalResp
+}
+var file_peers_proto_depIdxs = []int32{
+	22, // 0: pb.gubernator.GetPeerRateLimitsReq.requests:type_name -> pb.gubernator.RateLimitReq
+	23, // 1: pb.gubernator.GetPeerRateLimitsResp.rate_limits:type_name -> pb.gubernator.RateLimitResp
+	3,  // 2: pb.gubernator.UpdatePeerGlobalsReq.globals:type_name -> pb.gubernator.UpdatePeerGlobal
+	23, // 3: pb.gubernator.UpdatePeerGlobal.status:type_name -> pb.gubernator.RateLimitResp
+	24, // 4: pb.gubernator.UpdatePeerGlobal.algorithm:type_name -> pb.gubernator.Algorithm
+	25, // 5: pb.gubernator.UpdatePeerOverridesReq.set:type_name -> pb.gubernator.Override
+	25, // 6: pb.gubernator.UpdatePeerOverridesReq.delete:type_name -> pb.gubernator.Override
+	26, // 7: pb.gubernator.ListPeerLimitsResp.limits:type_name -> pb.gubernator.RegisteredLimit
+	19, // 8: pb.gubernator.TransferPeerRateLimitsReq.items:type_name -> pb.gubernator.CacheItemState
+	19, // 9: pb.gubernator.ReplicatePeerRateLimitsReq.items:type_name -> pb.gubernator.CacheItemState
+	24, // 10: pb.gubernator.CacheItemState.algorithm:type_name -> pb.gubernator.Algorithm
+	20, // 11: pb.gubernator.CacheItemState.token_bucket:type_name -> pb.gubernator.TokenBucketState
+	21, // 12: pb.gubernator.CacheItemState.leaky_bucket:type_name -> pb.gubernator.LeakyBucketState
+	27, // 13: pb.gubernator.TokenBucketState.status:type_name -> pb.gubernator.Status
+	0,  // 14: pb.gubernator.PeersV1.GetPeerRateLimits:input_type -> pb.gubernator.GetPeerRateLimitsReq
+	2,  // 15: pb.gubernator.PeersV1.UpdatePeerGlobals:input_type -> pb.gubernator.UpdatePeerGlobalsReq
+	5,  // 16: pb.gubernator.PeersV1.ResetPeerRateLimits:input_type -> pb.gubernator.ResetPeerRateLimitsReq
+	28, // 17: pb.gubernator.PeersV1.ReservePeerRateLimit:input_type -> pb.gubernator.ReserveRateLimitReq
+	29, // 18: pb.gubernator.PeersV1.CommitPeerReservation:input_type -> pb.gubernator.ReservationReq
+	29, // 19: pb.gubernator.PeersV1.CancelPeerReservation:input_type -> pb.gubernator.ReservationReq
+	30, // 20: pb.gubernator.PeersV1.RefundPeerRateLimit:input_type -> pb.gubernator.RefundReq
+	31, // 21: pb.gubernator.PeersV1.AcquirePeerLease:input_type -> pb.gubernator.LeaseReq
+	31, // 22: pb.gubernator.PeersV1.ReleasePeerLease:input_type -> pb.gubernator.LeaseReq
+	32, // 23: pb.gubernator.PeersV1.GetPeerNamespaceUsage:input_type -> pb.gubernator.GetNamespaceUsageReq
+	7,  // 24: pb.gubernator.PeersV1.UpdatePeerOverrides:input_type -> pb.gubernator.UpdatePeerOverridesReq
+	33, // 25: pb.gubernator.PeersV1.ListPeerOverrides:input_type -> pb.gubernator.ListOverridesReq
+	34, // 26: pb.gubernator.PeersV1.RegisterPeerLimits:input_type -> pb.gubernator.RegisterLimitsReq
+	9,  // 27: pb.gubernator.PeersV1.ListPeerLimits:input_type -> pb.gubernator.ListPeerLimitsReq
+	35, // 28: pb.gubernator.PeersV1.GetPeerLimitDrift:input_type -> pb.gubernator.GetLimitDriftReq
+	36, // 29: pb.gubernator.PeersV1.GetPeerKeyHistory:input_type -> pb.gubernator.GetKeyHistoryReq
+	37, // 30: pb.gubernator.PeersV1.ListPeerNamespaces:input_type -> pb.gubernator.ListNamespacesReq
+	17, // 31: pb.gubernator.PeersV1.GetPeerVersion:input_type -> pb.gubernator.GetPeerVersionReq
+	38, // 32: pb.gubernator.PeersV1.GetPeerTraffic:input_type -> pb.gubernator.GetTrafficReq
+	39, // 33: pb.gubernator.PeersV1.ReplayPeerJournal:input_type -> pb.gubernator.ReplayJournalReq
+	11, // 34: pb.gubernator.PeersV1.TransferPeerRateLimits:input_type -> pb.gubernator.TransferPeerRateLimitsReq
+	13, // 35: pb.gubernator.PeersV1.ReplicatePeerRateLimits:input_type -> pb.gubernator.ReplicatePeerRateLimitsReq
+	15, // 36: pb.gubernator.PeersV1.GetPeerKeyFilter:input_type -> pb.gubernator.GetPeerKeyFilterReq
+	1,  // 37: pb.gubernator.PeersV1.GetPeerRateLimits:output_type -> pb.gubernator.GetPeerRateLimitsResp
+	4,  // 38: pb.gubernator.PeersV1.UpdatePeerGlobals:output_type -> pb.gubernator.UpdatePeerGlobalsResp
+	6,  // 39: pb.gubernator.PeersV1.ResetPeerRateLimits:output_type -> pb.gubernator.ResetPeerRateLimitsResp
+	40, // 40: pb.gubernator.PeersV1.ReservePeerRateLimit:output_type -> pb.gubernator.ReserveRateLimitResp
+	41, // 41: pb.gubernator.PeersV1.CommitPeerReservation:output_type -> pb.gubernator.ReservationResp
+	41, // 42: pb.gubernator.PeersV1.CancelPeerReservation:output_type -> pb.gubernator.ReservationResp
+	42, // 43: pb.gubernator.PeersV1.RefundPeerRateLimit:output_type -> pb.gubernator.RefundResp
+	43, // 44: pb.gubernator.PeersV1.AcquirePeerLease:output_type -> pb.gubernator.LeaseResp
+	43, // 45: pb.gubernator.PeersV1.ReleasePeerLease:output_type -> pb.gubernator.LeaseResp
+	44, // 46: pb.gubernator.PeersV1.GetPeerNamespaceUsage:output_type -> pb.gubernator.GetNamespaceUsageResp
+	8,  // 47: pb.gubernator.PeersV1.UpdatePeerOverrides:output_type -> pb.gubernator.UpdatePeerOverridesResp
+	45, // 48: pb.gubernator.PeersV1.ListPeerOverrides:output_type -> pb.gubernator.ListOverridesResp
+	46, // 49: pb.gubernator.PeersV1.RegisterPeerLimits:output_type -> pb.gubernator.RegisterLimitsResp
+	10, // 50: pb.gubernator.PeersV1.ListPeerLimits:output_type -> pb.gubernator.ListPeerLimitsResp
+	47, // 51: pb.gubernator.PeersV1.GetPeerLimitDrift:output_type -> pb.gubernator.GetLimitDriftResp
+	48, // 52: pb.gubernator.PeersV1.GetPeerKeyHistory:output_type -> pb.gubernator.GetKeyHistoryResp
+	49, // 53: pb.gubernator.PeersV1.ListPeerNamespaces:output_type -> pb.gubernator.ListNamespacesResp
+	18, // 54: pb.gubernator.PeersV1.GetPeerVersion:output_type -> pb.gubernator.GetPeerVersionResp
+	50, // 55: pb.gubernator.PeersV1.GetPeerTraffic:output_type -> pb.gubernator.GetTrafficResp
+	51, // 56: pb.gubernator.PeersV1.ReplayPeerJournal:output_type -> pb.gubernator.ReplayJournalResp
+	12, // 57: pb.gubernator.PeersV1.TransferPeerRateLimits:output_type -> pb.gubernator.TransferPeerRateLimitsResp
+	14, // 58: pb.gubernator.PeersV1.ReplicatePeerRateLimits:output_type -> pb.gubernator.ReplicatePeerRateLimitsResp
+	16, // 59: pb.gubernator.PeersV1.GetPeerKeyFilter:output_type -> pb.gubernator.GetPeerKeyFilterResp
+	37, // [37:60] is the sub-list for method output_type
+	14, // [14:37] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_peers_proto_init() }
+func file_peers_proto_init() {
+	if File_peers_proto != nil {
+		return
+	}
+	file_gubernator_proto_init()
+	file_admin_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_peers_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePeerGlobalsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePeerGlobal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePeerGlobalsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetPeerRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetPeerRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePeerOverridesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdatePeerOverridesResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPeerLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPeerLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferPeerRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferPeerRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplicatePeerRateLimitsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplicatePeerRateLimitsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerKeyFilterReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerKeyFilterResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerVersionReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPeerVersionResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CacheItemState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TokenBucketState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peers_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LeakyBucketState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_peers_proto_msgTypes[19].OneofWrappers = []interface{}{
+		(*CacheItemState_TokenBucket)(nil),
+		(*CacheItemState_LeakyBucket)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_peers_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_peers_proto_goTypes,
+		DependencyIndexes: file_peers_proto_depIdxs,
+		MessageInfos:      file_peers_proto_msgTypes,
+	}.Build()
+	File_peers_proto = out.File
+	file_peers_proto_rawDesc = nil
+	file_peers_proto_goTypes = nil
+	file_peers_proto_depIdxs = nil
+}
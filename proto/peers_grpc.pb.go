@@ -0,0 +1,982 @@
+//
+//Copyright 2018-2022 Mailgun Technologies Inc
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: peers.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PeersV1_GetPeerRateLimits_FullMethodName       = "/pb.gubernator.PeersV1/GetPeerRateLimits"
+	PeersV1_UpdatePeerGlobals_FullMethodName       = "/pb.gubernator.PeersV1/UpdatePeerGlobals"
+	PeersV1_ResetPeerRateLimits_FullMethodName     = "/pb.gubernator.PeersV1/ResetPeerRateLimits"
+	PeersV1_ReservePeerRateLimit_FullMethodName    = "/pb.gubernator.PeersV1/ReservePeerRateLimit"
+	PeersV1_CommitPeerReservation_FullMethodName   = "/pb.gubernator.PeersV1/CommitPeerReservation"
+	PeersV1_CancelPeerReservation_FullMethodName   = "/pb.gubernator.PeersV1/CancelPeerReservation"
+	PeersV1_RefundPeerRateLimit_FullMethodName     = "/pb.gubernator.PeersV1/RefundPeerRateLimit"
+	PeersV1_AcquirePeerLease_FullMethodName        = "/pb.gubernator.PeersV1/AcquirePeerLease"
+	PeersV1_ReleasePeerLease_FullMethodName        = "/pb.gubernator.PeersV1/ReleasePeerLease"
+	PeersV1_GetPeerNamespaceUsage_FullMethodName   = "/pb.gubernator.PeersV1/GetPeerNamespaceUsage"
+	PeersV1_UpdatePeerOverrides_FullMethodName     = "/pb.gubernator.PeersV1/UpdatePeerOverrides"
+	PeersV1_ListPeerOverrides_FullMethodName       = "/pb.gubernator.PeersV1/ListPeerOverrides"
+	PeersV1_RegisterPeerLimits_FullMethodName      = "/pb.gubernator.PeersV1/RegisterPeerLimits"
+	PeersV1_ListPeerLimits_FullMethodName          = "/pb.gubernator.PeersV1/ListPeerLimits"
+	PeersV1_GetPeerLimitDrift_FullMethodName       = "/pb.gubernator.PeersV1/GetPeerLimitDrift"
+	PeersV1_GetPeerKeyHistory_FullMethodName       = "/pb.gubernator.PeersV1/GetPeerKeyHistory"
+	PeersV1_ListPeerNamespaces_FullMethodName      = "/pb.gubernator.PeersV1/ListPeerNamespaces"
+	PeersV1_GetPeerVersion_FullMethodName          = "/pb.gubernator.PeersV1/GetPeerVersion"
+	PeersV1_GetPeerTraffic_FullMethodName          = "/pb.gubernator.PeersV1/GetPeerTraffic"
+	PeersV1_ReplayPeerJournal_FullMethodName       = "/pb.gubernator.PeersV1/ReplayPeerJournal"
+	PeersV1_TransferPeerRateLimits_FullMethodName  = "/pb.gubernator.PeersV1/TransferPeerRateLimits"
+	PeersV1_ReplicatePeerRateLimits_FullMethodName = "/pb.gubernator.PeersV1/ReplicatePeerRateLimits"
+	PeersV1_GetPeerKeyFilter_FullMethodName        = "/pb.gubernator.PeersV1/GetPeerKeyFilter"
+)
+
+// PeersV1Client is the client API for PeersV1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PeersV1Client interface {
+	// Used by peers to relay batches of requests to an owner peer
+	GetPeerRateLimits(ctx context.Context, in *GetPeerRateLimitsReq, opts ...grpc.CallOption) (*GetPeerRateLimitsResp, error)
+	// Used by owner peers to send global rate limit updates to non-owner peers
+	UpdatePeerGlobals(ctx context.Context, in *UpdatePeerGlobalsReq, opts ...grpc.CallOption) (*UpdatePeerGlobalsResp, error)
+	// Used by AdminV1.ResetRateLimits to remove matching rate limits from each peer
+	ResetPeerRateLimits(ctx context.Context, in *ResetPeerRateLimitsReq, opts ...grpc.CallOption) (*ResetPeerRateLimitsResp, error)
+	// Used by peers to relay reservations to the owner peer, which holds the reservation
+	ReservePeerRateLimit(ctx context.Context, in *ReserveRateLimitReq, opts ...grpc.CallOption) (*ReserveRateLimitResp, error)
+	CommitPeerReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error)
+	CancelPeerReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error)
+	// Used by peers to relay refunds to the owner peer, which holds the refundable hits
+	RefundPeerRateLimit(ctx context.Context, in *RefundReq, opts ...grpc.CallOption) (*RefundResp, error)
+	// Used by peers to relay leases to the owner peer, which holds the lease
+	AcquirePeerLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error)
+	ReleasePeerLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error)
+	// Used by AdminV1.GetNamespaceUsage to collect the usage of the rate limits owned by each peer
+	GetPeerNamespaceUsage(ctx context.Context, in *GetNamespaceUsageReq, opts ...grpc.CallOption) (*GetNamespaceUsageResp, error)
+	// Used by AdminV1.SetOverride and AdminV1.DeleteOverride to update the overrides of each peer
+	UpdatePeerOverrides(ctx context.Context, in *UpdatePeerOverridesReq, opts ...grpc.CallOption) (*UpdatePeerOverridesResp, error)
+	// Used by peers to copy the overrides of an existing peer when they join the cluster
+	ListPeerOverrides(ctx context.Context, in *ListOverridesReq, opts ...grpc.CallOption) (*ListOverridesResp, error)
+	// Used by V1.RegisterLimits to register limit definitions on each peer
+	RegisterPeerLimits(ctx context.Context, in *RegisterLimitsReq, opts ...grpc.CallOption) (*RegisterLimitsResp, error)
+	// Used by peers to copy the limit definitions of an existing peer when they join the cluster
+	ListPeerLimits(ctx context.Context, in *ListPeerLimitsReq, opts ...grpc.CallOption) (*ListPeerLimitsResp, error)
+	// Used by AdminV1.GetLimitDrift to collect the drift of the rate limits owned by each peer
+	GetPeerLimitDrift(ctx context.Context, in *GetLimitDriftReq, opts ...grpc.CallOption) (*GetLimitDriftResp, error)
+	// Used by AdminV1.GetKeyHistory to collect the state and recent hits of a rate limit from each peer
+	GetPeerKeyHistory(ctx context.Context, in *GetKeyHistoryReq, opts ...grpc.CallOption) (*GetKeyHistoryResp, error)
+	// Used by AdminV1.ListNamespaces to collect the namespaces owned by each peer
+	ListPeerNamespaces(ctx context.Context, in *ListNamespacesReq, opts ...grpc.CallOption) (*ListNamespacesResp, error)
+	// Used by V1.HealthCheck to collect the build version of each peer
+	GetPeerVersion(ctx context.Context, in *GetPeerVersionReq, opts ...grpc.CallOption) (*GetPeerVersionResp, error)
+	// Used by AdminV1.GetTraffic to collect the traffic each peer sent to other peers
+	GetPeerTraffic(ctx context.Context, in *GetTrafficReq, opts ...grpc.CallOption) (*GetTrafficResp, error)
+	// Used by AdminV1.ReplayJournal to replay the journal of each peer
+	ReplayPeerJournal(ctx context.Context, in *ReplayJournalReq, opts ...grpc.CallOption) (*ReplayJournalResp, error)
+	// Used by AdminV1.PrepareShutdown to send the rate limits of a peer which is shutting down to
+	// their next owner
+	TransferPeerRateLimits(ctx context.Context, in *TransferPeerRateLimitsReq, opts ...grpc.CallOption) (*TransferPeerRateLimitsResp, error)
+	// Used by the owner of a rate limit listed in StandbyConfig.Names to replicate its state to the
+	// standby peer before responding
+	ReplicatePeerRateLimits(ctx context.Context, in *ReplicatePeerRateLimitsReq, opts ...grpc.CallOption) (*ReplicatePeerRateLimitsResp, error)
+	// Used by peers to answer the rate limits this peer has never seen without forwarding them, see
+	// Config.ColdKeyFilterInterval
+	GetPeerKeyFilter(ctx context.Context, in *GetPeerKeyFilterReq, opts ...grpc.CallOption) (*GetPeerKeyFilterResp, error)
+}
+
+type peersV1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeersV1Client(cc grpc.ClientConnInterface) PeersV1Client {
+	return &peersV1Client{cc}
+}
+
+func (c *peersV1Client) GetPeerRateLimits(ctx context.Context, in *GetPeerRateLimitsReq, opts ...grpc.CallOption) (*GetPeerRateLimitsResp, error) {
+	out := new(GetPeerRateLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) UpdatePeerGlobals(ctx context.Context, in *UpdatePeerGlobalsReq, opts ...grpc.CallOption) (*UpdatePeerGlobalsResp, error) {
+	out := new(UpdatePeerGlobalsResp)
+	err := c.cc.Invoke(ctx, PeersV1_UpdatePeerGlobals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ResetPeerRateLimits(ctx context.Context, in *ResetPeerRateLimitsReq, opts ...grpc.CallOption) (*ResetPeerRateLimitsResp, error) {
+	out := new(ResetPeerRateLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_ResetPeerRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ReservePeerRateLimit(ctx context.Context, in *ReserveRateLimitReq, opts ...grpc.CallOption) (*ReserveRateLimitResp, error) {
+	out := new(ReserveRateLimitResp)
+	err := c.cc.Invoke(ctx, PeersV1_ReservePeerRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) CommitPeerReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error) {
+	out := new(ReservationResp)
+	err := c.cc.Invoke(ctx, PeersV1_CommitPeerReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) CancelPeerReservation(ctx context.Context, in *ReservationReq, opts ...grpc.CallOption) (*ReservationResp, error) {
+	out := new(ReservationResp)
+	err := c.cc.Invoke(ctx, PeersV1_CancelPeerReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) RefundPeerRateLimit(ctx context.Context, in *RefundReq, opts ...grpc.CallOption) (*RefundResp, error) {
+	out := new(RefundResp)
+	err := c.cc.Invoke(ctx, PeersV1_RefundPeerRateLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) AcquirePeerLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error) {
+	out := new(LeaseResp)
+	err := c.cc.Invoke(ctx, PeersV1_AcquirePeerLease_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ReleasePeerLease(ctx context.Context, in *LeaseReq, opts ...grpc.CallOption) (*LeaseResp, error) {
+	out := new(LeaseResp)
+	err := c.cc.Invoke(ctx, PeersV1_ReleasePeerLease_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerNamespaceUsage(ctx context.Context, in *GetNamespaceUsageReq, opts ...grpc.CallOption) (*GetNamespaceUsageResp, error) {
+	out := new(GetNamespaceUsageResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerNamespaceUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) UpdatePeerOverrides(ctx context.Context, in *UpdatePeerOverridesReq, opts ...grpc.CallOption) (*UpdatePeerOverridesResp, error) {
+	out := new(UpdatePeerOverridesResp)
+	err := c.cc.Invoke(ctx, PeersV1_UpdatePeerOverrides_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ListPeerOverrides(ctx context.Context, in *ListOverridesReq, opts ...grpc.CallOption) (*ListOverridesResp, error) {
+	out := new(ListOverridesResp)
+	err := c.cc.Invoke(ctx, PeersV1_ListPeerOverrides_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) RegisterPeerLimits(ctx context.Context, in *RegisterLimitsReq, opts ...grpc.CallOption) (*RegisterLimitsResp, error) {
+	out := new(RegisterLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_RegisterPeerLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ListPeerLimits(ctx context.Context, in *ListPeerLimitsReq, opts ...grpc.CallOption) (*ListPeerLimitsResp, error) {
+	out := new(ListPeerLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_ListPeerLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerLimitDrift(ctx context.Context, in *GetLimitDriftReq, opts ...grpc.CallOption) (*GetLimitDriftResp, error) {
+	out := new(GetLimitDriftResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerLimitDrift_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerKeyHistory(ctx context.Context, in *GetKeyHistoryReq, opts ...grpc.CallOption) (*GetKeyHistoryResp, error) {
+	out := new(GetKeyHistoryResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerKeyHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ListPeerNamespaces(ctx context.Context, in *ListNamespacesReq, opts ...grpc.CallOption) (*ListNamespacesResp, error) {
+	out := new(ListNamespacesResp)
+	err := c.cc.Invoke(ctx, PeersV1_ListPeerNamespaces_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerVersion(ctx context.Context, in *GetPeerVersionReq, opts ...grpc.CallOption) (*GetPeerVersionResp, error) {
+	out := new(GetPeerVersionResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerVersion_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerTraffic(ctx context.Context, in *GetTrafficReq, opts ...grpc.CallOption) (*GetTrafficResp, error) {
+	out := new(GetTrafficResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerTraffic_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ReplayPeerJournal(ctx context.Context, in *ReplayJournalReq, opts ...grpc.CallOption) (*ReplayJournalResp, error) {
+	out := new(ReplayJournalResp)
+	err := c.cc.Invoke(ctx, PeersV1_ReplayPeerJournal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) TransferPeerRateLimits(ctx context.Context, in *TransferPeerRateLimitsReq, opts ...grpc.CallOption) (*TransferPeerRateLimitsResp, error) {
+	out := new(TransferPeerRateLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_TransferPeerRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) ReplicatePeerRateLimits(ctx context.Context, in *ReplicatePeerRateLimitsReq, opts ...grpc.CallOption) (*ReplicatePeerRateLimitsResp, error) {
+	out := new(ReplicatePeerRateLimitsResp)
+	err := c.cc.Invoke(ctx, PeersV1_ReplicatePeerRateLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersV1Client) GetPeerKeyFilter(ctx context.Context, in *GetPeerKeyFilterReq, opts ...grpc.CallOption) (*GetPeerKeyFilterResp, error) {
+	out := new(GetPeerKeyFilterResp)
+	err := c.cc.Invoke(ctx, PeersV1_GetPeerKeyFilter_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeersV1Server is the server API for PeersV1 service.
+// All implementations should embed UnimplementedPeersV1Server
+// for forward compatibility
+type PeersV1Server interface {
+	// Used by peers to relay batches of requests to an owner peer
+	GetPeerRateLimits(context.Context, *GetPeerRateLimitsReq) (*GetPeerRateLimitsResp, error)
+	// Used by owner peers to send global rate limit updates to non-owner peers
+	UpdatePeerGlobals(context.Context, *UpdatePeerGlobalsReq) (*UpdatePeerGlobalsResp, error)
+	// Used by AdminV1.ResetRateLimits to remove matching rate limits from each peer
+	ResetPeerRateLimits(context.Context, *ResetPeerRateLimitsReq) (*ResetPeerRateLimitsResp, error)
+	// Used by peers to relay reservations to the owner peer, which holds the reservation
+	ReservePeerRateLimit(context.Context, *ReserveRateLimitReq) (*ReserveRateLimitResp, error)
+	CommitPeerReservation(context.Context, *ReservationReq) (*ReservationResp, error)
+	CancelPeerReservation(context.Context, *ReservationReq) (*ReservationResp, error)
+	// Used by peers to relay refunds to the owner peer, which holds the refundable hits
+	RefundPeerRateLimit(context.Context, *RefundReq) (*RefundResp, error)
+	// Used by peers to relay leases to the owner peer, which holds the lease
+	AcquirePeerLease(context.Context, *LeaseReq) (*LeaseResp, error)
+	ReleasePeerLease(context.Context, *LeaseReq) (*LeaseResp, error)
+	// Used by AdminV1.GetNamespaceUsage to collect the usage of the rate limits owned by each peer
+	GetPeerNamespaceUsage(context.Context, *GetNamespaceUsageReq) (*GetNamespaceUsageResp, error)
+	// Used by AdminV1.SetOverride and AdminV1.DeleteOverride to update the overrides of each peer
+	UpdatePeerOverrides(context.Context, *UpdatePeerOverridesReq) (*UpdatePeerOverridesResp, error)
+	// Used by peers to copy the overrides of an existing peer when they join the cluster
+	ListPeerOverrides(context.Context, *ListOverridesReq) (*ListOverridesResp, error)
+	// Used by V1.RegisterLimits to register limit definitions on each peer
+	RegisterPeerLimits(context.Context, *RegisterLimitsReq) (*RegisterLimitsResp, error)
+	// Used by peers to copy the limit definitions of an existing peer when they join the cluster
+	ListPeerLimits(context.Context, *ListPeerLimitsReq) (*ListPeerLimitsResp, error)
+	// Used by AdminV1.GetLimitDrift to collect the drift of the rate limits owned by each peer
+	GetPeerLimitDrift(context.Context, *GetLimitDriftReq) (*GetLimitDriftResp, error)
+	// Used by AdminV1.GetKeyHistory to collect the state and recent hits of a rate limit from each peer
+	GetPeerKeyHistory(context.Context, *GetKeyHistoryReq) (*GetKeyHistoryResp, error)
+	// Used by AdminV1.ListNamespaces to collect the namespaces owned by each peer
+	ListPeerNamespaces(context.Context, *ListNamespacesReq) (*ListNamespacesResp, error)
+	// Used by V1.HealthCheck to collect the build version of each peer
+	GetPeerVersion(context.Context, *GetPeerVersionReq) (*GetPeerVersionResp, error)
+	// Used by AdminV1.GetTraffic to collect the traffic each peer sent to other peers
+	GetPeerTraffic(context.Context, *GetTrafficReq) (*GetTrafficResp, error)
+	// Used by AdminV1.ReplayJournal to replay the journal of each peer
+	ReplayPeerJournal(context.Context, *ReplayJournalReq) (*ReplayJournalResp, error)
+	// Used by AdminV1.PrepareShutdown to send the rate limits of a peer which is shutting down to
+	// their next owner
+	TransferPeerRateLimits(context.Context, *TransferPeerRateLimitsReq) (*TransferPeerRateLimitsResp, error)
+	// Used by the owner of a rate limit listed in StandbyConfig.Names to replicate its state to the
+	// standby peer before responding
+	ReplicatePeerRateLimits(context.Context, *ReplicatePeerRateLimitsReq) (*ReplicatePeerRateLimitsResp, error)
+	// Used by peers to answer the rate limits this peer has never seen without forwarding them, see
+	// Config.ColdKeyFilterInterval
+	GetPeerKeyFilter(context.Context, *GetPeerKeyFilterReq) (*GetPeerKeyFilterResp, error)
+}
+
+// UnimplementedPeersV1Server should be embedded to have forward compatible implementations.
+type UnimplementedPeersV1Server struct {
+}
+
+func (UnimplementedPeersV1Server) GetPeerRateLimits(context.Context, *GetPeerRateLimitsReq) (*GetPeerRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerRateLimits not implemented")
+}
+func (UnimplementedPeersV1Server) UpdatePeerGlobals(context.Context, *UpdatePeerGlobalsReq) (*UpdatePeerGlobalsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeerGlobals not implemented")
+}
+func (UnimplementedPeersV1Server) ResetPeerRateLimits(context.Context, *ResetPeerRateLimitsReq) (*ResetPeerRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPeerRateLimits not implemented")
+}
+func (UnimplementedPeersV1Server) ReservePeerRateLimit(context.Context, *ReserveRateLimitReq) (*ReserveRateLimitResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReservePeerRateLimit not implemented")
+}
+func (UnimplementedPeersV1Server) CommitPeerReservation(context.Context, *ReservationReq) (*ReservationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitPeerReservation not implemented")
+}
+func (UnimplementedPeersV1Server) CancelPeerReservation(context.Context, *ReservationReq) (*ReservationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelPeerReservation not implemented")
+}
+func (UnimplementedPeersV1Server) RefundPeerRateLimit(context.Context, *RefundReq) (*RefundResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundPeerRateLimit not implemented")
+}
+func (UnimplementedPeersV1Server) AcquirePeerLease(context.Context, *LeaseReq) (*LeaseResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcquirePeerLease not implemented")
+}
+func (UnimplementedPeersV1Server) ReleasePeerLease(context.Context, *LeaseReq) (*LeaseResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleasePeerLease not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerNamespaceUsage(context.Context, *GetNamespaceUsageReq) (*GetNamespaceUsageResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerNamespaceUsage not implemented")
+}
+func (UnimplementedPeersV1Server) UpdatePeerOverrides(context.Context, *UpdatePeerOverridesReq) (*UpdatePeerOverridesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeerOverrides not implemented")
+}
+func (UnimplementedPeersV1Server) ListPeerOverrides(context.Context, *ListOverridesReq) (*ListOverridesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerOverrides not implemented")
+}
+func (UnimplementedPeersV1Server) RegisterPeerLimits(context.Context, *RegisterLimitsReq) (*RegisterLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterPeerLimits not implemented")
+}
+func (UnimplementedPeersV1Server) ListPeerLimits(context.Context, *ListPeerLimitsReq) (*ListPeerLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerLimits not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerLimitDrift(context.Context, *GetLimitDriftReq) (*GetLimitDriftResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerLimitDrift not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerKeyHistory(context.Context, *GetKeyHistoryReq) (*GetKeyHistoryResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerKeyHistory not implemented")
+}
+func (UnimplementedPeersV1Server) ListPeerNamespaces(context.Context, *ListNamespacesReq) (*ListNamespacesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerNamespaces not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerVersion(context.Context, *GetPeerVersionReq) (*GetPeerVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerVersion not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerTraffic(context.Context, *GetTrafficReq) (*GetTrafficResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerTraffic not implemented")
+}
+func (UnimplementedPeersV1Server) ReplayPeerJournal(context.Context, *ReplayJournalReq) (*ReplayJournalResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayPeerJournal not implemented")
+}
+func (UnimplementedPeersV1Server) TransferPeerRateLimits(context.Context, *TransferPeerRateLimitsReq) (*TransferPeerRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferPeerRateLimits not implemented")
+}
+func (UnimplementedPeersV1Server) ReplicatePeerRateLimits(context.Context, *ReplicatePeerRateLimitsReq) (*ReplicatePeerRateLimitsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplicatePeerRateLimits not implemented")
+}
+func (UnimplementedPeersV1Server) GetPeerKeyFilter(context.Context, *GetPeerKeyFilterReq) (*GetPeerKeyFilterResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeerKeyFilter not implemented")
+}
+
+// UnsafePeersV1Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeersV1Server will
+// result in compilation errors.
+type UnsafePeersV1Server interface {
+	mustEmbedUnimplementedPeersV1Server()
+}
+
+func RegisterPeersV1Server(s grpc.ServiceRegistrar, srv PeersV1Server) {
+	s.RegisterService(&PeersV1_ServiceDesc, srv)
+}
+
+func _PeersV1_GetPeerRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerRateLimits(ctx, req.(*GetPeerRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_UpdatePeerGlobals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePeerGlobalsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).UpdatePeerGlobals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_UpdatePeerGlobals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).UpdatePeerGlobals(ctx, req.(*UpdatePeerGlobalsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ResetPeerRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPeerRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ResetPeerRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ResetPeerRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ResetPeerRateLimits(ctx, req.(*ResetPeerRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ReservePeerRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveRateLimitReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ReservePeerRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ReservePeerRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ReservePeerRateLimit(ctx, req.(*ReserveRateLimitReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_CommitPeerReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).CommitPeerReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_CommitPeerReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).CommitPeerReservation(ctx, req.(*ReservationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_CancelPeerReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).CancelPeerReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_CancelPeerReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).CancelPeerReservation(ctx, req.(*ReservationReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_RefundPeerRateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).RefundPeerRateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_RefundPeerRateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).RefundPeerRateLimit(ctx, req.(*RefundReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_AcquirePeerLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).AcquirePeerLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_AcquirePeerLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).AcquirePeerLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ReleasePeerLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ReleasePeerLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ReleasePeerLease_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ReleasePeerLease(ctx, req.(*LeaseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerNamespaceUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamespaceUsageReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerNamespaceUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerNamespaceUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerNamespaceUsage(ctx, req.(*GetNamespaceUsageReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_UpdatePeerOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePeerOverridesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).UpdatePeerOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_UpdatePeerOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).UpdatePeerOverrides(ctx, req.(*UpdatePeerOverridesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ListPeerOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOverridesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ListPeerOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ListPeerOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ListPeerOverrides(ctx, req.(*ListOverridesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_RegisterPeerLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).RegisterPeerLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_RegisterPeerLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).RegisterPeerLimits(ctx, req.(*RegisterLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ListPeerLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeerLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ListPeerLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ListPeerLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ListPeerLimits(ctx, req.(*ListPeerLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerLimitDrift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLimitDriftReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerLimitDrift(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerLimitDrift_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerLimitDrift(ctx, req.(*GetLimitDriftReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerKeyHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyHistoryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerKeyHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerKeyHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerKeyHistory(ctx, req.(*GetKeyHistoryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ListPeerNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ListPeerNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ListPeerNamespaces_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ListPeerNamespaces(ctx, req.(*ListNamespacesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerVersionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerVersion(ctx, req.(*GetPeerVersionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerTraffic_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrafficReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerTraffic(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerTraffic_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerTraffic(ctx, req.(*GetTrafficReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ReplayPeerJournal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayJournalReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ReplayPeerJournal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ReplayPeerJournal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ReplayPeerJournal(ctx, req.(*ReplayJournalReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_TransferPeerRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferPeerRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).TransferPeerRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_TransferPeerRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).TransferPeerRateLimits(ctx, req.(*TransferPeerRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_ReplicatePeerRateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicatePeerRateLimitsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).ReplicatePeerRateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_ReplicatePeerRateLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).ReplicatePeerRateLimits(ctx, req.(*ReplicatePeerRateLimitsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PeersV1_GetPeerKeyFilter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeerKeyFilterReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersV1Server).GetPeerKeyFilter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeersV1_GetPeerKeyFilter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersV1Server).GetPeerKeyFilter(ctx, req.(*GetPeerKeyFilterReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeersV1_ServiceDesc is the grpc.ServiceDesc for PeersV1 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeersV1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.gubernator.PeersV1",
+	HandlerType: (*PeersV1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPeerRateLimits",
+			Handler:    _PeersV1_GetPeerRateLimits_Handler,
+		},
+		{
+			MethodName: "UpdatePeerGlobals",
+			Handler:    _PeersV1_UpdatePeerGlobals_Handler,
+		},
+		{
+			MethodName: "ResetPeerRateLimits",
+			Handler:    _PeersV1_ResetPeerRateLimits_Handler,
+		},
+		{
+			MethodName: "ReservePeerRateLimit",
+			Handler:    _PeersV1_ReservePeerRateLimit_Handler,
+		},
+		{
+			MethodName: "CommitPeerReservation",
+			Handler:    _PeersV1_CommitPeerReservation_Handler,
+		},
+		{
+			MethodName: "CancelPeerReservation",
+			Handler:    _PeersV1_CancelPeerReservation_Handler,
+		},
+		{
+			MethodName: "RefundPeerRateLimit",
+			Handler:    _PeersV1_RefundPeerRateLimit_Handler,
+		},
+		{
+			MethodName: "AcquirePeerLease",
+			Handler:    _PeersV1_AcquirePeerLease_Handler,
+		},
+		{
+			MethodName: "ReleasePeerLease",
+			Handler:    _PeersV1_ReleasePeerLease_Handler,
+		},
+		{
+			MethodName: "GetPeerNamespaceUsage",
+			Handler:    _PeersV1_GetPeerNamespaceUsage_Handler,
+		},
+		{
+			MethodName: "UpdatePeerOverrides",
+			Handler:    _PeersV1_UpdatePeerOverrides_Handler,
+		},
+		{
+			MethodName: "ListPeerOverrides",
+			Handler:    _PeersV1_ListPeerOverrides_Handler,
+		},
+		{
+			MethodName: "RegisterPeerLimits",
+			Handler:    _PeersV1_RegisterPeerLimits_Handler,
+		},
+		{
+			MethodName: "ListPeerLimits",
+			Handler:    _PeersV1_ListPeerLimits_Handler,
+		},
+		{
+			MethodName: "GetPeerLimitDrift",
+			Handler:    _PeersV1_GetPeerLimitDrift_Handler,
+		},
+		{
+			MethodName: "GetPeerKeyHistory",
+			Handler:    _PeersV1_GetPeerKeyHistory_Handler,
+		},
+		{
+			MethodName: "ListPeerNamespaces",
+			Handler:    _PeersV1_ListPeerNamespaces_Handler,
+		},
+		{
+			MethodName: "GetPeerVersion",
+			Handler:    _PeersV1_GetPeerVersion_Handler,
+		},
+		{
+			MethodName: "GetPeerTraffic",
+			Handler:    _PeersV1_GetPeerTraffic_Handler,
+		},
+		{
+			MethodName: "ReplayPeerJournal",
+			Handler:    _PeersV1_ReplayPeerJournal_Handler,
+		},
+		{
+			MethodName: "TransferPeerRateLimits",
+			Handler:    _PeersV1_TransferPeerRateLimits_Handler,
+		},
+		{
+			MethodName: "ReplicatePeerRateLimits",
+			Handler:    _PeersV1_ReplicatePeerRateLimits_Handler,
+		},
+		{
+			MethodName: "GetPeerKeyFilter",
+			Handler:    _PeersV1_GetPeerKeyFilter_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "peers.proto",
+}
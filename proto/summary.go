@@ -0,0 +1,48 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proto
+
+import "sort"
+
+// NewBatchSummary counts the responses to the rate limits of `r` by outcome and collects the names
+// of the rate limits which returned an error
+func NewBatchSummary(r *GetRateLimitsReq, resps []*RateLimitResp) *BatchSummary {
+	summary := &BatchSummary{}
+	errored := make(map[string]bool)
+	for i, rl := range resps {
+		switch {
+		case rl == nil:
+			continue
+		case rl.Error != "":
+			summary.Errors++
+			name := r.Requests[i].Name
+			if name == "" {
+				name = r.Defaults.GetName()
+			}
+			if !errored[name] {
+				errored[name] = true
+				summary.ErrorNamespaces = append(summary.ErrorNamespaces, name)
+			}
+		case rl.Status == Status_OVER_LIMIT:
+			summary.OverLimit++
+		default:
+			summary.UnderLimit++
+		}
+	}
+	sort.Strings(summary.ErrorNamespaces)
+	return summary
+}
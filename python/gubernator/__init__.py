@@ -1,8 +1,11 @@
 # This code is py3.7 and py2.7 compatible
 
-import gubernator.ratelimit_pb2_grpc as pb_grpc
+import gubernator.gubernator_pb2 as pb
+import gubernator.gubernator_pb2_grpc as pb_grpc
+from gubernator.ring import ReplicatedConsistentHash, DEFAULT_REPLICAS, hash_key
 from datetime import datetime
 
+import random
 import time
 import grpc
 
@@ -10,6 +13,13 @@ MILLISECOND = 1
 SECOND = MILLISECOND * 1000
 MINUTE = SECOND * 60
 
+# The most rate limits a gubernator instance accepts in a single request
+MAX_BATCH_SIZE = 1000
+
+# Errors which mean the request did not reach the instance, as such it is safe
+# to send the request again without counting the hits twice
+RETRY_CODES = (grpc.StatusCode.UNAVAILABLE,)
+
 
 def sleep_until_reset(reset_time):
     now = datetime.now()
@@ -18,4 +28,104 @@ def sleep_until_reset(reset_time):
 
 def V1Client(endpoint='127.0.0.1:9090'):
     channel = grpc.insecure_channel(endpoint)
-    return pb_grpc.RateLimitServiceV1Stub(channel)
+    return pb_grpc.V1Stub(channel)
+
+
+class Client(object):
+    """A client of a cluster of gubernator instances.
+
+    Rate limits are sent to the instance which owns them, picked the same way
+    the instances pick the owner, such that requests are not forwarded between
+    instances. Rate limits owned by the same instance are batched into requests
+    of at most `batch_size` rate limits. A request which fails with one of the
+    `RETRY_CODES` is sent again up to `retries` times, waiting `backoff`
+    seconds, doubled on each attempt, in between.
+
+        client = gubernator.Client(['10.0.0.1:1051', '10.0.0.2:1051'])
+        resp = client.get_rate_limits([pb.RateLimitReq(
+            name='requests_per_sec', unique_key='account:1234',
+            hits=1, limit=10, duration=gubernator.SECOND)])
+    """
+
+    def __init__(self, peers, batch_size=MAX_BATCH_SIZE, retries=3,
+                 backoff=0.1, replicas=DEFAULT_REPLICAS,
+                 channel_factory=grpc.insecure_channel):
+        if not peers:
+            raise ValueError('at least one peer is required')
+        self._batch_size = max(batch_size, 1)
+        self._retries = retries
+        self._backoff = backoff
+        self._channel_factory = channel_factory
+        self._replicas = replicas
+        self._channels = {}
+        self._stubs = {}
+        self.set_peers(peers)
+
+    def set_peers(self, peers):
+        """Replaces the instances of the cluster with `peers`, the GRPC
+        addresses of the instances as advertised to each other."""
+        ring = ReplicatedConsistentHash(self._replicas)
+        for address in peers:
+            ring.add(address)
+            if address not in self._stubs:
+                channel = self._channel_factory(address)
+                self._channels[address] = channel
+                self._stubs[address] = pb_grpc.V1Stub(channel)
+        for address in list(self._stubs):
+            if address not in peers:
+                self._channels.pop(address).close()
+                del self._stubs[address]
+        self._ring = ring
+
+    def close(self):
+        for channel in self._channels.values():
+            channel.close()
+        self._channels = {}
+        self._stubs = {}
+
+    def owner(self, req):
+        """Returns the address of the instance which owns the rate limit."""
+        return self._ring.get(hash_key(req))
+
+    def get_rate_limits(self, requests, timeout=None, **kwargs):
+        """Returns a GetRateLimitsResp with a response for each of the
+        `requests`, in the order of the requests. Additional `kwargs` are set
+        on each GetRateLimitsReq, IE: `fail_fast=True`."""
+        by_owner = {}
+        for i, req in enumerate(requests):
+            by_owner.setdefault(self.owner(req), []).append(i)
+
+        responses = [None] * len(requests)
+        for address, indexes in by_owner.items():
+            for start in range(0, len(indexes), self._batch_size):
+                batch = indexes[start:start + self._batch_size]
+                req = pb.GetRateLimitsReq(
+                    requests=[requests[i] for i in batch], **kwargs)
+                resp = self._call(address, 'GetRateLimits', req, timeout)
+                if len(resp.responses) != len(batch):
+                    raise RuntimeError(
+                        "expected '{}' responses; got '{}'".format(
+                            len(batch), len(resp.responses)))
+                for i, r in zip(batch, resp.responses):
+                    responses[i] = r
+        return pb.GetRateLimitsResp(responses=responses)
+
+    def health_check(self, timeout=None):
+        """Returns the health of a random instance of the cluster."""
+        address = random.choice(self._ring.peers())
+        return self._call(address, 'HealthCheck', pb.HealthCheckReq(),
+                          timeout)
+
+    def _call(self, address, method, req, timeout):
+        backoff = self._backoff
+        attempt = 0
+        while True:
+            try:
+                return getattr(self._stubs[address], method)(
+                    req, timeout=timeout)
+            except grpc.RpcError as e:
+                if e.code() not in RETRY_CODES or attempt >= self._retries:
+                    raise
+            attempt += 1
+            time.sleep(backoff)
+            backoff *= 2
@@ -0,0 +1,133 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# source: admin.proto
+# Protobuf Python Version: 5.26.0
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+from google.api import annotations_pb2 as google_dot_api_dot_annotations__pb2
+import gubernator_pb2 as gubernator__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x61\x64min.proto\x12\rpb.gubernator\x1a\x1cgoogle/api/annotations.proto\x1a\x10gubernator.proto\"R\n\x12ResetRateLimitsReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\x12\x1b\n\tname_glob\x18\x02 \x01(\tR\x08nameGlob\"G\n\x13ResetRateLimitsResp\x12\x18\n\x07removed\x18\x01 \x01(\x03R\x07removed\x12\x16\n\x06\x65rrors\x18\x02 \x03(\tR\x06\x65rrors\"R\n\x14GetNamespaceUsageReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\x12\x19\n\x08top_keys\x18\x02 \x01(\x05R\x07topKeys\"=\n\x08KeyUsage\x12\x1d\n\nunique_key\x18\x01 \x01(\tR\tuniqueKey\x12\x12\n\x04hits\x18\x02 \x01(\x03R\x04hits\"\xb0\x01\n\x0eNamespaceUsage\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n\x04hits\x18\x02 \x01(\x03R\x04hits\x12\x1d\n\nover_limit\x18\x03 \x01(\x03R\toverLimit\x12#\n\rdistinct_keys\x18\x04 \x01(\x03R\x0c\x64istinctKeys\x12\x32\n\x08top_keys\x18\x05 \x03(\x0b\x32\x17.pb.gubernator.KeyUsageR\x07topKeys\"\x86\x01\n\x15GetNamespaceUsageResp\x12=\n\nnamespaces\x18\x01 \x03(\x0b\x32\x1d.pb.gubernator.NamespaceUsageR\nnamespaces\x12\x16\n\x06window\x18\x02 \x01(\x03R\x06window\x12\x16\n\x06\x65rrors\x18\x03 \x03(\tR\x06\x65rrors\"\xa9\x01\n\x08Override\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x35\n\x06\x61\x63tion\x18\x03 \x01(\x0e\x32\x1d.pb.gubernator.OverrideActionR\x06\x61\x63tion\x12\x1b\n\texpire_at\x18\x04 \x01(\x03R\x08\x65xpireAt\x12\x16\n\x06reason\x18\x05 \x01(\tR\x06reason\"E\n\x0eSetOverrideReq\x12\x33\n\x08override\x18\x01 \x01(\x0b\x32\x17.pb.gubernator.OverrideR\x08override\")\n\x0fSetOverrideResp\x12\x16\n\x06\x65rrors\x18\x01 \x03(\tR\x06\x65rrors\"F\n\x11\x44\x65leteOverrideReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\",\n\x12\x44\x65leteOverrideResp\x12\x16\n\x06\x65rrors\x18\x01 \x03(\tR\x06\x65rrors\"\x12\n\x10ListOverridesReq\"J\n\x11ListOverridesResp\x12\x35\n\toverrides\x18\x01 \x03(\x0b\x32\x17.pb.gubernator.OverrideR\toverrides\"\\\n\x11PauseNamespaceReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n\texpire_at\x18\x02 \x01(\x03R\x08\x65xpireAt\x12\x16\n\x06reason\x18\x03 \x01(\tR\x06reason\",\n\x12PauseNamespaceResp\x12\x16\n\x06\x65rrors\x18\x01 \x03(\tR\x06\x65rrors\"(\n\x12ResumeNamespaceReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\"-\n\x13ResumeNamespaceResp\x12\x16\n\x06\x65rrors\x18\x01 \x03(\tR\x06\x65rrors\"3\n\x10GetLimitDriftReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\"\x91\x01\n\x0fLimitDefinition\x12\x14\n\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x02 \x01(\x03R\x08\x64uration\x12\x36\n\talgorithm\x18\x03 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x14\n\x05\x62urst\x18\x04 \x01(\x03R\x05\x62urst\"\xee\x01\n\nLimitDrift\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12:\n\x08previous\x18\x03 \x01(\x0b\x32\x1e.pb.gubernator.LimitDefinitionR\x08previous\x12\x38\n\x07\x63urrent\x18\x04 \x01(\x0b\x32\x1e.pb.gubernator.LimitDefinitionR\x07\x63urrent\x12\x18\n\x07\x63hanges\x18\x05 \x01(\x03R\x07\x63hanges\x12\x1d\n\nchanged_at\x18\x06 \x01(\x03R\tchangedAt\"^\n\x11GetLimitDriftResp\x12\x31\n\x06\x64rifts\x18\x01 \x03(\x0b\x32\x19.pb.gubernator.LimitDriftR\x06\x64rifts\x12\x16\n\x06\x65rrors\x18\x02 \x03(\tR\x06\x65rrors\"4\n\x11ListNamespacesReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\"^\n\rNamespaceInfo\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n\x07\x65ntries\x18\x02 \x01(\x03R\x07\x65ntries\x12\x1f\n\x0blast_access\x18\x03 \x01(\x03R\nlastAccess\"j\n\x12ListNamespacesResp\x12<\n\nnamespaces\x18\x01 \x03(\x0b\x32\x1c.pb.gubernator.NamespaceInfoR\nnamespaces\x12\x16\n\x06\x65rrors\x18\x02 \x03(\tR\x06\x65rrors\"\x0f\n\rGetTrafficReq\"\xc9\x01\n\x0bPeerTraffic\x12\x12\n\x04\x66rom\x18\x01 \x01(\tR\x04\x66rom\x12\x0e\n\x02to\x18\x02 \x01(\tR\x02to\x12\x12\n\x04rpcs\x18\x03 \x01(\x03R\x04rpcs\x12\x16\n\x06\x65rrors\x18\x04 \x01(\x03R\x06\x65rrors\x12\x1d\n\nbytes_sent\x18\x05 \x01(\x03R\tbytesSent\x12%\n\x0e\x62ytes_received\x18\x06 \x01(\x03R\rbytesReceived\x12$\n\x0ep99_latency_ms\x18\x07 \x01(\x01R\x0cp99LatencyMs\"^\n\x0eGetTrafficResp\x12\x34\n\x07traffic\x18\x01 \x03(\x0b\x32\x1a.pb.gubernator.PeerTrafficR\x07traffic\x12\x16\n\x06\x65rrors\x18\x02 \x03(\tR\x06\x65rrors\"I\n\x10ReplayJournalReq\x12\x14\n\x05since\x18\x01 \x01(\x03R\x05since\x12\x1f\n\x0bname_prefix\x18\x02 \x01(\tR\nnamePrefix\"\x14\n\x12PrepareShutdownReq\"\x83\x01\n\x13PrepareShutdownResp\x12 \n\x0btransferred\x18\x01 \x01(\x03R\x0btransferred\x12\x1a\n\x08\x65xisting\x18\x02 \x01(\x03R\x08\x65xisting\x12\x16\n\x06\x66\x61iled\x18\x03 \x01(\x03R\x06\x66\x61iled\x12\x16\n\x06\x65rrors\x18\x04 \x03(\tR\x06\x65rrors\"y\n\x11ReplayJournalResp\x12\x1a\n\x08replayed\x18\x01 \x01(\x03R\x08replayed\x12\x18\n\x07\x65xpired\x18\x02 \x01(\x03R\x07\x65xpired\x12\x16\n\x06\x66\x61iled\x18\x03 \x01(\x03R\x06\x66\x61iled\x12\x16\n\x06\x65rrors\x18\x04 \x03(\tR\x06\x65rrors\"\x95\x01\n\x11ListRateLimitsReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\x12*\n\x11unique_key_prefix\x18\x02 \x01(\tR\x0funiqueKeyPrefix\x12\x1b\n\tpage_size\x18\x03 \x01(\x05R\x08pageSize\x12\x16\n\x06\x63ursor\x18\x04 \x01(\tR\x06\x63ursor\"\xd5\x02\n\x0eRateLimitState\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x10\n\x03key\x18\x03 \x01(\tR\x03key\x12\x36\n\talgorithm\x18\x04 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12-\n\x06status\x18\x05 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12\x14\n\x05limit\x18\x06 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x07 \x01(\x03R\x08\x64uration\x12\x1c\n\tremaining\x18\x08 \x01(\x03R\tremaining\x12\x14\n\x05\x62urst\x18\t \x01(\x03R\x05\x62urst\x12\x1b\n\texpire_at\x18\n \x01(\x03R\x08\x65xpireAt\x12\x14\n\x05owned\x18\x0b \x01(\x08R\x05owned\"u\n\x12ListRateLimitsResp\x12>\n\x0brate_limits\x18\x01 \x03(\x0b\x32\x1d.pb.gubernator.RateLimitStateR\nrateLimits\x12\x1f\n\x0bnext_cursor\x18\x02 \x01(\tR\nnextCursor\"E\n\x10GetKeyHistoryReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\"\xe3\x01\n\rKeyTransition\x12\x12\n\x04time\x18\x01 \x01(\x03R\x04time\x12\x12\n\x04hits\x18\x02 \x01(\x03R\x04hits\x12-\n\x06status\x18\x03 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12\x1c\n\tremaining\x18\x04 \x01(\x03R\tremaining\x12\x14\n\x05limit\x18\x05 \x01(\x03R\x05limit\x12\x14\n\x05owner\x18\x06 \x01(\tR\x05owner\x12\x12\n\x04peer\x18\x07 \x01(\tR\x04peer\x12\x1d\n\nrequest_id\x18\x08 \x01(\tR\trequestId\"\xa9\x01\n\x11GetKeyHistoryResp\x12<\n\nrate_limit\x18\x01 \x01(\x0b\x32\x1d.pb.gubernator.RateLimitStateR\trateLimit\x12>\n\x0btransitions\x18\x02 \x03(\x0b\x32\x1c.pb.gubernator.KeyTransitionR\x0btransitions\x12\x16\n\x06\x65rrors\x18\x03 \x03(\tR\x06\x65rrors*0\n\x0eOverrideAction\x12\x08\n\x04\x44\x45NY\x10\x00\x12\t\n\x05\x41LLOW\x10\x01\x12\t\n\x05PAUSE\x10\x02\x32\xbe\r\n\x07\x41\x64minV1\x12~\n\x0fResetRateLimits\x12!.pb.gubernator.ResetRateLimitsReq\x1a\".pb.gubernator.ResetRateLimitsResp\"$\x82\xd3\xe4\x93\x02\x1e\"\x19/v1/admin/ResetRateLimits:\x01*\x12\x86\x01\n\x11GetNamespaceUsage\x12#.pb.gubernator.GetNamespaceUsageReq\x1a$.pb.gubernator.GetNamespaceUsageResp\"&\x82\xd3\xe4\x93\x02 \"\x1b/v1/admin/GetNamespaceUsage:\x01*\x12n\n\x0bSetOverride\x12\x1d.pb.gubernator.SetOverrideReq\x1a\x1e.pb.gubernator.SetOverrideResp\" \x82\xd3\xe4\x93\x02\x1a\"\x15/v1/admin/SetOverride:\x01*\x12z\n\x0e\x44\x65leteOverride\x12 .pb.gubernator.DeleteOverrideReq\x1a!.pb.gubernator.DeleteOverrideResp\"#\x82\xd3\xe4\x93\x02\x1d\"\x18/v1/admin/DeleteOverride:\x01*\x12v\n\rListOverrides\x12\x1f.pb.gubernator.ListOverridesReq\x1a .pb.gubernator.ListOverridesResp\"\"\x82\xd3\xe4\x93\x02\x1c\"\x17/v1/admin/ListOverrides:\x01*\x12z\n\x0ePauseNamespace\x12 .pb.gubernator.PauseNamespaceReq\x1a!.pb.gubernator.PauseNamespaceResp\"#\x82\xd3\xe4\x93\x02\x1d\"\x18/v1/admin/PauseNamespace:\x01*\x12~\n\x0fResumeNamespace\x12!.pb.gubernator.ResumeNamespaceReq\x1a\".pb.gubernator.ResumeNamespaceResp\"$\x82\xd3\xe4\x93\x02\x1e\"\x19/v1/admin/ResumeNamespace:\x01*\x12v\n\rGetLimitDrift\x12\x1f.pb.gubernator.GetLimitDriftReq\x1a .pb.gubernator.GetLimitDriftResp\"\"\x82\xd3\xe4\x93\x02\x1c\"\x17/v1/admin/GetLimitDrift:\x01*\x12z\n\x0eListNamespaces\x12 .pb.gubernator.ListNamespacesReq\x1a!.pb.gubernator.ListNamespacesResp\"#\x82\xd3\xe4\x93\x02\x1d\"\x18/v1/admin/ListNamespaces:\x01*\x12j\n\nGetTraffic\x12\x1c.pb.gubernator.GetTrafficReq\x1a\x1d.pb.gubernator.GetTrafficResp\"\x1f\x82\xd3\xe4\x93\x02\x19\"\x14/v1/admin/GetTraffic:\x01*\x12v\n\rReplayJournal\x12\x1f.pb.gubernator.ReplayJournalReq\x1a .pb.gubernator.ReplayJournalResp\"\"\x82\xd3\xe4\x93\x02\x1c\"\x17/v1/admin/ReplayJournal:\x01*\x12~\n\x0fPrepareShutdown\x12!.pb.gubernator.PrepareShutdownReq\x1a\".pb.gubernator.PrepareShutdownResp\"$\x82\xd3\xe4\x93\x02\x1e\"\x19/v1/admin/PrepareShutdown:\x01*\x12z\n\x0eListRateLimits\x12 .pb.gubernator.ListRateLimitsReq\x1a!.pb.gubernator.ListRateLimitsResp\"#\x82\xd3\xe4\x93\x02\x1d\"\x18/v1/admin/ListRateLimits:\x01*\x12v\n\rGetKeyHistory\x12\x1f.pb.gubernator.GetKeyHistoryReq\x1a .pb.gubernator.GetKeyHistoryResp\"\"\x82\xd3\xe4\x93\x02\x1c\"\x17/v1/admin/GetKeyHistory:\x01*B1Z,github.com/gubernator-io/gubernator/v2/proto\x80\x01\x01\x62\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'admin_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z,github.com/gubernator-io/gubernator/v2/proto\200\001\001'
+  _globals['_ADMINV1'].methods_by_name['ResetRateLimits']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ResetRateLimits']._serialized_options = b'\202\323\344\223\002\036\"\031/v1/admin/ResetRateLimits:\001*'
+  _globals['_ADMINV1'].methods_by_name['GetNamespaceUsage']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['GetNamespaceUsage']._serialized_options = b'\202\323\344\223\002 \"\033/v1/admin/GetNamespaceUsage:\001*'
+  _globals['_ADMINV1'].methods_by_name['SetOverride']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['SetOverride']._serialized_options = b'\202\323\344\223\002\032\"\025/v1/admin/SetOverride:\001*'
+  _globals['_ADMINV1'].methods_by_name['DeleteOverride']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['DeleteOverride']._serialized_options = b'\202\323\344\223\002\035\"\030/v1/admin/DeleteOverride:\001*'
+  _globals['_ADMINV1'].methods_by_name['ListOverrides']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ListOverrides']._serialized_options = b'\202\323\344\223\002\034\"\027/v1/admin/ListOverrides:\001*'
+  _globals['_ADMINV1'].methods_by_name['PauseNamespace']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['PauseNamespace']._serialized_options = b'\202\323\344\223\002\035\"\030/v1/admin/PauseNamespace:\001*'
+  _globals['_ADMINV1'].methods_by_name['ResumeNamespace']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ResumeNamespace']._serialized_options = b'\202\323\344\223\002\036\"\031/v1/admin/ResumeNamespace:\001*'
+  _globals['_ADMINV1'].methods_by_name['GetLimitDrift']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['GetLimitDrift']._serialized_options = b'\202\323\344\223\002\034\"\027/v1/admin/GetLimitDrift:\001*'
+  _globals['_ADMINV1'].methods_by_name['ListNamespaces']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ListNamespaces']._serialized_options = b'\202\323\344\223\002\035\"\030/v1/admin/ListNamespaces:\001*'
+  _globals['_ADMINV1'].methods_by_name['GetTraffic']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['GetTraffic']._serialized_options = b'\202\323\344\223\002\031\"\024/v1/admin/GetTraffic:\001*'
+  _globals['_ADMINV1'].methods_by_name['ReplayJournal']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ReplayJournal']._serialized_options = b'\202\323\344\223\002\034\"\027/v1/admin/ReplayJournal:\001*'
+  _globals['_ADMINV1'].methods_by_name['PrepareShutdown']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['PrepareShutdown']._serialized_options = b'\202\323\344\223\002\036\"\031/v1/admin/PrepareShutdown:\001*'
+  _globals['_ADMINV1'].methods_by_name['ListRateLimits']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['ListRateLimits']._serialized_options = b'\202\323\344\223\002\035\"\030/v1/admin/ListRateLimits:\001*'
+  _globals['_ADMINV1'].methods_by_name['GetKeyHistory']._loaded_options = None
+  _globals['_ADMINV1'].methods_by_name['GetKeyHistory']._serialized_options = b'\202\323\344\223\002\034\"\027/v1/admin/GetKeyHistory:\001*'
+  _globals['_OVERRIDEACTION']._serialized_start=3982
+  _globals['_OVERRIDEACTION']._serialized_end=4030
+  _globals['_RESETRATELIMITSREQ']._serialized_start=78
+  _globals['_RESETRATELIMITSREQ']._serialized_end=160
+  _globals['_RESETRATELIMITSRESP']._serialized_start=162
+  _globals['_RESETRATELIMITSRESP']._serialized_end=233
+  _globals['_GETNAMESPACEUSAGEREQ']._serialized_start=235
+  _globals['_GETNAMESPACEUSAGEREQ']._serialized_end=317
+  _globals['_KEYUSAGE']._serialized_start=319
+  _globals['_KEYUSAGE']._serialized_end=380
+  _globals['_NAMESPACEUSAGE']._serialized_start=383
+  _globals['_NAMESPACEUSAGE']._serialized_end=559
+  _globals['_GETNAMESPACEUSAGERESP']._serialized_start=562
+  _globals['_GETNAMESPACEUSAGERESP']._serialized_end=696
+  _globals['_OVERRIDE']._serialized_start=699
+  _globals['_OVERRIDE']._serialized_end=868
+  _globals['_SETOVERRIDEREQ']._serialized_start=870
+  _globals['_SETOVERRIDEREQ']._serialized_end=939
+  _globals['_SETOVERRIDERESP']._serialized_start=941
+  _globals['_SETOVERRIDERESP']._serialized_end=982
+  _globals['_DELETEOVERRIDEREQ']._serialized_start=984
+  _globals['_DELETEOVERRIDEREQ']._serialized_end=1054
+  _globals['_DELETEOVERRIDERESP']._serialized_start=1056
+  _globals['_DELETEOVERRIDERESP']._serialized_end=1100
+  _globals['_LISTOVERRIDESREQ']._serialized_start=1102
+  _globals['_LISTOVERRIDESREQ']._serialized_end=1120
+  _globals['_LISTOVERRIDESRESP']._serialized_start=1122
+  _globals['_LISTOVERRIDESRESP']._serialized_end=1196
+  _globals['_PAUSENAMESPACEREQ']._serialized_start=1198
+  _globals['_PAUSENAMESPACEREQ']._serialized_end=1290
+  _globals['_PAUSENAMESPACERESP']._serialized_start=1292
+  _globals['_PAUSENAMESPACERESP']._serialized_end=1336
+  _globals['_RESUMENAMESPACEREQ']._serialized_start=1338
+  _globals['_RESUMENAMESPACEREQ']._serialized_end=1378
+  _globals['_RESUMENAMESPACERESP']._serialized_start=1380
+  _globals['_RESUMENAMESPACERESP']._serialized_end=1425
+  _globals['_GETLIMITDRIFTREQ']._serialized_start=1427
+  _globals['_GETLIMITDRIFTREQ']._serialized_end=1478
+  _globals['_LIMITDEFINITION']._serialized_start=1481
+  _globals['_LIMITDEFINITION']._serialized_end=1626
+  _globals['_LIMITDRIFT']._serialized_start=1629
+  _globals['_LIMITDRIFT']._serialized_end=1867
+  _globals['_GETLIMITDRIFTRESP']._serialized_start=1869
+  _globals['_GETLIMITDRIFTRESP']._serialized_end=1963
+  _globals['_LISTNAMESPACESREQ']._serialized_start=1965
+  _globals['_LISTNAMESPACESREQ']._serialized_end=2017
+  _globals['_NAMESPACEINFO']._serialized_start=2019
+  _globals['_NAMESPACEINFO']._serialized_end=2113
+  _globals['_LISTNAMESPACESRESP']._serialized_start=2115
+  _globals['_LISTNAMESPACESRESP']._serialized_end=2221
+  _globals['_GETTRAFFICREQ']._serialized_start=2223
+  _globals['_GETTRAFFICREQ']._serialized_end=2238
+  _globals['_PEERTRAFFIC']._serialized_start=2241
+  _globals['_PEERTRAFFIC']._serialized_end=2442
+  _globals['_GETTRAFFICRESP']._serialized_start=2444
+  _globals['_GETTRAFFICRESP']._serialized_end=2538
+  _globals['_REPLAYJOURNALREQ']._serialized_start=2540
+  _globals['_REPLAYJOURNALREQ']._serialized_end=2613
+  _globals['_PREPARESHUTDOWNREQ']._serialized_start=2615
+  _globals['_PREPARESHUTDOWNREQ']._serialized_end=2635
+  _globals['_PREPARESHUTDOWNRESP']._serialized_start=2638
+  _globals['_PREPARESHUTDOWNRESP']._serialized_end=2769
+  _globals['_REPLAYJOURNALRESP']._serialized_start=2771
+  _globals['_REPLAYJOURNALRESP']._serialized_end=2892
+  _globals['_LISTRATELIMITSREQ']._serialized_start=2895
+  _globals['_LISTRATELIMITSREQ']._serialized_end=3044
+  _globals['_RATELIMITSTATE']._serialized_start=3047
+  _globals['_RATELIMITSTATE']._serialized_end=3388
+  _globals['_LISTRATELIMITSRESP']._serialized_start=3390
+  _globals['_LISTRATELIMITSRESP']._serialized_end=3507
+  _globals['_GETKEYHISTORYREQ']._serialized_start=3509
+  _globals['_GETKEYHISTORYREQ']._serialized_end=3578
+  _globals['_KEYTRANSITION']._serialized_start=3581
+  _globals['_KEYTRANSITION']._serialized_end=3808
+  _globals['_GETKEYHISTORYRESP']._serialized_start=3811
+  _globals['_GETKEYHISTORYRESP']._serialized_end=3980
+  _globals['_ADMINV1']._serialized_start=4033
+  _globals['_ADMINV1']._serialized_end=5759
+# @@protoc_insertion_point(module_scope)
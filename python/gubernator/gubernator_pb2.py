@@ -15,44 +15,114 @@ _sym_db = _symbol_database.Default()
 from google.api import annotations_pb2 as google_dot_api_dot_annotations__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10gubernator.proto\x12\rpb.gubernator\x1a\x1cgoogle/api/annotations.proto\"K\n\x10GetRateLimitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\"O\n\x11GetRateLimitsResp\x12:\n\tresponses\x18\x01 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\tresponses\"\xc1\x03\n\x0cRateLimitReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x12\n\x04hits\x18\x03 \x01(\x03R\x04hits\x12\x14\n\x05limit\x18\x04 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x05 \x01(\x03R\x08\x64uration\x12\x36\n\talgorithm\x18\x06 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x33\n\x08\x62\x65havior\x18\x07 \x01(\x0e\x32\x17.pb.gubernator.BehaviorR\x08\x62\x65havior\x12\x14\n\x05\x62urst\x18\x08 \x01(\x03R\x05\x62urst\x12\x45\n\x08metadata\x18\t \x03(\x0b\x32).pb.gubernator.RateLimitReq.MetadataEntryR\x08metadata\x12\"\n\ncreated_at\x18\n \x01(\x03H\x00R\tcreatedAt\x88\x01\x01\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\x42\r\n\x0b_created_at\"\xac\x02\n\rRateLimitResp\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12\x14\n\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1c\n\tremaining\x18\x03 \x01(\x03R\tremaining\x12\x1d\n\nreset_time\x18\x04 \x01(\x03R\tresetTime\x12\x14\n\x05\x65rror\x18\x05 \x01(\tR\x05\x65rror\x12\x46\n\x08metadata\x18\x06 \x03(\x0b\x32*.pb.gubernator.RateLimitResp.MetadataEntryR\x08metadata\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\x10\n\x0eHealthCheckReq\"b\n\x0fHealthCheckResp\x12\x16\n\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n\x07message\x18\x02 \x01(\tR\x07message\x12\x1d\n\npeer_count\x18\x03 \x01(\x05R\tpeerCount*/\n\tAlgorithm\x12\x10\n\x0cTOKEN_BUCKET\x10\x00\x12\x10\n\x0cLEAKY_BUCKET\x10\x01*\x8d\x01\n\x08\x42\x65havior\x12\x0c\n\x08\x42\x41TCHING\x10\x00\x12\x0f\n\x0bNO_BATCHING\x10\x01\x12\n\n\x06GLOBAL\x10\x02\x12\x19\n\x15\x44URATION_IS_GREGORIAN\x10\x04\x12\x13\n\x0fRESET_REMAINING\x10\x08\x12\x10\n\x0cMULTI_REGION\x10\x10\x12\x14\n\x10\x44RAIN_OVER_LIMIT\x10 *)\n\x06Status\x12\x0f\n\x0bUNDER_LIMIT\x10\x00\x12\x0e\n\nOVER_LIMIT\x10\x01\x32\xdd\x01\n\x02V1\x12p\n\rGetRateLimits\x12\x1f.pb.gubernator.GetRateLimitsReq\x1a .pb.gubernator.GetRateLimitsResp\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x11/v1/GetRateLimits:\x01*\x12\x65\n\x0bHealthCheck\x12\x1d.pb.gubernator.HealthCheckReq\x1a\x1e.pb.gubernator.HealthCheckResp\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/HealthCheckB(Z#github.com/gubernator-io/gubernator\x80\x01\x01\x62\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x10gubernator.proto\x12\rpb.gubernator\x1a\x1cgoogle/api/annotations.proto\"\xd1\x01\n\x10GetRateLimitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\x12)\n\x10minimal_response\x18\x02 \x01(\x08R\x0fminimalResponse\x12\x1b\n\tfail_fast\x18\x03 \x01(\x08R\x08\x66\x61ilFast\x12<\n\x08\x64\x65\x66\x61ults\x18\x04 \x01(\x0b\x32 .pb.gubernator.RateLimitDefaultsR\x08\x64\x65\x66\x61ults\"\x94\x01\n\x11RateLimitDefaults\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x36\n\talgorithm\x18\x02 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x33\n\x08\x62\x65havior\x18\x03 \x01(\x0e\x32\x17.pb.gubernator.BehaviorR\x08\x62\x65havior\"\x86\x01\n\x11GetRateLimitsResp\x12:\n\tresponses\x18\x01 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\tresponses\x12\x35\n\x07summary\x18\x02 \x01(\x0b\x32\x1b.pb.gubernator.BatchSummaryR\x07summary\"\x91\x01\n\x0c\x42\x61tchSummary\x12\x1f\n\x0bunder_limit\x18\x01 \x01(\x05R\nunderLimit\x12\x1d\n\nover_limit\x18\x02 \x01(\x05R\toverLimit\x12\x16\n\x06\x65rrors\x18\x03 \x01(\x05R\x06\x65rrors\x12)\n\x10\x65rror_namespaces\x18\x04 \x03(\tR\x0f\x65rrorNamespaces\"O\n\x14GetRateLimitGroupReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\"\x82\x01\n\x15GetRateLimitGroupResp\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12:\n\tresponses\x18\x02 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\tresponses\"c\n\x13ReserveRateLimitReq\x12:\n\nrate_limit\x18\x01 \x01(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\trateLimit\x12\x10\n\x03ttl\x18\x02 \x01(\x03R\x03ttl\"\x97\x01\n\x14ReserveRateLimitResp\x12;\n\nrate_limit\x18\x01 \x01(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\trateLimit\x12%\n\x0ereservation_id\x18\x02 \x01(\tR\rreservationId\x12\x1b\n\texpire_at\x18\x03 \x01(\x03R\x08\x65xpireAt\"j\n\x0eReservationReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12%\n\x0ereservation_id\x18\x03 \x01(\tR\rreservationId\"%\n\x0fReservationResp\x12\x12\n\x04hits\x18\x01 \x01(\x03R\x04hits\"j\n\x15TransactRateLimitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\x12\x18\n\x07timeout\x18\x02 \x01(\x03R\x07timeout\"\x83\x01\n\x16TransactRateLimitsResp\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12:\n\tresponses\x18\x02 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\tresponses\"\xb2\x01\n\x18GetRateLimitHierarchyReq\x12\x33\n\x06levels\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x06levels\x12\x12\n\x04hits\x18\x02 \x01(\x03R\x04hits\x12\x33\n\x05order\x18\x03 \x01(\x0e\x32\x1d.pb.gubernator.HierarchyOrderR\x05order\x12\x18\n\x07timeout\x18\x04 \x01(\x03R\x07timeout\"\x86\x01\n\x19GetRateLimitHierarchyResp\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12:\n\tresponses\x18\x02 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\tresponses\"[\n\tRefundReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x1b\n\trefund_id\x18\x03 \x01(\tR\x08refundId\" \n\nRefundResp\x12\x12\n\x04hits\x18\x01 \x01(\x03R\x04hits\"H\n\x08LeaseReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n\x06holder\x18\x02 \x01(\tR\x06holder\x12\x10\n\x03ttl\x18\x03 \x01(\x03R\x03ttl\"\\\n\tLeaseResp\x12\x1a\n\x08\x61\x63quired\x18\x01 \x01(\x08R\x08\x61\x63quired\x12\x16\n\x06holder\x18\x02 \x01(\tR\x06holder\x12\x1b\n\texpire_at\x18\x03 \x01(\x03R\x08\x65xpireAt\"\xac\x04\n\x0cRateLimitReq\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x12\n\x04hits\x18\x03 \x01(\x03R\x04hits\x12\x14\n\x05limit\x18\x04 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x05 \x01(\x03R\x08\x64uration\x12\x36\n\talgorithm\x18\x06 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x33\n\x08\x62\x65havior\x18\x07 \x01(\x0e\x32\x17.pb.gubernator.BehaviorR\x08\x62\x65havior\x12\x14\n\x05\x62urst\x18\x08 \x01(\x03R\x05\x62urst\x12\x45\n\x08metadata\x18\t \x03(\x0b\x32).pb.gubernator.RateLimitReq.MetadataEntryR\x08metadata\x12\"\n\ncreated_at\x18\n \x01(\x03H\x00R\tcreatedAt\x88\x01\x01\x12\x1d\n\nrequest_id\x18\x0b \x01(\tR\trequestId\x12!\n\x0cmax_capacity\x18\x0c \x01(\x03R\x0bmaxCapacity\x12\'\n\x0fidempotency_key\x18\r \x01(\tR\x0eidempotencyKey\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\x42\r\n\x0b_created_at\"\x8b\x03\n\rRateLimitResp\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12\x14\n\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1c\n\tremaining\x18\x03 \x01(\x03R\tremaining\x12\x1d\n\nreset_time\x18\x04 \x01(\x03R\tresetTime\x12\x14\n\x05\x65rror\x18\x05 \x01(\tR\x05\x65rror\x12\x46\n\x08metadata\x18\x06 \x03(\x0b\x32*.pb.gubernator.RateLimitResp.MetadataEntryR\x08metadata\x12\x1d\n\nrequest_id\x18\x07 \x01(\tR\trequestId\x12\x1f\n\x0bqueue_depth\x18\x08 \x01(\x03R\nqueueDepth\x12\x1d\n\ndrain_time\x18\t \x01(\x03R\tdrainTime\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\xa1\x03\n\x0fRegisteredLimit\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x03 \x01(\x03R\x08\x64uration\x12\x36\n\talgorithm\x18\x04 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x33\n\x08\x62\x65havior\x18\x05 \x01(\x0e\x32\x17.pb.gubernator.BehaviorR\x08\x62\x65havior\x12\x14\n\x05\x62urst\x18\x06 \x01(\x03R\x05\x62urst\x12)\n\x10rollout_duration\x18\x07 \x01(\x03R\x0frolloutDuration\x12\x32\n\x15rollout_start_percent\x18\x08 \x01(\x05R\x13rolloutStartPercent\x12#\n\rrollout_start\x18\t \x01(\x03R\x0crolloutStart\x12\x41\n\x0crollout_from\x18\n \x01(\x0b\x32\x1e.pb.gubernator.RegisteredLimitR\x0brolloutFrom\"K\n\x11RegisterLimitsReq\x12\x36\n\x06limits\x18\x01 \x03(\x0b\x32\x1e.pb.gubernator.RegisteredLimitR\x06limits\",\n\x12RegisterLimitsResp\x12\x16\n\x06\x65rrors\x18\x01 \x03(\tR\x06\x65rrors\"d\n\rStreamHitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\x12\x1a\n\x08interval\x18\x02 \x01(\x03R\x08interval\"M\n\x0eStreamHitsResp\x12;\n\x08statuses\x18\x01 \x03(\x0b\x32\x1f.pb.gubernator.StreamHitsStatusR\x08statuses\"\x96\x01\n\x10StreamHitsStatus\x12\x12\n\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n\nunique_key\x18\x02 \x01(\tR\tuniqueKey\x12\x12\n\x04hits\x18\x03 \x01(\x03R\x04hits\x12;\n\nrate_limit\x18\x04 \x01(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\trateLimit\"h\n\x0eHealthCheckReq\x12+\n\x11include_endpoints\x18\x01 \x01(\x08R\x10includeEndpoints\x12)\n\x10include_versions\x18\x02 \x01(\x08R\x0fincludeVersions\"\xa1\x02\n\x0fHealthCheckResp\x12\x16\n\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n\x07message\x18\x02 \x01(\tR\x07message\x12\x1d\n\npeer_count\x18\x03 \x01(\x05R\tpeerCount\x12\x35\n\tendpoints\x18\x04 \x03(\x0b\x32\x17.pb.gubernator.EndpointR\tendpoints\x12\x36\n\x08versions\x18\x05 \x03(\x0b\x32\x1a.pb.gubernator.PeerVersionR\x08versions\x12%\n\x0epolicy_version\x18\x06 \x01(\tR\rpolicyVersion\x12\'\n\x0f\x63onfig_checksum\x18\x07 \x01(\tR\x0e\x63onfigChecksum\"\xdb\x01\n\x0bPeerVersion\x12!\n\x0cgrpc_address\x18\x01 \x01(\tR\x0bgrpcAddress\x12\x1f\n\x0b\x64\x61ta_center\x18\x02 \x01(\tR\ndataCenter\x12\x18\n\x07version\x18\x03 \x01(\tR\x07version\x12\x16\n\x06\x63ommit\x18\x04 \x01(\tR\x06\x63ommit\x12\x14\n\x05\x65rror\x18\x05 \x01(\tR\x05\x65rror\x12\'\n\x0f\x63onfig_checksum\x18\x06 \x01(\tR\x0e\x63onfigChecksum\x12\x17\n\x07node_id\x18\x07 \x01(\tR\x06nodeId\"\xa7\x01\n\x08\x45ndpoint\x12!\n\x0cgrpc_address\x18\x01 \x01(\tR\x0bgrpcAddress\x12!\n\x0chttp_address\x18\x02 \x01(\tR\x0bhttpAddress\x12\x1f\n\x0b\x64\x61ta_center\x18\x03 \x01(\tR\ndataCenter\x12\x16\n\x06weight\x18\x04 \x01(\x05R\x06weight\x12\x1c\n\townership\x18\x05 \x01(\x01R\townership*3\n\x0eHierarchyOrder\x12\x10\n\x0cPARENT_FIRST\x10\x00\x12\x0f\n\x0b\x43HILD_FIRST\x10\x01*/\n\tAlgorithm\x12\x10\n\x0cTOKEN_BUCKET\x10\x00\x12\x10\n\x0cLEAKY_BUCKET\x10\x01*\xf1\x01\n\x08\x42\x65havior\x12\x0c\n\x08\x42\x41TCHING\x10\x00\x12\x0f\n\x0bNO_BATCHING\x10\x01\x12\n\n\x06GLOBAL\x10\x02\x12\x19\n\x15\x44URATION_IS_GREGORIAN\x10\x04\x12\x13\n\x0fRESET_REMAINING\x10\x08\x12\x10\n\x0cMULTI_REGION\x10\x10\x12\x14\n\x10\x44RAIN_OVER_LIMIT\x10 \x12\x0b\n\x07\x44RY_RUN\x10@\x12\x12\n\rGREEDY_REFILL\x10\x80\x01\x12\x0f\n\nREFUNDABLE\x10\x80\x02\x12\x10\n\x0b\x46ORCE_LOCAL\x10\x80\x04\x12\x0f\n\nFORCE_PEER\x10\x80\x08\x12\r\n\x08ROLLOVER\x10\x80\x10*6\n\x06Status\x12\x0f\n\x0bUNDER_LIMIT\x10\x00\x12\x0e\n\nOVER_LIMIT\x10\x01\x12\x0b\n\x07UNKNOWN\x10\x02\x32\xd3\x0b\n\x02V1\x12p\n\rGetRateLimits\x12\x1f.pb.gubernator.GetRateLimitsReq\x1a .pb.gubernator.GetRateLimitsResp\"\x1c\x82\xd3\xe4\x93\x02\x16\"\x11/v1/GetRateLimits:\x01*\x12\x80\x01\n\x11GetRateLimitGroup\x12#.pb.gubernator.GetRateLimitGroupReq\x1a$.pb.gubernator.GetRateLimitGroupResp\" \x82\xd3\xe4\x93\x02\x1a\"\x15/v1/GetRateLimitGroup:\x01*\x12|\n\x10ReserveRateLimit\x12\".pb.gubernator.ReserveRateLimitReq\x1a#.pb.gubernator.ReserveRateLimitResp\"\x1f\x82\xd3\xe4\x93\x02\x19\"\x14/v1/ReserveRateLimit:\x01*\x12t\n\x11\x43ommitReservation\x12\x1d.pb.gubernator.ReservationReq\x1a\x1e.pb.gubernator.ReservationResp\" \x82\xd3\xe4\x93\x02\x1a\"\x15/v1/CommitReservation:\x01*\x12t\n\x11\x43\x61ncelReservation\x12\x1d.pb.gubernator.ReservationReq\x1a\x1e.pb.gubernator.ReservationResp\" \x82\xd3\xe4\x93\x02\x1a\"\x15/v1/CancelReservation:\x01*\x12\x84\x01\n\x12TransactRateLimits\x12$.pb.gubernator.TransactRateLimitsReq\x1a%.pb.gubernator.TransactRateLimitsResp\"!\x82\xd3\xe4\x93\x02\x1b\"\x16/v1/TransactRateLimits:\x01*\x12\x90\x01\n\x15GetRateLimitHierarchy\x12\'.pb.gubernator.GetRateLimitHierarchyReq\x1a(.pb.gubernator.GetRateLimitHierarchyResp\"$\x82\xd3\xe4\x93\x02\x1e\"\x19/v1/GetRateLimitHierarchy:\x01*\x12\x66\n\x0fRefundRateLimit\x12\x18.pb.gubernator.RefundReq\x1a\x19.pb.gubernator.RefundResp\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x13/v1/RefundRateLimit:\x01*\x12^\n\x0c\x41\x63quireLease\x12\x17.pb.gubernator.LeaseReq\x1a\x18.pb.gubernator.LeaseResp\"\x1b\x82\xd3\xe4\x93\x02\x15\"\x10/v1/AcquireLease:\x01*\x12^\n\x0cReleaseLease\x12\x17.pb.gubernator.LeaseReq\x1a\x18.pb.gubernator.LeaseResp\"\x1b\x82\xd3\xe4\x93\x02\x15\"\x10/v1/ReleaseLease:\x01*\x12t\n\x0eRegisterLimits\x12 .pb.gubernator.RegisterLimitsReq\x1a!.pb.gubernator.RegisterLimitsResp\"\x1d\x82\xd3\xe4\x93\x02\x17\"\x12/v1/RegisterLimits:\x01*\x12O\n\nStreamHits\x12\x1c.pb.gubernator.StreamHitsReq\x1a\x1d.pb.gubernator.StreamHitsResp\"\x00(\x01\x30\x01\x12\x65\n\x0bHealthCheck\x12\x1d.pb.gubernator.HealthCheckReq\x1a\x1e.pb.gubernator.HealthCheckResp\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/HealthCheckB1Z,github.com/gubernator-io/gubernator/v2/proto\x80\x01\x01\x62\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
 _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'gubernator_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
-  _globals['DESCRIPTOR']._serialized_options = b'Z#github.com/gubernator-io/gubernator\200\001\001'
+  _globals['DESCRIPTOR']._serialized_options = b'Z,github.com/gubernator-io/gubernator/v2/proto\200\001\001'
   _globals['_RATELIMITREQ_METADATAENTRY']._loaded_options = None
   _globals['_RATELIMITREQ_METADATAENTRY']._serialized_options = b'8\001'
   _globals['_RATELIMITRESP_METADATAENTRY']._loaded_options = None
   _globals['_RATELIMITRESP_METADATAENTRY']._serialized_options = b'8\001'
   _globals['_V1'].methods_by_name['GetRateLimits']._loaded_options = None
   _globals['_V1'].methods_by_name['GetRateLimits']._serialized_options = b'\202\323\344\223\002\026\"\021/v1/GetRateLimits:\001*'
+  _globals['_V1'].methods_by_name['GetRateLimitGroup']._loaded_options = None
+  _globals['_V1'].methods_by_name['GetRateLimitGroup']._serialized_options = b'\202\323\344\223\002\032\"\025/v1/GetRateLimitGroup:\001*'
+  _globals['_V1'].methods_by_name['ReserveRateLimit']._loaded_options = None
+  _globals['_V1'].methods_by_name['ReserveRateLimit']._serialized_options = b'\202\323\344\223\002\031\"\024/v1/ReserveRateLimit:\001*'
+  _globals['_V1'].methods_by_name['CommitReservation']._loaded_options = None
+  _globals['_V1'].methods_by_name['CommitReservation']._serialized_options = b'\202\323\344\223\002\032\"\025/v1/CommitReservation:\001*'
+  _globals['_V1'].methods_by_name['CancelReservation']._loaded_options = None
+  _globals['_V1'].methods_by_name['CancelReservation']._serialized_options = b'\202\323\344\223\002\032\"\025/v1/CancelReservation:\001*'
+  _globals['_V1'].methods_by_name['TransactRateLimits']._loaded_options = None
+  _globals['_V1'].methods_by_name['TransactRateLimits']._serialized_options = b'\202\323\344\223\002\033\"\026/v1/TransactRateLimits:\001*'
+  _globals['_V1'].methods_by_name['GetRateLimitHierarchy']._loaded_options = None
+  _globals['_V1'].methods_by_name['GetRateLimitHierarchy']._serialized_options = b'\202\323\344\223\002\036\"\031/v1/GetRateLimitHierarchy:\001*'
+  _globals['_V1'].methods_by_name['RefundRateLimit']._loaded_options = None
+  _globals['_V1'].methods_by_name['RefundRateLimit']._serialized_options = b'\202\323\344\223\002\030\"\023/v1/RefundRateLimit:\001*'
+  _globals['_V1'].methods_by_name['AcquireLease']._loaded_options = None
+  _globals['_V1'].methods_by_name['AcquireLease']._serialized_options = b'\202\323\344\223\002\025\"\020/v1/AcquireLease:\001*'
+  _globals['_V1'].methods_by_name['ReleaseLease']._loaded_options = None
+  _globals['_V1'].methods_by_name['ReleaseLease']._serialized_options = b'\202\323\344\223\002\025\"\020/v1/ReleaseLease:\001*'
+  _globals['_V1'].methods_by_name['RegisterLimits']._loaded_options = None
+  _globals['_V1'].methods_by_name['RegisterLimits']._serialized_options = b'\202\323\344\223\002\027\"\022/v1/RegisterLimits:\001*'
   _globals['_V1'].methods_by_name['HealthCheck']._loaded_options = None
   _globals['_V1'].methods_by_name['HealthCheck']._serialized_options = b'\202\323\344\223\002\021\022\017/v1/HealthCheck'
-  _globals['_ALGORITHM']._serialized_start=1096
-  _globals['_ALGORITHM']._serialized_end=1143
-  _globals['_BEHAVIOR']._serialized_start=1146
-  _globals['_BEHAVIOR']._serialized_end=1287
-  _globals['_STATUS']._serialized_start=1289
-  _globals['_STATUS']._serialized_end=1330
-  _globals['_GETRATELIMITSREQ']._serialized_start=65
-  _globals['_GETRATELIMITSREQ']._serialized_end=140
-  _globals['_GETRATELIMITSRESP']._serialized_start=142
-  _globals['_GETRATELIMITSRESP']._serialized_end=221
-  _globals['_RATELIMITREQ']._serialized_start=224
-  _globals['_RATELIMITREQ']._serialized_end=673
-  _globals['_RATELIMITREQ_METADATAENTRY']._serialized_start=599
-  _globals['_RATELIMITREQ_METADATAENTRY']._serialized_end=658
-  _globals['_RATELIMITRESP']._serialized_start=676
-  _globals['_RATELIMITRESP']._serialized_end=976
-  _globals['_RATELIMITRESP_METADATAENTRY']._serialized_start=599
-  _globals['_RATELIMITRESP_METADATAENTRY']._serialized_end=658
-  _globals['_HEALTHCHECKREQ']._serialized_start=978
-  _globals['_HEALTHCHECKREQ']._serialized_end=994
-  _globals['_HEALTHCHECKRESP']._serialized_start=996
-  _globals['_HEALTHCHECKRESP']._serialized_end=1094
-  _globals['_V1']._serialized_start=1333
-  _globals['_V1']._serialized_end=1554
+  _globals['_HIERARCHYORDER']._serialized_start=4808
+  _globals['_HIERARCHYORDER']._serialized_end=4859
+  _globals['_ALGORITHM']._serialized_start=4861
+  _globals['_ALGORITHM']._serialized_end=4908
+  _globals['_BEHAVIOR']._serialized_start=4911
+  _globals['_BEHAVIOR']._serialized_end=5152
+  _globals['_STATUS']._serialized_start=5154
+  _globals['_STATUS']._serialized_end=5208
+  _globals['_GETRATELIMITSREQ']._serialized_start=66
+  _globals['_GETRATELIMITSREQ']._serialized_end=275
+  _globals['_RATELIMITDEFAULTS']._serialized_start=278
+  _globals['_RATELIMITDEFAULTS']._serialized_end=426
+  _globals['_GETRATELIMITSRESP']._serialized_start=429
+  _globals['_GETRATELIMITSRESP']._serialized_end=563
+  _globals['_BATCHSUMMARY']._serialized_start=566
+  _globals['_BATCHSUMMARY']._serialized_end=711
+  _globals['_GETRATELIMITGROUPREQ']._serialized_start=713
+  _globals['_GETRATELIMITGROUPREQ']._serialized_end=792
+  _globals['_GETRATELIMITGROUPRESP']._serialized_start=795
+  _globals['_GETRATELIMITGROUPRESP']._serialized_end=925
+  _globals['_RESERVERATELIMITREQ']._serialized_start=927
+  _globals['_RESERVERATELIMITREQ']._serialized_end=1026
+  _globals['_RESERVERATELIMITRESP']._serialized_start=1029
+  _globals['_RESERVERATELIMITRESP']._serialized_end=1180
+  _globals['_RESERVATIONREQ']._serialized_start=1182
+  _globals['_RESERVATIONREQ']._serialized_end=1288
+  _globals['_RESERVATIONRESP']._serialized_start=1290
+  _globals['_RESERVATIONRESP']._serialized_end=1327
+  _globals['_TRANSACTRATELIMITSREQ']._serialized_start=1329
+  _globals['_TRANSACTRATELIMITSREQ']._serialized_end=1435
+  _globals['_TRANSACTRATELIMITSRESP']._serialized_start=1438
+  _globals['_TRANSACTRATELIMITSRESP']._serialized_end=1569
+  _globals['_GETRATELIMITHIERARCHYREQ']._serialized_start=1572
+  _globals['_GETRATELIMITHIERARCHYREQ']._serialized_end=1750
+  _globals['_GETRATELIMITHIERARCHYRESP']._serialized_start=1753
+  _globals['_GETRATELIMITHIERARCHYRESP']._serialized_end=1887
+  _globals['_REFUNDREQ']._serialized_start=1889
+  _globals['_REFUNDREQ']._serialized_end=1980
+  _globals['_REFUNDRESP']._serialized_start=1982
+  _globals['_REFUNDRESP']._serialized_end=2014
+  _globals['_LEASEREQ']._serialized_start=2016
+  _globals['_LEASEREQ']._serialized_end=2088
+  _globals['_LEASERESP']._serialized_start=2090
+  _globals['_LEASERESP']._serialized_end=2182
+  _globals['_RATELIMITREQ']._serialized_start=2185
+  _globals['_RATELIMITREQ']._serialized_end=2741
+  _globals['_RATELIMITREQ_METADATAENTRY']._serialized_start=2667
+  _globals['_RATELIMITREQ_METADATAENTRY']._serialized_end=2726
+  _globals['_RATELIMITRESP']._serialized_start=2744
+  _globals['_RATELIMITRESP']._serialized_end=3139
+  _globals['_RATELIMITRESP_METADATAENTRY']._serialized_start=2667
+  _globals['_RATELIMITRESP_METADATAENTRY']._serialized_end=2726
+  _globals['_REGISTEREDLIMIT']._serialized_start=3142
+  _globals['_REGISTEREDLIMIT']._serialized_end=3559
+  _globals['_REGISTERLIMITSREQ']._serialized_start=3561
+  _globals['_REGISTERLIMITSREQ']._serialized_end=3636
+  _globals['_REGISTERLIMITSRESP']._serialized_start=3638
+  _globals['_REGISTERLIMITSRESP']._serialized_end=3682
+  _globals['_STREAMHITSREQ']._serialized_start=3684
+  _globals['_STREAMHITSREQ']._serialized_end=3784
+  _globals['_STREAMHITSRESP']._serialized_start=3786
+  _globals['_STREAMHITSRESP']._serialized_end=3863
+  _globals['_STREAMHITSSTATUS']._serialized_start=3866
+  _globals['_STREAMHITSSTATUS']._serialized_end=4016
+  _globals['_HEALTHCHECKREQ']._serialized_start=4018
+  _globals['_HEALTHCHECKREQ']._serialized_end=4122
+  _globals['_HEALTHCHECKRESP']._serialized_start=4125
+  _globals['_HEALTHCHECKRESP']._serialized_end=4414
+  _globals['_PEERVERSION']._serialized_start=4417
+  _globals['_PEERVERSION']._serialized_end=4636
+  _globals['_ENDPOINT']._serialized_start=4639
+  _globals['_ENDPOINT']._serialized_end=4806
+  _globals['_V1']._serialized_start=5211
+  _globals['_V1']._serialized_end=6702
 # @@protoc_insertion_point(module_scope)
@@ -19,6 +19,61 @@ class V1Stub(object):
                 request_serializer=gubernator__pb2.GetRateLimitsReq.SerializeToString,
                 response_deserializer=gubernator__pb2.GetRateLimitsResp.FromString,
                 )
+        self.GetRateLimitGroup = channel.unary_unary(
+                '/pb.gubernator.V1/GetRateLimitGroup',
+                request_serializer=gubernator__pb2.GetRateLimitGroupReq.SerializeToString,
+                response_deserializer=gubernator__pb2.GetRateLimitGroupResp.FromString,
+                )
+        self.ReserveRateLimit = channel.unary_unary(
+                '/pb.gubernator.V1/ReserveRateLimit',
+                request_serializer=gubernator__pb2.ReserveRateLimitReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReserveRateLimitResp.FromString,
+                )
+        self.CommitReservation = channel.unary_unary(
+                '/pb.gubernator.V1/CommitReservation',
+                request_serializer=gubernator__pb2.ReservationReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReservationResp.FromString,
+                )
+        self.CancelReservation = channel.unary_unary(
+                '/pb.gubernator.V1/CancelReservation',
+                request_serializer=gubernator__pb2.ReservationReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReservationResp.FromString,
+                )
+        self.TransactRateLimits = channel.unary_unary(
+                '/pb.gubernator.V1/TransactRateLimits',
+                request_serializer=gubernator__pb2.TransactRateLimitsReq.SerializeToString,
+                response_deserializer=gubernator__pb2.TransactRateLimitsResp.FromString,
+                )
+        self.GetRateLimitHierarchy = channel.unary_unary(
+                '/pb.gubernator.V1/GetRateLimitHierarchy',
+                request_serializer=gubernator__pb2.GetRateLimitHierarchyReq.SerializeToString,
+                response_deserializer=gubernator__pb2.GetRateLimitHierarchyResp.FromString,
+                )
+        self.RefundRateLimit = channel.unary_unary(
+                '/pb.gubernator.V1/RefundRateLimit',
+                request_serializer=gubernator__pb2.RefundReq.SerializeToString,
+                response_deserializer=gubernator__pb2.RefundResp.FromString,
+                )
+        self.AcquireLease = channel.unary_unary(
+                '/pb.gubernator.V1/AcquireLease',
+                request_serializer=gubernator__pb2.LeaseReq.SerializeToString,
+                response_deserializer=gubernator__pb2.LeaseResp.FromString,
+                )
+        self.ReleaseLease = channel.unary_unary(
+                '/pb.gubernator.V1/ReleaseLease',
+                request_serializer=gubernator__pb2.LeaseReq.SerializeToString,
+                response_deserializer=gubernator__pb2.LeaseResp.FromString,
+                )
+        self.RegisterLimits = channel.unary_unary(
+                '/pb.gubernator.V1/RegisterLimits',
+                request_serializer=gubernator__pb2.RegisterLimitsReq.SerializeToString,
+                response_deserializer=gubernator__pb2.RegisterLimitsResp.FromString,
+                )
+        self.StreamHits = channel.stream_stream(
+                '/pb.gubernator.V1/StreamHits',
+                request_serializer=gubernator__pb2.StreamHitsReq.SerializeToString,
+                response_deserializer=gubernator__pb2.StreamHitsResp.FromString,
+                )
         self.HealthCheck = channel.unary_unary(
                 '/pb.gubernator.V1/HealthCheck',
                 request_serializer=gubernator__pb2.HealthCheckReq.SerializeToString,
@@ -36,6 +91,101 @@ class V1Servicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetRateLimitGroup(self, request, context):
+        """Given a group of rate limit requests, apply the hits to every rate limit in the
+        group only if all of them are under the limit. If any rate limit in the group
+        would be over the limit, no hits are applied to any of them.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReserveRateLimit(self, request, context):
+        """Reserve `hits` against a rate limit. The hits are held by the reservation until it is
+        committed or canceled. A reservation which is neither committed nor canceled before the
+        `ttl` elapses is canceled, and the hits it held are returned to the rate limit.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CommitReservation(self, request, context):
+        """Commit the hits held by a reservation, the hits are no longer returned to the rate limit.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CancelReservation(self, request, context):
+        """Cancel a reservation and return the hits it held to the rate limit.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def TransactRateLimits(self, request, context):
+        """Apply the hits of every rate limit in the transaction, or none of them, even when the rate
+        limits are owned by different peers. The peer which receives the request reserves the hits
+        of every rate limit, then commits the reservations only if every rate limit was under the
+        limit, otherwise it cancels them. Hits which are not committed before `timeout` elapses are
+        returned to their rate limits by the owning peers.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetRateLimitHierarchy(self, request, context):
+        """Consume hits from a rate limit and every one of its parents, IE: a key, its project and its
+        organization, such that the cap of a parent is shared by all of its children. The hits are
+        reserved on each level in the enforcement order, then committed only if every level was under
+        the limit, even when the levels are owned by different peers.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RefundRateLimit(self, request, context):
+        """Return the hits applied by a request with the REFUNDABLE behavior to the rate limit, IE: when
+        the operation the hits were consumed for has failed and the customer should not be charged.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def AcquireLease(self, request, context):
+        """Acquire a named lease on the peer which owns the lease name. Only one holder may hold the
+        lease until it expires or is released. Acquiring a lease already held by the same holder
+        renews the lease. Useful for "single execution" semantics across a fleet, IE: cron jobs.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReleaseLease(self, request, context):
+        """Release a lease held by the holder, such that other holders may acquire it.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RegisterLimits(self, request, context):
+        """Registers named limit definitions on every peer in the cluster, such that subsequent requests
+        reference a definition by name and provide only the unique key and hits.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def StreamHits(self, request_iterator, context):
+        """Accepts a stream of hits to a few rate limits, IE: from a metering agent which emits thousands
+        of hits per second for one key, instead of a unary call for each hit. The hits received for
+        each rate limit are summed and applied once per interval, after which the consolidated status
+        of each rate limit which received hits is streamed back.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def HealthCheck(self, request, context):
         """This method is for round trip benchmarking and can be used by
         the client to determine connectivity to the server
@@ -52,6 +202,61 @@ def add_V1Servicer_to_server(servicer, server):
                     request_deserializer=gubernator__pb2.GetRateLimitsReq.FromString,
                     response_serializer=gubernator__pb2.GetRateLimitsResp.SerializeToString,
             ),
+            'GetRateLimitGroup': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetRateLimitGroup,
+                    request_deserializer=gubernator__pb2.GetRateLimitGroupReq.FromString,
+                    response_serializer=gubernator__pb2.GetRateLimitGroupResp.SerializeToString,
+            ),
+            'ReserveRateLimit': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReserveRateLimit,
+                    request_deserializer=gubernator__pb2.ReserveRateLimitReq.FromString,
+                    response_serializer=gubernator__pb2.ReserveRateLimitResp.SerializeToString,
+            ),
+            'CommitReservation': grpc.unary_unary_rpc_method_handler(
+                    servicer.CommitReservation,
+                    request_deserializer=gubernator__pb2.ReservationReq.FromString,
+                    response_serializer=gubernator__pb2.ReservationResp.SerializeToString,
+            ),
+            'CancelReservation': grpc.unary_unary_rpc_method_handler(
+                    servicer.CancelReservation,
+                    request_deserializer=gubernator__pb2.ReservationReq.FromString,
+                    response_serializer=gubernator__pb2.ReservationResp.SerializeToString,
+            ),
+            'TransactRateLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.TransactRateLimits,
+                    request_deserializer=gubernator__pb2.TransactRateLimitsReq.FromString,
+                    response_serializer=gubernator__pb2.TransactRateLimitsResp.SerializeToString,
+            ),
+            'GetRateLimitHierarchy': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetRateLimitHierarchy,
+                    request_deserializer=gubernator__pb2.GetRateLimitHierarchyReq.FromString,
+                    response_serializer=gubernator__pb2.GetRateLimitHierarchyResp.SerializeToString,
+            ),
+            'RefundRateLimit': grpc.unary_unary_rpc_method_handler(
+                    servicer.RefundRateLimit,
+                    request_deserializer=gubernator__pb2.RefundReq.FromString,
+                    response_serializer=gubernator__pb2.RefundResp.SerializeToString,
+            ),
+            'AcquireLease': grpc.unary_unary_rpc_method_handler(
+                    servicer.AcquireLease,
+                    request_deserializer=gubernator__pb2.LeaseReq.FromString,
+                    response_serializer=gubernator__pb2.LeaseResp.SerializeToString,
+            ),
+            'ReleaseLease': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReleaseLease,
+                    request_deserializer=gubernator__pb2.LeaseReq.FromString,
+                    response_serializer=gubernator__pb2.LeaseResp.SerializeToString,
+            ),
+            'RegisterLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.RegisterLimits,
+                    request_deserializer=gubernator__pb2.RegisterLimitsReq.FromString,
+                    response_serializer=gubernator__pb2.RegisterLimitsResp.SerializeToString,
+            ),
+            'StreamHits': grpc.stream_stream_rpc_method_handler(
+                    servicer.StreamHits,
+                    request_deserializer=gubernator__pb2.StreamHitsReq.FromString,
+                    response_serializer=gubernator__pb2.StreamHitsResp.SerializeToString,
+            ),
             'HealthCheck': grpc.unary_unary_rpc_method_handler(
                     servicer.HealthCheck,
                     request_deserializer=gubernator__pb2.HealthCheckReq.FromString,
@@ -84,6 +289,193 @@ class V1(object):
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
 
+    @staticmethod
+    def GetRateLimitGroup(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/GetRateLimitGroup',
+            gubernator__pb2.GetRateLimitGroupReq.SerializeToString,
+            gubernator__pb2.GetRateLimitGroupResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReserveRateLimit(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/ReserveRateLimit',
+            gubernator__pb2.ReserveRateLimitReq.SerializeToString,
+            gubernator__pb2.ReserveRateLimitResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CommitReservation(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/CommitReservation',
+            gubernator__pb2.ReservationReq.SerializeToString,
+            gubernator__pb2.ReservationResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CancelReservation(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/CancelReservation',
+            gubernator__pb2.ReservationReq.SerializeToString,
+            gubernator__pb2.ReservationResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def TransactRateLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/TransactRateLimits',
+            gubernator__pb2.TransactRateLimitsReq.SerializeToString,
+            gubernator__pb2.TransactRateLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetRateLimitHierarchy(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/GetRateLimitHierarchy',
+            gubernator__pb2.GetRateLimitHierarchyReq.SerializeToString,
+            gubernator__pb2.GetRateLimitHierarchyResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def RefundRateLimit(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/RefundRateLimit',
+            gubernator__pb2.RefundReq.SerializeToString,
+            gubernator__pb2.RefundResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def AcquireLease(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/AcquireLease',
+            gubernator__pb2.LeaseReq.SerializeToString,
+            gubernator__pb2.LeaseResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReleaseLease(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/ReleaseLease',
+            gubernator__pb2.LeaseReq.SerializeToString,
+            gubernator__pb2.LeaseResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def RegisterLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.V1/RegisterLimits',
+            gubernator__pb2.RegisterLimitsReq.SerializeToString,
+            gubernator__pb2.RegisterLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def StreamHits(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(request_iterator, target, '/pb.gubernator.V1/StreamHits',
+            gubernator__pb2.StreamHitsReq.SerializeToString,
+            gubernator__pb2.StreamHitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
     @staticmethod
     def HealthCheck(request,
             target,
@@ -13,26 +13,61 @@ _sym_db = _symbol_database.Default()
 
 
 import gubernator_pb2 as gubernator__pb2
+import admin_pb2 as admin__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0bpeers.proto\x12\rpb.gubernator\x1a\x10gubernator.proto\"O\n\x14GetPeerRateLimitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\"V\n\x15GetPeerRateLimitsResp\x12=\n\x0brate_limits\x18\x01 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\nrateLimits\"Q\n\x14UpdatePeerGlobalsReq\x12\x39\n\x07globals\x18\x01 \x03(\x0b\x32\x1f.pb.gubernator.UpdatePeerGlobalR\x07globals\"\xcd\x01\n\x10UpdatePeerGlobal\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x34\n\x06status\x18\x02 \x01(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\x06status\x12\x36\n\talgorithm\x18\x03 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x1a\n\x08\x64uration\x18\x04 \x01(\x03R\x08\x64uration\x12\x1d\n\ncreated_at\x18\x05 \x01(\x03R\tcreatedAt\"\x17\n\x15UpdatePeerGlobalsResp2\xcd\x01\n\x07PeersV1\x12`\n\x11GetPeerRateLimits\x12#.pb.gubernator.GetPeerRateLimitsReq\x1a$.pb.gubernator.GetPeerRateLimitsResp\"\x00\x12`\n\x11UpdatePeerGlobals\x12#.pb.gubernator.UpdatePeerGlobalsReq\x1a$.pb.gubernator.UpdatePeerGlobalsResp\"\x00\x42(Z#github.com/gubernator-io/gubernator\x80\x01\x01\x62\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0bpeers.proto\x12\rpb.gubernator\x1a\x10gubernator.proto\x1a\x0b\x61\x64min.proto\"O\n\x14GetPeerRateLimitsReq\x12\x37\n\x08requests\x18\x01 \x03(\x0b\x32\x1b.pb.gubernator.RateLimitReqR\x08requests\"V\n\x15GetPeerRateLimitsResp\x12=\n\x0brate_limits\x18\x01 \x03(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\nrateLimits\"Q\n\x14UpdatePeerGlobalsReq\x12\x39\n\x07globals\x18\x01 \x03(\x0b\x32\x1f.pb.gubernator.UpdatePeerGlobalR\x07globals\"\xe1\x01\n\x10UpdatePeerGlobal\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x34\n\x06status\x18\x02 \x01(\x0b\x32\x1c.pb.gubernator.RateLimitRespR\x06status\x12\x36\n\talgorithm\x18\x03 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x1a\n\x08\x64uration\x18\x04 \x01(\x03R\x08\x64uration\x12\x1d\n\ncreated_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x12\n\x04name\x18\x06 \x01(\tR\x04name\"\x17\n\x15UpdatePeerGlobalsResp\"V\n\x16ResetPeerRateLimitsReq\x12\x1f\n\x0bname_prefix\x18\x01 \x01(\tR\nnamePrefix\x12\x1b\n\tname_glob\x18\x02 \x01(\tR\x08nameGlob\"3\n\x17ResetPeerRateLimitsResp\x12\x18\n\x07removed\x18\x01 \x01(\x03R\x07removed\"t\n\x16UpdatePeerOverridesReq\x12)\n\x03set\x18\x01 \x03(\x0b\x32\x17.pb.gubernator.OverrideR\x03set\x12/\n\x06\x64\x65lete\x18\x02 \x03(\x0b\x32\x17.pb.gubernator.OverrideR\x06\x64\x65lete\"\x19\n\x17UpdatePeerOverridesResp\"\x13\n\x11ListPeerLimitsReq\"L\n\x12ListPeerLimitsResp\x12\x36\n\x06limits\x18\x01 \x03(\x0b\x32\x1e.pb.gubernator.RegisteredLimitR\x06limits\"P\n\x19TransferPeerRateLimitsReq\x12\x33\n\x05items\x18\x01 \x03(\x0b\x32\x1d.pb.gubernator.CacheItemStateR\x05items\"N\n\x1aTransferPeerRateLimitsResp\x12\x14\n\x05\x61\x64\x64\x65\x64\x18\x01 \x01(\x03R\x05\x61\x64\x64\x65\x64\x12\x1a\n\x08\x65xisting\x18\x02 \x01(\x03R\x08\x65xisting\"Q\n\x1aReplicatePeerRateLimitsReq\x12\x33\n\x05items\x18\x01 \x03(\x0b\x32\x1d.pb.gubernator.CacheItemStateR\x05items\"\x1d\n\x1bReplicatePeerRateLimitsResp\"\x15\n\x13GetPeerKeyFilterReq\"B\n\x14GetPeerKeyFilterResp\x12\x12\n\x04\x62its\x18\x01 \x03(\x06R\x04\x62its\x12\x16\n\x06hashes\x18\x02 \x01(\x05R\x06hashes\"\x13\n\x11GetPeerVersionReq\"\x88\x01\n\x12GetPeerVersionResp\x12\x18\n\x07version\x18\x01 \x01(\tR\x07version\x12\x16\n\x06\x63ommit\x18\x02 \x01(\tR\x06\x63ommit\x12\'\n\x0f\x63onfig_checksum\x18\x03 \x01(\tR\x0e\x63onfigChecksum\x12\x17\n\x07node_id\x18\x04 \x01(\tR\x06nodeId\"\xda\x02\n\x0e\x43\x61\x63heItemState\x12\x18\n\x07version\x18\x01 \x01(\x05R\x07version\x12\x10\n\x03key\x18\x02 \x01(\tR\x03key\x12\x12\n\x04name\x18\x03 \x01(\tR\x04name\x12\x36\n\talgorithm\x18\x04 \x01(\x0e\x32\x18.pb.gubernator.AlgorithmR\talgorithm\x12\x1b\n\texpire_at\x18\x05 \x01(\x03R\x08\x65xpireAt\x12\x1d\n\ninvalid_at\x18\x06 \x01(\x03R\tinvalidAt\x12\x44\n\x0ctoken_bucket\x18\x07 \x01(\x0b\x32\x1f.pb.gubernator.TokenBucketStateH\x00R\x0btokenBucket\x12\x44\n\x0cleaky_bucket\x18\x08 \x01(\x0b\x32\x1f.pb.gubernator.LeakyBucketStateH\x00R\x0bleakyBucketB\x08\n\x06\x62ucket\"\xee\x01\n\x10TokenBucketState\x12-\n\x06status\x18\x01 \x01(\x0e\x32\x15.pb.gubernator.StatusR\x06status\x12\x14\n\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x03 \x01(\x03R\x08\x64uration\x12\x1c\n\tremaining\x18\x04 \x01(\x03R\tremaining\x12\x1d\n\ncreated_at\x18\x05 \x01(\x03R\tcreatedAt\x12\x1d\n\nupdated_at\x18\x06 \x01(\x03R\tupdatedAt\x12\x1d\n\ngrace_used\x18\x07 \x01(\x03R\tgraceUsed\"\x97\x01\n\x10LeakyBucketState\x12\x14\n\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x1a\n\x08\x64uration\x18\x02 \x01(\x03R\x08\x64uration\x12\x1c\n\tremaining\x18\x03 \x01(\x01R\tremaining\x12\x1d\n\nupdated_at\x18\x04 \x01(\x03R\tupdatedAt\x12\x14\n\x05\x62urst\x18\x05 \x01(\x03R\x05\x62urst2\xe3\x10\n\x07PeersV1\x12`\n\x11GetPeerRateLimits\x12#.pb.gubernator.GetPeerRateLimitsReq\x1a$.pb.gubernator.GetPeerRateLimitsResp\"\x00\x12`\n\x11UpdatePeerGlobals\x12#.pb.gubernator.UpdatePeerGlobalsReq\x1a$.pb.gubernator.UpdatePeerGlobalsResp\"\x00\x12\x66\n\x13ResetPeerRateLimits\x12%.pb.gubernator.ResetPeerRateLimitsReq\x1a&.pb.gubernator.ResetPeerRateLimitsResp\"\x00\x12\x61\n\x14ReservePeerRateLimit\x12\".pb.gubernator.ReserveRateLimitReq\x1a#.pb.gubernator.ReserveRateLimitResp\"\x00\x12X\n\x15\x43ommitPeerReservation\x12\x1d.pb.gubernator.ReservationReq\x1a\x1e.pb.gubernator.ReservationResp\"\x00\x12X\n\x15\x43\x61ncelPeerReservation\x12\x1d.pb.gubernator.ReservationReq\x1a\x1e.pb.gubernator.ReservationResp\"\x00\x12L\n\x13RefundPeerRateLimit\x12\x18.pb.gubernator.RefundReq\x1a\x19.pb.gubernator.RefundResp\"\x00\x12G\n\x10\x41\x63quirePeerLease\x12\x17.pb.gubernator.LeaseReq\x1a\x18.pb.gubernator.LeaseResp\"\x00\x12G\n\x10ReleasePeerLease\x12\x17.pb.gubernator.LeaseReq\x1a\x18.pb.gubernator.LeaseResp\"\x00\x12\x64\n\x15GetPeerNamespaceUsage\x12#.pb.gubernator.GetNamespaceUsageReq\x1a$.pb.gubernator.GetNamespaceUsageResp\"\x00\x12\x66\n\x13UpdatePeerOverrides\x12%.pb.gubernator.UpdatePeerOverridesReq\x1a&.pb.gubernator.UpdatePeerOverridesResp\"\x00\x12X\n\x11ListPeerOverrides\x12\x1f.pb.gubernator.ListOverridesReq\x1a .pb.gubernator.ListOverridesResp\"\x00\x12[\n\x12RegisterPeerLimits\x12 .pb.gubernator.RegisterLimitsReq\x1a!.pb.gubernator.RegisterLimitsResp\"\x00\x12W\n\x0eListPeerLimits\x12 .pb.gubernator.ListPeerLimitsReq\x1a!.pb.gubernator.ListPeerLimitsResp\"\x00\x12X\n\x11GetPeerLimitDrift\x12\x1f.pb.gubernator.GetLimitDriftReq\x1a .pb.gubernator.GetLimitDriftResp\"\x00\x12X\n\x11GetPeerKeyHistory\x12\x1f.pb.gubernator.GetKeyHistoryReq\x1a .pb.gubernator.GetKeyHistoryResp\"\x00\x12[\n\x12ListPeerNamespaces\x12 .pb.gubernator.ListNamespacesReq\x1a!.pb.gubernator.ListNamespacesResp\"\x00\x12W\n\x0eGetPeerVersion\x12 .pb.gubernator.GetPeerVersionReq\x1a!.pb.gubernator.GetPeerVersionResp\"\x00\x12O\n\x0eGetPeerTraffic\x12\x1c.pb.gubernator.GetTrafficReq\x1a\x1d.pb.gubernator.GetTrafficResp\"\x00\x12X\n\x11ReplayPeerJournal\x12\x1f.pb.gubernator.ReplayJournalReq\x1a .pb.gubernator.ReplayJournalResp\"\x00\x12o\n\x16TransferPeerRateLimits\x12(.pb.gubernator.TransferPeerRateLimitsReq\x1a).pb.gubernator.TransferPeerRateLimitsResp\"\x00\x12r\n\x17ReplicatePeerRateLimits\x12).pb.gubernator.ReplicatePeerRateLimitsReq\x1a*.pb.gubernator.ReplicatePeerRateLimitsResp\"\x00\x12]\n\x10GetPeerKeyFilter\x12\".pb.gubernator.GetPeerKeyFilterReq\x1a#.pb.gubernator.GetPeerKeyFilterResp\"\x00\x42\x31Z,github.com/gubernator-io/gubernator/v2/proto\x80\x01\x01\x62\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
 _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'peers_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
-  _globals['DESCRIPTOR']._serialized_options = b'Z#github.com/gubernator-io/gubernator\200\001\001'
-  _globals['_GETPEERRATELIMITSREQ']._serialized_start=48
-  _globals['_GETPEERRATELIMITSREQ']._serialized_end=127
-  _globals['_GETPEERRATELIMITSRESP']._serialized_start=129
-  _globals['_GETPEERRATELIMITSRESP']._serialized_end=215
-  _globals['_UPDATEPEERGLOBALSREQ']._serialized_start=217
-  _globals['_UPDATEPEERGLOBALSREQ']._serialized_end=298
-  _globals['_UPDATEPEERGLOBAL']._serialized_start=301
-  _globals['_UPDATEPEERGLOBAL']._serialized_end=506
-  _globals['_UPDATEPEERGLOBALSRESP']._serialized_start=508
-  _globals['_UPDATEPEERGLOBALSRESP']._serialized_end=531
-  _globals['_PEERSV1']._serialized_start=534
-  _globals['_PEERSV1']._serialized_end=739
+  _globals['DESCRIPTOR']._serialized_options = b'Z,github.com/gubernator-io/gubernator/v2/proto\200\001\001'
+  _globals['_GETPEERRATELIMITSREQ']._serialized_start=61
+  _globals['_GETPEERRATELIMITSREQ']._serialized_end=140
+  _globals['_GETPEERRATELIMITSRESP']._serialized_start=142
+  _globals['_GETPEERRATELIMITSRESP']._serialized_end=228
+  _globals['_UPDATEPEERGLOBALSREQ']._serialized_start=230
+  _globals['_UPDATEPEERGLOBALSREQ']._serialized_end=311
+  _globals['_UPDATEPEERGLOBAL']._serialized_start=314
+  _globals['_UPDATEPEERGLOBAL']._serialized_end=539
+  _globals['_UPDATEPEERGLOBALSRESP']._serialized_start=541
+  _globals['_UPDATEPEERGLOBALSRESP']._serialized_end=564
+  _globals['_RESETPEERRATELIMITSREQ']._serialized_start=566
+  _globals['_RESETPEERRATELIMITSREQ']._serialized_end=652
+  _globals['_RESETPEERRATELIMITSRESP']._serialized_start=654
+  _globals['_RESETPEERRATELIMITSRESP']._serialized_end=705
+  _globals['_UPDATEPEEROVERRIDESREQ']._serialized_start=707
+  _globals['_UPDATEPEEROVERRIDESREQ']._serialized_end=823
+  _globals['_UPDATEPEEROVERRIDESRESP']._serialized_start=825
+  _globals['_UPDATEPEEROVERRIDESRESP']._serialized_end=850
+  _globals['_LISTPEERLIMITSREQ']._serialized_start=852
+  _globals['_LISTPEERLIMITSREQ']._serialized_end=871
+  _globals['_LISTPEERLIMITSRESP']._serialized_start=873
+  _globals['_LISTPEERLIMITSRESP']._serialized_end=949
+  _globals['_TRANSFERPEERRATELIMITSREQ']._serialized_start=951
+  _globals['_TRANSFERPEERRATELIMITSREQ']._serialized_end=1031
+  _globals['_TRANSFERPEERRATELIMITSRESP']._serialized_start=1033
+  _globals['_TRANSFERPEERRATELIMITSRESP']._serialized_end=1111
+  _globals['_REPLICATEPEERRATELIMITSREQ']._serialized_start=1113
+  _globals['_REPLICATEPEERRATELIMITSREQ']._serialized_end=1194
+  _globals['_REPLICATEPEERRATELIMITSRESP']._serialized_start=1196
+  _globals['_REPLICATEPEERRATELIMITSRESP']._serialized_end=1225
+  _globals['_GETPEERKEYFILTERREQ']._serialized_start=1227
+  _globals['_GETPEERKEYFILTERREQ']._serialized_end=1248
+  _globals['_GETPEERKEYFILTERRESP']._serialized_start=1250
+  _globals['_GETPEERKEYFILTERRESP']._serialized_end=1316
+  _globals['_GETPEERVERSIONREQ']._serialized_start=1318
+  _globals['_GETPEERVERSIONREQ']._serialized_end=1337
+  _globals['_GETPEERVERSIONRESP']._serialized_start=1340
+  _globals['_GETPEERVERSIONRESP']._serialized_end=1476
+  _globals['_CACHEITEMSTATE']._serialized_start=1479
+  _globals['_CACHEITEMSTATE']._serialized_end=1825
+  _globals['_TOKENBUCKETSTATE']._serialized_start=1828
+  _globals['_TOKENBUCKETSTATE']._serialized_end=2066
+  _globals['_LEAKYBUCKETSTATE']._serialized_start=2069
+  _globals['_LEAKYBUCKETSTATE']._serialized_end=2220
+  _globals['_PEERSV1']._serialized_start=2223
+  _globals['_PEERSV1']._serialized_end=4370
 # @@protoc_insertion_point(module_scope)
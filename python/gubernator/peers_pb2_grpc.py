@@ -2,6 +2,8 @@
 """Client and server classes corresponding to protobuf-defined services."""
 import grpc
 
+import admin_pb2 as admin__pb2
+import gubernator_pb2 as gubernator__pb2
 import peers_pb2 as peers__pb2
 
 
@@ -25,6 +27,111 @@ class PeersV1Stub(object):
                 request_serializer=peers__pb2.UpdatePeerGlobalsReq.SerializeToString,
                 response_deserializer=peers__pb2.UpdatePeerGlobalsResp.FromString,
                 )
+        self.ResetPeerRateLimits = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ResetPeerRateLimits',
+                request_serializer=peers__pb2.ResetPeerRateLimitsReq.SerializeToString,
+                response_deserializer=peers__pb2.ResetPeerRateLimitsResp.FromString,
+                )
+        self.ReservePeerRateLimit = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ReservePeerRateLimit',
+                request_serializer=gubernator__pb2.ReserveRateLimitReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReserveRateLimitResp.FromString,
+                )
+        self.CommitPeerReservation = channel.unary_unary(
+                '/pb.gubernator.PeersV1/CommitPeerReservation',
+                request_serializer=gubernator__pb2.ReservationReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReservationResp.FromString,
+                )
+        self.CancelPeerReservation = channel.unary_unary(
+                '/pb.gubernator.PeersV1/CancelPeerReservation',
+                request_serializer=gubernator__pb2.ReservationReq.SerializeToString,
+                response_deserializer=gubernator__pb2.ReservationResp.FromString,
+                )
+        self.RefundPeerRateLimit = channel.unary_unary(
+                '/pb.gubernator.PeersV1/RefundPeerRateLimit',
+                request_serializer=gubernator__pb2.RefundReq.SerializeToString,
+                response_deserializer=gubernator__pb2.RefundResp.FromString,
+                )
+        self.AcquirePeerLease = channel.unary_unary(
+                '/pb.gubernator.PeersV1/AcquirePeerLease',
+                request_serializer=gubernator__pb2.LeaseReq.SerializeToString,
+                response_deserializer=gubernator__pb2.LeaseResp.FromString,
+                )
+        self.ReleasePeerLease = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ReleasePeerLease',
+                request_serializer=gubernator__pb2.LeaseReq.SerializeToString,
+                response_deserializer=gubernator__pb2.LeaseResp.FromString,
+                )
+        self.GetPeerNamespaceUsage = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerNamespaceUsage',
+                request_serializer=admin__pb2.GetNamespaceUsageReq.SerializeToString,
+                response_deserializer=admin__pb2.GetNamespaceUsageResp.FromString,
+                )
+        self.UpdatePeerOverrides = channel.unary_unary(
+                '/pb.gubernator.PeersV1/UpdatePeerOverrides',
+                request_serializer=peers__pb2.UpdatePeerOverridesReq.SerializeToString,
+                response_deserializer=peers__pb2.UpdatePeerOverridesResp.FromString,
+                )
+        self.ListPeerOverrides = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ListPeerOverrides',
+                request_serializer=admin__pb2.ListOverridesReq.SerializeToString,
+                response_deserializer=admin__pb2.ListOverridesResp.FromString,
+                )
+        self.RegisterPeerLimits = channel.unary_unary(
+                '/pb.gubernator.PeersV1/RegisterPeerLimits',
+                request_serializer=gubernator__pb2.RegisterLimitsReq.SerializeToString,
+                response_deserializer=gubernator__pb2.RegisterLimitsResp.FromString,
+                )
+        self.ListPeerLimits = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ListPeerLimits',
+                request_serializer=peers__pb2.ListPeerLimitsReq.SerializeToString,
+                response_deserializer=peers__pb2.ListPeerLimitsResp.FromString,
+                )
+        self.GetPeerLimitDrift = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerLimitDrift',
+                request_serializer=admin__pb2.GetLimitDriftReq.SerializeToString,
+                response_deserializer=admin__pb2.GetLimitDriftResp.FromString,
+                )
+        self.GetPeerKeyHistory = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerKeyHistory',
+                request_serializer=admin__pb2.GetKeyHistoryReq.SerializeToString,
+                response_deserializer=admin__pb2.GetKeyHistoryResp.FromString,
+                )
+        self.ListPeerNamespaces = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ListPeerNamespaces',
+                request_serializer=admin__pb2.ListNamespacesReq.SerializeToString,
+                response_deserializer=admin__pb2.ListNamespacesResp.FromString,
+                )
+        self.GetPeerVersion = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerVersion',
+                request_serializer=peers__pb2.GetPeerVersionReq.SerializeToString,
+                response_deserializer=peers__pb2.GetPeerVersionResp.FromString,
+                )
+        self.GetPeerTraffic = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerTraffic',
+                request_serializer=admin__pb2.GetTrafficReq.SerializeToString,
+                response_deserializer=admin__pb2.GetTrafficResp.FromString,
+                )
+        self.ReplayPeerJournal = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ReplayPeerJournal',
+                request_serializer=admin__pb2.ReplayJournalReq.SerializeToString,
+                response_deserializer=admin__pb2.ReplayJournalResp.FromString,
+                )
+        self.TransferPeerRateLimits = channel.unary_unary(
+                '/pb.gubernator.PeersV1/TransferPeerRateLimits',
+                request_serializer=peers__pb2.TransferPeerRateLimitsReq.SerializeToString,
+                response_deserializer=peers__pb2.TransferPeerRateLimitsResp.FromString,
+                )
+        self.ReplicatePeerRateLimits = channel.unary_unary(
+                '/pb.gubernator.PeersV1/ReplicatePeerRateLimits',
+                request_serializer=peers__pb2.ReplicatePeerRateLimitsReq.SerializeToString,
+                response_deserializer=peers__pb2.ReplicatePeerRateLimitsResp.FromString,
+                )
+        self.GetPeerKeyFilter = channel.unary_unary(
+                '/pb.gubernator.PeersV1/GetPeerKeyFilter',
+                request_serializer=peers__pb2.GetPeerKeyFilterReq.SerializeToString,
+                response_deserializer=peers__pb2.GetPeerKeyFilterResp.FromString,
+                )
 
 
 class PeersV1Servicer(object):
@@ -45,6 +152,153 @@ class PeersV1Servicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ResetPeerRateLimits(self, request, context):
+        """Used by AdminV1.ResetRateLimits to remove matching rate limits from each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReservePeerRateLimit(self, request, context):
+        """Used by peers to relay reservations to the owner peer, which holds the reservation
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CommitPeerReservation(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CancelPeerReservation(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RefundPeerRateLimit(self, request, context):
+        """Used by peers to relay refunds to the owner peer, which holds the refundable hits
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def AcquirePeerLease(self, request, context):
+        """Used by peers to relay leases to the owner peer, which holds the lease
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReleasePeerLease(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerNamespaceUsage(self, request, context):
+        """Used by AdminV1.GetNamespaceUsage to collect the usage of the rate limits owned by each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def UpdatePeerOverrides(self, request, context):
+        """Used by AdminV1.SetOverride and AdminV1.DeleteOverride to update the overrides of each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListPeerOverrides(self, request, context):
+        """Used by peers to copy the overrides of an existing peer when they join the cluster
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RegisterPeerLimits(self, request, context):
+        """Used by V1.RegisterLimits to register limit definitions on each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListPeerLimits(self, request, context):
+        """Used by peers to copy the limit definitions of an existing peer when they join the cluster
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerLimitDrift(self, request, context):
+        """Used by AdminV1.GetLimitDrift to collect the drift of the rate limits owned by each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerKeyHistory(self, request, context):
+        """Used by AdminV1.GetKeyHistory to collect the state and recent hits of a rate limit from each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListPeerNamespaces(self, request, context):
+        """Used by AdminV1.ListNamespaces to collect the namespaces owned by each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerVersion(self, request, context):
+        """Used by V1.HealthCheck to collect the build version of each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerTraffic(self, request, context):
+        """Used by AdminV1.GetTraffic to collect the traffic each peer sent to other peers
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReplayPeerJournal(self, request, context):
+        """Used by AdminV1.ReplayJournal to replay the journal of each peer
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def TransferPeerRateLimits(self, request, context):
+        """Used by AdminV1.PrepareShutdown to send the rate limits of a peer which is shutting down to
+        their next owner
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ReplicatePeerRateLimits(self, request, context):
+        """Used by the owner of a rate limit listed in StandbyConfig.Names to replicate its state to the
+        standby peer before responding
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def GetPeerKeyFilter(self, request, context):
+        """Used by peers to answer the rate limits this peer has never seen without forwarding them, see
+        Config.ColdKeyFilterInterval
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_PeersV1Servicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -58,6 +312,111 @@ def add_PeersV1Servicer_to_server(servicer, server):
                     request_deserializer=peers__pb2.UpdatePeerGlobalsReq.FromString,
                     response_serializer=peers__pb2.UpdatePeerGlobalsResp.SerializeToString,
             ),
+            'ResetPeerRateLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.ResetPeerRateLimits,
+                    request_deserializer=peers__pb2.ResetPeerRateLimitsReq.FromString,
+                    response_serializer=peers__pb2.ResetPeerRateLimitsResp.SerializeToString,
+            ),
+            'ReservePeerRateLimit': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReservePeerRateLimit,
+                    request_deserializer=gubernator__pb2.ReserveRateLimitReq.FromString,
+                    response_serializer=gubernator__pb2.ReserveRateLimitResp.SerializeToString,
+            ),
+            'CommitPeerReservation': grpc.unary_unary_rpc_method_handler(
+                    servicer.CommitPeerReservation,
+                    request_deserializer=gubernator__pb2.ReservationReq.FromString,
+                    response_serializer=gubernator__pb2.ReservationResp.SerializeToString,
+            ),
+            'CancelPeerReservation': grpc.unary_unary_rpc_method_handler(
+                    servicer.CancelPeerReservation,
+                    request_deserializer=gubernator__pb2.ReservationReq.FromString,
+                    response_serializer=gubernator__pb2.ReservationResp.SerializeToString,
+            ),
+            'RefundPeerRateLimit': grpc.unary_unary_rpc_method_handler(
+                    servicer.RefundPeerRateLimit,
+                    request_deserializer=gubernator__pb2.RefundReq.FromString,
+                    response_serializer=gubernator__pb2.RefundResp.SerializeToString,
+            ),
+            'AcquirePeerLease': grpc.unary_unary_rpc_method_handler(
+                    servicer.AcquirePeerLease,
+                    request_deserializer=gubernator__pb2.LeaseReq.FromString,
+                    response_serializer=gubernator__pb2.LeaseResp.SerializeToString,
+            ),
+            'ReleasePeerLease': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReleasePeerLease,
+                    request_deserializer=gubernator__pb2.LeaseReq.FromString,
+                    response_serializer=gubernator__pb2.LeaseResp.SerializeToString,
+            ),
+            'GetPeerNamespaceUsage': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerNamespaceUsage,
+                    request_deserializer=admin__pb2.GetNamespaceUsageReq.FromString,
+                    response_serializer=admin__pb2.GetNamespaceUsageResp.SerializeToString,
+            ),
+            'UpdatePeerOverrides': grpc.unary_unary_rpc_method_handler(
+                    servicer.UpdatePeerOverrides,
+                    request_deserializer=peers__pb2.UpdatePeerOverridesReq.FromString,
+                    response_serializer=peers__pb2.UpdatePeerOverridesResp.SerializeToString,
+            ),
+            'ListPeerOverrides': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListPeerOverrides,
+                    request_deserializer=admin__pb2.ListOverridesReq.FromString,
+                    response_serializer=admin__pb2.ListOverridesResp.SerializeToString,
+            ),
+            'RegisterPeerLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.RegisterPeerLimits,
+                    request_deserializer=gubernator__pb2.RegisterLimitsReq.FromString,
+                    response_serializer=gubernator__pb2.RegisterLimitsResp.SerializeToString,
+            ),
+            'ListPeerLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListPeerLimits,
+                    request_deserializer=peers__pb2.ListPeerLimitsReq.FromString,
+                    response_serializer=peers__pb2.ListPeerLimitsResp.SerializeToString,
+            ),
+            'GetPeerLimitDrift': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerLimitDrift,
+                    request_deserializer=admin__pb2.GetLimitDriftReq.FromString,
+                    response_serializer=admin__pb2.GetLimitDriftResp.SerializeToString,
+            ),
+            'GetPeerKeyHistory': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerKeyHistory,
+                    request_deserializer=admin__pb2.GetKeyHistoryReq.FromString,
+                    response_serializer=admin__pb2.GetKeyHistoryResp.SerializeToString,
+            ),
+            'ListPeerNamespaces': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListPeerNamespaces,
+                    request_deserializer=admin__pb2.ListNamespacesReq.FromString,
+                    response_serializer=admin__pb2.ListNamespacesResp.SerializeToString,
+            ),
+            'GetPeerVersion': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerVersion,
+                    request_deserializer=peers__pb2.GetPeerVersionReq.FromString,
+                    response_serializer=peers__pb2.GetPeerVersionResp.SerializeToString,
+            ),
+            'GetPeerTraffic': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerTraffic,
+                    request_deserializer=admin__pb2.GetTrafficReq.FromString,
+                    response_serializer=admin__pb2.GetTrafficResp.SerializeToString,
+            ),
+            'ReplayPeerJournal': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReplayPeerJournal,
+                    request_deserializer=admin__pb2.ReplayJournalReq.FromString,
+                    response_serializer=admin__pb2.ReplayJournalResp.SerializeToString,
+            ),
+            'TransferPeerRateLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.TransferPeerRateLimits,
+                    request_deserializer=peers__pb2.TransferPeerRateLimitsReq.FromString,
+                    response_serializer=peers__pb2.TransferPeerRateLimitsResp.SerializeToString,
+            ),
+            'ReplicatePeerRateLimits': grpc.unary_unary_rpc_method_handler(
+                    servicer.ReplicatePeerRateLimits,
+                    request_deserializer=peers__pb2.ReplicatePeerRateLimitsReq.FromString,
+                    response_serializer=peers__pb2.ReplicatePeerRateLimitsResp.SerializeToString,
+            ),
+            'GetPeerKeyFilter': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetPeerKeyFilter,
+                    request_deserializer=peers__pb2.GetPeerKeyFilterReq.FromString,
+                    response_serializer=peers__pb2.GetPeerKeyFilterResp.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'pb.gubernator.PeersV1', rpc_method_handlers)
@@ -102,3 +461,360 @@ class PeersV1(object):
             peers__pb2.UpdatePeerGlobalsResp.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ResetPeerRateLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ResetPeerRateLimits',
+            peers__pb2.ResetPeerRateLimitsReq.SerializeToString,
+            peers__pb2.ResetPeerRateLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReservePeerRateLimit(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ReservePeerRateLimit',
+            gubernator__pb2.ReserveRateLimitReq.SerializeToString,
+            gubernator__pb2.ReserveRateLimitResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CommitPeerReservation(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/CommitPeerReservation',
+            gubernator__pb2.ReservationReq.SerializeToString,
+            gubernator__pb2.ReservationResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CancelPeerReservation(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/CancelPeerReservation',
+            gubernator__pb2.ReservationReq.SerializeToString,
+            gubernator__pb2.ReservationResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def RefundPeerRateLimit(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/RefundPeerRateLimit',
+            gubernator__pb2.RefundReq.SerializeToString,
+            gubernator__pb2.RefundResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def AcquirePeerLease(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/AcquirePeerLease',
+            gubernator__pb2.LeaseReq.SerializeToString,
+            gubernator__pb2.LeaseResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReleasePeerLease(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ReleasePeerLease',
+            gubernator__pb2.LeaseReq.SerializeToString,
+            gubernator__pb2.LeaseResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerNamespaceUsage(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerNamespaceUsage',
+            admin__pb2.GetNamespaceUsageReq.SerializeToString,
+            admin__pb2.GetNamespaceUsageResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def UpdatePeerOverrides(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/UpdatePeerOverrides',
+            peers__pb2.UpdatePeerOverridesReq.SerializeToString,
+            peers__pb2.UpdatePeerOverridesResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListPeerOverrides(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ListPeerOverrides',
+            admin__pb2.ListOverridesReq.SerializeToString,
+            admin__pb2.ListOverridesResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def RegisterPeerLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/RegisterPeerLimits',
+            gubernator__pb2.RegisterLimitsReq.SerializeToString,
+            gubernator__pb2.RegisterLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListPeerLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ListPeerLimits',
+            peers__pb2.ListPeerLimitsReq.SerializeToString,
+            peers__pb2.ListPeerLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerLimitDrift(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerLimitDrift',
+            admin__pb2.GetLimitDriftReq.SerializeToString,
+            admin__pb2.GetLimitDriftResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerKeyHistory(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerKeyHistory',
+            admin__pb2.GetKeyHistoryReq.SerializeToString,
+            admin__pb2.GetKeyHistoryResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListPeerNamespaces(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ListPeerNamespaces',
+            admin__pb2.ListNamespacesReq.SerializeToString,
+            admin__pb2.ListNamespacesResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerVersion(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerVersion',
+            peers__pb2.GetPeerVersionReq.SerializeToString,
+            peers__pb2.GetPeerVersionResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerTraffic(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerTraffic',
+            admin__pb2.GetTrafficReq.SerializeToString,
+            admin__pb2.GetTrafficResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReplayPeerJournal(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ReplayPeerJournal',
+            admin__pb2.ReplayJournalReq.SerializeToString,
+            admin__pb2.ReplayJournalResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def TransferPeerRateLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/TransferPeerRateLimits',
+            peers__pb2.TransferPeerRateLimitsReq.SerializeToString,
+            peers__pb2.TransferPeerRateLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ReplicatePeerRateLimits(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/ReplicatePeerRateLimits',
+            peers__pb2.ReplicatePeerRateLimitsReq.SerializeToString,
+            peers__pb2.ReplicatePeerRateLimitsResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def GetPeerKeyFilter(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.gubernator.PeersV1/GetPeerKeyFilter',
+            peers__pb2.GetPeerKeyFilterReq.SerializeToString,
+            peers__pb2.GetPeerKeyFilterResp.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -0,0 +1,89 @@
+# Copyright 2018-2022 Mailgun Technologies Inc
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+# This code is py3.7 and py2.7 compatible
+
+import bisect
+import hashlib
+
+# The default number of replicas of each peer on the ring, the same as the
+# default of GUBER_REPLICATED_HASH_REPLICAS
+DEFAULT_REPLICAS = 512
+
+_FNV64_OFFSET = 14695981039346656037
+_FNV64_PRIME = 1099511628211
+_MASK64 = (1 << 64) - 1
+
+
+def fnv1_64(s):
+    """Returns the 64 bit FNV-1 hash of the string `s`, the default hash of the
+    gubernator ring."""
+    h = _FNV64_OFFSET
+    for b in bytearray(s.encode('utf-8')):
+        h = ((h * _FNV64_PRIME) & _MASK64) ^ b
+    return h
+
+
+def hash_key(req):
+    """Returns the key which picks the owner of the rate limit `req`, the same
+    as RateLimitReq.HashKey() in Go."""
+    return req.name + '_' + req.unique_key
+
+
+class ReplicatedConsistentHash(object):
+    """A port of the Go ReplicatedConsistentHash, such that a client picks the
+    same owner of a rate limit as the gubernator instances do and can send the
+    rate limit to its owner, saving the instance a forward to its peer.
+
+    Peers are the GRPC addresses of the instances, as advertised to each
+    other; the ring only matches the instances when both use the same
+    addresses, replicas and hash function."""
+
+    def __init__(self, replicas=DEFAULT_REPLICAS, hash_func=fnv1_64):
+        self._replicas = replicas
+        self._hash = hash_func
+        self._hashes = []
+        self._peers = []
+
+    def add(self, address, weight=1):
+        """Adds the peer `address` to the ring, the number of replicas of the
+        peer is multiplied by `weight`."""
+        key = hashlib.md5(address.encode('utf-8')).hexdigest()
+        ring = list(zip(self._hashes, self._peers))
+        for i in range(self._replicas * max(weight, 1)):
+            ring.append((self._hash(str(i) + key), address))
+        # Go sorts by hash alone with a stable merge, ties keep the first peer
+        ring.sort(key=lambda e: e[0])
+        self._hashes = [e[0] for e in ring]
+        self._peers = [e[1] for e in ring]
+
+    def remove(self, address):
+        """Removes the peer `address` and its replicas from the ring."""
+        ring = [e for e in zip(self._hashes, self._peers) if e[1] != address]
+        self._hashes = [e[0] for e in ring]
+        self._peers = [e[1] for e in ring]
+
+    def peers(self):
+        return sorted(set(self._peers))
+
+    def get(self, key):
+        """Returns the address of the peer which owns `key`."""
+        if not self._peers:
+            raise ValueError('unable to pick a peer; pool is empty')
+        idx = bisect.bisect_left(self._hashes, self._hash(key))
+        # Means we have cycled back to the first peer
+        if idx == len(self._hashes):
+            idx = 0
+        return self._peers[idx]
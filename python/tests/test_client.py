@@ -13,7 +13,7 @@
 # limitations under the License.
 #
 
-from gubernator import ratelimit_pb2 as pb
+from gubernator import gubernator_pb2 as pb
 
 import pytest
 import subprocess
@@ -26,26 +26,29 @@ def cluster():
     args = ["/bin/sh", "-c",
             "go run ./cmd/gubernator-cluster/main.go"]
 
-    os.chdir("golang")
-    proc = subprocess.Popen(args, stdout=subprocess.PIPE)
     os.chdir("..")
+    proc = subprocess.Popen(args, stdout=subprocess.PIPE)
+    os.chdir("python")
 
     while True:
         line = proc.stdout.readline()
-        if b'Ready' in line:
+        if b'Running' in line:
             break
     yield proc
     proc.kill()
 
 
+PEERS = ['127.0.0.1:999{}'.format(i) for i in range(6)]
+
+
 def test_health_check(cluster):
-    client = gubernator.V1Client()
-    resp = client.health_check()
+    client = gubernator.V1Client(PEERS[0])
+    resp = client.HealthCheck(pb.HealthCheckReq())
     print("Health:", resp)
 
 
 def test_get_rate_limit(cluster):
-    req = pb.Requests()
+    req = pb.GetRateLimitsReq()
     rate_limit = req.requests.add()
 
     rate_limit.algorithm = pb.TOKEN_BUCKET
@@ -55,6 +58,22 @@ def test_get_rate_limit(cluster):
     rate_limit.unique_key = 'domain-id-0001'
     rate_limit.hits = 1
 
-    client = gubernator.V1Client()
+    client = gubernator.V1Client(PEERS[0])
     resp = client.GetRateLimits(req, timeout=0.5)
     print("RateLimit: {}".format(resp))
+
+
+def test_client_get_rate_limits(cluster):
+    client = gubernator.Client(PEERS, batch_size=2)
+    requests = [pb.RateLimitReq(
+        name='test_client', unique_key='account:{}'.format(i), hits=1,
+        limit=10, duration=gubernator.SECOND * 2) for i in range(10)]
+
+    resp = client.get_rate_limits(requests, timeout=0.5)
+    assert len(resp.responses) == len(requests)
+    for r in resp.responses:
+        assert r.status == pb.UNDER_LIMIT
+        assert r.remaining == 9
+        # The rate limit was sent to its owner, as such was not forwarded
+        assert 'owner' not in r.metadata
+    client.close()
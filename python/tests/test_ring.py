@@ -0,0 +1,45 @@
+# Copyright 2018-2022 Mailgun Technologies Inc
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+# http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+
+from gubernator.ring import ReplicatedConsistentHash, fnv1_64
+
+import pytest
+
+
+def test_fnv1_64():
+    assert fnv1_64('') == 14695981039346656037
+    assert fnv1_64('a') == 0xaf63bd4c8601b7be
+
+
+def test_ring_matches_go():
+    # Owners picked by the Go ReplicatedConsistentHash of the same peers
+    ring = ReplicatedConsistentHash()
+    for address in ['10.0.0.1:1051', '10.0.0.2:1051', '10.0.0.3:1051']:
+        ring.add(address)
+
+    assert ring.get('requests_per_sec_account:1') == '10.0.0.1:1051'
+    assert ring.get('requests_per_sec_account:2') == '10.0.0.1:1051'
+    assert ring.get('login_10.1.1.1') == '10.0.0.2:1051'
+    assert ring.get('a_b') == '10.0.0.2:1051'
+    assert ring.get('api_key-1234') == '10.0.0.1:1051'
+
+    ring.remove('10.0.0.2:1051')
+    assert ring.get('a_b') != '10.0.0.2:1051'
+    assert ring.peers() == ['10.0.0.1:1051', '10.0.0.3:1051']
+
+
+def test_empty_ring():
+    with pytest.raises(ValueError):
+        ReplicatedConsistentHash().get('a_b')
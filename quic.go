@@ -0,0 +1,221 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+// EXPERIMENTAL: Peer RPCs over HTTP/3 (QUIC)
+//
+// Peers forward rate limits to each other over a single GRPC connection, during a packet loss
+// event every in-flight request on that connection waits for the lost TCP segment to be
+// retransmitted. With the QUIC transport each peer RPC is sent as an HTTP/3 request on its own
+// QUIC stream, such that a lost packet only delays the requests which were in that packet.
+//
+// The HTTP/3 server listens on the UDP port of the same address as the GRPC listener, as such
+// no additional addresses need to be discovered. The request and response bodies are the binary
+// protobuf encoding of the PeersV1 messages, errors are returned as an encoded `google.rpc.Status`.
+// Client traffic continues to use GRPC over HTTP/2.
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/quic-go/quic-go/http3"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// PeerTransportGRPC sends peer RPCs over GRPC, this is the default
+	PeerTransportGRPC = "grpc"
+	// PeerTransportQUIC sends peer RPCs over HTTP/3 (QUIC). EXPERIMENTAL
+	PeerTransportQUIC = "quic"
+
+	// The max size of a peer request or response body, the same as the GRPC default
+	maxQUICMessageSize = 4 << 20
+	quicContentType    = "application/x-protobuf"
+)
+
+func validatePeerTransport(transport string) error {
+	switch transport {
+	case "", PeerTransportGRPC, PeerTransportQUIC:
+		return nil
+	}
+	return fmt.Errorf("invalid peer transport '%s'; choices are [%s,%s]", transport, PeerTransportGRPC, PeerTransportQUIC)
+}
+
+// NewQUICPeerServer returns an HTTP/3 server which serves the PeersV1 service on the UDP port of
+// `address`. If `auth` is not nil, requests without an accepted token are rejected.
+// Call ListenAndServe() to start serving and Close() to stop.
+func NewQUICPeerServer(srv PeersV1Server, address string, conf *tls.Config, auth *PeerAuthConfig) *http3.Server {
+	handlers := make(map[string]grpc.MethodDesc)
+	for _, m := range PeersV1_ServiceDesc.Methods {
+		handlers[fmt.Sprintf("/%s/%s", PeersV1_ServiceDesc.ServiceName, m.MethodName)] = m
+	}
+
+	return &http3.Server{
+		Addr:      address,
+		TLSConfig: conf,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m, ok := handlers[r.URL.Path]
+			if !ok || r.Method != http.MethodPost {
+				writeQUICError(w, status.Errorf(codes.Unimplemented, "unknown method '%s'", r.URL.Path))
+				return
+			}
+
+			if auth != nil {
+				md := metadata.Pairs(peerAuthHeader, r.Header.Get(peerAuthHeader))
+				if err := auth.authorize(metadata.NewIncomingContext(r.Context(), md)); err != nil {
+					writeQUICError(w, err)
+					return
+				}
+			}
+
+			b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxQUICMessageSize))
+			if err != nil {
+				writeQUICError(w, status.Errorf(codes.ResourceExhausted, "while reading request: %s", err))
+				return
+			}
+			dec := func(in interface{}) error {
+				if err := proto.Unmarshal(b, in.(proto.Message)); err != nil {
+					return status.Errorf(codes.InvalidArgument, "while decoding request: %s", err)
+				}
+				return nil
+			}
+
+			out, err := m.Handler(srv, r.Context(), dec, nil)
+			if err != nil {
+				writeQUICError(w, err)
+				return
+			}
+			b, err = proto.Marshal(out.(proto.Message))
+			if err != nil {
+				writeQUICError(w, status.Errorf(codes.Internal, "while encoding response: %s", err))
+				return
+			}
+			w.Header().Set("Content-Type", quicContentType)
+			_, _ = w.Write(b)
+		}),
+	}
+}
+
+func writeQUICError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	b, _ := proto.Marshal(st.Proto())
+	w.Header().Set("Content-Type", quicContentType)
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	_, _ = w.Write(b)
+}
+
+// quicClientConn implements grpc.ClientConnInterface by sending each unary RPC as an HTTP/3
+// request, such that the generated PeersV1Client can be used with the QUIC transport.
+type quicClientConn struct {
+	url       string
+	client    *http.Client
+	transport *http3.RoundTripper
+	auth      string
+	// If not nil, the RPCs sent to the peer are recorded
+	stats *peerStats
+}
+
+var _ grpc.ClientConnInterface = &quicClientConn{}
+
+func newQUICClientConn(address string, conf *tls.Config, auth *PeerAuthConfig) *quicClientConn {
+	rt := &http3.RoundTripper{TLSClientConfig: conf}
+	c := &quicClientConn{
+		url:       "https://" + address,
+		client:    &http.Client{Transport: rt},
+		transport: rt,
+	}
+	if auth != nil {
+		c.auth = "Bearer " + auth.Token
+	}
+	return c
+}
+
+func (c *quicClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, _ ...grpc.CallOption) error {
+	if c.stats == nil {
+		return c.invoke(ctx, method, args, reply)
+	}
+	start := clock.Now()
+	err := c.invoke(ctx, method, args, reply)
+	c.stats.finish(ctx, err, clock.Since(start))
+	return err
+}
+
+func (c *quicClientConn) invoke(ctx context.Context, method string, args, reply interface{}) error {
+	b, err := proto.Marshal(args.(proto.Message))
+	if err != nil {
+		return status.Errorf(codes.Internal, "while encoding request: %s", err)
+	}
+	if c.stats != nil {
+		c.stats.addSent(len(b))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+method, bytes.NewReader(b))
+	if err != nil {
+		return status.Errorf(codes.Internal, "while creating request: %s", err)
+	}
+	req.Header.Set("Content-Type", quicContentType)
+	if c.auth != "" {
+		req.Header.Set(peerAuthHeader, c.auth)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Unavailable, "while sending '%s': %s", method, err)
+	}
+	defer resp.Body.Close()
+
+	b, err = io.ReadAll(io.LimitReader(resp.Body, maxQUICMessageSize))
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "while reading response: %s", err)
+	}
+	if c.stats != nil {
+		c.stats.addReceived(len(b))
+	}
+	if resp.StatusCode != http.StatusOK {
+		var st spb.Status
+		if !strings.HasPrefix(resp.Header.Get("Content-Type"), quicContentType) || proto.Unmarshal(b, &st) != nil {
+			return status.Errorf(codes.Unavailable, "'%s' returned '%s'", method, resp.Status)
+		}
+		return status.ErrorProto(&st)
+	}
+	if err := proto.Unmarshal(b, reply.(proto.Message)); err != nil {
+		return status.Errorf(codes.Internal, "while decoding response: %s", err)
+	}
+	return nil
+}
+
+func (c *quicClientConn) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "streams are not supported by the QUIC peer transport")
+}
+
+func (c *quicClientConn) Close() error {
+	return c.transport.Close()
+}
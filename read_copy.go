@@ -0,0 +1,138 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+)
+
+// readCopies holds a copy of each rate limit in the cache of a worker, such that query only
+// requests (Hits = 0) are evaluated by the caller instead of queueing behind the hits the worker
+// is evaluating. Only the worker publishes copies, after each command which changed the rate
+// limit; a published copy is replaced and never modified, as such readers need no lock. Readers of
+// a rate limit which is being updated see the copy published before the update.
+//
+// Keys which are read far more often than written stay in the read only map of the sync.Map,
+// which is read without a lock.
+type readCopies struct {
+	// The caches of the worker, see Config.CacheClasses
+	caches []ReadCopyCache
+	items  sync.Map
+}
+
+func newReadCopies(caches []ReadCopyCache) *readCopies {
+	return &readCopies{caches: caches}
+}
+
+// publish replaces the copy of the rate limit `key` with the item in the cache
+func (r *readCopies) publish(key string) {
+	for _, c := range r.caches {
+		if item, ok := c.Peek(key); ok {
+			r.items.Store(key, item.copy())
+			return
+		}
+	}
+	r.items.Delete(key)
+}
+
+// remove removes the copy of the rate limit `key`, which left the cache
+func (r *readCopies) remove(key string) {
+	r.items.Delete(key)
+}
+
+// get returns the copy of the rate limit `key`, unless it expired
+func (r *readCopies) get(key string) (*CacheItem, bool) {
+	v, ok := r.items.Load(key)
+	if !ok {
+		return nil, false
+	}
+	item := v.(*CacheItem)
+	if item.IsExpired() {
+		return nil, false
+	}
+	return item, true
+}
+
+// publish replaces the copy of the rate limit `key` read by readRateLimit()
+func (worker *Worker) publish(key string) {
+	if worker.readCopies != nil {
+		worker.readCopies.publish(key)
+	}
+}
+
+// readRateLimit evaluates the query only request `r` against the copy of its rate limit without
+// waiting for the worker. Returns false if the request must be evaluated by the worker, IE: the
+// rate limit is not in the cache or the request has side effects.
+func (worker *Worker) readRateLimit(ctx context.Context, r *RateLimitReq, reqState RateLimitReqState) (*RateLimitResp, bool) {
+	// The over-limit count is only held by the dispatch loop
+	if worker.readCopies == nil || !isQueryOnly(r) || r.IdempotencyKey != "" || r.Metadata[MetadataOverLimitCount] == "true" {
+		return nil, false
+	}
+	item, ok := worker.readCopies.get(worker.conf.HashKey(r))
+	if !ok {
+		return nil, false
+	}
+
+	// The Store is only consulted on a miss or after a hit, neither of which apply.
+	reqState.readCopy = true
+	var rl *RateLimitResp
+	var err error
+	switch r.Algorithm {
+	case Algorithm_TOKEN_BUCKET:
+		rl, err = tokenBucket(ctx, nil, readCopyCache{item: item}, worker.conf, r, reqState)
+	case Algorithm_LEAKY_BUCKET:
+		rl, err = leakyBucket(ctx, nil, readCopyCache{item: item}, worker.conf, r, reqState)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		// Let the worker report the error
+		return nil, false
+	}
+	worker.readCounter.Inc()
+	return rl, true
+}
+
+// readCopyCache is the Cache a query only request is evaluated against by readRateLimit(), which
+// only holds the copy of the requested rate limit. The algorithms never change the cache while
+// evaluating a query only request, nor the item, which they copy first.
+type readCopyCache struct {
+	item *CacheItem
+}
+
+var _ Cache = readCopyCache{}
+
+func (c readCopyCache) GetItem(key string) (*CacheItem, bool) {
+	if key != c.item.Key {
+		return nil, false
+	}
+	return c.item, true
+}
+
+func (c readCopyCache) Each() chan *CacheItem {
+	out := make(chan *CacheItem, 1)
+	out <- c.item
+	close(out)
+	return out
+}
+
+func (readCopyCache) Add(*CacheItem) bool                 { return false }
+func (readCopyCache) UpdateExpiration(string, int64) bool { return false }
+func (readCopyCache) Remove(string)                       {}
+func (readCopyCache) Size() int64                         { return 1 }
+func (readCopyCache) Close() error                        { return nil }
@@ -0,0 +1,57 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// How unique keys appear in logs and traces, see Config.KeyRedaction
+const (
+	// KeyRedactionHash replaces the key with a short SHA-256 digest of the key, IE: "sha256:9f86d081884c7d65",
+	// such that the log lines of the same key can still be correlated
+	KeyRedactionHash = "hash"
+	// KeyRedactionMask replaces the key with redactedKeyMask
+	KeyRedactionMask = "mask"
+	// KeyRedactionNone logs the key as is, intended for debugging
+	KeyRedactionNone = "none"
+)
+
+// redactedKeyMask replaces the keys redacted by KeyRedactionMask
+const redactedKeyMask = "[redacted]"
+
+// redactedKeyDigestLength is the number of hex characters of the digest kept by KeyRedactionHash
+const redactedKeyDigestLength = 16
+
+// redactKey returns `key` as it may appear in logs and traces according to `policy`. An empty
+// policy redacts as KeyRedactionHash, such that keys are never logged unless explicitly asked for.
+// The empty key is returned as is, as it does not identify anything.
+func redactKey(policy, key string) string {
+	if key == "" {
+		return key
+	}
+	switch policy {
+	case KeyRedactionNone:
+		return key
+	case KeyRedactionMask:
+		return redactedKeyMask
+	default:
+		sum := sha256.Sum256([]byte(key))
+		return hashedKeyPrefix + hex.EncodeToString(sum[:])[:redactedKeyDigestLength]
+	}
+}
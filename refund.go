@@ -0,0 +1,118 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRefundID is set in the metadata of a RateLimitResp to the id which refunds the hits
+// of a request with the REFUNDABLE behavior, see RefundRateLimit.
+const MetadataRefundID = "refund_id"
+
+// RefundRateLimit returns the hits of a REFUNDABLE request to the rate limit.
+func (s *V1Instance) RefundRateLimit(ctx context.Context, r *RefundReq) (*RefundResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.RefundRateLimit")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	s.normalizeKey(&r.Name, &r.UniqueKey)
+	if err := validateRefund(r); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+
+	key := s.conf.HashKey(&RateLimitReq{Name: r.Name, UniqueKey: r.UniqueKey})
+	peer, err := s.GetPeer(ctx, key)
+	if err != nil {
+		countError(err, "Error in GetPeer")
+		return nil, status.Errorf(codes.Unavailable, "while looking up peer that owns rate limit '%s': %s", key, err)
+	}
+	if peer.Info().IsOwner {
+		return s.refundLocal(ctx, r)
+	}
+	return peer.RefundPeerRateLimit(ctx, r)
+}
+
+// RefundPeerRateLimit is called by other peers to refund hits held by this peer.
+func (s *V1Instance) RefundPeerRateLimit(ctx context.Context, r *RefundReq) (*RefundResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.RefundPeerRateLimit")).ObserveDuration()
+	if err := validateRefund(r); err != nil {
+		return nil, err
+	}
+	return s.refundLocal(ctx, r)
+}
+
+func (s *V1Instance) refundLocal(ctx context.Context, r *RefundReq) (*RefundResp, error) {
+	key := s.conf.HashKey(&RateLimitReq{Name: r.Name, UniqueKey: r.UniqueKey})
+	hits, ok, err := s.workerPool.Refund(ctx, key, r.RefundId)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound,
+			"refund '%s' not found; the refund window may have passed or it was already refunded", r.RefundId)
+	}
+	return &RefundResp{Hits: hits}, nil
+}
+
+func validateRefund(r *RefundReq) error {
+	switch {
+	case r.UniqueKey == "":
+		return status.Error(codes.InvalidArgument, "field 'unique_key' cannot be empty")
+	case r.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case r.RefundId == "":
+		return status.Error(codes.InvalidArgument, "field 'refund_id' cannot be empty")
+	}
+	return nil
+}
+
+// addRefund holds the hits just applied by a REFUNDABLE request for `BehaviorConfig.RefundWindow`
+// and returns the id of the hold in the response metadata.
+func (worker *Worker) addRefund(r *RateLimitReq, rl *RateLimitResp, cache Cache) {
+	if r.Hits <= 0 || rl.Status != Status_UNDER_LIMIT || HasBehavior(r.Behavior, Behavior_GLOBAL) {
+		return
+	}
+	expireAt := epochMillis(clock.Now().Add(worker.conf.Behaviors.RefundWindow))
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata[MetadataRefundID] = worker.holdHits(r, expireAt, true, cache)
+	metricRefundCounter.WithLabelValues("recorded").Inc()
+}
+
+func (worker *Worker) refundHits(request workerReleaseRequest) workerReleaseResponse {
+	res, ok := worker.reservations[request.id]
+	if !ok || res.key != request.key || !res.refundable {
+		return workerReleaseResponse{}
+	}
+	delete(worker.reservations, request.id)
+	if res.expireAt <= epochMillis(clock.Now()) {
+		// The refund window passed before the sweep could remove it.
+		metricRefundCounter.WithLabelValues("expired").Inc()
+		return workerReleaseResponse{}
+	}
+
+	worker.refund(request.ctx, res)
+	metricRefundCounter.WithLabelValues("refunded").Inc()
+	return workerReleaseResponse{hits: res.req.Hits, ok: true}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/fasthash/fnv1a"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// limitRegistry holds a copy of every limit registered via V1.RegisterLimits. Limits are copied
+// to every peer, such that the peer which receives a request can apply the limit before the
+// request is forwarded to the owning peer. Limits are held in memory only, a peer which joins an
+// existing cluster copies the limits of another peer, see V1Instance.syncLimits().
+type limitRegistry struct {
+	mutex  sync.RWMutex
+	limits map[string]*registeredLimit
+}
+
+// registeredLimit is a registered limit and the templates which apply it to requests
+type registeredLimit struct {
+	limit    *RegisteredLimit
+	template *RateLimitTemplate
+	// Is nil unless the limit replaced another limit with a rollout, see `RegisteredLimit.rollout_duration`
+	from *RateLimitTemplate
+}
+
+func newRegisteredLimit(l *RegisteredLimit) *registeredLimit {
+	r := &registeredLimit{
+		limit:    proto.Clone(l).(*RegisteredLimit),
+		template: registeredTemplate(l),
+	}
+	if l.RolloutFrom != nil && l.RolloutDuration > 0 {
+		r.from = registeredTemplate(l.RolloutFrom)
+	}
+	return r
+}
+
+// templateFor returns the template which applies to the hash key at `now`. While the limit is
+// rolling out, the share of hash keys which use the new limit increases linearly from
+// `rollout_start_percent` to 100, the hash keys which use the old limit are chosen by their hash.
+func (r *registeredLimit) templateFor(hashKey string, now int64) *RateLimitTemplate {
+	if r.from == nil {
+		return r.template
+	}
+	elapsed := now - r.limit.RolloutStart
+	if elapsed >= r.limit.RolloutDuration {
+		return r.template
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	start := float64(r.limit.RolloutStartPercent)
+	percent := start + (100-start)*float64(elapsed)/float64(r.limit.RolloutDuration)
+	if float64(fnv1a.HashString64(hashKey)%100) < percent {
+		return r.template
+	}
+	return r.from
+}
+
+func newLimitRegistry() *limitRegistry {
+	return &limitRegistry{limits: make(map[string]*registeredLimit)}
+}
+
+// get returns the template of the limit registered with the name which applies to the hash key,
+// or nil if none is registered
+func (r *limitRegistry) get(name, hashKey string) *RateLimitTemplate {
+	r.mutex.RLock()
+	l, ok := r.limits[name]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	return l.templateFor(hashKey, epochMillis(clock.Now()))
+}
+
+// registered returns the limit registered with the name, or nil if none is registered
+func (r *limitRegistry) registered(name string) *RegisteredLimit {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if l, ok := r.limits[name]; ok {
+		return l.limit
+	}
+	return nil
+}
+
+// update adds or replaces the limits
+func (r *limitRegistry) update(limits []*RegisteredLimit) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, l := range limits {
+		r.limits[l.Name] = newRegisteredLimit(l)
+	}
+}
+
+// merge adds the limits which are not already registered
+func (r *limitRegistry) merge(limits []*RegisteredLimit) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, l := range limits {
+		if _, ok := r.limits[l.Name]; !ok {
+			r.limits[l.Name] = newRegisteredLimit(l)
+		}
+	}
+}
+
+// list returns the registered limits sorted by name
+func (r *limitRegistry) list() []*RegisteredLimit {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	result := make([]*RegisteredLimit, 0, len(r.limits))
+	for _, l := range r.limits {
+		result = append(result, proto.Clone(l.limit).(*RegisteredLimit))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// registeredTemplate returns the template which applies the registered limit to requests
+func registeredTemplate(l *RegisteredLimit) *RateLimitTemplate {
+	return &RateLimitTemplate{
+		Name:      l.Name,
+		Limit:     l.Limit,
+		Duration:  time.Duration(l.Duration) * time.Millisecond,
+		Algorithm: l.Algorithm,
+		Behavior:  l.Behavior,
+		Burst:     l.Burst,
+	}
+}
+
+// template returns the template which applies to the request. Templates set by `Config.Templates`
+// take precedence over limits registered via RegisterLimits.
+func (s *V1Instance) template(r *RateLimitReq) *RateLimitTemplate {
+	if t, ok := s.templates[r.Name]; ok {
+		return t
+	}
+	return s.registeredLimits.get(r.Name, r.HashKey())
+}
+
+// RegisterLimits registers the limits on every peer in the cluster, including peers in other regions.
+func (s *V1Instance) RegisterLimits(ctx context.Context, r *RegisterLimitsReq) (*RegisterLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.RegisterLimits")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	if len(r.Limits) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "field 'limits' cannot be empty")
+	}
+	for _, l := range r.Limits {
+		if err := validateRegisteredLimit(l); err != nil {
+			return nil, err
+		}
+		if _, ok := s.templates[l.Name]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "limit '%s' is defined by a template on the server", l.Name)
+		}
+	}
+	// Every peer must roll out the limit from the same start and limit
+	now := epochMillis(clock.Now())
+	for _, l := range r.Limits {
+		l.RolloutStart, l.RolloutFrom = 0, nil
+		if l.RolloutDuration == 0 {
+			continue
+		}
+		if prev := s.registeredLimits.registered(l.Name); prev != nil {
+			l.RolloutStart = now
+			l.RolloutFrom = &RegisteredLimit{
+				Name:      prev.Name,
+				Limit:     prev.Limit,
+				Duration:  prev.Duration,
+				Algorithm: prev.Algorithm,
+				Behavior:  prev.Behavior,
+				Burst:     prev.Burst,
+			}
+		}
+	}
+
+	errs := s.broadcastLimits(ctx, r)
+	s.log.WithField("limits", len(r.Limits)).
+		WithField("errors", len(errs)).
+		Debug("limits registered")
+	return &RegisterLimitsResp{Errors: errs}, nil
+}
+
+// RegisterPeerLimits is called by other peers to register limits on this peer.
+func (s *V1Instance) RegisterPeerLimits(ctx context.Context, r *RegisterLimitsReq) (*RegisterLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.RegisterPeerLimits")).ObserveDuration()
+	for _, l := range r.Limits {
+		if err := validateRegisteredLimit(l); err != nil {
+			return nil, err
+		}
+	}
+	s.registeredLimits.update(r.Limits)
+	return &RegisterLimitsResp{}, nil
+}
+
+// ListPeerLimits is called by peers which joined the cluster to copy the limits registered on this peer.
+func (s *V1Instance) ListPeerLimits(ctx context.Context, r *ListPeerLimitsReq) (*ListPeerLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ListPeerLimits")).ObserveDuration()
+	return &ListPeerLimitsResp{Limits: s.registeredLimits.list()}, nil
+}
+
+// broadcastLimits registers the limits on every peer in the cluster and returns an
+// error for each peer which failed.
+func (s *V1Instance) broadcastLimits(ctx context.Context, r *RegisterLimitsReq) []string {
+	peers := s.GetPeerList()
+	for _, picker := range s.GetRegionPickers() {
+		peers = append(peers, picker.Peers()...)
+	}
+
+	// Always update this instance, even if it is not yet in the list of peers
+	s.registeredLimits.update(r.Limits)
+
+	var (
+		errs  []string
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	for _, peer := range peers {
+		if peer.Info().IsOwner {
+			continue
+		}
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			if _, err := peer.RegisterPeerLimits(ctx, r); err != nil {
+				mutex.Lock()
+				errs = append(errs, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				mutex.Unlock()
+			}
+		}(peer)
+	}
+	wg.Wait()
+	return errs
+}
+
+// syncLimits copies the registered limits of the first peer in the local data center which
+// responds. Limits registered on this instance while syncing take precedence over the copy.
+// Until a peer responds, syncLimits is called again each time the peers change.
+func (s *V1Instance) syncLimits() {
+	defer s.limitsSyncing.Store(false)
+
+	var peers []*PeerClient
+	for _, peer := range s.GetPeerList() {
+		if !peer.Info().IsOwner {
+			peers = append(peers, peer)
+		}
+	}
+	for _, peer := range peers {
+		ctx, cancel := context.WithTimeout(context.Background(), s.conf.Behaviors.BatchTimeout)
+		resp, err := peer.ListPeerLimits(ctx, &ListPeerLimitsReq{})
+		cancel()
+		if err != nil {
+			s.log.WithError(err).WithField("peer", peer.Info().GRPCAddress).
+				Debug("while copying registered limits from peer")
+			continue
+		}
+		s.registeredLimits.merge(resp.Limits)
+		s.limitsSynced.Store(true)
+		if len(resp.Limits) > 0 {
+			s.log.WithField("limits", len(resp.Limits)).
+				WithField("peer", peer.Info().GRPCAddress).
+				Info("copied registered limits from peer")
+		}
+		return
+	}
+	if len(peers) > 0 {
+		s.log.Warn("unable to copy registered limits from any peer; will retry when the peers change")
+	}
+}
+
+func validateRegisteredLimit(l *RegisteredLimit) error {
+	switch {
+	case l == nil:
+		return status.Error(codes.InvalidArgument, "limit cannot be empty")
+	case l.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case l.Limit < 0 || l.Limit > MaxLimit:
+		return status.Errorf(codes.InvalidArgument, "field 'limit' of '%s' must be between 0 and '%d'", l.Name, MaxLimit)
+	case l.Duration <= 0 || l.Duration > MaxDuration:
+		return status.Errorf(codes.InvalidArgument, "field 'duration' of '%s' must be between 1 and '%d'", l.Name, MaxDuration)
+	case l.Burst < 0 || l.Burst > MaxLimit:
+		return status.Errorf(codes.InvalidArgument, "field 'burst' of '%s' must be between 0 and '%d'", l.Name, MaxLimit)
+	case l.RolloutDuration < 0 || l.RolloutDuration > MaxDuration:
+		return status.Errorf(codes.InvalidArgument, "field 'rollout_duration' of '%s' must be between 0 and '%d'", l.Name, MaxDuration)
+	case l.RolloutStartPercent < 0 || l.RolloutStartPercent > 100:
+		return status.Errorf(codes.InvalidArgument, "field 'rollout_start_percent' of '%s' must be between 0 and 100", l.Name)
+	}
+	if _, ok := Algorithm_name[int32(l.Algorithm)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "invalid 'algorithm' '%d' of '%s'", l.Algorithm, l.Name)
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ package gubernator
 import (
 	"crypto/md5"
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 
@@ -45,6 +46,16 @@ type peerInfo struct {
 	peer *PeerClient
 }
 
+// less orders the replicas on the ring by hash. Peers which share a node ID, IE: a peer which was
+// rescheduled with another address while its previous address is still known, have the same
+// replicas, which are ordered by address such that every peer agrees on the owner.
+func (pi peerInfo) less(o peerInfo) bool {
+	if pi.hash != o.hash {
+		return pi.hash < o.hash
+	}
+	return pi.peer.Info().GRPCAddress < o.peer.Info().GRPCAddress
+}
+
 func NewReplicatedConsistentHash(fn HashString64, replicas int) *ReplicatedConsistentHash {
 	ch := &ReplicatedConsistentHash{
 		hashFunc: fn,
@@ -74,20 +85,73 @@ func (ch *ReplicatedConsistentHash) Peers() []*PeerClient {
 	return results
 }
 
-// Adds a peer to the hash
+// Adds a peer to the hash. The number of replicas added for the peer
+// is multiplied by `PeerInfo.Weight`, their position is derived from `PeerInfo.HashKey()`
 func (ch *ReplicatedConsistentHash) Add(peer *PeerClient) {
 	ch.peers[peer.Info().GRPCAddress] = peer
 
-	key := fmt.Sprintf("%x", md5.Sum([]byte(peer.Info().GRPCAddress)))
-	for i := 0; i < ch.replicas; i++ {
-		hash := ch.hashFunc(strconv.Itoa(i) + key)
-		ch.peerKeys = append(ch.peerKeys, peerInfo{
-			hash: hash,
+	weight := peer.Info().Weight
+	if weight < 1 {
+		weight = 1
+	}
+
+	key := fmt.Sprintf("%x", md5.Sum([]byte(peer.Info().HashKey())))
+	keys := make([]peerInfo, ch.replicas*weight)
+	for i := range keys {
+		keys[i] = peerInfo{
+			hash: ch.hashFunc(strconv.Itoa(i) + key),
 			peer: peer,
-		})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+
+	// Merge the replicas of the peer into the ring, which is already sorted
+	merged := make([]peerInfo, 0, len(ch.peerKeys)+len(keys))
+	var i, j int
+	for i < len(ch.peerKeys) && j < len(keys) {
+		if keys[j].less(ch.peerKeys[i]) {
+			merged = append(merged, keys[j])
+			j++
+			continue
+		}
+		merged = append(merged, ch.peerKeys[i])
+		i++
 	}
+	merged = append(merged, ch.peerKeys[i:]...)
+	ch.peerKeys = append(merged, keys[j:]...)
+}
 
-	sort.Slice(ch.peerKeys, func(i, j int) bool { return ch.peerKeys[i].hash < ch.peerKeys[j].hash })
+// Remove removes the peer and its replicas from the hash
+func (ch *ReplicatedConsistentHash) Remove(info PeerInfo) {
+	peer, ok := ch.peers[info.GRPCAddress]
+	if !ok {
+		return
+	}
+	delete(ch.peers, info.GRPCAddress)
+
+	keys := make([]peerInfo, 0, len(ch.peerKeys))
+	for _, k := range ch.peerKeys {
+		if k.peer != peer {
+			keys = append(keys, k)
+		}
+	}
+	ch.peerKeys = keys
+}
+
+// Clone returns a copy of the hash, peers may be added to or removed from the copy without
+// changing the original.
+func (ch *ReplicatedConsistentHash) Clone() PeerPicker {
+	clone := &ReplicatedConsistentHash{
+		hashFunc: ch.hashFunc,
+		peerKeys: make([]peerInfo, len(ch.peerKeys)),
+		peers:    make(map[string]*PeerClient, len(ch.peers)),
+		replicas: ch.replicas,
+	}
+	copy(clone.peerKeys, ch.peerKeys)
+	for k, v := range ch.peers {
+		clone.peers[k] = v
+	}
+	return clone
 }
 
 // Returns number of peers in the picker
@@ -117,3 +181,35 @@ func (ch *ReplicatedConsistentHash) Get(key string) (*PeerClient, error) {
 
 	return ch.peerKeys[idx].peer, nil
 }
+
+// GetSecondary returns the next peer on the ring after the owner of the key, or nil if there is no other peer
+func (ch *ReplicatedConsistentHash) GetSecondary(key string) *PeerClient {
+	if ch.Size() < 2 {
+		return nil
+	}
+	hash := ch.hashFunc(key)
+	idx := sort.Search(len(ch.peerKeys), func(i int) bool { return ch.peerKeys[i].hash >= hash })
+
+	owner := ch.peerKeys[idx%len(ch.peerKeys)].peer
+	for i := 1; i < len(ch.peerKeys); i++ {
+		if p := ch.peerKeys[(idx+i)%len(ch.peerKeys)].peer; p != owner {
+			return p
+		}
+	}
+	return nil
+}
+
+// RingOwnership returns the fraction of the hash ring owned by each peer. A peer owns the hashes
+// between the previous replica on the ring and each of its replicas.
+func (ch *ReplicatedConsistentHash) RingOwnership() map[string]float64 {
+	result := make(map[string]float64, len(ch.peers))
+	for i, pk := range ch.peerKeys {
+		prev := ch.peerKeys[len(ch.peerKeys)-1].hash
+		if i > 0 {
+			prev = ch.peerKeys[i-1].hash
+		}
+		// Wraps around the end of the ring for the first replica
+		result[pk.peer.Info().GRPCAddress] += float64(pk.hash-prev) / math.MaxUint64
+	}
+	return result
+}
@@ -17,12 +17,14 @@ limitations under the License.
 package gubernator
 
 import (
+	"fmt"
 	"net"
 	"testing"
 
 	"github.com/segmentio/fasthash/fnv1"
 	"github.com/segmentio/fasthash/fnv1a"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReplicatedConsistentHash(t *testing.T) {
@@ -100,6 +102,137 @@ func TestReplicatedConsistentHash(t *testing.T) {
 		}
 	})
 
+	t.Run("weighted distribution", func(t *testing.T) {
+		hash := NewReplicatedConsistentHash(nil, defaultReplicas)
+		hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: "a.svc.local", Weight: 1}}})
+		hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: "b.svc.local", Weight: 4}}})
+		assert.Len(t, hash.peerKeys, defaultReplicas*5)
+
+		distribution := make(map[string]int)
+		for i := 0; i < 10000; i++ {
+			peer, _ := hash.Get(net.IPv4(192, 168, byte(i>>8), byte(i)).String())
+			distribution[peer.Info().GRPCAddress]++
+		}
+		// The heavier peer should own roughly 80% of the keys
+		assert.InDelta(t, 8000, distribution["b.svc.local"], 500)
+		assert.InDelta(t, 2000, distribution["a.svc.local"], 500)
+	})
+
+	t.Run("ring ownership", func(t *testing.T) {
+		hash := NewReplicatedConsistentHash(nil, defaultReplicas)
+		assert.Empty(t, hash.RingOwnership())
+		hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: "a.svc.local", Weight: 1}}})
+		assert.InDelta(t, 1.0, hash.RingOwnership()["a.svc.local"], 0.0001)
+
+		hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: "b.svc.local", Weight: 4}}})
+		ownership := hash.RingOwnership()
+		assert.InDelta(t, 1.0, ownership["a.svc.local"]+ownership["b.svc.local"], 0.0001)
+		assert.InDelta(t, 0.8, ownership["b.svc.local"], 0.05)
+	})
+
+	t.Run("secondary", func(t *testing.T) {
+		hash := NewReplicatedConsistentHash(nil, defaultReplicas)
+		hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: hosts[0]}}})
+		assert.Nil(t, hash.GetSecondary("account:1"))
+
+		for _, h := range hosts[1:] {
+			hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: h}}})
+		}
+		for i := 0; i < 100; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			owner, err := hash.Get(key)
+			require.NoError(t, err)
+			secondary := hash.GetSecondary(key)
+			require.NotNil(t, secondary)
+			assert.NotEqual(t, owner, secondary)
+
+			// The secondary owns the key once the owner is removed
+			without := NewReplicatedConsistentHash(nil, defaultReplicas)
+			for _, h := range hosts {
+				if h != owner.Info().GRPCAddress {
+					without.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: h}}})
+				}
+			}
+			next, err := without.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, next.Info().GRPCAddress, secondary.Info().GRPCAddress)
+		}
+	})
+
+	t.Run("remove and clone", func(t *testing.T) {
+		hash := NewReplicatedConsistentHash(nil, defaultReplicas)
+		for _, h := range hosts {
+			hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: h}}})
+		}
+		clone := hash.Clone().(*ReplicatedConsistentHash)
+		clone.Remove(PeerInfo{GRPCAddress: hosts[0]})
+		clone.Remove(PeerInfo{GRPCAddress: "unknown.svc.local"})
+		assert.Equal(t, len(hosts), hash.Size())
+		assert.Equal(t, len(hosts)-1, clone.Size())
+		assert.Nil(t, clone.GetByPeerInfo(PeerInfo{GRPCAddress: hosts[0]}))
+
+		// The ring matches a ring built without the removed peer
+		without := NewReplicatedConsistentHash(nil, defaultReplicas)
+		for _, h := range hosts[1:] {
+			without.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: h}}})
+		}
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			expected, err := without.Get(key)
+			require.NoError(t, err)
+			actual, err := clone.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Info().GRPCAddress, actual.Info().GRPCAddress)
+		}
+
+		// Adding the peer again restores the original ring
+		clone.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{GRPCAddress: hosts[0]}}})
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			expected, err := hash.Get(key)
+			require.NoError(t, err)
+			actual, err := clone.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Info().GRPCAddress, actual.Info().GRPCAddress)
+		}
+	})
+
+	t.Run("node id", func(t *testing.T) {
+		build := func(addresses ...string) *ReplicatedConsistentHash {
+			hash := NewReplicatedConsistentHash(nil, defaultReplicas)
+			for i, addr := range addresses {
+				hash.Add(&PeerClient{conf: PeerConfig{Info: PeerInfo{
+					GRPCAddress: addr,
+					NodeID:      fmt.Sprintf("node-%d", i%len(hosts)),
+				}}})
+			}
+			return hash
+		}
+		before := build(hosts...)
+		// The first peer is rescheduled with another address
+		after := build("10.0.0.7:81", hosts[1], hosts[2])
+		// While both addresses of the rescheduled peer are known, the lowest address owns its keys
+		both := build(append(append([]string{}, hosts...), "10.0.0.7:81")...)
+
+		for i := 0; i < 1000; i++ {
+			key := fmt.Sprintf("account:%d", i)
+			expected, err := before.Get(key)
+			require.NoError(t, err)
+			actual, err := after.Get(key)
+			require.NoError(t, err)
+			if expected.Info().GRPCAddress == hosts[0] {
+				assert.Equal(t, "10.0.0.7:81", actual.Info().GRPCAddress)
+			} else {
+				assert.Equal(t, expected.Info().GRPCAddress, actual.Info().GRPCAddress)
+			}
+			actual, err = both.Get(key)
+			require.NoError(t, err)
+			assert.Equal(t, expected.Info().NodeID, actual.Info().NodeID)
+			if actual.Info().NodeID == "node-0" {
+				assert.Equal(t, "10.0.0.7:81", actual.Info().GRPCAddress)
+			}
+		}
+	})
 }
 
 func BenchmarkReplicatedConsistantHash(b *testing.B) {
@@ -0,0 +1,272 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fairQueueQuantum is the cost, in rate limits, each namespace may admit per round of the queue
+const fairQueueQuantum = 100
+
+// requestLimiter bounds the number of V1 requests executing at once. Once `maxInFlight` requests
+// are executing, up to `maxQueued` additional requests wait for at most `queueTimeout` for one of
+// them to finish. Requests which do not fit in the queue, or wait longer than the timeout, are
+// rejected with codes.ResourceExhausted such that bursty clients fail fast instead of growing the
+// memory of the instance without bound.
+//
+// Queued requests are admitted by deficit round robin across namespaces, where the namespace of a
+// request is the name of its first rate limit and its cost is the number of rate limits it holds.
+// Such that a namespace which sends large batches cannot starve the small requests of others.
+type requestLimiter struct {
+	mutex        sync.Mutex
+	inFlight     int
+	maxInFlight  int
+	queued       atomic.Int64
+	maxQueued    int64
+	queueTimeout time.Duration
+	// The namespaces with queued requests in the order they take turns
+	active []*fairQueue
+	queues map[string]*fairQueue
+	// The index in `active` of the namespace whose turn it is
+	turn int
+	// Is true once the namespace whose turn it is received its quantum for the turn
+	credited bool
+}
+
+// fairQueue holds the queued requests of a namespace
+type fairQueue struct {
+	namespace string
+	waiters   []*queuedRequest
+	deficit   int
+}
+
+type queuedRequest struct {
+	queue   *fairQueue
+	cost    int
+	ready   chan struct{}
+	granted bool
+}
+
+// newRequestLimiter returns nil if `maxInFlight` is 0, which means no limit
+func newRequestLimiter(maxInFlight, maxQueued int, queueTimeout time.Duration) *requestLimiter {
+	if maxInFlight <= 0 {
+		return nil
+	}
+	return &requestLimiter{
+		maxInFlight:  maxInFlight,
+		maxQueued:    int64(maxQueued),
+		queueTimeout: queueTimeout,
+		queues:       make(map[string]*fairQueue),
+	}
+}
+
+// acquire returns codes.ResourceExhausted if the request could not be admitted. Every successful
+// call must be followed by a call to release().
+func (l *requestLimiter) acquire(ctx context.Context, namespace string, cost int) error {
+	l.mutex.Lock()
+	if l.inFlight < l.maxInFlight && len(l.active) == 0 {
+		l.inFlight++
+		l.mutex.Unlock()
+		return nil
+	}
+
+	if l.queued.Load() >= l.maxQueued {
+		l.mutex.Unlock()
+		metricRejectedRequests.WithLabelValues("queue_full").Inc()
+		return status.Error(codes.ResourceExhausted, "too many concurrent requests; the request queue is full")
+	}
+	r := l.enqueue(namespace, cost)
+	l.mutex.Unlock()
+
+	timer := clock.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case <-r.ready:
+		return nil
+	case <-timer.C():
+		if l.cancel(r) {
+			return nil
+		}
+		metricRejectedRequests.WithLabelValues("queue_timeout").Inc()
+		return status.Errorf(codes.ResourceExhausted, "too many concurrent requests; queued for longer than %s", l.queueTimeout)
+	case <-ctx.Done():
+		if l.cancel(r) {
+			return nil
+		}
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (l *requestLimiter) release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.inFlight--
+	for l.inFlight < l.maxInFlight {
+		r := l.next()
+		if r == nil {
+			return
+		}
+		r.granted = true
+		l.inFlight++
+		close(r.ready)
+	}
+}
+
+// enqueue adds a request to the queue of its namespace. The caller must hold the mutex.
+func (l *requestLimiter) enqueue(namespace string, cost int) *queuedRequest {
+	q, ok := l.queues[namespace]
+	if !ok {
+		q = &fairQueue{namespace: namespace}
+		l.queues[namespace] = q
+		l.active = append(l.active, q)
+	}
+	if cost < 1 {
+		cost = 1
+	}
+	r := &queuedRequest{queue: q, cost: cost, ready: make(chan struct{})}
+	q.waiters = append(q.waiters, r)
+	l.queued.Add(1)
+	return r
+}
+
+// cancel removes a request which is no longer waiting from the queue. Returns true if the request
+// was admitted before it could be removed, in which case the caller owns a slot.
+func (l *requestLimiter) cancel(r *queuedRequest) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if r.granted {
+		return true
+	}
+	q := r.queue
+	for i, w := range q.waiters {
+		if w == r {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			break
+		}
+	}
+	l.queued.Add(-1)
+	if len(q.waiters) == 0 {
+		l.remove(q)
+	}
+	return false
+}
+
+// next removes and returns the next request to admit by deficit round robin, or nil if no
+// request is queued. The caller must hold the mutex.
+func (l *requestLimiter) next() *queuedRequest {
+	for len(l.active) != 0 {
+		if l.turn >= len(l.active) {
+			l.turn = 0
+		}
+		q := l.active[l.turn]
+		if !l.credited {
+			q.deficit += fairQueueQuantum
+			l.credited = true
+		}
+		r := q.waiters[0]
+		if r.cost > q.deficit {
+			// The turn passes to the next namespace, the deficit carries over to the next round
+			l.turn++
+			l.credited = false
+			continue
+		}
+		q.deficit -= r.cost
+		q.waiters[0] = nil
+		q.waiters = q.waiters[1:]
+		l.queued.Add(-1)
+		if len(q.waiters) == 0 {
+			l.remove(q)
+		}
+		return r
+	}
+	return nil
+}
+
+// remove removes a namespace which has no queued requests from the rotation. The caller must hold the mutex.
+func (l *requestLimiter) remove(q *fairQueue) {
+	delete(l.queues, q.namespace)
+	for i, a := range l.active {
+		if a != q {
+			continue
+		}
+		l.active = append(l.active[:i], l.active[i+1:]...)
+		switch {
+		case i < l.turn:
+			l.turn--
+		case i == l.turn:
+			l.credited = false
+		}
+		return
+	}
+}
+
+// requestNamespace returns the namespace and cost of a V1 request, see requestLimiter
+func requestNamespace(req interface{}) (string, int) {
+	var reqs []*RateLimitReq
+	switch r := req.(type) {
+	case *GetRateLimitsReq:
+		if len(r.Requests) != 0 && r.Requests[0] != nil && r.Requests[0].Name == "" && r.Defaults != nil {
+			return r.Defaults.Name, len(r.Requests)
+		}
+		reqs = r.Requests
+	case *GetRateLimitGroupReq:
+		reqs = r.Requests
+	case *TransactRateLimitsReq:
+		reqs = r.Requests
+	case *GetRateLimitHierarchyReq:
+		reqs = r.Levels
+	case *ReserveRateLimitReq:
+		reqs = []*RateLimitReq{r.RateLimit}
+	case *RefundReq:
+		return r.Name, 1
+	case *LeaseReq:
+		return r.Name, 1
+	}
+	if len(reqs) == 0 || reqs[0] == nil {
+		return "", 1
+	}
+	return reqs[0].Name, len(reqs)
+}
+
+// unaryInterceptor returns a server interceptor which limits the requests made to the V1 service.
+// HealthCheck and requests made to other services, such as those forwarded by peers which were
+// already admitted by the forwarding peer, are not limited, see serviceBudget for their budget.
+func (l *requestLimiter) unaryInterceptor() grpc.UnaryServerInterceptor {
+	prefix := "/" + V1_ServiceDesc.ServiceName + "/"
+	healthCheck := prefix + "HealthCheck"
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) || info.FullMethod == healthCheck {
+			return handler(ctx, req)
+		}
+		namespace, cost := requestNamespace(req)
+		if err := l.acquire(ctx, namespace, cost); err != nil {
+			return nil, err
+		}
+		defer l.release()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRequestLimiter(t *testing.T) {
+	assert.Nil(t, newRequestLimiter(0, 10, time.Second))
+
+	l := newRequestLimiter(1, 1, 50*time.Millisecond)
+	intercept := l.unaryInterceptor()
+	getRateLimits := &grpc.UnaryServerInfo{FullMethod: "/pb.gubernator.V1/GetRateLimits"}
+
+	// call invokes the interceptor in the background, the handler blocks until `finish` is closed
+	call := func(info *grpc.UnaryServerInfo, finish chan struct{}) (started chan struct{}, result chan error) {
+		started, result = make(chan struct{}), make(chan error, 1)
+		go func() {
+			_, err := intercept(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+				close(started)
+				<-finish
+				return nil, nil
+			})
+			result <- err
+		}()
+		return started, result
+	}
+
+	finish := make(chan struct{})
+	started, inFlight := call(getRateLimits, finish)
+	<-started
+
+	// The second request waits in the queue until the first finishes
+	queuedStarted, queued := call(getRateLimits, finish)
+	require.Eventually(t, func() bool { return l.queued.Load() == 1 }, time.Second, time.Millisecond)
+
+	// The third request does not fit in the queue
+	_, full := call(getRateLimits, finish)
+	err := <-full
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), "queue is full")
+
+	// HealthCheck and peer requests are never limited
+	for _, method := range []string{"/pb.gubernator.V1/HealthCheck", "/pb.gubernator.PeersV1/GetPeerRateLimits"} {
+		exempt := make(chan struct{})
+		close(exempt)
+		_, result := call(&grpc.UnaryServerInfo{FullMethod: method}, exempt)
+		assert.NoError(t, <-result, method)
+	}
+
+	close(finish)
+	<-queuedStarted
+	assert.NoError(t, <-inFlight)
+	assert.NoError(t, <-queued)
+
+	// A queued request is rejected once it waits longer than the queue timeout
+	block := make(chan struct{})
+	started, inFlight = call(getRateLimits, block)
+	<-started
+	_, timeout := call(getRateLimits, block)
+	err = <-timeout
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, err.Error(), "queued for longer than 50ms")
+	close(block)
+	assert.NoError(t, <-inFlight)
+	assert.Equal(t, int64(0), l.queued.Load())
+}
+
+func TestRequestLimiterFairness(t *testing.T) {
+	l := newRequestLimiter(1, 100, time.Minute)
+	require.NoError(t, l.acquire(context.Background(), "", 1))
+
+	var (
+		mutex sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	queue := func(namespace string, cost int) {
+		queued := l.queued.Load()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, l.acquire(context.Background(), namespace, cost))
+			mutex.Lock()
+			order = append(order, namespace)
+			mutex.Unlock()
+			l.release()
+		}()
+		// Wait for the request to be queued, such that the requests queue in order
+		require.Eventually(t, func() bool { return l.queued.Load() == queued+1 }, time.Second, time.Millisecond)
+	}
+
+	// A namespace which sends large batches queues first
+	for i := 0; i < 5; i++ {
+		queue("batch", maxBatchSize)
+	}
+	queue("small_a", 1)
+	queue("small_b", 1)
+	queue("small_a", 1)
+
+	l.release()
+	wg.Wait()
+
+	// The small requests are admitted while the large batches wait for enough deficit, a namespace
+	// admits as many requests as its quantum allows per turn
+	assert.Equal(t, []string{"small_a", "small_a", "small_b", "batch", "batch", "batch", "batch", "batch"}, order)
+	assert.Equal(t, int64(0), l.queued.Load())
+	assert.Empty(t, l.active)
+	assert.Empty(t, l.queues)
+}
+
+func TestRequestNamespace(t *testing.T) {
+	for _, tc := range []struct {
+		req       interface{}
+		namespace string
+		cost      int
+	}{
+		{req: &GetRateLimitsReq{Requests: []*RateLimitReq{{Name: "a"}, {Name: "b"}}}, namespace: "a", cost: 2},
+		{req: &GetRateLimitsReq{}, namespace: "", cost: 1},
+		{req: &ReserveRateLimitReq{RateLimit: &RateLimitReq{Name: "c"}}, namespace: "c", cost: 1},
+		{req: &LeaseReq{Name: "d"}, namespace: "d", cost: 1},
+		{req: &HealthCheckReq{}, namespace: "", cost: 1},
+	} {
+		namespace, cost := requestNamespace(tc.req)
+		assert.Equal(t, tc.namespace, namespace)
+		assert.Equal(t, tc.cost, cost)
+	}
+}
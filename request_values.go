@@ -0,0 +1,207 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	grpcpeer "google.golang.org/grpc/peer"
+)
+
+// requestValueMetadataPrefix prefixes the request values in RateLimitReq.Metadata of the rate
+// limits forwarded to the owning peer
+const requestValueMetadataPrefix = "gubernator-value-"
+
+// requestValueSignatureMetadata is the signature of the request values in RateLimitReq.Metadata,
+// see RequestValueSigningConfig
+const requestValueSignatureMetadata = "gubernator-values-signature"
+
+// RequestValueClientCN is the request value set to the common name of the verified TLS client
+// certificate of the client which called GetRateLimits, unless a GRPC interceptor already set it.
+const RequestValueClientCN = "client_cn"
+
+// RequestValueSigningConfig signs the request values sent along with the rate limits forwarded to
+// the owning peer with HMAC-SHA256, such that the owner only trusts the values, IE: the identity of
+// the client, asserted by a peer which holds the key. The owner rejects forwarded rate limits whose
+// values are not signed with an accepted key.
+type RequestValueSigningConfig struct {
+	// (Required) The key the request values are signed with
+	Key string
+
+	// (Optional) Previous keys whose signatures are still accepted from other peers. Keys are
+	// rotated like PeerAuthConfig.AcceptTokens
+	AcceptKeys []string
+}
+
+func (c *RequestValueSigningConfig) validate() error {
+	if c.Key == "" {
+		return errors.New("RequestValueSigning.Key cannot be empty")
+	}
+	for _, k := range c.AcceptKeys {
+		if k == "" {
+			return errors.New("RequestValueSigning.AcceptKeys cannot contain an empty key")
+		}
+	}
+	return nil
+}
+
+// signRequestValues returns the signature of the request values of the rate limit `r` with `key`. The signature
+// covers the name and unique key, such that the values cannot be replayed with another rate limit.
+func signRequestValues(key string, r *RateLimitReq, values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	mac := hmac.New(sha256.New, []byte(key))
+	for _, v := range []string{r.Name, r.UniqueKey} {
+		mac.Write([]byte(v))
+		mac.Write([]byte{0})
+	}
+	for _, k := range keys {
+		mac.Write([]byte(k))
+		mac.Write([]byte{0})
+		mac.Write([]byte(values[k]))
+		mac.Write([]byte{0})
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verify returns an error unless the request values of `r` are signed with an accepted key
+func (c *RequestValueSigningConfig) verify(r *RateLimitReq, values map[string]string) error {
+	signature, err := hex.DecodeString(r.Metadata[requestValueSignatureMetadata])
+	if err != nil || len(signature) == 0 {
+		return errors.New("request values are not signed; see RequestValueSigningConfig")
+	}
+	for _, key := range append([]string{c.Key}, c.AcceptKeys...) {
+		expected, _ := hex.DecodeString(signRequestValues(key, r, values))
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return errors.New("request values signature is invalid; see RequestValueSigningConfig")
+}
+
+// withClientCN returns a copy of ctx with RequestValueClientCN set to the common name of the
+// verified client certificate of the caller, if any.
+func withClientCN(ctx context.Context) context.Context {
+	if _, ok := RequestValue(ctx, RequestValueClientCN); ok {
+		return ctx
+	}
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	if cn := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+		return WithRequestValue(ctx, RequestValueClientCN, cn)
+	}
+	return ctx
+}
+
+type requestValuesKey struct{}
+
+// WithRequestValue returns a copy of ctx which holds the value, IE: the tenant id or a claim of the
+// authenticated client set by a GRPC interceptor. Unlike context.WithValue(), request values are
+// sent along with the rate limits which are forwarded to the owning peer, such that BehaviorMiddleware,
+// Store and other extension points may read them with RequestValue() on every peer. Values are not
+// sent with the hits of GLOBAL rate limits, which are aggregated before they are sent to the owner.
+func WithRequestValue(ctx context.Context, key, value string) context.Context {
+	values := RequestValues(ctx)
+	next := make(map[string]string, len(values)+1)
+	for k, v := range values {
+		next[k] = v
+	}
+	next[key] = value
+	return context.WithValue(ctx, requestValuesKey{}, next)
+}
+
+// RequestValue returns the value set with WithRequestValue, if any.
+func RequestValue(ctx context.Context, key string) (string, bool) {
+	v, ok := RequestValues(ctx)[key]
+	return v, ok
+}
+
+// RequestValues returns every value set with WithRequestValue. The map must not be modified.
+func RequestValues(ctx context.Context) map[string]string {
+	values, _ := ctx.Value(requestValuesKey{}).(map[string]string)
+	return values
+}
+
+// injectRequestValues adds the request values in ctx to the metadata of a rate limit which
+// is forwarded to the owning peer, signed if `signing` is not nil
+func injectRequestValues(ctx context.Context, r *RateLimitReq, signing *RequestValueSigningConfig) {
+	values := RequestValues(ctx)
+	if len(values) == 0 {
+		return
+	}
+	if r.Metadata == nil {
+		r.Metadata = make(map[string]string, len(values))
+	}
+	for k, v := range values {
+		r.Metadata[requestValueMetadataPrefix+k] = v
+	}
+	if signing != nil {
+		r.Metadata[requestValueSignatureMetadata] = signRequestValues(signing.Key, r, values)
+	}
+}
+
+// extractRequestValues returns a copy of ctx with the request values injected by the peer
+// which forwarded the rate limit. If `signing` is not nil, returns an error unless the values
+// are signed with an accepted key.
+func extractRequestValues(ctx context.Context, r *RateLimitReq, signing *RequestValueSigningConfig) (context.Context, error) {
+	values := make(map[string]string)
+	for k, v := range r.Metadata {
+		if key, ok := strings.CutPrefix(k, requestValueMetadataPrefix); ok {
+			values[key] = v
+		}
+	}
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	if signing != nil {
+		if err := signing.verify(r, values); err != nil {
+			return ctx, err
+		}
+	}
+	for k, v := range values {
+		ctx = WithRequestValue(ctx, k, v)
+	}
+	return ctx, nil
+}
+
+// stripRequestValues removes request values from the metadata of a rate limit sent by a client,
+// such that clients cannot pose as another tenant or identity on the owning peer
+func stripRequestValues(r *RateLimitReq) {
+	for k := range r.Metadata {
+		if strings.HasPrefix(k, requestValueMetadataPrefix) {
+			delete(r.Metadata, k)
+		}
+	}
+	delete(r.Metadata, requestValueSignatureMetadata)
+}
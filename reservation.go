@@ -0,0 +1,283 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// How often each worker cancels the reservations which have expired
+const reservationSweepInterval = clock.Second
+
+// reservation holds hits applied to a rate limit until they are committed or returned to the
+// rate limit. Reservations are held in memory by the worker which owns the rate limit, as such
+// they are lost if the owning peer restarts or ownership of the rate limit moves to another peer.
+type reservation struct {
+	key      string
+	req      *RateLimitReq
+	expireAt int64
+	// The CreatedAt of the token bucket when the hits were reserved. Hits are not
+	// returned to a token bucket which has since been reset.
+	createdAt int64
+	// If true the hits were applied by a REFUNDABLE request, they are kept
+	// when the reservation expires instead of being returned to the rate limit.
+	refundable bool
+}
+
+// ReserveRateLimit applies `hits` to the rate limit and holds them in a reservation on the
+// owning peer until the reservation is committed, canceled or expires.
+func (s *V1Instance) ReserveRateLimit(ctx context.Context, r *ReserveRateLimitReq) (*ReserveRateLimitResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReserveRateLimit")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	if err := validateReserve(r); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+	s.normalize(r.RateLimit)
+	if err := limitKeyLength(&s.conf, r.RateLimit); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	key := s.conf.HashKey(r.RateLimit)
+	peer, err := s.GetPeer(ctx, key)
+	if err != nil {
+		countError(err, "Error in GetPeer")
+		return nil, status.Errorf(codes.Unavailable, "while looking up peer that owns rate limit '%s': %s", key, err)
+	}
+	if peer.Info().IsOwner {
+		return s.reserveLocal(ctx, r)
+	}
+	return peer.ReservePeerRateLimit(ctx, r)
+}
+
+// CommitReservation commits the hits held by a reservation.
+func (s *V1Instance) CommitReservation(ctx context.Context, r *ReservationReq) (*ReservationResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.CommitReservation")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	return s.releaseReservation(ctx, r, true)
+}
+
+// CancelReservation returns the hits held by a reservation to the rate limit.
+func (s *V1Instance) CancelReservation(ctx context.Context, r *ReservationReq) (*ReservationResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.CancelReservation")).ObserveDuration()
+	ctx, cancelTimeout := s.withDefaultTimeout(ctx)
+	defer cancelTimeout()
+	return s.releaseReservation(ctx, r, false)
+}
+
+// ReservePeerRateLimit is called by other peers to reserve hits against a rate limit owned by this peer.
+func (s *V1Instance) ReservePeerRateLimit(ctx context.Context, r *ReserveRateLimitReq) (*ReserveRateLimitResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReservePeerRateLimit")).ObserveDuration()
+	if err := validateReserve(r); err != nil {
+		return nil, err
+	}
+	return s.reserveLocal(ctx, r)
+}
+
+// CommitPeerReservation is called by other peers to commit a reservation held by this peer.
+func (s *V1Instance) CommitPeerReservation(ctx context.Context, r *ReservationReq) (*ReservationResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.CommitPeerReservation")).ObserveDuration()
+	if err := validateReservation(r); err != nil {
+		return nil, err
+	}
+	return s.releaseLocal(ctx, r, true)
+}
+
+// CancelPeerReservation is called by other peers to cancel a reservation held by this peer.
+func (s *V1Instance) CancelPeerReservation(ctx context.Context, r *ReservationReq) (*ReservationResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.CancelPeerReservation")).ObserveDuration()
+	if err := validateReservation(r); err != nil {
+		return nil, err
+	}
+	return s.releaseLocal(ctx, r, false)
+}
+
+func (s *V1Instance) reserveLocal(ctx context.Context, r *ReserveRateLimitReq) (*ReserveRateLimitResp, error) {
+	now := epochMillis(clock.Now())
+	if r.RateLimit.CreatedAt == nil || *r.RateLimit.CreatedAt == 0 {
+		r.RateLimit.CreatedAt = &now
+	}
+
+	expireAt := now + r.Ttl
+	rl, id, err := s.workerPool.Reserve(ctx, r.RateLimit, expireAt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "during workerPool.Reserve: %s", err)
+	}
+	resp := &ReserveRateLimitResp{RateLimit: rl}
+	if id != "" {
+		resp.ReservationId = id
+		resp.ExpireAt = expireAt
+	}
+	return resp, nil
+}
+
+func (s *V1Instance) releaseReservation(ctx context.Context, r *ReservationReq, commit bool) (*ReservationResp, error) {
+	s.normalizeKey(&r.Name, &r.UniqueKey)
+	if err := validateReservation(r); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+
+	key := s.conf.HashKey(&RateLimitReq{Name: r.Name, UniqueKey: r.UniqueKey})
+	peer, err := s.GetPeer(ctx, key)
+	if err != nil {
+		countError(err, "Error in GetPeer")
+		return nil, status.Errorf(codes.Unavailable, "while looking up peer that owns rate limit '%s': %s", key, err)
+	}
+	if peer.Info().IsOwner {
+		return s.releaseLocal(ctx, r, commit)
+	}
+	if commit {
+		return peer.CommitPeerReservation(ctx, r)
+	}
+	return peer.CancelPeerReservation(ctx, r)
+}
+
+func (s *V1Instance) releaseLocal(ctx context.Context, r *ReservationReq, commit bool) (*ReservationResp, error) {
+	key := s.conf.HashKey(&RateLimitReq{Name: r.Name, UniqueKey: r.UniqueKey})
+	hits, ok, err := s.workerPool.Release(ctx, key, r.ReservationId, commit)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound,
+			"reservation '%s' not found; it may have expired or already been released", r.ReservationId)
+	}
+	return &ReservationResp{Hits: hits}, nil
+}
+
+func validateReserve(r *ReserveRateLimitReq) error {
+	switch {
+	case r.RateLimit == nil:
+		return status.Error(codes.InvalidArgument, "field 'rate_limit' cannot be empty")
+	case r.RateLimit.UniqueKey == "":
+		return status.Error(codes.InvalidArgument, "field 'unique_key' cannot be empty")
+	case r.RateLimit.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'namespace' cannot be empty")
+	case r.RateLimit.Hits <= 0:
+		return status.Error(codes.InvalidArgument, "field 'hits' must be greater than zero")
+	case r.Ttl <= 0:
+		return status.Error(codes.InvalidArgument, "field 'ttl' must be greater than zero")
+	case HasBehavior(r.RateLimit.Behavior, Behavior_GLOBAL):
+		return status.Error(codes.InvalidArgument, "GLOBAL behavior is not supported by reservations")
+	}
+	return nil
+}
+
+func validateReservation(r *ReservationReq) error {
+	switch {
+	case r.UniqueKey == "":
+		return status.Error(codes.InvalidArgument, "field 'unique_key' cannot be empty")
+	case r.Name == "":
+		return status.Error(codes.InvalidArgument, "field 'name' cannot be empty")
+	case r.ReservationId == "":
+		return status.Error(codes.InvalidArgument, "field 'reservation_id' cannot be empty")
+	}
+	return nil
+}
+
+// addReservation holds the hits just applied by `r` in a new reservation and returns its id.
+func (worker *Worker) addReservation(r *RateLimitReq, expireAt int64, cache Cache) string {
+	id := worker.holdHits(r, expireAt, false, cache)
+	metricReservationCounter.WithLabelValues("reserved").Inc()
+	return id
+}
+
+// holdHits holds the hits just applied by `r` until `expireAt` and returns the id of the hold.
+func (worker *Worker) holdHits(r *RateLimitReq, expireAt int64, refundable bool, cache Cache) string {
+	res := &reservation{
+		key:        worker.conf.HashKey(r),
+		req:        r,
+		expireAt:   expireAt,
+		refundable: refundable,
+	}
+	if item, ok := cache.GetItem(res.key); ok {
+		if t, ok := item.Value.(*TokenBucketItem); ok {
+			res.createdAt = t.CreatedAt
+		}
+	}
+
+	id := generateID()
+	for worker.reservations[id] != nil {
+		id = generateID()
+	}
+	worker.reservations[id] = res
+	return id
+}
+
+func (worker *Worker) expireReservations() {
+	now := epochMillis(clock.Now())
+	for id, res := range worker.reservations {
+		if res.expireAt > now {
+			continue
+		}
+		delete(worker.reservations, id)
+		if res.refundable {
+			metricRefundCounter.WithLabelValues("expired").Inc()
+			continue
+		}
+		worker.refund(context.Background(), res)
+		worker.publish(res.key)
+		metricReservationCounter.WithLabelValues("expired").Inc()
+	}
+}
+
+// refund returns the hits held by `res` to the rate limit in the cache which holds it
+func (worker *Worker) refund(ctx context.Context, res *reservation) {
+	res.refund(ctx, worker.storeFor(res.req.Name), worker.cacheFor(res.req.Name))
+}
+
+// refund returns the reserved hits to the rate limit, up to the capacity of the rate limit.
+func (res *reservation) refund(ctx context.Context, s Store, c Cache) {
+	item, ok := c.GetItem(res.key)
+	if !ok {
+		return
+	}
+
+	switch b := item.Value.(type) {
+	case *TokenBucketItem:
+		if b.CreatedAt != res.createdAt {
+			return
+		}
+		b.Remaining += res.req.Hits
+		if capacity := tokenCapacity(res.req); b.Remaining > capacity {
+			b.Remaining = capacity
+		}
+		if b.Remaining > 0 {
+			b.Status = Status_UNDER_LIMIT
+		}
+	case *LeakyBucketItem:
+		b.Remaining += float64(res.req.Hits)
+		if b.Remaining > float64(b.Burst) {
+			b.Remaining = float64(b.Burst)
+		}
+	default:
+		return
+	}
+
+	if s != nil {
+		s.OnChange(ctx, res.req, item)
+	}
+}
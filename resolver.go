@@ -0,0 +1,228 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+)
+
+// ResolverScheme is the scheme of the targets resolved by NewResolverBuilder()
+const ResolverScheme = "gubernator"
+
+// The load balancing config published to clients by the resolver, such that requests are
+// balanced across every resolved peer instead of the first peer which connects.
+var resolverLoadBalancingConfig = []interface{}{map[string]interface{}{"round_robin": map[string]interface{}{}}}
+
+type ResolverConfig struct {
+	// (Optional) The TLS config used to call HealthCheck on the addresses in the target, should
+	// match the TLS config used to dial the cluster. Defaults to nil (no TLS)
+	TLS *tls.Config
+
+	// (Optional) How often the peers in the cluster are resolved again. Defaults to 30 seconds
+	RefreshInterval time.Duration
+
+	// (Optional) Only resolve the peers in this data center, such that clients send requests to
+	// peers nearby. If no peer is in this data center every peer is resolved. Defaults to "" (every peer)
+	DataCenter string
+
+	// (Optional) The logger used to report failures to resolve the peers
+	Logger FieldLogger
+
+	// (Optional) The GRPC service config published to clients alongside the `round_robin` load balancing
+	// config, such that calls are retried and time out as recommended. Defaults to DefaultServiceConfig
+	ServiceConfig string
+}
+
+// NewResolverBuilder returns a GRPC resolver builder for `gubernator:///` targets which resolves
+// every peer in the cluster by calling HealthCheck with `include_endpoints` on the comma separated
+// addresses in the target. Clients which dial a `gubernator:///` target balance requests across every
+// peer with the `round_robin` policy instead of sending every request to a single address, IE: a
+// load balancer in front of the cluster.
+//
+//	conn, err := grpc.Dial("gubernator:///gubernator.example.com:1051",
+//		grpc.WithResolvers(gubernator.NewResolverBuilder(gubernator.ResolverConfig{})),
+//		grpc.WithTransportCredentials(insecure.NewCredentials()))
+//
+// The data center and weight of each peer are available to custom balancers via AddressEndpoint()
+func NewResolverBuilder(conf ResolverConfig) resolver.Builder {
+	setter.SetDefault(&conf.RefreshInterval, 30*time.Second)
+	setter.SetDefault(&conf.Logger, logrus.WithField("category", "gubernator"))
+	setter.SetDefault(&conf.ServiceConfig, DefaultServiceConfig)
+	return &endpointBuilder{conf: conf}
+}
+
+type endpointBuilder struct {
+	conf ResolverConfig
+}
+
+var _ resolver.Builder = (*endpointBuilder)(nil)
+
+func (b *endpointBuilder) Scheme() string {
+	return ResolverScheme
+}
+
+func (b *endpointBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	if target.Endpoint() == "" {
+		return nil, errors.New("target must contain the address of at least one peer")
+	}
+
+	serviceConfig, err := withServiceConfig(b.conf.ServiceConfig, "loadBalancingConfig", resolverLoadBalancingConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ResolverConfig.ServiceConfig")
+	}
+
+	// The addresses in the target are only used to discover the peers in the cluster
+	creds := insecure.NewCredentials()
+	if b.conf.TLS != nil {
+		creds = credentials.NewTLS(b.conf.TLS)
+	}
+	conn, err := grpc.Dial("static:///"+target.Endpoint(),
+		grpc.WithResolvers(NewStaticBuilder()),
+		grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %s", target.Endpoint())
+	}
+
+	r := &endpointResolver{
+		conf:          b.conf,
+		serviceConfig: serviceConfig,
+		cc:            cc,
+		conn:          conn,
+		client:        NewV1Client(conn),
+		now:           make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+type endpointResolver struct {
+	conf          ResolverConfig
+	serviceConfig string
+	cc            resolver.ClientConn
+	conn          *grpc.ClientConn
+	client        V1Client
+	now           chan struct{}
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+var _ resolver.Resolver = (*endpointResolver)(nil)
+
+func (r *endpointResolver) run() {
+	defer r.wg.Done()
+	tick := clock.NewTicker(r.conf.RefreshInterval)
+	defer tick.Stop()
+
+	for {
+		if err := r.resolve(); err != nil {
+			r.conf.Logger.WithError(err).Warn("while resolving gubernator peers")
+			r.cc.ReportError(err)
+		}
+		select {
+		case <-tick.C():
+		case <-r.now:
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *endpointResolver) resolve() error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.conf.RefreshInterval)
+	defer cancel()
+
+	health, err := r.client.HealthCheck(ctx, &HealthCheckReq{IncludeEndpoints: true})
+	if err != nil {
+		return errors.Wrap(err, "during HealthCheck")
+	}
+
+	endpoints := health.Endpoints
+	if r.conf.DataCenter != "" {
+		var local []*Endpoint
+		for _, ep := range endpoints {
+			if ep.DataCenter == r.conf.DataCenter {
+				local = append(local, ep)
+			}
+		}
+		if len(local) != 0 {
+			endpoints = local
+		}
+	}
+	if len(endpoints) == 0 {
+		return errors.New("HealthCheck returned no endpoints")
+	}
+
+	state := resolver.State{
+		ServiceConfig: r.cc.ParseServiceConfig(r.serviceConfig),
+	}
+	for _, ep := range endpoints {
+		state.Addresses = append(state.Addresses, resolver.Address{
+			Addr: ep.GrpcAddress,
+			BalancerAttributes: attributes.New(endpointKey{}, endpointAttrs{
+				dataCenter: ep.DataCenter,
+				weight:     ep.Weight,
+			}),
+		})
+	}
+	return r.cc.UpdateState(state)
+}
+
+func (r *endpointResolver) ResolveNow(_ resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+func (r *endpointResolver) Close() {
+	close(r.done)
+	r.wg.Wait()
+	_ = r.conn.Close()
+}
+
+type endpointKey struct{}
+
+type endpointAttrs struct {
+	dataCenter string
+	weight     int32
+}
+
+// AddressEndpoint returns the data center and weight of the peer an address resolved by
+// NewResolverBuilder() belongs to, such that custom balancers may prefer peers by locality
+// and weight. Returns a weight of 0 if the address was not resolved by NewResolverBuilder().
+func AddressEndpoint(addr resolver.Address) (dataCenter string, weight int32) {
+	attrs, ok := addr.BalancerAttributes.Value(endpointKey{}).(endpointAttrs)
+	if !ok {
+		return "", 0
+	}
+	return attrs.dataCenter, attrs.weight
+}
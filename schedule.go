@@ -0,0 +1,172 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LimitSchedule replaces the limit of a RateLimitTemplate while the time a request was created
+// matches the schedule, IE: 1000 per minute during business hours but 200 per minute overnight.
+type LimitSchedule struct {
+	// (Required) A cron-like expression of minute, hour, day of month, month and day of week, IE:
+	// "* 0-7,22-23 * * *" matches every minute from 22:00 until 07:59. Each field is '*', a number,
+	// a range 'a-b' or a comma separated list of those, optionally followed by a step '/n'. The
+	// expression is evaluated in UTC unless prefixed with a time zone, IE: "TZ=America/New_York * 9-16 * * 1-5"
+	Schedule string
+
+	// (Required) The number of hits allowed per duration of the template while the schedule matches
+	Limit int64
+
+	cron *cronSchedule
+}
+
+func (s *LimitSchedule) validate(name string) error {
+	if s.Limit < 0 {
+		return errors.Errorf("Templates.Schedules.Limit of '%s' cannot be negative", name)
+	}
+	var err error
+	if s.cron, err = parseCronSchedule(s.Schedule); err != nil {
+		return errors.Wrapf(err, "Templates.Schedules.Schedule of '%s'", name)
+	}
+	return nil
+}
+
+// ParseLimitSchedule parses a schedule in the format used by `GUBER_RATE_LIMIT_SCHEDULES`, the name of
+// a template followed by the limit and the schedule during which the limit applies, IE:
+// "per-user-login: 200 during * 0-7,22-23 * * *". Returns the name of the template and the schedule.
+func ParseLimitSchedule(s string) (string, LimitSchedule, error) {
+	name, def, ok := strings.Cut(strings.TrimSpace(s), ":")
+	fields := strings.Fields(def)
+	if !ok || len(fields) < 3 || fields[1] != "during" {
+		return "", LimitSchedule{}, errors.Errorf("'%s' is invalid; expected '<name>: <limit> during <schedule>'", s)
+	}
+	name = strings.TrimSpace(name)
+
+	limit, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return name, LimitSchedule{}, errors.Wrapf(err, "invalid limit '%s' in schedule '%s'", fields[0], s)
+	}
+	ls := LimitSchedule{Schedule: strings.Join(fields[2:], " "), Limit: limit}
+	return name, ls, ls.validate(name)
+}
+
+// cronSchedule is a parsed cron-like expression, each field is a bitmask of the matching values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Standard cron matches either the day of month or the day of week if both are restricted
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	c := &cronSchedule{location: time.UTC}
+	fields := strings.Fields(expr)
+	if len(fields) > 0 {
+		if tz, ok := strings.CutPrefix(fields[0], "TZ="); ok {
+			var err error
+			if c.location, err = time.LoadLocation(tz); err != nil {
+				return nil, errors.Wrapf(err, "invalid time zone '%s'", tz)
+			}
+			fields = fields[1:]
+		}
+	}
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("'%s' is invalid; expected 5 fields '<minute> <hour> <day of month> <month> <day of week>'", expr)
+	}
+
+	masks := []*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, f := range cronFields {
+		var err error
+		if *masks[i], err = parseCronField(fields[i], f.min, f.max); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s '%s'", f.name, fields[i])
+		}
+	}
+	// Both 0 and 7 are Sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domStar, c.dowStar = fields[2] == "*", fields[4] == "*"
+	return c, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, errors.Errorf("invalid step '%s'", stepStr)
+			}
+		}
+
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, errors.Errorf("invalid value '%s'", loStr)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, errors.Errorf("invalid value '%s'", hiStr)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, errors.Errorf("'%s' is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (c *cronSchedule) matches(t time.Time) bool {
+	t = t.In(c.location)
+	if c.minute&(1<<uint(t.Minute())) == 0 || c.hour&(1<<uint(t.Hour())) == 0 ||
+		c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if !c.domStar && !c.dowStar {
+		return dom || dow
+	}
+	return dom && dow
+}
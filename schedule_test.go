@@ -0,0 +1,69 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronSchedule(t *testing.T) {
+	// 2024-01-01 is a Monday
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+
+	for _, test := range []struct {
+		schedule string
+		time     string
+		matches  bool
+	}{
+		{"* * * * *", "2024-01-01T12:34:00Z", true},
+		{"* 0-7,22-23 * * *", "2024-01-01T23:59:00Z", true},
+		{"* 0-7,22-23 * * *", "2024-01-01T07:59:00Z", true},
+		{"* 0-7,22-23 * * *", "2024-01-01T08:00:00Z", false},
+		{"*/15 * * * *", "2024-01-01T12:45:00Z", true},
+		{"*/15 * * * *", "2024-01-01T12:46:00Z", false},
+		{"5/20 * * * *", "2024-01-01T12:25:00Z", true},
+		{"* 9-16 * * 1-5", "2024-01-01T09:00:00Z", true},
+		{"* 9-16 * * 1-5", "2024-01-06T09:00:00Z", false},
+		{"* * * * 7", "2024-01-07T09:00:00Z", true},
+		{"* * * * 0", "2024-01-07T09:00:00Z", true},
+		{"* * * 12 *", "2024-01-01T09:00:00Z", false},
+		// Either the day of month or the day of week matches when both are restricted
+		{"* * 15 * 1", "2024-01-01T09:00:00Z", true},
+		{"* * 15 * 1", "2024-01-15T09:00:00Z", true},
+		{"* * 15 * 1", "2024-01-16T09:00:00Z", false},
+		{"TZ=America/New_York * 9-16 * * *", "2024-01-01T14:00:00Z", true},
+		{"TZ=America/New_York * 9-16 * * *", "2024-01-01T09:00:00Z", false},
+	} {
+		c, err := parseCronSchedule(test.schedule)
+		require.NoError(t, err, test.schedule)
+		assert.Equal(t, test.matches, c.matches(at(test.time)), "'%s' at %s", test.schedule, test.time)
+	}
+
+	for _, v := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *",
+		"* * * * 8", "5-1 * * * *", "*/0 * * * *", "a * * * *", "TZ=Nowhere/Special * * * * *"} {
+		_, err := parseCronSchedule(v)
+		assert.Error(t, err, v)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+const (
+	// metadataNodeID is the ID of the instance which forwarded a rate limit, such that an instance
+	// which is in its own peer list under an address it does not know as its own detects it.
+	metadataNodeID = "node_id"
+	// metadataSelfForward is set to "true" in the metadata of a RateLimitResp to a rate limit
+	// which the instance forwarded to itself
+	metadataSelfForward = "self_forward"
+)
+
+// canonicalAddress returns the GRPC address `addr` in a canonical form, such that addresses which
+// differ only in formatting compare equal, IE: "LOCALHOST:081", "[::ffff:127.0.0.1]:81" and
+// "127.0.0.1:81". Returns `addr` unchanged if it is not a host and port.
+func canonicalAddress(addr string) string {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(addr))
+	if err != nil {
+		return addr
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err == nil {
+		port = strconv.FormatUint(p, 10)
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" {
+		host = "127.0.0.1"
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		host = ip.Unmap().String()
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// markSelf flags the peers in `peerInfo` whose address is an address of this instance in another
+// form as owned by this instance, and remembers the addresses of the peers which are flagged as
+// owned. Must be called with peerUpdateMutex held.
+func (s *V1Instance) markSelf(peerInfo []PeerInfo) []PeerInfo {
+	for _, info := range peerInfo {
+		if info.IsOwner {
+			s.selfAddresses[canonicalAddress(info.GRPCAddress)] = true
+		}
+	}
+	var marked []PeerInfo
+	for i, info := range peerInfo {
+		if info.IsOwner || !s.selfAddresses[canonicalAddress(info.GRPCAddress)] {
+			continue
+		}
+		if marked == nil {
+			marked = make([]PeerInfo, len(peerInfo))
+			copy(marked, peerInfo)
+		}
+		s.log.WithField("address", info.GRPCAddress).
+			Warn("peer list contains this instance under another address; treating the peer as this instance")
+		marked[i].IsOwner = true
+	}
+	if marked == nil {
+		return peerInfo
+	}
+	return marked
+}
+
+// selfForwarded is called when the peer `peer` responded to a forwarded rate limit with
+// metadataSelfForward, IE: the peer is this instance under an address it does not know as its own.
+// Rate limits which hash to the peer are evaluated locally from then on.
+func (s *V1Instance) selfForwarded(peer *PeerClient) {
+	if !peer.markSelf() {
+		return
+	}
+	s.log.WithField("address", peer.Info().GRPCAddress).
+		Warn("forwarded a rate limit to this instance under another address; treating the peer as this instance")
+	s.peerUpdateMutex.Lock()
+	s.selfAddresses[canonicalAddress(peer.Info().GRPCAddress)] = true
+	s.peerUpdateMutex.Unlock()
+}
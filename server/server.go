@@ -0,0 +1,41 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package server runs Gubernator, either as a daemon or as an instance registered with another GRPC
+// server. Server binaries import this package while clients import the client and proto packages,
+// which do not depend on etcd, the GRPC server, logrus or the metrics of the server.
+//
+// The implementation remains in the root package, which this package re-exports until it moves here.
+package server
+
+import (
+	guber "github.com/gubernator-io/gubernator/v2"
+)
+
+type (
+	Config         = guber.Config
+	BehaviorConfig = guber.BehaviorConfig
+	DaemonConfig   = guber.DaemonConfig
+	Daemon         = guber.Daemon
+	V1Instance     = guber.V1Instance
+	PeerInfo       = guber.PeerInfo
+)
+
+var (
+	NewV1Instance     = guber.NewV1Instance
+	SpawnDaemon       = guber.SpawnDaemon
+	SetupDaemonConfig = guber.SetupDaemonConfig
+)
@@ -0,0 +1,104 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceBudget bounds the requests made to a single GRPC service by the number of requests
+// executing at once and the number of requests per second. The V1 and PeersV1 services each have
+// their own budget, such that a storm of requests forwarded by peers during an incident cannot
+// starve the clients of the instance, and clients cannot starve the peers. Unlike requestLimiter,
+// requests over the budget are rejected immediately with codes.ResourceExhausted.
+type serviceBudget struct {
+	// The name of the service, IE: "V1" or "PeersV1"
+	service     string
+	prefix      string
+	healthCheck string
+	maxInFlight int64
+	inFlight    atomic.Int64
+	// Is nil unless the requests per second are limited
+	rate *rate.Limiter
+}
+
+// newServiceBudget returns nil if both `maxInFlight` and `perSecond` are 0, which means no limit
+func newServiceBudget(desc grpc.ServiceDesc, maxInFlight, perSecond int) *serviceBudget {
+	if maxInFlight <= 0 && perSecond <= 0 {
+		return nil
+	}
+	name := desc.ServiceName[strings.LastIndex(desc.ServiceName, ".")+1:]
+	b := &serviceBudget{
+		service:     name,
+		prefix:      "/" + desc.ServiceName + "/",
+		healthCheck: "/" + desc.ServiceName + "/HealthCheck",
+		maxInFlight: int64(maxInFlight),
+	}
+	if perSecond > 0 {
+		// Allow a burst of one second worth of requests
+		b.rate = rate.NewLimiter(rate.Limit(perSecond), perSecond)
+	}
+	return b
+}
+
+// acquire returns codes.ResourceExhausted if the request is over the budget. Every successful
+// call must be followed by a call to release().
+func (b *serviceBudget) acquire() error {
+	if b.maxInFlight > 0 {
+		if b.inFlight.Add(1) > b.maxInFlight {
+			b.inFlight.Add(-1)
+			metricBudgetRejected.WithLabelValues(b.service, "in_flight").Inc()
+			return status.Errorf(codes.ResourceExhausted, "too many concurrent %s requests", b.service)
+		}
+		metricBudgetInFlight.WithLabelValues(b.service).Inc()
+	}
+	if b.rate != nil && !b.rate.Allow() {
+		b.release()
+		metricBudgetRejected.WithLabelValues(b.service, "rate").Inc()
+		return status.Errorf(codes.ResourceExhausted, "too many %s requests per second", b.service)
+	}
+	return nil
+}
+
+func (b *serviceBudget) release() {
+	if b.maxInFlight > 0 {
+		b.inFlight.Add(-1)
+		metricBudgetInFlight.WithLabelValues(b.service).Dec()
+	}
+}
+
+// unaryInterceptor returns a server interceptor which limits the requests made to the service of
+// the budget. HealthCheck and requests made to other services are not limited.
+func (b *serviceBudget) unaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, b.prefix) || info.FullMethod == b.healthCheck {
+			return handler(ctx, req)
+		}
+		if err := b.acquire(); err != nil {
+			return nil, err
+		}
+		defer b.release()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServiceBudget(t *testing.T) {
+	assert.Nil(t, newServiceBudget(PeersV1_ServiceDesc, 0, 0))
+
+	handler := func(context.Context, interface{}) (interface{}, error) { return nil, nil }
+	call := func(intercept grpc.UnaryServerInterceptor, method string) error {
+		_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	t.Run("in flight", func(t *testing.T) {
+		b := newServiceBudget(PeersV1_ServiceDesc, 1, 0)
+		intercept := b.unaryInterceptor()
+		require.NoError(t, b.acquire())
+
+		// The budget of the peers does not limit the clients, and the reverse
+		err := call(intercept, "/pb.gubernator.PeersV1/GetPeerRateLimits")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, err.Error(), "too many concurrent PeersV1 requests")
+		assert.NoError(t, call(intercept, "/pb.gubernator.V1/GetRateLimits"))
+
+		b.release()
+		assert.NoError(t, call(intercept, "/pb.gubernator.PeersV1/GetPeerRateLimits"))
+		assert.Equal(t, int64(0), b.inFlight.Load())
+	})
+
+	t.Run("per second", func(t *testing.T) {
+		b := newServiceBudget(V1_ServiceDesc, 0, 2)
+		intercept := b.unaryInterceptor()
+		assert.NoError(t, call(intercept, "/pb.gubernator.V1/GetRateLimits"))
+		assert.NoError(t, call(intercept, "/pb.gubernator.V1/GetRateLimits"))
+		err := call(intercept, "/pb.gubernator.V1/GetRateLimits")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, err.Error(), "too many V1 requests per second")
+
+		// HealthCheck is never limited
+		assert.NoError(t, call(intercept, "/pb.gubernator.V1/HealthCheck"))
+	})
+}
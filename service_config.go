@@ -0,0 +1,102 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultServiceConfig is the GRPC service config recommended for clients of Gubernator. Calls to
+// the V1 service time out after 1 second and calls to the AdminV1 service after 30 seconds. Calls
+// which fail with UNAVAILABLE, IE: the instance is not ready or the connection was lost, are retried
+// with backoff. Hits retried after a lost connection may be applied twice unless the requests set
+// an idempotency key. See https://github.com/grpc/grpc/blob/master/doc/service_config.md
+const DefaultServiceConfig = `{
+  "methodConfig": [
+    {
+      "name": [{"service": "pb.gubernator.V1"}],
+      "timeout": "1s",
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.05s",
+        "maxBackoff": "0.5s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    },
+    {
+      "name": [{"service": "pb.gubernator.AdminV1"}],
+      "timeout": "30s",
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.5s",
+        "maxBackoff": "5s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["UNAVAILABLE"]
+      }
+    }
+  ]
+}`
+
+// validateServiceConfig returns an error if `config` is not a JSON object
+func validateServiceConfig(config string) error {
+	var v map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &v); err != nil {
+		return errors.Wrap(err, "service config must be a JSON object")
+	}
+	return nil
+}
+
+// withServiceConfig returns `config` with the `key` set to `value`, such that the resolver
+// may publish the method config alongside its load balancing config.
+func withServiceConfig(config, key string, value interface{}) (string, error) {
+	var v map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &v); err != nil {
+		return "", errors.Wrap(err, "service config must be a JSON object")
+	}
+	v[key] = value
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// newServiceConfigHandler serves the service config to clients which cannot use the resolver.
+// With `?format=dns` the config is served as the value of the `_grpc_config.<hostname>` TXT record
+// read by the DNS resolver of the standard GRPC clients.
+func newServiceConfigHandler(config string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(config)); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Query().Get("format") == "dns" {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`grpc_config=[{"serviceConfig":` + buf.String() + `}]`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// ShadowConfig mirrors a percentage of the GetRateLimits requests to a secondary cluster, IE: a
+// cluster running a new version of gubernator during a migration, such that its decisions are
+// compared with the decisions of this cluster before any traffic depends on it. The responses of
+// the secondary cluster are discarded, only the comparison is reported.
+type ShadowConfig struct {
+	// (Required) The GRPC address of the secondary cluster, IE: a load balancer in front of its
+	// instances. Shadowing is disabled if empty.
+	Address string
+
+	// (Optional) The percentage of GetRateLimits requests mirrored to the secondary cluster.
+	// Defaults to 100
+	Percent int
+
+	// (Optional) The TLS config used to connect to the secondary cluster. Defaults to no TLS
+	TLS *tls.Config
+
+	// (Optional) The max time to wait for the secondary cluster to respond. Defaults to 500ms
+	Timeout time.Duration
+
+	// (Optional) The max number of requests mirrored concurrently, requests sampled while this many
+	// are waiting for the secondary cluster are not mirrored. Defaults to 100
+	MaxInFlight int
+}
+
+// The metadata key set on mirrored requests, such that a secondary cluster which
+// also shadows its requests does not mirror the requests it receives from us.
+const shadowMetadataKey = "gubernator-shadow"
+
+func (c *ShadowConfig) validate() error {
+	if c.Address == "" {
+		return nil
+	}
+	if c.Percent < 0 || c.Percent > 100 {
+		return errors.New("Shadow.Percent must be between 1 and 100")
+	}
+	if c.Timeout < 0 {
+		return errors.New("Shadow.Timeout cannot be negative")
+	}
+	if c.MaxInFlight < 0 {
+		return errors.New("Shadow.MaxInFlight cannot be negative")
+	}
+	setter.SetDefault(&c.Percent, 100)
+	setter.SetDefault(&c.Timeout, 500*time.Millisecond)
+	setter.SetDefault(&c.MaxInFlight, 100)
+	return nil
+}
+
+type shadow struct {
+	conf     ShadowConfig
+	conn     *grpc.ClientConn
+	client   V1Client
+	inFlight chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newShadow connects to the secondary cluster in a non-blocking fashion
+func newShadow(conf ShadowConfig) (*shadow, error) {
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if conf.TLS != nil {
+		creds = credentials.NewTLS(conf.TLS)
+	}
+	conn, err := grpc.Dial(conf.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "while dialing secondary cluster '%s'", conf.Address)
+	}
+	return &shadow{
+		conf:     conf,
+		conn:     conn,
+		client:   NewV1Client(conn),
+		inFlight: make(chan struct{}, conf.MaxInFlight),
+	}, nil
+}
+
+// sample returns a copy of the request to mirror, or nil if the request is not mirrored. The
+// copy is taken before the request is evaluated, which modifies the request.
+func (s *shadow) sample(ctx context.Context, r *GetRateLimitsReq) *GetRateLimitsReq {
+	if s == nil || len(r.Requests) == 0 || !chance(s.conf.Percent) {
+		return nil
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(shadowMetadataKey)) != 0 {
+		return nil
+	}
+	return proto.Clone(r).(*GetRateLimitsReq)
+}
+
+// mirror sends the copy of the request to the secondary cluster in the background and compares
+// its decisions with `resps`, the responses of this cluster.
+func (s *shadow) mirror(r *GetRateLimitsReq, resps []*RateLimitResp) {
+	if r == nil {
+		return
+	}
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		metricShadowCounter.WithLabelValues("dropped").Add(float64(len(r.Requests)))
+		return
+	}
+
+	// Only the decisions are compared, the responses may be modified once we return
+	decisions := make([]shadowDecision, len(resps))
+	for i, rl := range resps {
+		decisions[i] = newShadowDecision(rl)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			<-s.inFlight
+			s.wg.Done()
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), s.conf.Timeout)
+		defer cancel()
+		ctx = metadata.AppendToOutgoingContext(ctx, shadowMetadataKey, "true")
+
+		resp, err := s.client.GetRateLimits(ctx, r)
+		if err != nil || len(resp.Responses) != len(decisions) {
+			metricShadowCounter.WithLabelValues("error").Add(float64(len(decisions)))
+			return
+		}
+		for i, rl := range resp.Responses {
+			if newShadowDecision(rl) == decisions[i] {
+				metricShadowCounter.WithLabelValues("match").Inc()
+			} else {
+				metricShadowCounter.WithLabelValues("mismatch").Inc()
+			}
+		}
+	}()
+}
+
+// shadowDecision is the part of a response compared between the clusters. The remaining hits
+// and reset time are not compared as they change between the two requests.
+type shadowDecision struct {
+	status Status
+	failed bool
+}
+
+func newShadowDecision(rl *RateLimitResp) shadowDecision {
+	if rl == nil {
+		return shadowDecision{failed: true}
+	}
+	return shadowDecision{status: rl.Status, failed: rl.Error != ""}
+}
+
+// close waits for the mirrored requests to complete and disconnects from the secondary cluster
+func (s *shadow) close() {
+	if s != nil {
+		s.wg.Wait()
+		_ = s.conn.Close()
+	}
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// SimulationRequest is a request of a trace replayed by Simulate()
+type SimulationRequest struct {
+	// The unique key of the rate limit the request is counted against
+	Key string
+	// When the request was made
+	Time time.Time
+	// The hits of the request. Defaults to 1
+	Hits int64
+}
+
+// SimulationConfig configures Simulate()
+type SimulationConfig struct {
+	// (Required) The rate limit the requests of each key are evaluated against, IE: the algorithm,
+	// limit, duration and burst under consideration. The UniqueKey, Hits and CreatedAt are set from
+	// each request of the trace. DURATION_IS_GREGORIAN is not supported, as gregorian intervals
+	// are counted by the wall clock instead of the time of the request.
+	RateLimit *RateLimitReq
+
+	// (Optional) The grace and reset jitter which apply to the rate limit, see BehaviorConfig
+	Behaviors BehaviorConfig
+
+	// (Optional) The number of keys with the most denied requests in SimulationResult.TopKeys.
+	// Defaults to 10
+	TopKeys int
+}
+
+// SimulationResult is the outcome of replaying a trace with Simulate()
+type SimulationResult struct {
+	// The number of requests and hits in the trace
+	Requests int64
+	Hits     int64
+	// The number of requests and hits which would have been OVER_LIMIT
+	Denied     int64
+	DeniedHits int64
+	// The number of distinct keys in the trace, and of those with at least one denied request
+	Keys       int
+	DeniedKeys int
+	// The keys with the most denied requests, most denied first
+	TopKeys []SimulationKey
+}
+
+// SimulationKey is the outcome of the requests of a single key replayed by Simulate()
+type SimulationKey struct {
+	Key      string
+	Requests int64
+	Denied   int64
+}
+
+// Simulate replays the requests of `trace` against the rate limit of `conf` offline, in the order
+// the requests were made, and reports how many requests would have been denied. Use it to choose
+// the limits of a rate limit with the historical requests of the keys it will limit.
+func Simulate(conf SimulationConfig, trace []SimulationRequest) (*SimulationResult, error) {
+	if conf.RateLimit == nil {
+		return nil, errors.New("field 'RateLimit' is required")
+	}
+	if HasBehavior(conf.RateLimit.Behavior, Behavior_DURATION_IS_GREGORIAN) {
+		return nil, errors.New("DURATION_IS_GREGORIAN rate limits cannot be simulated")
+	}
+	if err := validateBounds(conf.RateLimit); err != nil {
+		return nil, err
+	}
+	if conf.TopKeys == 0 {
+		conf.TopKeys = 10
+	}
+
+	algorithmConf := &Config{
+		Behaviors:             conf.Behaviors,
+		HashKey:               LegacyHashKey,
+		AlgorithmChangePolicy: AlgorithmChangeReset,
+	}
+	evaluate := tokenBucket
+	switch conf.RateLimit.Algorithm {
+	case Algorithm_TOKEN_BUCKET:
+	case Algorithm_LEAKY_BUCKET:
+		evaluate = leakyBucket
+	default:
+		return nil, fmt.Errorf("invalid rate limit algorithm '%d'", conf.RateLimit.Algorithm)
+	}
+
+	sorted := make([]SimulationRequest, len(trace))
+	copy(sorted, trace)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	ctx := context.Background()
+	cache := newSimulationCache()
+	result := &SimulationResult{}
+	keys := make(map[string]*SimulationKey)
+	for _, sr := range sorted {
+		r := proto.Clone(conf.RateLimit).(*RateLimitReq)
+		r.UniqueKey = sr.Key
+		r.Hits = sr.Hits
+		if r.Hits == 0 {
+			r.Hits = 1
+		}
+		createdAt := sr.Time.UnixMilli()
+		r.CreatedAt = &createdAt
+		cache.now = createdAt
+
+		rl, err := evaluate(ctx, nil, cache, algorithmConf, r, RateLimitReqState{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "while simulating the request of key '%s' at '%s'", sr.Key, sr.Time)
+		}
+
+		k, ok := keys[sr.Key]
+		if !ok {
+			k = &SimulationKey{Key: sr.Key}
+			keys[sr.Key] = k
+		}
+		k.Requests++
+		result.Requests++
+		result.Hits += r.Hits
+		if rl.Status == Status_OVER_LIMIT {
+			if k.Denied == 0 {
+				result.DeniedKeys++
+			}
+			k.Denied++
+			result.Denied++
+			result.DeniedHits += r.Hits
+		}
+	}
+
+	result.Keys = len(keys)
+	for _, k := range keys {
+		if k.Denied != 0 {
+			result.TopKeys = append(result.TopKeys, *k)
+		}
+	}
+	sort.Slice(result.TopKeys, func(i, j int) bool {
+		if result.TopKeys[i].Denied != result.TopKeys[j].Denied {
+			return result.TopKeys[i].Denied > result.TopKeys[j].Denied
+		}
+		return result.TopKeys[i].Key < result.TopKeys[j].Key
+	})
+	if len(result.TopKeys) > conf.TopKeys {
+		result.TopKeys = result.TopKeys[:conf.TopKeys]
+	}
+	return result, nil
+}
+
+// ReadSimulationTrace reads a trace for Simulate() from CSV lines of `<time>,<key>[,<hits>]`, where
+// the time is either RFC 3339 or epoch milliseconds, IE: "2024-01-02T15:04:05Z,account:1234,1".
+// Empty lines and lines beginning with '#' are skipped.
+func ReadSimulationTrace(reader io.Reader) ([]SimulationRequest, error) {
+	var trace []SimulationRequest
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected '<time>,<key>[,<hits>]'", line)
+		}
+
+		var sr SimulationRequest
+		ts := strings.TrimSpace(fields[0])
+		if ms, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			sr.Time = time.UnixMilli(ms)
+		} else if sr.Time, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+			return nil, fmt.Errorf("line %d: time '%s' is neither RFC 3339 nor epoch milliseconds", line, ts)
+		}
+		sr.Key = strings.TrimSpace(fields[1])
+		if sr.Key == "" {
+			return nil, fmt.Errorf("line %d: key cannot be empty", line)
+		}
+		if len(fields) == 3 {
+			hits, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: hits '%s' is not an integer", line, fields[2])
+			}
+			sr.Hits = hits
+		}
+		trace = append(trace, sr)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "while reading trace")
+	}
+	return trace, nil
+}
+
+// simulationCache is the Cache of Simulate(), whose items expire by the time of the request being
+// simulated instead of the clock of the instance.
+type simulationCache struct {
+	items map[string]*CacheItem
+	// The time of the request being simulated in epoch milliseconds
+	now int64
+}
+
+var _ Cache = &simulationCache{}
+
+func newSimulationCache() *simulationCache {
+	return &simulationCache{items: make(map[string]*CacheItem)}
+}
+
+func (c *simulationCache) Add(item *CacheItem) bool {
+	_, exists := c.items[item.Key]
+	c.items[item.Key] = item
+	return exists
+}
+
+func (c *simulationCache) GetItem(key string) (*CacheItem, bool) {
+	item, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	if item.ExpireAt < c.now {
+		delete(c.items, key)
+		return nil, false
+	}
+	return item, true
+}
+
+func (c *simulationCache) UpdateExpiration(key string, expireAt int64) bool {
+	item, ok := c.items[key]
+	if ok {
+		item.ExpireAt = expireAt
+	}
+	return ok
+}
+
+func (c *simulationCache) Each() chan *CacheItem {
+	out := make(chan *CacheItem)
+	go func() {
+		for _, item := range c.items {
+			out <- item
+		}
+		close(out)
+	}()
+	return out
+}
+
+func (c *simulationCache) Remove(key string) { delete(c.items, key) }
+func (c *simulationCache) Size() int64       { return int64(len(c.items)) }
+func (c *simulationCache) Close() error      { return nil }
@@ -0,0 +1,85 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	// Historical requests are replayed by their own time, not the clock of the instance
+	begin := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	var trace []guber.SimulationRequest
+	for i := 0; i < 10; i++ {
+		trace = append(trace, guber.SimulationRequest{Key: "account:1", Time: begin.Add(time.Duration(i) * time.Second)})
+	}
+	// The window of account:1 resets after a minute
+	trace = append(trace,
+		guber.SimulationRequest{Key: "account:1", Time: begin.Add(61 * time.Second)},
+		guber.SimulationRequest{Key: "account:2", Time: begin, Hits: 3},
+	)
+
+	t.Run("Token bucket", func(t *testing.T) {
+		result, err := guber.Simulate(guber.SimulationConfig{
+			RateLimit: &guber.RateLimitReq{Algorithm: guber.Algorithm_TOKEN_BUCKET, Limit: 5, Duration: guber.Minute},
+		}, trace)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), result.Requests)
+		assert.Equal(t, int64(14), result.Hits)
+		assert.Equal(t, int64(5), result.Denied)
+		assert.Equal(t, 2, result.Keys)
+		assert.Equal(t, 1, result.DeniedKeys)
+		assert.Equal(t, []guber.SimulationKey{{Key: "account:1", Requests: 11, Denied: 5}}, result.TopKeys)
+	})
+
+	t.Run("Leaky bucket", func(t *testing.T) {
+		// Leaks a hit every 6 seconds, as such a burst of 5 denies 4 of the requests a second apart
+		result, err := guber.Simulate(guber.SimulationConfig{
+			RateLimit: &guber.RateLimitReq{Algorithm: guber.Algorithm_LEAKY_BUCKET, Limit: 10, Burst: 5, Duration: guber.Minute},
+		}, trace)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.Denied)
+	})
+
+	t.Run("Gregorian", func(t *testing.T) {
+		_, err := guber.Simulate(guber.SimulationConfig{
+			RateLimit: &guber.RateLimitReq{Limit: 5, Duration: guber.GregorianDays, Behavior: guber.Behavior_DURATION_IS_GREGORIAN},
+		}, trace)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadSimulationTrace(t *testing.T) {
+	trace, err := guber.ReadSimulationTrace(strings.NewReader(`# time,key,hits
+2020-01-01T12:00:00Z,account:1
+1577880001000, account:2 ,3
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []guber.SimulationRequest{
+		{Key: "account:1", Time: time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{Key: "account:2", Time: time.UnixMilli(1577880001000), Hits: 3},
+	}, trace)
+
+	_, err = guber.ReadSimulationTrace(strings.NewReader("yesterday,account:1\n"))
+	assert.EqualError(t, err, "line 1: time 'yesterday' is neither RFC 3339 nor epoch milliseconds")
+}
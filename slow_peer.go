@@ -0,0 +1,82 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MetadataSlowOwner is set to the address of the owning peer in the metadata of a RateLimitResp which
+// was evaluated by the next peer on the hash ring because the p99 latency of the owner exceeded
+// BehaviorConfig.SlowPeerThreshold. It is also set in the metadata of the rate limit forwarded to the
+// next peer, such that the next peer evaluates the rate limit although it does not own it.
+const MetadataSlowOwner = "slow_owner"
+
+// getSlowPeerRateLimit evaluates a rate limit on the next peer on the hash ring if the p99 latency
+// of the owning peer exceeds `BehaviorConfig.SlowPeerThreshold`, else returns nil.
+//
+// The next peer evaluates the rate limit from its own state, which does not include the hits applied
+// by the owner. As every peer picks the same next peer, the next peer enforces the limit for every
+// peer which considers the owner slow, until the latency of the owner recovers.
+func (s *V1Instance) getSlowPeerRateLimit(ctx context.Context, c *RateLimitCheck) *RateLimitResp {
+	threshold := s.conf.Behaviors.SlowPeerThreshold
+	if threshold <= 0 || c.Peer.Info().IsOwner || HasBehavior(c.Req.Behavior, Behavior_FORCE_PEER) ||
+		c.Peer.stats.recentP99() <= threshold {
+		return nil
+	}
+	secondary := s.getSecondaryPeer(c.Key)
+	if secondary == nil {
+		return nil
+	}
+
+	owner := c.Peer.Info().GRPCAddress
+	r := proto.Clone(c.Req).(*RateLimitReq)
+	var rl *RateLimitResp
+	if secondary.Info().IsOwner {
+		var err error
+		rl, err = s.getLocalRateLimit(ctx, r, RateLimitReqState{IsOwner: false})
+		if err != nil {
+			s.log.WithContext(ctx).WithError(err).WithField("key", redactKey(s.conf.KeyRedaction, c.Key)).
+				Error("while evaluating rate limit of a slow peer")
+			return nil
+		}
+	} else {
+		if r.Metadata == nil {
+			r.Metadata = make(map[string]string)
+		}
+		r.Metadata[MetadataSlowOwner] = owner
+		rl = s.forwardRequest(ctx, &RateLimitCheck{Req: r, Key: c.Key, Peer: secondary})
+	}
+
+	if rl.Metadata == nil {
+		rl.Metadata = make(map[string]string)
+	}
+	rl.Metadata[MetadataSlowOwner] = owner
+	metricSlowPeerCounter.WithLabelValues(owner).Inc()
+	return rl
+}
+
+// getSecondaryPeer returns the peer which owns the key if the owner was removed, or nil if
+// there is no other peer or the picker cannot pick one.
+func (s *V1Instance) getSecondaryPeer(key string) *PeerClient {
+	if p, ok := s.pickers.Load().local.(SecondaryPicker); ok {
+		return p.GetSecondary(key)
+	}
+	return nil
+}
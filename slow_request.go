@@ -0,0 +1,136 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/sirupsen/logrus"
+)
+
+// The phases of a GetRateLimits call timed by requestTimings
+const (
+	// Looking up the peer which owns each rate limit
+	phasePick = iota
+	// Waiting for the rate limits forwarded to their owning peer
+	phaseForward
+	// Evaluating the rate limits owned by this instance
+	phaseAlgorithm
+)
+
+var phaseNames = [...]string{"pick", "forward", "algorithm"}
+
+type requestTimingsKey struct{}
+
+// requestTimings sums the time a GetRateLimits call spends in each phase and collects the peers it
+// forwarded to, such that a call slower than `BehaviorConfig.SlowRequestThreshold` is logged with
+// the phase which took longest. Rate limits are evaluated concurrently, as such the sum of the
+// phases may exceed the duration of the call.
+type requestTimings struct {
+	mutex  sync.Mutex
+	phases [len(phaseNames)]time.Duration
+	peers  map[string]bool
+}
+
+// withRequestTimings returns a context which carries new timings if slow requests are logged
+func (s *V1Instance) withRequestTimings(ctx context.Context) (context.Context, *requestTimings) {
+	if s.conf.Behaviors.SlowRequestThreshold <= 0 {
+		return ctx, nil
+	}
+	t := &requestTimings{peers: make(map[string]bool)}
+	return context.WithValue(ctx, requestTimingsKey{}, t), t
+}
+
+// recordPhase adds `elapsed` to the phase of the call timed by `ctx`, if any
+func recordPhase(ctx context.Context, phase int, elapsed time.Duration) {
+	t, _ := ctx.Value(requestTimingsKey{}).(*requestTimings)
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	t.phases[phase] += elapsed
+	t.mutex.Unlock()
+}
+
+// recordForward adds `elapsed` to the forward phase of the call timed by `ctx`, if any, and
+// records the peer the rate limit was forwarded to
+func recordForward(ctx context.Context, peer string, elapsed time.Duration) {
+	t, _ := ctx.Value(requestTimingsKey{}).(*requestTimings)
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	t.phases[phaseForward] += elapsed
+	t.peers[peer] = true
+	t.mutex.Unlock()
+}
+
+// observeSlowRequest logs and counts the call if it took longer than the threshold
+func (s *V1Instance) observeSlowRequest(ctx context.Context, t *requestTimings, start time.Time, batchSize int) {
+	if t == nil {
+		return
+	}
+	elapsed := clock.Since(start)
+	if elapsed < s.conf.Behaviors.SlowRequestThreshold {
+		return
+	}
+	metricSlowRequestCounter.WithLabelValues("GetRateLimits").Inc()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	fields := logrus.Fields{
+		"duration":   elapsed.String(),
+		"batch_size": batchSize,
+	}
+	slowest := 0
+	for phase, d := range t.phases {
+		fields[phaseNames[phase]] = d.String()
+		if d > t.phases[slowest] {
+			slowest = phase
+		}
+	}
+	fields["slowest_phase"] = phaseNames[slowest]
+	peers := make([]string, 0, len(t.peers))
+	for peer := range t.peers {
+		peers = append(peers, peer)
+	}
+	sort.Strings(peers)
+	fields["peers"] = peers
+	s.log.WithContext(ctx).WithFields(fields).Warn("slow GetRateLimits request")
+}
+
+// observeSlowRPC logs and counts a GetPeerRateLimits RPC which took longer than the threshold
+func (c *PeerClient) observeSlowRPC(ctx context.Context, start time.Time, batchSize int) {
+	threshold := c.conf.Behavior.SlowRequestThreshold
+	if threshold <= 0 {
+		return
+	}
+	elapsed := clock.Since(start)
+	if elapsed < threshold {
+		return
+	}
+	metricSlowRequestCounter.WithLabelValues("GetPeerRateLimits").Inc()
+	c.conf.Log.WithContext(ctx).WithFields(logrus.Fields{
+		"duration":   elapsed.String(),
+		"batch_size": batchSize,
+		"peer":       c.conf.Info.GRPCAddress,
+	}).Warn("slow GetPeerRateLimits RPC")
+}
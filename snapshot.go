@@ -0,0 +1,243 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Identifies a snapshot file and the version of the encoding. Items in a snapshot are length
+// prefixed and encoded by MarshalCacheItem.
+var snapshotMagic = []byte("GUBSNAP\x02")
+
+// Identifies a snapshot saved by versions of gubernator prior to CacheItemState, which is still loaded
+var legacySnapshotMagic = []byte("GUBSNAP\x01")
+
+// The bucket types which begin an item encoded by versions of gubernator prior to CacheItemState
+const (
+	snapshotTokenBucket = 1
+	snapshotLeakyBucket = 2
+)
+
+// SnapshotLoader is a Loader which saves the cache to a binary snapshot file and loads the snapshot
+// by memory mapping the file, such that an instance which owns millions of rate limits is warm within
+// milliseconds of starting. Combine with `Config.SnapshotInterval` to save snapshots periodically
+// such that a crashed instance loses at most one interval of hits.
+//
+// Rate limits which are not in the snapshot are fetched from the `Config.Store` (if any) on the first
+// hit, as such a Store continues to act as the source of truth for rate limits missing from the snapshot.
+// Only TOKEN_BUCKET and LEAKY_BUCKET items are saved. A snapshot which is corrupt is ignored.
+type SnapshotLoader struct {
+	path string
+	log  FieldLogger
+}
+
+var _ Loader = &SnapshotLoader{}
+
+// NewSnapshotLoader returns a SnapshotLoader which saves to and loads from `path`
+func NewSnapshotLoader(path string, log FieldLogger) *SnapshotLoader {
+	setter.SetDefault(&log, logrus.WithField("category", "gubernator"))
+	return &SnapshotLoader{path: path, log: log}
+}
+
+// Load returns the unexpired items in the snapshot. Returns a closed channel if no snapshot exists.
+func (l *SnapshotLoader) Load() (chan *CacheItem, error) {
+	out := make(chan *CacheItem, 500)
+	data, unmap, err := mmapFile(l.path)
+	if err != nil {
+		close(out)
+		if os.IsNotExist(errors.Cause(err)) {
+			return out, nil
+		}
+		return nil, errors.Wrapf(err, "while opening snapshot '%s'", l.path)
+	}
+
+	if err := verifySnapshot(data); err != nil {
+		close(out)
+		_ = unmap()
+		l.log.WithError(err).WithField("file", l.path).Warn("ignoring corrupt snapshot")
+		return out, nil
+	}
+
+	go func() {
+		defer close(out)
+		defer func() { _ = unmap() }()
+		start := clock.Now()
+		now := MillisecondNow()
+		var count int
+
+		b := data[len(snapshotMagic) : len(data)-crc32.Size]
+		for len(b) > 0 {
+			size := int(binary.LittleEndian.Uint32(b))
+			item, err := UnmarshalCacheItem(b[4 : 4+size])
+			b = b[4+size:]
+			if err != nil || item.ExpireAt <= now {
+				continue
+			}
+			out <- item
+			count++
+		}
+		l.log.WithField("items", count).
+			WithField("duration", clock.Since(start).String()).
+			Info("loaded cache snapshot")
+	}()
+	return out, nil
+}
+
+// Save writes every item read from `in` to a new snapshot which replaces the previous snapshot
+// once complete, such that a crash while saving never leaves a partial snapshot behind.
+func (l *SnapshotLoader) Save(in chan *CacheItem) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*.tmp")
+	if err != nil {
+		// Drain the channel such that the workers are not blocked
+		for range in {
+		}
+		return errors.Wrap(err, "while creating snapshot")
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	w := bufio.NewWriterSize(f, 1<<20)
+	crc := crc32.NewIEEE()
+	body := io.MultiWriter(w, crc)
+	if _, err = w.Write(snapshotMagic); err != nil {
+		return errors.Wrap(err, "while writing snapshot")
+	}
+
+	var buf []byte
+	for item := range in {
+		if err != nil {
+			continue
+		}
+		// Items which are not a TokenBucketItem or LeakyBucketItem are not saved
+		b, encErr := appendCacheItem(append(buf[:0], 0, 0, 0, 0), item)
+		if encErr != nil {
+			continue
+		}
+		binary.LittleEndian.PutUint32(b, uint32(len(b)-4))
+		_, err = body.Write(b)
+		buf = b
+	}
+	if err != nil {
+		return errors.Wrap(err, "while writing snapshot")
+	}
+
+	if err = binary.Write(w, binary.LittleEndian, crc.Sum32()); err != nil {
+		return errors.Wrap(err, "while writing snapshot")
+	}
+	if err = w.Flush(); err != nil {
+		return errors.Wrap(err, "while writing snapshot")
+	}
+	if err = f.Sync(); err != nil {
+		return errors.Wrap(err, "while syncing snapshot")
+	}
+	if err = f.Close(); err != nil {
+		return errors.Wrap(err, "while closing snapshot")
+	}
+	return errors.Wrap(os.Rename(f.Name(), l.path), "while replacing snapshot")
+}
+
+func verifySnapshot(data []byte) error {
+	if len(data) < len(snapshotMagic)+crc32.Size {
+		return errors.New("not a snapshot or unsupported version")
+	}
+	if magic := string(data[:len(snapshotMagic)]); magic != string(snapshotMagic) && magic != string(legacySnapshotMagic) {
+		return errors.New("not a snapshot or unsupported version")
+	}
+	body := data[len(snapshotMagic) : len(data)-crc32.Size]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(data)-crc32.Size:]) {
+		return errors.New("checksum mismatch")
+	}
+	for b := body; len(b) > 0; {
+		if len(b) < 4 || int(binary.LittleEndian.Uint32(b)) > len(b)-4 {
+			return errors.New("truncated item")
+		}
+		b = b[4+binary.LittleEndian.Uint32(b):]
+	}
+	return nil
+}
+
+// decodeLegacyItem decodes an item encoded by versions of gubernator prior to CacheItemState,
+// returns nil if the item is invalid
+func decodeLegacyItem(b []byte) (item *CacheItem) {
+	r := snapshotReader{b: b}
+	item = &CacheItem{}
+	switch r.byte() {
+	case snapshotTokenBucket:
+		t := &TokenBucketItem{Status: Status(r.uint32())}
+		for _, n := range []*int64{&t.Limit, &t.Duration, &t.Remaining, &t.CreatedAt, &t.UpdatedAt, &t.GraceUsed} {
+			*n = r.int64()
+		}
+		item.Value = t
+	case snapshotLeakyBucket:
+		l := &LeakyBucketItem{}
+		for _, n := range []*int64{&l.Limit, &l.Duration, &l.UpdatedAt, &l.Burst} {
+			*n = r.int64()
+		}
+		l.Remaining = math.Float64frombits(uint64(r.int64()))
+		item.Value = l
+	default:
+		return nil
+	}
+	item.Algorithm = Algorithm(r.byte())
+	item.ExpireAt = r.int64()
+	item.InvalidAt = r.int64()
+	item.Key = r.string()
+	item.Name = r.string()
+	if r.err != nil {
+		return nil
+	}
+	return item
+}
+
+// snapshotReader reads fields from an encoded item, recording an error instead of panicking
+// if the item is shorter than expected.
+type snapshotReader struct {
+	b   []byte
+	err error
+}
+
+func (r *snapshotReader) next(n int) []byte {
+	if r.err != nil || len(r.b) < n {
+		r.err = fmt.Errorf("item truncated")
+		return make([]byte, n)
+	}
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+func (r *snapshotReader) byte() byte     { return r.next(1)[0] }
+func (r *snapshotReader) uint32() uint32 { return binary.LittleEndian.Uint32(r.next(4)) }
+func (r *snapshotReader) int64() int64   { return int64(binary.LittleEndian.Uint64(r.next(8))) }
+func (r *snapshotReader) string() string { return string(r.next(int(r.uint32()))) }
@@ -0,0 +1,315 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// soakWorkers is the number of requests a soak makes at once
+const soakWorkers = 16
+
+// SoakConfig configures NewSoak()
+type SoakConfig struct {
+	// (Required) The prefix of the unique keys of the rate limits, such that each instance running a
+	// soak verifies its own keys, IE: the instance id.
+	KeyPrefix string
+
+	// (Optional) The name of the rate limits. Defaults to "gubernator_soak"
+	Name string
+
+	// (Optional) The number of unique keys hit at random. Defaults to 100
+	Keys int
+
+	// (Optional) The limit and duration of each token bucket. Defaults to 5 per 10 seconds
+	Limit    int64
+	Duration time.Duration
+
+	// (Optional) The number of requests per second. Defaults to 100
+	Rate int
+
+	// (Optional) The reset times within Tolerance of each other belong to the same window, as the
+	// reset time is converted to the wall clock of the owner with each response. Must be less than
+	// half of Duration. Defaults to 500ms
+	Tolerance time.Duration
+
+	// (Optional) How often OnReport is called. Defaults to 1 minute
+	ReportInterval time.Duration
+
+	// (Optional) Called with each violation of an invariant as it is found
+	OnViolation func(SoakViolation)
+
+	// (Optional) Called with the totals since the soak started every ReportInterval
+	OnReport func(SoakReport)
+}
+
+// SoakViolation is a response which broke an invariant of the rate limits hit by a Soak
+type SoakViolation struct {
+	// The unique key of the rate limit
+	Key string
+	// The reset time of the window of the rate limit which broke the invariant
+	ResetTime int64
+	// Describes the invariant which was broken
+	Reason string
+}
+
+// SoakReport is the totals of the requests made by a Soak
+type SoakReport struct {
+	Requests   int64
+	UnderLimit int64
+	OverLimit  int64
+	// The requests which returned an error, IE: the owner was unreachable
+	Errors     int64
+	Violations int64
+}
+
+// Soak continuously hits rate limits and verifies the responses never break the invariants of a
+// token bucket. Such that correctness regressions, IE: a rate limit which allows more than the limit
+// when its owner changes, are visible after the cluster ran in staging for days. The invariants are
+//
+//   - No more than the limit is allowed in each window of a rate limit
+//   - A rate limit does not start a new window before the previous window ended
+//
+// The rate limits must only be hit by this soak, the instances of a cluster each running a soak
+// must use a different KeyPrefix.
+type Soak struct {
+	conf       SoakConfig
+	verifier   *soakVerifier
+	requests   atomic.Int64
+	underLimit atomic.Int64
+	overLimit  atomic.Int64
+	errors     atomic.Int64
+	violations atomic.Int64
+}
+
+// NewSoak returns an error if the config is invalid
+func NewSoak(conf SoakConfig) (*Soak, error) {
+	if err := conf.setDefaults(); err != nil {
+		return nil, err
+	}
+	return &Soak{
+		conf:     conf,
+		verifier: newSoakVerifier(conf.Limit, conf.Duration.Milliseconds(), conf.Tolerance.Milliseconds()),
+	}, nil
+}
+
+// Run hits the rate limits through `client` until `ctx` is done, then returns the totals
+func (s *Soak) Run(ctx context.Context, client V1Client) SoakReport {
+	conf := s.conf
+	limiter := rate.NewLimiter(rate.Limit(conf.Rate), 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < soakWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for limiter.Wait(ctx) == nil {
+				s.hit(ctx, client, fmt.Sprintf("%s%d", conf.KeyPrefix, rand.Intn(conf.Keys)))
+			}
+		}()
+	}
+
+	ticker := clock.NewTicker(conf.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C():
+			if conf.OnReport != nil {
+				conf.OnReport(s.totals())
+			}
+		case <-ctx.Done():
+			wg.Wait()
+			return s.totals()
+		}
+	}
+}
+
+func (c *SoakConfig) setDefaults() error {
+	if c.KeyPrefix == "" {
+		return errors.New("SoakConfig.KeyPrefix is required")
+	}
+	if c.Name == "" {
+		c.Name = "gubernator_soak"
+	}
+	if c.Keys <= 0 {
+		c.Keys = 100
+	}
+	if c.Limit <= 0 {
+		c.Limit = 5
+	}
+	if c.Duration <= 0 {
+		c.Duration = 10 * time.Second
+	}
+	if c.Rate <= 0 {
+		c.Rate = 100
+	}
+	if c.Tolerance <= 0 {
+		c.Tolerance = 500 * time.Millisecond
+	}
+	if c.ReportInterval <= 0 {
+		c.ReportInterval = time.Minute
+	}
+	if c.Tolerance*2 >= c.Duration {
+		return errors.Errorf("SoakConfig.Duration must be more than twice the tolerance of '%s'", c.Tolerance)
+	}
+	return nil
+}
+
+func (s *Soak) hit(ctx context.Context, client V1Client, key string) {
+	s.requests.Add(1)
+	resp, err := client.GetRateLimits(ctx, &GetRateLimitsReq{Requests: []*RateLimitReq{{
+		Name:      s.conf.Name,
+		UniqueKey: key,
+		Hits:      1,
+		Limit:     s.conf.Limit,
+		Duration:  s.conf.Duration.Milliseconds(),
+		Algorithm: Algorithm_TOKEN_BUCKET,
+	}}})
+	if err == nil && len(resp.Responses) != 1 {
+		err = errors.Errorf("expected 1 response; got '%d'", len(resp.Responses))
+	}
+	if err != nil {
+		// A request interrupted by the end of the soak is not an error
+		if ctx.Err() == nil {
+			s.errors.Add(1)
+		}
+		return
+	}
+	rl := resp.Responses[0]
+	switch {
+	case rl.Error != "":
+		s.errors.Add(1)
+		return
+	case rl.Status == Status_OVER_LIMIT:
+		s.overLimit.Add(1)
+	default:
+		s.underLimit.Add(1)
+	}
+	for _, v := range s.verifier.observe(key, rl, epochMillis(clock.Now())) {
+		s.violations.Add(1)
+		if s.conf.OnViolation != nil {
+			s.conf.OnViolation(v)
+		}
+	}
+}
+
+func (s *Soak) totals() SoakReport {
+	return SoakReport{
+		Requests:   s.requests.Load(),
+		UnderLimit: s.underLimit.Load(),
+		OverLimit:  s.overLimit.Load(),
+		Errors:     s.errors.Load(),
+		Violations: s.violations.Load(),
+	}
+}
+
+// soakVerifier counts the hits allowed in each window of the rate limits hit by a soak. A window is
+// identified by the reset time of the token bucket, which does not change until the bucket resets.
+type soakVerifier struct {
+	mutex     sync.Mutex
+	limit     int64
+	duration  int64
+	tolerance int64
+	keys      map[string][]*soakWindow
+}
+
+type soakWindow struct {
+	resetTime int64
+	allowed   int64
+	reported  bool
+}
+
+func newSoakVerifier(limit, duration, tolerance int64) *soakVerifier {
+	return &soakVerifier{
+		limit:     limit,
+		duration:  duration,
+		tolerance: tolerance,
+		keys:      make(map[string][]*soakWindow),
+	}
+}
+
+// observe records a response to a single hit of the rate limit `key` and returns the invariants
+// it broke. Responses may arrive in any order, as hits are made concurrently.
+func (v *soakVerifier) observe(key string, rl *RateLimitResp, now int64) []SoakViolation {
+	if rl.Error != "" {
+		return nil
+	}
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	// Windows which ended long ago can no longer receive responses
+	var windows []*soakWindow
+	for _, w := range v.keys[key] {
+		if w.resetTime+2*v.duration > now {
+			windows = append(windows, w)
+		}
+	}
+
+	var result []SoakViolation
+	var window *soakWindow
+	for _, w := range windows {
+		if abs64(w.resetTime-rl.ResetTime) <= v.tolerance {
+			window = w
+			break
+		}
+	}
+	if window == nil {
+		window = &soakWindow{resetTime: rl.ResetTime}
+		for _, w := range windows {
+			if abs64(w.resetTime-rl.ResetTime) < v.duration-v.tolerance {
+				result = append(result, SoakViolation{
+					Key:       key,
+					ResetTime: rl.ResetTime,
+					Reason: fmt.Sprintf("a new window which resets at '%d' started before the window which "+
+						"resets at '%d' ended", rl.ResetTime, w.resetTime),
+				})
+				break
+			}
+		}
+		windows = append(windows, window)
+	}
+	v.keys[key] = windows
+
+	if rl.Status == Status_UNDER_LIMIT {
+		window.allowed++
+	}
+	if window.allowed > v.limit && !window.reported {
+		window.reported = true
+		result = append(result, SoakViolation{
+			Key:       key,
+			ResetTime: window.resetTime,
+			Reason:    fmt.Sprintf("allowed more than the limit of '%d' in a single window", v.limit),
+		})
+	}
+	return result
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// forgetfulClient allows every hit, as if the owner of the rate limit lost its state after every
+// `forgetAfter` hits and started a new window. A zero `forgetAfter` never starts a new window.
+type forgetfulClient struct {
+	guber.V1Client
+	forgetAfter int64
+	mutex       sync.Mutex
+	hits        int64
+	reset       int64
+}
+
+func (c *forgetfulClient) GetRateLimits(_ context.Context, r *guber.GetRateLimitsReq, _ ...grpc.CallOption) (*guber.GetRateLimitsResp, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.hits == 0 || (c.forgetAfter != 0 && c.hits%c.forgetAfter == 0) {
+		c.reset = time.Now().Add(time.Duration(r.Requests[0].Duration) * time.Millisecond).UnixMilli()
+	}
+	c.hits++
+	return &guber.GetRateLimitsResp{Responses: []*guber.RateLimitResp{{
+		Status:    guber.Status_UNDER_LIMIT,
+		Limit:     r.Requests[0].Limit,
+		ResetTime: c.reset,
+	}}}, nil
+}
+
+// soakViolations runs a soak for `d` and returns the reasons of the violations found
+func soakViolations(t *testing.T, client guber.V1Client, conf guber.SoakConfig, d time.Duration) (guber.SoakReport, []string) {
+	t.Helper()
+	var mutex sync.Mutex
+	var reasons []string
+	conf.OnViolation = func(v guber.SoakViolation) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		reasons = append(reasons, v.Reason)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	soak, err := guber.NewSoak(conf)
+	require.NoError(t, err)
+	return soak.Run(ctx, client), reasons
+}
+
+func TestSoak(t *testing.T) {
+	conf := guber.SoakConfig{
+		KeyPrefix: "soak:",
+		Keys:      1,
+		Limit:     5,
+		Duration:  time.Second,
+		Rate:      50,
+		Tolerance: 100 * time.Millisecond,
+	}
+
+	t.Run("A healthy instance breaks no invariant", func(t *testing.T) {
+		srv := newV1Server(t, "localhost:0", guber.Config{})
+		defer srv.Close()
+		client, err := guber.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+
+		report, reasons := soakViolations(t, client, conf, 1500*time.Millisecond)
+		assert.Empty(t, reasons)
+		assert.Zero(t, report.Violations)
+		assert.Zero(t, report.Errors)
+		assert.Greater(t, report.UnderLimit, int64(0))
+		assert.Greater(t, report.OverLimit, int64(0))
+		assert.Equal(t, report.Requests, report.UnderLimit+report.OverLimit)
+	})
+
+	t.Run("More than the limit in a window is a violation", func(t *testing.T) {
+		report, reasons := soakViolations(t, &forgetfulClient{}, conf, 300*time.Millisecond)
+		// Each window is reported once
+		assert.Equal(t, int64(1), report.Violations)
+		assert.Equal(t, []string{"allowed more than the limit of '5' in a single window"}, reasons)
+	})
+
+	t.Run("A window which starts early is a violation", func(t *testing.T) {
+		// The reset times of each window are further apart than the tolerance
+		conf := conf
+		conf.Tolerance = 10 * time.Millisecond
+		_, reasons := soakViolations(t, &forgetfulClient{forgetAfter: 3}, conf, 300*time.Millisecond)
+		require.NotEmpty(t, reasons)
+		assert.Contains(t, reasons[0], "started before the window which resets at")
+	})
+
+	t.Run("Tolerance must fit in the duration", func(t *testing.T) {
+		_, err := guber.NewSoak(guber.SoakConfig{KeyPrefix: "soak:", Duration: time.Second})
+		assert.EqualError(t, err, "SoakConfig.Duration must be more than twice the tolerance of '500ms'")
+	})
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/setter"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS rate_limits (
+	key       TEXT PRIMARY KEY,
+	expire_at INTEGER NOT NULL,
+	item      BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS rate_limits_expire_at ON rate_limits (expire_at);
+`
+
+type SQLiteStoreConfig struct {
+	// (Required) The path of the SQLite database, created if it does not exist
+	Path string
+
+	// (Optional) How often pending changes are written to the database. Defaults to 100ms
+	FlushInterval time.Duration
+
+	// (Optional) Pending changes are written before FlushInterval elapses once this many rate
+	// limits have changed. Defaults to 1,000
+	BatchLimit int
+
+	// (Optional) How often expired rate limits are deleted from the database. Defaults to 1 minute
+	ExpireInterval time.Duration
+
+	// (Optional) Must be the same HashKeyFunc as `Config.HashKey`. Defaults to LegacyHashKey
+	HashKey HashKeyFunc
+
+	// (Optional) How keys appear in the log, see `Config.KeyRedaction`. Defaults to KeyRedactionHash
+	KeyRedaction string
+
+	// (Optional) The logger used to report failures to write to the database
+	Logger FieldLogger
+}
+
+// SQLiteStore is a Store which persists rate limits to a local SQLite database in WAL mode, such
+// that a single node deployment keeps its rate limits across restarts without an external
+// database. Changes are coalesced per rate limit and written asynchronously in batches, as such
+// a crash loses at most `FlushInterval` of hits. Only TOKEN_BUCKET and LEAKY_BUCKET items are saved.
+//
+// NOTE: The SQLite driver requires cgo, NewSQLiteStore() returns an error if built with CGO_ENABLED=0
+type SQLiteStore struct {
+	conf SQLiteStoreConfig
+	db   *sql.DB
+
+	mutex sync.Mutex
+	// Changes not yet written to the database, a nil item removes the rate limit
+	pending map[string]*CacheItem
+	// The changes currently being written to the database
+	writing map[string]*CacheItem
+
+	flush chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+var _ NamespaceStore = &SQLiteStore{}
+var _ PingStore = &SQLiteStore{}
+
+// NewSQLiteStore opens or creates the database at `conf.Path`. Call Close() once the
+// instance using the store is closed to write the remaining changes.
+func NewSQLiteStore(conf SQLiteStoreConfig) (*SQLiteStore, error) {
+	if conf.Path == "" {
+		return nil, errors.New("SQLiteStoreConfig.Path cannot be empty")
+	}
+	setter.SetDefault(&conf.FlushInterval, 100*time.Millisecond)
+	setter.SetDefault(&conf.BatchLimit, 1_000)
+	setter.SetDefault(&conf.ExpireInterval, time.Minute)
+	setter.SetDefault(&conf.Logger, logrus.WithField("category", "gubernator"))
+	if conf.HashKey == nil {
+		conf.HashKey = LegacyHashKey
+	}
+
+	// NORMAL synchronous is durable across process crashes in WAL mode, only a power
+	// loss may roll back the most recent transactions.
+	dsn := "file:" + (&url.URL{Path: conf.Path}).EscapedPath() +
+		"?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while opening '%s'", conf.Path)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, errors.Wrapf(err, "while creating schema in '%s'", conf.Path)
+	}
+
+	s := &SQLiteStore{
+		conf:    conf,
+		db:      db,
+		pending: make(map[string]*CacheItem),
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s, nil
+}
+
+func (s *SQLiteStore) OnChange(_ context.Context, _ *RateLimitReq, item *CacheItem) {
+	// The worker continues to update the item after OnChange() returns
+	item = item.copy()
+	s.mutex.Lock()
+	s.pending[item.Key] = item
+	full := len(s.pending) >= s.conf.BatchLimit
+	s.mutex.Unlock()
+
+	if full {
+		select {
+		case s.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, r *RateLimitReq) (*CacheItem, bool) {
+	key := s.conf.HashKey(r)
+	now := MillisecondNow()
+
+	// Changes which are not written yet are more recent than the database
+	s.mutex.Lock()
+	item, ok := s.pending[key]
+	if !ok {
+		item, ok = s.writing[key]
+	}
+	s.mutex.Unlock()
+	if ok {
+		if item == nil || item.ExpireAt <= now {
+			return nil, false
+		}
+		return item.copy(), true
+	}
+
+	var b []byte
+	err := s.db.QueryRowContext(ctx, "SELECT item FROM rate_limits WHERE key = ? AND expire_at > ?", key, now).Scan(&b)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.conf.Logger.WithError(err).WithField("key", redactKey(s.conf.KeyRedaction, key)).Warn("while reading rate limit from SQLite")
+		}
+		return nil, false
+	}
+	item, err = UnmarshalCacheItem(b)
+	if err != nil || item.Key != key {
+		return nil, false
+	}
+	return item, true
+}
+
+func (s *SQLiteStore) Remove(_ context.Context, key string) {
+	s.mutex.Lock()
+	s.pending[key] = nil
+	s.mutex.Unlock()
+}
+
+// RemoveNamespace removes every rate limit with the name from the database
+func (s *SQLiteStore) RemoveNamespace(ctx context.Context, name string) error {
+	s.mutex.Lock()
+	for key, item := range s.pending {
+		if item != nil && item.Name == name {
+			s.pending[key] = nil
+		}
+	}
+	s.mutex.Unlock()
+
+	// The name is only stored in the encoded item, as such every row is decoded
+	rows, err := s.db.QueryContext(ctx, "SELECT key, item FROM rate_limits")
+	if err != nil {
+		return errors.Wrap(err, "while reading rate limits")
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		var b []byte
+		if err := rows.Scan(&key, &b); err != nil {
+			return errors.Wrap(err, "while reading rate limits")
+		}
+		if item, err := UnmarshalCacheItem(b); err == nil && item.Name == name {
+			keys = append(keys, key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "while reading rate limits")
+	}
+
+	s.mutex.Lock()
+	for _, key := range keys {
+		s.pending[key] = nil
+	}
+	s.mutex.Unlock()
+	return nil
+}
+
+// Close writes the pending changes and closes the database
+// Ping returns an error if the database cannot be reached
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteStore) Close() error {
+	close(s.done)
+	s.wg.Wait()
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) run() {
+	defer s.wg.Done()
+	flush := clock.NewTicker(s.conf.FlushInterval)
+	defer flush.Stop()
+	expire := clock.NewTicker(s.conf.ExpireInterval)
+	defer expire.Stop()
+
+	for {
+		select {
+		case <-flush.C():
+			s.write()
+		case <-s.flush:
+			s.write()
+		case <-expire.C():
+			if _, err := s.db.Exec("DELETE FROM rate_limits WHERE expire_at <= ?", MillisecondNow()); err != nil {
+				s.conf.Logger.WithError(err).Warn("while deleting expired rate limits from SQLite")
+			}
+		case <-s.done:
+			s.write()
+			return
+		}
+	}
+}
+
+// write writes the pending changes in a single transaction. If the transaction fails
+// the changes are pending again, unless the rate limit changed since.
+func (s *SQLiteStore) write() {
+	s.mutex.Lock()
+	if len(s.pending) == 0 {
+		s.mutex.Unlock()
+		return
+	}
+	batch := s.pending
+	s.writing, s.pending = batch, make(map[string]*CacheItem, len(batch))
+	s.mutex.Unlock()
+
+	err := s.writeBatch(batch)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.writing = nil
+	if err != nil {
+		s.conf.Logger.WithError(err).WithField("items", len(batch)).Warn("while writing rate limits to SQLite")
+		for key, item := range batch {
+			if _, ok := s.pending[key]; !ok {
+				s.pending[key] = item
+			}
+		}
+	}
+}
+
+func (s *SQLiteStore) writeBatch(batch map[string]*CacheItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "while beginning transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	upsert, err := tx.Prepare("INSERT INTO rate_limits (key, expire_at, item) VALUES (?, ?, ?) " +
+		"ON CONFLICT (key) DO UPDATE SET expire_at = excluded.expire_at, item = excluded.item")
+	if err != nil {
+		return errors.Wrap(err, "while preparing insert")
+	}
+	defer upsert.Close()
+	remove, err := tx.Prepare("DELETE FROM rate_limits WHERE key = ?")
+	if err != nil {
+		return errors.Wrap(err, "while preparing delete")
+	}
+	defer remove.Close()
+
+	var buf []byte
+	for key, item := range batch {
+		if item == nil {
+			if _, err := remove.Exec(key); err != nil {
+				return errors.Wrapf(err, "while deleting '%s'", key)
+			}
+			continue
+		}
+		b, err := appendCacheItem(buf[:0], item)
+		if err != nil {
+			continue
+		}
+		buf = b
+		if _, err := upsert.Exec(key, item.ExpireAt, b); err != nil {
+			return errors.Wrapf(err, "while writing '%s'", key)
+		}
+	}
+	return errors.Wrap(tx.Commit(), "while committing transaction")
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/OneOfOne/xxhash"
+	"github.com/mailgun/holster/v4/setter"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StandbyConfig replicates the rate limits of a few critical names to a standby peer before the
+// owner responds, such that the rate limits survive the crash of their owner. The standby is the
+// peer which owns the rate limit once the owner is removed from the peer list, as such it holds the
+// hits applied before the crash once it becomes the owner. Each hit waits for a round trip to the
+// standby, this is only worth it for the rate limits whose reset would be costly.
+type StandbyConfig struct {
+	// (Optional) The names of the rate limits replicated to their standby peer. Replication is
+	// disabled if empty.
+	Names []string
+
+	// (Optional) The max time to wait for the standby peer to acknowledge the replicated state.
+	// Defaults to 500ms
+	Timeout time.Duration
+}
+
+func (c *StandbyConfig) validate() error {
+	if len(c.Names) == 0 {
+		return nil
+	}
+	if c.Timeout < 0 {
+		return errors.New("Standby.Timeout cannot be negative")
+	}
+	setter.SetDefault(&c.Timeout, 500*time.Millisecond)
+	return nil
+}
+
+// The number of locks which serialize the replication of the rate limits, each rate limit hashes
+// to one of them.
+const standbyLockStripes = 64
+
+type standby struct {
+	names   map[string]bool
+	timeout time.Duration
+	// Ensures the standby receives the states of a rate limit in the order they were applied
+	locks [standbyLockStripes]sync.Mutex
+}
+
+func newStandby(conf StandbyConfig) *standby {
+	s := &standby{
+		names:   make(map[string]bool, len(conf.Names)),
+		timeout: conf.Timeout,
+	}
+	for _, name := range conf.Names {
+		s.names[name] = true
+	}
+	return s
+}
+
+// replicates returns true if this instance owns the rate limit and must replicate the hits
+// applied to it before responding.
+func (s *standby) replicates(r *RateLimitReq, reqState RateLimitReqState) bool {
+	return s != nil && reqState.IsOwner && s.names[r.Name] && !isQueryOnly(r)
+}
+
+// lock serializes the evaluation and replication of the rate limit until the returned func is called
+func (s *standby) lock(key string) func() {
+	mu := &s.locks[xxhash.ChecksumString64(key)%standbyLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// replicateToStandby sends the current state of the rate limit to its standby peer and waits for
+// the standby to acknowledge it. Rate limits removed from the cache, IE: by RESET_REMAINING, are not
+// replicated, the standby keeps its last copy until it expires.
+func (s *V1Instance) replicateToStandby(ctx context.Context, key string) error {
+	peer := s.getSecondaryPeer(key)
+	if peer == nil {
+		metricStandbyCounter.WithLabelValues("skipped").Inc()
+		return nil
+	}
+	item, found, err := s.workerPool.GetCacheItem(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "during workerPool.GetCacheItem")
+	}
+	if !found {
+		return nil
+	}
+	state, err := newCacheItemState(item)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.standby.timeout)
+	defer cancel()
+	if _, err := peer.ReplicatePeerRateLimits(ctx, &ReplicatePeerRateLimitsReq{
+		Items: []*CacheItemState{state},
+	}); err != nil {
+		metricStandbyCounter.WithLabelValues("failed").Inc()
+		return errors.Wrapf(err, "while replicating rate limit to standby peer '%s'", peer.Info().GRPCAddress)
+	}
+	metricStandbyCounter.WithLabelValues("replicated").Inc()
+	return nil
+}
+
+// ReplicatePeerRateLimits is called by the owner of the rate limits to replace the copy this
+// instance holds as their standby.
+func (s *V1Instance) ReplicatePeerRateLimits(ctx context.Context, r *ReplicatePeerRateLimitsReq) (*ReplicatePeerRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.ReplicatePeerRateLimits")).ObserveDuration()
+	if len(r.Items) > maxBatchSize {
+		return nil, status.Errorf(codes.OutOfRange, "'items' list too large; max size is '%d'", maxBatchSize)
+	}
+
+	for _, state := range r.Items {
+		item, err := cacheItemFromState(state)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if item.IsExpired() {
+			continue
+		}
+		if err := s.workerPool.AddCacheItem(ctx, item.Key, item); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+	}
+	return &ReplicatePeerRateLimitsResp{}, nil
+}
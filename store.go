@@ -40,6 +40,10 @@ type TokenBucketItem struct {
 	Duration  int64
 	Remaining int64
 	CreatedAt int64
+	// The time tokens were last refilled when using GREEDY_REFILL
+	UpdatedAt int64
+	// The number of hits granted over the limit by `BehaviorConfig.GracePercent`
+	GraceUsed int64
 }
 
 // Store interface allows implementors to off load storage of all or a subset of ratelimits to
@@ -60,10 +64,68 @@ type Store interface {
 
 	// Called by gubernator when an existing rate limit should be removed from the store.
 	// NOTE: This is NOT called when an rate limit expires from the cache, store implementors
-	// must expire rate limits in the store.
+	// must expire rate limits in the store. Evictions from the cache are only reported to a
+	// BatchStore.
 	Remove(ctx context.Context, key string)
 }
 
+// The kinds of StoreChange reported to a BatchStore
+type StoreChangeType int
+
+const (
+	// StoreChangeUpdate reports the state of a rate limit after it was created or changed
+	StoreChangeUpdate StoreChangeType = iota
+	// StoreChangeRemove reports a rate limit which should be removed from the store, see Store.Remove()
+	StoreChangeRemove
+	// StoreChangeEvict reports an unexpired rate limit which left the cache without being removed,
+	// IE: it was evicted to make room or was idle. The rate limit must remain in the store, as it is
+	// loaded with Get() the next time it is requested.
+	StoreChangeEvict
+)
+
+// StoreChange is a change of a rate limit reported to a BatchStore
+type StoreChange struct {
+	Type StoreChangeType
+	// The hash key of the rate limit
+	Key string
+	// The request which changed the rate limit, only the name and algorithm are set for the rate
+	// limits spilled by CacheFullSpill. Nil unless Type is StoreChangeUpdate
+	Req *RateLimitReq
+	// A copy of the state of the rate limit after the change, which the store may keep. Nil unless
+	// Type is StoreChangeUpdate
+	Item *CacheItem
+}
+
+// BatchStore is an optional interface a Store may implement to receive every change of the rate
+// limits owned by the instance in batches, IE: to write the changes behind to a database without
+// coalescing the changes itself. Updates and removals are reported with OnChanges() instead of
+// OnChange() and Remove(); Get() is only called for rate limits without a change waiting to be
+// reported. See Config.StoreBatchLimit and Config.StoreBatchWait
+type BatchStore interface {
+	Store
+	// OnChanges is called with the changes since the previous batch. The changes of a rate limit
+	// are in the order they happened, consecutive updates of a rate limit are reported once with
+	// its latest state. Evictions may be reported for the copies of GLOBAL rate limits owned by
+	// other instances. Batches are reported one at a time.
+	OnChanges(ctx context.Context, changes []StoreChange)
+}
+
+// NamespaceStore is an optional interface a Store may implement to remove every rate limit with
+// a name, including the rate limits which are no longer in the cache. See Config.NamespaceGCAfter
+type NamespaceStore interface {
+	Store
+	// RemoveNamespace removes every rate limit with the name from the store
+	RemoveNamespace(ctx context.Context, name string) error
+}
+
+// PingStore is an optional interface a Store may implement to report whether the store is reachable,
+// an instance whose store is unreachable is not ready. See V1Instance.CheckReady()
+type PingStore interface {
+	Store
+	// Ping returns an error if the store cannot be reached
+	Ping(ctx context.Context) error
+}
+
 // Loader interface allows implementors to store all or a subset of ratelimits into a persistent
 // store during startup and shutdown of the gubernator instance.
 type Loader interface {
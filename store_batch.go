@@ -0,0 +1,173 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// storeBatcher is the Store the workers use in place of a BatchStore. It queues the changes of
+// the workers and reports them to the BatchStore with OnChanges() every Config.StoreBatchWait, or
+// as soon as Config.StoreBatchLimit changes are queued.
+//
+// Until a change is reported, Get() answers from the queued state of the rate limit, such that a
+// rate limit evicted from the cache and requested again is not loaded from a store which has not
+// seen its latest state.
+type storeBatcher struct {
+	store BatchStore
+	limit int
+
+	mutex   sync.Mutex
+	pending []StoreChange
+	// The index in `pending` of the last change of each key
+	last map[string]int
+	// The state of the keys with a change in `pending` and in the batch being reported. A nil
+	// item is a removed rate limit.
+	latest     map[string]*CacheItem
+	delivering map[string]*CacheItem
+
+	// Held while a batch is reported, such that batches are reported one at a time
+	deliverMutex sync.Mutex
+	full         chan struct{}
+	done         chan struct{}
+	wg           sync.WaitGroup
+}
+
+var _ Store = &storeBatcher{}
+
+func newStoreBatcher(store BatchStore, limit int, wait time.Duration) *storeBatcher {
+	b := &storeBatcher{
+		store:  store,
+		limit:  limit,
+		last:   make(map[string]int),
+		latest: make(map[string]*CacheItem),
+		full:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(wait)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-b.full:
+			case <-b.done:
+				return
+			}
+			b.flush(context.Background())
+		}
+	}()
+	return b
+}
+
+// OnChange queues an update of the rate limit. Consecutive updates of a rate limit are coalesced.
+func (b *storeBatcher) OnChange(_ context.Context, r *RateLimitReq, item *CacheItem) {
+	// The workers continue to modify the item in the cache
+	item = item.copy()
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.latest[item.Key] = item
+	if i, ok := b.last[item.Key]; ok && b.pending[i].Type == StoreChangeUpdate {
+		b.pending[i].Req = r
+		b.pending[i].Item = item
+		return
+	}
+	b.append(StoreChange{Type: StoreChangeUpdate, Key: item.Key, Req: r, Item: item})
+}
+
+// Remove queues a removal of the rate limit. It replaces an eviction of the rate limit queued just
+// before, as the workers remove a rate limit from the cache before removing it from the store.
+func (b *storeBatcher) Remove(_ context.Context, key string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.latest[key] = nil
+	if i, ok := b.last[key]; ok && b.pending[i].Type == StoreChangeEvict {
+		b.pending[i].Type = StoreChangeRemove
+		return
+	}
+	b.append(StoreChange{Type: StoreChangeRemove, Key: key})
+}
+
+// evict queues an eviction of an unexpired rate limit from the cache
+func (b *storeBatcher) evict(key string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.append(StoreChange{Type: StoreChangeEvict, Key: key})
+}
+
+// append queues the change, must be called with the mutex held
+func (b *storeBatcher) append(change StoreChange) {
+	b.last[change.Key] = len(b.pending)
+	b.pending = append(b.pending, change)
+	if len(b.pending) == b.limit {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Get returns the queued state of the rate limit, else the rate limit in the BatchStore
+func (b *storeBatcher) Get(ctx context.Context, r *RateLimitReq) (*CacheItem, bool) {
+	key := r.HashKey()
+	b.mutex.Lock()
+	item, ok := b.latest[key]
+	if !ok {
+		item, ok = b.delivering[key]
+	}
+	b.mutex.Unlock()
+	if ok {
+		if item == nil {
+			return nil, false
+		}
+		return item.copy(), true
+	}
+	return b.store.Get(ctx, r)
+}
+
+// flush reports the queued changes to the BatchStore and returns once they are reported
+func (b *storeBatcher) flush(ctx context.Context) {
+	b.deliverMutex.Lock()
+	defer b.deliverMutex.Unlock()
+
+	b.mutex.Lock()
+	changes := b.pending
+	b.delivering = b.latest
+	b.pending = nil
+	b.last = make(map[string]int)
+	b.latest = make(map[string]*CacheItem)
+	b.mutex.Unlock()
+
+	if len(changes) != 0 {
+		b.store.OnChanges(ctx, changes)
+	}
+
+	b.mutex.Lock()
+	b.delivering = nil
+	b.mutex.Unlock()
+}
+
+// close stops reporting changes every Config.StoreBatchWait and reports the remaining changes
+func (b *storeBatcher) close() {
+	close(b.done)
+	b.wg.Wait()
+	b.flush(context.Background())
+}
@@ -18,9 +18,14 @@ package gubernator_test
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gubernator-io/gubernator/v2"
 	"github.com/mailgun/holster/v4/clock"
@@ -28,6 +33,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
 type v1Server struct {
@@ -124,6 +132,551 @@ func TestLoader(t *testing.T) {
 	assert.Equal(t, gubernator.Status_UNDER_LIMIT, item.Status)
 }
 
+func TestSnapshotLoader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gubernator.snapshot")
+	req := &gubernator.RateLimitReq{
+		Name:      "test_snapshot",
+		UniqueKey: "account:1234",
+		Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+		Duration:  gubernator.Minute,
+		Limit:     3,
+		Hits:      1,
+	}
+	hit := func(srv *v1Server) *gubernator.RateLimitResp {
+		client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{req},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, len(resp.Responses))
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+	start := func() *v1Server {
+		srv := newV1Server(t, "localhost:0", gubernator.Config{
+			Loader: gubernator.NewSnapshotLoader(path, nil),
+		})
+		srv.srv.SetPeers([]gubernator.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+		return srv
+	}
+
+	// Starts without a snapshot
+	srv := start()
+	assert.Equal(t, int64(2), hit(srv).Remaining)
+	require.NoError(t, srv.Close())
+
+	// Restores the remaining hits from the snapshot saved by Close()
+	srv = start()
+	assert.Equal(t, int64(1), hit(srv).Remaining)
+	require.NoError(t, srv.Close())
+
+	// A corrupt snapshot is ignored
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	srv = start()
+	assert.Equal(t, int64(2), hit(srv).Remaining)
+	require.NoError(t, srv.Close())
+}
+
+func TestCacheItemEncoding(t *testing.T) {
+	for _, item := range []*gubernator.CacheItem{
+		{
+			Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+			Key:       "test_encoding_account:1234",
+			Name:      "test_encoding",
+			ExpireAt:  1000,
+			InvalidAt: 500,
+			Value: &gubernator.TokenBucketItem{
+				Status:    gubernator.Status_OVER_LIMIT,
+				Limit:     10,
+				Duration:  gubernator.Minute,
+				Remaining: 3,
+				CreatedAt: 100,
+				UpdatedAt: 200,
+				GraceUsed: 1,
+			},
+		},
+		{
+			Algorithm: gubernator.Algorithm_LEAKY_BUCKET,
+			Key:       "test_encoding_account:5678",
+			Name:      "test_encoding",
+			ExpireAt:  2000,
+			Value: &gubernator.LeakyBucketItem{
+				Limit:     10,
+				Duration:  gubernator.Minute,
+				Remaining: 4.5,
+				UpdatedAt: 300,
+				Burst:     20,
+			},
+		},
+	} {
+		t.Run(item.Algorithm.String(), func(t *testing.T) {
+			b, err := gubernator.MarshalCacheItem(item)
+			require.NoError(t, err)
+			decoded, err := gubernator.UnmarshalCacheItem(b)
+			require.NoError(t, err)
+			assert.Equal(t, item, decoded)
+
+			// Fields added by later versions are ignored
+			b = protowire.AppendTag(b, 100, protowire.VarintType)
+			b = protowire.AppendVarint(b, 42)
+			decoded, err = gubernator.UnmarshalCacheItem(b)
+			require.NoError(t, err)
+			assert.Equal(t, item, decoded)
+		})
+	}
+
+	t.Run("Newer version", func(t *testing.T) {
+		b, err := proto.Marshal(&gubernator.CacheItemState{
+			Version: gubernator.CacheItemVersion + 1,
+			Bucket:  &gubernator.CacheItemState_TokenBucket{TokenBucket: &gubernator.TokenBucketState{}},
+		})
+		require.NoError(t, err)
+		_, err = gubernator.UnmarshalCacheItem(b)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unsupported value", func(t *testing.T) {
+		_, err := gubernator.MarshalCacheItem(&gubernator.CacheItem{Value: "invalid"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Legacy encoding", func(t *testing.T) {
+		le := binary.LittleEndian
+		b := le.AppendUint32([]byte{1}, uint32(gubernator.Status_UNDER_LIMIT))
+		for _, n := range []int64{10, gubernator.Minute, 7, 100, 100, 0} {
+			b = le.AppendUint64(b, uint64(n))
+		}
+		b = append(b, byte(gubernator.Algorithm_TOKEN_BUCKET))
+		b = le.AppendUint64(b, 1000)
+		b = le.AppendUint64(b, 0)
+		for _, s := range []string{"test_encoding_account:1234", "test_encoding"} {
+			b = le.AppendUint32(b, uint32(len(s)))
+			b = append(b, s...)
+		}
+
+		item, err := gubernator.UnmarshalCacheItem(b)
+		require.NoError(t, err)
+		assert.Equal(t, "test_encoding", item.Name)
+		assert.Equal(t, int64(1000), item.ExpireAt)
+		assert.Equal(t, int64(7), item.Value.(*gubernator.TokenBucketItem).Remaining)
+
+		_, err = gubernator.UnmarshalCacheItem(b[:20])
+		assert.Error(t, err)
+	})
+}
+
+func TestSQLiteStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gubernator.db")
+	req := &gubernator.RateLimitReq{
+		Name:      "test_sqlite_store",
+		UniqueKey: "account:1234",
+		Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+		Duration:  gubernator.Minute,
+		Limit:     5,
+		Hits:      1,
+	}
+	start := func() (*v1Server, *gubernator.SQLiteStore, gubernator.V1Client) {
+		store, err := gubernator.NewSQLiteStore(gubernator.SQLiteStoreConfig{Path: path})
+		require.NoError(t, err)
+		srv := newV1Server(t, "localhost:0", gubernator.Config{Store: store})
+		srv.srv.SetPeers([]gubernator.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+		client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+		require.NoError(t, err)
+		return srv, store, client
+	}
+	hit := func(client gubernator.V1Client, r *gubernator.RateLimitReq) *gubernator.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{r},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	srv, store, client := start()
+	assert.Equal(t, int64(4), hit(client, req).Remaining)
+	assert.Equal(t, int64(3), hit(client, req).Remaining)
+	require.NoError(t, srv.Close())
+	require.NoError(t, store.Close())
+
+	// The rate limit is read from the database after a restart
+	srv, store, client = start()
+	assert.Equal(t, int64(2), hit(client, req).Remaining)
+
+	// Removed rate limits are not read from the database
+	reset := proto.Clone(req).(*gubernator.RateLimitReq)
+	reset.Behavior = gubernator.Behavior_RESET_REMAINING
+	hit(client, reset)
+	require.NoError(t, srv.Close())
+	require.NoError(t, store.Close())
+
+	srv, store, client = start()
+	defer func() {
+		require.NoError(t, srv.Close())
+		require.NoError(t, store.Close())
+	}()
+	assert.Equal(t, int64(4), hit(client, req).Remaining)
+
+	// Changes are written to the database while running
+	store2, err := gubernator.NewSQLiteStore(gubernator.SQLiteStoreConfig{Path: path})
+	require.NoError(t, err)
+	defer store2.Close()
+	assert.Eventually(t, func() bool {
+		item, ok := store2.Get(context.Background(), req)
+		return ok && item.Value.(*gubernator.TokenBucketItem).Remaining == 4
+	}, clock.Second, clock.Millisecond*10)
+}
+
+func TestCacheIdleTTL(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	store := gubernator.NewMockStore()
+	srv := newV1Server(t, "localhost:0", gubernator.Config{
+		Store:        store,
+		CacheIdleTTL: clock.Minute,
+	})
+	defer srv.Close()
+	srv.srv.SetPeers([]gubernator.PeerInfo{{GRPCAddress: srv.listener.Addr().String(), IsOwner: true}})
+	client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	hit := func(hits int64) *gubernator.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{{
+				Name:      "test_idle_ttl",
+				UniqueKey: "account:1234",
+				Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+				Duration:  gubernator.Minute * 60 * 24 * 30,
+				Limit:     10,
+				Hits:      hits,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	assert.Equal(t, int64(9), hit(1).Remaining)
+	gets := store.Called["Get()"]
+
+	// Accessed within the idle TTL, the rate limit remains in the cache
+	clock.Advance(clock.Second * 30)
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, int64(8), hit(1).Remaining)
+	assert.Equal(t, gets, store.Called["Get()"])
+
+	// Once idle the rate limit is evicted, and loaded from the Store when next accessed
+	clock.Advance(clock.Minute * 2)
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, int64(8), hit(0).Remaining)
+	assert.Equal(t, gets+1, store.Called["Get()"])
+}
+
+func TestCacheFullPolicy(t *testing.T) {
+	_, err := gubernator.NewV1Instance(gubernator.Config{
+		GRPCServers:     []*grpc.Server{grpc.NewServer()},
+		CacheFullPolicy: gubernator.CacheFullSpill,
+	})
+	assert.EqualError(t, err, "CacheFullPolicy 'spill' requires Store")
+
+	store := gubernator.NewMockStore()
+	for _, test := range []struct {
+		policy string
+		store  gubernator.Store
+		errKey string
+	}{
+		{policy: gubernator.CacheFullReject, errKey: "account:3"},
+		{policy: gubernator.CacheFullSpill, store: store},
+	} {
+		t.Run(test.policy, func(t *testing.T) {
+			srv := newV1Server(t, "localhost:0", gubernator.Config{
+				Store:           test.store,
+				CacheSize:       2,
+				Workers:         1,
+				CacheFullPolicy: test.policy,
+			})
+			defer srv.Close()
+			client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+			require.NoError(t, err)
+
+			hit := func(key string) *gubernator.RateLimitResp {
+				resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+					Requests: []*gubernator.RateLimitReq{{
+						Name:      "test_cache_full",
+						UniqueKey: key,
+						Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+						Duration:  gubernator.Minute,
+						Limit:     10,
+						Hits:      1,
+					}},
+				})
+				require.NoError(t, err)
+				return resp.Responses[0]
+			}
+
+			for _, key := range []string{"account:1", "account:2", "account:3", "account:1"} {
+				resp := hit(key)
+				if key == test.errKey {
+					assert.Contains(t, resp.Error, "ResourceExhausted")
+					continue
+				}
+				assert.Equal(t, "", resp.Error, key)
+			}
+			// A spilled rate limit is loaded from the Store with the hits it had when evicted
+			if test.store != nil {
+				assert.Equal(t, int64(8), hit("account:2").Remaining)
+				return
+			}
+			// Rate limits already in the cache are unaffected
+			assert.Equal(t, int64(7), hit("account:1").Remaining)
+		})
+	}
+}
+
+func TestCacheClasses(t *testing.T) {
+	store := gubernator.NewMockStore()
+	srv := newV1Server(t, "localhost:0", gubernator.Config{
+		Store:     store,
+		CacheSize: 100,
+		Workers:   1,
+		CacheClasses: []gubernator.CacheClass{
+			{Prefix: "test_abuse_", Size: 2, FullPolicy: gubernator.CacheFullReject, Volatile: true},
+			{Prefix: "test_abuse_login_", Size: 10, Volatile: true},
+		},
+	})
+	defer srv.Close()
+	client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	hit := func(name, key string) *gubernator.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{{
+				Name:      name,
+				UniqueKey: key,
+				Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+				Duration:  gubernator.Minute,
+				Limit:     10,
+				Hits:      1,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses[0]
+	}
+
+	// The class of "test_abuse_" is full after 2 rate limits, the other caches are unaffected
+	assert.Equal(t, "", hit("test_abuse_api", "account:1").Error)
+	assert.Equal(t, "", hit("test_abuse_api", "account:2").Error)
+	assert.Contains(t, hit("test_abuse_api", "account:3").Error, "ResourceExhausted")
+	for i := 0; i < 5; i++ {
+		key := fmt.Sprintf("account:%d", i)
+		assert.Equal(t, "", hit("test_abuse_login_form", key).Error)
+		assert.Equal(t, "", hit("test_billing", key).Error)
+	}
+	assert.Equal(t, int64(8), hit("test_abuse_api", "account:1").Remaining)
+
+	// Only the rate limits of classes which are not volatile reach the Store
+	assert.Len(t, store.CacheItems, 5)
+	for key := range store.CacheItems {
+		assert.Contains(t, key, "test_billing_")
+	}
+
+	t.Run("Invalid config", func(t *testing.T) {
+		for _, test := range []struct {
+			classes []gubernator.CacheClass
+			err     string
+		}{
+			{[]gubernator.CacheClass{{Size: 10}}, "CacheClasses.Prefix cannot be empty"},
+			{[]gubernator.CacheClass{{Prefix: "a_"}}, "CacheClasses.Size of 'a_' must be greater than 0"},
+			{[]gubernator.CacheClass{{Prefix: "a_", Size: 10}, {Prefix: "a_", Size: 10}},
+				"CacheClasses contains more than one class with prefix 'a_'"},
+			{[]gubernator.CacheClass{{Prefix: "a_", Size: 10, FullPolicy: gubernator.CacheFullSpill, Volatile: true}},
+				"CacheClasses.FullPolicy 'spill' of 'a_' requires Store and a class which is not volatile"},
+		} {
+			_, err := gubernator.NewV1Instance(gubernator.Config{
+				GRPCServers:  []*grpc.Server{grpc.NewServer()},
+				Store:        store,
+				Workers:      1,
+				CacheClasses: test.classes,
+			})
+			assert.EqualError(t, err, test.err)
+		}
+	})
+}
+
+func TestParseCacheClass(t *testing.T) {
+	c, err := gubernator.ParseCacheClass("abuse_;size=500000;idle_ttl=5m;full_policy=reject;volatile")
+	require.NoError(t, err)
+	assert.Equal(t, gubernator.CacheClass{
+		Prefix:     "abuse_",
+		Size:       500000,
+		IdleTTL:    5 * time.Minute,
+		FullPolicy: gubernator.CacheFullReject,
+		Volatile:   true,
+	}, c)
+
+	_, err = gubernator.ParseCacheClass("abuse_;size=lots")
+	assert.Error(t, err)
+	_, err = gubernator.ParseCacheClass("abuse_;ttl=5m")
+	assert.EqualError(t, err, "invalid option 'ttl=5m' in cache class 'abuse_;ttl=5m'")
+}
+
+type batchStore struct {
+	*gubernator.MockStore
+	mutex   sync.Mutex
+	batches [][]gubernator.StoreChange
+}
+
+func (s *batchStore) OnChanges(_ context.Context, changes []gubernator.StoreChange) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.batches = append(s.batches, changes)
+}
+
+func TestBatchStore(t *testing.T) {
+	store := &batchStore{MockStore: gubernator.NewMockStore()}
+	srv := newV1Server(t, "localhost:0", gubernator.Config{
+		Store:          store,
+		StoreBatchWait: time.Hour,
+		CacheSize:      2,
+		Workers:        1,
+	})
+	client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	hit := func(key string, hits int64, behavior gubernator.Behavior) *gubernator.RateLimitResp {
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{{
+				Name:      "test_batch_store",
+				UniqueKey: key,
+				Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+				Duration:  gubernator.Minute,
+				Limit:     10,
+				Hits:      hits,
+				Behavior:  behavior,
+			}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "", resp.Responses[0].Error)
+		return resp.Responses[0]
+	}
+
+	for i := 0; i < 3; i++ {
+		hit("account:1", 1, 0)
+	}
+	// Evicts account:1 from the cache
+	hit("account:2", 1, 0)
+	hit("account:3", 1, 0)
+
+	// The evicted rate limit is loaded from the change which was not yet reported
+	assert.Equal(t, int64(7), hit("account:1", 0, 0).Remaining)
+	hit("account:1", 0, gubernator.Behavior_RESET_REMAINING)
+	require.NoError(t, srv.Close())
+
+	// Every change was reported in a single batch when the instance closed
+	require.Len(t, store.batches, 1)
+	type change struct {
+		Type      gubernator.StoreChangeType
+		Key       string
+		Remaining int64
+	}
+	var changes []change
+	for _, c := range store.batches[0] {
+		var remaining int64
+		if c.Item != nil {
+			remaining = c.Item.Value.(*gubernator.TokenBucketItem).Remaining
+		}
+		changes = append(changes, change{Type: c.Type, Key: c.Key, Remaining: remaining})
+	}
+	assert.Equal(t, []change{
+		// Consecutive updates are coalesced
+		{Type: gubernator.StoreChangeUpdate, Key: "test_batch_store_account:1", Remaining: 7},
+		{Type: gubernator.StoreChangeUpdate, Key: "test_batch_store_account:2", Remaining: 9},
+		{Type: gubernator.StoreChangeEvict, Key: "test_batch_store_account:1"},
+		{Type: gubernator.StoreChangeUpdate, Key: "test_batch_store_account:3", Remaining: 9},
+		{Type: gubernator.StoreChangeEvict, Key: "test_batch_store_account:2"},
+		{Type: gubernator.StoreChangeRemove, Key: "test_batch_store_account:1"},
+	}, changes)
+	assert.Equal(t, 0, store.Called["OnChange()"])
+	assert.Equal(t, 0, store.Called["Remove()"])
+	// Only the rate limits without a queued change are requested from the store
+	assert.Equal(t, 3, store.Called["Get()"])
+}
+
+func TestNamespaceGC(t *testing.T) {
+	defer clock.Freeze(clock.Now()).Unfreeze()
+	store, err := gubernator.NewSQLiteStore(gubernator.SQLiteStoreConfig{Path: filepath.Join(t.TempDir(), "gubernator.db")})
+	require.NoError(t, err)
+	defer store.Close()
+	srv := newV1Server(t, "localhost:0", gubernator.Config{
+		Store:            store,
+		NamespaceGCAfter: clock.Hour * 2,
+		AdminEnabled:     true,
+	})
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	admin := gubernator.NewAdminV1Client(conn)
+	client, err := gubernator.DialV1Server(srv.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	req := func(name, key string) *gubernator.RateLimitReq {
+		return &gubernator.RateLimitReq{
+			Name:      name,
+			UniqueKey: key,
+			Duration:  gubernator.Minute * 60 * 24 * 30,
+			Limit:     10,
+			Hits:      1,
+		}
+	}
+	hit := func(reqs ...*gubernator.RateLimitReq) {
+		resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{Requests: reqs})
+		require.NoError(t, err)
+		for _, rl := range resp.Responses {
+			require.Equal(t, "", rl.Error)
+		}
+	}
+	list := func() []*gubernator.NamespaceInfo {
+		resp, err := admin.ListNamespaces(context.Background(), &gubernator.ListNamespacesReq{NamePrefix: "test_gc_"})
+		require.NoError(t, err)
+		require.Empty(t, resp.Errors)
+		return resp.Namespaces
+	}
+	advance := func() {
+		clock.Advance(clock.Hour)
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	hit(req("test_gc_unused", "account:1"), req("test_gc_unused", "account:2"), req("test_gc_active", "account:1"))
+	start := clock.Now().UnixMilli()
+	namespaces := list()
+	require.Len(t, namespaces, 2)
+	assert.Equal(t, "test_gc_active", namespaces[0].Name)
+	assert.Equal(t, int64(1), namespaces[0].Entries)
+	assert.Equal(t, "test_gc_unused", namespaces[1].Name)
+	assert.Equal(t, int64(2), namespaces[1].Entries)
+	assert.Equal(t, start, namespaces[1].LastAccess)
+
+	advance()
+	hit(req("test_gc_active", "account:1"))
+	advance()
+	require.Len(t, list(), 2, "not yet unused for 2 hours")
+
+	// The unused namespace is removed from the cache and the Store
+	advance()
+	namespaces = list()
+	require.Len(t, namespaces, 1)
+	assert.Equal(t, "test_gc_active", namespaces[0].Name)
+	assert.Equal(t, start+clock.Hour.Milliseconds(), namespaces[0].LastAccess)
+	_, ok := store.Get(context.Background(), req("test_gc_unused", "account:1"))
+	assert.False(t, ok)
+	_, ok = store.Get(context.Background(), req("test_gc_active", "account:1"))
+	assert.True(t, ok)
+}
+
 func TestStore(t *testing.T) {
 	ctx := context.Background()
 	setup := func() (*MockStore2, *v1Server, gubernator.V1Client) {
@@ -531,3 +1084,169 @@ func TestStore(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestValues(t *testing.T) {
+	var mutex sync.Mutex
+	tenants := make(map[string]string)
+	var spoofed bool
+
+	// Every peer records the tenant of each rate limit as seen by the Store of the owning peer
+	store := &MockStore2{}
+	store.On("Get", mock.Anything, mock.Anything).Return(nil, false)
+	store.On("OnChange", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		ctx := args.Get(0).(context.Context)
+		r := args.Get(1).(*gubernator.RateLimitReq)
+		mutex.Lock()
+		defer mutex.Unlock()
+		tenants[r.UniqueKey], _ = gubernator.RequestValue(ctx, "tenant")
+		if _, ok := gubernator.RequestValue(ctx, "admin"); ok {
+			spoofed = true
+		}
+	})
+
+	// The first peer authenticates the client and stashes the tenant in the context
+	authenticate := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(gubernator.WithRequestValue(ctx, "tenant", "acme"), req)
+	}
+	srv1 := newV1Server(t, "localhost:0", gubernator.Config{
+		GRPCServers: []*grpc.Server{grpc.NewServer(grpc.UnaryInterceptor(authenticate))},
+		Store:       store,
+	})
+	defer srv1.Close()
+	srv2 := newV1Server(t, "localhost:0", gubernator.Config{Store: store})
+	defer srv2.Close()
+	peers := []gubernator.PeerInfo{
+		{GRPCAddress: srv1.listener.Addr().String()},
+		{GRPCAddress: srv2.listener.Addr().String()},
+	}
+	peers[0].IsOwner = true
+	srv1.srv.SetPeers(peers)
+	peers[0].IsOwner, peers[1].IsOwner = false, true
+	srv2.srv.SetPeers(peers)
+
+	client, err := gubernator.DialV1Server(srv1.listener.Addr().String(), nil)
+	require.NoError(t, err)
+
+	// Both batched and unbatched requests carry the values of the first peer to the owner
+	var keys []string
+	for _, behavior := range []gubernator.Behavior{gubernator.Behavior_BATCHING, gubernator.Behavior_NO_BATCHING} {
+		// Choose keys owned by each peer
+		owners := make(map[bool]int)
+		for i := 0; owners[true] < 3 || owners[false] < 3; i++ {
+			key := fmt.Sprintf("account:%d:%d", behavior, i)
+			peer, err := srv1.srv.GetPeer(context.Background(), "test_request_values_"+key)
+			require.NoError(t, err)
+			if owners[peer.Info().IsOwner] == 3 {
+				continue
+			}
+			owners[peer.Info().IsOwner]++
+			keys = append(keys, key)
+
+			resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+				Requests: []*gubernator.RateLimitReq{{
+					Name:      "test_request_values",
+					UniqueKey: key,
+					Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+					Behavior:  behavior,
+					Duration:  gubernator.Minute,
+					Limit:     10,
+					Hits:      1,
+					// Clients cannot set request values
+					Metadata: map[string]string{"gubernator-value-admin": "true"},
+				}},
+			})
+			require.NoError(t, err)
+			require.Equal(t, "", resp.Responses[0].Error)
+		}
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, key := range keys {
+		assert.Equal(t, "acme", tenants[key], key)
+	}
+	assert.False(t, spoofed)
+}
+
+func TestSignedRequestValues(t *testing.T) {
+	var mutex sync.Mutex
+	tenants := make(map[string]string)
+	store := &MockStore2{}
+	store.On("Get", mock.Anything, mock.Anything).Return(nil, false)
+	store.On("OnChange", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		tenants[args.Get(1).(*gubernator.RateLimitReq).UniqueKey], _ = gubernator.RequestValue(args.Get(0).(context.Context), "tenant")
+	})
+
+	authenticate := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(gubernator.WithRequestValue(ctx, "tenant", "acme"), req)
+	}
+	srv1 := newV1Server(t, "localhost:0", gubernator.Config{
+		GRPCServers:         []*grpc.Server{grpc.NewServer(grpc.UnaryInterceptor(authenticate))},
+		RequestValueSigning: &gubernator.RequestValueSigningConfig{Key: "old-key"},
+	})
+	defer srv1.Close()
+	// The owner accepts the values signed with the key it is rotating from
+	srv2 := newV1Server(t, "localhost:0", gubernator.Config{
+		Store:               store,
+		RequestValueSigning: &gubernator.RequestValueSigningConfig{Key: "new-key", AcceptKeys: []string{"old-key"}},
+	})
+	defer srv2.Close()
+	peers := []gubernator.PeerInfo{
+		{GRPCAddress: srv1.listener.Addr().String()},
+		{GRPCAddress: srv2.listener.Addr().String(), IsOwner: true},
+	}
+	srv2.srv.SetPeers(peers)
+	peers[0].IsOwner, peers[1].IsOwner = true, false
+	srv1.srv.SetPeers(peers)
+
+	// Choose a key owned by the second peer
+	var key string
+	for i := 0; key == ""; i++ {
+		peer, err := srv1.srv.GetPeer(context.Background(), fmt.Sprintf("test_signed_request_values_account:%d", i))
+		require.NoError(t, err)
+		if !peer.Info().IsOwner {
+			key = fmt.Sprintf("account:%d", i)
+		}
+	}
+	req := &gubernator.RateLimitReq{
+		Name:      "test_signed_request_values",
+		UniqueKey: key,
+		Duration:  gubernator.Minute,
+		Limit:     10,
+		Hits:      1,
+	}
+
+	client, err := gubernator.DialV1Server(srv1.listener.Addr().String(), nil)
+	require.NoError(t, err)
+	resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{
+		Requests: []*gubernator.RateLimitReq{proto.Clone(req).(*gubernator.RateLimitReq)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "", resp.Responses[0].Error)
+	mutex.Lock()
+	assert.Equal(t, "acme", tenants[key])
+	mutex.Unlock()
+
+	// Values asserted without a signature of an accepted key are rejected by the owner
+	conn, err := grpc.Dial(srv2.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	peerClient := gubernator.NewPeersV1Client(conn)
+	for signature, expected := range map[string]string{
+		"":         "request values are not signed",
+		"0badc0de": "request values signature is invalid",
+	} {
+		forged := proto.Clone(req).(*gubernator.RateLimitReq)
+		forged.Metadata = map[string]string{"gubernator-value-tenant": "other"}
+		if signature != "" {
+			forged.Metadata["gubernator-values-signature"] = signature
+		}
+		resp, err := peerClient.GetPeerRateLimits(context.Background(), &gubernator.GetPeerRateLimitsReq{
+			Requests: []*gubernator.RateLimitReq{forged},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, resp.RateLimits[0].Error, expected)
+	}
+}
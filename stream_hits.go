@@ -0,0 +1,166 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	streamHitsDefaultInterval = time.Second
+	streamHitsMinInterval     = 10 * time.Millisecond
+)
+
+// StreamHits sums the hits received for each rate limit and applies them once per interval, after
+// which the status of each rate limit which received hits is sent back, see StreamHitsReq.
+func (s *V1Instance) StreamHits(stream V1_StreamHitsServer) error {
+	metricHitStreams.Inc()
+	defer metricHitStreams.Dec()
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	interval := streamHitsDefaultInterval
+	if first.Interval != 0 {
+		interval = time.Duration(first.Interval) * time.Millisecond
+		if interval < streamHitsMinInterval {
+			return status.Errorf(codes.InvalidArgument, "interval must be at least '%d' milliseconds",
+				streamHitsMinInterval.Milliseconds())
+		}
+	}
+
+	pending := newStreamedHits()
+	if err := pending.add(s.conf.HashKey, first.Requests); err != nil {
+		return err
+	}
+
+	// Recv() blocks until the next message, as such the stream is read in its own goroutine. It
+	// returns once the handler returns, as the context of the stream is then canceled.
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			r, err := stream.Recv()
+			if err == nil {
+				err = pending.add(s.conf.HashKey, r.Requests)
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+		}
+	}()
+
+	tick := clock.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C():
+			if err := s.applyStreamedHits(stream, pending); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			if err != io.EOF {
+				return err
+			}
+			// The hits received before the client closed the stream are applied one last time
+			return s.applyStreamedHits(stream, pending)
+		}
+	}
+}
+
+// applyStreamedHits applies the hits received since the previous interval and sends the status of
+// each rate limit, if any
+func (s *V1Instance) applyStreamedHits(stream V1_StreamHitsServer, pending *streamedHits) error {
+	reqs := pending.take()
+	if len(reqs) == 0 {
+		return nil
+	}
+	// Copied before GetRateLimits() normalizes the requests
+	statuses := make([]*StreamHitsStatus, len(reqs))
+	for i, r := range reqs {
+		statuses[i] = &StreamHitsStatus{Name: r.Name, UniqueKey: r.UniqueKey, Hits: r.Hits}
+	}
+	resp, err := s.GetRateLimits(stream.Context(), &GetRateLimitsReq{Requests: reqs})
+	if err != nil {
+		return err
+	}
+	for i, rl := range resp.Responses {
+		statuses[i].RateLimit = rl
+	}
+	return stream.Send(&StreamHitsResp{Statuses: statuses})
+}
+
+// streamedHits holds the sum of the hits received for each rate limit during an interval
+type streamedHits struct {
+	mutex sync.Mutex
+	reqs  map[string]*RateLimitReq
+	// The keys of `reqs` in the order they were first received
+	keys []string
+}
+
+func newStreamedHits() *streamedHits {
+	return &streamedHits{reqs: make(map[string]*RateLimitReq)}
+}
+
+// add sums the hits of each request with the hits of the same rate limit received earlier in the
+// interval. The most recent request defines the rate limit.
+func (h *streamedHits) add(hashKey HashKeyFunc, reqs []*RateLimitReq) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, r := range reqs {
+		if r == nil {
+			continue
+		}
+		// The hits of several requests are applied at once, they cannot be a retry of a single request
+		r.IdempotencyKey = ""
+		key := hashKey(r)
+		if prev, ok := h.reqs[key]; ok {
+			r.Hits = addInt64(r.Hits, prev.Hits)
+		} else {
+			if len(h.keys) >= maxBatchSize {
+				return status.Errorf(codes.OutOfRange,
+					"more than '%d' rate limits received within a single interval", maxBatchSize)
+			}
+			h.keys = append(h.keys, key)
+		}
+		h.reqs[key] = r
+	}
+	return nil
+}
+
+// take returns the requests received during the interval and starts the next interval
+func (h *streamedHits) take() []*RateLimitReq {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	reqs := make([]*RateLimitReq, len(h.keys))
+	for i, key := range h.keys {
+		reqs[i] = h.reqs[key]
+	}
+	h.reqs = make(map[string]*RateLimitReq, len(h.keys))
+	h.keys = h.keys[:0]
+	return reqs
+}
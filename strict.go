@@ -0,0 +1,84 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"github.com/mailgun/holster/v4/clock"
+)
+
+const (
+	// MetadataStrict is set to "true" in the metadata of a RateLimitReq to fail closed when the
+	// peer which owns the rate limit cannot be reached, see NamespacePolicy.Strict
+	MetadataStrict = "strict"
+	// MetadataErrorCode is set in the metadata of a RateLimitResp which failed closed, or whose
+	// status is UNKNOWN, to one of the ErrorCode constants
+	MetadataErrorCode = "error_code"
+	// MetadataStrictError is set to the error which caused a RateLimitResp to fail closed
+	MetadataStrictError = "strict_error"
+)
+
+// ErrorCodePeerUnavailable is the MetadataErrorCode of a strict rate limit whose owning peer
+// could not be reached or did not answer in time
+const ErrorCodePeerUnavailable = "peer_unavailable"
+
+// isStrict returns true if the request fails closed, either because it asked to or because the
+// policy of its namespace is strict
+func (s *V1Instance) isStrict(r *RateLimitReq) bool {
+	if r.Metadata[MetadataStrict] == "true" {
+		return true
+	}
+	p := s.policyFor(r.Name)
+	return p != nil && p.Strict
+}
+
+// peerUnavailable returns the response of a rate limit whose owning peer could not be reached,
+// which fails closed if the rate limit is strict and fails open otherwise
+func (s *V1Instance) peerUnavailable(r *RateLimitReq, err string) *RateLimitResp {
+	if s.isStrict(r) {
+		return failClosed(r, err)
+	}
+	return failOpen(r, err)
+}
+
+// failOpen returns an UNKNOWN response which keeps the error, such that clients which fail open
+// may tell an infrastructure failure from a denial by the Status alone.
+func failOpen(r *RateLimitReq, err string) *RateLimitResp {
+	return &RateLimitResp{
+		Status:    Status_UNKNOWN,
+		Limit:     r.Limit,
+		Error:     err,
+		RequestId: r.RequestId,
+		Metadata:  map[string]string{MetadataErrorCode: ErrorCodePeerUnavailable},
+	}
+}
+
+// failClosed returns an OVER_LIMIT response in place of the error of a strict rate limit. The
+// Error of the response is left empty, such that clients which fail open on errors still deny
+// the request; the cause is in the metadata instead.
+func failClosed(r *RateLimitReq, err string) *RateLimitResp {
+	metricStrictCounter.WithLabelValues(r.Name).Inc()
+	return &RateLimitResp{
+		Status:    Status_OVER_LIMIT,
+		Limit:     r.Limit,
+		ResetTime: epochMillis(clock.Now()),
+		RequestId: r.RequestId,
+		Metadata: map[string]string{
+			MetadataErrorCode:   ErrorCodePeerUnavailable,
+			MetadataStrictError: err,
+		},
+	}
+}
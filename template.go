@@ -0,0 +1,142 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RateLimitTemplate defines the limit, duration and algorithm of every rate limit with the name of
+// the template. Clients reference the template by name and provide only the unique key, IE:
+// `name: "per-user-login", unique_key: "user:1234"`, such that clients never hard code the numbers
+// and operators can tune the rate limit without deploying the clients. The limit, duration, algorithm
+// and burst of the request are replaced by those of the template.
+type RateLimitTemplate struct {
+	// (Required) The name of the rate limits the template applies to, IE: "per-user-login"
+	Name string
+
+	// (Required) The number of hits allowed per Duration
+	Limit int64
+
+	// (Required) The duration of the rate limit
+	Duration time.Duration
+
+	// (Optional) The algorithm of the rate limit. Defaults to TOKEN_BUCKET
+	Algorithm Algorithm
+
+	// (Optional) Behaviors added to the behaviors requested by the client. Defaults to none
+	Behavior Behavior
+
+	// (Optional) The burst of a LEAKY_BUCKET. Defaults to the burst requested by the client
+	Burst int64
+
+	// (Optional) Limits which replace Limit while the time the request was created matches their
+	// schedule, the first matching schedule wins. Defaults to none
+	Schedules []LimitSchedule
+}
+
+func (t *RateLimitTemplate) validate() error {
+	switch {
+	case t.Name == "":
+		return errors.New("Templates.Name cannot be empty")
+	case t.Limit < 0:
+		return errors.Errorf("Templates.Limit of '%s' cannot be negative", t.Name)
+	case t.Duration <= 0:
+		return errors.Errorf("Templates.Duration of '%s' must be greater than zero", t.Name)
+	case t.Burst < 0:
+		return errors.Errorf("Templates.Burst of '%s' cannot be negative", t.Name)
+	}
+	for i := range t.Schedules {
+		if err := t.Schedules[i].validate(t.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply replaces the limit, duration, algorithm and burst of the request with those of the template
+func (t *RateLimitTemplate) apply(r *RateLimitReq) {
+	r.Limit = t.limitAt(time.UnixMilli(r.GetCreatedAt()))
+	r.Duration = t.Duration.Milliseconds()
+	r.Algorithm = t.Algorithm
+	r.Behavior |= t.Behavior
+	if t.Burst != 0 {
+		r.Burst = t.Burst
+	}
+}
+
+// limitAt returns the limit of the first schedule which matches `at`, or Limit if none match
+func (t *RateLimitTemplate) limitAt(at time.Time) int64 {
+	for _, s := range t.Schedules {
+		if s.cron.matches(at) {
+			return s.Limit
+		}
+	}
+	return t.Limit
+}
+
+// ParseRateLimitTemplate parses a template in the format used by `GUBER_RATE_LIMIT_TEMPLATES`, a name
+// followed by the limit per duration and optionally the algorithm, behaviors and burst separated by
+// spaces, IE: "per-user-login: 5 per 60s token_bucket" or "uploads: 100 per 1h leaky_bucket burst=20"
+func ParseRateLimitTemplate(s string) (RateLimitTemplate, error) {
+	name, def, ok := strings.Cut(strings.TrimSpace(s), ":")
+	fields := strings.Fields(def)
+	if !ok || len(fields) < 3 || fields[1] != "per" {
+		return RateLimitTemplate{}, errors.Errorf("'%s' is invalid; expected '<name>: <limit> per <duration> [algorithm] [behaviors] [burst=<n>]'", s)
+	}
+
+	t := RateLimitTemplate{Name: strings.TrimSpace(name)}
+	var err error
+	if t.Limit, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return t, errors.Wrapf(err, "invalid limit '%s' in template '%s'", fields[0], s)
+	}
+	if t.Duration, err = time.ParseDuration(fields[2]); err != nil {
+		return t, errors.Wrapf(err, "invalid duration '%s' in template '%s'", fields[2], s)
+	}
+
+	for _, f := range fields[3:] {
+		if v, ok := strings.CutPrefix(f, "burst="); ok {
+			if t.Burst, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return t, errors.Wrapf(err, "invalid burst '%s' in template '%s'", v, s)
+			}
+			continue
+		}
+		if a, ok := Algorithm_value[strings.ToUpper(f)]; ok {
+			t.Algorithm = Algorithm(a)
+			continue
+		}
+		if b, ok := Behavior_value[strings.ToUpper(f)]; ok {
+			t.Behavior |= Behavior(b)
+			continue
+		}
+		return t, errors.Errorf("invalid option '%s' in template '%s'", f, s)
+	}
+	return t, t.validate()
+}
+
+// templatesByName returns the templates indexed by name
+func templatesByName(templates []RateLimitTemplate) map[string]*RateLimitTemplate {
+	result := make(map[string]*RateLimitTemplate, len(templates))
+	for i := range templates {
+		result[templates[i].Name] = &templates[i]
+	}
+	return result
+}
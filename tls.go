@@ -114,6 +114,15 @@ type TLSConfig struct {
 	// (Optional) the server name to check when validating the provided certificate
 	ClientAuthServerName string
 
+	// (Optional) Additional server certificates. The certificate presented to a client is selected
+	// by the server name (SNI) the client requested, the certificate in CertFile is presented when
+	// no certificate matches.
+	SNICertificates []CertificateFiles
+
+	// (Optional) How often the certificate files are checked for changes. Changed certificates are
+	// reloaded without a restart, IE: when rotated by cert-manager. Defaults to 0 (never reloaded)
+	ReloadInterval time.Duration
+
 	// (Optional) The config created for use by the gubernator server. If set, all other
 	// fields in this struct are ignored and this config is used. If unset, gubernator.SetupTLS()
 	// will create a config using the above fields.
@@ -256,6 +265,18 @@ func SetupTLS(conf *TLSConfig) error {
 		conf.ClientTLS.Certificates = []tls.Certificate{serverCert}
 	}
 
+	if len(conf.SNICertificates) != 0 || conf.ReloadInterval > 0 {
+		reloader, err := newCertReloader(conf)
+		if err != nil {
+			return err
+		}
+		conf.ServerTLS.GetCertificate = reloader.getCertificate
+		if len(conf.ClientTLS.Certificates) != 0 {
+			conf.ClientTLS.Certificates = nil
+			conf.ClientTLS.GetClientCertificate = reloader.getClientCertificate
+		}
+	}
+
 	// If user asked for client auth
 	if conf.ClientAuth != tls.NoClientCert {
 		clientPool := x509.NewCertPool()
@@ -282,6 +303,7 @@ func SetupTLS(conf *TLSConfig) error {
 				return errors.Wrap(err, "while parsing client certificate and private key")
 			}
 			conf.ClientTLS.Certificates = []tls.Certificate{clientCert}
+			conf.ClientTLS.GetClientCertificate = nil
 		}
 	}
 
@@ -0,0 +1,170 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+// CertificateFiles is a server certificate and private key in PEM format
+type CertificateFiles struct {
+	CertFile string
+	KeyFile  string
+}
+
+// ParseCertificateFiles parses a certificate in the format used by `GUBER_TLS_SNI_CERTS`, IE: "cert.pem:key.pem"
+func ParseCertificateFiles(s string) (CertificateFiles, error) {
+	cert, key, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || cert == "" || key == "" {
+		return CertificateFiles{}, errors.Errorf("'%s' is invalid; expected '<cert file>:<key file>'", s)
+	}
+	return CertificateFiles{CertFile: cert, KeyFile: key}, nil
+}
+
+// certReloader selects the server certificate by the server name (SNI) requested by the
+// client, and reloads the certificate files when they change on disk.
+type certReloader struct {
+	log      FieldLogger
+	interval time.Duration
+	files    []CertificateFiles
+	// The certificate presented when no file is configured, IE: generated by AutoTLS
+	static []tls.Certificate
+
+	mutex   sync.Mutex
+	checked time.Time
+	modTime []time.Time
+	certs   []tls.Certificate
+}
+
+func newCertReloader(conf *TLSConfig) (*certReloader, error) {
+	r := &certReloader{
+		log:      conf.Logger,
+		interval: conf.ReloadInterval,
+		checked:  clock.Now(),
+	}
+	if conf.CertFile != "" && conf.KeyFile != "" {
+		r.files = append(r.files, CertificateFiles{CertFile: conf.CertFile, KeyFile: conf.KeyFile})
+	} else {
+		r.static = conf.ServerTLS.Certificates
+	}
+	r.files = append(r.files, conf.SNICertificates...)
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads every certificate file, the current certificates are unchanged if any file is invalid
+func (r *certReloader) load() error {
+	certs := append([]tls.Certificate(nil), r.static...)
+	modTime := make([]time.Time, 0, len(r.files))
+	for _, f := range r.files {
+		mod, err := lastModified(f)
+		if err != nil {
+			return err
+		}
+		cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
+		if err != nil {
+			return errors.Wrapf(err, "while parsing certificate '%s' and private key '%s'", f.CertFile, f.KeyFile)
+		}
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return errors.Wrapf(err, "while parsing certificate '%s'", f.CertFile)
+		}
+		certs = append(certs, cert)
+		modTime = append(modTime, mod)
+	}
+	r.certs, r.modTime = certs, modTime
+	return nil
+}
+
+// changed returns true if any of the certificate files were modified since they were loaded
+func (r *certReloader) changed() bool {
+	for i, f := range r.files {
+		if mod, err := lastModified(f); err == nil && mod.After(r.modTime[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastModified returns the latest modification time of the certificate and key files
+func lastModified(f CertificateFiles) (time.Time, error) {
+	var last time.Time
+	for _, name := range []string{f.CertFile, f.KeyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return last, errors.Wrapf(err, "while reading '%s'", name)
+		}
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last, nil
+}
+
+// current returns the certificates, reloading them if `ReloadInterval` has passed since
+// the files were last checked and any of the files changed.
+func (r *certReloader) current() []tls.Certificate {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.interval > 0 && clock.Since(r.checked) >= r.interval {
+		r.checked = clock.Now()
+		if r.changed() {
+			if err := r.load(); err != nil {
+				r.log.WithError(err).Warn("while reloading TLS certificates; continuing with the previous certificates")
+			} else {
+				r.log.Info("reloaded TLS certificates")
+			}
+		}
+	}
+	return r.certs
+}
+
+// getCertificate implements `tls.Config.GetCertificate`. The first certificate which matches
+// the requested server name is presented, else the first certificate.
+func (r *certReloader) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certs := r.current()
+	if len(certs) == 0 {
+		return nil, nil
+	}
+	for i := range certs {
+		if hello.SupportsCertificate(&certs[i]) == nil {
+			return &certs[i], nil
+		}
+	}
+	return &certs[0], nil
+}
+
+// getClientCertificate implements `tls.Config.GetClientCertificate` for peers which
+// authenticate with the server certificate
+func (r *certReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certs := r.current()
+	if len(certs) == 0 {
+		return &tls.Certificate{}, nil
+	}
+	return &certs[0], nil
+}
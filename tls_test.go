@@ -21,15 +21,25 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/gubernator-io/gubernator/v2"
 	"github.com/mailgun/holster/v4/clock"
+	"github.com/mailgun/holster/v4/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/http2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 func spawnDaemon(t *testing.T, conf gubernator.DaemonConfig) *gubernator.Daemon {
@@ -153,6 +163,62 @@ func TestSetupTLS(t *testing.T) {
 	}
 }
 
+func TestSetupTLSSNICertificates(t *testing.T) {
+	// Copy the default certificate such that it can be replaced while running
+	dir := t.TempDir()
+	copyFile := func(from, to string) {
+		b, err := os.ReadFile(from)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(to, b, 0o600))
+	}
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key")
+	copyFile("contrib/certs/gubernator.pem", certFile)
+	copyFile("contrib/certs/gubernator.key", keyFile)
+
+	conf := gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9695",
+		HTTPListenAddress: "127.0.0.1:9685",
+		TLS: &gubernator.TLSConfig{
+			CaFile:   "contrib/certs/ca.cert",
+			CertFile: certFile,
+			KeyFile:  keyFile,
+			SNICertificates: []gubernator.CertificateFiles{{
+				CertFile: "contrib/certs/gubernator_no_ip_san.pem",
+				KeyFile:  "contrib/certs/gubernator_no_ip_san.key",
+			}},
+			ReloadInterval: clock.Millisecond * 10,
+		},
+	}
+	d := spawnDaemon(t, conf)
+	defer d.Close()
+
+	served := func(serverName string) []string {
+		conn, err := tls.Dial("tcp", conf.GRPCListenAddress, &tls.Config{
+			InsecureSkipVerify: true,
+			ServerName:         serverName,
+			NextProtos:         []string{"h2"},
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].DNSNames
+	}
+
+	// The certificate is selected by the requested server name
+	assert.Equal(t, []string{"localhost"}, served("localhost"))
+	assert.Equal(t, []string{"gubernator"}, served("gubernator"))
+	assert.Equal(t, []string{"localhost"}, served("unknown"))
+	require.NoError(t, makeRequest(t, conf))
+
+	// The default certificate is reloaded once replaced on disk
+	copyFile("contrib/certs/gubernator_no_ip_san.pem", certFile)
+	copyFile("contrib/certs/gubernator_no_ip_san.key", keyFile)
+	future := clock.Now().Add(clock.Second)
+	require.NoError(t, os.Chtimes(certFile, future, future))
+	testutil.UntilPass(t, 20, clock.Millisecond*50, func(t testutil.TestingT) {
+		assert.Equal(t, []string{"gubernator"}, served("localhost"))
+	})
+}
+
 func TestSetupTLSSkipVerify(t *testing.T) {
 	conf := gubernator.DaemonConfig{
 		GRPCListenAddress: "127.0.0.1:9695",
@@ -285,6 +351,83 @@ func TestTLSClusterWithClientAuthentication(t *testing.T) {
 	assert.Contains(t, string(b), `{method="/pb.gubernator.PeersV1/GetPeerRateLimits"} 1`)
 }
 
+func TestTLSClusterWithQUICPeerTransport(t *testing.T) {
+	serverTLS := gubernator.TLSConfig{
+		CaFile:   "contrib/certs/ca.cert",
+		CertFile: "contrib/certs/gubernator.pem",
+		KeyFile:  "contrib/certs/gubernator.key",
+	}
+
+	d1 := spawnDaemon(t, gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9695",
+		HTTPListenAddress: "127.0.0.1:9685",
+		PeerTransport:     gubernator.PeerTransportQUIC,
+		TLS:               &serverTLS,
+	})
+	defer d1.Close()
+
+	d2 := spawnDaemon(t, gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9696",
+		HTTPListenAddress: "127.0.0.1:9686",
+		PeerTransport:     gubernator.PeerTransportQUIC,
+		TLS:               &serverTLS,
+	})
+	defer d2.Close()
+
+	peers := []gubernator.PeerInfo{
+		{
+			GRPCAddress: d1.GRPCListeners[0].Addr().String(),
+			HTTPAddress: d1.HTTPListener.Addr().String(),
+		},
+		{
+			GRPCAddress: d2.GRPCListeners[0].Addr().String(),
+			HTTPAddress: d2.HTTPListener.Addr().String(),
+		},
+	}
+	d1.SetPeers(peers)
+	d2.SetPeers(peers)
+
+	conf := d1.Config()
+	client, err := gubernator.DialV1Server(conf.GRPCListenAddress, conf.ClientTLS())
+	require.NoError(t, err)
+
+	// Enough keys that some are owned by d2 and forwarded over QUIC
+	var reqs []*gubernator.RateLimitReq
+	for i := 0; i < 50; i++ {
+		reqs = append(reqs, &gubernator.RateLimitReq{
+			Name:      "test_quic",
+			UniqueKey: fmt.Sprintf("account:%d", i),
+			Algorithm: gubernator.Algorithm_TOKEN_BUCKET,
+			Duration:  gubernator.Second * 30,
+			Limit:     100,
+			Hits:      1,
+		})
+	}
+	resp, err := client.GetRateLimits(context.Background(), &gubernator.GetRateLimitsReq{Requests: reqs})
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, len(reqs))
+	for _, rl := range resp.Responses {
+		assert.Empty(t, rl.Error)
+		assert.Equal(t, int64(99), rl.Remaining)
+	}
+
+	config := d2.Config()
+	httpClient := &http.Client{
+		Transport: &http2.Transport{
+			TLSClientConfig: config.ClientTLS(),
+		},
+	}
+	r, err := httpClient.Get(fmt.Sprintf("https://%s/metrics", config.HTTPListenAddress))
+	require.NoError(t, err)
+	defer r.Body.Close()
+
+	b, err := io.ReadAll(r.Body)
+	require.NoError(t, err)
+	// d2 should have been called over QUIC rather than GRPC
+	assert.Contains(t, string(b), `gubernator_func_duration_count{name="V1Instance.GetPeerRateLimits"}`)
+	assert.NotContains(t, string(b), `{method="/pb.gubernator.PeersV1/GetPeerRateLimits"}`)
+}
+
 func TestHTTPSClientAuth(t *testing.T) {
 	conf := gubernator.DaemonConfig{
 		GRPCListenAddress:       "127.0.0.1:9695",
@@ -326,7 +469,7 @@ func TestHTTPSClientAuth(t *testing.T) {
 	defer resp.Body.Close()
 	b, err := io.ReadAll(resp.Body)
 	require.NoError(t, err)
-	assert.Equal(t, `{"status":"healthy","message":"","peer_count":1}`, strings.ReplaceAll(string(b), " ", ""))
+	assert.Regexp(t, `^{"status":"healthy","message":"","peer_count":1,"endpoints":\[\],"versions":\[\],"policy_version":"","config_checksum":"[0-9a-f]{16}"}$`, strings.ReplaceAll(string(b), " ", ""))
 
 	// Verify we get an error when we try to access existing HTTPListenAddress without cert
 	//nolint:bodyclose // Expect error, no body to close.
@@ -339,5 +482,150 @@ func TestHTTPSClientAuth(t *testing.T) {
 	defer resp2.Body.Close()
 	b, err = io.ReadAll(resp2.Body)
 	require.NoError(t, err)
-	assert.Equal(t, `{"status":"healthy","message":"","peer_count":1}`, strings.ReplaceAll(string(b), " ", ""))
+	assert.Regexp(t, `^{"status":"healthy","message":"","peer_count":1,"endpoints":\[\],"versions":\[\],"policy_version":"","config_checksum":"[0-9a-f]{16}"}$`, strings.ReplaceAll(string(b), " ", ""))
+}
+
+func TestGRPCListeners(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SO_REUSEPORT is not supported on windows")
+	}
+	conf := gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9698",
+		HTTPListenAddress: "127.0.0.1:9688",
+		GRPCListeners:     3,
+		TLS: &gubernator.TLSConfig{
+			CaFile:   "contrib/certs/ca.cert",
+			CertFile: "contrib/certs/gubernator.pem",
+			KeyFile:  "contrib/certs/gubernator.key",
+		},
+	}
+
+	d := spawnDaemon(t, conf)
+	defer d.Close()
+
+	// Three listeners bound to the same address, followed by the API Gateway listener
+	require.Len(t, d.GRPCListeners, 4)
+	for _, l := range d.GRPCListeners[:3] {
+		assert.Equal(t, conf.GRPCListenAddress, l.Addr().String())
+	}
+
+	// Each request dials a new connection, which the kernel may hand to any of the listeners
+	for i := 0; i < 10; i++ {
+		require.NoError(t, makeRequest(t, conf))
+	}
+
+	// The HTTP gateway is served by the API Gateway listener
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: conf.TLS.ClientTLS}}
+	resp, err := client.Get(fmt.Sprintf("https://%s/v1/HealthCheck", conf.HTTPListenAddress))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestProvidedListeners(t *testing.T) {
+	grpcListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	httpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	d := spawnDaemon(t, gubernator.DaemonConfig{
+		GRPCListener: grpcListener,
+		HTTPListener: httpListener,
+	})
+	defer d.Close()
+
+	// The addresses of the listeners replace the configured addresses
+	conf := d.Config()
+	assert.Equal(t, grpcListener.Addr().String(), conf.GRPCListenAddress)
+	assert.Equal(t, httpListener.Addr().String(), conf.HTTPListenAddress)
+	d.SetPeers([]gubernator.PeerInfo{{GRPCAddress: conf.GRPCListenAddress}})
+	assert.True(t, d.V1Server.GetPeerList()[0].Info().IsOwner)
+	client, err := gubernator.DialV1Server(conf.GRPCListenAddress, nil)
+	require.NoError(t, err)
+	_, err = client.HealthCheck(context.Background(), &gubernator.HealthCheckReq{})
+	require.NoError(t, err)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/v1/HealthCheck", conf.HTTPListenAddress))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A provided listener cannot be split into GRPCListeners
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	_, err = gubernator.SpawnDaemon(context.Background(), gubernator.DaemonConfig{GRPCListener: l, GRPCListeners: 2})
+	assert.ErrorContains(t, err, "GRPCListeners")
+}
+
+func TestDaemonErr(t *testing.T) {
+	conf := gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9699",
+		HTTPListenAddress: "127.0.0.1:9689",
+	}
+	d := spawnDaemon(t, conf)
+
+	select {
+	case err := <-d.Err():
+		t.Fatalf("unexpected error from a healthy daemon: %s", err)
+	default:
+	}
+
+	// A listener which fails while the daemon is running is reported by Err()
+	require.NoError(t, d.GRPCListeners[0].Close())
+	select {
+	case err := <-d.Err():
+		assert.ErrorContains(t, err, "while serving GRPC")
+	case <-clock.After(clock.Second * 5):
+		t.Fatal("timed out waiting for the listener error")
+	}
+
+	// Close() closes the channel
+	d.Close()
+	for range d.Err() {
+	}
+}
+
+func TestLivenessReadiness(t *testing.T) {
+	conf := gubernator.DaemonConfig{
+		GRPCListenAddress: "127.0.0.1:9680",
+		HTTPListenAddress: "127.0.0.1:9690",
+		ReadyMinPeers:     2,
+	}
+	d := spawnDaemon(t, conf)
+	defer d.Close()
+
+	conn, err := grpc.Dial(conf.GRPCListenAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	health := grpc_health_v1.NewHealthClient(conn)
+
+	check := func(t testutil.TestingT, path string, code int, service string, serving grpc_health_v1.HealthCheckResponse_ServingStatus) {
+		resp, err := http.DefaultClient.Get("http://" + conf.HTTPListenAddress + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, code, resp.StatusCode)
+
+		hc, err := health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, serving, hc.Status)
+	}
+
+	// Alive, but waiting for more peers
+	check(t, "/healthz", http.StatusOK, gubernator.HealthServiceLiveness, grpc_health_v1.HealthCheckResponse_SERVING)
+	check(t, "/readyz", http.StatusServiceUnavailable, gubernator.HealthServiceReadiness, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	_, err = health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Enough peers, but the overrides of the other peer cannot be copied
+	d.SetPeers([]gubernator.PeerInfo{{GRPCAddress: conf.GRPCListenAddress, IsOwner: true}, {GRPCAddress: "127.0.0.1:1"}})
+	err = d.V1Server.CheckReady(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "waiting to copy")
+
+	d.SetPeers([]gubernator.PeerInfo{{GRPCAddress: conf.GRPCListenAddress, IsOwner: true}})
+	testutil.UntilPass(t, 20, clock.Millisecond*100, func(t testutil.TestingT) {
+		check(t, "/readyz", http.StatusOK, gubernator.HealthServiceReadiness, grpc_health_v1.HealthCheckResponse_SERVING)
+	})
+	check(t, "/healthz", http.StatusOK, "", grpc_health_v1.HealthCheckResponse_SERVING)
 }
@@ -0,0 +1,169 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// The number of milliseconds the hits of a transaction are held when `TransactRateLimitsReq.timeout` is not set
+const defaultTransactionTimeout = 5 * Second
+
+// TransactRateLimits applies the hits of every rate limit in the transaction or none of them using
+// two phases coordinated by this instance. The prepare phase reserves the hits on the peers which
+// own the rate limits, the commit phase commits every reservation if all of them were reserved and
+// cancels them otherwise. Should this instance fail between the phases, the owning peers return the
+// held hits to the rate limits once the reservations expire.
+func (s *V1Instance) TransactRateLimits(ctx context.Context, r *TransactRateLimitsReq) (*TransactRateLimitsResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.TransactRateLimits")).ObserveDuration()
+	if err := validateTransaction(r); err != nil {
+		metricCheckErrorCounter.WithLabelValues("Invalid request").Inc()
+		return nil, err
+	}
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = defaultTransactionTimeout
+	}
+	// Reservations made after the deadline would expire before they could be committed
+	deadline := clock.Now().Add(clock.Duration(timeout) * clock.Millisecond)
+	prepareCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	// Prepare: hold the hits of every rate limit on its owner
+	resp := &TransactRateLimitsResp{
+		Status:    Status_UNDER_LIMIT,
+		Responses: make([]*RateLimitResp, len(r.Requests)),
+	}
+	reservations := make([]*ReservationReq, len(r.Requests))
+	eachTransactionRequest(r.Requests, func(i int, req *RateLimitReq) {
+		reserved, err := s.ReserveRateLimit(prepareCtx, &ReserveRateLimitReq{
+			RateLimit: proto.Clone(req).(*RateLimitReq),
+			Ttl:       timeout,
+		})
+		if err != nil {
+			resp.Responses[i] = &RateLimitResp{Error: err.Error()}
+			return
+		}
+		resp.Responses[i] = reserved.RateLimit
+		if reserved.ReservationId != "" {
+			reservations[i] = &ReservationReq{
+				Name:          req.Name,
+				UniqueKey:     req.UniqueKey,
+				ReservationId: reserved.ReservationId,
+			}
+		}
+	})
+
+	commit := true
+	for i := range r.Requests {
+		if reservations[i] == nil {
+			commit = false
+		}
+	}
+	if !clock.Now().Before(deadline) {
+		commit = false
+	}
+
+	s.releaseReservations(deadline, r.Requests, reservations, resp.Responses, commit)
+	if !commit {
+		resp.Status = Status_OVER_LIMIT
+		metricTransactionCounter.WithLabelValues("aborted").Inc()
+		return resp, nil
+	}
+	if committedStatus(resp.Responses) == Status_UNKNOWN {
+		resp.Status = Status_UNKNOWN
+		metricTransactionCounter.WithLabelValues("partial").Inc()
+		return resp, nil
+	}
+	metricTransactionCounter.WithLabelValues("committed").Inc()
+	return resp, nil
+}
+
+// releaseReservations commits every reservation if `commit` is true and cancels them otherwise.
+// The error of a failed commit is set on the response of its rate limit. The reservations must be
+// released even if the caller went away, as such the deadline of the caller is not inherited.
+func (s *V1Instance) releaseReservations(deadline time.Time, reqs []*RateLimitReq, reservations []*ReservationReq,
+	responses []*RateLimitResp, commit bool) {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	eachTransactionRequest(reqs, func(i int, req *RateLimitReq) {
+		if reservations[i] == nil {
+			return
+		}
+		if !commit {
+			// The hits are returned once the reservation expires if the cancel fails
+			if _, err := s.CancelReservation(ctx, reservations[i]); err != nil {
+				s.log.WithError(err).WithField("key", req.HashKey()).
+					Debug("while canceling the reservation of an aborted transaction")
+			}
+			return
+		}
+		if _, err := s.CommitReservation(ctx, reservations[i]); err != nil {
+			responses[i].Error = fmt.Sprintf("while committing reservation: %s", err)
+		}
+	})
+}
+
+// committedStatus returns UNKNOWN if any of the committed reservations failed, UNDER_LIMIT otherwise
+func committedStatus(responses []*RateLimitResp) Status {
+	for _, rl := range responses {
+		if rl.Error != "" {
+			return Status_UNKNOWN
+		}
+	}
+	return Status_UNDER_LIMIT
+}
+
+// eachTransactionRequest calls `fn` for every request concurrently,
+// such that the latency of a phase is the latency of the slowest owner.
+func eachTransactionRequest(reqs []*RateLimitReq, fn func(int, *RateLimitReq)) {
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *RateLimitReq) {
+			defer wg.Done()
+			fn(i, req)
+		}(i, req)
+	}
+	wg.Wait()
+}
+
+func validateTransaction(r *TransactRateLimitsReq) error {
+	switch {
+	case len(r.Requests) == 0:
+		return status.Error(codes.InvalidArgument, "Requests list cannot be empty")
+	case len(r.Requests) > maxBatchSize:
+		return status.Errorf(codes.OutOfRange, "Requests.RateLimits list too large; max size is '%d'", maxBatchSize)
+	case r.Timeout < 0:
+		return status.Error(codes.InvalidArgument, "field 'timeout' cannot be negative")
+	}
+	for i, req := range r.Requests {
+		if err := validateReserve(&ReserveRateLimitReq{RateLimit: req, Ttl: 1}); err != nil {
+			return status.Errorf(codes.InvalidArgument, "request %d: %s", i, status.Convert(err).Message())
+		}
+	}
+	return nil
+}
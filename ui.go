@@ -0,0 +1,37 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// The operator dashboard, a static page which calls HealthCheck and the AdminV1 service via the HTTP gateway
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// newUIHandler serves the operator dashboard under `/ui/`, see DaemonConfig.UIEnabled
+func newUIHandler() http.Handler {
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix("/ui/", http.FileServer(http.FS(files)))
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The number of buckets the rolling usage window is divided into
+const usageBuckets = 60
+
+// UsageReport is the usage of the rate limits owned by a single instance, as exported by a UsageExportFunc
+type UsageReport struct {
+	Time       time.Time         `json:"time"`
+	InstanceID string            `json:"instance_id"`
+	Window     time.Duration     `json:"window"`
+	Namespaces []*NamespaceUsage `json:"namespaces"`
+	// The consumption of each cost center over the window, see Config.MaxCostCenters
+	CostCenters []*CostCenterUsage `json:"cost_centers,omitempty"`
+}
+
+// UsageExportFunc exports the usage of the rate limits owned by this instance. Each instance
+// exports only the rate limits it owns, as such the reports of every instance in the cluster
+// should be summed to get the usage of the entire cluster.
+type UsageExportFunc func(ctx context.Context, report UsageReport) error
+
+// NewWebhookUsageExporter returns a UsageExportFunc which POSTs each report to `url` as JSON.
+func NewWebhookUsageExporter(url string) UsageExportFunc {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return func(ctx context.Context, report UsageReport) error {
+		b, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("usage webhook '%s' returned '%s'", url, resp.Status)
+		}
+		return nil
+	}
+}
+
+// usageTracker aggregates the consumption of each rate limit name over a rolling window. The
+// window is divided into buckets, the oldest bucket is discarded as the window moves forward.
+type usageTracker struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	width   int64
+	buckets [usageBuckets]usageBucket
+	// Seeds the noise of UsagePrivacyConfig, such that the noise cannot be derived from the counts
+	noiseSalt uint64
+}
+
+type usageBucket struct {
+	start int64
+	names map[string]*usageCounts
+	// The consumption of each cost center, see MetadataCostCenter
+	costCenters map[string]*CostCenterUsage
+}
+
+type usageCounts struct {
+	hits      int64
+	overLimit int64
+	// The hits of each unique key
+	keys map[string]int64
+}
+
+func newUsageTracker(window time.Duration) *usageTracker {
+	width := window.Milliseconds() / usageBuckets
+	if width < 1 {
+		width = 1
+	}
+	return &usageTracker{window: window, width: width, noiseSalt: rand.Uint64()}
+}
+
+// record adds the rate limit request, which took `elapsed` to evaluate, to the bucket of the current
+// time. `costCenter` is empty unless cost centers are tracked.
+func (u *usageTracker) record(r *RateLimitReq, resp *RateLimitResp, costCenter string, elapsed time.Duration) {
+	start := epochMillis(clock.Now()) / u.width * u.width
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	b := &u.buckets[(start/u.width)%usageBuckets]
+	if b.start != start || b.names == nil {
+		*b = usageBucket{
+			start:       start,
+			names:       make(map[string]*usageCounts),
+			costCenters: make(map[string]*CostCenterUsage),
+		}
+	}
+
+	if costCenter != "" {
+		cc, ok := b.costCenters[costCenter]
+		if !ok {
+			cc = &CostCenterUsage{Name: costCenter}
+			b.costCenters[costCenter] = cc
+		}
+		cc.Hits += r.Hits
+		cc.Seconds += elapsed.Seconds()
+	}
+
+	c, ok := b.names[r.Name]
+	if !ok {
+		c = &usageCounts{keys: make(map[string]int64)}
+		b.names[r.Name] = c
+	}
+	c.hits += r.Hits
+	if resp.Status == Status_OVER_LIMIT {
+		c.overLimit++
+	}
+	c.keys[r.UniqueKey] += r.Hits
+}
+
+// usage returns the usage of each rate limit name beginning with `prefix` over the window,
+// including the `topKeys` unique keys with the most hits of each name
+func (u *usageTracker) usage(prefix string, topKeys int) []*NamespaceUsage {
+	oldest := epochMillis(clock.Now())/u.width*u.width - (usageBuckets-1)*u.width
+	names := make(map[string]*NamespaceUsage)
+	keys := make(map[string]map[string]int64)
+
+	u.mutex.Lock()
+	for i := range u.buckets {
+		b := &u.buckets[i]
+		if b.names == nil || b.start < oldest {
+			continue
+		}
+		for name, c := range b.names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			n, ok := names[name]
+			if !ok {
+				n = &NamespaceUsage{Name: name}
+				names[name] = n
+				keys[name] = make(map[string]int64)
+			}
+			n.Hits += c.hits
+			n.OverLimit += c.overLimit
+			for k, hits := range c.keys {
+				keys[name][k] += hits
+			}
+		}
+	}
+	u.mutex.Unlock()
+
+	result := make([]*NamespaceUsage, 0, len(names))
+	for name, n := range names {
+		n.DistinctKeys = int64(len(keys[name]))
+		if topKeys > 0 {
+			for k, hits := range keys[name] {
+				n.TopKeys = append(n.TopKeys, &KeyUsage{UniqueKey: k, Hits: hits})
+			}
+			n.TopKeys = sortTopKeys(n.TopKeys, topKeys)
+		}
+		result = append(result, n)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// costCenterUsage returns the consumption of each cost center over the window
+func (u *usageTracker) costCenterUsage() []*CostCenterUsage {
+	oldest := epochMillis(clock.Now())/u.width*u.width - (usageBuckets-1)*u.width
+	costCenters := make(map[string]*CostCenterUsage)
+
+	u.mutex.Lock()
+	for i := range u.buckets {
+		b := &u.buckets[i]
+		if b.names == nil || b.start < oldest {
+			continue
+		}
+		for name, c := range b.costCenters {
+			cc, ok := costCenters[name]
+			if !ok {
+				cc = &CostCenterUsage{Name: name}
+				costCenters[name] = cc
+			}
+			cc.Hits += c.Hits
+			cc.Seconds += c.Seconds
+		}
+	}
+	u.mutex.Unlock()
+
+	result := make([]*CostCenterUsage, 0, len(costCenters))
+	for _, cc := range costCenters {
+		result = append(result, cc)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// GetNamespaceUsage returns the usage of each rate limit name summed across every peer in the
+// local data center. Peers in other regions hold copies of the same rate limits and are not included.
+func (s *V1Instance) GetNamespaceUsage(ctx context.Context, r *GetNamespaceUsageReq) (*GetNamespaceUsageResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetNamespaceUsage")).ObserveDuration()
+	if s.usage == nil {
+		return nil, errUsageDisabled
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	resp := &GetNamespaceUsageResp{Window: s.usage.window.Milliseconds()}
+	names := make(map[string]*NamespaceUsage)
+	for _, peer := range s.GetPeerList() {
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			var peerResp *GetNamespaceUsageResp
+			var err error
+			if peer.Info().IsOwner {
+				peerResp, err = s.GetPeerNamespaceUsage(ctx, r)
+			} else {
+				peerResp, err = peer.GetPeerNamespaceUsage(ctx, r)
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				resp.Errors = append(resp.Errors, fmt.Sprintf("peer '%s': %s", peer.Info().GRPCAddress, err))
+				return
+			}
+			for _, u := range peerResp.Namespaces {
+				n, ok := names[u.Name]
+				if !ok {
+					n = &NamespaceUsage{Name: u.Name}
+					names[u.Name] = n
+				}
+				// Each key is owned by a single peer, as such distinct keys may be summed
+				n.Hits += u.Hits
+				n.OverLimit += u.OverLimit
+				n.DistinctKeys += u.DistinctKeys
+				n.TopKeys = append(n.TopKeys, u.TopKeys...)
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	for _, n := range names {
+		n.TopKeys = sortTopKeys(n.TopKeys, int(r.TopKeys))
+		resp.Namespaces = append(resp.Namespaces, n)
+	}
+	sort.Slice(resp.Namespaces, func(i, j int) bool { return resp.Namespaces[i].Name < resp.Namespaces[j].Name })
+	s.usage.applyPrivacy(s.conf.UsagePrivacy, resp.Namespaces)
+	return resp, nil
+}
+
+// sortTopKeys returns the `n` keys with the most hits, sorted by hits then unique key
+func sortTopKeys(keys []*KeyUsage, n int) []*KeyUsage {
+	if n <= 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Hits != keys[j].Hits {
+			return keys[i].Hits > keys[j].Hits
+		}
+		return keys[i].UniqueKey < keys[j].UniqueKey
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// GetPeerNamespaceUsage is called by other peers to collect the usage of the rate limits owned by this peer.
+func (s *V1Instance) GetPeerNamespaceUsage(ctx context.Context, r *GetNamespaceUsageReq) (*GetNamespaceUsageResp, error) {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.GetPeerNamespaceUsage")).ObserveDuration()
+	if s.usage == nil {
+		return nil, errUsageDisabled
+	}
+	return &GetNamespaceUsageResp{
+		Namespaces: s.usage.usage(r.NamePrefix, int(r.TopKeys)),
+		Window:     s.usage.window.Milliseconds(),
+	}, nil
+}
+
+var errUsageDisabled = status.Error(codes.FailedPrecondition, "namespace usage is disabled; see Config.UsageWindow")
+
+// exportUsage calls `Config.UsageExporter` with the usage of the rate limits owned by this
+// instance every `Config.UsageExportInterval` until the instance is closed.
+func (s *V1Instance) exportUsage() {
+	tick := clock.NewTicker(s.conf.UsageExportInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C():
+			namespaces := s.usage.usage("", 0)
+			s.usage.applyPrivacy(s.conf.UsagePrivacy, namespaces)
+			ctx, cancel := context.WithTimeout(context.Background(), s.conf.UsageExportInterval)
+			err := s.conf.UsageExporter(ctx, UsageReport{
+				Time:        clock.Now(),
+				InstanceID:  s.conf.InstanceID,
+				Window:      s.usage.window,
+				Namespaces:  namespaces,
+				CostCenters: s.usage.costCenterUsage(),
+			})
+			cancel()
+			if err != nil {
+				s.log.WithError(err).Error("while exporting namespace usage")
+			}
+		case <-s.usageDone:
+			return
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"encoding/binary"
+
+	"github.com/OneOfOne/xxhash"
+	"github.com/mailgun/holster/v4/clock"
+	"github.com/pkg/errors"
+)
+
+// UsagePrivacyConfig hides the exact traffic of each rate limit name in the usage reported by
+// AdminV1.GetNamespaceUsage and Config.UsageExporter, IE: when the usage is shown to customers as
+// quota feedback. The noise is applied before the counts are rounded up to Bucket.
+type UsagePrivacyConfig struct {
+	// (Optional) Moves each count by a random amount of up to this percent of the count. The noise
+	// of a count only changes when the usage window moves forward, such that repeating the query
+	// cannot average it away. Defaults to 0 (no noise)
+	NoisePercent int
+
+	// (Optional) Rounds each count other than zero up to a multiple of Bucket. Defaults to 0 (counts
+	// are not rounded)
+	Bucket int64
+}
+
+func (c *UsagePrivacyConfig) validate() error {
+	if c.NoisePercent < 0 || c.NoisePercent > 100 {
+		return errors.New("UsagePrivacy.NoisePercent must be between 0 and 100")
+	}
+	if c.Bucket < 0 {
+		return errors.New("UsagePrivacy.Bucket cannot be negative")
+	}
+	return nil
+}
+
+// applyPrivacy replaces the counts of each namespace with their noisy and rounded value, see
+// UsagePrivacyConfig
+func (u *usageTracker) applyPrivacy(c UsagePrivacyConfig, namespaces []*NamespaceUsage) {
+	if c.NoisePercent == 0 && c.Bucket == 0 {
+		return
+	}
+	// The noise is derived from the bucket of the window the usage ends in
+	seed := epochMillis(clock.Now()) / u.width
+	for _, n := range namespaces {
+		n.Hits = u.privateCount(c, seed, n.Name, "hits", n.Hits)
+		n.OverLimit = u.privateCount(c, seed, n.Name, "over_limit", n.OverLimit)
+		n.DistinctKeys = u.privateCount(c, seed, n.Name, "distinct_keys", n.DistinctKeys)
+		for _, k := range n.TopKeys {
+			k.Hits = u.privateCount(c, seed, n.Name, "top_key:"+k.UniqueKey, k.Hits)
+		}
+		n.TopKeys = sortTopKeys(n.TopKeys, len(n.TopKeys))
+	}
+}
+
+// privateCount returns `v` moved by noise of up to `NoisePercent` of `v` and rounded up to a multiple
+// of `Bucket`. The noise is the same for the same `seed`, `name` and `field`.
+func (u *usageTracker) privateCount(c UsagePrivacyConfig, seed int64, name, field string, v int64) int64 {
+	if v <= 0 {
+		return v
+	}
+	if bound := mulInt64(v, int64(c.NoisePercent)) / 100; bound > 0 {
+		h := xxhash.NewS64(u.noiseSalt)
+		var b [8]byte
+		binary.LittleEndian.PutUint64(b[:], uint64(seed))
+		_, _ = h.Write(b[:])
+		_, _ = h.WriteString(name)
+		_, _ = h.WriteString("\x00")
+		_, _ = h.WriteString(field)
+		v += int64(h.Sum64()%uint64(2*bound+1)) - bound
+	}
+	if c.Bucket > 0 && v > 0 {
+		v = addInt64(v, c.Bucket-1) / c.Bucket * c.Bucket
+	}
+	return v
+}
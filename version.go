@@ -0,0 +1,104 @@
+/*
+Copyright 2018-2022 Mailgun Technologies Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gubernator
+
+import (
+	"context"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Version is the version of gubernator reported to peers, the daemon sets this to the version it was built with.
+var Version = "dev-build"
+
+// Commit is the VCS revision gubernator was built from. Defaults to the revision recorded by `go build`
+// which is empty if the binary was not built from a VCS checkout.
+var Commit = vcsRevision()
+
+func vcsRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// GetPeerVersion is called by other peers to collect the build version of this peer.
+func (s *V1Instance) GetPeerVersion(ctx context.Context, r *GetPeerVersionReq) (*GetPeerVersionResp, error) {
+	return &GetPeerVersionResp{
+		Version:        Version,
+		Commit:         Commit,
+		ConfigChecksum: s.configChecksum(),
+		NodeId:         s.conf.NodeID,
+	}, nil
+}
+
+// peerVersions returns the build version of every peer in the local and region pickers,
+// sorted by data center then address.
+func (s *V1Instance) peerVersions(ctx context.Context) []*PeerVersion {
+	defer prometheus.NewTimer(metricFuncTimeDuration.WithLabelValues("V1Instance.peerVersions")).ObserveDuration()
+
+	peers := s.GetPeerList()
+	for _, picker := range s.GetRegionPickers() {
+		peers = append(peers, picker.Peers()...)
+	}
+
+	var wg sync.WaitGroup
+	versions := make([]*PeerVersion, len(peers))
+	for i, peer := range peers {
+		info := peer.Info()
+		v := &PeerVersion{GrpcAddress: info.GRPCAddress, DataCenter: info.DataCenter}
+		versions[i] = v
+		if info.IsOwner {
+			v.Version, v.Commit, v.ConfigChecksum, v.NodeId = Version, Commit, s.configChecksum(), s.conf.NodeID
+			continue
+		}
+		wg.Add(1)
+		go func(peer *PeerClient) {
+			defer wg.Done()
+			resp, err := peer.GetPeerVersion(ctx, &GetPeerVersionReq{})
+			if status.Code(err) == codes.Unimplemented {
+				v.Error = "peer does not report its version; it predates GetPeerVersion"
+				return
+			}
+			if err != nil {
+				v.Error = err.Error()
+				return
+			}
+			v.Version, v.Commit, v.ConfigChecksum, v.NodeId = resp.Version, resp.Commit, resp.ConfigChecksum, resp.NodeId
+		}(peer)
+	}
+	wg.Wait()
+
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].DataCenter != versions[j].DataCenter {
+			return versions[i].DataCenter < versions[j].DataCenter
+		}
+		return versions[i].GrpcAddress < versions[j].GrpcAddress
+	})
+	return versions
+}
@@ -42,33 +42,69 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/OneOfOne/xxhash"
+	"github.com/mailgun/holster/v4/clock"
 	"github.com/mailgun/holster/v4/errors"
 	"github.com/mailgun/holster/v4/setter"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type WorkerPool struct {
 	hasher          workerHasher
 	workers         []*Worker
 	workerCacheSize int
+	classes         []CacheClass
 	hashRingStep    uint64
 	conf            *Config
 	done            chan struct{}
 }
 
 type Worker struct {
-	name                string
-	conf                *Config
-	cache               Cache
+	name string
+	conf *Config
+	// Holds the rate limits which match none of the `classes`
+	cache Cache
+	// The classes of Config.CacheClasses ordered by longest prefix first, and the cache of each class
+	classes             []CacheClass
+	classCaches         []Cache
 	getRateLimitRequest chan request
 	storeRequest        chan workerStoreRequest
 	loadRequest         chan workerLoadRequest
 	addCacheItemRequest chan workerAddCacheItemRequest
 	getCacheItemRequest chan workerGetCacheItemRequest
+	resetRequest        chan workerResetRequest
+	reserveRequest      chan workerReserveRequest
+	releaseRequest      chan workerReleaseRequest
+	leaseRequest        chan workerLeaseRequest
+	// Reservations, leases and idempotent responses held by this worker, only accessed by the worker's dispatch loop.
+	reservations map[string]*reservation
+	leases       map[string]*lease
+	idempotent   map[string]*idempotentResult
+	// The over-limit counts of the rate limits owned by this worker, see countOverLimit()
+	overLimit map[string]*overLimitCount
+	// Set if the Store implements OverLimitStore
+	overLimitStore OverLimitStore
+	// Is nil unless Config.LockFreeReads, see readRateLimit()
+	readCopies  *readCopies
+	readCounter prometheus.Counter
+	// The metrics of GetRateLimit, looked up once instead of on every request
+	commandCounter prometheus.Counter
+	queueGauge     prometheus.Gauge
+	// Set if the Store implements BatchStore
+	storeBatcher *storeBatcher
+}
+
+// responseChanPool holds the channels on which workers return the responses of GetRateLimit. A
+// channel is only returned to the pool once its response was read, or if the request was never
+// sent, as a worker may still send to the channel of a request whose context was cancelled.
+var responseChanPool = sync.Pool{
+	New: func() any { return make(chan *response, 1) },
 }
 
 type workerHasher interface {
@@ -117,6 +153,51 @@ type workerGetCacheItemResponse struct {
 	ok   bool
 }
 
+type workerResetRequest struct {
+	ctx      context.Context
+	response chan workerResetResponse
+	match    func(name string) bool
+}
+
+type workerResetResponse struct {
+	removed int64
+}
+
+type workerReserveRequest struct {
+	ctx      context.Context
+	response chan workerReserveResponse
+	request  *RateLimitReq
+	expireAt int64
+}
+
+type workerReserveResponse struct {
+	rl  *RateLimitResp
+	id  string
+	err error
+}
+
+type workerReleaseRequest struct {
+	ctx      context.Context
+	response chan workerReleaseResponse
+	key      string
+	id       string
+	commit   bool
+	// If true, refunds the hits of a REFUNDABLE request instead of releasing a reservation
+	refund bool
+}
+
+type workerReleaseResponse struct {
+	hits int64
+	ok   bool
+}
+
+type workerLeaseRequest struct {
+	ctx      context.Context
+	response chan *LeaseResp
+	request  *LeaseReq
+	release  bool
+}
+
 var _ io.Closer = &WorkerPool{}
 var _ workerHasher = &hasher{}
 
@@ -124,12 +205,16 @@ var workerCounter int64
 
 func NewWorkerPool(conf *Config) *WorkerPool {
 	setter.SetDefault(&conf.CacheSize, 50_000)
+	if conf.HashKey == nil {
+		conf.HashKey = LegacyHashKey
+	}
 
 	// Compute hashRingStep as interval between workers' 63-bit hash ranges.
 	// 64th bit is used here as a max value that is just out of range of 63-bit space to calculate the step.
 	chp := &WorkerPool{
 		workers:         make([]*Worker, conf.Workers),
 		workerCacheSize: conf.CacheSize / conf.Workers,
+		classes:         sortCacheClasses(conf.CacheClasses),
 		hasher:          newHasher(),
 		hashRingStep:    uint64(1<<63) / uint64(conf.Workers),
 		conf:            conf,
@@ -163,24 +248,108 @@ func (p *WorkerPool) Close() error {
 func (p *WorkerPool) newWorker() *Worker {
 	worker := &Worker{
 		conf:                p.conf,
-		cache:               p.conf.CacheFactory(p.workerCacheSize),
+		classes:             p.classes,
 		getRateLimitRequest: make(chan request),
 		storeRequest:        make(chan workerStoreRequest),
 		loadRequest:         make(chan workerLoadRequest),
 		addCacheItemRequest: make(chan workerAddCacheItemRequest),
 		getCacheItemRequest: make(chan workerGetCacheItemRequest),
+		resetRequest:        make(chan workerResetRequest),
+		reserveRequest:      make(chan workerReserveRequest),
+		releaseRequest:      make(chan workerReleaseRequest),
+		leaseRequest:        make(chan workerLeaseRequest),
+		reservations:        make(map[string]*reservation),
+		leases:              make(map[string]*lease),
+		idempotent:          make(map[string]*idempotentResult),
+		overLimit:           make(map[string]*overLimitCount),
+		overLimitStore:      overLimitStoreOf(p.conf.Store),
+	}
+	worker.storeBatcher, _ = p.conf.Store.(*storeBatcher)
+
+	worker.cache = p.newWorkerCache(worker, p.workerCacheSize, p.conf.CacheFullPolicy, p.conf.CacheIdleTTL)
+	if p.conf.MaxCacheBytes != 0 {
+		if c, ok := worker.cache.(ByteLimitedCache); ok {
+			c.SetMaxBytes(p.conf.MaxCacheBytes / int64(p.conf.Workers))
+		} else {
+			p.conf.Logger.Warn("MaxCacheBytes is set, but the cache provided by CacheFactory does not implement ByteLimitedCache")
+		}
+	}
+	for _, class := range p.classes {
+		size := class.Size / p.conf.Workers
+		if size < 1 {
+			size = 1
+		}
+		worker.classCaches = append(worker.classCaches, p.newWorkerCache(worker, size, class.FullPolicy, class.IdleTTL))
+	}
+
+	if p.conf.LockFreeReads {
+		var caches []ReadCopyCache
+		for _, cache := range worker.caches() {
+			if c, ok := cache.(ReadCopyCache); ok {
+				caches = append(caches, c)
+			}
+		}
+		if len(caches) == len(worker.caches()) {
+			worker.readCopies = newReadCopies(caches)
+		} else {
+			p.conf.Logger.Warn("LockFreeReads is set, but the cache provided by CacheFactory does not implement ReadCopyCache")
+		}
+	}
+	if worker.readCopies != nil || worker.storeBatcher != nil {
+		for _, cache := range worker.caches() {
+			if c, ok := cache.(RemovalCache); ok {
+				c.SetOnRemove(worker.removed)
+			} else if worker.storeBatcher != nil {
+				p.conf.Logger.Warn("Store implements BatchStore, but the cache provided by CacheFactory does not implement RemovalCache; evictions are not reported")
+			}
+		}
 	}
 	workerNumber := atomic.AddInt64(&workerCounter, 1) - 1
 	worker.name = strconv.FormatInt(workerNumber, 10)
+	worker.readCounter = metricCommandCounter.WithLabelValues(worker.name, "ReadRateLimit")
+	worker.commandCounter = metricCommandCounter.WithLabelValues(worker.name, "GetRateLimit")
+	worker.queueGauge = metricWorkerQueue.WithLabelValues("GetRateLimit", worker.name)
 	return worker
 }
 
+// newWorkerCache creates a cache of the worker which holds `size` rate limits, either the default
+// cache or the cache of a class, see Config.CacheClasses
+func (p *WorkerPool) newWorkerCache(worker *Worker, size int, fullPolicy string, idleTTL time.Duration) Cache {
+	cache := p.conf.CacheFactory(size)
+	if _, ok := cache.(IdleCache); idleTTL != 0 && !ok {
+		p.conf.Logger.Warn("CacheIdleTTL is set, but the cache provided by CacheFactory does not implement IdleCache")
+	}
+	if c, ok := cache.(CapacityCache); ok {
+		c.SetFullPolicy(fullPolicy, worker.spill)
+	} else if fullPolicy != "" && fullPolicy != CacheFullEvictLRU {
+		p.conf.Logger.Warn("CacheFullPolicy is set, but the cache provided by CacheFactory does not implement CapacityCache")
+	}
+	if p.conf.CacheTenantSeparator != "" {
+		if c, ok := cache.(TenantCache); ok {
+			setTenantQuota(p.conf, c, size)
+		} else {
+			p.conf.Logger.Warn("CacheTenantSeparator is set, but the cache provided by CacheFactory does not implement TenantCache")
+		}
+	}
+	if p.conf.CacheKeyHash != nil {
+		if c, ok := cache.(HashedKeyCache); ok {
+			c.SetKeyHash(p.conf.CacheKeyHash)
+		} else {
+			p.conf.Logger.Warn("CacheKeyHash is set, but the cache provided by CacheFactory does not implement HashedKeyCache")
+		}
+	}
+	return cache
+}
+
 // getWorker Returns the request channel associated with the key.
 // Hash the key, then lookup hash ring to find the worker.
 func (p *WorkerPool) getWorker(key string) *Worker {
-	hash := p.hasher.ComputeHash63(key)
-	idx := hash / p.hashRingStep
-	return p.workers[idx]
+	return p.workers[p.shard(key)]
+}
+
+// shard returns the index of the worker which owns the key
+func (p *WorkerPool) shard(key string) int {
+	return int(p.hasher.ComputeHash63(key) / p.hashRingStep)
 }
 
 // Pool worker for processing Gubernator requests.
@@ -188,6 +357,9 @@ func (p *WorkerPool) getWorker(key string) *Worker {
 // A hash ring will distribute requests to an assigned worker by key.
 // See: getWorker()
 func (p *WorkerPool) dispatch(worker *Worker) {
+	sweep := clock.NewTicker(reservationSweepInterval)
+	defer sweep.Stop()
+
 	for {
 		// Dispatch requests from each channel.
 		select {
@@ -199,7 +371,20 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 			}
 
 			resp := new(response)
-			resp.rl, resp.err = worker.handleGetRateLimit(req.ctx, req.request, req.reqState, worker.cache)
+			if rl, ok := worker.replayIdempotent(req.request); ok {
+				resp.rl = rl
+			} else {
+				cache := worker.cacheFor(req.request.Name)
+				resp.rl, resp.err = worker.handleGetRateLimit(req.ctx, req.request, req.reqState, cache)
+				if resp.err == nil && req.reqState.IsOwner && HasBehavior(req.request.Behavior, Behavior_REFUNDABLE) {
+					worker.addRefund(req.request, resp.rl, cache)
+				}
+				if resp.err == nil {
+					worker.countOverLimit(req.ctx, req.request, req.reqState, resp.rl)
+					worker.addIdempotent(req.request, resp.rl)
+				}
+				worker.publish(req.reqState.keyOf(worker.conf, req.request))
+			}
 			select {
 			case req.resp <- resp:
 				// Success.
@@ -208,7 +393,7 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 				// Context canceled.
 				trace.SpanFromContext(req.ctx).RecordError(resp.err)
 			}
-			metricCommandCounter.WithLabelValues(worker.name, "GetRateLimit").Inc()
+			worker.commandCounter.Inc()
 
 		case req, ok := <-worker.storeRequest:
 			if !ok {
@@ -217,7 +402,7 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 				return
 			}
 
-			worker.handleStore(req, worker.cache)
+			worker.handleStore(req)
 			metricCommandCounter.WithLabelValues(worker.name, "Store").Inc()
 
 		case req, ok := <-worker.loadRequest:
@@ -227,7 +412,7 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 				return
 			}
 
-			worker.handleLoad(req, worker.cache)
+			worker.handleLoad(req)
 			metricCommandCounter.WithLabelValues(worker.name, "Load").Inc()
 
 		case req, ok := <-worker.addCacheItemRequest:
@@ -237,7 +422,7 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 				return
 			}
 
-			worker.handleAddCacheItem(req, worker.cache)
+			worker.handleAddCacheItem(req, worker.cacheFor(req.item.Name))
 			metricCommandCounter.WithLabelValues(worker.name, "AddCacheItem").Inc()
 
 		case req, ok := <-worker.getCacheItemRequest:
@@ -247,9 +432,56 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 				return
 			}
 
-			worker.handleGetCacheItem(req, worker.cache)
+			worker.handleGetCacheItem(req)
 			metricCommandCounter.WithLabelValues(worker.name, "GetCacheItem").Inc()
 
+		case req, ok := <-worker.resetRequest:
+			if !ok {
+				// Channel closed.  Unexpected, but should be handled.
+				logrus.Error("workerPool worker stopped because channel closed")
+				return
+			}
+
+			worker.handleReset(req)
+			metricCommandCounter.WithLabelValues(worker.name, "Reset").Inc()
+
+		case req, ok := <-worker.reserveRequest:
+			if !ok {
+				// Channel closed.  Unexpected, but should be handled.
+				logrus.Error("workerPool worker stopped because channel closed")
+				return
+			}
+
+			worker.handleReserve(req, worker.cacheFor(req.request.Name))
+			metricCommandCounter.WithLabelValues(worker.name, "Reserve").Inc()
+
+		case req, ok := <-worker.releaseRequest:
+			if !ok {
+				// Channel closed.  Unexpected, but should be handled.
+				logrus.Error("workerPool worker stopped because channel closed")
+				return
+			}
+
+			worker.handleRelease(req)
+			metricCommandCounter.WithLabelValues(worker.name, "Release").Inc()
+
+		case req, ok := <-worker.leaseRequest:
+			if !ok {
+				// Channel closed.  Unexpected, but should be handled.
+				logrus.Error("workerPool worker stopped because channel closed")
+				return
+			}
+
+			worker.handleLease(req)
+			metricCommandCounter.WithLabelValues(worker.name, "Lease").Inc()
+
+		case <-sweep.C():
+			worker.expireReservations()
+			worker.expireLeases()
+			worker.expireIdle()
+			worker.expireIdempotent()
+			worker.expireOverLimit()
+
 		case <-p.done:
 			// Clean up.
 			return
@@ -260,13 +492,16 @@ func (p *WorkerPool) dispatch(worker *Worker) {
 // GetRateLimit sends a GetRateLimit request to worker pool.
 func (p *WorkerPool) GetRateLimit(ctx context.Context, rlRequest *RateLimitReq, reqState RateLimitReqState) (*RateLimitResp, error) {
 	// Delegate request to assigned channel based on request key.
-	worker := p.getWorker(rlRequest.HashKey())
-	queueGauge := metricWorkerQueue.WithLabelValues("GetRateLimit", worker.name)
-	queueGauge.Inc()
-	defer queueGauge.Dec()
+	reqState.hashKey = p.conf.HashKey(rlRequest)
+	worker := p.getWorker(reqState.hashKey)
+	if rl, ok := worker.readRateLimit(ctx, rlRequest, reqState); ok {
+		return rl, nil
+	}
+	worker.queueGauge.Inc()
+	defer worker.queueGauge.Dec()
 	handlerRequest := request{
 		ctx:      ctx,
-		resp:     make(chan *response, 1),
+		resp:     responseChanPool.Get().(chan *response),
 		request:  rlRequest,
 		reqState: reqState,
 	}
@@ -276,13 +511,15 @@ func (p *WorkerPool) GetRateLimit(ctx context.Context, rlRequest *RateLimitReq,
 	case worker.getRateLimitRequest <- handlerRequest:
 		// Successfully sent request.
 	case <-ctx.Done():
+		responseChanPool.Put(handlerRequest.resp)
 		return nil, ctx.Err()
 	}
 
 	// Wait for response.
 	select {
 	case handlerResponse := <-handlerRequest.resp:
-		// Successfully read response.
+		// Successfully read response, the channel is empty and may be reused.
+		responseChanPool.Put(handlerRequest.resp)
 		return handlerResponse.rl, handlerResponse.err
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -295,9 +532,14 @@ func (worker *Worker) handleGetRateLimit(ctx context.Context, req *RateLimitReq,
 	var rlResponse *RateLimitResp
 	var err error
 
+	if c, ok := cache.(CapacityCache); ok && worker.fullPolicyFor(req.Name) == CacheFullReject && c.IsFull(reqState.keyOf(worker.conf, req)) {
+		metricCacheFullCounter.WithLabelValues("rejected").Inc()
+		return nil, status.Error(codes.ResourceExhausted, "the cache is full of unexpired rate limits; new rate limits are rejected")
+	}
+
 	switch req.Algorithm {
 	case Algorithm_TOKEN_BUCKET:
-		rlResponse, err = tokenBucket(ctx, worker.conf.Store, cache, req, reqState)
+		rlResponse, err = tokenBucket(ctx, worker.storeFor(req.Name), cache, worker.conf, req, reqState)
 		if err != nil {
 			msg := "Error in tokenBucket"
 			countError(err, msg)
@@ -306,7 +548,7 @@ func (worker *Worker) handleGetRateLimit(ctx context.Context, req *RateLimitReq,
 		}
 
 	case Algorithm_LEAKY_BUCKET:
-		rlResponse, err = leakyBucket(ctx, worker.conf.Store, cache, req, reqState)
+		rlResponse, err = leakyBucket(ctx, worker.storeFor(req.Name), cache, worker.conf, req, reqState)
 		if err != nil {
 			msg := "Error in leakyBucket"
 			countError(err, msg)
@@ -412,7 +654,7 @@ MAIN:
 	return nil
 }
 
-func (worker *Worker) handleLoad(request workerLoadRequest, cache Cache) {
+func (worker *Worker) handleLoad(request workerLoadRequest) {
 MAIN:
 	for {
 		var item *CacheItem
@@ -430,7 +672,8 @@ MAIN:
 			return
 		}
 
-		cache.Add(item)
+		worker.cacheFor(item.Name).Add(item)
+		worker.publish(item.Key)
 	}
 
 	response := workerLoadResponse{}
@@ -452,6 +695,22 @@ func (p *WorkerPool) Store(ctx context.Context) (err error) {
 	queueGauge := metricWorkerQueue.WithLabelValues("Store", "")
 	queueGauge.Inc()
 	defer queueGauge.Dec()
+
+	out := p.Each(ctx)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err = p.conf.Loader.Save(out); err != nil {
+		return errors.Wrap(err, "while calling p.conf.Loader.Save()")
+	}
+
+	return nil
+}
+
+// Each returns a channel which receives a copy of every item in all workers' caches. The
+// channel is closed once every item was sent. The channel MUST be read until closed.
+func (p *WorkerPool) Each(ctx context.Context) chan *CacheItem {
 	var wg sync.WaitGroup
 	out := make(chan *CacheItem, 500)
 
@@ -497,27 +756,59 @@ func (p *WorkerPool) Store(ctx context.Context) (err error) {
 		close(out)
 	}()
 
-	if ctx.Err() != nil {
-		return ctx.Err()
+	return out
+}
+
+// expireIdle evicts the rate limits which were not accessed within `Config.CacheIdleTTL`, or the
+// `CacheClass.IdleTTL` of their class.
+// The rate limits remain in the Store and are loaded again when next accessed.
+func (worker *Worker) expireIdle() {
+	for i, cache := range worker.caches() {
+		ttl := worker.conf.CacheIdleTTL
+		if i > 0 {
+			ttl = worker.classes[i-1].IdleTTL
+		}
+		if c, ok := cache.(IdleCache); ok && ttl > 0 {
+			c.RemoveIdle(MillisecondNow() - ttl.Milliseconds())
+		}
 	}
+}
 
-	if err = p.conf.Loader.Save(out); err != nil {
-		return errors.Wrap(err, "while calling p.conf.Loader.Save()")
+// removed is called with each item which leaves the cache, see RemovalCache
+func (worker *Worker) removed(item *CacheItem) {
+	if worker.readCopies != nil {
+		worker.readCopies.remove(item.Key)
+	}
+	if worker.storeBatcher != nil && !item.IsExpired() && worker.storeFor(item.Name) != nil {
+		worker.storeBatcher.evict(item.Key)
 	}
+}
 
-	return nil
+// spill saves an unexpired rate limit evicted from the full cache to the Store, see CacheFullSpill.
+// Only the name and algorithm of the request passed to Store.OnChange() are known.
+func (worker *Worker) spill(item *CacheItem) {
+	if worker.conf.Store == nil {
+		return
+	}
+	worker.conf.Store.OnChange(context.Background(), &RateLimitReq{Name: item.Name, Algorithm: item.Algorithm}, item)
 }
 
-func (worker *Worker) handleStore(request workerStoreRequest, cache Cache) {
-	for item := range cache.Each() {
-		select {
-		case request.out <- item:
-			// Successfully sent item.
+func (worker *Worker) handleStore(request workerStoreRequest) {
+	for i, cache := range worker.caches() {
+		// The rate limits of volatile classes are not persisted
+		if i > 0 && worker.classes[i-1].Volatile {
+			continue
+		}
+		for item := range cache.Each() {
+			select {
+			case request.out <- item.copy():
+				// Successfully sent item.
 
-		case <-request.ctx.Done():
-			// Context canceled.
-			trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
-			return
+			case <-request.ctx.Done():
+				// Context canceled.
+				trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
+				return
+			}
 		}
 	}
 
@@ -567,6 +858,7 @@ func (p *WorkerPool) AddCacheItem(ctx context.Context, key string, item *CacheIt
 
 func (worker *Worker) handleAddCacheItem(request workerAddCacheItemRequest, cache Cache) {
 	exists := cache.Add(request.item)
+	worker.publish(request.item.Key)
 	response := workerAddCacheItemResponse{exists}
 
 	select {
@@ -611,9 +903,305 @@ func (p *WorkerPool) GetCacheItem(ctx context.Context, key string) (item *CacheI
 	}
 }
 
-func (worker *Worker) handleGetCacheItem(request workerGetCacheItemRequest, cache Cache) {
-	item, ok := cache.GetItem(request.key)
-	response := workerGetCacheItemResponse{item, ok}
+func (worker *Worker) handleGetCacheItem(request workerGetCacheItemRequest) {
+	// Only the key is known, as such each cache is searched
+	var response workerGetCacheItemResponse
+	for _, cache := range worker.caches() {
+		if item, ok := cache.GetItem(request.key); ok {
+			response = workerGetCacheItemResponse{item, ok}
+			break
+		}
+	}
+
+	select {
+	case request.response <- response:
+		// Successfully sent response.
+
+	case <-request.ctx.Done():
+		// Context canceled.
+		trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
+	}
+}
+
+// Reset removes every rate limit whose name matches from all workers' caches and
+// returns the number of rate limits removed.
+func (p *WorkerPool) Reset(ctx context.Context, match func(name string) bool) (int64, error) {
+	queueGauge := metricWorkerQueue.WithLabelValues("Reset", "")
+	queueGauge.Inc()
+	defer queueGauge.Dec()
+	var wg sync.WaitGroup
+	var removed int64
+
+	for _, worker := range p.workers {
+		wg.Add(1)
+
+		go func(ctx context.Context, worker *Worker) {
+			defer wg.Done()
+
+			respChan := make(chan workerResetResponse)
+			req := workerResetRequest{
+				ctx:      ctx,
+				response: respChan,
+				match:    match,
+			}
+
+			select {
+			case worker.resetRequest <- req:
+				// Successfully sent request.
+				select {
+				case resp := <-respChan:
+					// Successfully received response.
+					atomic.AddInt64(&removed, resp.removed)
+
+				case <-ctx.Done():
+					// Context canceled.
+					trace.SpanFromContext(ctx).RecordError(ctx.Err())
+				}
+
+			case <-ctx.Done():
+				// Context canceled.
+				trace.SpanFromContext(ctx).RecordError(ctx.Err())
+			}
+		}(ctx, worker)
+	}
+
+	wg.Wait()
+	if ctx.Err() != nil {
+		return removed, ctx.Err()
+	}
+	return removed, nil
+}
+
+func (worker *Worker) handleReset(request workerResetRequest) {
+	var removed int64
+	for _, cache := range worker.caches() {
+		// Collect the keys first, the cache must not be modified while iterating.
+		var keys []string
+		for item := range cache.Each() {
+			if request.match(item.Name) {
+				keys = append(keys, item.Key)
+			}
+		}
+
+		for _, key := range keys {
+			cache.Remove(key)
+			if worker.conf.Store != nil {
+				worker.conf.Store.Remove(request.ctx, key)
+			}
+		}
+		removed += int64(len(keys))
+	}
+
+	response := workerResetResponse{removed: removed}
+
+	select {
+	case request.response <- response:
+		// Successfully sent response.
+
+	case <-request.ctx.Done():
+		// Context canceled.
+		trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
+	}
+}
+
+// Reserve applies the hits of the request and, if the rate limit is under the limit, holds the
+// hits in a reservation which expires at `expireAt`. Returns the id of the reservation, which is
+// empty if no hits were reserved.
+func (p *WorkerPool) Reserve(ctx context.Context, rlRequest *RateLimitReq, expireAt int64) (*RateLimitResp, string, error) {
+	worker := p.getWorker(p.conf.HashKey(rlRequest))
+	queueGauge := metricWorkerQueue.WithLabelValues("Reserve", worker.name)
+	queueGauge.Inc()
+	defer queueGauge.Dec()
+	respChan := make(chan workerReserveResponse)
+	req := workerReserveRequest{
+		ctx:      ctx,
+		response: respChan,
+		request:  rlRequest,
+		expireAt: expireAt,
+	}
+
+	select {
+	case worker.reserveRequest <- req:
+		// Successfully sent request.
+		select {
+		case resp := <-respChan:
+			// Successfully received response.
+			return resp.rl, resp.id, resp.err
+
+		case <-ctx.Done():
+			// Context canceled.
+			return nil, "", ctx.Err()
+		}
+
+	case <-ctx.Done():
+		// Context canceled.
+		return nil, "", ctx.Err()
+	}
+}
+
+func (worker *Worker) handleReserve(request workerReserveRequest, cache Cache) {
+	reqState := RateLimitReqState{IsOwner: true}
+	rl, err := worker.handleGetRateLimit(request.ctx, request.request, reqState, cache)
+	response := workerReserveResponse{rl: rl, err: err}
+	if err == nil && rl.Status == Status_UNDER_LIMIT {
+		response.id = worker.addReservation(request.request, request.expireAt, cache)
+	}
+	worker.publish(worker.conf.HashKey(request.request))
+
+	select {
+	case request.response <- response:
+		// Successfully sent response.
+
+	case <-request.ctx.Done():
+		// Context canceled.
+		trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
+	}
+}
+
+// Release commits or cancels the reservation held against the rate limit `key`. Returns the hits
+// held by the reservation and false if the reservation does not exist.
+func (p *WorkerPool) Release(ctx context.Context, key, id string, commit bool) (int64, bool, error) {
+	worker := p.getWorker(key)
+	queueGauge := metricWorkerQueue.WithLabelValues("Release", worker.name)
+	queueGauge.Inc()
+	defer queueGauge.Dec()
+	respChan := make(chan workerReleaseResponse)
+	req := workerReleaseRequest{
+		ctx:      ctx,
+		response: respChan,
+		key:      key,
+		id:       id,
+		commit:   commit,
+	}
+
+	select {
+	case worker.releaseRequest <- req:
+		// Successfully sent request.
+		select {
+		case resp := <-respChan:
+			// Successfully received response.
+			return resp.hits, resp.ok, nil
+
+		case <-ctx.Done():
+			// Context canceled.
+			return 0, false, ctx.Err()
+		}
+
+	case <-ctx.Done():
+		// Context canceled.
+		return 0, false, ctx.Err()
+	}
+}
+
+func (worker *Worker) handleRelease(request workerReleaseRequest) {
+	var response workerReleaseResponse
+	if request.refund {
+		response = worker.refundHits(request)
+	} else if res, ok := worker.reservations[request.id]; ok && res.key == request.key && !res.refundable {
+		delete(worker.reservations, request.id)
+		switch {
+		case res.expireAt <= epochMillis(clock.Now()):
+			// Expired before the sweep could cancel it.
+			worker.refund(request.ctx, res)
+			metricReservationCounter.WithLabelValues("expired").Inc()
+			res = nil
+		case request.commit:
+			metricReservationCounter.WithLabelValues("committed").Inc()
+		default:
+			worker.refund(request.ctx, res)
+			metricReservationCounter.WithLabelValues("canceled").Inc()
+		}
+		if res != nil {
+			response = workerReleaseResponse{hits: res.req.Hits, ok: true}
+		}
+	}
+	worker.publish(request.key)
+
+	select {
+	case request.response <- response:
+		// Successfully sent response.
+
+	case <-request.ctx.Done():
+		// Context canceled.
+		trace.SpanFromContext(request.ctx).RecordError(request.ctx.Err())
+	}
+}
+
+// Refund returns the hits of the REFUNDABLE request identified by `id` to the rate limit `key`.
+// Returns the hits refunded and false if the refund does not exist or the refund window has passed.
+func (p *WorkerPool) Refund(ctx context.Context, key, id string) (int64, bool, error) {
+	worker := p.getWorker(key)
+	queueGauge := metricWorkerQueue.WithLabelValues("Refund", worker.name)
+	queueGauge.Inc()
+	defer queueGauge.Dec()
+	respChan := make(chan workerReleaseResponse)
+	req := workerReleaseRequest{
+		ctx:      ctx,
+		response: respChan,
+		key:      key,
+		id:       id,
+		refund:   true,
+	}
+
+	select {
+	case worker.releaseRequest <- req:
+		// Successfully sent request.
+		select {
+		case resp := <-respChan:
+			// Successfully received response.
+			return resp.hits, resp.ok, nil
+
+		case <-ctx.Done():
+			// Context canceled.
+			return 0, false, ctx.Err()
+		}
+
+	case <-ctx.Done():
+		// Context canceled.
+		return 0, false, ctx.Err()
+	}
+}
+
+// Lease acquires, renews or releases the lease named by the request.
+func (p *WorkerPool) Lease(ctx context.Context, r *LeaseReq, release bool) (*LeaseResp, error) {
+	worker := p.getWorker(r.Name)
+	queueGauge := metricWorkerQueue.WithLabelValues("Lease", worker.name)
+	queueGauge.Inc()
+	defer queueGauge.Dec()
+	respChan := make(chan *LeaseResp)
+	req := workerLeaseRequest{
+		ctx:      ctx,
+		response: respChan,
+		request:  r,
+		release:  release,
+	}
+
+	select {
+	case worker.leaseRequest <- req:
+		// Successfully sent request.
+		select {
+		case resp := <-respChan:
+			// Successfully received response.
+			return resp, nil
+
+		case <-ctx.Done():
+			// Context canceled.
+			return nil, ctx.Err()
+		}
+
+	case <-ctx.Done():
+		// Context canceled.
+		return nil, ctx.Err()
+	}
+}
+
+func (worker *Worker) handleLease(request workerLeaseRequest) {
+	var response *LeaseResp
+	if request.release {
+		response = worker.releaseLease(request.request)
+	} else {
+		response = worker.acquireLease(request.request)
+	}
 
 	select {
 	case request.response <- response:
@@ -20,9 +20,11 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"testing"
 
 	guber "github.com/gubernator-io/gubernator/v2"
+	"github.com/mailgun/holster/v4/clock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -132,3 +134,56 @@ func TestGubernatorPool(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkerPoolLockFreeReads(t *testing.T) {
+	ctx := context.Background()
+	conf := &guber.Config{Workers: 2, LockFreeReads: true}
+	require.NoError(t, conf.SetDefaults())
+	pool := guber.NewWorkerPool(conf)
+	defer pool.Close()
+
+	createdAt := epochMillis(clock.Now())
+	getRateLimit := func(key string, hits int64, behavior guber.Behavior) *guber.RateLimitResp {
+		rl, err := pool.GetRateLimit(ctx, &guber.RateLimitReq{
+			Name:      "test_lock_free_reads",
+			UniqueKey: key,
+			Algorithm: guber.Algorithm_TOKEN_BUCKET,
+			Behavior:  behavior,
+			Limit:     100,
+			Duration:  guber.Minute,
+			Hits:      hits,
+			CreatedAt: &createdAt,
+		}, guber.RateLimitReqState{IsOwner: true})
+		require.NoError(t, err)
+		return rl
+	}
+
+	// A query of a rate limit which is not in the cache does not create it
+	assert.Equal(t, int64(100), getRateLimit("account:1", 0, 0).Remaining)
+	assert.Equal(t, int64(97), getRateLimit("account:1", 3, 0).Remaining)
+	assert.Equal(t, int64(97), getRateLimit("account:1", 0, 0).Remaining)
+
+	// Queries concurrent with hits never see more remaining than before the hits
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := int64(97)
+			for j := 0; j < 200; j++ {
+				remaining := getRateLimit("account:1", 0, 0).Remaining
+				assert.LessOrEqual(t, remaining, last)
+				last = remaining
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		getRateLimit("account:1", 1, 0)
+	}
+	wg.Wait()
+	assert.Equal(t, int64(47), getRateLimit("account:1", 0, 0).Remaining)
+
+	// A reset removes the copy
+	getRateLimit("account:1", 0, guber.Behavior_RESET_REMAINING)
+	assert.Equal(t, int64(100), getRateLimit("account:1", 0, 0).Remaining)
+}